@@ -0,0 +1,37 @@
+package archive
+
+import (
+	"errors"
+
+	"github.com/ME-MotherEarth/me-elastic-indexer/queue"
+)
+
+// ErrEmptyDirectory signals that an empty archive directory path has been provided
+var ErrEmptyDirectory = errors.New("empty archive directory")
+
+// ErrInvalidMaxSegmentSize signals that an invalid maximum segment size has been provided
+var ErrInvalidMaxSegmentSize = errors.New("invalid maximum segment size")
+
+// ErrWriterClosed signals that an operation was attempted on a closed writer
+var ErrWriterClosed = errors.New("archive writer is closed")
+
+// ErrRecordTooLarge signals that a record exceeds the maximum accepted size
+var ErrRecordTooLarge = queue.ErrRecordTooLarge
+
+// ErrChecksumMismatch signals that the checksum of a record read from the archive does not match its content
+var ErrChecksumMismatch = queue.ErrChecksumMismatch
+
+// ErrNilPayloadCodec signals that a nil payload codec has been provided
+var ErrNilPayloadCodec = errors.New("nil payload codec")
+
+// ErrNilIndexer signals that a nil indexer has been provided
+var ErrNilIndexer = errors.New("nil indexer")
+
+// ErrNilRecordWriter signals that a nil record writer has been provided
+var ErrNilRecordWriter = errors.New("nil record writer")
+
+// ErrNilShardCoordinator signals that a nil shard coordinator has been provided
+var ErrNilShardCoordinator = errors.New("nil shard coordinator")
+
+// ErrInvalidNonceRange signals that the start of the nonce range is after its end
+var ErrInvalidNonceRange = errors.New("invalid nonce range")
@@ -0,0 +1,28 @@
+package archive
+
+import (
+	coreData "github.com/ME-MotherEarth/me-core/data"
+	"github.com/ME-MotherEarth/me-core/data/indexer"
+	"github.com/ME-MotherEarth/me-elastic-indexer/payload"
+)
+
+// PayloadCodec defines what a component that converts payloads to and from bytes should be able to do
+type PayloadCodec interface {
+	Encode(p *payload.Payload) ([]byte, error)
+	Decode(buff []byte) (*payload.Payload, error)
+	IsInterfaceNil() bool
+}
+
+// RecordWriter defines what a component that appends records to an archive should be able to do
+type RecordWriter interface {
+	Append(record *Record) error
+	Close() error
+	IsInterfaceNil() bool
+}
+
+// AccountsCacheHandler defines what a component that keeps the accounts read from the archive should be able to do
+type AccountsCacheHandler interface {
+	UpdateAlteredAccounts(alteredAccounts map[string]*indexer.AlteredAccount)
+	UpdateAccounts(accounts []coreData.UserAccountHandler)
+	IsInterfaceNil() bool
+}
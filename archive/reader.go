@@ -0,0 +1,142 @@
+package archive
+
+import (
+	"bufio"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+type segmentFile struct {
+	index int
+	path  string
+}
+
+type reader struct {
+	segments   []*segmentFile
+	file       *os.File
+	gzipReader *gzip.Reader
+	buffered   *bufio.Reader
+}
+
+// NewReader will create a reader that returns, in the order they were written, the records of the archive placed in
+// the provided directory. A segment that ends with a truncated or corrupted record, as left by a crash, is read up to
+// that record
+func NewReader(directory string) (*reader, error) {
+	if directory == "" {
+		return nil, ErrEmptyDirectory
+	}
+
+	segments, err := listSegments(directory)
+	if err != nil {
+		return nil, err
+	}
+
+	return &reader{
+		segments: segments,
+	}, nil
+}
+
+// Next returns the next record of the archive, or io.EOF when all the segments were read
+func (r *reader) Next() (*Record, error) {
+	for {
+		if r.buffered == nil {
+			if len(r.segments) == 0 {
+				return nil, io.EOF
+			}
+
+			err := r.openSegment(r.segments[0])
+			r.segments = r.segments[1:]
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		record, err := readRecord(r.buffered)
+		if err == nil {
+			return record, nil
+		}
+		if err != io.EOF {
+			log.Warn("archive reader: truncated or corrupted record, the rest of the segment will be ignored",
+				"segment", r.file.Name(), "error", err.Error())
+		}
+
+		r.closeSegment()
+	}
+}
+
+func (r *reader) openSegment(segment *segmentFile) error {
+	file, err := os.Open(segment.path)
+	if err != nil {
+		return err
+	}
+
+	gzipReader, err := gzip.NewReader(file)
+	if errors.Is(err, io.EOF) {
+		// the writer stopped before writing the first record of the segment
+		_ = file.Close()
+		return nil
+	}
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("%w while opening the segment %s", err, segment.path)
+	}
+
+	r.file = file
+	r.gzipReader = gzipReader
+	r.buffered = bufio.NewReader(gzipReader)
+
+	return nil
+}
+
+func (r *reader) closeSegment() {
+	if r.gzipReader != nil {
+		_ = r.gzipReader.Close()
+	}
+	if r.file != nil {
+		_ = r.file.Close()
+	}
+
+	r.gzipReader = nil
+	r.file = nil
+	r.buffered = nil
+}
+
+// Close will close the segment that is being read
+func (r *reader) Close() error {
+	r.closeSegment()
+	r.segments = nil
+
+	return nil
+}
+
+func listSegments(directory string) ([]*segmentFile, error) {
+	paths, err := filepath.Glob(filepath.Join(directory, segmentFileGlob))
+	if err != nil {
+		return nil, err
+	}
+
+	segments := make([]*segmentFile, 0, len(paths))
+	for _, path := range paths {
+		index := 0
+		_, errScan := fmt.Sscanf(filepath.Base(path), segmentFilePattern, &index)
+		if errScan != nil {
+			continue
+		}
+
+		segments = append(segments, &segmentFile{
+			index: index,
+			path:  path,
+		})
+	}
+	sort.Slice(segments, func(i, j int) bool {
+		return segments[i].index < segments[j].index
+	})
+
+	return segments, nil
+}
@@ -0,0 +1,59 @@
+package archive
+
+import (
+	"encoding/binary"
+	"io"
+
+	"github.com/ME-MotherEarth/me-elastic-indexer/payload"
+	"github.com/ME-MotherEarth/me-elastic-indexer/queue"
+)
+
+const (
+	headerSize   = 1 + 1 + 4 + 8
+	flagHasBlock = byte(1)
+	flagIsGap    = byte(2)
+)
+
+// Record is one indexer call stored in the archive. The shard and the nonce are kept outside the encoded payload, so
+// the records can be selected without decoding them. For the calls that are not made for a block, the nonce is the one
+// of the last block recorded before them. A gap record is written in place of the calls that could not be recorded, and
+// its data holds their number
+type Record struct {
+	Type     payload.Type
+	HasBlock bool
+	IsGap    bool
+	ShardID  uint32
+	Nonce    uint64
+	Data     []byte
+}
+
+func encodeRecord(record *Record) []byte {
+	header := make([]byte, headerSize)
+	header[0] = byte(record.Type)
+	if record.HasBlock {
+		header[1] |= flagHasBlock
+	}
+	if record.IsGap {
+		header[1] |= flagIsGap
+	}
+	binary.BigEndian.PutUint32(header[2:6], record.ShardID)
+	binary.BigEndian.PutUint64(header[6:14], record.Nonce)
+
+	return queue.EncodeFrame(header, record.Data)
+}
+
+func readRecord(reader io.Reader) (*Record, error) {
+	header, recordData, err := queue.ReadFrame(reader, headerSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Record{
+		Type:     payload.Type(header[0]),
+		HasBlock: header[1]&flagHasBlock != 0,
+		IsGap:    header[1]&flagIsGap != 0,
+		ShardID:  binary.BigEndian.Uint32(header[2:6]),
+		Nonce:    binary.BigEndian.Uint64(header[6:14]),
+		Data:     recordData,
+	}, nil
+}
@@ -0,0 +1,223 @@
+package archive
+
+import (
+	"encoding/binary"
+	"sync"
+
+	"github.com/ME-MotherEarth/me-core/core/check"
+	coreData "github.com/ME-MotherEarth/me-core/data"
+	"github.com/ME-MotherEarth/me-core/data/indexer"
+	elasticIndexer "github.com/ME-MotherEarth/me-elastic-indexer"
+	"github.com/ME-MotherEarth/me-elastic-indexer/data"
+	"github.com/ME-MotherEarth/me-elastic-indexer/payload"
+)
+
+// ArgsRecorderIndexer holds all dependencies required by the recorder indexer in order to create new instances
+type ArgsRecorderIndexer struct {
+	Indexer          elasticIndexer.Indexer
+	Writer           RecordWriter
+	Codec            PayloadCodec
+	ShardCoordinator elasticIndexer.ShardCoordinator
+}
+
+type recorderIndexer struct {
+	indexer elasticIndexer.Indexer
+	writer  RecordWriter
+	codec   PayloadCodec
+	selfID  uint32
+
+	mutRecord sync.Mutex
+	lastNonce uint64
+	lostCalls map[uint32]*lostCalls
+}
+
+// lostCalls holds the calls of a shard that could not be recorded since the last gap record
+type lostCalls struct {
+	nonce    uint64
+	numCalls uint32
+}
+
+// NewRecorderIndexer will create an indexer that writes every call in the archive before passing it to the wrapped
+// indexer. A call that cannot be recorded is still passed to the wrapped indexer, so the archive never stops the
+// indexing, and a gap record is written in its place as soon as the archive can be written again
+func NewRecorderIndexer(args ArgsRecorderIndexer) (*recorderIndexer, error) {
+	if check.IfNil(args.Indexer) {
+		return nil, ErrNilIndexer
+	}
+	if check.IfNil(args.Writer) {
+		return nil, ErrNilRecordWriter
+	}
+	if check.IfNil(args.Codec) {
+		return nil, ErrNilPayloadCodec
+	}
+	if check.IfNil(args.ShardCoordinator) {
+		return nil, ErrNilShardCoordinator
+	}
+
+	return &recorderIndexer{
+		indexer:   args.Indexer,
+		writer:    args.Writer,
+		codec:     args.Codec,
+		selfID:    args.ShardCoordinator.SelfId(),
+		lostCalls: make(map[uint32]*lostCalls),
+	}, nil
+}
+
+// SaveBlock will record the block and will pass it to the wrapped indexer
+func (ri *recorderIndexer) SaveBlock(args *indexer.ArgsSaveBlockData) error {
+	if args != nil && !check.IfNil(args.Header) {
+		ri.recordBlock(payload.NewSaveBlock(args), args.Header)
+	}
+
+	return ri.indexer.SaveBlock(args)
+}
+
+// RevertIndexedBlock will record the reverted block and will pass it to the wrapped indexer
+func (ri *recorderIndexer) RevertIndexedBlock(header coreData.HeaderHandler, body coreData.BodyHandler) error {
+	if !check.IfNil(header) {
+		ri.recordBlock(payload.NewRevertIndexedBlock(header, body), header)
+	}
+
+	return ri.indexer.RevertIndexedBlock(header, body)
+}
+
+// SaveRoundsInfo will record the rounds info and will pass them to the wrapped indexer
+func (ri *recorderIndexer) SaveRoundsInfo(roundsInfos []*indexer.RoundInfo) error {
+	ri.record(payload.NewSaveRoundsInfo(roundsInfos))
+
+	return ri.indexer.SaveRoundsInfo(roundsInfos)
+}
+
+// SaveValidatorsPubKeys will record the validators public keys and will pass them to the wrapped indexer
+func (ri *recorderIndexer) SaveValidatorsPubKeys(validatorsPubKeys map[uint32][][]byte, epoch uint32) error {
+	ri.record(payload.NewSaveValidatorsPubKeys(validatorsPubKeys, epoch))
+
+	return ri.indexer.SaveValidatorsPubKeys(validatorsPubKeys, epoch)
+}
+
+// SaveValidatorsRating will record the validators rating and will pass it to the wrapped indexer
+func (ri *recorderIndexer) SaveValidatorsRating(indexID string, infoRating []*indexer.ValidatorRatingInfo) error {
+	ri.record(payload.NewSaveValidatorsRating(indexID, infoRating))
+
+	return ri.indexer.SaveValidatorsRating(indexID, infoRating)
+}
+
+// SaveAccounts will record the accounts and will pass them to the wrapped indexer
+func (ri *recorderIndexer) SaveAccounts(blockTimestamp uint64, accounts []coreData.UserAccountHandler) error {
+	ri.record(payload.NewSaveAccounts(blockTimestamp, accounts))
+
+	return ri.indexer.SaveAccounts(blockTimestamp, accounts)
+}
+
+// FinalizedBlock will record the hash of the finalized block and will pass it to the wrapped indexer
+func (ri *recorderIndexer) FinalizedBlock(headerHash []byte) error {
+	ri.record(payload.NewFinalizedBlock(headerHash))
+
+	return ri.indexer.FinalizedBlock(headerHash)
+}
+
+func (ri *recorderIndexer) recordBlock(p *payload.Payload, header coreData.HeaderHandler) {
+	ri.mutRecord.Lock()
+	defer ri.mutRecord.Unlock()
+
+	ri.lastNonce = header.GetNonce()
+	ri.append(p, header.GetShardID(), true)
+}
+
+func (ri *recorderIndexer) record(p *payload.Payload) {
+	ri.mutRecord.Lock()
+	defer ri.mutRecord.Unlock()
+
+	ri.append(p, ri.selfID, false)
+}
+
+func (ri *recorderIndexer) append(p *payload.Payload, shardID uint32, hasBlock bool) {
+	ri.recordLostCalls()
+
+	buff, err := ri.codec.Encode(p)
+	if err != nil {
+		log.Warn("recorderIndexer: cannot encode the call, a gap will be recorded", "type", p.Type, "error", err.Error())
+		ri.addLostCall(shardID)
+		ri.recordLostCalls()
+		return
+	}
+
+	err = ri.writer.Append(&Record{
+		Type:     p.Type,
+		HasBlock: hasBlock,
+		ShardID:  shardID,
+		Nonce:    ri.lastNonce,
+		Data:     buff,
+	})
+	if err != nil {
+		log.Warn("recorderIndexer: cannot record the call, a gap will be recorded", "type", p.Type, "error", err.Error())
+		ri.addLostCall(shardID)
+	}
+}
+
+func (ri *recorderIndexer) addLostCall(shardID uint32) {
+	lost, found := ri.lostCalls[shardID]
+	if !found {
+		lost = &lostCalls{nonce: ri.lastNonce}
+		ri.lostCalls[shardID] = lost
+	}
+
+	lost.numCalls++
+}
+
+// recordLostCalls will write a gap record for every shard that has calls that could not be recorded
+func (ri *recorderIndexer) recordLostCalls() {
+	for shardID, lost := range ri.lostCalls {
+		numCalls := make([]byte, 4)
+		binary.BigEndian.PutUint32(numCalls, lost.numCalls)
+
+		err := ri.writer.Append(&Record{
+			IsGap:   true,
+			ShardID: shardID,
+			Nonce:   lost.nonce,
+			Data:    numCalls,
+		})
+		if err != nil {
+			log.Warn("recorderIndexer: cannot record the gap", "shard", shardID, "nonce", lost.nonce,
+				"num lost calls", lost.numCalls, "error", err.Error())
+			return
+		}
+
+		delete(ri.lostCalls, shardID)
+	}
+}
+
+// GetCheckpoints returns the checkpoints of the wrapped indexer
+func (ri *recorderIndexer) GetCheckpoints() ([]*data.Checkpoint, error) {
+	return ri.indexer.GetCheckpoints()
+}
+
+// Close will close the archive and the wrapped indexer. The calls that could not be recorded and have no gap record
+// yet are logged
+func (ri *recorderIndexer) Close() error {
+	ri.mutRecord.Lock()
+	ri.recordLostCalls()
+	for shardID, lost := range ri.lostCalls {
+		log.Error("recorderIndexer: calls were not recorded and the archive has no gap for them",
+			"shard", shardID, "nonce", lost.nonce, "num lost calls", lost.numCalls)
+	}
+	ri.mutRecord.Unlock()
+
+	errWriter := ri.writer.Close()
+	errIndexer := ri.indexer.Close()
+	if errWriter != nil {
+		return errWriter
+	}
+
+	return errIndexer
+}
+
+// IsNilIndexer returns true if the wrapped indexer is a nil indexer
+func (ri *recorderIndexer) IsNilIndexer() bool {
+	return ri.indexer.IsNilIndexer()
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (ri *recorderIndexer) IsInterfaceNil() bool {
+	return ri == nil
+}
@@ -0,0 +1,296 @@
+package archive
+
+import (
+	"errors"
+	"testing"
+
+	coreData "github.com/ME-MotherEarth/me-core/data"
+	dataBlock "github.com/ME-MotherEarth/me-core/data/block"
+	"github.com/ME-MotherEarth/me-core/data/indexer"
+	"github.com/ME-MotherEarth/me-elastic-indexer/data"
+	"github.com/ME-MotherEarth/me-elastic-indexer/mock"
+	"github.com/ME-MotherEarth/me-elastic-indexer/payload"
+	"github.com/stretchr/testify/require"
+)
+
+type replayedCall struct {
+	callType payload.Type
+	shardID  uint32
+	nonce    uint64
+}
+
+func createCodec() PayloadCodec {
+	codec, _ := payload.NewCodec(&mock.MarshalizerMock{})
+	return codec
+}
+
+func createRecorder(t *testing.T, directory string, selfID uint32, inner *mock.IndexerStub) *recorderIndexer {
+	w, err := NewWriter(ArgsWriter{Directory: directory, MaxSegmentSize: 1 << 20})
+	require.Nil(t, err)
+
+	ri, err := NewRecorderIndexer(ArgsRecorderIndexer{
+		Indexer:          inner,
+		Writer:           w,
+		Codec:            createCodec(),
+		ShardCoordinator: &mock.ShardCoordinatorMock{SelfID: selfID},
+	})
+	require.Nil(t, err)
+
+	return ri
+}
+
+// recordShard records the calls made by a node of the provided shard for two blocks, the second one being reverted
+func recordShard(t *testing.T, directory string, shardID uint32) {
+	numForwarded := 0
+	inner := &mock.IndexerStub{
+		SaveBlockCalled: func(_ *indexer.ArgsSaveBlockData) error {
+			numForwarded++
+			return nil
+		},
+	}
+	ri := createRecorder(t, directory, shardID, inner)
+
+	require.Nil(t, ri.SaveValidatorsPubKeys(map[uint32][][]byte{shardID: {[]byte("pk")}}, 0))
+	for nonce := uint64(1); nonce <= 2; nonce++ {
+		require.Nil(t, ri.SaveBlock(&indexer.ArgsSaveBlockData{
+			HeaderHash: []byte("hash"),
+			Header:     &dataBlock.Header{Nonce: nonce, ShardID: shardID},
+			Body:       &dataBlock.Body{},
+			AlteredAccounts: map[string]*indexer.AlteredAccount{
+				"addr": {Address: "addr", Balance: "10"},
+			},
+		}))
+		require.Nil(t, ri.SaveRoundsInfo([]*indexer.RoundInfo{{Index: nonce, ShardId: shardID}}))
+	}
+	require.Nil(t, ri.RevertIndexedBlock(&dataBlock.Header{Nonce: 2, ShardID: shardID}, &dataBlock.Body{}))
+	require.Nil(t, ri.FinalizedBlock([]byte("hash")))
+	require.Nil(t, ri.Close())
+
+	require.Equal(t, 2, numForwarded)
+}
+
+// failingWriter fails to append the records while fail is set
+type failingWriter struct {
+	RecordWriter
+	fail bool
+}
+
+func (fw *failingWriter) Append(record *Record) error {
+	if fw.fail {
+		return errors.New("append failed")
+	}
+
+	return fw.RecordWriter.Append(record)
+}
+
+func createReplayIndexer(calls *[]*replayedCall) *mock.IndexerStub {
+	var lastShard uint32
+	var lastNonce uint64
+	return &mock.IndexerStub{
+		SaveBlockCalled: func(args *indexer.ArgsSaveBlockData) error {
+			lastShard, lastNonce = args.Header.GetShardID(), args.Header.GetNonce()
+			*calls = append(*calls, &replayedCall{payload.SaveBlock, lastShard, lastNonce})
+			return nil
+		},
+		RevertIndexedBlockCalled: func(header coreData.HeaderHandler, _ coreData.BodyHandler) error {
+			*calls = append(*calls, &replayedCall{payload.RevertIndexedBlock, header.GetShardID(), header.GetNonce()})
+			return nil
+		},
+		SaveRoundsInfoCalled: func(roundsInfos []*indexer.RoundInfo) error {
+			*calls = append(*calls, &replayedCall{payload.SaveRoundsInfo, roundsInfos[0].ShardId, roundsInfos[0].Index})
+			return nil
+		},
+		SaveValidatorsPubKeysCalled: func(_ map[uint32][][]byte, _ uint32) error {
+			*calls = append(*calls, &replayedCall{callType: payload.SaveValidatorsPubKeys})
+			return nil
+		},
+		FinalizedBlockCalled: func(_ []byte) error {
+			*calls = append(*calls, &replayedCall{payload.FinalizedBlock, lastShard, lastNonce})
+			return nil
+		},
+	}
+}
+
+func TestNewRecorderIndexer(t *testing.T) {
+	t.Parallel()
+
+	w, _ := NewWriter(ArgsWriter{Directory: t.TempDir(), MaxSegmentSize: 1})
+	args := ArgsRecorderIndexer{
+		Indexer:          &mock.IndexerStub{},
+		Writer:           w,
+		Codec:            createCodec(),
+		ShardCoordinator: &mock.ShardCoordinatorMock{},
+	}
+
+	argsNilIndexer := args
+	argsNilIndexer.Indexer = nil
+	_, err := NewRecorderIndexer(argsNilIndexer)
+	require.Equal(t, ErrNilIndexer, err)
+
+	argsNilWriter := args
+	argsNilWriter.Writer = nil
+	_, err = NewRecorderIndexer(argsNilWriter)
+	require.Equal(t, ErrNilRecordWriter, err)
+
+	argsNilCodec := args
+	argsNilCodec.Codec = nil
+	_, err = NewRecorderIndexer(argsNilCodec)
+	require.Equal(t, ErrNilPayloadCodec, err)
+
+	argsNilCoordinator := args
+	argsNilCoordinator.ShardCoordinator = nil
+	_, err = NewRecorderIndexer(argsNilCoordinator)
+	require.Equal(t, ErrNilShardCoordinator, err)
+
+	ri, err := NewRecorderIndexer(args)
+	require.Nil(t, err)
+	require.False(t, ri.IsInterfaceNil())
+	require.False(t, ri.IsNilIndexer())
+}
+
+func TestRecorderIndexer_ShouldForwardCallsWhenRecordingFails(t *testing.T) {
+	t.Parallel()
+
+	ri := createRecorder(t, t.TempDir(), 0, &mock.IndexerStub{})
+	require.Nil(t, ri.writer.Close())
+
+	expectedErr := errors.New("expected error")
+	ri.indexer = &mock.IndexerStub{
+		SaveRoundsInfoCalled: func(_ []*indexer.RoundInfo) error {
+			return expectedErr
+		},
+	}
+	require.Equal(t, expectedErr, ri.SaveRoundsInfo([]*indexer.RoundInfo{{Index: 1}}))
+}
+
+func TestRecorderIndexer_CallsThatCannotBeRecordedShouldBeReportedByReplay(t *testing.T) {
+	t.Parallel()
+
+	directory := t.TempDir()
+	ri := createRecorder(t, directory, 0, &mock.IndexerStub{})
+	writer := &failingWriter{RecordWriter: ri.writer}
+	ri.writer = writer
+
+	saveBlock := func(nonce uint64) {
+		require.Nil(t, ri.SaveBlock(&indexer.ArgsSaveBlockData{
+			HeaderHash: []byte("hash"),
+			Header:     &dataBlock.Header{Nonce: nonce},
+			Body:       &dataBlock.Body{},
+		}))
+	}
+
+	saveBlock(1)
+	writer.fail = true
+	saveBlock(2)
+	require.Nil(t, ri.SaveRoundsInfo([]*indexer.RoundInfo{{Index: 2}}))
+	saveBlock(3)
+	writer.fail = false
+	saveBlock(4)
+	require.Nil(t, ri.Close())
+
+	calls := make([]*replayedCall, 0)
+	report, err := Replay(ArgsReplay{
+		Directory: directory,
+		Codec:     createCodec(),
+		Indexer:   createReplayIndexer(&calls),
+	})
+	require.Nil(t, err)
+	require.Equal(t, 2, report.NumReplayed)
+	require.Equal(t, map[uint32][]*data.NonceRange{0: {{From: 2, To: 3}}}, report.MissingNonces)
+	require.Equal(t, map[uint32]uint32{0: 3}, report.NumLostCalls)
+}
+
+func TestReplay_ShouldFilterByNonceAndShard(t *testing.T) {
+	t.Parallel()
+
+	directory := t.TempDir()
+	recordShard(t, directory, 0)
+	recordShard(t, directory, 1)
+
+	t.Run("all the records", func(t *testing.T) {
+		t.Parallel()
+
+		calls := make([]*replayedCall, 0)
+		report, err := Replay(ArgsReplay{
+			Directory: directory,
+			Codec:     createCodec(),
+			Indexer:   createReplayIndexer(&calls),
+		})
+		require.Nil(t, err)
+		require.Equal(t, 14, report.NumReplayed)
+		require.Len(t, calls, 14)
+		require.Empty(t, report.MissingNonces)
+		require.Empty(t, report.NumLostCalls)
+	})
+	t.Run("nonce range of one shard", func(t *testing.T) {
+		t.Parallel()
+
+		calls := make([]*replayedCall, 0)
+		report, err := Replay(ArgsReplay{
+			Directory: directory,
+			Codec:     createCodec(),
+			Indexer:   createReplayIndexer(&calls),
+			Filter: Filter{
+				FromNonce: 2,
+				ToNonce:   2,
+				ShardIDs:  []uint32{1},
+			},
+		})
+		require.Nil(t, err)
+		require.Equal(t, 4, report.NumReplayed)
+		require.Equal(t, []*replayedCall{
+			{payload.SaveBlock, 1, 2},
+			{payload.SaveRoundsInfo, 1, 2},
+			{payload.RevertIndexedBlock, 1, 2},
+			{payload.FinalizedBlock, 1, 2},
+		}, calls)
+	})
+	t.Run("accounts cache should be updated before the block is replayed", func(t *testing.T) {
+		t.Parallel()
+
+		numUpdates := 0
+		accountsCache := &mock.AccountsCacheStub{
+			UpdateAlteredAccountsCalled: func(alteredAccounts map[string]*indexer.AlteredAccount) {
+				require.Equal(t, "10", alteredAccounts["addr"].Balance)
+				numUpdates++
+			},
+		}
+		calls := make([]*replayedCall, 0)
+		_, err := Replay(ArgsReplay{
+			Directory:     directory,
+			Codec:         createCodec(),
+			Indexer:       createReplayIndexer(&calls),
+			AccountsCache: accountsCache,
+			Filter:        Filter{ShardIDs: []uint32{0}},
+		})
+		require.Nil(t, err)
+		require.Equal(t, 2, numUpdates)
+	})
+	t.Run("invalid nonce range should error", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := Replay(ArgsReplay{
+			Directory: directory,
+			Codec:     createCodec(),
+			Indexer:   &mock.IndexerStub{},
+			Filter:    Filter{FromNonce: 3, ToNonce: 2},
+		})
+		require.True(t, errors.Is(err, ErrInvalidNonceRange))
+	})
+	t.Run("indexer error should stop the replay", func(t *testing.T) {
+		t.Parallel()
+
+		expectedErr := errors.New("expected error")
+		report, err := Replay(ArgsReplay{
+			Directory: directory,
+			Codec:     createCodec(),
+			Indexer: &mock.IndexerStub{
+				SaveBlockCalled: func(_ *indexer.ArgsSaveBlockData) error {
+					return expectedErr
+				},
+			},
+		})
+		require.True(t, errors.Is(err, expectedErr))
+		require.Equal(t, 1, report.NumReplayed)
+	})
+}
@@ -0,0 +1,158 @@
+package archive
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/ME-MotherEarth/me-core/core/check"
+	"github.com/ME-MotherEarth/me-elastic-indexer/data"
+	"github.com/ME-MotherEarth/me-elastic-indexer/payload"
+)
+
+// Filter selects the records of an archive that are replayed. A zero ToNonce means there is no upper limit and an
+// empty ShardIDs means all the shards are replayed
+type Filter struct {
+	FromNonce uint64
+	ToNonce   uint64
+	ShardIDs  []uint32
+}
+
+// ArgsReplay holds all dependencies required to replay an archive. The AccountsCache is optional: if provided, it is
+// updated with the accounts read from the archive before they are indexed
+type ArgsReplay struct {
+	Directory     string
+	Codec         PayloadCodec
+	Indexer       payload.CallsHandler
+	AccountsCache AccountsCacheHandler
+	Filter        Filter
+}
+
+// ReplayReport holds the outcome of an archive replay
+type ReplayReport struct {
+	NumReplayed int
+	// MissingNonces holds, for every shard, the block nonces skipped between two consecutive blocks of the archive
+	MissingNonces map[uint32][]*data.NonceRange
+	// NumLostCalls holds, for every shard, the number of calls that could not be recorded, as found in the gap records
+	NumLostCalls map[uint32]uint32
+}
+
+// Replay will make on the provided indexer, in the order they were recorded, the calls of the archive selected by the
+// filter. The calls that are not made for a block are selected by the shard and the nonce of the last block recorded
+// before them. The returned report holds the number of replayed calls and the discontinuities found in the archive
+func Replay(args ArgsReplay) (*ReplayReport, error) {
+	if check.IfNil(args.Codec) {
+		return nil, ErrNilPayloadCodec
+	}
+	if args.Indexer == nil {
+		return nil, ErrNilIndexer
+	}
+	if args.Filter.ToNonce != 0 && args.Filter.FromNonce > args.Filter.ToNonce {
+		return nil, fmt.Errorf("%w: from %d to %d", ErrInvalidNonceRange, args.Filter.FromNonce, args.Filter.ToNonce)
+	}
+
+	archiveReader, err := NewReader(args.Directory)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = archiveReader.Close()
+	}()
+
+	report := &ReplayReport{
+		MissingNonces: make(map[uint32][]*data.NonceRange),
+		NumLostCalls:  make(map[uint32]uint32),
+	}
+	lastNonces := make(map[uint32]uint64)
+	for {
+		record, errNext := archiveReader.Next()
+		if errNext == io.EOF {
+			return report, nil
+		}
+		if errNext != nil {
+			return report, errNext
+		}
+		if !args.Filter.selects(record) {
+			continue
+		}
+		if record.IsGap {
+			report.addGap(record)
+			continue
+		}
+		if record.HasBlock && record.Type == payload.SaveBlock {
+			report.checkNonce(lastNonces, record)
+		}
+
+		p, errDecode := args.Codec.Decode(record.Data)
+		if errDecode != nil {
+			return report, fmt.Errorf("%w while decoding the record of shard %d, nonce %d",
+				errDecode, record.ShardID, record.Nonce)
+		}
+
+		updateAccountsCache(args.AccountsCache, p)
+		err = payload.Apply(p, args.Indexer)
+		if err != nil {
+			return report, fmt.Errorf("%w while replaying the record of shard %d, nonce %d",
+				err, record.ShardID, record.Nonce)
+		}
+
+		report.NumReplayed++
+	}
+}
+
+func (rr *ReplayReport) addGap(record *Record) {
+	numLostCalls := uint32(0)
+	if len(record.Data) == 4 {
+		numLostCalls = binary.BigEndian.Uint32(record.Data)
+	}
+
+	rr.NumLostCalls[record.ShardID] += numLostCalls
+}
+
+// checkNonce will record the nonces skipped before the provided block. A block with a nonce that is not greater than
+// the last one was saved again after a revert, so it does not leave a gap
+func (rr *ReplayReport) checkNonce(lastNonces map[uint32]uint64, record *Record) {
+	lastNonce, found := lastNonces[record.ShardID]
+	if found && record.Nonce <= lastNonce {
+		return
+	}
+	if found && record.Nonce > lastNonce+1 {
+		missing := &data.NonceRange{From: lastNonce + 1, To: record.Nonce - 1}
+		rr.MissingNonces[record.ShardID] = append(rr.MissingNonces[record.ShardID], missing)
+	}
+
+	lastNonces[record.ShardID] = record.Nonce
+}
+
+func (f *Filter) selects(record *Record) bool {
+	if record.Nonce < f.FromNonce {
+		return false
+	}
+	if f.ToNonce != 0 && record.Nonce > f.ToNonce {
+		return false
+	}
+	if len(f.ShardIDs) == 0 {
+		return true
+	}
+
+	for _, shardID := range f.ShardIDs {
+		if shardID == record.ShardID {
+			return true
+		}
+	}
+
+	return false
+}
+
+func updateAccountsCache(accountsCache AccountsCacheHandler, p *payload.Payload) {
+	if check.IfNil(accountsCache) {
+		return
+	}
+
+	switch p.Type {
+	case payload.SaveBlock:
+		accountsCache.UpdateAlteredAccounts(p.ArgsSaveBlock.AlteredAccounts)
+	case payload.SaveAccounts:
+		accountsCache.UpdateAccounts(p.Accounts)
+	}
+}
@@ -0,0 +1,176 @@
+package archive
+
+import (
+	"compress/gzip"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/ME-MotherEarth/me-elastic-indexer/queue"
+	logger "github.com/ME-MotherEarth/me-logger"
+)
+
+const (
+	segmentFilePattern = "segment-%08d.gz"
+	segmentFileGlob    = "segment-*.gz"
+	filePermission     = 0644
+	dirPermission      = 0755
+)
+
+var log = logger.GetOrCreate("indexer/archive")
+
+// ArgsWriter holds all dependencies required by the archive writer in order to create new instances
+type ArgsWriter struct {
+	Directory      string
+	MaxSegmentSize int64
+}
+
+// countingWriter counts the bytes written in the segment file, after compression
+type countingWriter struct {
+	file    *os.File
+	written int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.file.Write(p)
+	cw.written += int64(n)
+	return n, err
+}
+
+type writer struct {
+	directory      string
+	maxSegmentSize int64
+
+	mutex       sync.Mutex
+	nextSegment int
+	file        *countingWriter
+	gzipWriter  *gzip.Writer
+	closed      bool
+}
+
+// NewWriter will create a writer that appends records to gzip compressed segment files placed in the provided
+// directory. Every writer starts a new segment, after the ones left by the previous runs, and moves to the next segment
+// when the current one exceeds MaxSegmentSize bytes. Each record is flushed, so a crash loses at most the record that
+// was being written
+func NewWriter(args ArgsWriter) (*writer, error) {
+	if args.Directory == "" {
+		return nil, ErrEmptyDirectory
+	}
+	if args.MaxSegmentSize <= 0 {
+		return nil, fmt.Errorf("%w: %d", ErrInvalidMaxSegmentSize, args.MaxSegmentSize)
+	}
+
+	err := os.MkdirAll(args.Directory, dirPermission)
+	if err != nil {
+		return nil, err
+	}
+
+	segments, err := listSegments(args.Directory)
+	if err != nil {
+		return nil, err
+	}
+
+	nextSegment := 0
+	if len(segments) > 0 {
+		nextSegment = segments[len(segments)-1].index + 1
+	}
+
+	return &writer{
+		directory:      args.Directory,
+		maxSegmentSize: args.MaxSegmentSize,
+		nextSegment:    nextSegment,
+	}, nil
+}
+
+// Append will add the provided record at the end of the archive
+func (w *writer) Append(record *Record) error {
+	if len(record.Data) > queue.MaxFrameDataSize {
+		return fmt.Errorf("%w: %d bytes", ErrRecordTooLarge, len(record.Data))
+	}
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.closed {
+		return ErrWriterClosed
+	}
+
+	if w.gzipWriter == nil {
+		err := w.openSegment()
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err := w.gzipWriter.Write(encodeRecord(record))
+	if err != nil {
+		return err
+	}
+
+	err = w.gzipWriter.Flush()
+	if err != nil {
+		return err
+	}
+
+	if w.file.written < w.maxSegmentSize {
+		return nil
+	}
+
+	return w.closeSegment()
+}
+
+func (w *writer) openSegment() error {
+	path := filepath.Join(w.directory, fmt.Sprintf(segmentFilePattern, w.nextSegment))
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, filePermission)
+	if err != nil {
+		return err
+	}
+
+	log.Debug("archive writer opened a new segment", "path", path)
+
+	w.nextSegment++
+	w.file = &countingWriter{file: file}
+	w.gzipWriter = gzip.NewWriter(w.file)
+
+	return nil
+}
+
+func (w *writer) closeSegment() error {
+	if w.gzipWriter == nil {
+		return nil
+	}
+
+	errGzip := w.gzipWriter.Close()
+	errSync := w.file.file.Sync()
+	errClose := w.file.file.Close()
+	w.gzipWriter = nil
+	w.file = nil
+
+	switch {
+	case errGzip != nil:
+		return errGzip
+	case errSync != nil:
+		return errSync
+	default:
+		return errClose
+	}
+}
+
+// Close will close the current segment
+func (w *writer) Close() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.closed {
+		return nil
+	}
+
+	w.closed = true
+	return w.closeSegment()
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (w *writer) IsInterfaceNil() bool {
+	return w == nil
+}
@@ -0,0 +1,123 @@
+package archive
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ME-MotherEarth/me-elastic-indexer/payload"
+	"github.com/stretchr/testify/require"
+)
+
+func readAll(t *testing.T, directory string) []*Record {
+	archiveReader, err := NewReader(directory)
+	require.Nil(t, err)
+	defer func() {
+		_ = archiveReader.Close()
+	}()
+
+	records := make([]*Record, 0)
+	for {
+		record, errNext := archiveReader.Next()
+		if errNext == io.EOF {
+			return records
+		}
+		require.Nil(t, errNext)
+		records = append(records, record)
+	}
+}
+
+func TestNewWriter(t *testing.T) {
+	t.Parallel()
+
+	w, err := NewWriter(ArgsWriter{MaxSegmentSize: 1})
+	require.Nil(t, w)
+	require.Equal(t, ErrEmptyDirectory, err)
+
+	w, err = NewWriter(ArgsWriter{Directory: t.TempDir()})
+	require.Nil(t, w)
+	require.True(t, errors.Is(err, ErrInvalidMaxSegmentSize))
+
+	w, err = NewWriter(ArgsWriter{Directory: t.TempDir(), MaxSegmentSize: 1})
+	require.Nil(t, err)
+	require.False(t, w.IsInterfaceNil())
+}
+
+func TestWriter_AppendAndRead(t *testing.T) {
+	t.Parallel()
+
+	directory := t.TempDir()
+	w, _ := NewWriter(ArgsWriter{Directory: directory, MaxSegmentSize: 1 << 20})
+
+	records := []*Record{
+		{Type: payload.SaveBlock, HasBlock: true, ShardID: 1, Nonce: 10, Data: []byte("block")},
+		{Type: payload.SaveRoundsInfo, ShardID: 1, Nonce: 10, Data: []byte("rounds")},
+		{Type: payload.FinalizedBlock, ShardID: 1, Nonce: 10},
+	}
+	for _, record := range records {
+		require.Nil(t, w.Append(record))
+	}
+	require.Nil(t, w.Close())
+	require.Equal(t, ErrWriterClosed, w.Append(records[0]))
+
+	read := readAll(t, directory)
+	require.Len(t, read, 3)
+	require.Equal(t, records[0], read[0])
+	require.Equal(t, records[1], read[1])
+	require.Equal(t, records[2].Nonce, read[2].Nonce)
+	require.Empty(t, read[2].Data)
+}
+
+func TestWriter_ShouldRotateSegments(t *testing.T) {
+	t.Parallel()
+
+	directory := t.TempDir()
+	w, _ := NewWriter(ArgsWriter{Directory: directory, MaxSegmentSize: 1})
+	require.Nil(t, w.Append(&Record{Type: payload.SaveBlock, Nonce: 1, Data: []byte("first")}))
+	require.Nil(t, w.Append(&Record{Type: payload.SaveBlock, Nonce: 2, Data: []byte("second")}))
+	require.Nil(t, w.Close())
+
+	// a new writer continues with a new segment
+	w, _ = NewWriter(ArgsWriter{Directory: directory, MaxSegmentSize: 1 << 20})
+	require.Nil(t, w.Append(&Record{Type: payload.SaveBlock, Nonce: 3, Data: []byte("third")}))
+	require.Nil(t, w.Close())
+
+	segments, _ := listSegments(directory)
+	require.Len(t, segments, 3)
+	require.Equal(t, "segment-00000002.gz", filepath.Base(segments[2].path))
+
+	read := readAll(t, directory)
+	require.Len(t, read, 3)
+	for idx, record := range read {
+		require.Equal(t, uint64(idx+1), record.Nonce)
+	}
+}
+
+func TestReader_ShouldIgnoreTruncatedRecords(t *testing.T) {
+	t.Parallel()
+
+	directory := t.TempDir()
+	w, _ := NewWriter(ArgsWriter{Directory: directory, MaxSegmentSize: 1 << 20})
+	require.Nil(t, w.Append(&Record{Type: payload.SaveBlock, Nonce: 1, Data: []byte("first")}))
+	require.Nil(t, w.Append(&Record{Type: payload.SaveBlock, Nonce: 2, Data: []byte("second record")}))
+
+	// simulate a crash while the second record was written
+	segmentPath := filepath.Join(directory, "segment-00000000.gz")
+	info, err := os.Stat(segmentPath)
+	require.Nil(t, err)
+	require.Nil(t, os.Truncate(segmentPath, info.Size()-8))
+
+	// an empty segment, as left by a crash before the first record
+	require.Nil(t, os.WriteFile(filepath.Join(directory, "segment-00000001.gz"), nil, filePermission))
+
+	w2, _ := NewWriter(ArgsWriter{Directory: directory, MaxSegmentSize: 1 << 20})
+	require.Nil(t, w2.Append(&Record{Type: payload.SaveBlock, Nonce: 3, Data: []byte("third")}))
+	require.Nil(t, w2.Close())
+
+	read := readAll(t, directory)
+	require.Len(t, read, 2)
+	require.Equal(t, uint64(1), read[0].Nonce)
+	require.Equal(t, uint64(3), read[1].Nonce)
+}
@@ -0,0 +1,338 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	indexer "github.com/ME-MotherEarth/me-elastic-indexer"
+	"github.com/ME-MotherEarth/me-elastic-indexer/data"
+	"github.com/elastic/go-elasticsearch/v7/esapi"
+)
+
+const (
+	maxBulkRetries       = 3
+	bulkRetryDelay       = time.Second
+	operationDelete      = "delete"
+	operationCreate      = "create"
+	bulkLinesSeparator   = '\n'
+	documentKeySeparator = "/"
+)
+
+// bulkAction holds the lines of one action from a bulk request body, so it can be sent again on its own
+type bulkAction struct {
+	operation string
+	index     string
+	id        string
+	lines     []byte
+}
+
+func (ba *bulkAction) key() string {
+	return ba.index + documentKeySeparator + ba.id
+}
+
+// bulkResult holds the outcome of a bulk request, with the actions that have to be sent again
+type bulkResult struct {
+	retry           []*bulkAction
+	rejected        []*data.RejectedDocument
+	numFailed       int
+	numThrottled    int
+	retryErrorsText string
+}
+
+// splitBulkActions will split a bulk request body in actions. Every action has a metadata line, followed by the
+// document line, except the delete actions which have only the metadata line
+func splitBulkActions(body []byte, defaultIndex string) ([]*bulkAction, error) {
+	actions := make([]*bulkAction, 0)
+	for len(body) > 0 {
+		metaLine, rest := nextLine(body)
+		body = rest
+		if len(bytes.TrimSpace(metaLine)) == 0 {
+			continue
+		}
+
+		action, err := parseBulkMeta(metaLine, defaultIndex)
+		if err != nil {
+			return nil, err
+		}
+
+		action.lines = metaLine
+		if action.operation != operationDelete {
+			var sourceLine []byte
+			sourceLine, body = nextLine(body)
+			action.lines = append(append(make([]byte, 0, len(metaLine)+len(sourceLine)), metaLine...), sourceLine...)
+		}
+
+		actions = append(actions, action)
+	}
+
+	return actions, nil
+}
+
+// nextLine returns the first line, including its line separator, and the rest of the provided bytes
+func nextLine(body []byte) ([]byte, []byte) {
+	idx := bytes.IndexByte(body, bulkLinesSeparator)
+	if idx < 0 {
+		return append(append(make([]byte, 0, len(body)+1), body...), bulkLinesSeparator), nil
+	}
+
+	return body[:idx+1], body[idx+1:]
+}
+
+func parseBulkMeta(metaLine []byte, defaultIndex string) (*bulkAction, error) {
+	meta := make(map[string]struct {
+		Index string `json:"_index"`
+		ID    string `json:"_id"`
+	})
+	err := json.Unmarshal(metaLine, &meta)
+	if err != nil {
+		return nil, fmt.Errorf("%w while parsing bulk action %s", err, string(metaLine))
+	}
+	if len(meta) != 1 {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidBulkAction, string(metaLine))
+	}
+
+	action := &bulkAction{}
+	for operation, target := range meta {
+		action.operation = operation
+		action.index = target.Index
+		action.id = target.ID
+	}
+	if action.index == "" {
+		action.index = defaultIndex
+	}
+
+	return action, nil
+}
+
+func joinBulkActions(actions []*bulkAction) []byte {
+	size := 0
+	for _, action := range actions {
+		size += len(action.lines)
+	}
+
+	body := make([]byte, 0, size)
+	for _, action := range actions {
+		body = append(body, action.lines...)
+	}
+
+	return body
+}
+
+// doBulkWithPartialRetry will send the actions and will send again only the ones that failed with an error that
+// can go away, such as a throttled request. The documents rejected by elasticsearch are not sent again, they are
+// added to the provided rejected documents, so the rest of the data is still written. A request that is too large is
+// split in half
+func (ec *elasticClient) doBulkWithPartialRetry(actions []*bulkAction, index string, rejected *data.RejectedDocumentsError) error {
+	for attempt := 1; ; attempt++ {
+		result, err := ec.sendBulk(actions, index)
+		if errors.Is(err, ErrRequestEntityTooLarge) {
+			return ec.splitAndSendBulk(actions, index, err, rejected)
+		}
+		if err != nil {
+			return err
+		}
+
+		reportRejectedDocuments(result.rejected)
+		rejected.Documents = append(rejected.Documents, result.rejected...)
+		if len(result.retry) == 0 {
+			return nil
+		}
+		if attempt > maxBulkRetries {
+			return result.retryError()
+		}
+
+		log.Debug("elasticClient.DoBulkRequest: some documents were not written, they will be sent again",
+			"num documents", len(result.retry), "num throttled", result.numThrottled, "attempt", attempt)
+		time.Sleep(ec.bulkRetryDelay * time.Duration(attempt))
+		actions = result.retry
+	}
+}
+
+// splitAndSendBulk will send the two halves of a request that was too large, one after the other, so the changes
+// of a document are still applied in order. A single action that is too large is rejected
+func (ec *elasticClient) splitAndSendBulk(
+	actions []*bulkAction,
+	index string,
+	tooLargeErr error,
+	rejected *data.RejectedDocumentsError,
+) error {
+	if len(actions) == 1 {
+		tooLarge := []*data.RejectedDocument{{
+			Index:     actions[0].index,
+			ID:        actions[0].id,
+			Status:    http.StatusRequestEntityTooLarge,
+			ErrorType: ErrRequestEntityTooLarge.Error(),
+			Reason:    tooLargeErr.Error(),
+			Action:    string(actions[0].lines),
+		}}
+		reportRejectedDocuments(tooLarge)
+		rejected.Documents = append(rejected.Documents, tooLarge...)
+
+		return nil
+	}
+
+	log.Debug("elasticClient.DoBulkRequest: request is too large, it will be split in half",
+		"num documents", len(actions))
+
+	middle := len(actions) / 2
+	err := ec.doBulkWithPartialRetry(actions[:middle], index, rejected)
+	if err != nil {
+		return err
+	}
+
+	return ec.doBulkWithPartialRetry(actions[middle:], index, rejected)
+}
+
+func reportRejectedDocuments(rejected []*data.RejectedDocument) {
+	for _, document := range rejected {
+		log.Warn("elasticClient.DoBulkRequest: document was rejected, it will not be sent again",
+			"index", document.Index, "id", document.ID, "status", document.Status,
+			"type", document.ErrorType, "reason", document.Reason)
+	}
+}
+
+func (ec *elasticClient) sendBulk(actions []*bulkAction, index string) (*bulkResult, error) {
+	options := make([]func(*esapi.BulkRequest), 0)
+	if index != "" {
+		options = append(options, ec.client.Bulk.WithIndex(index))
+	}
+
+	res, err := ec.client.Bulk(
+		bytes.NewReader(joinBulkActions(actions)),
+		options...,
+	)
+	if err != nil {
+		log.Warn("elasticClient.DoBulkRequest",
+			"indexer do bulk request no response", err.Error())
+		return nil, err
+	}
+	defer closeBody(res)
+
+	err = bulkResponseError(res)
+	if err != nil {
+		return nil, err
+	}
+
+	bodyBytes, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("%w cannot read elastic response body bytes", err)
+	}
+
+	return classifyBulkItems(actions, bodyBytes)
+}
+
+// bulkResponseError returns the error of a bulk request that failed as a whole
+func bulkResponseError(res *esapi.Response) error {
+	switch {
+	case res.StatusCode == http.StatusTooManyRequests:
+		return fmt.Errorf("%w: %s", indexer.ErrBackOff, res.String())
+	case res.StatusCode == http.StatusRequestEntityTooLarge:
+		return fmt.Errorf("%w: %s", ErrRequestEntityTooLarge, res.String())
+	case res.StatusCode == http.StatusBadRequest:
+		return fmt.Errorf("%w: %s", indexer.ErrPermanentFailure, res.String())
+	case res.IsError():
+		return fmt.Errorf("%s", res.String())
+	default:
+		return nil
+	}
+}
+
+// classifyBulkItems will match the items of a bulk response with the sent actions. An action that failed with an
+// error that can go away is sent again, together with the next actions on the same document, so the changes of a
+// document are still applied in order
+func classifyBulkItems(actions []*bulkAction, bodyBytes []byte) (*bulkResult, error) {
+	response := BulkRequestResponse{}
+	err := json.Unmarshal(bodyBytes, &response)
+	if err != nil {
+		return nil, err
+	}
+	if len(response.Items) != len(actions) {
+		return nil, fmt.Errorf("%w: sent %d actions, received %d items",
+			ErrUnexpectedBulkResponse, len(actions), len(response.Items))
+	}
+
+	result := &bulkResult{
+		retry:    make([]*bulkAction, 0),
+		rejected: make([]*data.RejectedDocument, 0),
+	}
+	retryKeys := make(map[string]struct{})
+	for idx, action := range actions {
+		_, shouldRetryDocument := retryKeys[action.key()]
+		if shouldRetryDocument {
+			result.retry = append(result.retry, action)
+			continue
+		}
+
+		item := response.Items[idx].Get()
+		switch {
+		case item == nil:
+			return nil, fmt.Errorf("%w: empty item for action %s", ErrUnexpectedBulkResponse, action.operation)
+		case isBulkItemWritten(action, item):
+			continue
+		case isBulkItemRetryable(item):
+			retryKeys[action.key()] = struct{}{}
+			result.retry = append(result.retry, action)
+			if item.Status == http.StatusTooManyRequests {
+				result.numThrottled++
+			}
+			if result.numFailed < numOfErrorsToExtractBulkResponse {
+				result.retryErrorsText += formatBulkItemError(item)
+			}
+			result.numFailed++
+		default:
+			result.rejected = append(result.rejected, &data.RejectedDocument{
+				Index:     item.Index,
+				ID:        item.ID,
+				Status:    item.Status,
+				ErrorType: item.Error.Type,
+				Reason:    item.Error.Reason,
+				Action:    string(action.lines),
+			})
+		}
+	}
+
+	return result, nil
+}
+
+// isBulkItemWritten returns true if the action was applied. Creating a document that already exists or deleting one
+// that does not exist is not an error, as the document ends up in the expected state
+func isBulkItemWritten(action *bulkAction, item *Item) bool {
+	switch {
+	case item.Status < http.StatusBadRequest:
+		return true
+	case action.operation == operationCreate:
+		return item.Status == http.StatusConflict
+	case action.operation == operationDelete:
+		return item.Status == http.StatusNotFound
+	default:
+		return false
+	}
+}
+
+func isBulkItemRetryable(item *Item) bool {
+	return item.Status == http.StatusTooManyRequests ||
+		item.Status == http.StatusConflict ||
+		item.Status == http.StatusRequestTimeout ||
+		item.Status >= http.StatusInternalServerError
+}
+
+func formatBulkItemError(item *Item) string {
+	return fmt.Sprintf(`{ "index": "%s", "id": "%s", "statusCode": %d, "errorType": "%s", "reason": "%s", "causedBy": { "type": "%s", "reason": "%s" }}\n`,
+		item.Index, item.ID, item.Status, item.Error.Type, item.Error.Reason, item.Error.Cause.Type, item.Error.Cause.Reason)
+}
+
+// retryError returns ErrBackOff if all the documents that were not written were throttled, so the caller waits
+// before trying again
+func (br *bulkResult) retryError() error {
+	if br.numThrottled == br.numFailed {
+		return fmt.Errorf("%w: %d documents were throttled", indexer.ErrBackOff, br.numThrottled)
+	}
+
+	return fmt.Errorf("%w: %d documents were not written, errors: %s",
+		ErrBulkDocumentsNotWritten, br.numFailed, br.retryErrorsText)
+}
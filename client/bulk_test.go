@@ -0,0 +1,253 @@
+package client
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	indexer "github.com/ME-MotherEarth/me-elastic-indexer"
+	"github.com/ME-MotherEarth/me-elastic-indexer/data"
+	"github.com/elastic/go-elasticsearch/v7"
+	"github.com/stretchr/testify/require"
+)
+
+const testBulkBody = `{ "index" : { "_index":"blocks", "_id" : "b1" } }
+{"nonce":1}
+{ "delete" : { "_index":"blocks", "_id" : "b2" } }
+{ "update" : { "_index":"accounts", "_id" : "a1" } }
+{"doc":{"balance":"1"}}
+{ "update" : { "_index":"accounts", "_id" : "a1" } }
+{"doc":{"balance":"2"}}
+`
+
+const tooLargeResponse = `{"error":"request entity too large"}`
+
+func TestSplitBulkActions(t *testing.T) {
+	t.Parallel()
+
+	actions, err := splitBulkActions([]byte(testBulkBody), "")
+	require.Nil(t, err)
+	require.Len(t, actions, 4)
+
+	require.Equal(t, "index", actions[0].operation)
+	require.Equal(t, "blocks/b1", actions[0].key())
+	require.Equal(t, "{ \"index\" : { \"_index\":\"blocks\", \"_id\" : \"b1\" } }\n{\"nonce\":1}\n", string(actions[0].lines))
+	require.Equal(t, "delete", actions[1].operation)
+	require.Equal(t, "{ \"delete\" : { \"_index\":\"blocks\", \"_id\" : \"b2\" } }\n", string(actions[1].lines))
+	require.Equal(t, "accounts/a1", actions[2].key())
+	require.Equal(t, testBulkBody, string(joinBulkActions(actions)))
+
+	actions, err = splitBulkActions([]byte(`{ "delete" : { "_id" : "b2" } }`), "blocks")
+	require.Nil(t, err)
+	require.Len(t, actions, 1)
+	require.Equal(t, "blocks/b2", actions[0].key())
+	require.Equal(t, "{ \"delete\" : { \"_id\" : \"b2\" } }\n", string(actions[0].lines))
+
+	_, err = splitBulkActions([]byte("{}\n"), "")
+	require.True(t, errors.Is(err, ErrInvalidBulkAction))
+
+	_, err = splitBulkActions([]byte("not json\n"), "")
+	require.NotNil(t, err)
+}
+
+func TestClassifyBulkItems(t *testing.T) {
+	t.Parallel()
+
+	actions, _ := splitBulkActions([]byte(testBulkBody), "")
+
+	t.Run("items count mismatch should error", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := classifyBulkItems(actions, []byte(`{"errors":false,"items":[]}`))
+		require.True(t, errors.Is(err, ErrUnexpectedBulkResponse))
+	})
+	t.Run("all written", func(t *testing.T) {
+		t.Parallel()
+
+		result, err := classifyBulkItems(actions, []byte(`{"errors":false,"items":[
+			{"index":{"_index":"blocks-000001","_id":"b1","status":201}},
+			{"delete":{"_index":"blocks-000001","_id":"b2","status":404}},
+			{"update":{"_index":"accounts-000001","_id":"a1","status":200}},
+			{"update":{"_index":"accounts-000001","_id":"a1","status":200}}]}`))
+		require.Nil(t, err)
+		require.Empty(t, result.retry)
+		require.Empty(t, result.rejected)
+	})
+	t.Run("version conflict should be retried together with the next changes of the document", func(t *testing.T) {
+		t.Parallel()
+
+		result, err := classifyBulkItems(actions, []byte(`{"errors":true,"items":[
+			{"index":{"_index":"blocks-000001","_id":"b1","status":201}},
+			{"delete":{"_index":"blocks-000001","_id":"b2","status":200}},
+			{"update":{"_index":"accounts-000001","_id":"a1","status":409,"error":{"type":"version_conflict_engine_exception"}}},
+			{"update":{"_index":"accounts-000001","_id":"a1","status":200}}]}`))
+		require.Nil(t, err)
+		require.Equal(t, []*bulkAction{actions[2], actions[3]}, result.retry)
+		require.Equal(t, 1, result.numFailed)
+		require.Equal(t, 0, result.numThrottled)
+		require.False(t, errors.Is(result.retryError(), indexer.ErrBackOff))
+		require.True(t, errors.Is(result.retryError(), ErrBulkDocumentsNotWritten))
+	})
+	t.Run("throttled items should signal back off", func(t *testing.T) {
+		t.Parallel()
+
+		result, err := classifyBulkItems(actions, []byte(`{"errors":true,"items":[
+			{"index":{"_index":"blocks-000001","_id":"b1","status":429,"error":{"type":"es_rejected_execution_exception"}}},
+			{"delete":{"_index":"blocks-000001","_id":"b2","status":200}},
+			{"update":{"_index":"accounts-000001","_id":"a1","status":200}},
+			{"update":{"_index":"accounts-000001","_id":"a1","status":200}}]}`))
+		require.Nil(t, err)
+		require.Equal(t, []*bulkAction{actions[0]}, result.retry)
+		require.True(t, errors.Is(result.retryError(), indexer.ErrBackOff))
+	})
+	t.Run("mapping errors should be rejected", func(t *testing.T) {
+		t.Parallel()
+
+		result, err := classifyBulkItems(actions, []byte(`{"errors":true,"items":[
+			{"index":{"_index":"blocks-000001","_id":"b1","status":400,"error":{"type":"mapper_parsing_exception","reason":"failed to parse field [nonce]"}}},
+			{"delete":{"_index":"blocks-000001","_id":"b2","status":503,"error":{"type":"unavailable_shards_exception"}}},
+			{"update":{"_index":"accounts-000001","_id":"a1","status":200}},
+			{"update":{"_index":"accounts-000001","_id":"a1","status":200}}]}`))
+		require.Nil(t, err)
+		require.Equal(t, []*bulkAction{actions[1]}, result.retry)
+		require.Equal(t, []*data.RejectedDocument{{
+			Index:     "blocks-000001",
+			ID:        "b1",
+			Status:    http.StatusBadRequest,
+			ErrorType: "mapper_parsing_exception",
+			Reason:    "failed to parse field [nonce]",
+			Action:    string(actions[0].lines),
+		}}, result.rejected)
+	})
+	t.Run("creating an existing document is not an error", func(t *testing.T) {
+		t.Parallel()
+
+		createActions, _ := splitBulkActions([]byte("{ \"create\" : { \"_index\":\"tokens\", \"_id\" : \"t1\" } }\n{}\n"), "")
+		result, err := classifyBulkItems(createActions, []byte(`{"errors":true,"items":[
+			{"create":{"_index":"tokens-000001","_id":"t1","status":409,"error":{"type":"version_conflict_engine_exception"}}}]}`))
+		require.Nil(t, err)
+		require.Empty(t, result.retry)
+		require.Empty(t, result.rejected)
+	})
+}
+
+func TestElasticClient_DoBulkRequest(t *testing.T) {
+	t.Parallel()
+
+	mutex := sync.Mutex{}
+	responses := make([]string, 0)
+	requests := make([]string, 0)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		requests = append(requests, string(body))
+		response := responses[0]
+		responses = responses[1:]
+		w.Header().Set("Content-Type", "application/json")
+		if response == tooLargeResponse {
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+		}
+		_, _ = w.Write([]byte(response))
+	}))
+	defer ts.Close()
+
+	esClient, _ := NewElasticClient(elasticsearch.Config{
+		Addresses: []string{ts.URL},
+	})
+	esClient.bulkRetryDelay = 0
+
+	t.Run("only the failed documents should be sent again", func(t *testing.T) {
+		responses = []string{
+			`{"errors":true,"items":[
+				{"index":{"_index":"blocks","_id":"b1","status":201}},
+				{"delete":{"_index":"blocks","_id":"b2","status":429,"error":{"type":"es_rejected_execution_exception"}}},
+				{"update":{"_index":"accounts","_id":"a1","status":200}},
+				{"update":{"_index":"accounts","_id":"a1","status":200}}]}`,
+			`{"errors":false,"items":[{"delete":{"_index":"blocks","_id":"b2","status":200}}]}`,
+		}
+		requests = make([]string, 0)
+
+		err := esClient.DoBulkRequest(bytes.NewBufferString(testBulkBody), "")
+		require.Nil(t, err)
+		require.Len(t, requests, 2)
+		require.Equal(t, testBulkBody, requests[0])
+		require.Equal(t, "{ \"delete\" : { \"_index\":\"blocks\", \"_id\" : \"b2\" } }\n", requests[1])
+	})
+	t.Run("documents throttled on every attempt should signal back off", func(t *testing.T) {
+		throttled := `{"errors":true,"items":[{"delete":{"_index":"blocks","_id":"b2","status":429}}]}`
+		responses = []string{throttled, throttled, throttled, throttled}
+		requests = make([]string, 0)
+
+		err := esClient.DoBulkRequest(bytes.NewBufferString(`{ "delete" : { "_index":"blocks", "_id" : "b2" } }`+"\n"), "")
+		require.True(t, errors.Is(err, indexer.ErrBackOff))
+		require.Len(t, requests, maxBulkRetries+1)
+	})
+	t.Run("rejected documents should be returned after the others were written", func(t *testing.T) {
+		responses = []string{
+			`{"errors":true,"items":[
+				{"index":{"_index":"blocks","_id":"b1","status":400,"error":{"type":"mapper_parsing_exception","reason":"failed to parse"}}},
+				{"delete":{"_index":"blocks","_id":"b2","status":429}},
+				{"update":{"_index":"accounts","_id":"a1","status":200}},
+				{"update":{"_index":"accounts","_id":"a1","status":200}}]}`,
+			`{"errors":false,"items":[{"delete":{"_index":"blocks","_id":"b2","status":200}}]}`,
+		}
+		requests = make([]string, 0)
+
+		err := esClient.DoBulkRequest(bytes.NewBufferString(testBulkBody), "")
+		require.Len(t, requests, 2)
+
+		rejected := &data.RejectedDocumentsError{}
+		require.True(t, errors.As(err, &rejected))
+		require.False(t, errors.Is(err, indexer.ErrPermanentFailure))
+		require.Equal(t, []*data.RejectedDocument{{
+			Index:     "blocks",
+			ID:        "b1",
+			Status:    http.StatusBadRequest,
+			ErrorType: "mapper_parsing_exception",
+			Reason:    "failed to parse",
+			Action:    "{ \"index\" : { \"_index\":\"blocks\", \"_id\" : \"b1\" } }\n{\"nonce\":1}\n",
+		}}, rejected.Documents)
+	})
+	t.Run("too large request should be split in half", func(t *testing.T) {
+		responses = []string{
+			tooLargeResponse,
+			`{"errors":false,"items":[
+				{"index":{"_index":"blocks","_id":"b1","status":201}},
+				{"delete":{"_index":"blocks","_id":"b2","status":200}}]}`,
+			tooLargeResponse,
+			tooLargeResponse,
+			`{"errors":false,"items":[{"update":{"_index":"accounts","_id":"a1","status":200}}]}`,
+		}
+		requests = make([]string, 0)
+
+		err := esClient.DoBulkRequest(bytes.NewBufferString(testBulkBody), "")
+		require.Len(t, requests, 5)
+		require.Equal(t, testBulkBody, requests[0])
+		require.Equal(t, `{ "index" : { "_index":"blocks", "_id" : "b1" } }
+{"nonce":1}
+{ "delete" : { "_index":"blocks", "_id" : "b2" } }
+`, requests[1])
+		require.Equal(t, `{ "update" : { "_index":"accounts", "_id" : "a1" } }
+{"doc":{"balance":"1"}}
+{ "update" : { "_index":"accounts", "_id" : "a1" } }
+{"doc":{"balance":"2"}}
+`, requests[2])
+		require.Equal(t, "{ \"update\" : { \"_index\":\"accounts\", \"_id\" : \"a1\" } }\n{\"doc\":{\"balance\":\"2\"}}\n", requests[4])
+
+		// the single action that is still too large is rejected, the next one is written
+		rejected := &data.RejectedDocumentsError{}
+		require.True(t, errors.As(err, &rejected))
+		require.Len(t, rejected.Documents, 1)
+		require.Equal(t, "accounts", rejected.Documents[0].Index)
+		require.Equal(t, "a1", rejected.Documents[0].ID)
+		require.Equal(t, http.StatusRequestEntityTooLarge, rejected.Documents[0].Status)
+		require.Equal(t, requests[3], rejected.Documents[0].Action)
+	})
+}
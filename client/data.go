@@ -11,11 +11,30 @@ var headerContentTypeJSON = []string{"application/json"}
 
 // BulkRequestResponse defines the structure of a bulk request response
 type BulkRequestResponse struct {
-	Errors bool `json:"errors"`
-	Items  []struct {
-		ItemIndex  *Item `json:"index"`
-		ItemUpdate *Item `json:"update"`
-	} `json:"items"`
+	Errors bool               `json:"errors"`
+	Items  []BulkResponseItem `json:"items"`
+}
+
+// BulkResponseItem defines the structure of the result of one action from a bulk request
+type BulkResponseItem struct {
+	ItemIndex  *Item `json:"index"`
+	ItemCreate *Item `json:"create"`
+	ItemUpdate *Item `json:"update"`
+	ItemDelete *Item `json:"delete"`
+}
+
+// Get returns the result of the action, whatever its operation was
+func (bri *BulkResponseItem) Get() *Item {
+	switch {
+	case bri.ItemIndex != nil:
+		return bri.ItemIndex
+	case bri.ItemCreate != nil:
+		return bri.ItemCreate
+	case bri.ItemUpdate != nil:
+		return bri.ItemUpdate
+	default:
+		return bri.ItemDelete
+	}
 }
 
 // Item defines the structure of a item from a bulk response
@@ -3,10 +3,12 @@ package client
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"strings"
+	"time"
 
 	indexer "github.com/ME-MotherEarth/me-elastic-indexer"
 	"github.com/ME-MotherEarth/me-elastic-indexer/data"
@@ -32,6 +34,7 @@ type (
 type elasticClient struct {
 	elasticBaseUrl string
 	client         *elasticsearch.Client
+	bulkRetryDelay time.Duration
 
 	// countScroll is used to be incremented after each scroll so the scroll duration is different each time,
 	// bypassing any possible caching based on the same request
@@ -52,6 +55,7 @@ func NewElasticClient(cfg elasticsearch.Config) (*elasticClient, error) {
 	ec := &elasticClient{
 		client:         es,
 		elasticBaseUrl: cfg.Addresses[0],
+		bulkRetryDelay: bulkRetryDelay,
 	}
 
 	return ec, nil
@@ -66,6 +70,70 @@ func (ec *elasticClient) CheckAndCreateTemplate(templateName string, template *b
 	return ec.createIndexTemplate(templateName, template)
 }
 
+// GetTemplate returns the index template with the provided name, as it is stored in the cluster, or nil if the
+// template does not exist
+func (ec *elasticClient) GetTemplate(templateName string) ([]byte, error) {
+	res, err := ec.client.Indices.GetTemplate(ec.client.Indices.GetTemplate.WithName(templateName))
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode == http.StatusNotFound {
+		closeBody(res)
+		return nil, nil
+	}
+
+	response := make(map[string]json.RawMessage)
+	err = parseResponse(res, &response, elasticDefaultErrorResponseHandler)
+	if err != nil {
+		return nil, err
+	}
+
+	return response[templateName], nil
+}
+
+// PutTemplate creates the index template or replaces the existing one. The indices that already exist are not changed
+func (ec *elasticClient) PutTemplate(templateName string, template *bytes.Buffer) error {
+	return ec.createIndexTemplate(templateName, template)
+}
+
+// GetMappings returns the mappings of every index the provided alias or index points to. An index that does not
+// exist has no mappings
+func (ec *elasticClient) GetMappings(index string) (map[string][]byte, error) {
+	res, err := ec.client.Indices.GetMapping(ec.client.Indices.GetMapping.WithIndex(index))
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode == http.StatusNotFound {
+		closeBody(res)
+		return make(map[string][]byte), nil
+	}
+
+	response := make(map[string]struct {
+		Mappings json.RawMessage `json:"mappings"`
+	})
+	err = parseResponse(res, &response, elasticDefaultErrorResponseHandler)
+	if err != nil {
+		return nil, err
+	}
+
+	mappings := make(map[string][]byte, len(response))
+	for indexName, indexMappings := range response {
+		mappings[indexName] = indexMappings.Mappings
+	}
+
+	return mappings, nil
+}
+
+// PutMapping adds the provided field mappings to the index. Elasticsearch refuses the changes of the existing fields
+func (ec *elasticClient) PutMapping(index string, mapping *bytes.Buffer) error {
+	res, err := ec.client.Indices.PutMapping(mapping, ec.client.Indices.PutMapping.WithIndex(index))
+	if err != nil {
+		return err
+	}
+
+	return parseResponse(res, nil, elasticDefaultErrorResponseHandler)
+}
+
 // CheckAndCreatePolicy creates a new index policy if it does not already exist
 func (ec *elasticClient) CheckAndCreatePolicy(policyName string, policy *bytes.Buffer) error {
 	if ec.PolicyExists(policyName) {
@@ -93,6 +161,45 @@ func (ec *elasticClient) CheckAndCreateAlias(alias string, indexName string) err
 	return ec.createAlias(alias, indexName)
 }
 
+// CheckAndCreateLifecyclePolicy creates an index lifecycle management policy if it does not already exist
+func (ec *elasticClient) CheckAndCreateLifecyclePolicy(policyName string, policy *bytes.Buffer) error {
+	res, err := ec.client.ILM.GetLifecycle(ec.client.ILM.GetLifecycle.WithPolicy(policyName))
+	if exists(res, err) {
+		return nil
+	}
+
+	res, err = ec.client.ILM.PutLifecycle(policyName, ec.client.ILM.PutLifecycle.WithBody(policy))
+	if err != nil {
+		return err
+	}
+
+	return parseResponse(res, nil, elasticDefaultErrorResponseHandler)
+}
+
+// CheckAndCreateWriteAlias creates an alias that writes in the provided index, if the alias does not already exist.
+// An alias that points to a single index without marking it as write index, as the aliases created before the
+// indices were rolled over, is updated so the index becomes its write index
+func (ec *elasticClient) CheckAndCreateWriteAlias(alias string, indexName string) error {
+	aliasIndices, err := ec.getAliasIndices(alias)
+	if err != nil {
+		return err
+	}
+	if len(aliasIndices) == 0 {
+		return ec.createWriteAlias(alias, indexName)
+	}
+
+	for index, isWriteIndex := range aliasIndices {
+		if isWriteIndex {
+			return nil
+		}
+		if len(aliasIndices) == 1 {
+			return ec.createWriteAlias(alias, index)
+		}
+	}
+
+	return fmt.Errorf("%w: %s", ErrAliasWithoutWriteIndex, alias)
+}
+
 // DoRequest will do a request to elastic server
 func (ec *elasticClient) DoRequest(req *esapi.IndexRequest) error {
 	res, err := req.Do(context.Background(), ec.client)
@@ -103,26 +210,25 @@ func (ec *elasticClient) DoRequest(req *esapi.IndexRequest) error {
 	return parseResponse(res, nil, elasticDefaultErrorResponseHandler)
 }
 
-// DoBulkRequest will do a bulk of request to elastic server
+// DoBulkRequest will do a bulk of request to elastic server. The documents that fail with an error that can go
+// away are sent again, while the ones rejected by elasticsearch are returned in a data.RejectedDocumentsError, after
+// all the other documents were written
 func (ec *elasticClient) DoBulkRequest(buff *bytes.Buffer, index string) error {
-	reader := bytes.NewReader(buff.Bytes())
-
-	options := make([]func(*esapi.BulkRequest), 0)
-	if index != "" {
-		options = append(options, ec.client.Bulk.WithIndex(index))
+	actions, err := splitBulkActions(buff.Bytes(), index)
+	if err != nil {
+		return err
+	}
+	if len(actions) == 0 {
+		return nil
 	}
 
-	res, err := ec.client.Bulk(
-		reader,
-		options...,
-	)
+	rejected := &data.RejectedDocumentsError{}
+	err = ec.doBulkWithPartialRetry(actions, index, rejected)
 	if err != nil {
-		log.Warn("elasticClient.DoBulkRequest",
-			"indexer do bulk request no response", err.Error())
 		return err
 	}
 
-	return elasticBulkRequestResponseHandler(res)
+	return rejected.AsError()
 }
 
 // DoMultiGet wil do a multi get request to elaticsearch server
@@ -155,7 +261,7 @@ func (ec *elasticClient) DoMultiGet(ids []string, index string, withSource bool,
 
 // DoQueryRemove will do a query remove to elasticsearch server
 func (ec *elasticClient) DoQueryRemove(index string, body *bytes.Buffer) error {
-	if err := ec.doRefresh(index); err != nil {
+	if err := ec.DoRefresh(index); err != nil {
 		log.Warn("elasticClient.doRefresh", "cannot do refresh", err.Error())
 	}
 
@@ -180,7 +286,8 @@ func (ec *elasticClient) DoQueryRemove(index string, body *bytes.Buffer) error {
 	return nil
 }
 
-func (ec *elasticClient) doRefresh(index string) error {
+// DoRefresh will make the last changes of the index visible to the search requests
+func (ec *elasticClient) DoRefresh(index string) error {
 	res, err := ec.client.Indices.Refresh(
 		ec.client.Indices.Refresh.WithIndex(index),
 		ec.client.Indices.Refresh.WithIgnoreUnavailable(true),
@@ -328,6 +435,51 @@ func (ec *elasticClient) createAlias(alias string, index string) error {
 	return parseResponse(res, nil, elasticDefaultErrorResponseHandler)
 }
 
+func (ec *elasticClient) createWriteAlias(alias string, index string) error {
+	body, err := encode(objectsMap{
+		"is_write_index": true,
+	})
+	if err != nil {
+		return err
+	}
+
+	res, err := ec.client.Indices.PutAlias([]string{index}, alias, ec.client.Indices.PutAlias.WithBody(&body))
+	if err != nil {
+		return err
+	}
+
+	return parseResponse(res, nil, elasticDefaultErrorResponseHandler)
+}
+
+// getAliasIndices returns the indices the alias points to, each with true if it is the write index of the alias
+func (ec *elasticClient) getAliasIndices(alias string) (map[string]bool, error) {
+	res, err := ec.client.Indices.GetAlias(ec.client.Indices.GetAlias.WithName(alias))
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode == http.StatusNotFound {
+		closeBody(res)
+		return make(map[string]bool), nil
+	}
+
+	response := make(map[string]struct {
+		Aliases map[string]struct {
+			IsWriteIndex bool `json:"is_write_index"`
+		} `json:"aliases"`
+	})
+	err = parseResponse(res, &response, elasticDefaultErrorResponseHandler)
+	if err != nil {
+		return nil, err
+	}
+
+	aliasIndices := make(map[string]bool, len(response))
+	for index, indexAliases := range response {
+		aliasIndices[index] = indexAliases.Aliases[alias].IsWriteIndex
+	}
+
+	return aliasIndices, nil
+}
+
 // IsInterfaceNil returns true if there is no value under the interface
 func (ec *elasticClient) IsInterfaceNil() bool {
 	return ec == nil
@@ -91,63 +91,6 @@ func elasticDefaultErrorResponseHandler(res *esapi.Response) error {
 		res.StatusCode, responseBody, string(bodyBytes))
 }
 
-func elasticBulkRequestResponseHandler(res *esapi.Response) error {
-	if res.IsError() {
-		return fmt.Errorf("%s", res.String())
-	}
-
-	bodyBytes, err := ioutil.ReadAll(res.Body)
-	if err != nil {
-		return fmt.Errorf("%w cannot read elastic response body bytes", err)
-	}
-
-	return extractErrorFromBulkBodyResponseBytes(bodyBytes)
-}
-
-func extractErrorFromBulkBodyResponseBytes(bodyBytes []byte) error {
-	response := BulkRequestResponse{}
-	err := json.Unmarshal(bodyBytes, &response)
-	if err != nil {
-		return err
-	}
-
-	count := 0
-	errorsString := ""
-	for _, item := range response.Items {
-		var selectedItem Item
-
-		switch {
-		case item.ItemIndex != nil:
-			selectedItem = *item.ItemIndex
-		case item.ItemUpdate != nil:
-			selectedItem = *item.ItemUpdate
-		}
-
-		log.Trace("worked on", "index", selectedItem.Index,
-			"_id", selectedItem.ID,
-			"result", selectedItem.Result,
-			"status", selectedItem.Status,
-		)
-
-		if selectedItem.Status < http.StatusBadRequest {
-			continue
-		}
-
-		count++
-		errorsString += fmt.Sprintf(`{ "index": "%s", "id": "%s", "statusCode": %d, "errorType": "%s", "reason": "%s", "causedBy": { "type": "%s", "reason": "%s" }}\n`,
-			selectedItem.Index, selectedItem.ID, selectedItem.Status, selectedItem.Error.Type, selectedItem.Error.Reason, selectedItem.Error.Cause.Type, selectedItem.Error.Cause.Reason)
-
-		if count == numOfErrorsToExtractBulkResponse {
-			break
-		}
-	}
-	if errorsString == "" {
-		return nil
-	}
-
-	return fmt.Errorf("%s", errorsString)
-}
-
 func errIsAlreadyExists(response map[string]interface{}) bool {
 	alreadyExistsMessage := "resource_already_exists_exception"
 	errKey := "error"
@@ -140,17 +140,3 @@ func createMockEsapiResponseWithText(str string) *esapi.Response {
 		},
 	}
 }
-
-func TestExtractErrorFromBulkBodyResponseBytesUpdate(t *testing.T) {
-	responseBytes := []byte(`{"took":39,"errors":true,"items":[{"update":{"_index":"transactions-000001","_type":"_doc","_id":"76c11e808085df75b21ae3196b9a7b533a15a346ab79346d81795f5131ae66fa","status":409,"error":{"type":"version_conflict_engine_exception","reason":"[76c11e808085df75b21ae3196b9a7b533a15a346ab79346d81795f5131ae66fa]: version conflict, required seqNo [1904], primary term [1]. current document has seqNo [1975] and primary term [1]","index_uuid":"_mEW9HB_QiSbIvkbythJ7Q","shard":"2","index":"transactions-000001"}}}]}`)
-
-	err := extractErrorFromBulkBodyResponseBytes(responseBytes)
-	require.NotNil(t, err)
-}
-
-func TestExtractErrorFromBulkBodyResponseBytesIndex(t *testing.T) {
-	responseBytes := []byte(`{"took":39,"errors":true,"items":[{"index":{"_index":"transactions-000001","_type":"_doc","_id":"76c11e808085df75b21ae3196b9a7b533a15a346ab79346d81795f5131ae66fa","status":409,"error":{"type":"version_conflict_engine_exception","reason":"[76c11e808085df75b21ae3196b9a7b533a15a346ab79346d81795f5131ae66fa]: version conflict, required seqNo [1904], primary term [1]. current document has seqNo [1975] and primary term [1]","index_uuid":"_mEW9HB_QiSbIvkbythJ7Q","shard":"2","index":"transactions-000001"}}}]}`)
-
-	err := extractErrorFromBulkBodyResponseBytes(responseBytes)
-	require.NotNil(t, err)
-}
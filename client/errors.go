@@ -0,0 +1,18 @@
+package client
+
+import "errors"
+
+// ErrInvalidBulkAction signals that a bulk request body contains an action that cannot be parsed
+var ErrInvalidBulkAction = errors.New("invalid bulk action")
+
+// ErrUnexpectedBulkResponse signals that the items of a bulk response do not match the sent actions
+var ErrUnexpectedBulkResponse = errors.New("unexpected bulk response")
+
+// ErrBulkDocumentsNotWritten signals that some documents of a bulk request were still not written after retrying
+var ErrBulkDocumentsNotWritten = errors.New("bulk documents not written")
+
+// ErrAliasWithoutWriteIndex signals that an alias points to several indices, but none of them is its write index
+var ErrAliasWithoutWriteIndex = errors.New("alias without write index")
+
+// ErrRequestEntityTooLarge signals that a bulk request body is bigger than what elasticsearch accepts
+var ErrRequestEntityTooLarge = errors.New("request entity too large")
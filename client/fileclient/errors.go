@@ -0,0 +1,12 @@
+package fileclient
+
+import "errors"
+
+// ErrEmptyDirectory signals that an empty output directory path has been provided
+var ErrEmptyDirectory = errors.New("empty output directory")
+
+// ErrInvalidMaxFileSize signals that an invalid maximum file size has been provided
+var ErrInvalidMaxFileSize = errors.New("invalid maximum file size")
+
+// ErrClientClosed signals that a request was made on a closed client
+var ErrClientClosed = errors.New("file client is closed")
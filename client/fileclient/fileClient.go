@@ -0,0 +1,480 @@
+package fileclient
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	indexer "github.com/ME-MotherEarth/me-elastic-indexer"
+	"github.com/ME-MotherEarth/me-elastic-indexer/client/memstore"
+	logger "github.com/ME-MotherEarth/me-logger"
+	"github.com/elastic/go-elasticsearch/v7/esapi"
+)
+
+const (
+	filePattern    = "requests-%08d.ndjson"
+	fileGlob       = "requests-*.ndjson"
+	filePermission = 0644
+	dirPermission  = 0755
+	scrollPageSize = 9000
+
+	requestBulk          = "bulk"
+	requestIndex         = "index"
+	requestDeleteByQuery = "delete_by_query"
+	requestCreateIndex   = "create_index"
+	requestCreateAlias   = "create_alias"
+	requestTemplate      = "template"
+	requestPolicy        = "policy"
+	requestLifecycle     = "lifecycle_policy"
+	requestMapping       = "mapping"
+)
+
+var log = logger.GetOrCreate("indexer/client/fileclient")
+
+// ArgsFileClient holds all dependencies required by the file client in order to create new instances
+type ArgsFileClient struct {
+	Directory   string
+	MaxFileSize int64
+	IndexPrefix string
+}
+
+// entry is one line of the output files
+type entry struct {
+	Timestamp string          `json:"timestamp"`
+	BlockHash string          `json:"blockHash,omitempty"`
+	Request   string          `json:"request"`
+	Operation string          `json:"operation,omitempty"`
+	Index     string          `json:"index,omitempty"`
+	ID        string          `json:"id,omitempty"`
+	Name      string          `json:"name,omitempty"`
+	Body      json.RawMessage `json:"body,omitempty"`
+}
+
+type fileClient struct {
+	directory      string
+	maxFileSize    int64
+	blocksIndex    string
+	store          storeHandler
+	getTimeHandler func() time.Time
+
+	mutex     sync.Mutex
+	nextFile  int
+	file      *os.File
+	writer    *bufio.Writer
+	written   int64
+	blockHash string
+	closed    bool
+}
+
+// NewFileClient will create a database client that does not call elasticsearch. Every write request is appended to
+// NDJSON files placed in the provided directory, one line per document, together with the time of the request and the
+// hash of the block being written. A new file is started when the current one exceeds MaxFileSize bytes. The read
+// requests are answered from an in-memory store built from what was written, so the indexer can run without a cluster.
+// The IndexPrefix of the indexer is needed to find the blocks index, e.g. "testnet-blocks", in the written requests
+func NewFileClient(args ArgsFileClient) (*fileClient, error) {
+	if args.Directory == "" {
+		return nil, ErrEmptyDirectory
+	}
+	if args.MaxFileSize <= 0 {
+		return nil, fmt.Errorf("%w: %d", ErrInvalidMaxFileSize, args.MaxFileSize)
+	}
+
+	err := os.MkdirAll(args.Directory, dirPermission)
+	if err != nil {
+		return nil, err
+	}
+
+	nextFile, err := getNextFileIndex(args.Directory)
+	if err != nil {
+		return nil, err
+	}
+
+	return &fileClient{
+		directory:      args.Directory,
+		maxFileSize:    args.MaxFileSize,
+		blocksIndex:    indexer.PrefixIndex(args.IndexPrefix, indexer.BlockIndex),
+		store:          memstore.NewStore(),
+		getTimeHandler: time.Now,
+		nextFile:       nextFile,
+	}, nil
+}
+
+// DoBulkRequest will write every action of the bulk request and will apply it on the in-memory store
+func (fc *fileClient) DoBulkRequest(buff *bytes.Buffer, index string) error {
+	actions, err := memstore.ParseBulk(buff.Bytes(), index)
+	if err != nil {
+		return err
+	}
+
+	fc.mutex.Lock()
+	defer fc.mutex.Unlock()
+
+	entries := make([]*entry, 0, len(actions))
+	for _, action := range actions {
+		if action.Index == fc.blocksIndex {
+			fc.blockHash = action.ID
+		}
+
+		entries = append(entries, &entry{
+			Request:   requestBulk,
+			Operation: action.Operation,
+			Index:     action.Index,
+			ID:        action.ID,
+			Body:      action.Source,
+		})
+	}
+
+	err = fc.writeEntries(entries...)
+	if err != nil {
+		return err
+	}
+
+	items, err := fc.store.ApplyBulk(buff.Bytes(), index)
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		if item.ErrorType != "" {
+			log.Warn("fileClient.DoBulkRequest: document cannot be applied on the in-memory store",
+				"index", item.Index, "id", item.ID, "type", item.ErrorType, "reason", item.Reason)
+		}
+	}
+
+	return nil
+}
+
+// DoRequest will write the indexed document and will keep it in the in-memory store
+func (fc *fileClient) DoRequest(req *esapi.IndexRequest) error {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = ioutil.ReadAll(req.Body)
+		if err != nil {
+			return err
+		}
+	}
+
+	fc.mutex.Lock()
+	defer fc.mutex.Unlock()
+
+	err := fc.writeEntries(&entry{
+		Request: requestIndex,
+		Index:   req.Index,
+		ID:      req.DocumentID,
+		Body:    body,
+	})
+	if err != nil {
+		return err
+	}
+
+	fc.store.PutDocument(req.Index, req.DocumentID, body)
+
+	return nil
+}
+
+// DoQueryRemove will write the query and will remove the matching documents from the in-memory store
+func (fc *fileClient) DoQueryRemove(index string, buff *bytes.Buffer) error {
+	fc.mutex.Lock()
+	defer fc.mutex.Unlock()
+
+	err := fc.writeEntries(&entry{
+		Request: requestDeleteByQuery,
+		Index:   index,
+		Body:    buff.Bytes(),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = fc.store.DeleteByQuery(index, buff.Bytes())
+	return err
+}
+
+// DoMultiGet will return the requested documents from the in-memory store
+func (fc *fileClient) DoMultiGet(ids []string, index string, withSource bool, res interface{}) error {
+	response, err := json.Marshal(fc.store.MultiGet(index, ids, withSource))
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(response, res)
+}
+
+// DoScrollRequest will call the handler with pages of the matching documents from the in-memory store
+func (fc *fileClient) DoScrollRequest(index string, body []byte, withSource bool, handlerFunc func(responseBytes []byte) error) error {
+	hits, err := fc.store.Search(index, body)
+	if err != nil {
+		return err
+	}
+
+	for start := 0; start == 0 || start < len(hits); start += scrollPageSize {
+		end := start + scrollPageSize
+		if end > len(hits) {
+			end = len(hits)
+		}
+
+		response, errResponse := memstore.SearchResponse(hits[start:end], len(hits), withSource)
+		if errResponse != nil {
+			return errResponse
+		}
+
+		err = handlerFunc(response)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DoCountRequest will count the matching documents from the in-memory store
+func (fc *fileClient) DoCountRequest(index string, body []byte) (uint64, error) {
+	return fc.store.Count(index, body)
+}
+
+// CheckAndCreateIndex will write the index creation, if the index does not exist
+func (fc *fileClient) CheckAndCreateIndex(index string) error {
+	if fc.store.IndexExists(index) {
+		return nil
+	}
+
+	fc.store.CreateIndex(index)
+
+	return fc.writeLockedEntry(&entry{
+		Request: requestCreateIndex,
+		Index:   index,
+	})
+}
+
+// CheckAndCreateAlias will write the alias creation, if the alias does not exist
+func (fc *fileClient) CheckAndCreateAlias(alias string, index string) error {
+	if fc.store.AliasExists(alias) {
+		return nil
+	}
+
+	fc.store.PutAlias(alias, index)
+
+	return fc.writeLockedEntry(&entry{
+		Request: requestCreateAlias,
+		Index:   index,
+		Name:    alias,
+	})
+}
+
+// CheckAndCreateWriteAlias will write the creation of the alias, together with its write index, if the alias does
+// not exist
+func (fc *fileClient) CheckAndCreateWriteAlias(alias string, index string) error {
+	if fc.store.AliasExists(alias) {
+		return nil
+	}
+
+	fc.store.PutAlias(alias, index)
+
+	return fc.writeLockedEntry(&entry{
+		Request: requestCreateAlias,
+		Index:   index,
+		Name:    alias,
+		Body:    []byte(`{"is_write_index":true}`),
+	})
+}
+
+// CheckAndCreateTemplate will write the template, so the changes of the templates can be reviewed
+func (fc *fileClient) CheckAndCreateTemplate(templateName string, template *bytes.Buffer) error {
+	return fc.writeLockedEntry(&entry{
+		Request: requestTemplate,
+		Name:    templateName,
+		Body:    template.Bytes(),
+	})
+}
+
+// GetTemplate returns nil, as the templates are only written, so every template is written again
+func (fc *fileClient) GetTemplate(_ string) ([]byte, error) {
+	return nil, nil
+}
+
+// PutTemplate will write the template
+func (fc *fileClient) PutTemplate(templateName string, template *bytes.Buffer) error {
+	return fc.CheckAndCreateTemplate(templateName, template)
+}
+
+// GetMappings returns no mappings, as the indices written by the file client have the mappings of their templates
+func (fc *fileClient) GetMappings(_ string) (map[string][]byte, error) {
+	return make(map[string][]byte), nil
+}
+
+// PutMapping will write the new field mappings of the index
+func (fc *fileClient) PutMapping(index string, mapping *bytes.Buffer) error {
+	return fc.writeLockedEntry(&entry{
+		Request: requestMapping,
+		Index:   index,
+		Body:    mapping.Bytes(),
+	})
+}
+
+// CheckAndCreatePolicy will write the policy
+func (fc *fileClient) CheckAndCreatePolicy(policyName string, policy *bytes.Buffer) error {
+	return fc.writeLockedEntry(&entry{
+		Request: requestPolicy,
+		Name:    policyName,
+		Body:    policy.Bytes(),
+	})
+}
+
+// CheckAndCreateLifecyclePolicy will write the index lifecycle management policy
+func (fc *fileClient) CheckAndCreateLifecyclePolicy(policyName string, policy *bytes.Buffer) error {
+	return fc.writeLockedEntry(&entry{
+		Request: requestLifecycle,
+		Name:    policyName,
+		Body:    policy.Bytes(),
+	})
+}
+
+// DoRefresh does nothing, as the in-memory store answers with the last changes
+func (fc *fileClient) DoRefresh(_ string) error {
+	return nil
+}
+
+func (fc *fileClient) writeLockedEntry(e *entry) error {
+	fc.mutex.Lock()
+	defer fc.mutex.Unlock()
+
+	return fc.writeEntries(e)
+}
+
+func (fc *fileClient) writeEntries(entries ...*entry) error {
+	if fc.closed {
+		return ErrClientClosed
+	}
+
+	if fc.writer == nil {
+		err := fc.openFile()
+		if err != nil {
+			return err
+		}
+	}
+
+	timestamp := fc.getTimeHandler().UTC().Format(time.RFC3339Nano)
+	for _, e := range entries {
+		e.Timestamp = timestamp
+		e.BlockHash = fc.blockHash
+		e.Body = compactJSON(e.Body)
+
+		line, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+
+		n, err := fc.writer.Write(append(line, '\n'))
+		fc.written += int64(n)
+		if err != nil {
+			return err
+		}
+	}
+
+	err := fc.writer.Flush()
+	if err != nil {
+		return err
+	}
+
+	if fc.written < fc.maxFileSize {
+		return nil
+	}
+
+	return fc.closeFile()
+}
+
+func (fc *fileClient) openFile() error {
+	path := filepath.Join(fc.directory, fmt.Sprintf(filePattern, fc.nextFile))
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, filePermission)
+	if err != nil {
+		return err
+	}
+
+	log.Debug("file client opened a new file", "path", path)
+
+	fc.nextFile++
+	fc.file = file
+	fc.writer = bufio.NewWriter(file)
+	fc.written = 0
+
+	return nil
+}
+
+func (fc *fileClient) closeFile() error {
+	if fc.writer == nil {
+		return nil
+	}
+
+	errFlush := fc.writer.Flush()
+	errClose := fc.file.Close()
+	fc.writer = nil
+	fc.file = nil
+	if errFlush != nil {
+		return errFlush
+	}
+
+	return errClose
+}
+
+// Close will close the current output file
+func (fc *fileClient) Close() error {
+	fc.mutex.Lock()
+	defer fc.mutex.Unlock()
+
+	if fc.closed {
+		return nil
+	}
+
+	fc.closed = true
+	return fc.closeFile()
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (fc *fileClient) IsInterfaceNil() bool {
+	return fc == nil
+}
+
+// compactJSON will keep the body on one line. A body that is not a valid json is kept as a json string
+func compactJSON(body []byte) json.RawMessage {
+	if len(body) == 0 {
+		return nil
+	}
+
+	buff := &bytes.Buffer{}
+	err := json.Compact(buff, body)
+	if err == nil {
+		return buff.Bytes()
+	}
+
+	quoted, _ := json.Marshal(string(body))
+	return quoted
+}
+
+func getNextFileIndex(directory string) (int, error) {
+	paths, err := filepath.Glob(filepath.Join(directory, fileGlob))
+	if err != nil {
+		return 0, err
+	}
+
+	indices := make([]int, 0, len(paths))
+	for _, path := range paths {
+		index := 0
+		_, errScan := fmt.Sscanf(filepath.Base(path), filePattern, &index)
+		if errScan == nil {
+			indices = append(indices, index)
+		}
+	}
+	if len(indices) == 0 {
+		return 0, nil
+	}
+	sort.Ints(indices)
+
+	return indices[len(indices)-1] + 1, nil
+}
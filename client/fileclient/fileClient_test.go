@@ -0,0 +1,218 @@
+package fileclient
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	dataBlock "github.com/ME-MotherEarth/me-core/data/block"
+	"github.com/ME-MotherEarth/me-core/data/indexer"
+	elasticIndexer "github.com/ME-MotherEarth/me-elastic-indexer"
+	"github.com/ME-MotherEarth/me-elastic-indexer/data"
+	"github.com/ME-MotherEarth/me-elastic-indexer/mock"
+	"github.com/ME-MotherEarth/me-elastic-indexer/process/factory"
+	"github.com/ME-MotherEarth/me-elastic-indexer/process/sink/elastic"
+	"github.com/stretchr/testify/require"
+)
+
+func createFileClient(t *testing.T, directory string, maxFileSize int64) *fileClient {
+	fc, err := NewFileClient(ArgsFileClient{
+		Directory:   directory,
+		MaxFileSize: maxFileSize,
+	})
+	require.Nil(t, err)
+	fc.getTimeHandler = func() time.Time {
+		return time.Unix(1600000000, 0)
+	}
+	t.Cleanup(func() {
+		_ = fc.Close()
+	})
+
+	return fc
+}
+
+func readEntries(t *testing.T, path string) []*entry {
+	content, err := ioutil.ReadFile(path)
+	require.Nil(t, err)
+
+	entries := make([]*entry, 0)
+	for _, line := range strings.Split(strings.TrimSpace(string(content)), "\n") {
+		e := &entry{}
+		require.Nil(t, json.Unmarshal([]byte(line), e))
+		entries = append(entries, e)
+	}
+
+	return entries
+}
+
+func TestNewFileClient(t *testing.T) {
+	t.Parallel()
+
+	fc, err := NewFileClient(ArgsFileClient{MaxFileSize: 1})
+	require.Nil(t, fc)
+	require.Equal(t, ErrEmptyDirectory, err)
+
+	fc, err = NewFileClient(ArgsFileClient{Directory: t.TempDir()})
+	require.Nil(t, fc)
+	require.True(t, errors.Is(err, ErrInvalidMaxFileSize))
+
+	fc, err = NewFileClient(ArgsFileClient{Directory: t.TempDir(), MaxFileSize: 1})
+	require.Nil(t, err)
+	require.False(t, fc.IsInterfaceNil())
+}
+
+func TestFileClient_WritesAndReadsThroughTheElasticSink(t *testing.T) {
+	t.Parallel()
+
+	directory := t.TempDir()
+	fc := createFileClient(t, directory, 1<<20)
+	es, err := elastic.NewElasticSink(elastic.ArgsElasticSink{
+		DBClient:                  fc,
+		MetricsHandler:            &mock.MetricsHandlerStub{},
+		BulkRequestMaxSize:        1 << 20,
+		NumConcurrentBulkRequests: 1,
+		IndexTemplates:            map[string]*bytes.Buffer{"tags": bytes.NewBufferString(`{"index_patterns": ["tags-*"]}`)},
+	})
+	require.Nil(t, err)
+
+	err = es.WriteDocuments([]*data.Document{
+		{Index: "blocks", ID: "h1", Action: data.ActionIndex, Body: map[string]interface{}{"nonce": 1}},
+		{Index: "tags", ID: "art", Action: data.ActionUpsert, Body: map[string]interface{}{"tag": "art", "count": 1}, Increments: map[string]int64{"count": 1}},
+		{Index: "tags", ID: "art", Action: data.ActionUpsert, Body: map[string]interface{}{"tag": "art", "count": 1}, Increments: map[string]int64{"count": 1}},
+		{Index: "tags", ID: "music", Action: data.ActionCreate, Body: map[string]interface{}{"tag": "music", "count": 1}},
+	})
+	require.Nil(t, err)
+
+	documents, err := es.GetDocuments("tags", []string{"art", "music", "missing"})
+	require.Nil(t, err)
+	require.Equal(t, map[string][]byte{
+		"art":   []byte(`{"count":2,"tag":"art"}`),
+		"music": []byte(`{"count":1,"tag":"music"}`),
+	}, documents)
+
+	matchingIDs := make([]string, 0)
+	err = es.IterateMatchingIDs("tags", map[string]interface{}{"tag": "music"}, func(ids []string) error {
+		matchingIDs = append(matchingIDs, ids...)
+		return nil
+	})
+	require.Nil(t, err)
+	require.Equal(t, []string{"music"}, matchingIDs)
+
+	require.Nil(t, es.DeleteMatching("tags", map[string]interface{}{"tag": "art"}))
+	documents, _ = es.GetDocuments("tags", []string{"art"})
+	require.Empty(t, documents)
+
+	entries := readEntries(t, filepath.Join(directory, "requests-00000000.ndjson"))
+	requests := make([]string, 0, len(entries))
+	for _, e := range entries {
+		require.Equal(t, "2020-09-13T12:26:40Z", e.Timestamp)
+		requests = append(requests, e.Request)
+	}
+	require.Contains(t, requests, requestTemplate)
+	require.Contains(t, requests, requestCreateAlias)
+
+	last := entries[len(entries)-1]
+	require.Equal(t, requestDeleteByQuery, last.Request)
+	require.Equal(t, "h1", last.BlockHash)
+
+	bulkEntries := entries[len(entries)-5 : len(entries)-1]
+	require.Equal(t, &entry{
+		Timestamp: "2020-09-13T12:26:40Z",
+		BlockHash: "h1",
+		Request:   requestBulk,
+		Operation: "index",
+		Index:     "blocks",
+		ID:        "h1",
+		Body:      []byte(`{"nonce":1}`),
+	}, bulkEntries[0])
+	require.Equal(t, "update", bulkEntries[1].Operation)
+	require.Equal(t, "update", bulkEntries[3].Operation)
+	require.Contains(t, string(bulkEntries[3].Body), `"action":"create"`)
+}
+
+func TestFileClient_ShouldFindTheBlocksIndexWithPrefix(t *testing.T) {
+	t.Parallel()
+
+	directory := t.TempDir()
+	fc, err := NewFileClient(ArgsFileClient{
+		Directory:   directory,
+		MaxFileSize: 1 << 20,
+		IndexPrefix: "testnet",
+	})
+	require.Nil(t, err)
+
+	err = fc.DoBulkRequest(bytes.NewBufferString(`{ "index" : { "_index":"testnet-blocks", "_id" : "h1" } }
+{"nonce":1}
+{ "index" : { "_index":"testnet-tags", "_id" : "art" } }
+{"count":1}
+`), "")
+	require.Nil(t, err)
+	require.Nil(t, fc.Close())
+
+	entries := readEntries(t, filepath.Join(directory, "requests-00000000.ndjson"))
+	require.Len(t, entries, 2)
+	require.Equal(t, "h1", entries[1].BlockHash)
+}
+
+func TestFileClient_ShouldRotateFiles(t *testing.T) {
+	t.Parallel()
+
+	directory := t.TempDir()
+	fc := createFileClient(t, directory, 1)
+	for _, id := range []string{"a", "b"} {
+		err := fc.DoBulkRequest(bytes.NewBufferString(`{ "index" : { "_index":"tags", "_id" : "`+id+`" } }`+"\n{}\n"), "")
+		require.Nil(t, err)
+	}
+	require.Nil(t, fc.Close())
+	require.Equal(t, ErrClientClosed, fc.DoBulkRequest(bytes.NewBufferString(`{ "delete" : { "_index":"tags", "_id" : "a" } }`), ""))
+
+	fc = createFileClient(t, directory, 1)
+	require.Nil(t, fc.CheckAndCreateIndex("tags-000001"))
+
+	paths, _ := filepath.Glob(filepath.Join(directory, fileGlob))
+	require.Len(t, paths, 3)
+	require.Equal(t, "b", readEntries(t, paths[1])[0].ID)
+	require.Equal(t, requestCreateIndex, readEntries(t, paths[2])[0].Request)
+}
+
+func TestFileClient_RunsTheElasticProcessor(t *testing.T) {
+	t.Parallel()
+
+	directory := t.TempDir()
+	fc := createFileClient(t, directory, 1<<20)
+	processor, err := factory.CreateElasticProcessor(factory.ArgElasticProcessorFactory{
+		Marshalizer:              &mock.MarshalizerMock{},
+		Hasher:                   &mock.HasherMock{},
+		AddressPubkeyConverter:   mock.NewPubkeyConverterMock(32),
+		ValidatorPubkeyConverter: &mock.PubkeyConverterMock{},
+		DBClient:                 fc,
+		AccountsDB:               &mock.AccountsStub{},
+		ShardCoordinator:         &mock.ShardCoordinatorMock{},
+		TransactionFeeCalculator: &mock.EconomicsHandlerStub{},
+		EnabledIndexes:           []string{elasticIndexer.BlockIndex, elasticIndexer.CheckpointsIndex},
+		Denomination:             1,
+	})
+	require.Nil(t, err)
+
+	headerHash := []byte("hash")
+	header := &dataBlock.Header{Nonce: 7, ShardID: 0, TimeStamp: 100}
+	err = processor.SaveHeader(headerHash, header, nil, &dataBlock.Body{}, nil, indexer.HeaderGasConsumption{}, 0)
+	require.Nil(t, err)
+	require.Nil(t, processor.SaveCheckpoint(headerHash, header))
+
+	checkpoints, err := processor.GetCheckpoints([]uint32{0})
+	require.Nil(t, err)
+	require.Len(t, checkpoints, 1)
+	require.Equal(t, uint64(7), checkpoints[0].Nonce)
+
+	entries := readEntries(t, filepath.Join(directory, "requests-00000000.ndjson"))
+	last := entries[len(entries)-1]
+	require.Equal(t, elasticIndexer.CheckpointsIndex, last.Index)
+	require.Equal(t, hex.EncodeToString(headerHash), last.BlockHash)
+}
@@ -0,0 +1,18 @@
+package fileclient
+
+import "github.com/ME-MotherEarth/me-elastic-indexer/client/memstore"
+
+// storeHandler defines what the store that answers the read requests should be able to do
+type storeHandler interface {
+	ApplyBulk(body []byte, defaultIndex string) ([]*memstore.BulkItem, error)
+	PutDocument(index string, id string, source []byte)
+	MultiGet(index string, ids []string, withSource bool) *memstore.MultiGetResponse
+	Search(index string, body []byte) ([]*memstore.Hit, error)
+	Count(index string, body []byte) (uint64, error)
+	DeleteByQuery(index string, body []byte) (uint64, error)
+	CreateIndex(index string)
+	IndexExists(index string) bool
+	PutAlias(alias string, index string)
+	AliasExists(alias string) bool
+	IsInterfaceNil() bool
+}
@@ -0,0 +1,19 @@
+package memserver
+
+import "github.com/ME-MotherEarth/me-elastic-indexer/client/memstore"
+
+// storeHandler defines what the store that holds the documents of the server should be able to do
+type storeHandler interface {
+	ApplyBulk(body []byte, defaultIndex string) ([]*memstore.BulkItem, error)
+	PutDocument(index string, id string, source []byte)
+	GetDocument(index string, id string) ([]byte, bool)
+	MultiGet(index string, ids []string, withSource bool) *memstore.MultiGetResponse
+	Search(index string, body []byte) ([]*memstore.Hit, error)
+	Count(index string, body []byte) (uint64, error)
+	DeleteByQuery(index string, body []byte) (uint64, error)
+	CreateIndex(index string)
+	IndexExists(index string) bool
+	PutAlias(alias string, index string)
+	GetAliasIndex(alias string) (string, bool)
+	IsInterfaceNil() bool
+}
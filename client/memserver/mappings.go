@@ -0,0 +1,195 @@
+package memserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+)
+
+const (
+	errorIndexNotFound = "index_not_found_exception"
+	propertiesField    = "properties"
+	typeField          = "type"
+)
+
+// indexMappings holds the field mappings of an index, together with its top level mapping parameters, e.g. "dynamic"
+type indexMappings struct {
+	Properties objectsMap
+	Parameters objectsMap
+}
+
+// UnmarshalJSON will split the mappings in the field mappings and the mapping parameters
+func (im *indexMappings) UnmarshalJSON(buff []byte) error {
+	decoded := objectsMap{}
+	err := json.Unmarshal(buff, &decoded)
+	if err != nil {
+		return err
+	}
+
+	properties, ok := decoded[propertiesField].(map[string]interface{})
+	if decoded[propertiesField] != nil && !ok {
+		return fmt.Errorf("the [%s] of the mappings is not an object", propertiesField)
+	}
+	delete(decoded, propertiesField)
+
+	im.Properties = properties
+	im.Parameters = decoded
+
+	return nil
+}
+
+// MarshalJSON will put the mapping parameters next to the field mappings, as elasticsearch returns them
+func (im *indexMappings) MarshalJSON() ([]byte, error) {
+	mappings := make(objectsMap, len(im.Parameters)+1)
+	for name, value := range im.Parameters {
+		mappings[name] = value
+	}
+	mappings[propertiesField] = im.Properties
+
+	return json.Marshal(mappings)
+}
+
+type indexTemplate struct {
+	IndexPatterns []string      `json:"index_patterns"`
+	Mappings      indexMappings `json:"mappings"`
+}
+
+// mapping answers with the mappings of the index, or adds the new fields to them and sets the provided mapping
+// parameters. As elasticsearch does, the type of an existing field cannot be changed
+func (s *server) mapping(w http.ResponseWriter, r *http.Request, index string, body []byte) {
+	if !s.store.IndexExists(index) {
+		writeError(w, http.StatusNotFound, errorIndexNotFound, fmt.Sprintf("no such index [%s]", index))
+		return
+	}
+	if aliasIndex, isAlias := s.store.GetAliasIndex(index); isAlias {
+		index = aliasIndex
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, r, http.StatusOK, objectsMap{
+			index: objectsMap{
+				"mappings": s.getMappings(index),
+			},
+		})
+	case http.MethodPut, http.MethodPost:
+		newMappings := &indexMappings{}
+		err := json.Unmarshal(body, newMappings)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, errorParsing, err.Error())
+			return
+		}
+
+		err = s.getMappings(index).merge(newMappings)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, errorIllegalArgument, err.Error())
+			return
+		}
+
+		writeJSON(w, r, http.StatusOK, objectsMap{
+			"acknowledged": true,
+		})
+	default:
+		s.unsupported(w, r)
+	}
+}
+
+// applyTemplates sets, on the new index, the mappings of the templates that match its name
+func (s *server) applyTemplates(index string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, rawTemplate := range s.templates {
+		template := &indexTemplate{}
+		err := json.Unmarshal(rawTemplate, template)
+		if err != nil || !matchesAny(index, template.IndexPatterns) {
+			continue
+		}
+
+		err = s.getMappings(index).merge(&template.Mappings)
+		if err != nil {
+			log.Warn("server.applyTemplates", "index", index, "error", err.Error())
+		}
+	}
+}
+
+func (s *server) getMappings(index string) *indexMappings {
+	mappings, found := s.mappings[index]
+	if !found {
+		mappings = &indexMappings{
+			Properties: objectsMap{},
+			Parameters: objectsMap{},
+		}
+		s.mappings[index] = mappings
+	}
+
+	return mappings
+}
+
+func (im *indexMappings) merge(added *indexMappings) error {
+	err := mergeProperties(im.Properties, added.Properties, "")
+	if err != nil {
+		return err
+	}
+
+	for name, value := range added.Parameters {
+		im.Parameters[name] = value
+	}
+
+	return nil
+}
+
+func mergeProperties(existing objectsMap, added objectsMap, parentPath string) error {
+	for name, value := range added {
+		fieldPath := name
+		if parentPath != "" {
+			fieldPath = parentPath + "." + name
+		}
+
+		addedField, ok := value.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("the mapping of the field [%s] is not an object", fieldPath)
+		}
+
+		existingField, found := existing[name].(map[string]interface{})
+		if !found {
+			existing[name] = addedField
+			continue
+		}
+		if existingField[typeField] != addedField[typeField] {
+			return fmt.Errorf("mapper [%s] cannot be changed from type [%v] to [%v]", fieldPath, existingField[typeField], addedField[typeField])
+		}
+
+		addedProperties, hasProperties := addedField[propertiesField].(map[string]interface{})
+		if !hasProperties {
+			continue
+		}
+		existingProperties, ok := existingField[propertiesField].(map[string]interface{})
+		if !ok {
+			existingProperties = objectsMap{}
+			existingField[propertiesField] = existingProperties
+		}
+
+		err := mergeProperties(existingProperties, addedProperties, fieldPath)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func matchesAny(index string, patterns []string) bool {
+	for _, pattern := range patterns {
+		matched, err := path.Match(pattern, index)
+		if err == nil && matched {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,730 @@
+package memserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ME-MotherEarth/me-elastic-indexer/client/memstore"
+	logger "github.com/ME-MotherEarth/me-logger"
+)
+
+const (
+	defaultSearchSize = 10
+	serverVersion     = "7.12.0"
+	productHeader     = "X-Elastic-Product"
+	productName       = "Elasticsearch"
+	contentTypeHeader = "Content-Type"
+	contentTypeJSON   = "application/json"
+	scrollIDPrefix    = "scroll-"
+	allScrolls        = "_all"
+
+	errorResourceExists       = "resource_already_exists_exception"
+	errorResourceNotFound     = "resource_not_found_exception"
+	errorAliasesNotFound      = "aliases_not_found_exception"
+	errorSearchContextMissing = "search_context_missing_exception"
+	errorIllegalArgument      = "illegal_argument_exception"
+	errorParsing              = "parsing_exception"
+	errorPolicyExists         = "version_conflict_engine_exception"
+)
+
+var log = logger.GetOrCreate("indexer/client/memserver")
+
+type objectsMap = map[string]interface{}
+
+// scroll holds the hits of a search that were not returned yet
+type scroll struct {
+	hits       []*memstore.Hit
+	total      int
+	size       int
+	withSource bool
+}
+
+type server struct {
+	store storeHandler
+
+	mutex             sync.Mutex
+	templates         map[string]json.RawMessage
+	policies          map[string]json.RawMessage
+	lifecyclePolicies map[string]json.RawMessage
+	mappings          map[string]*indexMappings
+	scrolls           map[string]*scroll
+	nextScroll        uint64
+}
+
+// NewServer will create an http handler that answers the subset of the elasticsearch API used by the client package:
+// bulk requests, with the updates made by the document script of the elasticsearch sink, multi get, search with
+// scroll, count, delete by query, index creation, templates, field mappings, aliases and the lifecycle and state
+// management policies. The documents are kept in memory, so the server can replace a cluster in tests, e.g. behind an
+// httptest.Server
+func NewServer() *server {
+	return &server{
+		store:             memstore.NewStore(),
+		templates:         make(map[string]json.RawMessage),
+		policies:          make(map[string]json.RawMessage),
+		lifecyclePolicies: make(map[string]json.RawMessage),
+		mappings:          make(map[string]*indexMappings),
+		scrolls:           make(map[string]*scroll),
+	}
+}
+
+// ServeHTTP will route the request to the handler of the called API
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set(productHeader, productName)
+	w.Header().Set(contentTypeHeader, contentTypeJSON)
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, errorParsing, err.Error())
+		return
+	}
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) == 1 && parts[0] == "" {
+		parts = parts[:0]
+	}
+
+	switch {
+	case len(parts) == 0:
+		s.info(w, r)
+	case len(parts) >= 2 && parts[len(parts)-2] == "policies":
+		s.policy(w, r, parts[len(parts)-1], body)
+	case parts[0] == "_bulk":
+		s.bulk(w, r, "", body)
+	case parts[0] == "_search" && len(parts) >= 2 && parts[1] == "scroll":
+		s.scroll(w, r, parts[2:], body)
+	case parts[0] == "_template" && len(parts) == 2:
+		s.template(w, r, parts[1], body)
+	case parts[0] == "_alias" && len(parts) == 2:
+		s.alias(w, r, parts[1])
+	case parts[0] == "_ilm" && len(parts) == 3 && parts[1] == "policy":
+		s.lifecyclePolicy(w, r, parts[2], body)
+	case parts[0] == "_refresh":
+		s.refresh(w, r)
+	case len(parts) == 1:
+		s.index(w, r, parts[0])
+	case len(parts) == 2:
+		s.indexAPI(w, r, parts[0], parts[1], body)
+	case len(parts) == 3 && (parts[1] == "_alias" || parts[1] == "_aliases"):
+		s.putAlias(w, r, parts[0], parts[2])
+	case len(parts) == 3 && parts[1] == "_doc":
+		s.document(w, r, parts[0], parts[2], body)
+	default:
+		s.unsupported(w, r)
+	}
+}
+
+func (s *server) indexAPI(w http.ResponseWriter, r *http.Request, index string, api string, body []byte) {
+	switch api {
+	case "_bulk":
+		s.bulk(w, r, index, body)
+	case "_mget":
+		s.multiGet(w, r, index, body)
+	case "_search":
+		s.search(w, r, index, body)
+	case "_count":
+		s.count(w, r, index, body)
+	case "_delete_by_query":
+		s.deleteByQuery(w, r, index, body)
+	case "_refresh":
+		s.refresh(w, r)
+	case "_mapping":
+		s.mapping(w, r, index, body)
+	default:
+		s.unsupported(w, r)
+	}
+}
+
+func (s *server) info(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, r, http.StatusOK, objectsMap{
+		"name":         "memserver",
+		"cluster_name": "memserver",
+		"version": objectsMap{
+			"number":       serverVersion,
+			"build_flavor": "default",
+		},
+		"tagline": "You Know, for Search",
+	})
+}
+
+func (s *server) bulk(w http.ResponseWriter, r *http.Request, index string, body []byte) {
+	if !isMethod(r, http.MethodPost, http.MethodPut) {
+		s.unsupported(w, r)
+		return
+	}
+
+	items, err := s.store.ApplyBulk(body, index)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, errorIllegalArgument, err.Error())
+		return
+	}
+
+	hasErrors := false
+	responseItems := make([]objectsMap, 0, len(items))
+	for _, item := range items {
+		responseItem := objectsMap{
+			"_index": item.Index,
+			"_id":    item.ID,
+			"status": item.Status,
+		}
+		if item.Result != "" {
+			responseItem["result"] = item.Result
+		}
+		if item.ErrorType != "" {
+			hasErrors = true
+			responseItem["error"] = objectsMap{
+				"type":   item.ErrorType,
+				"reason": item.Reason,
+			}
+		}
+
+		responseItems = append(responseItems, objectsMap{item.Operation: responseItem})
+	}
+
+	writeJSON(w, r, http.StatusOK, objectsMap{
+		"took":   0,
+		"errors": hasErrors,
+		"items":  responseItems,
+	})
+}
+
+func (s *server) multiGet(w http.ResponseWriter, r *http.Request, index string, body []byte) {
+	request := struct {
+		IDs  []string `json:"ids"`
+		Docs []struct {
+			ID     string `json:"_id"`
+			Source *bool  `json:"_source"`
+		} `json:"docs"`
+	}{}
+	err := json.Unmarshal(body, &request)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, errorParsing, err.Error())
+		return
+	}
+
+	withSource := sourceParam(r, true)
+	ids := request.IDs
+	for _, doc := range request.Docs {
+		ids = append(ids, doc.ID)
+		if doc.Source != nil {
+			withSource = *doc.Source
+		}
+	}
+
+	writeJSON(w, r, http.StatusOK, s.store.MultiGet(index, ids, withSource))
+}
+
+func (s *server) search(w http.ResponseWriter, r *http.Request, index string, body []byte) {
+	hits, err := s.store.Search(index, body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, errorParsing, err.Error())
+		return
+	}
+
+	request := struct {
+		Size *int `json:"size"`
+		From int  `json:"from"`
+	}{}
+	if len(body) > 0 {
+		err = json.Unmarshal(body, &request)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, errorParsing, err.Error())
+			return
+		}
+	}
+
+	size := defaultSearchSize
+	if request.Size != nil {
+		size = *request.Size
+	}
+	size = intParam(r, "size", size)
+	from := intParam(r, "from", request.From)
+	if from > len(hits) {
+		from = len(hits)
+	}
+
+	state := &scroll{
+		hits:       hits[from:],
+		total:      len(hits),
+		size:       size,
+		withSource: sourceParam(r, true),
+	}
+	page := state.nextPage()
+
+	scrollID := ""
+	if r.URL.Query().Get("scroll") != "" {
+		scrollID = s.addScroll(state)
+	}
+
+	s.writeSearchResponse(w, r, page, state, scrollID)
+}
+
+func (s *server) scroll(w http.ResponseWriter, r *http.Request, pathIDs []string, body []byte) {
+	request := struct {
+		ScrollID json.RawMessage `json:"scroll_id"`
+	}{}
+	if len(body) > 0 {
+		err := json.Unmarshal(body, &request)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, errorParsing, err.Error())
+			return
+		}
+	}
+
+	ids := append([]string{}, pathIDs...)
+	if len(ids) == 1 {
+		ids = strings.Split(ids[0], ",")
+	}
+	queryID := r.URL.Query().Get("scroll_id")
+	if queryID != "" {
+		ids = append(ids, strings.Split(queryID, ",")...)
+	}
+	ids = append(ids, decodeScrollIDs(request.ScrollID)...)
+
+	if r.Method == http.MethodDelete {
+		s.clearScrolls(w, r, ids)
+		return
+	}
+	if len(ids) != 1 {
+		writeError(w, http.StatusBadRequest, errorIllegalArgument, "exactly one scroll id is required")
+		return
+	}
+
+	state, page, found := s.nextScrollPage(ids[0])
+	if !found {
+		writeError(w, http.StatusNotFound, errorSearchContextMissing, fmt.Sprintf("no search context found for id [%s]", ids[0]))
+		return
+	}
+
+	s.writeSearchResponse(w, r, page, state, ids[0])
+}
+
+func (s *server) nextScrollPage(scrollID string) (*scroll, []*memstore.Hit, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	state, found := s.scrolls[scrollID]
+	if !found {
+		return nil, nil, false
+	}
+
+	return state, state.nextPage(), true
+}
+
+func (s *server) clearScrolls(w http.ResponseWriter, r *http.Request, ids []string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	numFreed := 0
+	for _, id := range ids {
+		if id == allScrolls {
+			numFreed += len(s.scrolls)
+			s.scrolls = make(map[string]*scroll)
+			continue
+		}
+
+		_, found := s.scrolls[id]
+		if found {
+			delete(s.scrolls, id)
+			numFreed++
+		}
+	}
+
+	status := http.StatusOK
+	if numFreed == 0 {
+		status = http.StatusNotFound
+	}
+
+	writeJSON(w, r, status, objectsMap{
+		"succeeded": numFreed > 0,
+		"num_freed": numFreed,
+	})
+}
+
+func (s *server) addScroll(state *scroll) string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.nextScroll++
+	scrollID := scrollIDPrefix + strconv.FormatUint(s.nextScroll, 10)
+	s.scrolls[scrollID] = state
+
+	return scrollID
+}
+
+func (s *server) writeSearchResponse(w http.ResponseWriter, r *http.Request, page []*memstore.Hit, state *scroll, scrollID string) {
+	response, err := memstore.SearchResponse(page, state.total, state.withSource)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, errorIllegalArgument, err.Error())
+		return
+	}
+
+	fields := make(map[string]json.RawMessage)
+	err = json.Unmarshal(response, &fields)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, errorIllegalArgument, err.Error())
+		return
+	}
+	fields["took"] = json.RawMessage("0")
+	fields["timed_out"] = json.RawMessage("false")
+	if scrollID != "" {
+		fields["_scroll_id"], _ = json.Marshal(scrollID)
+	}
+
+	writeJSON(w, r, http.StatusOK, fields)
+}
+
+func (s *server) count(w http.ResponseWriter, r *http.Request, index string, body []byte) {
+	count, err := s.store.Count(index, body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, errorParsing, err.Error())
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, objectsMap{
+		"count": count,
+	})
+}
+
+func (s *server) deleteByQuery(w http.ResponseWriter, r *http.Request, index string, body []byte) {
+	if !isMethod(r, http.MethodPost) {
+		s.unsupported(w, r)
+		return
+	}
+
+	deleted, err := s.store.DeleteByQuery(index, body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, errorParsing, err.Error())
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, objectsMap{
+		"took":     0,
+		"total":    deleted,
+		"deleted":  deleted,
+		"failures": []interface{}{},
+	})
+}
+
+func (s *server) refresh(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, r, http.StatusOK, objectsMap{
+		"_shards": objectsMap{
+			"total":      1,
+			"successful": 1,
+			"failed":     0,
+		},
+	})
+}
+
+func (s *server) index(w http.ResponseWriter, r *http.Request, index string) {
+	exists := s.store.IndexExists(index)
+
+	switch r.Method {
+	case http.MethodHead:
+		writeExists(w, exists)
+	case http.MethodPut:
+		if exists {
+			writeError(w, http.StatusBadRequest, errorResourceExists, fmt.Sprintf("index [%s] already exists", index))
+			return
+		}
+
+		s.store.CreateIndex(index)
+		s.applyTemplates(index)
+		writeJSON(w, r, http.StatusOK, objectsMap{
+			"acknowledged":        true,
+			"shards_acknowledged": true,
+			"index":               index,
+		})
+	default:
+		s.unsupported(w, r)
+	}
+}
+
+// alias answers as if the alias had its only index as write index
+func (s *server) alias(w http.ResponseWriter, r *http.Request, alias string) {
+	index, exists := s.store.GetAliasIndex(alias)
+
+	switch r.Method {
+	case http.MethodHead:
+		writeExists(w, exists)
+	case http.MethodGet:
+		if !exists {
+			writeError(w, http.StatusNotFound, errorAliasesNotFound, fmt.Sprintf("alias [%s] missing", alias))
+			return
+		}
+
+		writeJSON(w, r, http.StatusOK, objectsMap{
+			index: objectsMap{
+				"aliases": objectsMap{
+					alias: objectsMap{
+						"is_write_index": true,
+					},
+				},
+			},
+		})
+	default:
+		s.unsupported(w, r)
+	}
+}
+
+func (s *server) putAlias(w http.ResponseWriter, r *http.Request, index string, alias string) {
+	if !isMethod(r, http.MethodPut, http.MethodPost) {
+		s.unsupported(w, r)
+		return
+	}
+
+	s.store.PutAlias(alias, index)
+	writeJSON(w, r, http.StatusOK, objectsMap{
+		"acknowledged": true,
+	})
+}
+
+func (s *server) template(w http.ResponseWriter, r *http.Request, name string, body []byte) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	template, exists := s.templates[name]
+
+	switch r.Method {
+	case http.MethodHead:
+		writeExists(w, exists)
+	case http.MethodGet:
+		if !exists {
+			writeJSON(w, r, http.StatusNotFound, objectsMap{})
+			return
+		}
+
+		writeJSON(w, r, http.StatusOK, map[string]json.RawMessage{name: template})
+	case http.MethodPut, http.MethodPost:
+		if !json.Valid(body) {
+			writeError(w, http.StatusBadRequest, errorParsing, "the template is not a valid json")
+			return
+		}
+
+		s.templates[name] = body
+		writeJSON(w, r, http.StatusOK, objectsMap{
+			"acknowledged": true,
+		})
+	default:
+		s.unsupported(w, r)
+	}
+}
+
+// policy answers the same way as the index state management plugin: the body of the errors holds the status
+func (s *server) policy(w http.ResponseWriter, r *http.Request, name string, body []byte) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	policy, exists := s.policies[name]
+
+	switch r.Method {
+	case http.MethodGet:
+		if !exists {
+			writeError(w, http.StatusNotFound, errorResourceNotFound, "Policy not found")
+			return
+		}
+
+		writeJSON(w, r, http.StatusOK, map[string]json.RawMessage{
+			"policy": policy,
+		})
+	case http.MethodPut:
+		if exists {
+			writeError(w, http.StatusConflict, errorPolicyExists,
+				fmt.Sprintf("[%s]: version conflict, document already exists", name))
+			return
+		}
+		if !json.Valid(body) {
+			writeError(w, http.StatusBadRequest, errorParsing, "the policy is not a valid json")
+			return
+		}
+
+		s.policies[name] = body
+		writeJSON(w, r, http.StatusCreated, objectsMap{
+			"_id": name,
+		})
+	default:
+		s.unsupported(w, r)
+	}
+}
+
+func (s *server) lifecyclePolicy(w http.ResponseWriter, r *http.Request, name string, body []byte) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	policy, exists := s.lifecyclePolicies[name]
+
+	switch r.Method {
+	case http.MethodGet:
+		if !exists {
+			writeError(w, http.StatusNotFound, errorResourceNotFound, fmt.Sprintf("Lifecycle policy not found: %s", name))
+			return
+		}
+
+		writeJSON(w, r, http.StatusOK, map[string]json.RawMessage{name: policy})
+	case http.MethodPut:
+		if !json.Valid(body) {
+			writeError(w, http.StatusBadRequest, errorParsing, "the policy is not a valid json")
+			return
+		}
+
+		s.lifecyclePolicies[name] = body
+		writeJSON(w, r, http.StatusOK, objectsMap{
+			"acknowledged": true,
+		})
+	default:
+		s.unsupported(w, r)
+	}
+}
+
+func (s *server) document(w http.ResponseWriter, r *http.Request, index string, id string, body []byte) {
+	switch r.Method {
+	case http.MethodGet:
+		source, found := s.store.GetDocument(index, id)
+		if !found {
+			writeJSON(w, r, http.StatusNotFound, objectsMap{
+				"_index": index,
+				"_id":    id,
+				"found":  false,
+			})
+			return
+		}
+
+		writeJSON(w, r, http.StatusOK, objectsMap{
+			"_index":  index,
+			"_id":     id,
+			"found":   true,
+			"_source": json.RawMessage(source),
+		})
+	case http.MethodPut, http.MethodPost:
+		if !json.Valid(body) {
+			writeError(w, http.StatusBadRequest, errorParsing, "the document is not a valid json")
+			return
+		}
+
+		_, existed := s.store.GetDocument(index, id)
+		s.store.PutDocument(index, id, body)
+
+		status, result := http.StatusCreated, "created"
+		if existed {
+			status, result = http.StatusOK, "updated"
+		}
+		writeJSON(w, r, status, objectsMap{
+			"_index": index,
+			"_id":    id,
+			"result": result,
+		})
+	default:
+		s.unsupported(w, r)
+	}
+}
+
+func (s *server) unsupported(w http.ResponseWriter, r *http.Request) {
+	log.Warn("memserver: unsupported request", "method", r.Method, "path", r.URL.Path)
+	writeError(w, http.StatusBadRequest, errorIllegalArgument,
+		fmt.Sprintf("unsupported request %s %s", r.Method, r.URL.Path))
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (s *server) IsInterfaceNil() bool {
+	return s == nil
+}
+
+func (sc *scroll) nextPage() []*memstore.Hit {
+	size := sc.size
+	if size > len(sc.hits) || size < 0 {
+		size = len(sc.hits)
+	}
+
+	page := sc.hits[:size]
+	sc.hits = sc.hits[size:]
+
+	return page
+}
+
+func decodeScrollIDs(raw json.RawMessage) []string {
+	if len(raw) == 0 {
+		return nil
+	}
+
+	ids := make([]string, 0)
+	err := json.Unmarshal(raw, &ids)
+	if err == nil {
+		return ids
+	}
+
+	id := ""
+	err = json.Unmarshal(raw, &id)
+	if err != nil || id == "" {
+		return nil
+	}
+
+	return []string{id}
+}
+
+func sourceParam(r *http.Request, defaultValue bool) bool {
+	value, err := strconv.ParseBool(r.URL.Query().Get("_source"))
+	if err != nil {
+		return defaultValue
+	}
+
+	return value
+}
+
+func intParam(r *http.Request, name string, defaultValue int) int {
+	value, err := strconv.Atoi(r.URL.Query().Get(name))
+	if err != nil {
+		return defaultValue
+	}
+
+	return value
+}
+
+func isMethod(r *http.Request, methods ...string) bool {
+	for _, method := range methods {
+		if r.Method == method {
+			return true
+		}
+	}
+
+	return false
+}
+
+func writeExists(w http.ResponseWriter, exists bool) {
+	if exists {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	w.WriteHeader(http.StatusNotFound)
+}
+
+func writeError(w http.ResponseWriter, status int, errorType string, reason string) {
+	response, _ := json.Marshal(objectsMap{
+		"error": objectsMap{
+			"type":   errorType,
+			"reason": reason,
+		},
+		"status": status,
+	})
+
+	w.WriteHeader(status)
+	_, _ = w.Write(response)
+}
+
+func writeJSON(w http.ResponseWriter, r *http.Request, status int, value interface{}) {
+	response, err := json.Marshal(value)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, errorIllegalArgument, err.Error())
+		return
+	}
+
+	w.WriteHeader(status)
+	if r.Method == http.MethodHead {
+		return
+	}
+	_, _ = w.Write(response)
+}
@@ -0,0 +1,222 @@
+package memserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ME-MotherEarth/me-elastic-indexer/client"
+	"github.com/elastic/go-elasticsearch/v7"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+)
+
+func startServer(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(NewServer())
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func doRequest(t *testing.T, method string, url string, body string) (int, string) {
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	require.Nil(t, err)
+
+	res, err := http.DefaultClient.Do(req)
+	require.Nil(t, err)
+	defer func() {
+		_ = res.Body.Close()
+	}()
+
+	response, err := ioutil.ReadAll(res.Body)
+	require.Nil(t, err)
+	require.Equal(t, productName, res.Header.Get(productHeader))
+
+	return res.StatusCode, string(response)
+}
+
+func TestServer_ElasticClient(t *testing.T) {
+	t.Parallel()
+
+	server := startServer(t)
+	esClient, err := client.NewElasticClient(elasticsearch.Config{
+		Addresses: []string{server.URL},
+	})
+	require.Nil(t, err)
+
+	require.Nil(t, esClient.CheckAndCreateTemplate("blocks", bytes.NewBufferString(`{"index_patterns":["blocks-*"]}`)))
+	require.Nil(t, esClient.CheckAndCreateTemplate("blocks", bytes.NewBufferString(`{"index_patterns":["blocks-*"]}`)))
+	require.Nil(t, esClient.CheckAndCreatePolicy("blocks_policy", bytes.NewBufferString(`{"policy":{}}`)))
+	require.Nil(t, esClient.CheckAndCreatePolicy("blocks_policy", bytes.NewBufferString(`{"policy":{}}`)))
+	require.Nil(t, esClient.CheckAndCreateIndex("blocks-000001"))
+	require.Nil(t, esClient.CheckAndCreateIndex("blocks-000001"))
+	require.Nil(t, esClient.CheckAndCreateAlias("blocks", "blocks-000001"))
+	require.Nil(t, esClient.CheckAndCreateAlias("blocks", "blocks-000001"))
+
+	bulk := `{"index":{"_id":"h1"}}
+{"nonce":1,"shardId":0}
+{"index":{"_index":"blocks","_id":"h2"}}
+{"nonce":2,"shardId":1}
+{"update":{"_id":"h3"}}
+{"doc":{"nonce":3,"shardId":0},"doc_as_upsert":true}
+{"update":{"_id":"h3"}}
+{"doc":{"shardId":1}}
+`
+	require.Nil(t, esClient.DoBulkRequest(bytes.NewBufferString(bulk), "blocks"))
+
+	response := &struct {
+		Docs []struct {
+			ID     string          `json:"_id"`
+			Found  bool            `json:"found"`
+			Source json.RawMessage `json:"_source"`
+		} `json:"docs"`
+	}{}
+	require.Nil(t, esClient.DoMultiGet([]string{"h3", "h4"}, "blocks", true, response))
+	require.Len(t, response.Docs, 2)
+	require.True(t, response.Docs[0].Found)
+	require.JSONEq(t, `{"nonce":3,"shardId":1}`, string(response.Docs[0].Source))
+	require.False(t, response.Docs[1].Found)
+
+	query := []byte(`{"query":{"term":{"shardId":1}}}`)
+	count, err := esClient.DoCountRequest("blocks", query)
+	require.Nil(t, err)
+	require.Equal(t, uint64(2), count)
+
+	ids := make([]string, 0)
+	err = esClient.DoScrollRequest("blocks", []byte(`{"sort":[{"nonce":"asc"}]}`), false, func(responseBytes []byte) error {
+		for _, hit := range gjson.GetBytes(responseBytes, "hits.hits").Array() {
+			require.False(t, hit.Get("_source").Exists())
+			ids = append(ids, hit.Get("_id").String())
+		}
+		return nil
+	})
+	require.Nil(t, err)
+	require.Equal(t, []string{"h1", "h2", "h3"}, ids)
+
+	require.Nil(t, esClient.DoQueryRemove("blocks", bytes.NewBuffer(query)))
+	count, err = esClient.DoCountRequest("blocks", nil)
+	require.Nil(t, err)
+	require.Equal(t, uint64(1), count)
+}
+
+func TestServer_BulkItemErrors(t *testing.T) {
+	t.Parallel()
+
+	server := startServer(t)
+	bulk := `{"create":{"_index":"tokens","_id":"t1"}}
+{"name":"first"}
+{"create":{"_index":"tokens","_id":"t1"}}
+{"name":"second"}
+{"delete":{"_index":"tokens","_id":"t2"}}
+`
+	status, response := doRequest(t, http.MethodPost, server.URL+"/_bulk", bulk)
+	require.Equal(t, http.StatusOK, status)
+	require.True(t, gjson.Get(response, "errors").Bool())
+	require.Equal(t, int64(http.StatusCreated), gjson.Get(response, "items.0.create.status").Int())
+	require.Equal(t, int64(http.StatusConflict), gjson.Get(response, "items.1.create.status").Int())
+	require.Equal(t, "version_conflict_engine_exception", gjson.Get(response, "items.1.create.error.type").String())
+	require.Equal(t, int64(http.StatusNotFound), gjson.Get(response, "items.2.delete.status").Int())
+
+	status, response = doRequest(t, http.MethodPost, server.URL+"/_bulk", `{"index":{"_id":"t1"}}`)
+	require.Equal(t, http.StatusBadRequest, status)
+	require.Equal(t, errorIllegalArgument, gjson.Get(response, "error.type").String())
+}
+
+func TestServer_SearchWithScroll(t *testing.T) {
+	t.Parallel()
+
+	server := startServer(t)
+	for _, id := range []string{"a", "b", "c"} {
+		status, _ := doRequest(t, http.MethodPut, server.URL+"/accounts/_doc/"+id, `{"balance":"1"}`)
+		require.Equal(t, http.StatusCreated, status)
+	}
+
+	status, response := doRequest(t, http.MethodGet, server.URL+"/accounts/_search?size=2&scroll=1m", "")
+	require.Equal(t, http.StatusOK, status)
+	require.Equal(t, int64(3), gjson.Get(response, "hits.total.value").Int())
+	require.Equal(t, `["a","b"]`, gjson.Get(response, "hits.hits.#._id").Raw)
+	scrollID := gjson.Get(response, "_scroll_id").String()
+	require.NotEmpty(t, scrollID)
+
+	status, response = doRequest(t, http.MethodGet, server.URL+"/_search/scroll?scroll=1m&scroll_id="+scrollID, "")
+	require.Equal(t, http.StatusOK, status)
+	require.Equal(t, `["c"]`, gjson.Get(response, "hits.hits.#._id").Raw)
+
+	status, response = doRequest(t, http.MethodPost, server.URL+"/_search/scroll", `{"scroll_id":"`+scrollID+`"}`)
+	require.Equal(t, http.StatusOK, status)
+	require.Equal(t, int64(0), gjson.Get(response, "hits.hits.#").Int())
+
+	status, _ = doRequest(t, http.MethodDelete, server.URL+"/_search/scroll/"+scrollID, "")
+	require.Equal(t, http.StatusOK, status)
+
+	status, response = doRequest(t, http.MethodGet, server.URL+"/_search/scroll?scroll_id="+scrollID, "")
+	require.Equal(t, http.StatusNotFound, status)
+	require.Equal(t, errorSearchContextMissing, gjson.Get(response, "error.type").String())
+}
+
+func TestServer_IndicesTemplatesAndAliases(t *testing.T) {
+	t.Parallel()
+
+	server := startServer(t)
+
+	status, _ := doRequest(t, http.MethodHead, server.URL+"/_template/tokens", "")
+	require.Equal(t, http.StatusNotFound, status)
+	status, _ = doRequest(t, http.MethodPut, server.URL+"/_template/tokens", `{"index_patterns":["tokens-*"]}`)
+	require.Equal(t, http.StatusOK, status)
+	status, response := doRequest(t, http.MethodGet, server.URL+"/_template/tokens", "")
+	require.Equal(t, http.StatusOK, status)
+	require.JSONEq(t, `{"tokens":{"index_patterns":["tokens-*"]}}`, response)
+
+	status, _ = doRequest(t, http.MethodPut, server.URL+"/tokens-000001", "")
+	require.Equal(t, http.StatusOK, status)
+	status, response = doRequest(t, http.MethodPut, server.URL+"/tokens-000001", "")
+	require.Equal(t, http.StatusBadRequest, status)
+	require.Equal(t, errorResourceExists, gjson.Get(response, "error.type").String())
+
+	status, _ = doRequest(t, http.MethodHead, server.URL+"/_alias/tokens", "")
+	require.Equal(t, http.StatusNotFound, status)
+	status, _ = doRequest(t, http.MethodPut, server.URL+"/tokens-000001/_aliases/tokens", "")
+	require.Equal(t, http.StatusOK, status)
+	status, _ = doRequest(t, http.MethodHead, server.URL+"/_alias/tokens", "")
+	require.Equal(t, http.StatusOK, status)
+	status, _ = doRequest(t, http.MethodHead, server.URL+"/tokens", "")
+	require.Equal(t, http.StatusOK, status)
+
+	status, _ = doRequest(t, http.MethodGet, server.URL+"/_cat/indices", "")
+	require.Equal(t, http.StatusBadRequest, status)
+}
+
+func TestServer_Mappings(t *testing.T) {
+	t.Parallel()
+
+	server := startServer(t)
+
+	status, response := doRequest(t, http.MethodGet, server.URL+"/tokens/_mapping", "")
+	require.Equal(t, http.StatusNotFound, status)
+	require.Equal(t, errorIndexNotFound, gjson.Get(response, "error.type").String())
+
+	status, _ = doRequest(t, http.MethodPut, server.URL+"/_template/tokens", `{"index_patterns":["tokens-*"],"mappings":{"properties":{"name":{"type":"keyword"}}}}`)
+	require.Equal(t, http.StatusOK, status)
+	status, _ = doRequest(t, http.MethodPut, server.URL+"/tokens-000001", "")
+	require.Equal(t, http.StatusOK, status)
+	status, _ = doRequest(t, http.MethodPut, server.URL+"/tokens-000001/_aliases/tokens", "")
+	require.Equal(t, http.StatusOK, status)
+
+	status, response = doRequest(t, http.MethodGet, server.URL+"/tokens/_mapping", "")
+	require.Equal(t, http.StatusOK, status)
+	require.JSONEq(t, `{"tokens-000001":{"mappings":{"properties":{"name":{"type":"keyword"}}}}}`, response)
+
+	status, _ = doRequest(t, http.MethodPut, server.URL+"/tokens/_mapping", `{"dynamic":false,"properties":{"ticker":{"type":"keyword"}}}`)
+	require.Equal(t, http.StatusOK, status)
+	status, response = doRequest(t, http.MethodPut, server.URL+"/tokens/_mapping", `{"properties":{"name":{"type":"text"}}}`)
+	require.Equal(t, http.StatusBadRequest, status)
+	require.Equal(t, errorIllegalArgument, gjson.Get(response, "error.type").String())
+
+	status, response = doRequest(t, http.MethodGet, server.URL+"/tokens-000001/_mapping", "")
+	require.Equal(t, http.StatusOK, status)
+	require.JSONEq(t, `{"tokens-000001":{"mappings":{"dynamic":false,"properties":{"name":{"type":"keyword"},"ticker":{"type":"keyword"}}}}}`, response)
+}
@@ -0,0 +1,329 @@
+package memstore
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/ME-MotherEarth/me-elastic-indexer/data"
+	"github.com/ME-MotherEarth/me-elastic-indexer/process/sink/docstate"
+)
+
+const (
+	operationIndex  = "index"
+	operationCreate = "create"
+	operationUpdate = "update"
+	operationDelete = "delete"
+
+	resultCreated  = "created"
+	resultUpdated  = "updated"
+	resultDeleted  = "deleted"
+	resultNoop     = "noop"
+	resultNotFound = "not_found"
+)
+
+var scriptActions = map[string]data.DocumentAction{
+	"index":  data.ActionIndex,
+	"create": data.ActionCreate,
+	"upsert": data.ActionUpsert,
+	"update": data.ActionUpdate,
+	"delete": data.ActionDelete,
+}
+
+// BulkAction is one action of a bulk request, with its document line. The delete actions have no document line
+type BulkAction struct {
+	Operation string
+	Index     string
+	ID        string
+	Source    []byte
+}
+
+// updateBody is the document line of an update action. The script is expected to be the document script of the
+// elasticsearch sink, so only its params are used
+type updateBody struct {
+	Doc            json.RawMessage `json:"doc"`
+	DocAsUpsert    bool            `json:"doc_as_upsert"`
+	ScriptedUpsert bool            `json:"scripted_upsert"`
+	Upsert         json.RawMessage `json:"upsert"`
+	Script         *struct {
+		Params *scriptParams `json:"params"`
+	} `json:"script"`
+}
+
+type scriptParams struct {
+	Action        string                   `json:"action"`
+	Body          json.RawMessage          `json:"body"`
+	Timestamp     uint64                   `json:"timestamp"`
+	Nonce         uint64                   `json:"nonce"`
+	Keep          []string                 `json:"keep"`
+	Fields        map[string]interface{}   `json:"fields"`
+	Increments    map[string]int64         `json:"increments"`
+	Append        map[string][]interface{} `json:"append"`
+	AddToSet      map[string][]interface{} `json:"addToSet"`
+	RemoveFromSet map[string][]interface{} `json:"removeFromSet"`
+	Cleanup       []string                 `json:"cleanup"`
+}
+
+// ParseBulk will split a bulk request body in actions. The actions without an index use the provided default index
+func ParseBulk(body []byte, defaultIndex string) ([]*BulkAction, error) {
+	lines := bytes.Split(body, []byte("\n"))
+	actions := make([]*BulkAction, 0)
+	for idx := 0; idx < len(lines); idx++ {
+		metaLine := bytes.TrimSpace(lines[idx])
+		if len(metaLine) == 0 {
+			continue
+		}
+
+		meta := make(map[string]struct {
+			Index string `json:"_index"`
+			ID    string `json:"_id"`
+		})
+		err := json.Unmarshal(metaLine, &meta)
+		if err != nil || len(meta) != 1 {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidBulkAction, string(metaLine))
+		}
+
+		action := &BulkAction{}
+		for operation, target := range meta {
+			action.Operation = operation
+			action.Index = target.Index
+			action.ID = target.ID
+		}
+		if action.Index == "" {
+			action.Index = defaultIndex
+		}
+		if action.Operation == operationDelete {
+			actions = append(actions, action)
+			continue
+		}
+
+		idx++
+		if idx >= len(lines) {
+			return nil, fmt.Errorf("%w: missing document line for %s", ErrInvalidBulkAction, string(metaLine))
+		}
+		action.Source = bytes.TrimSpace(lines[idx])
+		actions = append(actions, action)
+	}
+
+	return actions, nil
+}
+
+// ApplyBulk will apply, in order, the actions of the provided bulk request body and will return their outcome
+func (s *store) ApplyBulk(body []byte, defaultIndex string) ([]*BulkItem, error) {
+	actions, err := ParseBulk(body, defaultIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	items := make([]*BulkItem, 0, len(actions))
+	for _, action := range actions {
+		index := s.resolve(action.Index)
+		item := &BulkItem{
+			Operation: action.Operation,
+			Index:     index,
+			ID:        action.ID,
+		}
+		s.applyAction(s.getOrCreateIndex(index), action, item)
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+func (s *store) applyAction(documents map[string][]byte, action *BulkAction, item *BulkItem) {
+	_, existed := documents[action.ID]
+	switch action.Operation {
+	case operationIndex:
+		if !json.Valid(action.Source) {
+			item.setError(http.StatusBadRequest, "mapper_parsing_exception", "failed to parse the document")
+			return
+		}
+		documents[action.ID] = compact(action.Source)
+		item.setWritten(existed)
+	case operationCreate:
+		if existed {
+			item.setError(http.StatusConflict, "version_conflict_engine_exception", "document already exists")
+			return
+		}
+		if !json.Valid(action.Source) {
+			item.setError(http.StatusBadRequest, "mapper_parsing_exception", "failed to parse the document")
+			return
+		}
+		documents[action.ID] = compact(action.Source)
+		item.setWritten(false)
+	case operationDelete:
+		if !existed {
+			item.Status, item.Result = http.StatusNotFound, resultNotFound
+			return
+		}
+		delete(documents, action.ID)
+		item.Status, item.Result = http.StatusOK, resultDeleted
+	case operationUpdate:
+		applyUpdate(documents, action, item, existed)
+	default:
+		item.setError(http.StatusBadRequest, "illegal_argument_exception", "unknown operation "+action.Operation)
+	}
+}
+
+func applyUpdate(documents map[string][]byte, action *BulkAction, item *BulkItem, existed bool) {
+	body := &updateBody{}
+	decoder := json.NewDecoder(bytes.NewReader(action.Source))
+	decoder.UseNumber()
+	err := decoder.Decode(body)
+	if err != nil {
+		item.setError(http.StatusBadRequest, "x_content_parse_exception", err.Error())
+		return
+	}
+
+	var state *docstate.State
+	switch {
+	case body.Script != nil && body.Script.Params != nil:
+		if !existed && !body.ScriptedUpsert {
+			if !hasContent(body.Upsert) {
+				item.setError(http.StatusNotFound, "document_missing_exception", "document missing")
+				return
+			}
+			state, err = applyState(documents, action.ID, replaceWith(body.Upsert))
+			break
+		}
+
+		var document *data.Document
+		document, err = body.Script.Params.toDocument()
+		if err != nil {
+			item.setError(http.StatusBadRequest, "illegal_argument_exception", err.Error())
+			return
+		}
+		state, err = applyState(documents, action.ID, func(state *docstate.State) error {
+			return state.Apply(document)
+		})
+	case hasContent(body.Doc):
+		if !existed && !body.DocAsUpsert && !hasContent(body.Upsert) {
+			item.setError(http.StatusNotFound, "document_missing_exception", "document missing")
+			return
+		}
+		state, err = applyState(documents, action.ID, mergeDoc(body, existed))
+	default:
+		item.setError(http.StatusBadRequest, "action_request_validation_exception", "script or doc is missing")
+		return
+	}
+	if err != nil {
+		item.setError(http.StatusBadRequest, "illegal_argument_exception", err.Error())
+		return
+	}
+
+	switch {
+	case !state.Changed():
+		item.Status, item.Result = http.StatusOK, resultNoop
+	case !state.Exists():
+		item.Status, item.Result = http.StatusOK, resultDeleted
+	default:
+		item.setWritten(existed)
+	}
+}
+
+func replaceWith(source json.RawMessage) func(state *docstate.State) error {
+	return func(state *docstate.State) error {
+		return state.Apply(&data.Document{
+			Action: data.ActionIndex,
+			Body:   source,
+		})
+	}
+}
+
+// mergeDoc will merge the partial document in the stored one, the same way elasticsearch merges the objects
+func mergeDoc(body *updateBody, existed bool) func(state *docstate.State) error {
+	return func(state *docstate.State) error {
+		if !existed {
+			if body.DocAsUpsert {
+				return replaceWith(body.Doc)(state)
+			}
+			return replaceWith(body.Upsert)(state)
+		}
+
+		stored, err := state.Serialize()
+		if err != nil {
+			return err
+		}
+		source, err := decodeSource(stored)
+		if err != nil {
+			return err
+		}
+		partial, err := decodeSource(body.Doc)
+		if err != nil {
+			return err
+		}
+
+		mergeObjects(source, partial)
+		serialized, err := json.Marshal(source)
+		if err != nil {
+			return err
+		}
+		if bytes.Equal(compact(serialized), compact(stored)) {
+			return nil
+		}
+
+		return replaceWith(serialized)(state)
+	}
+}
+
+func mergeObjects(target objectsMap, partial objectsMap) {
+	for key, value := range partial {
+		partialObject, isObject := value.(objectsMap)
+		targetObject, isTargetObject := target[key].(objectsMap)
+		if isObject && isTargetObject {
+			mergeObjects(targetObject, partialObject)
+			continue
+		}
+
+		target[key] = value
+	}
+}
+
+func (sp *scriptParams) toDocument() (*data.Document, error) {
+	action, ok := scriptActions[sp.Action]
+	if !ok {
+		return nil, fmt.Errorf("unknown script action %s", sp.Action)
+	}
+
+	document := &data.Document{
+		Action:        action,
+		KeepFields:    sp.Keep,
+		Fields:        sp.Fields,
+		Increments:    sp.Increments,
+		Append:        sp.Append,
+		AddToSet:      sp.AddToSet,
+		RemoveFromSet: sp.RemoveFromSet,
+		Timestamp:     sp.Timestamp,
+		Nonce:         sp.Nonce,
+		DeleteIfEmpty: sp.Cleanup != nil,
+	}
+	if hasContent(sp.Body) {
+		document.Body = sp.Body
+	}
+
+	return document, nil
+}
+
+func hasContent(raw json.RawMessage) bool {
+	trimmed := bytes.TrimSpace(raw)
+	return len(trimmed) != 0 && !bytes.Equal(trimmed, []byte("null"))
+}
+
+func (bi *BulkItem) setWritten(existed bool) {
+	if existed {
+		bi.Status, bi.Result = http.StatusOK, resultUpdated
+		return
+	}
+
+	bi.Status, bi.Result = http.StatusCreated, resultCreated
+}
+
+func (bi *BulkItem) setError(status int, errorType string, reason string) {
+	bi.Status = status
+	bi.ErrorType = errorType
+	bi.Reason = reason
+}
@@ -0,0 +1,12 @@
+package memstore
+
+import "errors"
+
+// ErrInvalidBulkAction signals that a bulk request body contains an action that cannot be parsed
+var ErrInvalidBulkAction = errors.New("invalid bulk action")
+
+// ErrUnsupportedQuery signals that a query uses a clause the store cannot evaluate
+var ErrUnsupportedQuery = errors.New("unsupported query")
+
+// ErrInvalidQuery signals that a query cannot be parsed
+var ErrInvalidQuery = errors.New("invalid query")
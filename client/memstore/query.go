@@ -0,0 +1,550 @@
+package memstore
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type matcher func(id string, source objectsMap) bool
+
+type sortField struct {
+	field      string
+	descending bool
+}
+
+type searchRequest struct {
+	query      matcher
+	sortFields []*sortField
+	docValues  []string
+}
+
+func parseSearchRequest(body []byte) (*searchRequest, error) {
+	request := &searchRequest{
+		query: matchAll,
+	}
+	if len(bytes.TrimSpace(body)) == 0 {
+		return request, nil
+	}
+
+	decoded := make(objectsMap)
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	err := decoder.Decode(&decoded)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidQuery, err.Error())
+	}
+
+	query, found := decoded["query"]
+	if found {
+		request.query, err = compileQuery(query)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	request.sortFields, err = parseSort(decoded["sort"])
+	if err != nil {
+		return nil, err
+	}
+
+	request.docValues, err = parseDocValueFields(decoded["docvalue_fields"])
+	if err != nil {
+		return nil, err
+	}
+
+	return request, nil
+}
+
+func compileQuery(query interface{}) (matcher, error) {
+	clause, ok := query.(objectsMap)
+	if !ok || len(clause) != 1 {
+		return nil, fmt.Errorf("%w: a query has to hold exactly one clause", ErrInvalidQuery)
+	}
+
+	for name, value := range clause {
+		switch name {
+		case "match_all":
+			return matchAll, nil
+		case "bool":
+			return compileBool(value)
+		case "ids":
+			return compileIds(value)
+		case "exists":
+			return compileExists(value)
+		case "term", "match", "match_phrase", "range", "terms":
+			return compileFieldClause(name, value)
+		default:
+			return nil, fmt.Errorf("%w: %s", ErrUnsupportedQuery, name)
+		}
+	}
+
+	return nil, ErrInvalidQuery
+}
+
+func matchAll(_ string, _ objectsMap) bool {
+	return true
+}
+
+func compileBool(value interface{}) (matcher, error) {
+	clauses, ok := value.(objectsMap)
+	if !ok {
+		return nil, fmt.Errorf("%w: bool", ErrInvalidQuery)
+	}
+
+	compiled := make(map[string][]matcher)
+	for _, occur := range []string{"must", "filter", "should", "must_not"} {
+		matchers, err := compileList(clauses[occur])
+		if err != nil {
+			return nil, err
+		}
+		compiled[occur] = matchers
+	}
+
+	minimumShouldMatch := 0
+	if len(compiled["should"]) > 0 && len(compiled["must"]) == 0 && len(compiled["filter"]) == 0 {
+		minimumShouldMatch = 1
+	}
+	value, found := clauses["minimum_should_match"]
+	if found {
+		number, err := strconv.Atoi(fmt.Sprintf("%v", value))
+		if err != nil {
+			return nil, fmt.Errorf("%w: minimum_should_match %v", ErrUnsupportedQuery, value)
+		}
+		minimumShouldMatch = number
+	}
+
+	return func(id string, source objectsMap) bool {
+		for _, m := range append(compiled["must"], compiled["filter"]...) {
+			if !m(id, source) {
+				return false
+			}
+		}
+		for _, m := range compiled["must_not"] {
+			if m(id, source) {
+				return false
+			}
+		}
+
+		numMatched := 0
+		for _, m := range compiled["should"] {
+			if m(id, source) {
+				numMatched++
+			}
+		}
+
+		return numMatched >= minimumShouldMatch
+	}, nil
+}
+
+func compileList(value interface{}) ([]matcher, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	list, ok := value.([]interface{})
+	if !ok {
+		list = []interface{}{value}
+	}
+
+	matchers := make([]matcher, 0, len(list))
+	for _, query := range list {
+		m, err := compileQuery(query)
+		if err != nil {
+			return nil, err
+		}
+		matchers = append(matchers, m)
+	}
+
+	return matchers, nil
+}
+
+func compileIds(value interface{}) (matcher, error) {
+	clause, ok := value.(objectsMap)
+	if !ok {
+		return nil, fmt.Errorf("%w: ids", ErrInvalidQuery)
+	}
+
+	values, _ := clause["values"].([]interface{})
+	ids := make(map[string]struct{}, len(values))
+	for _, id := range values {
+		ids[fmt.Sprintf("%v", id)] = struct{}{}
+	}
+
+	return func(id string, _ objectsMap) bool {
+		_, found := ids[id]
+		return found
+	}, nil
+}
+
+func compileExists(value interface{}) (matcher, error) {
+	clause, ok := value.(objectsMap)
+	if !ok {
+		return nil, fmt.Errorf("%w: exists", ErrInvalidQuery)
+	}
+
+	field, ok := clause["field"].(string)
+	if !ok {
+		return nil, fmt.Errorf("%w: exists without field", ErrInvalidQuery)
+	}
+
+	return func(_ string, source objectsMap) bool {
+		return len(fieldValues(source, field)) > 0
+	}, nil
+}
+
+func compileFieldClause(name string, value interface{}) (matcher, error) {
+	clause, ok := value.(objectsMap)
+	if !ok || len(clause) != 1 {
+		return nil, fmt.Errorf("%w: %s has to hold exactly one field", ErrInvalidQuery, name)
+	}
+
+	for field, params := range clause {
+		switch name {
+		case "term":
+			return compileTerm(field, params), nil
+		case "terms":
+			return compileTerms(field, params)
+		case "match", "match_phrase":
+			return compileMatch(field, params, name == "match_phrase"), nil
+		default:
+			return compileRange(field, params)
+		}
+	}
+
+	return nil, ErrInvalidQuery
+}
+
+func compileTerm(field string, params interface{}) matcher {
+	expected := params
+	object, isObject := params.(objectsMap)
+	if isObject {
+		expected = object["value"]
+	}
+
+	return func(_ string, source objectsMap) bool {
+		for _, value := range fieldValues(source, field) {
+			if valuesEqual(value, expected) {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+func compileTerms(field string, params interface{}) (matcher, error) {
+	expectedValues, ok := params.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%w: terms of %s has to be a list", ErrInvalidQuery, field)
+	}
+
+	return func(_ string, source objectsMap) bool {
+		for _, value := range fieldValues(source, field) {
+			for _, expected := range expectedValues {
+				if valuesEqual(value, expected) {
+					return true
+				}
+			}
+		}
+
+		return false
+	}, nil
+}
+
+// compileMatch will match the values of the field as the standard analyzer would: the text values are split in
+// lowercase words and all the words of the query have to be found if the operator is AND, any of them otherwise
+func compileMatch(field string, params interface{}, isPhrase bool) matcher {
+	query := params
+	operatorAnd := isPhrase
+	object, isObject := params.(objectsMap)
+	if isObject {
+		query = object["query"]
+		operator, _ := object["operator"].(string)
+		operatorAnd = operatorAnd || strings.EqualFold(operator, "and")
+	}
+
+	queryText, isText := query.(string)
+	if !isText {
+		return compileTerm(field, query)
+	}
+	queryWords := tokenize(queryText)
+
+	return func(_ string, source objectsMap) bool {
+		for _, value := range fieldValues(source, field) {
+			text, ok := value.(string)
+			if !ok {
+				if valuesEqual(value, query) {
+					return true
+				}
+				continue
+			}
+
+			if isPhrase && strings.Contains(strings.Join(tokenize(text), " "), strings.Join(queryWords, " ")) {
+				return true
+			}
+			if !isPhrase && containsWords(tokenize(text), queryWords, operatorAnd) {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+func compileRange(field string, params interface{}) (matcher, error) {
+	bounds, ok := params.(objectsMap)
+	if !ok {
+		return nil, fmt.Errorf("%w: range of %s", ErrInvalidQuery, field)
+	}
+
+	checks := make([]func(cmp int) bool, 0)
+	limits := make([]interface{}, 0)
+	for operator, limit := range bounds {
+		var check func(cmp int) bool
+		switch operator {
+		case "gt":
+			check = func(cmp int) bool { return cmp > 0 }
+		case "gte":
+			check = func(cmp int) bool { return cmp >= 0 }
+		case "lt":
+			check = func(cmp int) bool { return cmp < 0 }
+		case "lte":
+			check = func(cmp int) bool { return cmp <= 0 }
+		default:
+			continue
+		}
+		checks = append(checks, check)
+		limits = append(limits, limit)
+	}
+
+	return func(_ string, source objectsMap) bool {
+		for _, value := range fieldValues(source, field) {
+			inRange := true
+			for idx, check := range checks {
+				if !check(compareValues(value, limits[idx])) {
+					inRange = false
+					break
+				}
+			}
+			if inRange {
+				return true
+			}
+		}
+
+		return false
+	}, nil
+}
+
+func parseSort(value interface{}) ([]*sortField, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	list, ok := value.([]interface{})
+	if !ok {
+		list = []interface{}{value}
+	}
+
+	fields := make([]*sortField, 0, len(list))
+	for _, element := range list {
+		switch typed := element.(type) {
+		case string:
+			fields = append(fields, &sortField{field: typed})
+		case objectsMap:
+			for field, order := range typed {
+				descending := order == "desc"
+				orderObject, isObject := order.(objectsMap)
+				if isObject {
+					descending = orderObject["order"] == "desc"
+				}
+				fields = append(fields, &sortField{field: field, descending: descending})
+			}
+		default:
+			return nil, fmt.Errorf("%w: sort %v", ErrInvalidQuery, element)
+		}
+	}
+
+	return fields, nil
+}
+
+func parseDocValueFields(value interface{}) ([]string, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	list, ok := value.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%w: docvalue_fields has to be a list", ErrInvalidQuery)
+	}
+
+	fields := make([]string, 0, len(list))
+	for _, element := range list {
+		switch typed := element.(type) {
+		case string:
+			fields = append(fields, typed)
+		case objectsMap:
+			field, _ := typed["field"].(string)
+			fields = append(fields, field)
+		}
+	}
+
+	return fields, nil
+}
+
+func (sr *searchRequest) docValueFields(source objectsMap) map[string][]interface{} {
+	if len(sr.docValues) == 0 {
+		return nil
+	}
+
+	fields := make(map[string][]interface{})
+	for _, field := range sr.docValues {
+		values := fieldValues(source, field)
+		if len(values) > 0 {
+			fields[field] = values
+		}
+	}
+
+	return fields
+}
+
+func (sr *searchRequest) sortHits(hits []*Hit) {
+	sort.SliceStable(hits, func(i, j int) bool {
+		for _, sf := range sr.sortFields {
+			cmp := compareSortValues(hits[i], hits[j], sf.field)
+			if cmp == 0 {
+				continue
+			}
+			if sf.descending {
+				return cmp > 0
+			}
+			return cmp < 0
+		}
+
+		return hits[i].ID < hits[j].ID
+	})
+}
+
+func compareSortValues(first *Hit, second *Hit, field string) int {
+	if field == "_id" {
+		return strings.Compare(first.ID, second.ID)
+	}
+
+	firstValues := fieldValues(first.decoded, field)
+	secondValues := fieldValues(second.decoded, field)
+	switch {
+	case len(firstValues) == 0 && len(secondValues) == 0:
+		return 0
+	case len(firstValues) == 0:
+		return 1
+	case len(secondValues) == 0:
+		return -1
+	default:
+		return compareValues(firstValues[0], secondValues[0])
+	}
+}
+
+// fieldValues returns the values of the field addressed by the provided dotted path. The arrays are flattened, as
+// elasticsearch does when it indexes them
+func fieldValues(source objectsMap, path string) []interface{} {
+	value, found := source[path]
+	if found {
+		return flatten(value)
+	}
+
+	keys := strings.SplitN(path, ".", 2)
+	if len(keys) < 2 {
+		return nil
+	}
+
+	values := make([]interface{}, 0)
+	for _, child := range flatten(source[keys[0]]) {
+		object, isObject := child.(objectsMap)
+		if isObject {
+			values = append(values, fieldValues(object, keys[1])...)
+		}
+	}
+
+	return values
+}
+
+func flatten(value interface{}) []interface{} {
+	switch typed := value.(type) {
+	case nil:
+		return nil
+	case []interface{}:
+		values := make([]interface{}, 0, len(typed))
+		for _, element := range typed {
+			values = append(values, flatten(element)...)
+		}
+		return values
+	default:
+		return []interface{}{value}
+	}
+}
+
+func valuesEqual(first interface{}, second interface{}) bool {
+	return compareValues(first, second) == 0
+}
+
+// compareValues compares the values as numbers if both can be read as numbers and as text otherwise
+func compareValues(first interface{}, second interface{}) int {
+	firstNumber, isFirstNumber := toFloat(first)
+	secondNumber, isSecondNumber := toFloat(second)
+	if isFirstNumber && isSecondNumber {
+		switch {
+		case firstNumber < secondNumber:
+			return -1
+		case firstNumber > secondNumber:
+			return 1
+		default:
+			return 0
+		}
+	}
+
+	return strings.Compare(fmt.Sprintf("%v", first), fmt.Sprintf("%v", second))
+}
+
+func toFloat(value interface{}) (float64, bool) {
+	switch typed := value.(type) {
+	case json.Number:
+		number, err := typed.Float64()
+		return number, err == nil
+	case float64:
+		return typed, true
+	case string:
+		number, err := strconv.ParseFloat(typed, 64)
+		return number, err == nil
+	default:
+		return 0, false
+	}
+}
+
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func containsWords(words []string, queryWords []string, all bool) bool {
+	wordsSet := make(map[string]struct{}, len(words))
+	for _, word := range words {
+		wordsSet[word] = struct{}{}
+	}
+
+	for _, queryWord := range queryWords {
+		_, found := wordsSet[queryWord]
+		if found && !all {
+			return true
+		}
+		if !found && all {
+			return false
+		}
+	}
+
+	return all && len(queryWords) > 0
+}
@@ -0,0 +1,65 @@
+package memstore
+
+import "encoding/json"
+
+// Hit is a document returned by a search
+type Hit struct {
+	Index  string                   `json:"_index"`
+	ID     string                   `json:"_id"`
+	Source json.RawMessage          `json:"_source,omitempty"`
+	Fields map[string][]interface{} `json:"fields,omitempty"`
+
+	decoded objectsMap
+}
+
+// MultiGetResponse holds the documents returned by a multi get request
+type MultiGetResponse struct {
+	Docs []*MultiGetDocument `json:"docs"`
+}
+
+// MultiGetDocument is a document returned by a multi get request
+type MultiGetDocument struct {
+	Index  string          `json:"_index"`
+	ID     string          `json:"_id"`
+	Found  bool            `json:"found"`
+	Source json.RawMessage `json:"_source,omitempty"`
+}
+
+// BulkItem holds the outcome of one action of a bulk request
+type BulkItem struct {
+	Operation string
+	Index     string
+	ID        string
+	Status    int
+	Result    string
+	ErrorType string
+	Reason    string
+}
+
+// SearchResponse will return a search response holding the provided hits. The source of the hits is kept only if
+// withSource is true
+func SearchResponse(hits []*Hit, total int, withSource bool) ([]byte, error) {
+	responseHits := make([]*Hit, 0, len(hits))
+	for _, hit := range hits {
+		responseHit := &Hit{
+			Index:  hit.Index,
+			ID:     hit.ID,
+			Fields: hit.Fields,
+		}
+		if withSource {
+			responseHit.Source = hit.Source
+		}
+
+		responseHits = append(responseHits, responseHit)
+	}
+
+	return json.Marshal(map[string]interface{}{
+		"hits": map[string]interface{}{
+			"total": map[string]interface{}{
+				"value":    total,
+				"relation": "eq",
+			},
+			"hits": responseHits,
+		},
+	})
+}
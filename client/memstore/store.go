@@ -0,0 +1,273 @@
+package memstore
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"sync"
+
+	"github.com/ME-MotherEarth/me-elastic-indexer/process/sink/docstate"
+	logger "github.com/ME-MotherEarth/me-logger"
+)
+
+var log = logger.GetOrCreate("indexer/client/memstore")
+
+type objectsMap = map[string]interface{}
+
+type store struct {
+	mutex   sync.RWMutex
+	indices map[string]map[string][]byte
+	aliases map[string]string
+}
+
+// NewStore will create an in-memory documents store that understands the subset of the elasticsearch API used by the
+// indexer: bulk requests, including the updates made with the document script of the elasticsearch sink, multi get,
+// search, count and delete by query. The aliases are resolved to their index, while an index that was not created
+// is created when the first document is written in it
+func NewStore() *store {
+	return &store{
+		indices: make(map[string]map[string][]byte),
+		aliases: make(map[string]string),
+	}
+}
+
+// CreateIndex will create an empty index if it does not exist
+func (s *store) CreateIndex(index string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.getOrCreateIndex(index)
+}
+
+// IndexExists returns true if the provided name is an index or an alias
+func (s *store) IndexExists(index string) bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	_, found := s.indices[s.resolve(index)]
+	return found
+}
+
+// PutAlias will point the alias to the provided index, which is created if missing
+func (s *store) PutAlias(alias string, index string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.getOrCreateIndex(index)
+	s.aliases[alias] = index
+}
+
+// AliasExists returns true if the alias was created
+func (s *store) AliasExists(alias string) bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	_, found := s.aliases[alias]
+	return found
+}
+
+// GetAliasIndex returns the index the alias points to
+func (s *store) GetAliasIndex(alias string) (string, bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	index, found := s.aliases[alias]
+	return index, found
+}
+
+// PutDocument will replace the source of the document
+func (s *store) PutDocument(index string, id string, source []byte) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.getOrCreateIndex(s.resolve(index))[id] = compact(source)
+}
+
+// GetDocument returns the source of the document, if it exists
+func (s *store) GetDocument(index string, id string) ([]byte, bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	source, found := s.indices[s.resolve(index)][id]
+	return source, found
+}
+
+// MultiGet returns the documents with the provided ids, in the format of a multi get response
+func (s *store) MultiGet(index string, ids []string, withSource bool) *MultiGetResponse {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	resolved := s.resolve(index)
+	response := &MultiGetResponse{
+		Docs: make([]*MultiGetDocument, 0, len(ids)),
+	}
+	for _, id := range ids {
+		source, found := s.indices[resolved][id]
+		document := &MultiGetDocument{
+			Index: resolved,
+			ID:    id,
+			Found: found,
+		}
+		if found && withSource {
+			document.Source = source
+		}
+
+		response.Docs = append(response.Docs, document)
+	}
+
+	return response
+}
+
+// Search returns all the documents of the index that match the query of the provided request body, sorted as the
+// request requires or by id otherwise
+func (s *store) Search(index string, body []byte) ([]*Hit, error) {
+	request, err := parseSearchRequest(body)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	resolved := s.resolve(index)
+	hits := make([]*Hit, 0)
+	for id, source := range s.indices[resolved] {
+		decoded, errDecode := decodeSource(source)
+		if errDecode != nil {
+			return nil, errDecode
+		}
+		if !request.query(id, decoded) {
+			continue
+		}
+
+		hits = append(hits, &Hit{
+			Index:   resolved,
+			ID:      id,
+			Source:  source,
+			Fields:  request.docValueFields(decoded),
+			decoded: decoded,
+		})
+	}
+
+	request.sortHits(hits)
+
+	return hits, nil
+}
+
+// Count returns the number of documents of the index that match the query of the provided request body
+func (s *store) Count(index string, body []byte) (uint64, error) {
+	hits, err := s.Search(index, body)
+	if err != nil {
+		return 0, err
+	}
+
+	return uint64(len(hits)), nil
+}
+
+// DeleteByQuery will remove the documents of the index that match the query of the provided request body and will
+// return their number
+func (s *store) DeleteByQuery(index string, body []byte) (uint64, error) {
+	hits, err := s.Search(index, body)
+	if err != nil {
+		return 0, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, hit := range hits {
+		delete(s.indices[hit.Index], hit.ID)
+	}
+
+	return uint64(len(hits)), nil
+}
+
+// Indices returns the names of the indices, sorted
+func (s *store) Indices() []string {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	names := make([]string, 0, len(s.indices))
+	for name := range s.indices {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func (s *store) resolve(index string) string {
+	target, isAlias := s.aliases[index]
+	if isAlias {
+		return target
+	}
+
+	return index
+}
+
+func (s *store) getOrCreateIndex(index string) map[string][]byte {
+	documents, found := s.indices[index]
+	if !found {
+		documents = make(map[string][]byte)
+		s.indices[index] = documents
+	}
+
+	return documents
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (s *store) IsInterfaceNil() bool {
+	return s == nil
+}
+
+func decodeSource(source []byte) (objectsMap, error) {
+	decoded := make(objectsMap)
+	decoder := json.NewDecoder(bytes.NewReader(source))
+	decoder.UseNumber()
+	err := decoder.Decode(&decoded)
+	if err != nil {
+		return nil, err
+	}
+
+	return decoded, nil
+}
+
+func compact(source []byte) []byte {
+	buff := &bytes.Buffer{}
+	err := json.Compact(buff, source)
+	if err != nil {
+		log.Debug("memstore: document source is not a valid json, it is stored as received", "error", err.Error())
+		return source
+	}
+
+	return buff.Bytes()
+}
+
+// applyState will change the stored document with the provided function and will keep the result
+func applyState(documents map[string][]byte, id string, apply func(state *docstate.State) error) (*docstate.State, error) {
+	stored, found := documents[id]
+	if !found {
+		stored = nil
+	}
+
+	state := docstate.NewState(stored)
+	err := apply(state)
+	if err != nil {
+		return nil, err
+	}
+	if !state.Changed() {
+		return state, nil
+	}
+	if !state.Exists() {
+		delete(documents, id)
+		return state, nil
+	}
+
+	serialized, err := state.Serialize()
+	if err != nil {
+		return nil, err
+	}
+	documents[id] = compact(serialized)
+
+	return state, nil
+}
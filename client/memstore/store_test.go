@@ -0,0 +1,177 @@
+package memstore
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func applyBulk(t *testing.T, s *store, body string) []*BulkItem {
+	items, err := s.ApplyBulk([]byte(body), "")
+	require.Nil(t, err)
+
+	return items
+}
+
+func getSource(s *store, index string, id string) string {
+	source, found := s.GetDocument(index, id)
+	if !found {
+		return ""
+	}
+
+	return string(source)
+}
+
+func TestParseBulk(t *testing.T) {
+	t.Parallel()
+
+	actions, err := ParseBulk([]byte(`{ "index" : { "_index":"blocks", "_id" : "b1" } }
+{"nonce":1}
+{ "delete" : { "_id" : "b2" } }
+`), "miniblocks")
+	require.Nil(t, err)
+	require.Equal(t, []*BulkAction{
+		{Operation: "index", Index: "blocks", ID: "b1", Source: []byte(`{"nonce":1}`)},
+		{Operation: "delete", Index: "miniblocks", ID: "b2"},
+	}, actions)
+
+	_, err = ParseBulk([]byte(`{}`), "")
+	require.True(t, errors.Is(err, ErrInvalidBulkAction))
+
+	_, err = ParseBulk([]byte(`{ "index" : { "_index":"blocks", "_id" : "b1" } }`), "")
+	require.True(t, errors.Is(err, ErrInvalidBulkAction))
+}
+
+func TestStore_ApplyBulk(t *testing.T) {
+	t.Parallel()
+
+	s := NewStore()
+	s.PutAlias("accounts", "accounts-000001")
+
+	items := applyBulk(t, s, `{ "index" : { "_index":"accounts", "_id" : "a1" } }
+{"balance":"1", "nonce": 1}
+{ "create" : { "_index":"accounts", "_id" : "a1" } }
+{"balance":"2"}
+{ "delete" : { "_index":"accounts", "_id" : "a2" } }
+{ "update" : { "_index":"accounts", "_id" : "a1" } }
+{"doc":{"nonce":2}}
+{ "update" : { "_index":"accounts", "_id" : "a3" } }
+{"doc":{"nonce":2}}
+`)
+	require.Len(t, items, 5)
+	require.Equal(t, http.StatusCreated, items[0].Status)
+	require.Equal(t, "accounts-000001", items[0].Index)
+	require.Equal(t, http.StatusConflict, items[1].Status)
+	require.Equal(t, http.StatusNotFound, items[2].Status)
+	require.Equal(t, http.StatusOK, items[3].Status)
+	require.Equal(t, resultUpdated, items[3].Result)
+	require.Equal(t, http.StatusNotFound, items[4].Status)
+	require.Equal(t, "document_missing_exception", items[4].ErrorType)
+
+	require.Equal(t, `{"balance":"1","nonce":2}`, getSource(s, "accounts", "a1"))
+	require.Equal(t, `{"balance":"1","nonce":2}`, getSource(s, "accounts-000001", "a1"))
+	require.Equal(t, []string{"accounts-000001"}, s.Indices())
+}
+
+func TestStore_ApplyBulkScriptedUpserts(t *testing.T) {
+	t.Parallel()
+
+	s := NewStore()
+	items := applyBulk(t, s, `{ "update" : { "_index":"tags", "_id" : "art" } }
+{"scripted_upsert":true,"script":{"source":"...","lang":"painless","params":{"action":"upsert","body":{"tag":"art","count":1},"increments":{"count":1}}},"upsert":{}}
+{ "update" : { "_index":"tags", "_id" : "art" } }
+{"scripted_upsert":true,"script":{"source":"...","lang":"painless","params":{"action":"upsert","body":{"tag":"art","count":1},"increments":{"count":1},"addToSet":{"owners":["a","b"]}}},"upsert":{}}
+{ "update" : { "_index":"tags", "_id" : "missing" } }
+{"scripted_upsert":true,"script":{"source":"...","lang":"painless","params":{"action":"update","fields":{"count":5}}},"upsert":{}}
+{ "update" : { "_index":"tags", "_id" : "art" } }
+{"scripted_upsert":true,"script":{"source":"...","lang":"painless","params":{"action":"update","timestamp":10,"fields":{"timestamp":20}}},"upsert":{}}
+{ "update" : { "_index":"tags", "_id" : "art" } }
+{"scripted_upsert":true,"script":{"source":"...","lang":"painless","params":{"action":"delete","timestamp":15}},"upsert":{}}
+`)
+	require.Len(t, items, 5)
+	require.Equal(t, http.StatusCreated, items[0].Status)
+	require.Equal(t, http.StatusOK, items[1].Status)
+	require.Equal(t, resultNoop, items[2].Result)
+	require.Equal(t, resultUpdated, items[3].Result)
+	require.Equal(t, resultNoop, items[4].Result)
+	require.Equal(t, `{"count":2,"owners":["a","b"],"tag":"art","timestamp":20}`, getSource(s, "tags", "art"))
+
+	items = applyBulk(t, s, `{ "update" : { "_index":"tags", "_id" : "art" } }
+{"scripted_upsert":true,"script":{"source":"...","lang":"painless","params":{"action":"update","fields":{"count":null,"owners":null,"tag":null,"timestamp":null},"cleanup":["count","owners","tag","timestamp"]}},"upsert":{}}
+`)
+	require.Equal(t, resultDeleted, items[0].Result)
+	_, found := s.GetDocument("tags", "art")
+	require.False(t, found)
+}
+
+func TestStore_Search(t *testing.T) {
+	t.Parallel()
+
+	s := NewStore()
+	applyBulk(t, s, `{ "index" : { "_index":"blocks", "_id" : "h1" } }
+{"shardId":0,"nonce":1,"proposer":"alice","miniBlocksHashes":["m1","m2"]}
+{ "index" : { "_index":"blocks", "_id" : "h3" } }
+{"shardId":0,"nonce":3,"proposer":"bob"}
+{ "index" : { "_index":"blocks", "_id" : "h2" } }
+{"shardId":1,"nonce":2,"proposer":"alice","validators":[{"key":"k1"},{"key":"k2"}]}
+`)
+
+	searchIDs := func(query string) []string {
+		hits, err := s.Search("blocks", []byte(query))
+		require.Nil(t, err)
+
+		ids := make([]string, 0, len(hits))
+		for _, hit := range hits {
+			ids = append(ids, hit.ID)
+		}
+		return ids
+	}
+
+	require.Equal(t, []string{"h1", "h2", "h3"}, searchIDs(""))
+	require.Equal(t, []string{"h3", "h1"}, searchIDs(`{"query":{"bool":{"filter":[{"term":{"shardId":0}},{"range":{"nonce":{"gte":1,"lte":5}}}]}},"sort":[{"nonce":{"order":"desc"}}]}`))
+	require.Equal(t, []string{"h1", "h2"}, searchIDs(`{"query":{"bool":{"must":[{"match":{"proposer":{"query":"alice","operator":"AND"}}}]}}}`))
+	require.Equal(t, []string{"h2", "h3"}, searchIDs(`{"query":{"bool":{"must_not":[{"exists":{"field":"miniBlocksHashes"}}]}}}`))
+	require.Equal(t, []string{"h1"}, searchIDs(`{"query":{"terms":{"miniBlocksHashes":["m2","m9"]}}}`))
+	require.Equal(t, []string{"h2"}, searchIDs(`{"query":{"match":{"validators.key":"k2"}}}`))
+	require.Equal(t, []string{"h1", "h3"}, searchIDs(`{"query":{"ids":{"values":["h3","h1"]}}}`))
+	require.Equal(t, []string{"h1", "h2"}, searchIDs(`{"query":{"bool":{"should":[{"term":{"nonce":1}},{"term":{"nonce":2}}]}}}`))
+
+	hits, err := s.Search("blocks", []byte(`{"query":{"term":{"shardId":1}},"docvalue_fields":["nonce"]}`))
+	require.Nil(t, err)
+	response, err := SearchResponse(hits, len(hits), false)
+	require.Nil(t, err)
+	require.Equal(t, `{"hits":{"hits":[{"_index":"blocks","_id":"h2","fields":{"nonce":[2]}}],"total":{"relation":"eq","value":1}}}`, string(response))
+
+	count, err := s.Count("blocks", []byte(`{"query":{"match":{"proposer":"alice"}}}`))
+	require.Nil(t, err)
+	require.Equal(t, uint64(2), count)
+
+	_, err = s.Search("blocks", []byte(`{"query":{"wildcard":{"proposer":"a*"}}}`))
+	require.True(t, errors.Is(err, ErrUnsupportedQuery))
+
+	_, err = s.Search("blocks", []byte(`not json`))
+	require.True(t, errors.Is(err, ErrInvalidQuery))
+}
+
+func TestStore_DeleteByQueryAndMultiGet(t *testing.T) {
+	t.Parallel()
+
+	s := NewStore()
+	applyBulk(t, s, `{ "index" : { "_index":"logs", "_id" : "l1" } }
+{"address":"a"}
+{ "index" : { "_index":"logs", "_id" : "l2" } }
+{"address":"b"}
+`)
+
+	numDeleted, err := s.DeleteByQuery("logs", []byte(`{"query":{"match":{"address":"a"}}}`))
+	require.Nil(t, err)
+	require.Equal(t, uint64(1), numDeleted)
+
+	response := s.MultiGet("logs", []string{"l1", "l2"}, true)
+	require.Equal(t, &MultiGetResponse{Docs: []*MultiGetDocument{
+		{Index: "logs", ID: "l1"},
+		{Index: "logs", ID: "l2", Found: true, Source: []byte(`{"address":"b"}`)},
+	}}, response)
+}
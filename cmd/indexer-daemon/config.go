@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	indexer "github.com/ME-MotherEarth/me-elastic-indexer"
+)
+
+// indexesWithAccountsData holds the indices filled from the MECT data of the accounts. The daemon does not have the
+// data tries of the accounts, so it cannot fill them
+var indexesWithAccountsData = []string{
+	indexer.AccountsMECTIndex,
+	indexer.AccountsMECTHistoryIndex,
+	indexer.CollectionsIndex,
+}
+
+// Config holds the configuration of the indexer daemon
+type Config struct {
+	Outport struct {
+		ListenAddress     string `toml:"ListenAddress"`
+		MaxCachedAccounts int    `toml:"MaxCachedAccounts"`
+	} `toml:"Outport"`
+	Elastic struct {
+		Url                       string   `toml:"Url"`
+		UserName                  string   `toml:"UserName"`
+		Password                  string   `toml:"Password"`
+		UseKibana                 bool     `toml:"UseKibana"`
+		BulkRequestMaxSize        int      `toml:"BulkRequestMaxSize"`
+		NumConcurrentBulkRequests int      `toml:"NumConcurrentBulkRequests"`
+		EnabledIndexes            []string `toml:"EnabledIndexes"`
+		FinalizedIndexes          []string `toml:"FinalizedIndexes"`
+		DryRunPath                string   `toml:"DryRunPath"`
+		IndexPrefix               string   `toml:"IndexPrefix"`
+		AllowMappingsConflicts    bool     `toml:"AllowMappingsConflicts"`
+		CheckMigrations           bool     `toml:"CheckMigrations"`
+		RolloverMaxAge            string   `toml:"RolloverMaxAge"`
+		RolloverMaxSize           string   `toml:"RolloverMaxSize"`
+		RolloverMaxDocs           uint64   `toml:"RolloverMaxDocs"`
+	} `toml:"Elastic"`
+	Postgres struct {
+		DataSourceName string `toml:"DataSourceName"`
+	} `toml:"Postgres"`
+	Indexer struct {
+		IndexerCacheSize    int    `toml:"IndexerCacheSize"`
+		PersistentQueuePath string `toml:"PersistentQueuePath"`
+		DeadLettersPath     string `toml:"DeadLettersPath"`
+		MaxWorkItemAttempts int    `toml:"MaxWorkItemAttempts"`
+		MetricsAddress      string `toml:"MetricsAddress"`
+	} `toml:"Indexer"`
+	ContractABIs []struct {
+		FilePath   string   `toml:"FilePath"`
+		Addresses  []string `toml:"Addresses"`
+		CodeHashes []string `toml:"CodeHashes"`
+	} `toml:"ContractABI"`
+	Archive struct {
+		RecordPath     string `toml:"RecordPath"`
+		MaxSegmentSize int64  `toml:"MaxSegmentSize"`
+	} `toml:"Archive"`
+	Chain struct {
+		NumberOfShards        uint32  `toml:"NumberOfShards"`
+		SelfShardID           uint32  `toml:"SelfShardID"`
+		Denomination          int     `toml:"Denomination"`
+		Marshalizer           string  `toml:"Marshalizer"`
+		AddressPubkeyLength   int     `toml:"AddressPubkeyLength"`
+		ValidatorPubkeyLength int     `toml:"ValidatorPubkeyLength"`
+		MinGasLimit           uint64  `toml:"MinGasLimit"`
+		GasPerDataByte        uint64  `toml:"GasPerDataByte"`
+		GasPriceModifier      float64 `toml:"GasPriceModifier"`
+	} `toml:"Chain"`
+}
+
+// checkEnabledIndexes returns an error if an index that cannot be filled by the daemon is enabled
+func checkEnabledIndexes(enabledIndexes []string) error {
+	notSupported := make([]string, 0)
+	for _, index := range enabledIndexes {
+		for _, indexWithAccountsData := range indexesWithAccountsData {
+			if index == indexWithAccountsData {
+				notSupported = append(notSupported, index)
+			}
+		}
+	}
+	if len(notSupported) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("the %s indices cannot be enabled in the indexer daemon, as the node does not send the MECT "+
+		"data of the accounts. Remove them from EnabledIndexes or run the indexer inside the node",
+		strings.Join(notSupported, ", "))
+}
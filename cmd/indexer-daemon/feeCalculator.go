@@ -0,0 +1,86 @@
+package main
+
+import (
+	"math/big"
+
+	"github.com/ME-MotherEarth/me-core/core"
+	coreData "github.com/ME-MotherEarth/me-core/data"
+)
+
+// feeCalculator computes the transaction fees from the economics configuration of the chain, in the same way the
+// node does
+type feeCalculator struct {
+	minGasLimit      uint64
+	gasPerDataByte   uint64
+	gasPriceModifier float64
+}
+
+func newFeeCalculator(minGasLimit uint64, gasPerDataByte uint64, gasPriceModifier float64) *feeCalculator {
+	return &feeCalculator{
+		minGasLimit:      minGasLimit,
+		gasPerDataByte:   gasPerDataByte,
+		gasPriceModifier: gasPriceModifier,
+	}
+}
+
+// ComputeGasLimit returns the gas needed to move the balance and to store the data of the transaction
+func (fc *feeCalculator) ComputeGasLimit(tx coreData.TransactionWithFeeHandler) uint64 {
+	return fc.minGasLimit + uint64(len(tx.GetData()))*fc.gasPerDataByte
+}
+
+// ComputeGasUsedAndFeeBasedOnRefundValue returns the gas used and the fee of a transaction that received a refund
+func (fc *feeCalculator) ComputeGasUsedAndFeeBasedOnRefundValue(tx coreData.TransactionWithFeeHandler, refundValue *big.Int) (uint64, *big.Int) {
+	txFee := fc.computeTxFee(tx)
+	if refundValue.Sign() == 0 {
+		return tx.GetGasLimit(), txFee
+	}
+
+	txFee = big.NewInt(0).Sub(txFee, refundValue)
+
+	moveBalanceGasUnits := fc.ComputeGasLimit(tx)
+	moveBalanceFee := core.SafeMul(tx.GetGasPrice(), moveBalanceGasUnits)
+
+	gasPriceForProcessing := fc.gasPriceForProcessing(tx)
+	if gasPriceForProcessing == 0 {
+		return moveBalanceGasUnits, txFee
+	}
+
+	scOpFee := big.NewInt(0).Sub(txFee, moveBalanceFee)
+	scOpGasUnits := big.NewInt(0).Div(scOpFee, big.NewInt(0).SetUint64(gasPriceForProcessing))
+
+	return moveBalanceGasUnits + scOpGasUnits.Uint64(), txFee
+}
+
+// ComputeTxFeeBasedOnGasUsed returns the fee of a transaction that used the provided gas
+func (fc *feeCalculator) ComputeTxFeeBasedOnGasUsed(tx coreData.TransactionWithFeeHandler, gasUsed uint64) *big.Int {
+	moveBalanceGasLimit := fc.ComputeGasLimit(tx)
+	moveBalanceFee := core.SafeMul(tx.GetGasPrice(), moveBalanceGasLimit)
+	if gasUsed <= moveBalanceGasLimit {
+		return moveBalanceFee
+	}
+
+	processingFee := core.SafeMul(fc.gasPriceForProcessing(tx), gasUsed-moveBalanceGasLimit)
+
+	return big.NewInt(0).Add(moveBalanceFee, processingFee)
+}
+
+func (fc *feeCalculator) computeTxFee(tx coreData.TransactionWithFeeHandler) *big.Int {
+	moveBalanceGasLimit := fc.ComputeGasLimit(tx)
+	moveBalanceFee := core.SafeMul(tx.GetGasPrice(), moveBalanceGasLimit)
+	if tx.GetGasLimit() <= moveBalanceGasLimit {
+		return moveBalanceFee
+	}
+
+	processingFee := core.SafeMul(fc.gasPriceForProcessing(tx), tx.GetGasLimit()-moveBalanceGasLimit)
+
+	return moveBalanceFee.Add(moveBalanceFee, processingFee)
+}
+
+func (fc *feeCalculator) gasPriceForProcessing(tx coreData.TransactionWithFeeHandler) uint64 {
+	return uint64(float64(tx.GetGasPrice()) * fc.gasPriceModifier)
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (fc *feeCalculator) IsInterfaceNil() bool {
+	return fc == nil
+}
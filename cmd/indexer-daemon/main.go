@@ -0,0 +1,337 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/ME-MotherEarth/me-core/core"
+	"github.com/ME-MotherEarth/me-core/core/pubkeyConverter"
+	"github.com/ME-MotherEarth/me-core/hashing/blake2b"
+	"github.com/ME-MotherEarth/me-core/marshal/factory"
+	indexer "github.com/ME-MotherEarth/me-elastic-indexer"
+	"github.com/ME-MotherEarth/me-elastic-indexer/archive"
+	indexerFactory "github.com/ME-MotherEarth/me-elastic-indexer/factory"
+	"github.com/ME-MotherEarth/me-elastic-indexer/metrics"
+	"github.com/ME-MotherEarth/me-elastic-indexer/outport"
+	"github.com/ME-MotherEarth/me-elastic-indexer/payload"
+	"github.com/ME-MotherEarth/me-elastic-indexer/process/abi"
+	"github.com/ME-MotherEarth/me-elastic-indexer/process/sink/elastic"
+	logger "github.com/ME-MotherEarth/me-logger"
+	// registers the PostgreSQL driver used by the documents sink that writes in a relational database
+	_ "github.com/lib/pq"
+)
+
+const (
+	metricsPath        = "/metrics"
+	shardIDsSeparator  = ","
+	metachainShardName = "metachain"
+)
+
+var (
+	log = logger.GetOrCreate("indexer-daemon")
+
+	configFile    = flag.String("config", "./config.toml", "The path to the configuration file of the indexer daemon")
+	logLevel      = flag.String("log-level", "*:INFO", "The logger level pattern")
+	replayArchive = flag.String("replay-archive", "", "If set, the archive placed in this directory is indexed, "+
+		"instead of listening for the node")
+	fromNonce = flag.Uint64("from-nonce", 0, "The first block nonce replayed from the archive")
+	toNonce   = flag.Uint64("to-nonce", 0, "The last block nonce replayed from the archive, 0 meaning no limit")
+	shards    = flag.String("shards", "", "The comma separated shards replayed from the archive, all if empty. "+
+		"The metachain can be given as \"metachain\"")
+)
+
+// The indexer daemon runs the indexer outside the node process. The node sends the indexer calls over the outport
+// protocol, using the client indexer from the outport package, and every call is acknowledged once it was handed to
+// the indexer. The daemon can also index again, in a fresh cluster, the calls recorded in an archive
+func main() {
+	flag.Parse()
+
+	err := run(*configFile, *logLevel)
+	if err != nil {
+		fmt.Println("indexer daemon stopped with error:", err.Error())
+		os.Exit(1)
+	}
+}
+
+type components struct {
+	cfg           *Config
+	codec         archive.PayloadCodec
+	accountsCache outport.AccountsCacheHandler
+	dataIndexer   indexer.Indexer
+}
+
+func run(configFile string, logLevel string) error {
+	err := logger.SetLogLevel(logLevel)
+	if err != nil {
+		return err
+	}
+
+	cfg := &Config{}
+	err = core.LoadTomlFile(cfg, configFile)
+	if err != nil {
+		return err
+	}
+
+	c, err := createComponents(cfg)
+	if err != nil {
+		return err
+	}
+
+	if *replayArchive != "" {
+		return replay(c, *replayArchive)
+	}
+
+	return listen(c)
+}
+
+func createComponents(cfg *Config) (*components, error) {
+	err := checkEnabledIndexes(cfg.Elastic.EnabledIndexes)
+	if err != nil {
+		return nil, err
+	}
+
+	marshalizer, err := factory.NewMarshalizer(cfg.Chain.Marshalizer)
+	if err != nil {
+		return nil, err
+	}
+
+	addressPubkeyConverter, err := pubkeyConverter.NewBech32PubkeyConverter(cfg.Chain.AddressPubkeyLength, log)
+	if err != nil {
+		return nil, err
+	}
+
+	validatorPubkeyConverter, err := pubkeyConverter.NewHexPubkeyConverter(cfg.Chain.ValidatorPubkeyLength)
+	if err != nil {
+		return nil, err
+	}
+
+	accountsCache, err := outport.NewAccountsCache(outport.ArgsAccountsCache{
+		AddressPubkeyConverter: addressPubkeyConverter,
+		MaxAccounts:            cfg.Outport.MaxCachedAccounts,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	metricsHandler, err := startMetricsServer(cfg.Indexer.MetricsAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	shardCoordinator := newMultiShardCoordinator(cfg.Chain.NumberOfShards, cfg.Chain.SelfShardID)
+	dataIndexer, err := indexerFactory.NewIndexer(&indexerFactory.ArgsIndexerFactory{
+		Enabled:                   true,
+		UseKibana:                 cfg.Elastic.UseKibana,
+		IndexerCacheSize:          cfg.Indexer.IndexerCacheSize,
+		Denomination:              cfg.Chain.Denomination,
+		BulkRequestMaxSize:        cfg.Elastic.BulkRequestMaxSize,
+		NumConcurrentBulkRequests: cfg.Elastic.NumConcurrentBulkRequests,
+		MaxWorkItemAttempts:       cfg.Indexer.MaxWorkItemAttempts,
+		Url:                       cfg.Elastic.Url,
+		UserName:                  cfg.Elastic.UserName,
+		Password:                  cfg.Elastic.Password,
+		PersistentQueuePath:       cfg.Indexer.PersistentQueuePath,
+		DeadLettersPath:           cfg.Indexer.DeadLettersPath,
+		DryRunPath:                cfg.Elastic.DryRunPath,
+		IndexPrefix:               cfg.Elastic.IndexPrefix,
+		AllowMappingsConflicts:    cfg.Elastic.AllowMappingsConflicts,
+		CheckMigrations:           cfg.Elastic.CheckMigrations,
+		EnabledIndexes:            cfg.Elastic.EnabledIndexes,
+		FinalizedIndexes:          cfg.Elastic.FinalizedIndexes,
+		ShardCoordinator:          shardCoordinator,
+		Marshalizer:               marshalizer,
+		Hasher:                    blake2b.NewBlake2b(),
+		AddressPubkeyConverter:    addressPubkeyConverter,
+		ValidatorPubkeyConverter:  validatorPubkeyConverter,
+		AccountsDB:                accountsCache,
+		TransactionFeeCalculator:  newFeeCalculator(cfg.Chain.MinGasLimit, cfg.Chain.GasPerDataByte, cfg.Chain.GasPriceModifier),
+		MetricsHandler:            metricsHandler,
+		ContractABIs:              createContractABIs(cfg),
+		PostgresDataSourceName:    cfg.Postgres.DataSourceName,
+		Rollover: elastic.RolloverConditions{
+			MaxAge:  cfg.Elastic.RolloverMaxAge,
+			MaxSize: cfg.Elastic.RolloverMaxSize,
+			MaxDocs: cfg.Elastic.RolloverMaxDocs,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	codec, err := payload.NewCodec(marshalizer)
+	if err != nil {
+		return nil, err
+	}
+
+	return &components{
+		cfg:           cfg,
+		codec:         codec,
+		accountsCache: accountsCache,
+		dataIndexer:   dataIndexer,
+	}, nil
+}
+
+func createContractABIs(cfg *Config) []abi.ContractConfig {
+	contracts := make([]abi.ContractConfig, 0, len(cfg.ContractABIs))
+	for _, contract := range cfg.ContractABIs {
+		contracts = append(contracts, abi.ContractConfig{
+			FilePath:   contract.FilePath,
+			Addresses:  contract.Addresses,
+			CodeHashes: contract.CodeHashes,
+		})
+	}
+
+	return contracts
+}
+
+// listen will serve the node until the daemon is stopped. If an archive path is configured, the received calls are
+// also recorded
+func listen(c *components) error {
+	calls := c.dataIndexer
+	if c.cfg.Archive.RecordPath != "" {
+		archiveWriter, err := archive.NewWriter(archive.ArgsWriter{
+			Directory:      c.cfg.Archive.RecordPath,
+			MaxSegmentSize: c.cfg.Archive.MaxSegmentSize,
+		})
+		if err != nil {
+			return err
+		}
+
+		calls, err = archive.NewRecorderIndexer(archive.ArgsRecorderIndexer{
+			Indexer:          c.dataIndexer,
+			Writer:           archiveWriter,
+			Codec:            c.codec,
+			ShardCoordinator: newMultiShardCoordinator(c.cfg.Chain.NumberOfShards, c.cfg.Chain.SelfShardID),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	server, err := outport.NewServer(outport.ArgsServer{
+		Address:       c.cfg.Outport.ListenAddress,
+		Codec:         c.codec,
+		Indexer:       calls,
+		AccountsCache: c.accountsCache,
+	})
+	if err != nil {
+		return err
+	}
+
+	err = server.Start()
+	if err != nil {
+		return err
+	}
+
+	waitForSignal()
+
+	log.Info("indexer daemon is closing...")
+	errServer := server.Close()
+	errIndexer := calls.Close()
+	if errServer != nil {
+		return errServer
+	}
+
+	return errIndexer
+}
+
+// replay will index the selected calls of the archive. The indexer is closed at the end, so all the replayed calls
+// are saved before the daemon stops
+func replay(c *components, directory string) error {
+	shardIDs, err := parseShardIDs(*shards)
+	if err != nil {
+		return err
+	}
+
+	log.Info("replaying archive", "directory", directory, "from nonce", *fromNonce, "to nonce", *toNonce,
+		"shards", *shards)
+
+	report, errReplay := archive.Replay(archive.ArgsReplay{
+		Directory:     directory,
+		Codec:         c.codec,
+		Indexer:       c.dataIndexer,
+		AccountsCache: c.accountsCache,
+		Filter: archive.Filter{
+			FromNonce: *fromNonce,
+			ToNonce:   *toNonce,
+			ShardIDs:  shardIDs,
+		},
+	})
+	errIndexer := c.dataIndexer.Close()
+	if errReplay != nil {
+		return errReplay
+	}
+	if errIndexer != nil {
+		return errIndexer
+	}
+
+	log.Info("archive was replayed", "num calls", report.NumReplayed)
+	for shardID, missingNonces := range report.MissingNonces {
+		for _, missing := range missingNonces {
+			log.Warn("the archive has no block for these nonces, they have to be indexed from another source",
+				"shard", shardID, "from nonce", missing.From, "to nonce", missing.To)
+		}
+	}
+	for shardID, numLostCalls := range report.NumLostCalls {
+		log.Warn("the archive misses calls that could not be recorded", "shard", shardID, "num calls", numLostCalls)
+	}
+
+	return nil
+}
+
+func parseShardIDs(value string) ([]uint32, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	shardIDs := make([]uint32, 0)
+	for _, field := range strings.Split(value, shardIDsSeparator) {
+		field = strings.TrimSpace(field)
+		if field == metachainShardName {
+			shardIDs = append(shardIDs, core.MetachainShardId)
+			continue
+		}
+
+		shardID, err := strconv.ParseUint(field, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("%w while parsing the shard %s", err, field)
+		}
+		shardIDs = append(shardIDs, uint32(shardID))
+	}
+
+	return shardIDs, nil
+}
+
+// startMetricsServer will expose the indexer metrics if an address is configured. A nil handler is returned
+// otherwise, so the factory uses the disabled metrics
+func startMetricsServer(address string) (indexer.MetricsHandler, error) {
+	if address == "" {
+		return nil, nil
+	}
+
+	indexerMetrics, err := metrics.NewIndexerMetrics()
+	if err != nil {
+		return nil, err
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(metricsPath, indexerMetrics)
+	go func() {
+		errServe := http.ListenAndServe(address, mux)
+		log.Error("metrics server stopped", "error", errServe)
+	}()
+
+	return indexerMetrics, nil
+}
+
+func waitForSignal() {
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+	<-sigs
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"math"
+
+	"github.com/ME-MotherEarth/me-core/core"
+)
+
+// multiShardCoordinator computes the shard of an address in the same way the node does
+type multiShardCoordinator struct {
+	maskHigh       uint32
+	maskLow        uint32
+	selfId         uint32
+	numberOfShards uint32
+}
+
+func newMultiShardCoordinator(numberOfShards uint32, selfId uint32) *multiShardCoordinator {
+	msc := &multiShardCoordinator{
+		selfId:         selfId,
+		numberOfShards: numberOfShards,
+	}
+	msc.maskHigh, msc.maskLow = msc.calculateMasks()
+
+	return msc
+}
+
+func (msc *multiShardCoordinator) calculateMasks() (uint32, uint32) {
+	n := math.Ceil(math.Log2(float64(msc.numberOfShards)))
+	return (1 << uint(n)) - 1, (1 << uint(n-1)) - 1
+}
+
+// ComputeId calculates the shard for a given address
+func (msc *multiShardCoordinator) ComputeId(address []byte) uint32 {
+	var bytesNeed int
+	if msc.numberOfShards <= 256 {
+		bytesNeed = 1
+	} else if msc.numberOfShards <= 65536 {
+		bytesNeed = 2
+	} else if msc.numberOfShards <= 16777216 {
+		bytesNeed = 3
+	} else {
+		bytesNeed = 4
+	}
+
+	startingIndex := 0
+	if len(address) > bytesNeed {
+		startingIndex = len(address) - bytesNeed
+	}
+
+	buffNeeded := address[startingIndex:]
+	if core.IsSmartContractOnMetachain(buffNeeded, address) {
+		return core.MetachainShardId
+	}
+
+	addr := uint32(0)
+	for i := 0; i < len(buffNeeded); i++ {
+		addr = addr<<8 + uint32(buffNeeded[i])
+	}
+
+	shard := addr & msc.maskHigh
+	if shard > msc.numberOfShards-1 {
+		shard = addr & msc.maskLow
+	}
+
+	return shard
+}
+
+// NumberOfShards returns the number of shards
+func (msc *multiShardCoordinator) NumberOfShards() uint32 {
+	return msc.numberOfShards
+}
+
+// SelfId returns the shard of the node that feeds the indexer
+func (msc *multiShardCoordinator) SelfId() uint32 {
+	return msc.selfId
+}
+
+// SameShard returns true if the addresses are in the same shard
+func (msc *multiShardCoordinator) SameShard(firstAddress, secondAddress []byte) bool {
+	if len(firstAddress) == 0 || len(secondAddress) == 0 {
+		return true
+	}
+
+	return msc.ComputeId(firstAddress) == msc.ComputeId(secondAddress)
+}
+
+// CommunicationIdentifier returns the identifier between the current shard and the destination shard
+func (msc *multiShardCoordinator) CommunicationIdentifier(destShardID uint32) string {
+	return core.CommunicationIdentifierBetweenShards(msc.selfId, destShardID)
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (msc *multiShardCoordinator) IsInterfaceNil() bool {
+	return msc == nil
+}
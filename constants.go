@@ -1,8 +1,14 @@
 package indexer
 
 const (
-	// IndexSuffix is the suffix for the Elasticsearch indexes
+	// IndexSuffix is the suffix of the first backing index of every alias. The rolling indices get a new backing index,
+	// with the suffix incremented, each time they are rolled over
 	IndexSuffix = "000001"
+	// SchemaVersion is the version of the indices schema written by this indexer. It is increased with every migration
+	// registered in the index-modifier tool, so the indexer does not write in a cluster with pending migrations
+	SchemaVersion = 4
+	// IndexPrefixSeparator separates the index prefix, set when several networks share a cluster, from the index name
+	IndexPrefixSeparator = "-"
 	// BlockIndex is the Elasticsearch index for the blocks
 	BlockIndex = "blocks"
 	// MiniblocksIndex is the Elasticsearch index for the miniblocks
@@ -39,12 +45,22 @@ const (
 	TagsIndex = "tags"
 	// LogsIndex is the Elasticsearch index for logs
 	LogsIndex = "logs"
+	// EventsIndex is the Elasticsearch index for the events of the logs, with their topics decoded when possible
+	EventsIndex = "events"
+	// TransfersIndex is the Elasticsearch index for the movements of tokens, with a document for every token transferred
+	TransfersIndex = "transfers"
 	// DelegatorsIndex is the Elasticsearch index for delegators
 	DelegatorsIndex = "delegators"
 	// OperationsIndex is the Elasticsearch index for transactions and smart contract results
 	OperationsIndex = "operations"
 	// CollectionsIndex is the Elasticsearch index for collections
 	CollectionsIndex = "collections"
+	// JournalIndex is the Elasticsearch index for the previous state of the documents changed by each block
+	JournalIndex = "journal"
+	// CheckpointsIndex is the Elasticsearch index for the last indexed block of every shard
+	CheckpointsIndex = "checkpoints"
+	// MigrationsIndex is the Elasticsearch index for the schema version of the cluster and the state of its migrations
+	MigrationsIndex = "migrations"
 
 	// TransactionsPolicy is the Elasticsearch policy for the transactions
 	TransactionsPolicy = "transactions_policy"
@@ -70,4 +86,12 @@ const (
 	ScResultsPolicy = "scresults_policy"
 	// ReceiptsPolicy is the Elasticsearch policy for the receipts
 	ReceiptsPolicy = "receipts_policy"
+	// LogsPolicy is the Elasticsearch policy for the logs
+	LogsPolicy = "logs_policy"
+	// EventsPolicy is the Elasticsearch policy for the events
+	EventsPolicy = "events_policy"
+	// TransfersPolicy is the Elasticsearch policy for the transfers
+	TransfersPolicy = "transfers_policy"
+	// OperationsPolicy is the Elasticsearch policy for the operations
+	OperationsPolicy = "operations_policy"
 )
@@ -3,6 +3,7 @@ package converters
 import (
 	"math"
 	"math/big"
+	"strings"
 
 	"github.com/ME-MotherEarth/me-core/core"
 	indexer "github.com/ME-MotherEarth/me-elastic-indexer"
@@ -11,6 +12,8 @@ import (
 const (
 	numDecimalsInFloatBalance     = 10
 	numDecimalsInFloatBalanceMECT = 18
+	// sortableNumberLength is the number of digits of the largest unsigned 256 bits integer, so every balance fits
+	sortableNumberLength = 78
 )
 
 var zero = big.NewInt(0)
@@ -36,15 +39,29 @@ func NewBalanceConverter(denomination int) (*balanceConverter, error) {
 
 // ComputeBalanceAsFloat will compute balance as float
 func (bc *balanceConverter) ComputeBalanceAsFloat(balance *big.Int) float64 {
-	return bc.computeBalanceAsFloat(balance, bc.balancePrecision)
+	return computeBalanceAsFloat(balance, bc.dividerForDenomination, bc.balancePrecision)
 }
 
-// ComputeMECTBalanceAsFloat will compute MECT balance as float
+// ComputeMECTBalanceAsFloat will compute MECT balance as float, using the denomination of the network. It is used
+// for the tokens whose number of decimals is not known
 func (bc *balanceConverter) ComputeMECTBalanceAsFloat(balance *big.Int) float64 {
-	return bc.computeBalanceAsFloat(balance, bc.balancePrecisionMECT)
+	return computeBalanceAsFloat(balance, bc.dividerForDenomination, bc.balancePrecisionMECT)
 }
 
-func (bc *balanceConverter) computeBalanceAsFloat(balance *big.Int, balancePrecision float64) float64 {
+// ComputeMECTBalanceAsFloatWithDecimals will compute MECT balance as float, using the number of decimals of the token
+func (bc *balanceConverter) ComputeMECTBalanceAsFloatWithDecimals(balance *big.Int, numDecimals uint64) float64 {
+	precisionDecimals := numDecimals
+	if precisionDecimals > numDecimalsInFloatBalanceMECT {
+		precisionDecimals = numDecimalsInFloatBalanceMECT
+	}
+
+	divider := math.Pow(10, float64(numDecimals))
+	balancePrecision := math.Pow(10, float64(precisionDecimals))
+
+	return computeBalanceAsFloat(balance, divider, balancePrecision)
+}
+
+func computeBalanceAsFloat(balance *big.Int, divider float64, balancePrecision float64) float64 {
 	if balance == nil || balance.Cmp(zero) == 0 {
 		return 0
 	}
@@ -52,7 +69,7 @@ func (bc *balanceConverter) computeBalanceAsFloat(balance *big.Int, balancePreci
 	balanceBigFloat := big.NewFloat(0).SetInt(balance)
 	balanceFloat64, _ := balanceBigFloat.Float64()
 
-	bal := balanceFloat64 / bc.dividerForDenomination
+	bal := balanceFloat64 / divider
 
 	balanceFloatWithDecimals := math.Round(bal*balancePrecision) / balancePrecision
 
@@ -72,3 +89,18 @@ func BigIntToString(value *big.Int) string {
 
 	return value.String()
 }
+
+// BigIntToSortableString will convert a big.Int to its exact decimal representation, padded with zeros to a fixed
+// length, so the values sort and compare as keywords the same way they do as numbers. A negative value is converted
+// as zero, as the balances converted as float
+func BigIntToSortableString(value *big.Int) string {
+	digits := "0"
+	if value != nil && value.Sign() > 0 {
+		digits = value.String()
+	}
+	if len(digits) >= sortableNumberLength {
+		return digits
+	}
+
+	return strings.Repeat("0", sortableNumberLength-len(digits)) + digits
+}
@@ -2,6 +2,7 @@ package converters
 
 import (
 	"math/big"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -51,6 +52,52 @@ func TestComputeBalanceAsFloat(t *testing.T) {
 	}
 }
 
+func TestComputeMECTBalanceAsFloatWithDecimals(t *testing.T) {
+	t.Parallel()
+
+	ap, _ := NewBalanceConverter(18)
+	require.NotNil(t, ap)
+
+	tests := []struct {
+		input       *big.Int
+		numDecimals uint64
+		output      float64
+	}{
+		{
+			input:       big.NewInt(1234567),
+			numDecimals: 6,
+			output:      1.234567,
+		},
+		{
+			input:       big.NewInt(5),
+			numDecimals: 0,
+			output:      5,
+		},
+		{
+			input:       big.NewInt(1000000000000000000),
+			numDecimals: 18,
+			output:      1,
+		},
+		{
+			input:       big.NewInt(-7),
+			numDecimals: 2,
+			output:      0,
+		},
+		{
+			input:       nil,
+			numDecimals: 2,
+			output:      0,
+		},
+	}
+
+	for _, tt := range tests {
+		out := ap.ComputeMECTBalanceAsFloatWithDecimals(tt.input, tt.numDecimals)
+		assert.Equal(t, tt.output, out)
+	}
+
+	require.Equal(t, 0.000000000001234567, ap.ComputeMECTBalanceAsFloat(big.NewInt(1234567)))
+}
+
 func TestBigIntToString(t *testing.T) {
 	t.Parallel()
 
@@ -58,3 +105,20 @@ func TestBigIntToString(t *testing.T) {
 	require.Equal(t, "0", BigIntToString(big.NewInt(0)))
 	require.Equal(t, "1", BigIntToString(big.NewInt(1)))
 }
+
+func TestBigIntToSortableString(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, strings.Repeat("0", sortableNumberLength), BigIntToSortableString(nil))
+	require.Equal(t, strings.Repeat("0", sortableNumberLength), BigIntToSortableString(big.NewInt(-7)))
+	require.Equal(t, strings.Repeat("0", sortableNumberLength-3)+"112", BigIntToSortableString(big.NewInt(112)))
+
+	maxUint256 := big.NewInt(0).Sub(big.NewInt(0).Lsh(big.NewInt(1), 256), big.NewInt(1))
+	require.Equal(t, maxUint256.String(), BigIntToSortableString(maxUint256))
+
+	// the balances that differ only after the 15th significant digit are equal as float64, but not as sortable strings
+	whale, _ := big.NewInt(0).SetString("123456789012345678901234567890", 10)
+	largerWhale := big.NewInt(0).Add(whale, big.NewInt(1))
+	require.True(t, BigIntToSortableString(whale) < BigIntToSortableString(largerWhale))
+	require.True(t, BigIntToSortableString(big.NewInt(99)) < BigIntToSortableString(big.NewInt(100)))
+}
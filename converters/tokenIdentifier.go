@@ -4,8 +4,11 @@ import (
 	"encoding/hex"
 	"fmt"
 	"math/big"
+	"strings"
 )
 
+const numPartsTokenIdentifierWithNonce = 3
+
 // ComputeTokenIdentifier will compute the token identifier based on the token string and the nonce
 func ComputeTokenIdentifier(token string, nonce uint64) string {
 	if token == "" || nonce == 0 {
@@ -27,3 +30,19 @@ func EncodeNonceToHex(nonce uint64) string {
 
 	return hex.EncodeToString(nonceBigBytes)
 }
+
+// ExtractTokenAndNonce will split the provided token identifier in the token and the nonce. The identifier of a
+// fungible token has no nonce, so it is returned as the token with a zero nonce
+func ExtractTokenAndNonce(tokenIdentifier string) (string, uint64) {
+	parts := strings.Split(tokenIdentifier, "-")
+	if len(parts) != numPartsTokenIdentifierWithNonce {
+		return tokenIdentifier, 0
+	}
+
+	nonceBytes, err := hex.DecodeString(parts[2])
+	if err != nil {
+		return tokenIdentifier, 0
+	}
+
+	return parts[0] + "-" + parts[1], big.NewInt(0).SetBytes(nonceBytes).Uint64()
+}
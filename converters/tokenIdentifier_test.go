@@ -13,3 +13,19 @@ func TestComputeTokenIdentifier(t *testing.T) {
 	require.Equal(t, "", ComputeTokenIdentifier("token", 0))
 	require.Equal(t, "my-token-01", ComputeTokenIdentifier("my-token", 1))
 }
+
+func TestExtractTokenAndNonce(t *testing.T) {
+	t.Parallel()
+
+	token, nonce := ExtractTokenAndNonce("TKN-abcd")
+	require.Equal(t, "TKN-abcd", token)
+	require.Equal(t, uint64(0), nonce)
+
+	token, nonce = ExtractTokenAndNonce("NFT-abcd-0a0b")
+	require.Equal(t, "NFT-abcd", token)
+	require.Equal(t, uint64(2571), nonce)
+
+	token, nonce = ExtractTokenAndNonce("NFT-abcd-zz")
+	require.Equal(t, "NFT-abcd-zz", token)
+	require.Equal(t, uint64(0), nonce)
+}
@@ -2,7 +2,6 @@ package converters
 
 import (
 	"encoding/base64"
-	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -76,83 +75,50 @@ func whiteListedStorage(uris [][]byte) bool {
 }
 
 // PrepareNFTUpdateData will prepare nfts update data
-func PrepareNFTUpdateData(buffSlice *data.BufferSlice, updateNFTData []*data.NFTDataUpdate, accountMECT bool, index string) error {
+func PrepareNFTUpdateData(docs *data.DocumentsSlice, updateNFTData []*data.NFTDataUpdate, accountMECT bool, index string) {
 	for _, nftUpdate := range updateNFTData {
 		id := nftUpdate.Identifier
 		if accountMECT {
 			id = fmt.Sprintf("%s-%s", nftUpdate.Address, nftUpdate.Identifier)
 		}
 
-		metaData := []byte(fmt.Sprintf(`{"update":{ "_index":"%s","_id":"%s"}}%s`, index, id, "\n"))
-		base64Attr := base64.StdEncoding.EncodeToString(nftUpdate.NewAttributes)
-
-		newTags := ExtractTagsFromAttributes(nftUpdate.NewAttributes)
-		newMetadata := ExtractMetaDataFromAttributes(nftUpdate.NewAttributes)
-
-		marshalizedTags, errM := json.Marshal(newTags)
-		if errM != nil {
-			return errM
+		document := &data.Document{
+			Index:  index,
+			ID:     id,
+			Action: data.ActionUpdate,
 		}
 
-		codeToExecute := `
-			if (ctx._source.containsKey('data')) {
-				ctx._source.data.attributes = params.attributes;
-				if (!params.metadata.isEmpty() ) {
-					ctx._source.data.metadata = params.metadata
-				} else {
-					if (ctx._source.data.containsKey('metadata')) {
-						ctx._source.data.remove('metadata')
-					}
-				}
-				if (params.tags != null) {
-					ctx._source.data.tags = params.tags
-				} else {
-					if (ctx._source.data.containsKey('tags')) {
-						ctx._source.data.remove('tags')
-					}
-				}
-			}
-`
-		serializedData := []byte(fmt.Sprintf(`{"script": {"source": "%s","lang": "painless","params": {"attributes": "%s", "metadata": "%s", "tags": %s}}, "upsert": {}}`,
-			FormatPainlessSource(codeToExecute), base64Attr, newMetadata, marshalizedTags),
-		)
 		if len(nftUpdate.URIsToAdd) != 0 {
-			marshalizedURIS, err := json.Marshal(nftUpdate.URIsToAdd)
-			if err != nil {
-				return err
+			uris := make([]interface{}, 0, len(nftUpdate.URIsToAdd))
+			for _, uri := range nftUpdate.URIsToAdd {
+				uris = append(uris, uri)
 			}
 
-			codeToExecute = `
-				if (ctx._source.containsKey('data')) {
-					if (!ctx._source.data.containsKey('uris')) {
-						ctx._source.data.uris = params.uris;
-					} else {
-						int i;
-						for ( i = 0; i < params.uris.length; i++) {
-							boolean found = false;
-							int j;
-							for ( j = 0; j < ctx._source.data.uris.length; j++) {
-								if ( params.uris.get(i) == ctx._source.data.uris.get(j) ) {
-									found = true;
-									break
-								}
-							}
-							if ( !found ) {
-								ctx._source.data.uris.add(params.uris.get(i))
-							}
-						}
-					}
-					ctx.nonEmptyURIs = true;
-				}
-`
-			serializedData = []byte(fmt.Sprintf(`{"script": {"source": "%s","lang": "painless","params": {"uris": %s}},"upsert": {}}`, FormatPainlessSource(codeToExecute), marshalizedURIS))
+			document.AddToSet = map[string][]interface{}{
+				"data.uris": uris,
+			}
+			docs.Add(document)
+			continue
 		}
 
-		err := buffSlice.PutData(metaData, serializedData)
-		if err != nil {
-			return err
+		// a nil value removes the field from the stored document
+		var newMetadata interface{}
+		metadata := ExtractMetaDataFromAttributes(nftUpdate.NewAttributes)
+		if metadata != "" {
+			newMetadata = metadata
 		}
-	}
 
-	return nil
+		var newTags interface{}
+		tags := ExtractTagsFromAttributes(nftUpdate.NewAttributes)
+		if tags != nil {
+			newTags = tags
+		}
+
+		document.Fields = map[string]interface{}{
+			"data.attributes": base64.StdEncoding.EncodeToString(nftUpdate.NewAttributes),
+			"data.metadata":   newMetadata,
+			"data.tags":       newTags,
+		}
+		docs.Add(document)
+	}
 }
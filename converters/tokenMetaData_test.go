@@ -46,25 +46,55 @@ func TestPrepareTokenMetaData(t *testing.T) {
 func TestPrepareNFTUpdateData(t *testing.T) {
 	t.Parallel()
 
-	buffSlice := data.NewBufferSlice(data.DefaultMaxBulkSize)
+	docs := data.NewDocumentsSlice()
 
 	nftUpdateData := []*data.NFTDataUpdate{
 		{
 			Identifier:    "MYTKN-abcd-01",
 			NewAttributes: []byte("aaaa"),
 		},
+		{
+			Identifier:    "MYTKN-abcd-02",
+			NewAttributes: []byte("tags:test,free;metadata:meta"),
+		},
 		{
 			Identifier: "TOKEN-1234-1a",
 			URIsToAdd:  [][]byte{[]byte("uri1"), []byte("uri2")},
 		},
 	}
-	err := PrepareNFTUpdateData(buffSlice, nftUpdateData, false, "tokens")
-	require.Nil(t, err)
-	require.Equal(t, `{"update":{ "_index":"tokens","_id":"MYTKN-abcd-01"}}
-{"script": {"source": "if (ctx._source.containsKey('data')) {ctx._source.data.attributes = params.attributes;if (!params.metadata.isEmpty() ) {ctx._source.data.metadata = params.metadata} else {if (ctx._source.data.containsKey('metadata')) {ctx._source.data.remove('metadata')}}if (params.tags != null) {ctx._source.data.tags = params.tags} else {if (ctx._source.data.containsKey('tags')) {ctx._source.data.remove('tags')}}}","lang": "painless","params": {"attributes": "YWFhYQ==", "metadata": "", "tags": null}}, "upsert": {}}
-{"update":{ "_index":"tokens","_id":"TOKEN-1234-1a"}}
-{"script": {"source": "if (ctx._source.containsKey('data')) {if (!ctx._source.data.containsKey('uris')) {ctx._source.data.uris = params.uris;} else {int i;for ( i = 0; i < params.uris.length; i++) {boolean found = false;int j;for ( j = 0; j < ctx._source.data.uris.length; j++) {if ( params.uris.get(i) == ctx._source.data.uris.get(j) ) {found = true;break}}if ( !found ) {ctx._source.data.uris.add(params.uris.get(i))}}}ctx.nonEmptyURIs = true;}","lang": "painless","params": {"uris": ["dXJpMQ==","dXJpMg=="]}},"upsert": {}}
-`, buffSlice.Buffers()[0].String())
+	PrepareNFTUpdateData(docs, nftUpdateData, false, "tokens")
+
+	expectedDocs := []*data.Document{
+		{
+			Index:  "tokens",
+			ID:     "MYTKN-abcd-01",
+			Action: data.ActionUpdate,
+			Fields: map[string]interface{}{
+				"data.attributes": "YWFhYQ==",
+				"data.metadata":   nil,
+				"data.tags":       nil,
+			},
+		},
+		{
+			Index:  "tokens",
+			ID:     "MYTKN-abcd-02",
+			Action: data.ActionUpdate,
+			Fields: map[string]interface{}{
+				"data.attributes": "dGFnczp0ZXN0LGZyZWU7bWV0YWRhdGE6bWV0YQ==",
+				"data.metadata":   "meta",
+				"data.tags":       []string{"test", "free"},
+			},
+		},
+		{
+			Index:  "tokens",
+			ID:     "TOKEN-1234-1a",
+			Action: data.ActionUpdate,
+			AddToSet: map[string][]interface{}{
+				"data.uris": {[]byte("uri1"), []byte("uri2")},
+			},
+		},
+	}
+	require.Equal(t, expectedDocs, docs.Documents())
 }
 
 func TestWhiteListedStorage(t *testing.T) {
@@ -8,24 +8,26 @@ import (
 
 // AccountInfo holds (serializable) data about an account
 type AccountInfo struct {
-	Address                  string         `json:"address,omitempty"`
-	Nonce                    uint64         `json:"nonce,omitempty"`
-	Balance                  string         `json:"balance"`
-	BalanceNum               float64        `json:"balanceNum"`
-	TokenName                string         `json:"token,omitempty"`
-	TokenIdentifier          string         `json:"identifier,omitempty"`
-	TokenNonce               uint64         `json:"tokenNonce,omitempty"`
-	Properties               string         `json:"properties,omitempty"`
-	TotalBalanceWithStake    string         `json:"totalBalanceWithStake,omitempty"`
-	TotalBalanceWithStakeNum float64        `json:"totalBalanceWithStakeNum,omitempty"`
-	Data                     *TokenMetaData `json:"data,omitempty"`
-	Timestamp                time.Duration  `json:"timestamp,omitempty"`
-	Type                     string         `json:"type,omitempty"`
-	CurrentOwner             string         `json:"currentOwner,omitempty"`
-	ShardID                  uint32         `json:"shardID"`
-	IsSender                 bool           `json:"-"`
-	IsSmartContract          bool           `json:"-"`
-	IsNFTCreate              bool           `json:"-"`
+	Address                       string         `json:"address,omitempty"`
+	Nonce                         uint64         `json:"nonce,omitempty"`
+	Balance                       string         `json:"balance"`
+	BalanceNum                    float64        `json:"balanceNum"`
+	BalanceSortable               string         `json:"balanceSortable"`
+	TokenName                     string         `json:"token,omitempty"`
+	TokenIdentifier               string         `json:"identifier,omitempty"`
+	TokenNonce                    uint64         `json:"tokenNonce,omitempty"`
+	Properties                    string         `json:"properties,omitempty"`
+	TotalBalanceWithStake         string         `json:"totalBalanceWithStake,omitempty"`
+	TotalBalanceWithStakeNum      float64        `json:"totalBalanceWithStakeNum,omitempty"`
+	TotalBalanceWithStakeSortable string         `json:"totalBalanceWithStakeSortable,omitempty"`
+	Data                          *TokenMetaData `json:"data,omitempty"`
+	Timestamp                     time.Duration  `json:"timestamp,omitempty"`
+	Type                          string         `json:"type,omitempty"`
+	CurrentOwner                  string         `json:"currentOwner,omitempty"`
+	ShardID                       uint32         `json:"shardID"`
+	IsSender                      bool           `json:"-"`
+	IsSmartContract               bool           `json:"-"`
+	IsNFTCreate                   bool           `json:"-"`
 }
 
 // TokenMetaData holds data about a token metadata
@@ -47,6 +49,8 @@ type AccountBalanceHistory struct {
 	Address         string        `json:"address"`
 	Timestamp       time.Duration `json:"timestamp"`
 	Balance         string        `json:"balance"`
+	BalanceNum      float64       `json:"balanceNum"`
+	BalanceSortable string        `json:"balanceSortable"`
 	Token           string        `json:"token,omitempty"`
 	Identifier      string        `json:"identifier,omitempty"`
 	TokenNonce      uint64        `json:"tokenNonce,omitempty"`
@@ -37,6 +37,7 @@ type Block struct {
 	MaxGasLimit           uint64                 `json:"maxGasLimit"`
 	ScheduledData         *ScheduledData         `json:"scheduledData,omitempty"`
 	EpochStartShardsData  []*EpochStartShardData `json:"epochStartShardsData,omitempty"`
+	Finalized             bool                   `json:"finalized"`
 }
 
 // MiniBlocksDetails is a structure that hold information about mini-blocks execution details
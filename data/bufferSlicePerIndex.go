@@ -0,0 +1,37 @@
+package data
+
+// BufferSlicePerIndex holds a separate BufferSlice for every index, so the bulk requests of different indices can be
+// sent in parallel while the documents of the same index keep the order in which they were added
+type BufferSlicePerIndex struct {
+	bulkSizeThreshold int
+	buffSlices        map[string]*BufferSlice
+	indices           []string
+}
+
+// NewBufferSlicePerIndex will create a new instance of BufferSlicePerIndex
+func NewBufferSlicePerIndex(bulkSizeThreshold int) *BufferSlicePerIndex {
+	return &BufferSlicePerIndex{
+		bulkSizeThreshold: bulkSizeThreshold,
+		buffSlices:        make(map[string]*BufferSlice),
+		indices:           make([]string, 0),
+	}
+}
+
+// Get will return the buffer slice of the provided index, creating it if it does not exist
+func (bspi *BufferSlicePerIndex) Get(index string) *BufferSlice {
+	buffSlice, ok := bspi.buffSlices[index]
+	if ok {
+		return buffSlice
+	}
+
+	buffSlice = NewBufferSlice(bspi.bulkSizeThreshold)
+	bspi.buffSlices[index] = buffSlice
+	bspi.indices = append(bspi.indices, index)
+
+	return buffSlice
+}
+
+// Indices will return the indices that have a buffer slice, in the order they were requested for the first time
+func (bspi *BufferSlicePerIndex) Indices() []string {
+	return bspi.indices
+}
@@ -0,0 +1,24 @@
+package data
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBufferSlicePerIndex_Get(t *testing.T) {
+	t.Parallel()
+
+	bspi := NewBufferSlicePerIndex(0)
+	require.Empty(t, bspi.Indices())
+
+	txsBuff := bspi.Get("transactions")
+	require.Nil(t, txsBuff.PutData([]byte("meta1\n"), []byte("tx1")))
+	blocksBuff := bspi.Get("blocks")
+	require.Nil(t, blocksBuff.PutData([]byte("meta2\n"), []byte("block")))
+	require.Nil(t, bspi.Get("transactions").PutData([]byte("meta3\n"), []byte("tx2")))
+
+	require.Equal(t, []string{"transactions", "blocks"}, bspi.Indices())
+	require.Equal(t, "meta1\ntx1\nmeta3\ntx2\n", bspi.Get("transactions").Buffers()[0].String())
+	require.Equal(t, "meta2\nblock\n", bspi.Get("blocks").Buffers()[0].String())
+}
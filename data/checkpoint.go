@@ -0,0 +1,17 @@
+package data
+
+import "time"
+
+// Checkpoint is a structure containing the last block that was indexed for a shard
+type Checkpoint struct {
+	ShardID   uint32        `json:"shardId"`
+	Nonce     uint64        `json:"nonce"`
+	Hash      string        `json:"hash"`
+	Timestamp time.Duration `json:"timestamp"`
+}
+
+// NonceRange holds an inclusive range of block nonces
+type NonceRange struct {
+	From uint64 `json:"from"`
+	To   uint64 `json:"to"`
+}
@@ -0,0 +1,17 @@
+package data
+
+// DecodedData holds a smart contract call or event decoded with the ABI of the smart contract. The name is the one of
+// the endpoint called or of the event written
+type DecodedData struct {
+	ABI  string             `json:"abi"`
+	Name string             `json:"name"`
+	Args []*DecodedArgument `json:"args"`
+}
+
+// DecodedArgument holds an argument of a call, or a field of an event, with the name and the type from the ABI. The
+// arguments of a variadic parameter are held separately, with the same name
+type DecodedArgument struct {
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
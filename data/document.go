@@ -0,0 +1,101 @@
+package data
+
+import "sort"
+
+// DocumentAction defines what a storage backend has to do with a document
+type DocumentAction uint8
+
+const (
+	// ActionIndex will create the document or will replace it if it already exists
+	ActionIndex DocumentAction = iota + 1
+	// ActionCreate will create the document only if it does not exist
+	ActionCreate
+	// ActionUpsert will apply the changes on the existing document or will create it from Body if it does not exist
+	ActionUpsert
+	// ActionUpdate will apply the changes on the existing document and will do nothing if it does not exist
+	ActionUpdate
+	// ActionDelete will remove the document
+	ActionDelete
+)
+
+// Document is the backend-neutral representation of a write operation produced from the prepared data.
+// Keys of Fields, Increments, Append, AddToSet and RemoveFromSet can address nested objects using dots
+type Document struct {
+	Index  string
+	ID     string
+	Action DocumentAction
+
+	// Body holds the whole document. It is used by ActionIndex and ActionCreate and by ActionUpsert when the
+	// document does not exist
+	Body interface{}
+	// KeepFields holds the fields of the existing document that are not overwritten when ActionIndex replaces it
+	KeepFields []string
+	// Fields will be set on the existing document. A nil value removes the field
+	Fields map[string]interface{}
+	// Increments will be added to the numeric fields of the existing document
+	Increments map[string]int64
+	// Append holds values that will be appended to the array fields of the existing document
+	Append map[string][]interface{}
+	// AddToSet holds values that will be appended to the array fields of the existing document only if missing
+	AddToSet map[string][]interface{}
+	// RemoveFromSet holds values that will be removed from the array fields of the existing document
+	RemoveFromSet map[string][]interface{}
+
+	// Timestamp, when not zero, makes ActionIndex, ActionUpsert, ActionUpdate and ActionDelete apply only if the stored
+	// document does not have a newer "timestamp" field
+	Timestamp uint64
+	// Nonce, when not zero, makes ActionIndex, ActionUpsert, ActionUpdate and ActionDelete apply only if the stored
+	// document does not have a greater "nonce" field
+	Nonce uint64
+	// DeleteIfEmpty will remove the empty objects and arrays left after the changes and the document itself if no field
+	// is left
+	DeleteIfEmpty bool
+}
+
+// ChangedPaths returns the sorted paths of Fields and RemoveFromSet, which are the paths that can be left empty by the
+// changes of the document
+func (d *Document) ChangedPaths() []string {
+	paths := make([]string, 0, len(d.Fields)+len(d.RemoveFromSet))
+	for path := range d.Fields {
+		paths = append(paths, path)
+	}
+	for path := range d.RemoveFromSet {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	return paths
+}
+
+// AtMost can be used as the value of a matching field, to match the documents whose numeric field is lower than or
+// equal to Value
+type AtMost struct {
+	Value uint64
+}
+
+// DocumentsSlice holds the documents that have to be written, in the order they were added
+type DocumentsSlice struct {
+	documents []*Document
+}
+
+// NewDocumentsSlice will create a new instance of DocumentsSlice
+func NewDocumentsSlice() *DocumentsSlice {
+	return &DocumentsSlice{
+		documents: make([]*Document, 0),
+	}
+}
+
+// Add will append the provided documents
+func (ds *DocumentsSlice) Add(documents ...*Document) {
+	ds.documents = append(ds.documents, documents...)
+}
+
+// Documents will return all the added documents
+func (ds *DocumentsSlice) Documents() []*Document {
+	return ds.documents
+}
+
+// Len will return the number of added documents
+func (ds *DocumentsSlice) Len() int {
+	return len(ds.documents)
+}
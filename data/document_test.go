@@ -0,0 +1,20 @@
+package data
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDocument_ChangedPaths(t *testing.T) {
+	t.Parallel()
+
+	require.Empty(t, (&Document{}).ChangedPaths())
+
+	document := &Document{
+		Fields:        map[string]interface{}{"b.c": 1, "a": nil},
+		Increments:    map[string]int64{"counter": 1},
+		RemoveFromSet: map[string][]interface{}{"roles": {"x"}},
+	}
+	require.Equal(t, []string{"a", "b.c", "roles"}, document.ChangedPaths())
+}
@@ -2,7 +2,7 @@ package data
 
 // CountTags defines what a TagCount handler should be able to do
 type CountTags interface {
-	Serialize(buffSlice *BufferSlice, index string) error
+	Serialize(docs *DocumentsSlice, index string) error
 	ParseTags(attributes []string)
 	GetTags() []string
 	Len() int
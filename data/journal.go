@@ -0,0 +1,22 @@
+package data
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// BlockJournal is a structure containing the state that the documents changed by a block had before the block was
+// indexed, so the changes can be reverted
+type BlockJournal struct {
+	Nonce     uint64          `json:"nonce"`
+	ShardID   uint32          `json:"shardId"`
+	Timestamp time.Duration   `json:"timestamp"`
+	Entries   []*JournalEntry `json:"entries"`
+}
+
+// JournalEntry holds the previous state of one document. An empty Previous means that the document did not exist
+type JournalEntry struct {
+	Index    string          `json:"index"`
+	ID       string          `json:"id"`
+	Previous json.RawMessage `json:"previous,omitempty"`
+}
@@ -17,11 +17,35 @@ type Logs struct {
 
 // Event holds all the fields needed for an event structure
 type Event struct {
-	Address    string   `json:"address"`
-	Identifier string   `json:"identifier"`
-	Topics     [][]byte `json:"topics"`
-	Data       []byte   `json:"data"`
-	Order      int      `json:"order"`
+	Address    string       `json:"address"`
+	Identifier string       `json:"identifier"`
+	Topics     [][]byte     `json:"topics"`
+	Data       []byte       `json:"data"`
+	Order      int          `json:"order"`
+	Decoded    *DecodedData `json:"decoded,omitempty"`
+}
+
+// LogEvent holds an event of a log, indexed on its own so the events can be searched by their decoded topics. The
+// topics and the data are hex encoded, while the token, nonce, value and receiver are set only for the events whose
+// identifier is known
+type LogEvent struct {
+	ID              string        `json:"-"`
+	TxHash          string        `json:"txHash"`
+	OriginalTxHash  string        `json:"originalTxHash,omitempty"`
+	LogAddress      string        `json:"logAddress"`
+	Address         string        `json:"address"`
+	Identifier      string        `json:"identifier"`
+	Topics          []string      `json:"topics"`
+	Data            string        `json:"data,omitempty"`
+	Order           int           `json:"order"`
+	ShardID         uint32        `json:"shardID"`
+	Timestamp       time.Duration `json:"timestamp,omitempty"`
+	Token           string        `json:"token,omitempty"`
+	TokenIdentifier string        `json:"tokenIdentifier,omitempty"`
+	Nonce           uint64        `json:"nonce,omitempty"`
+	Value           string        `json:"value,omitempty"`
+	Receiver        string        `json:"receiver,omitempty"`
+	Decoded         *DecodedData  `json:"decoded,omitempty"`
 }
 
 // PreparedLogsResults is the DTO that holds all the results after processing
@@ -33,4 +57,6 @@ type PreparedLogsResults struct {
 	TokensInfo              []*TokenInfo
 	NFTsDataUpdates         []*NFTDataUpdate
 	TokenRolesAndProperties *tokeninfo.TokenRolesAndProperties
+	CustomDocuments         []*Document
+	Transfers               []*Transfer
 }
@@ -0,0 +1,10 @@
+package data
+
+import "time"
+
+// SchemaState is a structure containing the version of the indices schema of a cluster, increased by every migration
+// applied with the index-modifier tool
+type SchemaState struct {
+	Version   uint32        `json:"version"`
+	Timestamp time.Duration `json:"timestamp"`
+}
@@ -0,0 +1,59 @@
+package data
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// RejectedDocument holds a document that the database refused to write, together with the reason and the bulk lines
+// of its action, so it can be written by hand
+type RejectedDocument struct {
+	Index     string `json:"index"`
+	ID        string `json:"id"`
+	Status    int    `json:"status"`
+	ErrorType string `json:"errorType"`
+	Reason    string `json:"reason"`
+	Action    string `json:"action,omitempty"`
+}
+
+// RejectedDocumentsError signals that the database refused to write some documents, while all the others were
+// written. Sending them again does not help, so the caller has to keep them aside and go on
+type RejectedDocumentsError struct {
+	Documents []*RejectedDocument
+}
+
+// Error returns the index, id and reason of every rejected document
+func (rde *RejectedDocumentsError) Error() string {
+	descriptions := make([]string, 0, len(rde.Documents))
+	for _, document := range rde.Documents {
+		descriptions = append(descriptions, fmt.Sprintf("index: %s, id: %s, status: %d, type: %s, reason: %s",
+			document.Index, document.ID, document.Status, document.ErrorType, document.Reason))
+	}
+
+	return fmt.Sprintf("%d documents were rejected: [%s]", len(rde.Documents), strings.Join(descriptions, "; "))
+}
+
+// Add will keep the documents rejected in the provided error and will return nil, so the caller can go on with its
+// next writes. Any other error is returned unchanged
+func (rde *RejectedDocumentsError) Add(err error) error {
+	rejected := &RejectedDocumentsError{}
+	if !errors.As(err, &rejected) {
+		return err
+	}
+
+	rde.Documents = append(rde.Documents, rejected.Documents...)
+
+	return nil
+}
+
+// AsError returns the kept documents in a RejectedDocumentsError, or nil if no document was rejected
+func (rde *RejectedDocumentsError) AsError() error {
+	if len(rde.Documents) == 0 {
+		return nil
+	}
+
+	return &RejectedDocumentsError{
+		Documents: rde.Documents,
+	}
+}
@@ -0,0 +1,33 @@
+package data
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRejectedDocumentsError_AddAndAsError(t *testing.T) {
+	t.Parallel()
+
+	rejected := &RejectedDocumentsError{}
+	require.Nil(t, rejected.AsError())
+	require.Nil(t, rejected.Add(nil))
+
+	localErr := errors.New("local error")
+	require.Equal(t, localErr, rejected.Add(localErr))
+	require.Nil(t, rejected.AsError())
+
+	err := rejected.Add(&RejectedDocumentsError{Documents: []*RejectedDocument{{Index: "blocks", ID: "b1"}}})
+	require.Nil(t, err)
+	err = rejected.Add(fmt.Errorf("wrapped: %w", &RejectedDocumentsError{Documents: []*RejectedDocument{{Index: "miniblocks", ID: "m1"}}}))
+	require.Nil(t, err)
+
+	err = rejected.AsError()
+	result := &RejectedDocumentsError{}
+	require.True(t, errors.As(err, &result))
+	require.Equal(t, []*RejectedDocument{{Index: "blocks", ID: "b1"}, {Index: "miniblocks", ID: "m1"}}, result.Documents)
+	require.Contains(t, err.Error(), "2 documents were rejected")
+	require.Contains(t, err.Error(), "index: miniblocks, id: m1")
+}
@@ -33,8 +33,10 @@ type ScResult struct {
 	ReceiversShardIDs  []uint32      `json:"receiversShardIDs,omitempty"`
 	Operation          string        `json:"operation,omitempty"`
 	Function           string        `json:"function,omitempty"`
+	Decoded            *DecodedData  `json:"decoded,omitempty"`
 	IsRelayed          bool          `json:"isRelayed,omitempty"`
 	CanBeIgnored       bool          `json:"canBeIgnored,omitempty"`
 	OriginalSender     string        `json:"originalSender,omitempty"`
+	Finalized          bool          `json:"finalized"`
 	SenderAddressBytes []byte        `json:"-"`
 }
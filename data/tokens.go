@@ -30,6 +30,7 @@ type ResponseTokenDB struct {
 type SourceToken struct {
 	Type         string `json:"type"`
 	CurrentOwner string `json:"currentOwner"`
+	NumDecimals  uint64 `json:"numDecimals"`
 }
 
 // TokenInfo is a structure that is needed to store information about a token
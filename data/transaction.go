@@ -40,8 +40,10 @@ type Transaction struct {
 	Type                 string        `json:"type,omitempty"`
 	Operation            string        `json:"operation,omitempty"`
 	Function             string        `json:"function,omitempty"`
+	Decoded              *DecodedData  `json:"decoded,omitempty"`
 	IsRelayed            bool          `json:"isRelayed,omitempty"`
 	Version              uint32        `json:"version,omitempty"`
+	Finalized            bool          `json:"finalized"`
 	SmartContractResults []*ScResult   `json:"-"`
 	ReceiverAddressBytes []byte        `json:"-"`
 	Hash                 string        `json:"-"`
@@ -99,6 +101,15 @@ type PreparedResults struct {
 	TxHashRefund map[string]*RefundData
 }
 
+// PreparedBlockTransactions holds the results of the processing of the transactions of a block that does not
+// need any request to the database, together with the documents that were already serialized
+type PreparedBlockTransactions struct {
+	Results   *PreparedResults
+	LogsData  *PreparedLogsResults
+	Logs      []*Logs
+	Documents *DocumentsSlice
+}
+
 // ResponseTransactions is the structure for the transactions response
 type ResponseTransactions struct {
 	Docs []*ResponseTransactionDB `json:"docs"`
@@ -0,0 +1,23 @@
+package data
+
+import "time"
+
+// Transfer is a structure containing the fields that need to be saved for a movement of a token. A transaction that
+// transfers more tokens, or that calls smart contracts which transfer tokens, has a transfer for every token moved
+type Transfer struct {
+	ID              string        `json:"-"`
+	TxHash          string        `json:"txHash"`
+	ScrHash         string        `json:"scrHash,omitempty"`
+	Operation       string        `json:"operation"`
+	Sender          string        `json:"sender"`
+	Receiver        string        `json:"receiver"`
+	ReceiverShard   uint32        `json:"receiverShard"`
+	Token           string        `json:"token"`
+	TokenIdentifier string        `json:"tokenIdentifier"`
+	Nonce           uint64        `json:"nonce,omitempty"`
+	Amount          string        `json:"amount"`
+	AmountNum       float64       `json:"amountNum"`
+	AmountSortable  string        `json:"amountSortable"`
+	ShardID         uint32        `json:"shardID"`
+	Timestamp       time.Duration `json:"timestamp"`
+}
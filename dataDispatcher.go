@@ -3,12 +3,18 @@ package indexer
 import (
 	"context"
 	"errors"
+	"fmt"
 	"runtime/debug"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/ME-MotherEarth/me-core/core/atomic"
 	"github.com/ME-MotherEarth/me-core/core/check"
+	"github.com/ME-MotherEarth/me-core/marshal"
+	"github.com/ME-MotherEarth/me-elastic-indexer/data"
+	"github.com/ME-MotherEarth/me-elastic-indexer/payload"
+	"github.com/ME-MotherEarth/me-elastic-indexer/queue"
 	"github.com/ME-MotherEarth/me-elastic-indexer/workItems"
 	logger "github.com/ME-MotherEarth/me-logger"
 )
@@ -16,12 +22,35 @@ import (
 var log = logger.GetOrCreate("indexer")
 
 const (
-	durationBetweenErrorRetry = time.Second * 3
-	closeTimeout              = time.Second * 20
-	backOffTime               = time.Second * 10
-	maxBackOff                = time.Minute * 5
+	durationBetweenErrorRetry   = time.Second * 3
+	durationBetweenPersistRetry = time.Second
+	closeTimeout                = time.Second * 20
+	backOffTime                 = time.Second * 10
+	maxBackOff                  = time.Minute * 5
 )
 
+// ArgsDataDispatcher holds all dependencies required by a data dispatcher. If a DeadLetterStore is provided, the
+// items that fail with a permanent error, or that fail MaxAttempts times, are moved in it so they do not block the
+// items that follow. Otherwise, or if MaxAttempts is zero, the failed items are retried until they are saved
+type ArgsDataDispatcher struct {
+	CacheSize       int
+	MaxAttempts     int
+	MetricsHandler  MetricsHandler
+	DeadLetterStore DeadLetterStoreHandler
+}
+
+// ArgsPersistentDataDispatcher holds all dependencies required by a data dispatcher that persists the work items
+// in a durable queue before they are saved. FinalizedDataProcessor is needed only to replay the blocks that are
+// saved after they were finalized
+type ArgsPersistentDataDispatcher struct {
+	ArgsDataDispatcher
+	Queue                  PersistentQueueHandler
+	Codec                  PayloadCodec
+	Marshalizer            marshal.Marshalizer
+	ElasticProcessor       ElasticProcessor
+	FinalizedDataProcessor ElasticProcessor
+}
+
 type dataDispatcher struct {
 	backOffTime         time.Duration
 	chanWorkItems       chan workItems.WorkItemHandler
@@ -30,21 +59,89 @@ type dataDispatcher struct {
 	currentWriteDone    chan struct{}
 	closeStartTime      time.Time
 	mutexCloseStartTime sync.RWMutex
+	metricsHandler      MetricsHandler
+	maxAttempts         int
+	deadLetterStore     DeadLetterStoreHandler
+
+	persistRetryTime       time.Duration
+	queue                  PersistentQueueHandler
+	codec                  PayloadCodec
+	marshalizer            marshal.Marshalizer
+	elasticProcessor       ElasticProcessor
+	finalizedDataProcessor ElasticProcessor
+
+	lookAheadItem *preparingItem
+}
+
+type queuedItem struct {
+	workItems.WorkItemHandler
+	id uint64
+}
+
+// preparingItem holds a work item that is prepared in background while the previous one is being saved
+type preparingItem struct {
+	item        workItems.WorkItemHandler
+	prepareDone chan struct{}
+}
+
+func (pi *preparingItem) waitPrepared() workItems.WorkItemHandler {
+	<-pi.prepareDone
+	return pi.item
 }
 
 // NewDataDispatcher creates a new dataDispatcher instance, capable of saving sequentially data in elasticsearch database
-func NewDataDispatcher(cacheSize int) (*dataDispatcher, error) {
-	if cacheSize < 0 {
+func NewDataDispatcher(args ArgsDataDispatcher) (*dataDispatcher, error) {
+	if args.CacheSize < 0 {
 		return nil, ErrNegativeCacheSize
 	}
+	if args.MaxAttempts < 0 {
+		return nil, ErrNegativeMaxAttempts
+	}
+	if check.IfNil(args.MetricsHandler) {
+		return nil, ErrNilMetricsHandler
+	}
 
 	dd := &dataDispatcher{
-		chanWorkItems:       make(chan workItems.WorkItemHandler, cacheSize),
+		chanWorkItems:       make(chan workItems.WorkItemHandler, args.CacheSize),
 		wasClosed:           &atomic.Flag{},
 		currentWriteDone:    make(chan struct{}),
 		mutexCloseStartTime: sync.RWMutex{},
+		metricsHandler:      args.MetricsHandler,
+		maxAttempts:         args.MaxAttempts,
+		deadLetterStore:     args.DeadLetterStore,
+	}
+
+	return dd, nil
+}
+
+// NewPersistentDataDispatcher creates a new dataDispatcher instance that appends every added item to the provided
+// queue and acknowledges it only after it was saved. Items left unacknowledged are replayed on StartIndexData
+func NewPersistentDataDispatcher(args ArgsPersistentDataDispatcher) (*dataDispatcher, error) {
+	if check.IfNil(args.Queue) {
+		return nil, ErrNilPersistentQueue
+	}
+	if check.IfNil(args.Codec) {
+		return nil, ErrNilPayloadCodec
+	}
+	if check.IfNil(args.Marshalizer) {
+		return nil, ErrNilMarshalizer
+	}
+	if check.IfNil(args.ElasticProcessor) {
+		return nil, ErrNilElasticProcessor
+	}
+
+	dd, err := NewDataDispatcher(args.ArgsDataDispatcher)
+	if err != nil {
+		return nil, err
 	}
 
+	dd.queue = args.Queue
+	dd.persistRetryTime = durationBetweenPersistRetry
+	dd.codec = args.Codec
+	dd.marshalizer = args.Marshalizer
+	dd.elasticProcessor = args.ElasticProcessor
+	dd.finalizedDataProcessor = args.FinalizedDataProcessor
+
 	return dd, nil
 }
 
@@ -67,18 +164,190 @@ func (d *dataDispatcher) doDataDispatch(ctx context.Context) {
 		}
 	}()
 
+	shouldStop := d.replayPendingItems(ctx)
+	if shouldStop {
+		d.stopWorker()
+		return
+	}
+
 	for {
-		select {
-		case <-ctx.Done():
+		wi, ok := d.nextItem(ctx)
+		if !ok {
 			d.stopWorker()
 			return
-		case wi := <-d.chanWorkItems:
-			timeoutOnClose := d.doWork(wi)
-			if timeoutOnClose {
-				d.stopWorker()
-				return
-			}
 		}
+
+		d.startLookAhead()
+
+		timeoutOnClose := d.doWork(wi)
+		if timeoutOnClose {
+			d.stopWorker()
+			return
+		}
+	}
+}
+
+// nextItem returns the item that was prepared in background, if any, otherwise it waits for a new item
+func (d *dataDispatcher) nextItem(ctx context.Context) (workItems.WorkItemHandler, bool) {
+	select {
+	case <-ctx.Done():
+		return nil, false
+	default:
+	}
+
+	if d.lookAheadItem != nil {
+		wi := d.lookAheadItem.waitPrepared()
+		d.lookAheadItem = nil
+		return wi, true
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil, false
+	case wi := <-d.chanWorkItems:
+		d.metricsHandler.SetQueueDepth(len(d.chanWorkItems))
+		return wi, true
+	}
+}
+
+// startLookAhead will start preparing the next queued item, if there is one, so its processing overlaps with
+// saving the current item
+func (d *dataDispatcher) startLookAhead() {
+	select {
+	case wi := <-d.chanWorkItems:
+		d.metricsHandler.SetQueueDepth(len(d.chanWorkItems))
+		d.lookAheadItem = &preparingItem{
+			item:        wi,
+			prepareDone: make(chan struct{}),
+		}
+		go prepareItem(d.lookAheadItem)
+	default:
+	}
+}
+
+func prepareItem(pi *preparingItem) {
+	defer close(pi.prepareDone)
+
+	wi := pi.item
+	item, ok := wi.(*queuedItem)
+	if ok {
+		wi = item.WorkItemHandler
+	}
+
+	preparableItem, ok := wi.(workItems.PreparableWorkItemHandler)
+	if !ok {
+		return
+	}
+
+	preparableItem.Prepare()
+}
+
+func (d *dataDispatcher) replayPendingItems(ctx context.Context) bool {
+	if check.IfNil(d.queue) {
+		return false
+	}
+
+	records := d.queue.Pending()
+	if len(records) > 0 {
+		log.Info("dataDispatcher: replaying items from the persistent queue", "num items", len(records))
+	}
+
+	for _, record := range records {
+		select {
+		case <-ctx.Done():
+			return true
+		default:
+		}
+
+		wi, err := d.restoreItem(record.Data)
+		if err != nil {
+			d.quarantineItem(record, err)
+			continue
+		}
+
+		timeoutOnClose := d.doWork(&queuedItem{WorkItemHandler: wi, id: record.ID})
+		if timeoutOnClose {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (d *dataDispatcher) restoreItem(itemData []byte) (workItems.WorkItemHandler, error) {
+	p, err := d.codec.Decode(itemData)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.Type != payload.SaveFinalizedBlock {
+		return workItems.NewItemFromPayload(d.elasticProcessor, d.marshalizer, p)
+	}
+	if check.IfNil(d.finalizedDataProcessor) {
+		return nil, ErrNilFinalizedDataProcessor
+	}
+
+	return workItems.NewItemFromPayload(d.finalizedDataProcessor, d.marshalizer, p)
+}
+
+// quarantineItem will move the item that cannot be restored out of the persistent queue, so it can be inspected
+// later. The item is left in the queue if it cannot be moved, and it will be replayed again on restart
+func (d *dataDispatcher) quarantineItem(record *queue.Record, reason error) {
+	err := d.queue.Quarantine(record)
+	if err != nil {
+		log.Error("dataDispatcher.replayPendingItems cannot restore item nor move it in quarantine, it will be replayed on restart",
+			"id", record.ID, "reason", reason.Error(), "error", err.Error())
+		return
+	}
+
+	log.Error("dataDispatcher.replayPendingItems cannot restore item, it was moved in quarantine",
+		"id", record.ID, "error", reason.Error())
+}
+
+// persistItem will append the item in the persistent queue, if there is one. An item that cannot be encoded is
+// rejected, while the append is retried, with a growing delay, until it succeeds or the dispatcher is closed
+func (d *dataDispatcher) persistItem(item workItems.WorkItemHandler) (workItems.WorkItemHandler, error) {
+	if check.IfNil(d.queue) {
+		return item, nil
+	}
+
+	persistableItem, ok := item.(workItems.PersistableWorkItemHandler)
+	if !ok {
+		log.Warn("dataDispatcher.persistItem item cannot be persisted, it will be kept only in memory")
+		return item, nil
+	}
+
+	itemData, err := d.codec.Encode(persistableItem.Payload())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrCannotPersistItem, err.Error())
+	}
+
+	retryTime := d.persistRetryTime
+	for {
+		id, errAppend := d.queue.Append(itemData)
+		if errAppend == nil {
+			return &queuedItem{WorkItemHandler: item, id: id}, nil
+		}
+		if d.wasClosed.IsSet() || errors.Is(errAppend, queue.ErrQueueClosed) {
+			return nil, fmt.Errorf("%w: %s", ErrCannotPersistItem, errAppend.Error())
+		}
+
+		log.Warn("dataDispatcher.persistItem cannot append item, will retry",
+			"retry in", retryTime, "error", errAppend.Error())
+		time.Sleep(retryTime)
+
+		retryTime *= 2
+		if retryTime > maxBackOff {
+			retryTime = maxBackOff
+		}
+	}
+}
+
+func (d *dataDispatcher) ackItem(id uint64) {
+	err := d.queue.Ack(id)
+	if err != nil {
+		log.Warn("dataDispatcher.ackItem cannot acknowledge item, it will be replayed on restart",
+			"id", id, "error", err.Error())
 	}
 }
 
@@ -104,10 +373,31 @@ func (d *dataDispatcher) Close() error {
 
 	<-d.currentWriteDone
 	d.consumeRemainingItems()
-	return nil
+
+	if !check.IfNil(d.deadLetterStore) {
+		err := d.deadLetterStore.Close()
+		if err != nil {
+			log.Warn("dataDispatcher.Close cannot close the dead letter store", "error", err.Error())
+		}
+	}
+
+	if check.IfNil(d.queue) {
+		return nil
+	}
+
+	return d.queue.Close()
 }
 
 func (d *dataDispatcher) consumeRemainingItems() {
+	if d.lookAheadItem != nil {
+		wi := d.lookAheadItem.waitPrepared()
+		d.lookAheadItem = nil
+		isTimeout := d.doWork(wi)
+		if isTimeout {
+			return
+		}
+	}
+
 	for {
 		select {
 		case wi := <-d.chanWorkItems:
@@ -121,21 +411,32 @@ func (d *dataDispatcher) consumeRemainingItems() {
 	}
 }
 
-// Add will add a new item in queue
-func (d *dataDispatcher) Add(item workItems.WorkItemHandler) {
+// Add will add a new item in queue. It blocks while the item cannot be appended in the persistent queue, and it
+// returns an error if the item cannot be persisted at all
+func (d *dataDispatcher) Add(item workItems.WorkItemHandler) error {
 	if check.IfNil(item) {
 		log.Warn("dataDispatcher.Add nil item: will do nothing")
-		return
+		return nil
 	}
 	if d.wasClosed.IsSet() {
 		log.Warn("dataDispatcher.Add cannot add item: channel chanWorkItems is closed")
-		return
+		return ErrDispatcherClosed
+	}
+
+	wi, err := d.persistItem(item)
+	if err != nil {
+		log.Error("dataDispatcher.Add cannot add item", "error", err.Error())
+		return err
 	}
 
-	d.chanWorkItems <- item
+	d.chanWorkItems <- wi
+	d.metricsHandler.SetQueueDepth(len(d.chanWorkItems))
+
+	return nil
 }
 
 func (d *dataDispatcher) doWork(wi workItems.WorkItemHandler) bool {
+	numAttempts := 0
 	for {
 		if d.exitIfTimeoutOnClose() {
 			log.Warn("dataDispatcher.doWork could not index item",
@@ -143,28 +444,153 @@ func (d *dataDispatcher) doWork(wi workItems.WorkItemHandler) bool {
 			return true
 		}
 
-		err := wi.Save()
+		err := d.saveItem(wi)
 		if errors.Is(err, ErrBackOff) {
 			log.Warn("dataDispatcher.doWork could not index item",
 				"received back off:", err.Error())
 
 			d.increaseBackOffTime()
+			d.metricsHandler.SetBackOff(d.backOffTime)
 			time.Sleep(d.backOffTime)
 
 			continue
 		}
 
-		d.backOffTime = 0
+		if d.backOffTime != 0 {
+			d.backOffTime = 0
+			d.metricsHandler.SetBackOff(0)
+		}
+
+		rejected := &data.RejectedDocumentsError{}
+		if errors.As(err, &rejected) {
+			d.keepRejectedDocuments(wi, rejected)
+			err = nil
+		}
 		if err != nil {
+			numAttempts++
+			if d.shouldGiveUp(err, numAttempts) && d.moveToDeadLetters(wi, err, numAttempts) {
+				return false
+			}
+
 			log.Warn("dataDispatcher.doWork could not index item (will retry)", "error", err.Error())
 			time.Sleep(durationBetweenErrorRetry)
 
 			continue
 		}
 
+		d.recordIndexedBlock(wi)
+		d.ackIfQueued(wi)
+
 		return false
 	}
+}
+
+// shouldGiveUp returns true if the item cannot be saved by retrying, because the error is permanent or because it
+// failed too many times. The items are never given up if there is no dead letter store to keep them
+func (d *dataDispatcher) shouldGiveUp(err error, numAttempts int) bool {
+	if check.IfNil(d.deadLetterStore) {
+		return false
+	}
+	if errors.Is(err, ErrPermanentFailure) || errors.Is(err, workItems.ErrBodyTypeAssertion) {
+		return true
+	}
+
+	return d.maxAttempts > 0 && numAttempts >= d.maxAttempts
+}
+
+// moveToDeadLetters will put the item in the dead letter store and will acknowledge it, so the dispatcher moves on.
+// It returns false if the item could not be stored, in which case it has to be retried
+func (d *dataDispatcher) moveToDeadLetters(wi workItems.WorkItemHandler, reason error, numAttempts int) bool {
+	itemType := workItemType(wi)
+	err := d.deadLetterStore.Put(itemType, payloadOf(wi), reason)
+	if err != nil {
+		log.Error("dataDispatcher.moveToDeadLetters cannot store item, it will be retried",
+			"type", itemType, "error", err.Error())
+		return false
+	}
+
+	log.Error("dataDispatcher.doWork could not index item, it was moved to the dead letters",
+		"type", itemType, "attempts", numAttempts, "error", reason.Error())
+	d.ackIfQueued(wi)
+
+	return true
+}
+
+// keepRejectedDocuments will put the documents the database refused to write in the dead letter store, together with
+// the block of the item. The rest of the item was saved, so the item is not saved again, as the documents that are
+// not idempotent would be applied twice. Without a store, or if the store fails, the documents are only logged
+func (d *dataDispatcher) keepRejectedDocuments(wi workItems.WorkItemHandler, rejected *data.RejectedDocumentsError) {
+	itemType := workItemType(wi)
+	if !check.IfNil(d.deadLetterStore) {
+		err := d.deadLetterStore.PutRejectedDocuments(itemType, payloadOf(wi), rejected.Documents)
+		if err == nil {
+			log.Error("dataDispatcher.doWork item was saved without the documents the database rejected, "+
+				"they were moved to the dead letters", "type", itemType, "num documents", len(rejected.Documents))
+			return
+		}
+
+		log.Error("dataDispatcher.keepRejectedDocuments cannot store the rejected documents",
+			"type", itemType, "error", err.Error())
+	}
+
+	for _, document := range rejected.Documents {
+		log.Error("dataDispatcher.doWork item was saved without a document the database rejected",
+			"type", itemType, "index", document.Index, "id", document.ID, "status", document.Status,
+			"error type", document.ErrorType, "reason", document.Reason, "action", document.Action)
+	}
+}
+
+func (d *dataDispatcher) saveItem(wi workItems.WorkItemHandler) error {
+	startTime := time.Now()
+	err := wi.Save()
+	d.metricsHandler.ObserveWorkItem(workItemType(wi), time.Since(startTime), err)
+
+	return err
+}
+
+// payloadOf returns the payload of the item, or nil for the items that cannot be persisted
+func payloadOf(wi workItems.WorkItemHandler) *payload.Payload {
+	persistableItem, ok := unwrapItem(wi).(workItems.PersistableWorkItemHandler)
+	if !ok {
+		return nil
+	}
+
+	return persistableItem.Payload()
+}
+
+// unwrapItem returns the work item without the persistent queue information
+func unwrapItem(wi workItems.WorkItemHandler) workItems.WorkItemHandler {
+	item, ok := wi.(*queuedItem)
+	if ok {
+		return item.WorkItemHandler
+	}
+
+	return wi
+}
+
+// workItemType returns the name of the work item type, without the package name
+func workItemType(wi workItems.WorkItemHandler) string {
+	typeName := strings.TrimPrefix(fmt.Sprintf("%T", unwrapItem(wi)), "*")
+	return typeName[strings.LastIndex(typeName, ".")+1:]
+}
+
+func (d *dataDispatcher) recordIndexedBlock(wi workItems.WorkItemHandler) {
+	blockItem, ok := unwrapItem(wi).(workItems.BlockWorkItemHandler)
+	if !ok || check.IfNil(blockItem.GetHeader()) {
+		return
+	}
+
+	header := blockItem.GetHeader()
+	d.metricsHandler.SetLastIndexedNonce(header.GetShardID(), header.GetNonce())
+}
+
+func (d *dataDispatcher) ackIfQueued(wi workItems.WorkItemHandler) {
+	item, ok := wi.(*queuedItem)
+	if !ok {
+		return
+	}
 
+	d.ackItem(item.id)
 }
 
 func (d *dataDispatcher) exitIfTimeoutOnClose() bool {
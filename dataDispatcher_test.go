@@ -2,32 +2,72 @@ package indexer
 
 import (
 	"context"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
 
+	coreData "github.com/ME-MotherEarth/me-core/data"
+	dataBlock "github.com/ME-MotherEarth/me-core/data/block"
+	"github.com/ME-MotherEarth/me-core/data/indexer"
 	"github.com/ME-MotherEarth/me-elastic-indexer/data"
+	"github.com/ME-MotherEarth/me-elastic-indexer/deadletter"
 	"github.com/ME-MotherEarth/me-elastic-indexer/mock"
+	"github.com/ME-MotherEarth/me-elastic-indexer/payload"
+	"github.com/ME-MotherEarth/me-elastic-indexer/queue"
 	"github.com/ME-MotherEarth/me-elastic-indexer/workItems"
 	"github.com/stretchr/testify/require"
 )
 
+func createMockArgsDataDispatcher() ArgsDataDispatcher {
+	return ArgsDataDispatcher{
+		CacheSize:      100,
+		MetricsHandler: &mock.MetricsHandlerStub{},
+	}
+}
+
 func TestNewDataDispatcher_InvalidCacheSize(t *testing.T) {
 	t.Parallel()
 
-	dataDist, err := NewDataDispatcher(-1)
+	args := createMockArgsDataDispatcher()
+	args.CacheSize = -1
+	dataDist, err := NewDataDispatcher(args)
 
 	require.Nil(t, dataDist)
 	require.Equal(t, ErrNegativeCacheSize, err)
 }
 
+func TestNewDataDispatcher_InvalidMaxAttempts(t *testing.T) {
+	t.Parallel()
+
+	args := createMockArgsDataDispatcher()
+	args.MaxAttempts = -1
+	dataDist, err := NewDataDispatcher(args)
+
+	require.Nil(t, dataDist)
+	require.Equal(t, ErrNegativeMaxAttempts, err)
+}
+
+func TestNewDataDispatcher_NilMetricsHandler(t *testing.T) {
+	t.Parallel()
+
+	args := createMockArgsDataDispatcher()
+	args.MetricsHandler = nil
+	dataDist, err := NewDataDispatcher(args)
+
+	require.Nil(t, dataDist)
+	require.Equal(t, ErrNilMetricsHandler, err)
+}
+
 func TestNewDataDispatcher(t *testing.T) {
 	t.Parallel()
 
-	dispatcher, err := NewDataDispatcher(100)
+	dispatcher, err := NewDataDispatcher(createMockArgsDataDispatcher())
 	require.NoError(t, err)
 	require.NotNil(t, dispatcher)
 }
@@ -35,7 +75,7 @@ func TestNewDataDispatcher(t *testing.T) {
 func TestDataDispatcher_StartIndexDataClose(t *testing.T) {
 	t.Parallel()
 
-	dispatcher, err := NewDataDispatcher(100)
+	dispatcher, err := NewDataDispatcher(createMockArgsDataDispatcher())
 	require.NoError(t, err)
 	dispatcher.StartIndexData()
 
@@ -76,7 +116,7 @@ func TestDataDispatcher_StartIndexDataClose(t *testing.T) {
 func TestDataDispatcher_Add(t *testing.T) {
 	t.Parallel()
 
-	dispatcher, err := NewDataDispatcher(100)
+	dispatcher, err := NewDataDispatcher(createMockArgsDataDispatcher())
 	require.NoError(t, err)
 	dispatcher.StartIndexData()
 
@@ -112,7 +152,7 @@ func TestDataDispatcher_Add(t *testing.T) {
 func TestDataDispatcher_AddWithErrorShouldRetryTheReprocessing(t *testing.T) {
 	t.Parallel()
 
-	dispatcher, err := NewDataDispatcher(100)
+	dispatcher, err := NewDataDispatcher(createMockArgsDataDispatcher())
 	require.NoError(t, err)
 	dispatcher.StartIndexData()
 
@@ -145,10 +185,154 @@ func TestDataDispatcher_AddWithErrorShouldRetryTheReprocessing(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestDataDispatcher_NextItemShouldBePreparedWhileCurrentIsSaved(t *testing.T) {
+	t.Parallel()
+
+	dispatcher, err := NewDataDispatcher(createMockArgsDataDispatcher())
+	require.NoError(t, err)
+
+	firstSaveStarted := make(chan struct{})
+	releaseFirstSave := make(chan struct{})
+	secondPrepared := make(chan struct{})
+	savedOrder := make([]uint64, 0)
+	mutSavedOrder := sync.Mutex{}
+	wg := sync.WaitGroup{}
+	wg.Add(2)
+
+	elasticProc := &mock.ElasticProcessorStub{
+		PrepareTransactionsCalled: func(body *dataBlock.Body, header coreData.HeaderHandler, pool *indexer.Pool) (*data.PreparedBlockTransactions, error) {
+			if header.GetNonce() == 2 {
+				close(secondPrepared)
+			}
+			return &data.PreparedBlockTransactions{}, nil
+		},
+		SaveTransactionsCalled: func(body *dataBlock.Body, header coreData.HeaderHandler, pool *indexer.Pool) error {
+			close(firstSaveStarted)
+			<-releaseFirstSave
+
+			mutSavedOrder.Lock()
+			savedOrder = append(savedOrder, header.GetNonce())
+			mutSavedOrder.Unlock()
+			wg.Done()
+			return nil
+		},
+		SavePreparedTransactionsCalled: func(header coreData.HeaderHandler, pool *indexer.Pool, preparedTxs *data.PreparedBlockTransactions) error {
+			mutSavedOrder.Lock()
+			savedOrder = append(savedOrder, header.GetNonce())
+			mutSavedOrder.Unlock()
+			wg.Done()
+			return nil
+		},
+	}
+
+	createItem := func(nonce uint64) workItems.WorkItemHandler {
+		return workItems.NewItemBlock(elasticProc, &mock.MarshalizerMock{}, &indexer.ArgsSaveBlockData{
+			Header: &dataBlock.Header{Nonce: nonce},
+			Body:   &dataBlock.Body{MiniBlocks: []*dataBlock.MiniBlock{{}}},
+		})
+	}
+
+	dispatcher.Add(createItem(1))
+	dispatcher.Add(createItem(2))
+	dispatcher.StartIndexData()
+
+	<-firstSaveStarted
+	select {
+	case <-secondPrepared:
+	case <-time.After(time.Second * 5):
+		require.Fail(t, "second item was not prepared while the first one was saved")
+	}
+	close(releaseFirstSave)
+
+	wg.Wait()
+	require.Equal(t, []uint64{1, 2}, savedOrder)
+
+	err = dispatcher.Close()
+	require.NoError(t, err)
+}
+
+func TestDataDispatcher_ShouldRecordMetrics(t *testing.T) {
+	t.Parallel()
+
+	mutMetrics := sync.Mutex{}
+	observedTypes := make([]string, 0)
+	observedErrors := 0
+	lastNonces := make(map[uint32]uint64)
+	backOffs := make([]time.Duration, 0)
+	metricsHandler := &mock.MetricsHandlerStub{
+		ObserveWorkItemCalled: func(itemType string, duration time.Duration, err error) {
+			mutMetrics.Lock()
+			defer mutMetrics.Unlock()
+
+			observedTypes = append(observedTypes, itemType)
+			if err != nil {
+				observedErrors++
+			}
+		},
+		SetLastIndexedNonceCalled: func(shardID uint32, nonce uint64) {
+			mutMetrics.Lock()
+			lastNonces[shardID] = nonce
+			mutMetrics.Unlock()
+		},
+		SetBackOffCalled: func(backOff time.Duration) {
+			mutMetrics.Lock()
+			backOffs = append(backOffs, backOff)
+			mutMetrics.Unlock()
+		},
+	}
+
+	args := createMockArgsDataDispatcher()
+	args.MetricsHandler = metricsHandler
+	dispatcher, err := NewDataDispatcher(args)
+	require.NoError(t, err)
+
+	wg := sync.WaitGroup{}
+	wg.Add(2)
+	numSaveHeaderCalls := uint32(0)
+	elasticProc := &mock.ElasticProcessorStub{
+		SaveHeaderCalled: func(headerHash []byte, header coreData.HeaderHandler, signersIndexes []uint64, body *dataBlock.Body, notarizedHeadersHashes []string, gasConsumptionData indexer.HeaderGasConsumption, txsSize int) error {
+			if atomic.AddUint32(&numSaveHeaderCalls, 1) == 1 {
+				return ErrBackOff
+			}
+			return nil
+		},
+		SaveRoundsInfoCalled: func(infos []*data.RoundInfo) error {
+			wg.Done()
+			return nil
+		},
+		SaveTransactionsCalled: func(body *dataBlock.Body, header coreData.HeaderHandler, pool *indexer.Pool) error {
+			wg.Done()
+			return nil
+		},
+	}
+
+	dispatcher.backOffTime = time.Millisecond
+	dispatcher.Add(workItems.NewItemBlock(elasticProc, &mock.MarshalizerMock{}, &indexer.ArgsSaveBlockData{
+		Header: &dataBlock.Header{Nonce: 7, ShardID: 1},
+		Body:   &dataBlock.Body{MiniBlocks: []*dataBlock.MiniBlock{{}}},
+	}))
+	dispatcher.Add(workItems.NewItemRounds(elasticProc, []*data.RoundInfo{{}}))
+	dispatcher.StartIndexData()
+
+	wg.Wait()
+	err = dispatcher.Close()
+	require.NoError(t, err)
+
+	mutMetrics.Lock()
+	defer mutMetrics.Unlock()
+
+	require.Equal(t, []string{"itemBlock", "itemBlock", "itemRounds"}, observedTypes)
+	require.Equal(t, 1, observedErrors)
+	require.Equal(t, map[uint32]uint64{1: 7}, lastNonces)
+	require.Len(t, backOffs, 2)
+	require.True(t, backOffs[0] > 0)
+	require.Equal(t, time.Duration(0), backOffs[1])
+}
+
 func TestDataDispatcher_Close(t *testing.T) {
 	t.Parallel()
 
-	dispatcher, err := NewDataDispatcher(100)
+	dispatcher, err := NewDataDispatcher(createMockArgsDataDispatcher())
 	require.NoError(t, err)
 	dispatcher.StartIndexData()
 
@@ -195,7 +379,7 @@ func TestDataDispatcher_RecoverPanic(t *testing.T) {
 		require.NotNil(t, r)
 	}()
 
-	dispatcher, err := NewDataDispatcher(100)
+	dispatcher, err := NewDataDispatcher(createMockArgsDataDispatcher())
 	require.NoError(t, err)
 
 	elasticProc := &mock.ElasticProcessorStub{
@@ -207,3 +391,463 @@ func TestDataDispatcher_RecoverPanic(t *testing.T) {
 	dispatcher.Add(workItems.NewItemRounds(elasticProc, []*data.RoundInfo{}))
 	dispatcher.doDataDispatch(context.Background())
 }
+
+func createMockArgsPersistentDataDispatcher(t *testing.T, elasticProc ElasticProcessor) ArgsPersistentDataDispatcher {
+	diskQueue, err := queue.NewDiskQueue(t.TempDir())
+	require.NoError(t, err)
+
+	codec, _ := payload.NewCodec(&mock.MarshalizerMock{})
+
+	return ArgsPersistentDataDispatcher{
+		ArgsDataDispatcher: createMockArgsDataDispatcher(),
+		Queue:              diskQueue,
+		Codec:              codec,
+		Marshalizer:        &mock.MarshalizerMock{},
+		ElasticProcessor:   elasticProc,
+	}
+}
+
+func TestNewPersistentDataDispatcher_InvalidArgsShouldErr(t *testing.T) {
+	t.Parallel()
+
+	args := createMockArgsPersistentDataDispatcher(t, &mock.ElasticProcessorStub{})
+	args.Queue = nil
+	dispatcher, err := NewPersistentDataDispatcher(args)
+	require.Nil(t, dispatcher)
+	require.Equal(t, ErrNilPersistentQueue, err)
+
+	args = createMockArgsPersistentDataDispatcher(t, &mock.ElasticProcessorStub{})
+	args.Codec = nil
+	dispatcher, err = NewPersistentDataDispatcher(args)
+	require.Nil(t, dispatcher)
+	require.Equal(t, ErrNilPayloadCodec, err)
+
+	args = createMockArgsPersistentDataDispatcher(t, &mock.ElasticProcessorStub{})
+	args.Marshalizer = nil
+	dispatcher, err = NewPersistentDataDispatcher(args)
+	require.Nil(t, dispatcher)
+	require.Equal(t, ErrNilMarshalizer, err)
+
+	args = createMockArgsPersistentDataDispatcher(t, &mock.ElasticProcessorStub{})
+	args.ElasticProcessor = nil
+	dispatcher, err = NewPersistentDataDispatcher(args)
+	require.Nil(t, dispatcher)
+	require.Equal(t, ErrNilElasticProcessor, err)
+
+	args = createMockArgsPersistentDataDispatcher(t, &mock.ElasticProcessorStub{})
+	args.MetricsHandler = nil
+	dispatcher, err = NewPersistentDataDispatcher(args)
+	require.Nil(t, dispatcher)
+	require.Equal(t, ErrNilMetricsHandler, err)
+
+	args = createMockArgsPersistentDataDispatcher(t, &mock.ElasticProcessorStub{})
+	args.CacheSize = -1
+	dispatcher, err = NewPersistentDataDispatcher(args)
+	require.Nil(t, dispatcher)
+	require.Equal(t, ErrNegativeCacheSize, err)
+}
+
+func TestPersistentDataDispatcher_SavedItemsShouldBeAcknowledged(t *testing.T) {
+	t.Parallel()
+
+	wg := sync.WaitGroup{}
+	wg.Add(2)
+	elasticProc := &mock.ElasticProcessorStub{
+		SaveRoundsInfoCalled: func(infos []*data.RoundInfo) error {
+			wg.Done()
+			return nil
+		},
+	}
+
+	args := createMockArgsPersistentDataDispatcher(t, elasticProc)
+	diskQueue := args.Queue
+	dispatcher, err := NewPersistentDataDispatcher(args)
+	require.NoError(t, err)
+	dispatcher.StartIndexData()
+
+	dispatcher.Add(workItems.NewItemRounds(elasticProc, []*data.RoundInfo{{Index: 1}}))
+	dispatcher.Add(workItems.NewItemRounds(elasticProc, []*data.RoundInfo{{Index: 2}}))
+	wg.Wait()
+
+	err = dispatcher.Close()
+	require.NoError(t, err)
+	require.Empty(t, diskQueue.Pending())
+}
+
+func TestPersistentDataDispatcher_StartIndexDataShouldReplayPendingItems(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	codec, _ := payload.NewCodec(&mock.MarshalizerMock{})
+	diskQueue, _ := queue.NewDiskQueue(dir)
+	for _, round := range []uint64{1, 2, 3} {
+		itemData, _ := codec.Encode(&payload.Payload{
+			Type:       payload.SaveRoundsInfo,
+			RoundsInfo: []*data.RoundInfo{{Index: round}},
+		})
+		_, err := diskQueue.Append(itemData)
+		require.NoError(t, err)
+	}
+	_, _ = diskQueue.Append([]byte("invalid item"))
+	require.NoError(t, diskQueue.Close())
+
+	mutex := sync.Mutex{}
+	savedRounds := make([]uint64, 0)
+	wg := sync.WaitGroup{}
+	wg.Add(4)
+	elasticProc := &mock.ElasticProcessorStub{
+		SaveRoundsInfoCalled: func(infos []*data.RoundInfo) error {
+			mutex.Lock()
+			savedRounds = append(savedRounds, infos[0].Index)
+			mutex.Unlock()
+
+			wg.Done()
+			return nil
+		},
+	}
+
+	diskQueue, _ = queue.NewDiskQueue(dir)
+	args := createMockArgsPersistentDataDispatcher(t, elasticProc)
+	args.Queue = diskQueue
+	dispatcher, _ := NewPersistentDataDispatcher(args)
+	dispatcher.Add(workItems.NewItemRounds(elasticProc, []*data.RoundInfo{{Index: 4}}))
+	dispatcher.StartIndexData()
+	wg.Wait()
+
+	err := dispatcher.Close()
+	require.NoError(t, err)
+	require.Equal(t, []uint64{1, 2, 3, 4}, savedRounds)
+
+	diskQueue, _ = queue.NewDiskQueue(dir)
+	require.Empty(t, diskQueue.Pending())
+	require.NoError(t, diskQueue.Close())
+
+	// the item that cannot be restored is kept in the quarantine file
+	info, err := os.Stat(filepath.Join(dir, queue.QuarantineFileName))
+	require.NoError(t, err)
+	require.NotZero(t, info.Size())
+}
+
+func TestPersistentDataDispatcher_AddShouldErrIfItemCannotBeEncoded(t *testing.T) {
+	t.Parallel()
+
+	elasticProc := &mock.ElasticProcessorStub{}
+	args := createMockArgsPersistentDataDispatcher(t, elasticProc)
+	args.Codec, _ = payload.NewCodec(&mock.MarshalizerMock{Fail: true})
+	dispatcher, _ := NewPersistentDataDispatcher(args)
+
+	err := dispatcher.Add(workItems.NewItemRemoveBlock(elasticProc, &dataBlock.Body{}, &dataBlock.Header{Nonce: 1}))
+	require.True(t, errors.Is(err, ErrCannotPersistItem))
+	require.Zero(t, len(dispatcher.chanWorkItems))
+}
+
+func TestPersistentDataDispatcher_AddShouldRetryTheAppendUntilItSucceeds(t *testing.T) {
+	t.Parallel()
+
+	numAppends := 0
+	elasticProc := &mock.ElasticProcessorStub{}
+	args := createMockArgsPersistentDataDispatcher(t, elasticProc)
+	args.Queue = &mock.PersistentQueueStub{
+		AppendCalled: func(itemData []byte) (uint64, error) {
+			numAppends++
+			if numAppends < 3 {
+				return 0, errors.New("disk full")
+			}
+			return 7, nil
+		},
+	}
+	dispatcher, _ := NewPersistentDataDispatcher(args)
+	dispatcher.persistRetryTime = time.Millisecond
+
+	err := dispatcher.Add(workItems.NewItemRounds(elasticProc, []*data.RoundInfo{{Index: 1}}))
+	require.NoError(t, err)
+	require.Equal(t, 3, numAppends)
+
+	wi := <-dispatcher.chanWorkItems
+	require.Equal(t, uint64(7), wi.(*queuedItem).id)
+}
+
+func TestPersistentDataDispatcher_AddAfterCloseShouldErr(t *testing.T) {
+	t.Parallel()
+
+	elasticProc := &mock.ElasticProcessorStub{}
+	dispatcher, _ := NewPersistentDataDispatcher(createMockArgsPersistentDataDispatcher(t, elasticProc))
+	dispatcher.StartIndexData()
+	require.NoError(t, dispatcher.Close())
+
+	err := dispatcher.Add(workItems.NewItemRounds(elasticProc, []*data.RoundInfo{{Index: 1}}))
+	require.Equal(t, ErrDispatcherClosed, err)
+}
+
+func TestPersistentDataDispatcher_RestoreFinalizedBlockDataShouldUseFinalizedDataProcessor(t *testing.T) {
+	t.Parallel()
+
+	args := createMockArgsPersistentDataDispatcher(t, &mock.ElasticProcessorStub{
+		SaveHeaderCalled: func(_ []byte, _ coreData.HeaderHandler, _ []uint64, _ *dataBlock.Body, _ []string, _ indexer.HeaderGasConsumption, _ int) error {
+			require.Fail(t, "should have not been called")
+			return nil
+		},
+	})
+	dispatcher, _ := NewPersistentDataDispatcher(args)
+
+	itemData, _ := args.Codec.Encode(&payload.Payload{
+		Type: payload.SaveFinalizedBlock,
+		ArgsSaveBlock: &indexer.ArgsSaveBlockData{
+			HeaderHash: []byte("hash"),
+			Header:     &dataBlock.Header{Nonce: 1},
+			Body:       &dataBlock.Body{},
+		},
+	})
+	_, err := dispatcher.restoreItem(itemData)
+	require.Equal(t, ErrNilFinalizedDataProcessor, err)
+
+	savedHeaderHash := make([]byte, 0)
+	args.FinalizedDataProcessor = &mock.ElasticProcessorStub{
+		SaveHeaderCalled: func(headerHash []byte, _ coreData.HeaderHandler, _ []uint64, _ *dataBlock.Body, _ []string, _ indexer.HeaderGasConsumption, _ int) error {
+			savedHeaderHash = headerHash
+			return nil
+		},
+	}
+	dispatcher, _ = NewPersistentDataDispatcher(args)
+
+	wi, err := dispatcher.restoreItem(itemData)
+	require.NoError(t, err)
+	require.NoError(t, wi.Save())
+	require.Equal(t, []byte("hash"), savedHeaderHash)
+}
+
+func TestDataDispatcher_PermanentErrorShouldMoveItemToDeadLetters(t *testing.T) {
+	t.Parallel()
+
+	mutex := sync.Mutex{}
+	deadLetters := make([]*payload.Payload, 0)
+	deadLetterStore := &mock.DeadLetterStoreStub{
+		PutCalled: func(itemType string, p *payload.Payload, reason error) error {
+			require.Equal(t, "itemRounds", itemType)
+			require.True(t, errors.Is(reason, ErrPermanentFailure))
+
+			mutex.Lock()
+			deadLetters = append(deadLetters, p)
+			mutex.Unlock()
+			return nil
+		},
+	}
+
+	wg := sync.WaitGroup{}
+	wg.Add(2)
+	numCalls := uint32(0)
+	elasticProc := &mock.ElasticProcessorStub{
+		SaveRoundsInfoCalled: func(infos []*data.RoundInfo) error {
+			defer wg.Done()
+
+			atomic.AddUint32(&numCalls, 1)
+			if infos[0].Index == 1 {
+				return fmt.Errorf("%w: mapper_parsing_exception", ErrPermanentFailure)
+			}
+			return nil
+		},
+	}
+
+	args := createMockArgsPersistentDataDispatcher(t, elasticProc)
+	args.DeadLetterStore = deadLetterStore
+	diskQueue := args.Queue
+	dispatcher, err := NewPersistentDataDispatcher(args)
+	require.NoError(t, err)
+	dispatcher.StartIndexData()
+
+	dispatcher.Add(workItems.NewItemRounds(elasticProc, []*data.RoundInfo{{Index: 1}}))
+	dispatcher.Add(workItems.NewItemRounds(elasticProc, []*data.RoundInfo{{Index: 2}}))
+	wg.Wait()
+
+	err = dispatcher.Close()
+	require.NoError(t, err)
+	require.Equal(t, uint32(2), atomic.LoadUint32(&numCalls))
+	require.Len(t, deadLetters, 1)
+	require.Equal(t, payload.SaveRoundsInfo, deadLetters[0].Type)
+	require.Equal(t, uint64(1), deadLetters[0].RoundsInfo[0].Index)
+	require.Empty(t, diskQueue.Pending())
+}
+
+func TestDataDispatcher_ItemShouldBeMovedToDeadLettersAfterMaxAttempts(t *testing.T) {
+	t.Parallel()
+
+	numDeadLetters := uint32(0)
+	numClosed := uint32(0)
+	deadLetterStore := &mock.DeadLetterStoreStub{
+		PutCalled: func(itemType string, p *payload.Payload, reason error) error {
+			atomic.AddUint32(&numDeadLetters, 1)
+			return nil
+		},
+		CloseCalled: func() error {
+			atomic.AddUint32(&numClosed, 1)
+			return nil
+		},
+	}
+
+	wg := sync.WaitGroup{}
+	wg.Add(2)
+	elasticProc := &mock.ElasticProcessorStub{
+		SaveRoundsInfoCalled: func(infos []*data.RoundInfo) error {
+			wg.Done()
+			return errors.New("local error")
+		},
+	}
+
+	args := createMockArgsDataDispatcher()
+	args.MaxAttempts = 2
+	args.DeadLetterStore = deadLetterStore
+	dispatcher, err := NewDataDispatcher(args)
+	require.NoError(t, err)
+	dispatcher.StartIndexData()
+
+	dispatcher.Add(workItems.NewItemRounds(elasticProc, []*data.RoundInfo{{}}))
+	wg.Wait()
+
+	err = dispatcher.Close()
+	require.NoError(t, err)
+	require.Equal(t, uint32(1), atomic.LoadUint32(&numDeadLetters))
+	require.Equal(t, uint32(1), atomic.LoadUint32(&numClosed))
+}
+
+func TestDataDispatcher_ItemShouldBeRetriedIfDeadLetterStoreFails(t *testing.T) {
+	t.Parallel()
+
+	numPutCalls := uint32(0)
+	deadLetterStore := &mock.DeadLetterStoreStub{
+		PutCalled: func(itemType string, p *payload.Payload, reason error) error {
+			atomic.AddUint32(&numPutCalls, 1)
+			return errors.New("disk full")
+		},
+	}
+
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	numSaveCalls := uint32(0)
+	elasticProc := &mock.ElasticProcessorStub{
+		SaveRoundsInfoCalled: func(infos []*data.RoundInfo) error {
+			if atomic.AddUint32(&numSaveCalls, 1) == 1 {
+				return ErrPermanentFailure
+			}
+
+			wg.Done()
+			return nil
+		},
+	}
+
+	args := createMockArgsDataDispatcher()
+	args.DeadLetterStore = deadLetterStore
+	dispatcher, err := NewDataDispatcher(args)
+	require.NoError(t, err)
+	dispatcher.StartIndexData()
+
+	dispatcher.Add(workItems.NewItemRounds(elasticProc, []*data.RoundInfo{{}}))
+	wg.Wait()
+
+	err = dispatcher.Close()
+	require.NoError(t, err)
+	require.Equal(t, uint32(1), atomic.LoadUint32(&numPutCalls))
+	require.Equal(t, uint32(2), atomic.LoadUint32(&numSaveCalls))
+}
+
+func TestDataDispatcher_RejectedDocumentsShouldNotRetryTheItem(t *testing.T) {
+	t.Parallel()
+
+	wg := sync.WaitGroup{}
+	wg.Add(2)
+	numCalls := uint32(0)
+	elasticProc := &mock.ElasticProcessorStub{
+		SaveRoundsInfoCalled: func(infos []*data.RoundInfo) error {
+			defer wg.Done()
+
+			atomic.AddUint32(&numCalls, 1)
+			if infos[0].Index == 1 {
+				return &data.RejectedDocumentsError{Documents: []*data.RejectedDocument{{Index: "rounds", ID: "1"}}}
+			}
+			return nil
+		},
+	}
+
+	args := createMockArgsPersistentDataDispatcher(t, elasticProc)
+	diskQueue := args.Queue
+	dispatcher, err := NewPersistentDataDispatcher(args)
+	require.NoError(t, err)
+	dispatcher.StartIndexData()
+
+	dispatcher.Add(workItems.NewItemRounds(elasticProc, []*data.RoundInfo{{Index: 1}}))
+	dispatcher.Add(workItems.NewItemRounds(elasticProc, []*data.RoundInfo{{Index: 2}}))
+	wg.Wait()
+
+	err = dispatcher.Close()
+	require.NoError(t, err)
+	require.Equal(t, uint32(2), atomic.LoadUint32(&numCalls))
+	require.Empty(t, diskQueue.Pending())
+}
+
+func TestDataDispatcher_MappingConflictShouldMoveTheDocumentToDeadLetters(t *testing.T) {
+	t.Parallel()
+
+	mappingConflict := &data.RejectedDocument{
+		Index:     "transactions",
+		ID:        "tx1",
+		Status:    400,
+		ErrorType: "mapper_parsing_exception",
+		Reason:    "failed to parse field [data] of type [keyword]",
+		Action:    `{ "index" : { "_index":"transactions", "_id" : "tx1" } }` + "\n{\"data\":{}}\n",
+	}
+
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	numHeaderCalls := uint32(0)
+	elasticProc := &mock.ElasticProcessorStub{
+		SaveHeaderCalled: func(_ []byte, _ coreData.HeaderHandler, _ []uint64, _ *dataBlock.Body, _ []string, _ indexer.HeaderGasConsumption, _ int) error {
+			atomic.AddUint32(&numHeaderCalls, 1)
+			return nil
+		},
+		SaveTransactionsCalled: func(_ *dataBlock.Body, _ coreData.HeaderHandler, _ *indexer.Pool) error {
+			return &data.RejectedDocumentsError{Documents: []*data.RejectedDocument{mappingConflict}}
+		},
+		SaveCheckpointCalled: func(_ []byte, _ coreData.HeaderHandler) error {
+			wg.Done()
+			return nil
+		},
+	}
+
+	deadLettersDir := t.TempDir()
+	codec, _ := payload.NewCodec(&mock.MarshalizerMock{})
+	deadLetterStore, err := deadletter.NewFileStore(deadletter.ArgsFileStore{
+		Directory: deadLettersDir,
+		Encoder:   codec,
+	})
+	require.NoError(t, err)
+
+	args := createMockArgsPersistentDataDispatcher(t, elasticProc)
+	args.DeadLetterStore = deadLetterStore
+	diskQueue := args.Queue
+	dispatcher, err := NewPersistentDataDispatcher(args)
+	require.NoError(t, err)
+	dispatcher.StartIndexData()
+
+	headerHash := []byte("header hash")
+	dispatcher.Add(workItems.NewItemBlock(elasticProc, &mock.MarshalizerMock{}, &indexer.ArgsSaveBlockData{
+		HeaderHash:       headerHash,
+		Header:           &dataBlock.Header{Nonce: 7},
+		Body:             &dataBlock.Body{MiniBlocks: []*dataBlock.MiniBlock{{}}},
+		TransactionsPool: &indexer.Pool{},
+	}))
+	wg.Wait()
+
+	err = dispatcher.Close()
+	require.NoError(t, err)
+	require.Equal(t, uint32(1), atomic.LoadUint32(&numHeaderCalls))
+	require.Empty(t, diskQueue.Pending())
+
+	letters, err := deadletter.ReadLetters(filepath.Join(deadLettersDir, deadletter.FileName))
+	require.NoError(t, err)
+	require.Len(t, letters, 1)
+	require.Equal(t, "itemBlock", letters[0].ItemType)
+	require.Equal(t, hex.EncodeToString(headerHash), letters[0].HeaderHash)
+	require.Equal(t, uint64(7), letters[0].Nonce)
+	require.Contains(t, letters[0].Error, "mapper_parsing_exception")
+	require.Empty(t, letters[0].Payload)
+	require.Equal(t, []*data.RejectedDocument{mappingConflict}, letters[0].Documents)
+}
@@ -1,6 +1,10 @@
 package indexer
 
 import (
+	"bytes"
+	"encoding/hex"
+
+	"github.com/ME-MotherEarth/me-core/core"
 	"github.com/ME-MotherEarth/me-core/core/check"
 	coreData "github.com/ME-MotherEarth/me-core/data"
 	"github.com/ME-MotherEarth/me-core/data/indexer"
@@ -9,19 +13,28 @@ import (
 	"github.com/ME-MotherEarth/me-elastic-indexer/workItems"
 )
 
-// ArgDataIndexer is a structure that is used to store all the components that are needed to create an indexer
+// ArgDataIndexer is a structure that is used to store all the components that are needed to create an indexer.
+// FinalizedDataProcessor is optional: if provided, the blocks are also kept until they are finalized and are saved
+// with it only after that. If PendingBlocksQueue is provided too, the kept blocks are also written in it, encoded
+// with Codec, so the blocks that were not finalized before a restart are not lost
 type ArgDataIndexer struct {
-	ShardCoordinator ShardCoordinator
-	Marshalizer      marshal.Marshalizer
-	DataDispatcher   DispatcherHandler
-	ElasticProcessor ElasticProcessor
+	ShardCoordinator       ShardCoordinator
+	Marshalizer            marshal.Marshalizer
+	DataDispatcher         DispatcherHandler
+	ElasticProcessor       ElasticProcessor
+	FinalizedDataProcessor ElasticProcessor
+	PendingBlocksQueue     PersistentQueueHandler
+	Codec                  PayloadCodec
 }
 
 type dataIndexer struct {
-	isNilIndexer     bool
-	dispatcher       DispatcherHandler
-	elasticProcessor ElasticProcessor
-	marshalizer      marshal.Marshalizer
+	isNilIndexer           bool
+	dispatcher             DispatcherHandler
+	elasticProcessor       ElasticProcessor
+	finalizedDataProcessor ElasticProcessor
+	marshalizer            marshal.Marshalizer
+	shardCoordinator       ShardCoordinator
+	pendingBlocks          *pendingBlocks
 }
 
 // NewDataIndexer will create a new data indexer
@@ -36,6 +49,16 @@ func NewDataIndexer(arguments ArgDataIndexer) (*dataIndexer, error) {
 		dispatcher:       arguments.DataDispatcher,
 		elasticProcessor: arguments.ElasticProcessor,
 		marshalizer:      arguments.Marshalizer,
+		shardCoordinator: arguments.ShardCoordinator,
+	}
+	if check.IfNil(arguments.FinalizedDataProcessor) {
+		return dataIndexerObj, nil
+	}
+
+	dataIndexerObj.finalizedDataProcessor = arguments.FinalizedDataProcessor
+	dataIndexerObj.pendingBlocks, err = newPendingBlocks(arguments.PendingBlocksQueue, arguments.Codec)
+	if err != nil {
+		return nil, err
 	}
 
 	return dataIndexerObj, nil
@@ -60,19 +83,55 @@ func checkIndexerArgs(arguments ArgDataIndexer) error {
 
 // SaveBlock saves the block info in the queue to be sent to elastic
 func (di *dataIndexer) SaveBlock(args *indexer.ArgsSaveBlockData) error {
+	// the block is kept before it is added, so it is not lost if the node stops in between
+	pendingID, isPending, err := di.addPendingBlock(args)
+	if err != nil {
+		return err
+	}
+
 	wi := workItems.NewItemBlock(
 		di.elasticProcessor,
 		di.marshalizer,
 		args,
 	)
-	di.dispatcher.Add(wi)
+	err = di.dispatcher.Add(wi)
+	if err != nil {
+		if isPending {
+			di.pendingBlocks.remove(pendingID)
+		}
+		return err
+	}
 
 	return nil
 }
 
+// addPendingBlock will keep the block until it is finalized, if the finality mode is enabled
+func (di *dataIndexer) addPendingBlock(args *indexer.ArgsSaveBlockData) (uint64, bool, error) {
+	if di.pendingBlocks == nil || args == nil || check.IfNil(args.Header) {
+		return 0, false, nil
+	}
+
+	id, err := di.pendingBlocks.add(args)
+	if err != nil {
+		return 0, false, err
+	}
+
+	return id, true, nil
+}
+
 // Close will stop goroutine that index data in database
 func (di *dataIndexer) Close() error {
-	return di.dispatcher.Close()
+	err := di.dispatcher.Close()
+	if di.pendingBlocks == nil {
+		return err
+	}
+
+	errPendingBlocks := di.pendingBlocks.close()
+	if err != nil {
+		return err
+	}
+
+	return errPendingBlocks
 }
 
 // RevertIndexedBlock will remove from database block and miniblocks
@@ -82,11 +141,40 @@ func (di *dataIndexer) RevertIndexedBlock(header coreData.HeaderHandler, body co
 		body,
 		header,
 	)
-	di.dispatcher.Add(wi)
+	err := di.dispatcher.Add(wi)
+	if err != nil {
+		return err
+	}
+
+	di.removePendingBlocks(header)
 
 	return nil
 }
 
+// removePendingBlocks will drop the not finalized block of the reverted header and the blocks built on top of it, as
+// they will never be finalized. The blocks are matched by their marshalled header, as the hash is not provided
+func (di *dataIndexer) removePendingBlocks(header coreData.HeaderHandler) {
+	if di.pendingBlocks == nil || check.IfNil(header) {
+		return
+	}
+
+	revertedHeader, err := di.marshalizer.Marshal(header)
+	if err != nil {
+		log.Warn("dataIndexer.RevertIndexedBlock: cannot marshal the reverted header, the kept blocks are not changed",
+			"nonce", header.GetNonce(), "error", err.Error())
+		return
+	}
+
+	di.pendingBlocks.removeReverted(func(keptHeader coreData.HeaderHandler) bool {
+		if keptHeader.GetNonce() != header.GetNonce() {
+			return false
+		}
+
+		marshalledHeader, errMarshal := di.marshalizer.Marshal(keptHeader)
+		return errMarshal == nil && bytes.Equal(marshalledHeader, revertedHeader)
+	})
+}
+
 // SaveRoundsInfo will save data about a slice of rounds in elasticsearch
 func (di *dataIndexer) SaveRoundsInfo(rf []*indexer.RoundInfo) error {
 	roundsInfo := make([]*data.RoundInfo, 0)
@@ -102,9 +190,7 @@ func (di *dataIndexer) SaveRoundsInfo(rf []*indexer.RoundInfo) error {
 	}
 
 	wi := workItems.NewItemRounds(di.elasticProcessor, roundsInfo)
-	di.dispatcher.Add(wi)
-
-	return nil
+	return di.dispatcher.Add(wi)
 }
 
 // SaveValidatorsRating will save all validators rating info to elasticsearch
@@ -122,9 +208,7 @@ func (di *dataIndexer) SaveValidatorsRating(indexID string, validatorsRatingInfo
 		indexID,
 		valRatingInfo,
 	)
-	di.dispatcher.Add(wi)
-
-	return nil
+	return di.dispatcher.Add(wi)
 }
 
 // SaveValidatorsPubKeys will save all validators public keys to elasticsearch
@@ -134,24 +218,70 @@ func (di *dataIndexer) SaveValidatorsPubKeys(validatorsPubKeys map[uint32][][]by
 		epoch,
 		validatorsPubKeys,
 	)
-	di.dispatcher.Add(wi)
-
-	return nil
+	return di.dispatcher.Add(wi)
 }
 
 // SaveAccounts will save the provided accounts
 func (di *dataIndexer) SaveAccounts(timestamp uint64, accounts []coreData.UserAccountHandler) error {
 	wi := workItems.NewItemAccounts(di.elasticProcessor, timestamp, accounts)
-	di.dispatcher.Add(wi)
+	return di.dispatcher.Add(wi)
+}
 
-	return nil
+// FinalizedBlock will mark as final the already indexed data of the block with the provided hash and of the
+// blocks before it. If the finality mode is enabled, the kept blocks up to the finalized one are saved as well
+func (di *dataIndexer) FinalizedBlock(headerHash []byte) error {
+	err := di.addFinalizedBlocks(headerHash)
+	if err != nil {
+		return err
+	}
+
+	wi := workItems.NewItemFinalizedBlock(di.elasticProcessor, headerHash)
+	return di.dispatcher.Add(wi)
 }
 
-// FinalizedBlock returns nil
-func (di *dataIndexer) FinalizedBlock(_ []byte) error {
+// addFinalizedBlocks will add, sorted by nonce, the kept block with the provided hash and the kept blocks it is built
+// on top of. A block is dropped only after it was added, while the kept blocks of the abandoned forks up to the
+// finalized nonce are dropped without being saved
+func (di *dataIndexer) addFinalizedBlocks(headerHash []byte) error {
+	if di.pendingBlocks == nil {
+		return nil
+	}
+
+	finalizedBlocks, abandonedBlocks := di.pendingBlocks.finalized(headerHash)
+	for _, block := range finalizedBlocks {
+		wi := workItems.NewItemFinalizedBlockData(di.finalizedDataProcessor, di.marshalizer, block.args)
+		err := di.dispatcher.Add(wi)
+		if err != nil {
+			return err
+		}
+
+		di.pendingBlocks.remove(block.id)
+	}
+
+	abandonedIDs := make([]uint64, 0, len(abandonedBlocks))
+	for _, block := range abandonedBlocks {
+		log.Debug("dataIndexer.FinalizedBlock: dropping block of an abandoned fork",
+			"hash", hex.EncodeToString(block.args.HeaderHash), "nonce", block.args.Header.GetNonce())
+		abandonedIDs = append(abandonedIDs, block.id)
+	}
+	di.pendingBlocks.remove(abandonedIDs...)
+
 	return nil
 }
 
+// GetCheckpoints returns the last indexed block of every shard, including the metachain. The shards that have no
+// indexed block are skipped
+func (di *dataIndexer) GetCheckpoints() ([]*data.Checkpoint, error) {
+	numShards := di.shardCoordinator.NumberOfShards()
+	shardIDs := make([]uint32, 0, numShards+1)
+	for shardID := uint32(0); shardID < numShards; shardID++ {
+		shardIDs = append(shardIDs, shardID)
+	}
+	shardIDs = append(shardIDs, core.MetachainShardId)
+
+	return di.elasticProcessor.GetCheckpoints(shardIDs)
+}
+
 // IsNilIndexer will return a bool value that signals if the indexer's implementation is a NilIndexer
 func (di *dataIndexer) IsNilIndexer() bool {
 	return di.isNilIndexer
@@ -1,13 +1,17 @@
 package indexer
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/ME-MotherEarth/me-core/core"
 	"github.com/ME-MotherEarth/me-core/core/check"
 	dataBlock "github.com/ME-MotherEarth/me-core/data/block"
 	"github.com/ME-MotherEarth/me-core/data/indexer"
+	"github.com/ME-MotherEarth/me-elastic-indexer/data"
 	"github.com/ME-MotherEarth/me-elastic-indexer/mock"
+	"github.com/ME-MotherEarth/me-elastic-indexer/payload"
+	"github.com/ME-MotherEarth/me-elastic-indexer/queue"
 	"github.com/ME-MotherEarth/me-elastic-indexer/workItems"
 	"github.com/stretchr/testify/require"
 )
@@ -63,8 +67,9 @@ func TestDataIndexer_SaveBlock(t *testing.T) {
 
 	arguments := NewDataIndexerArguments()
 	arguments.DataDispatcher = &mock.DispatcherMock{
-		AddCalled: func(item workItems.WorkItemHandler) {
+		AddCalled: func(item workItems.WorkItemHandler) error {
 			called = true
+			return nil
 		},
 	}
 	ei, _ := NewDataIndexer(arguments)
@@ -82,13 +87,32 @@ func TestDataIndexer_SaveBlock(t *testing.T) {
 	require.Nil(t, err)
 }
 
+func TestDataIndexer_SaveBlockShouldErrIfItemCannotBeAdded(t *testing.T) {
+	expectedErr := errors.New("expected error")
+
+	arguments := NewDataIndexerArguments()
+	arguments.DataDispatcher = &mock.DispatcherMock{
+		AddCalled: func(item workItems.WorkItemHandler) error {
+			return expectedErr
+		},
+	}
+	ei, _ := NewDataIndexer(arguments)
+
+	err := ei.SaveBlock(&indexer.ArgsSaveBlockData{
+		HeaderHash: []byte("hash"),
+		Body:       &dataBlock.Body{},
+	})
+	require.Equal(t, expectedErr, err)
+}
+
 func TestDataIndexer_SaveRoundInfo(t *testing.T) {
 	called := false
 
 	arguments := NewDataIndexerArguments()
 	arguments.DataDispatcher = &mock.DispatcherMock{
-		AddCalled: func(item workItems.WorkItemHandler) {
+		AddCalled: func(item workItems.WorkItemHandler) error {
 			called = true
+			return nil
 		},
 	}
 
@@ -106,8 +130,9 @@ func TestDataIndexer_SaveValidatorsPubKeys(t *testing.T) {
 
 	arguments := NewDataIndexerArguments()
 	arguments.DataDispatcher = &mock.DispatcherMock{
-		AddCalled: func(item workItems.WorkItemHandler) {
+		AddCalled: func(item workItems.WorkItemHandler) error {
 			called = true
+			return nil
 		},
 	}
 	ei, _ := NewDataIndexer(arguments)
@@ -128,8 +153,9 @@ func TestDataIndexer_SaveValidatorsRating(t *testing.T) {
 
 	arguments := NewDataIndexerArguments()
 	arguments.DataDispatcher = &mock.DispatcherMock{
-		AddCalled: func(item workItems.WorkItemHandler) {
+		AddCalled: func(item workItems.WorkItemHandler) error {
 			called = true
+			return nil
 		},
 	}
 	ei, _ := NewDataIndexer(arguments)
@@ -146,8 +172,9 @@ func TestDataIndexer_RevertIndexedBlock(t *testing.T) {
 
 	arguments := NewDataIndexerArguments()
 	arguments.DataDispatcher = &mock.DispatcherMock{
-		AddCalled: func(item workItems.WorkItemHandler) {
+		AddCalled: func(item workItems.WorkItemHandler) error {
 			called = true
+			return nil
 		},
 	}
 	ei, _ := NewDataIndexer(arguments)
@@ -156,3 +183,260 @@ func TestDataIndexer_RevertIndexedBlock(t *testing.T) {
 	require.True(t, called)
 	require.Nil(t, err)
 }
+
+func TestDataIndexer_FinalizedBlock(t *testing.T) {
+	addedItems := make([]workItems.WorkItemHandler, 0)
+
+	arguments := NewDataIndexerArguments()
+	arguments.DataDispatcher = &mock.DispatcherMock{
+		AddCalled: func(item workItems.WorkItemHandler) error {
+			addedItems = append(addedItems, item)
+			return nil
+		},
+	}
+	ei, _ := NewDataIndexer(arguments)
+
+	err := ei.FinalizedBlock([]byte("hash"))
+	require.Nil(t, err)
+	require.Equal(t, []workItems.WorkItemHandler{workItems.NewItemFinalizedBlock(arguments.ElasticProcessor, []byte("hash"))}, addedItems)
+}
+
+func TestDataIndexer_FinalizedBlockShouldSaveKeptBlocksUpToTheFinalizedOne(t *testing.T) {
+	addedItems := make([]workItems.WorkItemHandler, 0)
+
+	arguments := NewDataIndexerArguments()
+	arguments.FinalizedDataProcessor = &mock.ElasticProcessorStub{}
+	arguments.DataDispatcher = &mock.DispatcherMock{
+		AddCalled: func(item workItems.WorkItemHandler) error {
+			addedItems = append(addedItems, item)
+			return nil
+		},
+	}
+	ei, _ := NewDataIndexer(arguments)
+
+	blocks := []*indexer.ArgsSaveBlockData{
+		{HeaderHash: []byte("h1"), Header: &dataBlock.Header{Nonce: 1}, Body: &dataBlock.Body{}},
+		{HeaderHash: []byte("h2"), Header: &dataBlock.Header{Nonce: 2, PrevHash: []byte("h1")}, Body: &dataBlock.Body{}},
+		{HeaderHash: []byte("h3"), Header: &dataBlock.Header{Nonce: 3, PrevHash: []byte("h2")}, Body: &dataBlock.Body{}},
+		{HeaderHash: []byte("h3-fork"), Header: &dataBlock.Header{Nonce: 3, Round: 4, PrevHash: []byte("h2")}, Body: &dataBlock.Body{}},
+	}
+	for _, args := range blocks[:3] {
+		_ = ei.SaveBlock(args)
+	}
+	_ = ei.RevertIndexedBlock(blocks[2].Header, blocks[2].Body)
+	_ = ei.SaveBlock(blocks[3])
+	require.Len(t, addedItems, 5)
+
+	addedItems = addedItems[:0]
+	err := ei.FinalizedBlock([]byte("h2"))
+	require.Nil(t, err)
+	require.Equal(t, []workItems.WorkItemHandler{
+		workItems.NewItemFinalizedBlockData(arguments.FinalizedDataProcessor, arguments.Marshalizer, blocks[0]),
+		workItems.NewItemFinalizedBlockData(arguments.FinalizedDataProcessor, arguments.Marshalizer, blocks[1]),
+		workItems.NewItemFinalizedBlock(arguments.ElasticProcessor, []byte("h2")),
+	}, addedItems)
+
+	addedItems = addedItems[:0]
+	err = ei.FinalizedBlock([]byte("h3-fork"))
+	require.Nil(t, err)
+	require.Equal(t, []workItems.WorkItemHandler{
+		workItems.NewItemFinalizedBlockData(arguments.FinalizedDataProcessor, arguments.Marshalizer, blocks[3]),
+		workItems.NewItemFinalizedBlock(arguments.ElasticProcessor, []byte("h3-fork")),
+	}, addedItems)
+}
+
+func TestDataIndexer_FinalizedBlockShouldDropTheBlocksOfAbandonedForks(t *testing.T) {
+	addedItems := make([]workItems.WorkItemHandler, 0)
+
+	arguments := NewDataIndexerArguments()
+	arguments.FinalizedDataProcessor = &mock.ElasticProcessorStub{}
+	arguments.DataDispatcher = &mock.DispatcherMock{
+		AddCalled: func(item workItems.WorkItemHandler) error {
+			addedItems = append(addedItems, item)
+			return nil
+		},
+	}
+	ei, _ := NewDataIndexer(arguments)
+
+	for _, args := range []*indexer.ArgsSaveBlockData{
+		{HeaderHash: []byte("h1"), Header: &dataBlock.Header{Nonce: 1}, Body: &dataBlock.Body{}},
+		{HeaderHash: []byte("h2-fork"), Header: &dataBlock.Header{Nonce: 2, Round: 2, PrevHash: []byte("h1")}, Body: &dataBlock.Body{}},
+		{HeaderHash: []byte("h2"), Header: &dataBlock.Header{Nonce: 2, Round: 3, PrevHash: []byte("h1")}, Body: &dataBlock.Body{}},
+		{HeaderHash: []byte("h3"), Header: &dataBlock.Header{Nonce: 3, Round: 4, PrevHash: []byte("h2")}, Body: &dataBlock.Body{}},
+		{HeaderHash: []byte("h4"), Header: &dataBlock.Header{Nonce: 4, Round: 5, PrevHash: []byte("h3")}, Body: &dataBlock.Body{}},
+	} {
+		_ = ei.SaveBlock(args)
+	}
+
+	addedItems = addedItems[:0]
+	err := ei.FinalizedBlock([]byte("h3"))
+	require.Nil(t, err)
+	require.Equal(t, []string{"h1", "h2", "h3", "h3"}, addedHeaderHashes(addedItems))
+
+	// the sibling was dropped, so it is not saved when a later block is finalized
+	addedItems = addedItems[:0]
+	err = ei.FinalizedBlock([]byte("h4"))
+	require.Nil(t, err)
+	require.Equal(t, []string{"h4", "h4"}, addedHeaderHashes(addedItems))
+}
+
+func TestDataIndexer_RevertIndexedBlockShouldDropTheRevertedBlockAndTheBlocksBuiltOnIt(t *testing.T) {
+	addedItems := make([]workItems.WorkItemHandler, 0)
+
+	arguments := NewDataIndexerArguments()
+	arguments.FinalizedDataProcessor = &mock.ElasticProcessorStub{}
+	arguments.DataDispatcher = &mock.DispatcherMock{
+		AddCalled: func(item workItems.WorkItemHandler) error {
+			addedItems = append(addedItems, item)
+			return nil
+		},
+	}
+	ei, _ := NewDataIndexer(arguments)
+
+	blocks := []*indexer.ArgsSaveBlockData{
+		{HeaderHash: []byte("h1"), Header: &dataBlock.Header{Nonce: 1}, Body: &dataBlock.Body{}},
+		{HeaderHash: []byte("h2"), Header: &dataBlock.Header{Nonce: 2, Round: 2, PrevHash: []byte("h1")}, Body: &dataBlock.Body{}},
+		{HeaderHash: []byte("h2-sibling"), Header: &dataBlock.Header{Nonce: 2, Round: 3, PrevHash: []byte("h1")}, Body: &dataBlock.Body{}},
+		{HeaderHash: []byte("h3"), Header: &dataBlock.Header{Nonce: 3, Round: 4, PrevHash: []byte("h2")}, Body: &dataBlock.Body{}},
+	}
+	for _, args := range blocks {
+		_ = ei.SaveBlock(args)
+	}
+	_ = ei.RevertIndexedBlock(&dataBlock.Header{Nonce: 2, Round: 2, PrevHash: []byte("h1")}, &dataBlock.Body{})
+	require.Len(t, ei.pendingBlocks.blocks, 2)
+
+	addedItems = addedItems[:0]
+	err := ei.FinalizedBlock([]byte("h2-sibling"))
+	require.Nil(t, err)
+	require.Equal(t, []string{"h1", "h2-sibling", "h2-sibling"}, addedHeaderHashes(addedItems))
+	require.Empty(t, ei.pendingBlocks.blocks)
+}
+
+func addedHeaderHashes(items []workItems.WorkItemHandler) []string {
+	hashes := make([]string, 0, len(items))
+	for _, item := range items {
+		p := item.(workItems.PersistableWorkItemHandler).Payload()
+		if p.ArgsSaveBlock != nil {
+			hashes = append(hashes, string(p.ArgsSaveBlock.HeaderHash))
+			continue
+		}
+		hashes = append(hashes, string(p.HeaderHash))
+	}
+
+	return hashes
+}
+
+func TestDataIndexer_KeptBlocksShouldBeRestoredAfterRestart(t *testing.T) {
+	dir := t.TempDir()
+	codec, _ := payload.NewCodec(&mock.MarshalizerMock{})
+	addedItems := make([]workItems.WorkItemHandler, 0)
+
+	createIndexer := func() *dataIndexer {
+		diskQueue, err := queue.NewDiskQueue(dir)
+		require.Nil(t, err)
+
+		arguments := NewDataIndexerArguments()
+		arguments.FinalizedDataProcessor = &mock.ElasticProcessorStub{}
+		arguments.PendingBlocksQueue = diskQueue
+		arguments.Codec = codec
+		arguments.DataDispatcher = &mock.DispatcherMock{
+			AddCalled: func(item workItems.WorkItemHandler) error {
+				addedItems = append(addedItems, item)
+				return nil
+			},
+		}
+		ei, err := NewDataIndexer(arguments)
+		require.Nil(t, err)
+
+		return ei
+	}
+
+	ei := createIndexer()
+	for nonce, hash := range []string{"h1", "h2", "h3"} {
+		err := ei.SaveBlock(&indexer.ArgsSaveBlockData{
+			HeaderHash: []byte(hash),
+			Header:     &dataBlock.Header{Nonce: uint64(nonce + 1)},
+			Body:       &dataBlock.Body{},
+		})
+		require.Nil(t, err)
+	}
+	require.Nil(t, ei.FinalizedBlock([]byte("h1")))
+	require.Nil(t, ei.Close())
+
+	ei = createIndexer()
+	addedItems = addedItems[:0]
+	require.Nil(t, ei.FinalizedBlock([]byte("h2")))
+	require.Equal(t, []string{"h2", "h2"}, addedHeaderHashes(addedItems))
+	require.Nil(t, ei.Close())
+
+	ei = createIndexer()
+	addedItems = addedItems[:0]
+	require.Nil(t, ei.FinalizedBlock([]byte("h3")))
+	require.Equal(t, []string{"h3", "h3"}, addedHeaderHashes(addedItems))
+	require.Nil(t, ei.Close())
+}
+
+func TestDataIndexer_SaveBlockShouldNotKeepTheBlockIfItCannotBeAdded(t *testing.T) {
+	diskQueue, _ := queue.NewDiskQueue(t.TempDir())
+	codec, _ := payload.NewCodec(&mock.MarshalizerMock{})
+
+	arguments := NewDataIndexerArguments()
+	arguments.FinalizedDataProcessor = &mock.ElasticProcessorStub{}
+	arguments.PendingBlocksQueue = diskQueue
+	arguments.Codec = codec
+	arguments.DataDispatcher = &mock.DispatcherMock{
+		AddCalled: func(item workItems.WorkItemHandler) error {
+			return errors.New("local error")
+		},
+	}
+	ei, _ := NewDataIndexer(arguments)
+
+	err := ei.SaveBlock(&indexer.ArgsSaveBlockData{
+		HeaderHash: []byte("h1"),
+		Header:     &dataBlock.Header{Nonce: 1},
+		Body:       &dataBlock.Body{},
+	})
+	require.NotNil(t, err)
+	require.Zero(t, diskQueue.Len())
+}
+
+func TestNewDataIndexer_InvalidKeptBlockShouldBeMovedInQuarantine(t *testing.T) {
+	dir := t.TempDir()
+	diskQueue, _ := queue.NewDiskQueue(dir)
+	_, _ = diskQueue.Append([]byte("invalid block"))
+	_ = diskQueue.Close()
+
+	diskQueue, _ = queue.NewDiskQueue(dir)
+	codec, _ := payload.NewCodec(&mock.MarshalizerMock{})
+	arguments := NewDataIndexerArguments()
+	arguments.FinalizedDataProcessor = &mock.ElasticProcessorStub{}
+	arguments.PendingBlocksQueue = diskQueue
+	arguments.Codec = codec
+	_, err := NewDataIndexer(arguments)
+	require.Nil(t, err)
+	require.Zero(t, diskQueue.Len())
+
+	arguments.Codec = nil
+	_, err = NewDataIndexer(arguments)
+	require.Equal(t, ErrNilPayloadCodec, err)
+}
+
+func TestDataIndexer_GetCheckpointsShouldRequestAllShards(t *testing.T) {
+	checkpoints := []*data.Checkpoint{{ShardID: 1, Nonce: 10}}
+	requestedShards := make([]uint32, 0)
+
+	arguments := NewDataIndexerArguments()
+	arguments.ShardCoordinator = &mock.ShardCoordinatorMock{NumOfShards: 2}
+	arguments.ElasticProcessor = &mock.ElasticProcessorStub{
+		GetCheckpointsCalled: func(shardIDs []uint32) ([]*data.Checkpoint, error) {
+			requestedShards = shardIDs
+			return checkpoints, nil
+		},
+	}
+	ei, _ := NewDataIndexer(arguments)
+
+	result, err := ei.GetCheckpoints()
+	require.Nil(t, err)
+	require.Equal(t, checkpoints, result)
+	require.Equal(t, []uint32{0, 1, core.MetachainShardId}, requestedShards)
+}
@@ -0,0 +1,15 @@
+package deadletter
+
+import "errors"
+
+// ErrEmptyDirectory signals that an empty directory path has been provided
+var ErrEmptyDirectory = errors.New("empty dead letters directory")
+
+// ErrNilPayloadEncoder signals that a nil payload encoder has been provided
+var ErrNilPayloadEncoder = errors.New("nil payload encoder")
+
+// ErrStoreClosed signals that an operation was attempted on a closed store
+var ErrStoreClosed = errors.New("dead letters store is closed")
+
+// ErrNilQueue signals that a nil queue has been provided
+var ErrNilQueue = errors.New("nil queue")
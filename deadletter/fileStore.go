@@ -0,0 +1,173 @@
+package deadletter
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/ME-MotherEarth/me-core/core/check"
+	"github.com/ME-MotherEarth/me-elastic-indexer/data"
+	"github.com/ME-MotherEarth/me-elastic-indexer/payload"
+	logger "github.com/ME-MotherEarth/me-logger"
+)
+
+const (
+	// FileName is the name of the file created in the dead letters directory
+	FileName = "deadletters.jsonl"
+
+	filePermission = 0644
+	dirPermission  = 0755
+)
+
+var log = logger.GetOrCreate("indexer/deadletter")
+
+// Letter holds a work item that could not be saved, together with the reason. The payload is encoded in the same
+// format used by the persistent queue, so the letter can be replayed through it. The letters of the documents rejected
+// by the database hold only the documents, as the rest of their item was saved
+type Letter struct {
+	Timestamp  int64                    `json:"timestamp"`
+	ItemType   string                   `json:"itemType"`
+	HeaderHash string                   `json:"headerHash,omitempty"`
+	Nonce      uint64                   `json:"nonce,omitempty"`
+	Error      string                   `json:"error"`
+	Payload    []byte                   `json:"payload,omitempty"`
+	Documents  []*data.RejectedDocument `json:"documents,omitempty"`
+}
+
+// ArgsFileStore holds all dependencies required by the file store in order to create new instances
+type ArgsFileStore struct {
+	Directory string
+	Encoder   PayloadEncoder
+}
+
+type fileStore struct {
+	mutex   sync.Mutex
+	file    *os.File
+	encoder PayloadEncoder
+	closed  bool
+}
+
+// NewFileStore will create a dead letters store that appends every letter, as a JSON line, in a file placed in the
+// provided directory
+func NewFileStore(args ArgsFileStore) (*fileStore, error) {
+	if args.Directory == "" {
+		return nil, ErrEmptyDirectory
+	}
+	if check.IfNil(args.Encoder) {
+		return nil, ErrNilPayloadEncoder
+	}
+
+	err := os.MkdirAll(args.Directory, dirPermission)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(filepath.Join(args.Directory, FileName), os.O_CREATE|os.O_APPEND|os.O_WRONLY, filePermission)
+	if err != nil {
+		return nil, err
+	}
+
+	return &fileStore{
+		file:    file,
+		encoder: args.Encoder,
+	}, nil
+}
+
+// Put will persist the work item that could not be saved. The payload is nil for the items that cannot be
+// persisted, in which case only the reason is kept and the letter cannot be replayed
+func (fs *fileStore) Put(itemType string, p *payload.Payload, reason error) error {
+	letter := &Letter{
+		Timestamp: time.Now().Unix(),
+		ItemType:  itemType,
+	}
+	if reason != nil {
+		letter.Error = reason.Error()
+	}
+
+	if p != nil {
+		headerHash, nonce := blockOf(p)
+		letter.HeaderHash = hex.EncodeToString(headerHash)
+		letter.Nonce = nonce
+
+		encodedPayload, err := fs.encoder.Encode(p)
+		if err != nil {
+			return err
+		}
+		letter.Payload = encodedPayload
+	}
+
+	return fs.write(letter)
+}
+
+// PutRejectedDocuments will persist the documents of a work item that the database refused to write, together with
+// the block of the item, if any. The payload is not kept, as replaying the whole item would apply its other
+// documents twice
+func (fs *fileStore) PutRejectedDocuments(itemType string, p *payload.Payload, documents []*data.RejectedDocument) error {
+	letter := &Letter{
+		Timestamp: time.Now().Unix(),
+		ItemType:  itemType,
+		Error:     (&data.RejectedDocumentsError{Documents: documents}).Error(),
+		Documents: documents,
+	}
+	if p != nil {
+		headerHash, nonce := blockOf(p)
+		letter.HeaderHash = hex.EncodeToString(headerHash)
+		letter.Nonce = nonce
+	}
+
+	return fs.write(letter)
+}
+
+func (fs *fileStore) write(letter *Letter) error {
+	line, err := json.Marshal(letter)
+	if err != nil {
+		return err
+	}
+
+	fs.mutex.Lock()
+	defer fs.mutex.Unlock()
+
+	if fs.closed {
+		return ErrStoreClosed
+	}
+
+	_, err = fs.file.Write(append(line, '\n'))
+	if err != nil {
+		return err
+	}
+
+	return fs.file.Sync()
+}
+
+// blockOf returns the hash and the nonce of the block the payload refers to, if any
+func blockOf(p *payload.Payload) ([]byte, uint64) {
+	switch {
+	case p.ArgsSaveBlock != nil && !check.IfNil(p.ArgsSaveBlock.Header):
+		return p.ArgsSaveBlock.HeaderHash, p.ArgsSaveBlock.Header.GetNonce()
+	case !check.IfNil(p.Header):
+		return p.HeaderHash, p.Header.GetNonce()
+	default:
+		return p.HeaderHash, 0
+	}
+}
+
+// Close will close the underlying file
+func (fs *fileStore) Close() error {
+	fs.mutex.Lock()
+	defer fs.mutex.Unlock()
+
+	if fs.closed {
+		return nil
+	}
+	fs.closed = true
+
+	return fs.file.Close()
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (fs *fileStore) IsInterfaceNil() bool {
+	return fs == nil
+}
@@ -0,0 +1,138 @@
+package deadletter_test
+
+import (
+	"encoding/hex"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/ME-MotherEarth/me-core/data/block"
+	"github.com/ME-MotherEarth/me-core/data/indexer"
+	"github.com/ME-MotherEarth/me-elastic-indexer/data"
+	"github.com/ME-MotherEarth/me-elastic-indexer/deadletter"
+	"github.com/ME-MotherEarth/me-elastic-indexer/mock"
+	"github.com/ME-MotherEarth/me-elastic-indexer/payload"
+	"github.com/stretchr/testify/require"
+)
+
+func createMockArgsFileStore(t *testing.T) deadletter.ArgsFileStore {
+	codec, _ := payload.NewCodec(&mock.MarshalizerMock{})
+
+	return deadletter.ArgsFileStore{
+		Directory: t.TempDir(),
+		Encoder:   codec,
+	}
+}
+
+func TestNewFileStore(t *testing.T) {
+	t.Parallel()
+
+	args := createMockArgsFileStore(t)
+	args.Directory = ""
+	store, err := deadletter.NewFileStore(args)
+	require.Nil(t, store)
+	require.Equal(t, deadletter.ErrEmptyDirectory, err)
+
+	args = createMockArgsFileStore(t)
+	args.Encoder = nil
+	store, err = deadletter.NewFileStore(args)
+	require.Nil(t, store)
+	require.Equal(t, deadletter.ErrNilPayloadEncoder, err)
+
+	store, err = deadletter.NewFileStore(createMockArgsFileStore(t))
+	require.Nil(t, err)
+	require.False(t, store.IsInterfaceNil())
+	require.Nil(t, store.Close())
+}
+
+func TestFileStore_PutAndReadLetters(t *testing.T) {
+	t.Parallel()
+
+	args := createMockArgsFileStore(t)
+	store, _ := deadletter.NewFileStore(args)
+
+	headerHash := []byte("header hash")
+	err := store.Put("itemBlock", &payload.Payload{
+		Type: payload.SaveBlock,
+		ArgsSaveBlock: &indexer.ArgsSaveBlockData{
+			HeaderHash: headerHash,
+			Header:     &block.Header{Nonce: 10},
+			Body:       &block.Body{},
+		},
+	}, errors.New("mapper_parsing_exception"))
+	require.Nil(t, err)
+
+	err = store.Put("itemRounds", &payload.Payload{
+		Type:       payload.SaveRoundsInfo,
+		RoundsInfo: []*data.RoundInfo{{Index: 5}},
+	}, errors.New("local error"))
+	require.Nil(t, err)
+
+	err = store.Put("itemStub", nil, errors.New("not persistable"))
+	require.Nil(t, err)
+
+	require.Nil(t, store.Close())
+	err = store.Put("itemStub", nil, errors.New("after close"))
+	require.Equal(t, deadletter.ErrStoreClosed, err)
+
+	letters, err := deadletter.ReadLetters(filepath.Join(args.Directory, deadletter.FileName))
+	require.Nil(t, err)
+	require.Len(t, letters, 3)
+
+	require.Equal(t, "itemBlock", letters[0].ItemType)
+	require.Equal(t, hex.EncodeToString(headerHash), letters[0].HeaderHash)
+	require.Equal(t, uint64(10), letters[0].Nonce)
+	require.Equal(t, "mapper_parsing_exception", letters[0].Error)
+	require.NotEmpty(t, letters[0].Payload)
+
+	codec, _ := payload.NewCodec(&mock.MarshalizerMock{})
+	restored, err := codec.Decode(letters[1].Payload)
+	require.Nil(t, err)
+	require.Equal(t, payload.SaveRoundsInfo, restored.Type)
+	require.Equal(t, uint64(5), restored.RoundsInfo[0].Index)
+
+	require.Equal(t, "itemStub", letters[2].ItemType)
+	require.Empty(t, letters[2].Payload)
+}
+
+func TestFileStore_PutRejectedDocuments(t *testing.T) {
+	t.Parallel()
+
+	args := createMockArgsFileStore(t)
+	store, _ := deadletter.NewFileStore(args)
+
+	documents := []*data.RejectedDocument{{
+		Index:     "transactions",
+		ID:        "tx1",
+		Status:    400,
+		ErrorType: "mapper_parsing_exception",
+		Reason:    "failed to parse",
+	}}
+	headerHash := []byte("header hash")
+	err := store.PutRejectedDocuments("itemBlock", &payload.Payload{
+		Type: payload.SaveBlock,
+		ArgsSaveBlock: &indexer.ArgsSaveBlockData{
+			HeaderHash: headerHash,
+			Header:     &block.Header{Nonce: 10},
+			Body:       &block.Body{},
+		},
+	}, documents)
+	require.Nil(t, err)
+
+	err = store.PutRejectedDocuments("itemRounds", nil, documents)
+	require.Nil(t, err)
+	require.Nil(t, store.Close())
+
+	letters, err := deadletter.ReadLetters(filepath.Join(args.Directory, deadletter.FileName))
+	require.Nil(t, err)
+	require.Len(t, letters, 2)
+
+	require.Equal(t, hex.EncodeToString(headerHash), letters[0].HeaderHash)
+	require.Equal(t, uint64(10), letters[0].Nonce)
+	require.Contains(t, letters[0].Error, "mapper_parsing_exception")
+	require.Empty(t, letters[0].Payload)
+	require.Equal(t, documents, letters[0].Documents)
+
+	require.Empty(t, letters[1].HeaderHash)
+	require.Equal(t, documents, letters[1].Documents)
+}
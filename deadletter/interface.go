@@ -0,0 +1,15 @@
+package deadletter
+
+import "github.com/ME-MotherEarth/me-elastic-indexer/payload"
+
+// PayloadEncoder defines what a component that converts payloads in bytes should be able to do
+type PayloadEncoder interface {
+	Encode(p *payload.Payload) ([]byte, error)
+	IsInterfaceNil() bool
+}
+
+// QueueAppender defines what the queue in which the dead letters are replayed should be able to do
+type QueueAppender interface {
+	Append(itemData []byte) (uint64, error)
+	IsInterfaceNil() bool
+}
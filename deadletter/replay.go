@@ -0,0 +1,80 @@
+package deadletter
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+
+	"github.com/ME-MotherEarth/me-core/core/check"
+)
+
+// ReadLetters will return all the letters written in the provided file
+func ReadLetters(filePath string) ([]*Letter, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	letters := make([]*Letter, 0)
+	reader := bufio.NewReader(file)
+	for {
+		line, errRead := reader.ReadBytes('\n')
+		if len(line) > 0 && line[len(line)-1] == '\n' {
+			letter := &Letter{}
+			errUnmarshal := json.Unmarshal(line, letter)
+			if errUnmarshal != nil {
+				return nil, errUnmarshal
+			}
+
+			letters = append(letters, letter)
+		}
+		if errors.Is(errRead, io.EOF) {
+			if len(line) > 0 {
+				log.Warn("deadletter.ReadLetters: the last letter is truncated, it will be ignored", "file", filePath)
+			}
+			return letters, nil
+		}
+		if errRead != nil {
+			return nil, errRead
+		}
+	}
+}
+
+// Replay will append the payloads of the provided letters in the queue, so they are saved again when the
+// persistent dispatcher starts. It returns the number of letters replayed, the ones without payload are skipped. The
+// rejected documents are only reported, they have to be fixed and written by hand
+func Replay(letters []*Letter, queue QueueAppender) (int, error) {
+	if check.IfNil(queue) {
+		return 0, ErrNilQueue
+	}
+
+	numReplayed := 0
+	for _, letter := range letters {
+		if len(letter.Documents) > 0 {
+			for _, document := range letter.Documents {
+				log.Warn("deadletter.Replay: rejected document has to be written by hand",
+					"item type", letter.ItemType, "header hash", letter.HeaderHash, "nonce", letter.Nonce,
+					"index", document.Index, "id", document.ID, "reason", document.Reason)
+			}
+			continue
+		}
+		if len(letter.Payload) == 0 {
+			log.Warn("deadletter.Replay: letter without payload cannot be replayed",
+				"item type", letter.ItemType, "error", letter.Error)
+			continue
+		}
+
+		_, err := queue.Append(letter.Payload)
+		if err != nil {
+			return numReplayed, err
+		}
+		numReplayed++
+	}
+
+	return numReplayed, nil
+}
@@ -0,0 +1,54 @@
+package deadletter_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ME-MotherEarth/me-elastic-indexer/data"
+	"github.com/ME-MotherEarth/me-elastic-indexer/deadletter"
+	"github.com/ME-MotherEarth/me-elastic-indexer/queue"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadLetters_TruncatedLastLetterShouldBeIgnored(t *testing.T) {
+	t.Parallel()
+
+	filePath := filepath.Join(t.TempDir(), deadletter.FileName)
+	err := os.WriteFile(filePath, []byte(`{"itemType":"itemRounds","error":"local error"}`+"\n"+`{"itemType":"item`), 0644)
+	require.Nil(t, err)
+
+	letters, err := deadletter.ReadLetters(filePath)
+	require.Nil(t, err)
+	require.Len(t, letters, 1)
+	require.Equal(t, "local error", letters[0].Error)
+}
+
+func TestReplay(t *testing.T) {
+	t.Parallel()
+
+	numReplayed, err := deadletter.Replay(nil, nil)
+	require.Equal(t, 0, numReplayed)
+	require.Equal(t, deadletter.ErrNilQueue, err)
+
+	dir := t.TempDir()
+	diskQueue, _ := queue.NewDiskQueue(dir)
+	letters := []*deadletter.Letter{
+		{ItemType: "itemRounds", Payload: []byte("first")},
+		{ItemType: "itemStub"},
+		{ItemType: "itemBlock", Documents: []*data.RejectedDocument{{Index: "transactions", ID: "tx1"}}},
+		{ItemType: "itemRounds", Payload: []byte("second")},
+	}
+
+	numReplayed, err = deadletter.Replay(letters, diskQueue)
+	require.Nil(t, err)
+	require.Equal(t, 2, numReplayed)
+	require.Nil(t, diskQueue.Close())
+
+	diskQueue, _ = queue.NewDiskQueue(dir)
+	pending := diskQueue.Pending()
+	require.Len(t, pending, 2)
+	require.Equal(t, []byte("first"), pending[0].Data)
+	require.Equal(t, []byte("second"), pending[1].Data)
+	require.Nil(t, diskQueue.Close())
+}
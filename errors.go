@@ -103,3 +103,69 @@ var ErrNilBalanceConverter = errors.New("nil balance converter")
 
 // ErrNilOperationsHandler signals that a nil operations handler has been provided
 var ErrNilOperationsHandler = errors.New("nil operations handler")
+
+// ErrNilPersistentQueue signals that a nil persistent queue has been provided
+var ErrNilPersistentQueue = errors.New("nil persistent queue")
+
+// ErrNilPayloadCodec signals that a nil payload codec has been provided
+var ErrNilPayloadCodec = errors.New("nil payload codec")
+
+// ErrNilPreparedTransactions signals that nil prepared transactions have been provided
+var ErrNilPreparedTransactions = errors.New("nil prepared transactions")
+
+// ErrNilSQLDatabase signals that a nil sql database has been provided
+var ErrNilSQLDatabase = errors.New("nil sql database")
+
+// ErrNilEventsPublisher signals that a nil events publisher has been provided
+var ErrNilEventsPublisher = errors.New("nil events publisher")
+
+// ErrNilFinalizedDataProcessor signals that a nil processor for the finalized data has been provided
+var ErrNilFinalizedDataProcessor = errors.New("nil finalized data processor")
+
+// ErrIndexCannotWaitForFinality signals that an index that does not hold block data was selected to be written only
+// after the blocks are finalized
+var ErrIndexCannotWaitForFinality = errors.New("index cannot wait for the blocks to be finalized")
+
+// ErrNilMetricsHandler signals that a nil metrics handler has been provided
+var ErrNilMetricsHandler = errors.New("nil metrics handler")
+
+// ErrPermanentFailure signals that the data cannot be saved no matter how many times it is retried
+var ErrPermanentFailure = errors.New("permanent failure")
+
+// ErrMappingsConflict signals that the mappings of some existing fields differ from the ones of the templates
+var ErrMappingsConflict = errors.New("the mappings of the indices conflict with the templates")
+
+// ErrPendingMigrations signals that the schema of the cluster is older than the one written by the indexer
+var ErrPendingMigrations = errors.New("the cluster has pending schema migrations")
+
+// ErrNegativeMaxAttempts signals that a negative maximum number of attempts has been provided
+var ErrNegativeMaxAttempts = errors.New("negative max attempts")
+
+// ErrNilEventProcessor signals that a nil event processor has been provided
+var ErrNilEventProcessor = errors.New("nil event processor")
+
+// ErrInvalidCustomIndex signals that an index declared by an event processor has an invalid name or no template
+var ErrInvalidCustomIndex = errors.New("invalid custom index")
+
+// ErrCustomIndexAlreadyDeclared signals that an index declared by an event processor is already a built-in index or
+// was declared by another event processor
+var ErrCustomIndexAlreadyDeclared = errors.New("custom index already declared")
+
+// ErrNilABIDecoder signals that a nil ABI decoder has been provided
+var ErrNilABIDecoder = errors.New("nil ABI decoder")
+
+// ErrInvalidABI signals that a smart contract ABI file cannot be used to decode the calls and events
+var ErrInvalidABI = errors.New("invalid ABI")
+
+// ErrCannotPersistItem signals that a work item cannot be written in the persistent queue
+var ErrCannotPersistItem = errors.New("cannot persist item")
+
+// ErrDispatcherClosed signals that an item was added after the dispatcher was closed
+var ErrDispatcherClosed = errors.New("dispatcher is closed")
+
+// ErrInvalidPendingBlock signals that a block restored from the queue of the not finalized blocks has no header
+var ErrInvalidPendingBlock = errors.New("invalid pending block")
+
+// ErrIndexNeedsDataTries signals that an enabled index is filled from the data tries of the accounts, which are not
+// written in the persistent queue
+var ErrIndexNeedsDataTries = errors.New("index needs the data tries of the accounts, which are not persisted")
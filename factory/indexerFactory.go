@@ -1,9 +1,12 @@
 package factory
 
 import (
+	"database/sql"
 	"fmt"
 	"math"
 	"net/http"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/ME-MotherEarth/me-core/core"
@@ -12,28 +15,96 @@ import (
 	"github.com/ME-MotherEarth/me-core/marshal"
 	indexer "github.com/ME-MotherEarth/me-elastic-indexer"
 	"github.com/ME-MotherEarth/me-elastic-indexer/client"
+	"github.com/ME-MotherEarth/me-elastic-indexer/client/fileclient"
 	"github.com/ME-MotherEarth/me-elastic-indexer/client/logging"
+	"github.com/ME-MotherEarth/me-elastic-indexer/deadletter"
+	"github.com/ME-MotherEarth/me-elastic-indexer/metrics"
+	"github.com/ME-MotherEarth/me-elastic-indexer/payload"
+	"github.com/ME-MotherEarth/me-elastic-indexer/process/abi"
 	"github.com/ME-MotherEarth/me-elastic-indexer/process/factory"
+	"github.com/ME-MotherEarth/me-elastic-indexer/process/logsevents"
+	"github.com/ME-MotherEarth/me-elastic-indexer/process/sink/elastic"
+	"github.com/ME-MotherEarth/me-elastic-indexer/process/sink/postgres"
+	"github.com/ME-MotherEarth/me-elastic-indexer/process/stream"
+	"github.com/ME-MotherEarth/me-elastic-indexer/queue"
 	logger "github.com/ME-MotherEarth/me-logger"
 	"github.com/elastic/go-elasticsearch/v7"
 )
 
 var log = logger.GetOrCreate("indexer/factory")
 
-// ArgsIndexerFactory holds all dependencies required by the data indexer factory in order to create
-// new instances
+const (
+	// postgresDriverName is the name under which the binary that uses the indexer has to register a PostgreSQL driver
+	postgresDriverName = "postgres"
+	// pendingBlocksDirectory is the directory, placed in the persistent queue directory, where the blocks that wait
+	// to be finalized are kept
+	pendingBlocksDirectory = "pending"
+	// dryRunMaxFileSize is the size after which the dry run client moves to a new file
+	dryRunMaxFileSize = 256 * 1024 * 1024
+)
+
+// finalityIndexes holds the indices that can be written only after the blocks are finalized. The other indices
+// depend on the state of the node at the moment the block is received, so they are always written right away
+var finalityIndexes = map[string]struct{}{
+	indexer.BlockIndex:        {},
+	indexer.MiniblocksIndex:   {},
+	indexer.TransactionsIndex: {},
+	indexer.ScResultsIndex:    {},
+	indexer.ReceiptsIndex:     {},
+	indexer.LogsIndex:         {},
+	indexer.OperationsIndex:   {},
+	indexer.EpochInfoIndex:    {},
+}
+
+// dataTriesIndexes holds the indices filled from the MECT data of the accounts, read from their data tries. The data
+// tries are not written in the persistent queue, so the items replayed after a restart could not fill them
+var dataTriesIndexes = map[string]struct{}{
+	indexer.AccountsMECTIndex:        {},
+	indexer.AccountsMECTHistoryIndex: {},
+	indexer.CollectionsIndex:         {},
+}
+
+// ArgsIndexerFactory holds all dependencies required by the data indexer factory in order to create new instances
 type ArgsIndexerFactory struct {
-	Enabled                  bool
-	UseKibana                bool
-	IsInImportDBMode         bool
-	IndexerCacheSize         int
-	Denomination             int
-	BulkRequestMaxSize       int
-	Url                      string
-	UserName                 string
-	Password                 string
-	TemplatesPath            string
-	EnabledIndexes           []string
+	Enabled          bool
+	UseKibana        bool
+	IsInImportDBMode bool
+	// AllowMappingsConflicts only logs the existing fields whose mapping differs from their template, instead of
+	// stopping the indexer
+	AllowMappingsConflicts bool
+	// CheckMigrations stops the indexer while the cluster has pending schema migrations, applied with the
+	// index-modifier tool
+	CheckMigrations           bool
+	IndexerCacheSize          int
+	Denomination              int
+	BulkRequestMaxSize        int
+	NumConcurrentBulkRequests int
+	// MaxWorkItemAttempts is the number of failed attempts after which an item is moved in the dead letters file
+	MaxWorkItemAttempts int
+	Url                 string
+	UserName            string
+	Password            string
+	TemplatesPath       string
+	// PersistentQueuePath, if set, keeps the received items on disk until they are saved. The blocks that wait to be
+	// finalized are kept on disk too, in the "pending" directory placed in this one. The accountsmect,
+	// accountsmecthistory and collections indices cannot be enabled with it, as the data tries are not kept on disk
+	PersistentQueuePath string
+	// DeadLettersPath, if set, is the directory where the items that cannot be saved are moved, after
+	// MaxWorkItemAttempts failed attempts or right away if the error is permanent
+	DeadLettersPath string
+	// DryRunPath, if set, replaces elasticsearch with NDJSON files placed in this directory
+	DryRunPath string
+	// PostgresDataSourceName, if set, also writes the documents in this PostgreSQL database, one table per index. The
+	// binary that uses the indexer has to register a driver named "postgres"
+	PostgresDataSourceName string
+	EventsTopicPrefix      string
+	// IndexPrefix, if set, e.g. "testnet", is added to the names of all the indices, aliases, templates and policies,
+	// so several networks can be indexed in the same cluster
+	IndexPrefix    string
+	EnabledIndexes []string
+	// FinalizedIndexes are the enabled indices written only after the blocks are finalized. The others are written as
+	// soon as the blocks are received
+	FinalizedIndexes         []string
 	ShardCoordinator         indexer.ShardCoordinator
 	Marshalizer              marshal.Marshalizer
 	Hasher                   hashing.Hasher
@@ -41,6 +112,16 @@ type ArgsIndexerFactory struct {
 	ValidatorPubkeyConverter core.PubkeyConverter
 	AccountsDB               indexer.AccountsAdapter
 	TransactionFeeCalculator indexer.FeesProcessorHandler
+	EventsBroker             stream.BrokerHandler
+	// MetricsHandler, if provided, records the metrics of the indexer pipeline, so the node can expose them
+	MetricsHandler indexer.MetricsHandler
+	// Rollover rolls the large indices that are mostly appended over to a new backing index when a condition is met
+	Rollover elastic.RolloverConditions
+	// EventProcessors are added to the built-in ones, so integrators can index the events of their own smart contracts
+	// in the indices they declare
+	EventProcessors []logsevents.EventProcessor
+	// ContractABIs decode the calls and the events of the listed smart contracts
+	ContractABIs []abi.ContractConfig
 }
 
 // NewIndexer will create a new instance of Indexer
@@ -54,12 +135,27 @@ func NewIndexer(args *ArgsIndexerFactory) (indexer.Indexer, error) {
 		return indexer.NewNilIndexer(), nil
 	}
 
-	elasticProcessor, err := createElasticProcessor(args)
+	metricsHandler := args.MetricsHandler
+	if check.IfNil(metricsHandler) {
+		metricsHandler = metrics.NewDisabledMetrics()
+	}
+
+	elasticProcessor, finalizedDataProcessor, err := createElasticProcessors(args, metricsHandler)
+	if err != nil {
+		return nil, err
+	}
+
+	codec, err := payload.NewCodec(args.Marshalizer)
+	if err != nil {
+		return nil, err
+	}
+
+	pendingBlocksQueue, err := createPendingBlocksQueue(args, finalizedDataProcessor)
 	if err != nil {
 		return nil, err
 	}
 
-	dispatcher, err := indexer.NewDataDispatcher(args.IndexerCacheSize)
+	dispatcher, err := createDispatcher(args, codec, metricsHandler, elasticProcessor, finalizedDataProcessor)
 	if err != nil {
 		return nil, err
 	}
@@ -67,15 +163,73 @@ func NewIndexer(args *ArgsIndexerFactory) (indexer.Indexer, error) {
 	dispatcher.StartIndexData()
 
 	arguments := indexer.ArgDataIndexer{
-		Marshalizer:      args.Marshalizer,
-		ShardCoordinator: args.ShardCoordinator,
-		ElasticProcessor: elasticProcessor,
-		DataDispatcher:   dispatcher,
+		Marshalizer:            args.Marshalizer,
+		ShardCoordinator:       args.ShardCoordinator,
+		ElasticProcessor:       elasticProcessor,
+		FinalizedDataProcessor: finalizedDataProcessor,
+		DataDispatcher:         dispatcher,
+		PendingBlocksQueue:     pendingBlocksQueue,
+		Codec:                  codec,
 	}
 
 	return indexer.NewDataIndexer(arguments)
 }
 
+// createPendingBlocksQueue will return a queue placed in the persistent queue directory, that keeps the blocks which
+// wait to be finalized, if the finality mode is enabled. Otherwise, or if there is no persistent queue, the blocks
+// are kept only in memory
+func createPendingBlocksQueue(args *ArgsIndexerFactory, finalizedDataProcessor indexer.ElasticProcessor) (indexer.PersistentQueueHandler, error) {
+	if args.PersistentQueuePath == "" || check.IfNil(finalizedDataProcessor) {
+		return nil, nil
+	}
+
+	return queue.NewDiskQueue(filepath.Join(args.PersistentQueuePath, pendingBlocksDirectory))
+}
+
+// createDispatcher will create a dispatcher that keeps the work items only in memory, or, if a persistent queue path
+// is provided, one that also writes them in an append-only log so they survive a restart
+func createDispatcher(
+	args *ArgsIndexerFactory,
+	codec indexer.PayloadCodec,
+	metricsHandler indexer.MetricsHandler,
+	elasticProcessor indexer.ElasticProcessor,
+	finalizedDataProcessor indexer.ElasticProcessor,
+) (indexer.DispatcherHandler, error) {
+	var err error
+	argsDataDispatcher := indexer.ArgsDataDispatcher{
+		CacheSize:      args.IndexerCacheSize,
+		MaxAttempts:    args.MaxWorkItemAttempts,
+		MetricsHandler: metricsHandler,
+	}
+	if args.DeadLettersPath != "" {
+		argsDataDispatcher.DeadLetterStore, err = deadletter.NewFileStore(deadletter.ArgsFileStore{
+			Directory: args.DeadLettersPath,
+			Encoder:   codec,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if args.PersistentQueuePath == "" {
+		return indexer.NewDataDispatcher(argsDataDispatcher)
+	}
+
+	diskQueue, err := queue.NewDiskQueue(args.PersistentQueuePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return indexer.NewPersistentDataDispatcher(indexer.ArgsPersistentDataDispatcher{
+		ArgsDataDispatcher:     argsDataDispatcher,
+		Queue:                  diskQueue,
+		Codec:                  codec,
+		Marshalizer:            args.Marshalizer,
+		ElasticProcessor:       elasticProcessor,
+		FinalizedDataProcessor: finalizedDataProcessor,
+	})
+}
+
 func retryBackOff(attempt int) time.Duration {
 	d := time.Duration(math.Exp2(float64(attempt))) * time.Second
 	log.Debug("elastic: retry backoff", "attempt", attempt, "sleep duration", d)
@@ -83,8 +237,111 @@ func retryBackOff(attempt int) time.Duration {
 	return d
 }
 
-func createElasticProcessor(args *ArgsIndexerFactory) (indexer.ElasticProcessor, error) {
-	databaseClient, err := client.NewElasticClient(elasticsearch.Config{
+// createElasticProcessors will create the processor for the indices written as soon as the blocks are received and,
+// if any of the enabled indices has to wait for finality, the processor for the finalized blocks. The events are
+// published only by the first one
+func createElasticProcessors(args *ArgsIndexerFactory, metricsHandler indexer.MetricsHandler) (indexer.ElasticProcessor, indexer.ElasticProcessor, error) {
+	enabledIndexes, finalizedIndexes, err := splitIndexesByFinality(args.EnabledIndexes, args.FinalizedIndexes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	databaseClient, err := createDatabaseClient(args)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sqlClient, err := createSQLClient(args.PostgresDataSourceName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	argsElasticProcFac := factory.ArgElasticProcessorFactory{
+		Marshalizer:               args.Marshalizer,
+		Hasher:                    args.Hasher,
+		AddressPubkeyConverter:    args.AddressPubkeyConverter,
+		ValidatorPubkeyConverter:  args.ValidatorPubkeyConverter,
+		UseKibana:                 args.UseKibana,
+		DBClient:                  databaseClient,
+		SQLClient:                 sqlClient,
+		MetricsHandler:            metricsHandler,
+		EventsBroker:              args.EventsBroker,
+		EventsTopicPrefix:         args.EventsTopicPrefix,
+		AccountsDB:                args.AccountsDB,
+		Denomination:              args.Denomination,
+		TransactionFeeCalculator:  args.TransactionFeeCalculator,
+		IsInImportDBMode:          args.IsInImportDBMode,
+		ShardCoordinator:          args.ShardCoordinator,
+		EnabledIndexes:            enabledIndexes,
+		BulkRequestMaxSize:        args.BulkRequestMaxSize,
+		NumConcurrentBulkRequests: args.NumConcurrentBulkRequests,
+		Rollover:                  args.Rollover,
+		IndexPrefix:               args.IndexPrefix,
+		AllowMappingsConflicts:    args.AllowMappingsConflicts,
+		CheckMigrations:           args.CheckMigrations,
+		EventProcessors:           args.EventProcessors,
+		ContractABIs:              args.ContractABIs,
+	}
+
+	elasticProcessor, err := factory.CreateElasticProcessor(argsElasticProcFac)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(finalizedIndexes) == 0 {
+		return elasticProcessor, nil, nil
+	}
+
+	argsElasticProcFac.EnabledIndexes = finalizedIndexes
+	argsElasticProcFac.FinalizedDataOnly = true
+	argsElasticProcFac.EventsBroker = nil
+	finalizedDataProcessor, err := factory.CreateElasticProcessor(argsElasticProcFac)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return elasticProcessor, finalizedDataProcessor, nil
+}
+
+// splitIndexesByFinality will return the enabled indices that are written right away and the ones that wait for the
+// blocks to be finalized
+func splitIndexesByFinality(enabledIndexes []string, finalizedIndexes []string) ([]string, []string, error) {
+	finalizedIndexesMap := make(map[string]struct{}, len(finalizedIndexes))
+	for _, index := range finalizedIndexes {
+		_, canWait := finalityIndexes[index]
+		if !canWait {
+			return nil, nil, fmt.Errorf("%w: %s", indexer.ErrIndexCannotWaitForFinality, index)
+		}
+
+		finalizedIndexesMap[index] = struct{}{}
+	}
+
+	immediateIndexes := make([]string, 0, len(enabledIndexes))
+	waitingIndexes := make([]string, 0, len(finalizedIndexes))
+	for _, index := range enabledIndexes {
+		_, shouldWait := finalizedIndexesMap[index]
+		if shouldWait {
+			waitingIndexes = append(waitingIndexes, index)
+			continue
+		}
+
+		immediateIndexes = append(immediateIndexes, index)
+	}
+
+	return immediateIndexes, waitingIndexes, nil
+}
+
+// createDatabaseClient will return a client that writes the requests in files if a dry run path is provided, or an
+// elasticsearch client otherwise
+func createDatabaseClient(args *ArgsIndexerFactory) (elastic.DatabaseClientHandler, error) {
+	if args.DryRunPath != "" {
+		return fileclient.NewFileClient(fileclient.ArgsFileClient{
+			Directory:   args.DryRunPath,
+			MaxFileSize: dryRunMaxFileSize,
+			IndexPrefix: args.IndexPrefix,
+		})
+	}
+
+	return client.NewElasticClient(elasticsearch.Config{
 		Addresses:     []string{args.Url},
 		Username:      args.UserName,
 		Password:      args.Password,
@@ -92,40 +349,42 @@ func createElasticProcessor(args *ArgsIndexerFactory) (indexer.ElasticProcessor,
 		RetryOnStatus: []int{http.StatusConflict},
 		RetryBackoff:  retryBackOff,
 	})
+}
+
+// createSQLClient will return a nil client if no data source is provided, so the documents are written only in
+// elasticsearch
+func createSQLClient(dataSourceName string) (postgres.DatabaseClientHandler, error) {
+	if dataSourceName == "" {
+		return nil, nil
+	}
+
+	db, err := sql.Open(postgresDriverName, dataSourceName)
 	if err != nil {
 		return nil, err
 	}
 
-	argsElasticProcFac := factory.ArgElasticProcessorFactory{
-		Marshalizer:              args.Marshalizer,
-		Hasher:                   args.Hasher,
-		AddressPubkeyConverter:   args.AddressPubkeyConverter,
-		ValidatorPubkeyConverter: args.ValidatorPubkeyConverter,
-		UseKibana:                args.UseKibana,
-		DBClient:                 databaseClient,
-		AccountsDB:               args.AccountsDB,
-		Denomination:             args.Denomination,
-		TransactionFeeCalculator: args.TransactionFeeCalculator,
-		IsInImportDBMode:         args.IsInImportDBMode,
-		ShardCoordinator:         args.ShardCoordinator,
-		EnabledIndexes:           args.EnabledIndexes,
-		BulkRequestMaxSize:       args.BulkRequestMaxSize,
-	}
-
-	return factory.CreateElasticProcessor(argsElasticProcFac)
+	err = db.Ping()
+	if err != nil {
+		return nil, err
+	}
+
+	return postgres.NewSQLClient(db)
 }
 
 func checkDataIndexerParams(arguments *ArgsIndexerFactory) error {
 	if arguments.IndexerCacheSize < 0 {
 		return indexer.ErrNegativeCacheSize
 	}
+	if arguments.MaxWorkItemAttempts < 0 {
+		return indexer.ErrNegativeMaxAttempts
+	}
 	if check.IfNil(arguments.AddressPubkeyConverter) {
 		return fmt.Errorf("%w when setting AddressPubkeyConverter in indexer", indexer.ErrNilPubkeyConverter)
 	}
 	if check.IfNil(arguments.ValidatorPubkeyConverter) {
 		return fmt.Errorf("%w when setting ValidatorPubkeyConverter in indexer", indexer.ErrNilPubkeyConverter)
 	}
-	if arguments.Url == "" {
+	if arguments.Url == "" && arguments.DryRunPath == "" {
 		return indexer.ErrNilUrl
 	}
 	if check.IfNil(arguments.Marshalizer) {
@@ -143,6 +402,27 @@ func checkDataIndexerParams(arguments *ArgsIndexerFactory) error {
 	if check.IfNil(arguments.ShardCoordinator) {
 		return indexer.ErrNilShardCoordinator
 	}
+	if arguments.PersistentQueuePath != "" {
+		return checkIndexesWithPersistentQueue(arguments.EnabledIndexes)
+	}
 
 	return nil
 }
+
+// checkIndexesWithPersistentQueue returns an error if an index filled from the data tries of the accounts is enabled
+// together with the persistent queue
+func checkIndexesWithPersistentQueue(enabledIndexes []string) error {
+	notSupported := make([]string, 0)
+	for _, index := range enabledIndexes {
+		_, needsDataTries := dataTriesIndexes[index]
+		if needsDataTries {
+			notSupported = append(notSupported, index)
+		}
+	}
+	if len(notSupported) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("%w: the %s indices cannot be enabled together with PersistentQueuePath",
+		indexer.ErrIndexNeedsDataTries, strings.Join(notSupported, ", "))
+}
@@ -4,15 +4,19 @@ import (
 	errorsGo "errors"
 	"net/http"
 	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 
 	indexer "github.com/ME-MotherEarth/me-elastic-indexer"
+	"github.com/ME-MotherEarth/me-elastic-indexer/client/memserver"
 	"github.com/ME-MotherEarth/me-elastic-indexer/mock"
 	"github.com/stretchr/testify/require"
 )
 
 func createMockIndexerFactoryArgs() *ArgsIndexerFactory {
-	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	ts := httptest.NewServer(memserver.NewServer())
 
 	return &ArgsIndexerFactory{
 		Enabled:                  true,
@@ -48,6 +52,15 @@ func TestNewIndexerFactory(t *testing.T) {
 			},
 			exError: indexer.ErrNegativeCacheSize,
 		},
+		{
+			name: "InvalidMaxWorkItemAttempts",
+			argsFunc: func() *ArgsIndexerFactory {
+				args := createMockIndexerFactoryArgs()
+				args.MaxWorkItemAttempts = -1
+				return args
+			},
+			exError: indexer.ErrNegativeMaxAttempts,
+		},
 		{
 			name: "NilAddressPubkeyConverter",
 			argsFunc: func() *ArgsIndexerFactory {
@@ -120,6 +133,15 @@ func TestNewIndexerFactory(t *testing.T) {
 			},
 			exError: indexer.ErrNilShardCoordinator,
 		},
+		{
+			name: "AccountsIndexCannotWaitForFinality",
+			argsFunc: func() *ArgsIndexerFactory {
+				args := createMockIndexerFactoryArgs()
+				args.FinalizedIndexes = []string{"blocks", "accounts"}
+				return args
+			},
+			exError: indexer.ErrIndexCannotWaitForFinality,
+		},
 		{
 			name: "All arguments ok",
 			argsFunc: func() *ArgsIndexerFactory {
@@ -150,7 +172,7 @@ func TestIndexerFactoryCreate_NilIndexer(t *testing.T) {
 }
 
 func TestIndexerFactoryCreate_ElasticIndexer(t *testing.T) {
-	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	ts := httptest.NewServer(memserver.NewServer())
 	args := createMockIndexerFactoryArgs()
 	args.Url = ts.URL
 
@@ -164,3 +186,102 @@ func TestIndexerFactoryCreate_ElasticIndexer(t *testing.T) {
 	err = elasticIndexer.Close()
 	require.NoError(t, err)
 }
+
+func TestIndexerFactoryCreate_RestartShouldNotUpdateTheMappings(t *testing.T) {
+	mutex := sync.Mutex{}
+	numMappingUpdates := 0
+	server := memserver.NewServer()
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut && strings.HasSuffix(r.URL.Path, "/_mapping") {
+			mutex.Lock()
+			numMappingUpdates++
+			mutex.Unlock()
+		}
+		server.ServeHTTP(w, r)
+	}))
+	defer ts.Close()
+
+	args := createMockIndexerFactoryArgs()
+	args.Url = ts.URL
+	args.EnabledIndexes = []string{indexer.BlockIndex, indexer.CollectionsIndex}
+
+	for i := 0; i < 2; i++ {
+		elasticIndexer, err := NewIndexer(args)
+		require.NoError(t, err)
+		require.NoError(t, elasticIndexer.Close())
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	require.Equal(t, 0, numMappingUpdates)
+}
+
+func TestIndexerFactoryCreate_ElasticIndexerWithPersistentQueue(t *testing.T) {
+	args := createMockIndexerFactoryArgs()
+	args.PersistentQueuePath = t.TempDir()
+
+	elasticIndexer, err := NewIndexer(args)
+	require.NoError(t, err)
+	require.False(t, elasticIndexer.IsNilIndexer())
+
+	err = elasticIndexer.Close()
+	require.NoError(t, err)
+}
+
+func TestIndexerFactoryCreate_PersistentQueueWithDataTriesIndexesShouldErr(t *testing.T) {
+	args := createMockIndexerFactoryArgs()
+	args.PersistentQueuePath = t.TempDir()
+	args.EnabledIndexes = append(args.EnabledIndexes, indexer.AccountsMECTIndex, indexer.CollectionsIndex)
+
+	elasticIndexer, err := NewIndexer(args)
+	require.Nil(t, elasticIndexer)
+	require.True(t, errorsGo.Is(err, indexer.ErrIndexNeedsDataTries))
+	require.True(t, strings.Contains(err.Error(), "accountsmect, collections"))
+
+	args.PersistentQueuePath = ""
+	elasticIndexer, err = NewIndexer(args)
+	require.NoError(t, err)
+
+	err = elasticIndexer.Close()
+	require.NoError(t, err)
+}
+
+func TestIndexerFactoryCreate_ElasticIndexerWithFinalizedIndexes(t *testing.T) {
+	args := createMockIndexerFactoryArgs()
+	args.FinalizedIndexes = []string{"blocks", "transactions"}
+
+	elasticIndexer, err := NewIndexer(args)
+	require.NoError(t, err)
+	require.False(t, elasticIndexer.IsNilIndexer())
+
+	err = elasticIndexer.Close()
+	require.NoError(t, err)
+}
+
+func TestIndexerFactoryCreate_ElasticIndexerWithDryRun(t *testing.T) {
+	args := createMockIndexerFactoryArgs()
+	args.Url = ""
+	args.DryRunPath = t.TempDir()
+
+	elasticIndexer, err := NewIndexer(args)
+	require.NoError(t, err)
+	require.False(t, elasticIndexer.IsNilIndexer())
+
+	err = elasticIndexer.Close()
+	require.NoError(t, err)
+
+	paths, _ := filepath.Glob(filepath.Join(args.DryRunPath, "*.ndjson"))
+	require.Len(t, paths, 1)
+}
+
+func TestSplitIndexesByFinality(t *testing.T) {
+	t.Parallel()
+
+	immediateIndexes, waitingIndexes, err := splitIndexesByFinality([]string{"blocks", "accounts", "transactions"}, []string{"transactions", "logs"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"blocks", "accounts"}, immediateIndexes)
+	require.Equal(t, []string{"transactions"}, waitingIndexes)
+
+	_, _, err = splitIndexesByFinality([]string{"blocks"}, []string{"rounds"})
+	require.True(t, errorsGo.Is(err, indexer.ErrIndexCannotWaitForFinality))
+}
@@ -0,0 +1,9 @@
+package gaps
+
+import "errors"
+
+// ErrNilScrollClient signals that a nil scroll client has been provided
+var ErrNilScrollClient = errors.New("nil scroll client")
+
+// ErrInvalidNonceRange signals that the provided nonce range is invalid
+var ErrInvalidNonceRange = errors.New("invalid nonce range")
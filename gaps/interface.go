@@ -0,0 +1,7 @@
+package gaps
+
+// ScrollClient defines what the client used to walk over the indexed blocks should be able to do
+type ScrollClient interface {
+	DoScrollRequest(index string, body []byte, withSource bool, handlerFunc func(responseBytes []byte) error) error
+	IsInterfaceNil() bool
+}
@@ -0,0 +1,156 @@
+package gaps
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/ME-MotherEarth/me-core/core/check"
+	indexer "github.com/ME-MotherEarth/me-elastic-indexer"
+	"github.com/ME-MotherEarth/me-elastic-indexer/data"
+)
+
+const nonceField = "nonce"
+
+// ArgsScanner holds all dependencies required by the gaps scanner in order to create new instances
+type ArgsScanner struct {
+	ScrollClient ScrollClient
+	IndexPrefix  string
+}
+
+type scanner struct {
+	scrollClient ScrollClient
+	blocksIndex  string
+}
+
+type nonceHits struct {
+	Hits struct {
+		Hits []struct {
+			Fields struct {
+				Nonce []float64 `json:"nonce"`
+			} `json:"fields"`
+		} `json:"hits"`
+	} `json:"hits"`
+}
+
+// NewScanner will create a component that finds the block nonces missing from the blocks index. If an index prefix is
+// provided, the blocks index of that network is scanned
+func NewScanner(args ArgsScanner) (*scanner, error) {
+	if check.IfNil(args.ScrollClient) {
+		return nil, ErrNilScrollClient
+	}
+
+	return &scanner{
+		scrollClient: args.ScrollClient,
+		blocksIndex:  indexer.PrefixIndex(args.IndexPrefix, indexer.BlockIndex),
+	}, nil
+}
+
+// FindGaps will walk, in ascending nonce order, over the indexed blocks of the provided shard and will return the
+// ranges of nonces between fromNonce and toNonce, both inclusive, that have no block. The returned ranges can be
+// used to feed the missing blocks again
+func (s *scanner) FindGaps(shardID uint32, fromNonce uint64, toNonce uint64) ([]*data.NonceRange, error) {
+	if fromNonce > toNonce {
+		return nil, fmt.Errorf("%w: from %d is greater than to %d", ErrInvalidNonceRange, fromNonce, toNonce)
+	}
+
+	query, err := prepareNoncesQuery(shardID, fromNonce, toNonce)
+	if err != nil {
+		return nil, err
+	}
+
+	tracker := newGapsTracker(fromNonce)
+	scrollHandler := func(responseBytes []byte) error {
+		response := &nonceHits{}
+		errUnmarshal := json.Unmarshal(responseBytes, response)
+		if errUnmarshal != nil {
+			return errUnmarshal
+		}
+
+		for _, hit := range response.Hits.Hits {
+			for _, nonce := range hit.Fields.Nonce {
+				tracker.addNonce(uint64(nonce))
+			}
+		}
+
+		return nil
+	}
+
+	err = s.scrollClient.DoScrollRequest(s.blocksIndex, query, false, scrollHandler)
+	if err != nil {
+		return nil, err
+	}
+
+	return tracker.finish(toNonce), nil
+}
+
+func prepareNoncesQuery(shardID uint32, fromNonce uint64, toNonce uint64) ([]byte, error) {
+	query := map[string]interface{}{
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"filter": []interface{}{
+					map[string]interface{}{
+						"term": map[string]interface{}{
+							"shardId": shardID,
+						},
+					},
+					map[string]interface{}{
+						"range": map[string]interface{}{
+							nonceField: map[string]interface{}{
+								"gte": fromNonce,
+								"lte": toNonce,
+							},
+						},
+					},
+				},
+			},
+		},
+		"sort": []interface{}{
+			map[string]interface{}{
+				nonceField: map[string]interface{}{
+					"order": "asc",
+				},
+			},
+		},
+		"docvalue_fields": []string{nonceField},
+	}
+
+	return json.Marshal(query)
+}
+
+// gapsTracker receives the indexed nonces in ascending order and keeps the ranges that were skipped. The same nonce
+// can be received more than once, if the blocks of a fork were not removed
+type gapsTracker struct {
+	nextNonce uint64
+	gaps      []*data.NonceRange
+}
+
+func newGapsTracker(fromNonce uint64) *gapsTracker {
+	return &gapsTracker{
+		nextNonce: fromNonce,
+		gaps:      make([]*data.NonceRange, 0),
+	}
+}
+
+func (gt *gapsTracker) addNonce(nonce uint64) {
+	if nonce < gt.nextNonce {
+		return
+	}
+	if nonce > gt.nextNonce {
+		gt.gaps = append(gt.gaps, &data.NonceRange{From: gt.nextNonce, To: nonce - 1})
+	}
+
+	gt.nextNonce = nonce + 1
+}
+
+func (gt *gapsTracker) finish(toNonce uint64) []*data.NonceRange {
+	if gt.nextNonce <= toNonce {
+		gt.gaps = append(gt.gaps, &data.NonceRange{From: gt.nextNonce, To: toNonce})
+	}
+
+	return gt.gaps
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (s *scanner) IsInterfaceNil() bool {
+	return s == nil
+}
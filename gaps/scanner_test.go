@@ -0,0 +1,141 @@
+package gaps
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	indexer "github.com/ME-MotherEarth/me-elastic-indexer"
+	"github.com/ME-MotherEarth/me-elastic-indexer/data"
+	"github.com/ME-MotherEarth/me-elastic-indexer/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func createNoncesResponse(nonces ...uint64) []byte {
+	hits := make([]string, 0, len(nonces))
+	for _, nonce := range nonces {
+		hits = append(hits, fmt.Sprintf(`{"_id":"h%d","fields":{"nonce":[%d.0]}}`, nonce, nonce))
+	}
+
+	return []byte(`{"hits":{"hits":[` + strings.Join(hits, ",") + `]}}`)
+}
+
+func createScrollClient(pages ...[]byte) *mock.DatabaseWriterStub {
+	return &mock.DatabaseWriterStub{
+		DoScrollRequestCalled: func(index string, body []byte, withSource bool, handlerFunc func(responseBytes []byte) error) error {
+			for _, page := range pages {
+				err := handlerFunc(page)
+				if err != nil {
+					return err
+				}
+			}
+
+			return nil
+		},
+	}
+}
+
+func TestNewScanner(t *testing.T) {
+	t.Parallel()
+
+	s, err := NewScanner(ArgsScanner{})
+	require.Nil(t, s)
+	require.Equal(t, ErrNilScrollClient, err)
+
+	s, err = NewScanner(ArgsScanner{ScrollClient: &mock.DatabaseWriterStub{}})
+	require.Nil(t, err)
+	require.False(t, s.IsInterfaceNil())
+}
+
+func TestScanner_FindGapsShouldQueryTheBlocksOfTheShard(t *testing.T) {
+	t.Parallel()
+
+	s, _ := NewScanner(ArgsScanner{ScrollClient: &mock.DatabaseWriterStub{
+		DoScrollRequestCalled: func(index string, body []byte, withSource bool, _ func(responseBytes []byte) error) error {
+			require.Equal(t, indexer.BlockIndex, index)
+			require.False(t, withSource)
+
+			query := make(map[string]interface{})
+			require.Nil(t, json.Unmarshal(body, &query))
+			require.Equal(t, []interface{}{"nonce"}, query["docvalue_fields"])
+			require.Contains(t, string(body), `{"term":{"shardId":2}}`)
+			require.Contains(t, string(body), `{"range":{"nonce":{"gte":5,"lte":10}}}`)
+
+			return nil
+		},
+	}})
+
+	gaps, err := s.FindGaps(2, 5, 10)
+	require.Nil(t, err)
+	require.Equal(t, []*data.NonceRange{{From: 5, To: 10}}, gaps)
+}
+
+func TestScanner_FindGapsWithIndexPrefix(t *testing.T) {
+	t.Parallel()
+
+	called := false
+	s, _ := NewScanner(ArgsScanner{
+		IndexPrefix: "testnet",
+		ScrollClient: &mock.DatabaseWriterStub{
+			DoScrollRequestCalled: func(index string, body []byte, withSource bool, _ func(responseBytes []byte) error) error {
+				called = true
+				require.Equal(t, "testnet-blocks", index)
+				return nil
+			},
+		},
+	})
+
+	_, err := s.FindGaps(0, 1, 2)
+	require.Nil(t, err)
+	require.True(t, called)
+}
+
+func TestScanner_FindGaps(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid range should error", func(t *testing.T) {
+		t.Parallel()
+
+		s, _ := NewScanner(ArgsScanner{ScrollClient: createScrollClient()})
+		_, err := s.FindGaps(0, 10, 9)
+		require.True(t, errors.Is(err, ErrInvalidNonceRange))
+	})
+	t.Run("scroll error should be returned", func(t *testing.T) {
+		t.Parallel()
+
+		localErr := errors.New("local err")
+		s, _ := NewScanner(ArgsScanner{ScrollClient: &mock.DatabaseWriterStub{
+			DoScrollRequestCalled: func(_ string, _ []byte, _ bool, _ func(responseBytes []byte) error) error {
+				return localErr
+			},
+		}})
+		_, err := s.FindGaps(0, 1, 9)
+		require.Equal(t, localErr, err)
+	})
+	t.Run("no gaps", func(t *testing.T) {
+		t.Parallel()
+
+		s, _ := NewScanner(ArgsScanner{ScrollClient: createScrollClient(createNoncesResponse(1, 2, 3), createNoncesResponse(4))})
+		gaps, err := s.FindGaps(0, 1, 4)
+		require.Nil(t, err)
+		require.Empty(t, gaps)
+	})
+	t.Run("missing ranges across pages should be reported", func(t *testing.T) {
+		t.Parallel()
+
+		s, _ := NewScanner(ArgsScanner{ScrollClient: createScrollClient(
+			createNoncesResponse(3, 4, 4, 7),
+			createNoncesResponse(8, 12),
+		)})
+		gaps, err := s.FindGaps(0, 1, 15)
+		require.Nil(t, err)
+		require.Equal(t, []*data.NonceRange{
+			{From: 1, To: 2},
+			{From: 5, To: 6},
+			{From: 9, To: 11},
+			{From: 13, To: 15},
+		}, gaps)
+	})
+}
@@ -7,6 +7,7 @@ require (
 	github.com/ME-MotherEarth/me-logger v0.0.1
 	github.com/ME-MotherEarth/me-vm-common v0.0.1
 	github.com/elastic/go-elasticsearch/v7 v7.12.0
+	github.com/lib/pq v1.10.9
 	github.com/stretchr/testify v1.8.0
 	github.com/tidwall/gjson v1.14.3
 )
@@ -22,6 +23,7 @@ require (
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/tidwall/match v1.1.1 // indirect
 	github.com/tidwall/pretty v1.2.0 // indirect
+	golang.org/x/crypto v0.0.0-20221012134737-56aed061732a // indirect
 	golang.org/x/sys v0.0.0-20210615035016-665e8c7367d1 // indirect
 	google.golang.org/protobuf v1.26.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
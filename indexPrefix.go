@@ -0,0 +1,11 @@
+package indexer
+
+// PrefixIndex returns the name under which an index, alias, template or policy is stored when the provided prefix
+// is used, e.g. "mainnet-transactions". An empty prefix leaves the name unchanged
+func PrefixIndex(prefix string, name string) string {
+	if prefix == "" {
+		return name
+	}
+
+	return prefix + IndexPrefixSeparator + name
+}
@@ -0,0 +1,15 @@
+package indexer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrefixIndex(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, TransactionsIndex, PrefixIndex("", TransactionsIndex))
+	require.Equal(t, "testnet-transactions", PrefixIndex("testnet", TransactionsIndex))
+	require.Equal(t, "testnet-transactions-*", PrefixIndex("testnet", "transactions-*"))
+}
@@ -0,0 +1,26 @@
+//go:build integrationtests
+
+package integrationtests
+
+import (
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/ME-MotherEarth/me-elastic-indexer/client/memserver"
+)
+
+func TestMain(m *testing.M) {
+	url := os.Getenv(esURLEnvVariable)
+	if url != "" {
+		esURL = url
+		os.Exit(m.Run())
+	}
+
+	server := httptest.NewServer(memserver.NewServer())
+	esURL = server.URL
+	code := m.Run()
+	server.Close()
+
+	os.Exit(code)
+}
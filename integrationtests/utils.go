@@ -11,20 +11,24 @@ import (
 	"github.com/ME-MotherEarth/me-elastic-indexer/client/logging"
 	"github.com/ME-MotherEarth/me-elastic-indexer/data"
 	"github.com/ME-MotherEarth/me-elastic-indexer/mock"
-	"github.com/ME-MotherEarth/me-elastic-indexer/process"
 	"github.com/ME-MotherEarth/me-elastic-indexer/process/factory"
+	"github.com/ME-MotherEarth/me-elastic-indexer/process/sink/elastic"
 	logger "github.com/ME-MotherEarth/me-logger"
 	"github.com/elastic/go-elasticsearch/v7"
 	"github.com/stretchr/testify/require"
 )
 
-const esURL = "http://localhost:9200"
+// esURLEnvVariable is the environment variable that holds the address of the cluster the tests should run against.
+// When it is not set, the tests run against an in-memory server
+const esURLEnvVariable = "ES_URL"
+
+var esURL = "http://localhost:9200"
 
 func setLogLevelDebug() {
 	_ = logger.SetLogLevel("process:DEBUG")
 }
 
-func createESClient(url string) (process.DatabaseClientHandler, error) {
+func createESClient(url string) (elastic.DatabaseClientHandler, error) {
 	return client.NewElasticClient(elasticsearch.Config{
 		Addresses: []string{url},
 		Logger:    &logging.CustomLogger{},
@@ -33,7 +37,7 @@ func createESClient(url string) (process.DatabaseClientHandler, error) {
 
 // CreateElasticProcessor -
 func CreateElasticProcessor(
-	esClient process.DatabaseClientHandler,
+	esClient elastic.DatabaseClientHandler,
 	accountsDB indexer.AccountsAdapter,
 	shardCoordinator indexer.ShardCoordinator,
 	feeProcessor indexer.FeesProcessorHandler,
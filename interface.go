@@ -2,11 +2,14 @@ package indexer
 
 import (
 	"math/big"
+	"time"
 
 	coreData "github.com/ME-MotherEarth/me-core/data"
 	"github.com/ME-MotherEarth/me-core/data/block"
 	"github.com/ME-MotherEarth/me-core/data/indexer"
 	"github.com/ME-MotherEarth/me-elastic-indexer/data"
+	"github.com/ME-MotherEarth/me-elastic-indexer/payload"
+	"github.com/ME-MotherEarth/me-elastic-indexer/queue"
 	"github.com/ME-MotherEarth/me-elastic-indexer/workItems"
 	vmcommon "github.com/ME-MotherEarth/me-vm-common"
 )
@@ -15,7 +18,42 @@ import (
 type DispatcherHandler interface {
 	StartIndexData()
 	Close() error
-	Add(item workItems.WorkItemHandler)
+	Add(item workItems.WorkItemHandler) error
+	IsInterfaceNil() bool
+}
+
+// PersistentQueueHandler defines what a durable queue that backs the dispatcher should be able to do
+type PersistentQueueHandler interface {
+	Append(itemData []byte) (uint64, error)
+	Ack(id uint64) error
+	Quarantine(record *queue.Record) error
+	Pending() []*queue.Record
+	Close() error
+	IsInterfaceNil() bool
+}
+
+// PayloadCodec defines what a component that converts payloads in bytes and back should be able to do
+type PayloadCodec interface {
+	Encode(p *payload.Payload) ([]byte, error)
+	Decode(buff []byte) (*payload.Payload, error)
+	IsInterfaceNil() bool
+}
+
+// DeadLetterStoreHandler defines what a store that keeps the work items that could not be saved should be able to do
+type DeadLetterStoreHandler interface {
+	Put(itemType string, p *payload.Payload, reason error) error
+	PutRejectedDocuments(itemType string, p *payload.Payload, documents []*data.RejectedDocument) error
+	Close() error
+	IsInterfaceNil() bool
+}
+
+// MetricsHandler defines what the component that records the metrics of the indexer pipeline should be able to do
+type MetricsHandler interface {
+	SetQueueDepth(depth int)
+	SetBackOff(backOff time.Duration)
+	ObserveBulkRequest(index string, sizeInBytes int, duration time.Duration, err error)
+	ObserveWorkItem(itemType string, duration time.Duration, err error)
+	SetLastIndexedNonce(shardID uint32, nonce uint64)
 	IsInterfaceNil() bool
 }
 
@@ -34,12 +72,26 @@ type ElasticProcessor interface {
 	RemoveMiniblocks(header coreData.HeaderHandler, body *block.Body) error
 	RemoveTransactions(header coreData.HeaderHandler, body *block.Body) error
 	RemoveAccountsMECT(headerTimestamp uint64) error
+	RevertBlockChanges(header coreData.HeaderHandler) error
 	SaveMiniblocks(header coreData.HeaderHandler, body *block.Body) error
 	SaveTransactions(body *block.Body, header coreData.HeaderHandler, pool *indexer.Pool) error
+	PrepareTransactions(body *block.Body, header coreData.HeaderHandler, pool *indexer.Pool) (*data.PreparedBlockTransactions, error)
+	SavePreparedTransactions(header coreData.HeaderHandler, pool *indexer.Pool, preparedTxs *data.PreparedBlockTransactions) error
 	SaveValidatorsRating(index string, validatorsRatingInfo []*data.ValidatorRatingInfo) error
 	SaveRoundsInfo(infos []*data.RoundInfo) error
 	SaveShardValidatorsPubKeys(shardID, epoch uint32, shardValidatorsPubKeys [][]byte) error
 	SaveAccounts(blockTimestamp uint64, accounts []*data.Account) error
+	FinalizeBlock(headerHash []byte) error
+	SaveCheckpoint(headerHash []byte, header coreData.HeaderHandler) error
+	GetCheckpoints(shardIDs []uint32) ([]*data.Checkpoint, error)
+	IsInterfaceNil() bool
+}
+
+// ABIDecoder defines what an ABI decoder should be able to do. The calls and the events of the smart contracts without
+// an ABI are not decoded, so nil is returned
+type ABIDecoder interface {
+	DecodeCall(contract []byte, function string, dataField []byte) *data.DecodedData
+	DecodeEvent(contract []byte, topics [][]byte, eventData []byte) *data.DecodedData
 	IsInterfaceNil() bool
 }
 
@@ -71,6 +123,7 @@ type Indexer interface {
 	SaveValidatorsRating(indexID string, infoRating []*indexer.ValidatorRatingInfo) error
 	SaveAccounts(blockTimestamp uint64, acc []coreData.UserAccountHandler) error
 	FinalizedBlock(headerHash []byte) error
+	GetCheckpoints() ([]*data.Checkpoint, error)
 	Close() error
 	IsInterfaceNil() bool
 	IsNilIndexer() bool
@@ -85,5 +138,6 @@ type AccountsAdapter interface {
 type BalanceConverter interface {
 	ComputeBalanceAsFloat(balance *big.Int) float64
 	ComputeMECTBalanceAsFloat(balance *big.Int) float64
+	ComputeMECTBalanceAsFloatWithDecimals(balance *big.Int, numDecimals uint64) float64
 	IsInterfaceNil() bool
 }
@@ -0,0 +1,43 @@
+package metrics
+
+import (
+	"net/http"
+	"time"
+)
+
+type disabledMetrics struct{}
+
+// NewDisabledMetrics will create a metrics handler that does not record anything
+func NewDisabledMetrics() *disabledMetrics {
+	return &disabledMetrics{}
+}
+
+// SetQueueDepth does nothing
+func (dm *disabledMetrics) SetQueueDepth(_ int) {
+}
+
+// SetBackOff does nothing
+func (dm *disabledMetrics) SetBackOff(_ time.Duration) {
+}
+
+// ObserveBulkRequest does nothing
+func (dm *disabledMetrics) ObserveBulkRequest(_ string, _ int, _ time.Duration, _ error) {
+}
+
+// ObserveWorkItem does nothing
+func (dm *disabledMetrics) ObserveWorkItem(_ string, _ time.Duration, _ error) {
+}
+
+// SetLastIndexedNonce does nothing
+func (dm *disabledMetrics) SetLastIndexedNonce(_ uint32, _ uint64) {
+}
+
+// ServeHTTP will respond with no content
+func (dm *disabledMetrics) ServeHTTP(writer http.ResponseWriter, _ *http.Request) {
+	writer.WriteHeader(http.StatusNoContent)
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (dm *disabledMetrics) IsInterfaceNil() bool {
+	return dm == nil
+}
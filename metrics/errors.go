@@ -0,0 +1,12 @@
+package metrics
+
+import "errors"
+
+// ErrEmptyMetricName signals that a metric without name was registered
+var ErrEmptyMetricName = errors.New("empty metric name")
+
+// ErrMetricAlreadyRegistered signals that a metric with the same name was already registered
+var ErrMetricAlreadyRegistered = errors.New("metric already registered")
+
+// ErrNoBuckets signals that a histogram without buckets was registered
+var ErrNoBuckets = errors.New("no buckets provided for histogram")
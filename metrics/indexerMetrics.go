@@ -0,0 +1,130 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const metricsPrefix = "elastic_indexer_"
+
+var (
+	durationBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+	sizeBuckets     = []float64{1 << 10, 16 << 10, 64 << 10, 256 << 10, 1 << 20, 4 << 20, 16 << 20, 64 << 20}
+)
+
+type indexerMetrics struct {
+	registry *Registry
+
+	queueDepth          *Gauge
+	backOff             *Gauge
+	backOffs            *Counter
+	bulkRequestDuration *Histogram
+	bulkRequestSize     *Histogram
+	bulkRequestErrors   *Counter
+	workItemDuration    *Histogram
+	workItemErrors      *Counter
+	lastIndexedNonce    *Gauge
+}
+
+// NewIndexerMetrics will create the metrics of the indexer pipeline. The returned component is also an http.Handler
+// that serves the metrics in the Prometheus text exposition format, so it can be mounted by the node
+func NewIndexerMetrics() (*indexerMetrics, error) {
+	im := &indexerMetrics{
+		registry: NewRegistry(),
+	}
+
+	var err error
+	im.queueDepth, err = im.registry.NewGauge(metricsPrefix+"dispatcher_queue_depth",
+		"Number of work items waiting in the dispatcher queue")
+	if err != nil {
+		return nil, err
+	}
+	im.backOff, err = im.registry.NewGauge(metricsPrefix+"dispatcher_backoff_seconds",
+		"Current back off time of the dispatcher, zero if the database does not throttle the requests")
+	if err != nil {
+		return nil, err
+	}
+	im.backOffs, err = im.registry.NewCounter(metricsPrefix+"dispatcher_backoffs_total",
+		"Number of times the dispatcher backed off because the database throttled the requests")
+	if err != nil {
+		return nil, err
+	}
+	im.bulkRequestDuration, err = im.registry.NewHistogram(metricsPrefix+"bulk_request_duration_seconds",
+		"Duration of the bulk requests", durationBuckets, "index")
+	if err != nil {
+		return nil, err
+	}
+	im.bulkRequestSize, err = im.registry.NewHistogram(metricsPrefix+"bulk_request_size_bytes",
+		"Size of the bulk requests", sizeBuckets, "index")
+	if err != nil {
+		return nil, err
+	}
+	im.bulkRequestErrors, err = im.registry.NewCounter(metricsPrefix+"bulk_request_errors_total",
+		"Number of failed bulk requests", "index")
+	if err != nil {
+		return nil, err
+	}
+	im.workItemDuration, err = im.registry.NewHistogram(metricsPrefix+"work_item_duration_seconds",
+		"Duration of saving a work item, measured for every attempt", durationBuckets, "type")
+	if err != nil {
+		return nil, err
+	}
+	im.workItemErrors, err = im.registry.NewCounter(metricsPrefix+"work_item_errors_total",
+		"Number of failed attempts of saving a work item", "type")
+	if err != nil {
+		return nil, err
+	}
+	im.lastIndexedNonce, err = im.registry.NewGauge(metricsPrefix+"last_indexed_nonce",
+		"Nonce of the last indexed block", "shard")
+	if err != nil {
+		return nil, err
+	}
+
+	return im, nil
+}
+
+// SetQueueDepth will record the number of work items waiting to be saved
+func (im *indexerMetrics) SetQueueDepth(depth int) {
+	im.queueDepth.Set(float64(depth))
+}
+
+// SetBackOff will record the current back off time of the dispatcher. A non-zero value also counts as a back off
+func (im *indexerMetrics) SetBackOff(backOff time.Duration) {
+	im.backOff.Set(backOff.Seconds())
+	if backOff > 0 {
+		im.backOffs.Inc()
+	}
+}
+
+// ObserveBulkRequest will record the duration and the size of a bulk request sent for the provided index
+func (im *indexerMetrics) ObserveBulkRequest(index string, sizeInBytes int, duration time.Duration, err error) {
+	im.bulkRequestDuration.Observe(duration.Seconds(), index)
+	im.bulkRequestSize.Observe(float64(sizeInBytes), index)
+	if err != nil {
+		im.bulkRequestErrors.Inc(index)
+	}
+}
+
+// ObserveWorkItem will record the duration of an attempt to save a work item of the provided type
+func (im *indexerMetrics) ObserveWorkItem(itemType string, duration time.Duration, err error) {
+	im.workItemDuration.Observe(duration.Seconds(), itemType)
+	if err != nil {
+		im.workItemErrors.Inc(itemType)
+	}
+}
+
+// SetLastIndexedNonce will record the nonce of the last block indexed for the provided shard
+func (im *indexerMetrics) SetLastIndexedNonce(shardID uint32, nonce uint64) {
+	im.lastIndexedNonce.Set(float64(nonce), strconv.FormatUint(uint64(shardID), 10))
+}
+
+// ServeHTTP will respond with the current values of the indexer metrics
+func (im *indexerMetrics) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	im.registry.ServeHTTP(writer, request)
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (im *indexerMetrics) IsInterfaceNil() bool {
+	return im == nil
+}
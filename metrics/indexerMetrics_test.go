@@ -0,0 +1,61 @@
+package metrics
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndexerMetrics_ServeHTTP(t *testing.T) {
+	t.Parallel()
+
+	im, err := NewIndexerMetrics()
+	require.Nil(t, err)
+	require.False(t, im.IsInterfaceNil())
+
+	im.SetQueueDepth(4)
+	im.SetBackOff(12 * time.Second)
+	im.SetBackOff(0)
+	im.ObserveBulkRequest("blocks", 2048, 20*time.Millisecond, nil)
+	im.ObserveBulkRequest("blocks", 1024, 10*time.Millisecond, errors.New("local error"))
+	im.ObserveWorkItem("itemBlock", time.Second, nil)
+	im.SetLastIndexedNonce(1, 100)
+	im.SetLastIndexedNonce(1, 101)
+	im.SetLastIndexedNonce(4294967295, 7)
+
+	recorder := httptest.NewRecorder()
+	im.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	response := recorder.Body.String()
+
+	require.Contains(t, response, "elastic_indexer_dispatcher_queue_depth 4\n")
+	require.Contains(t, response, "elastic_indexer_dispatcher_backoff_seconds 0\n")
+	require.Contains(t, response, "elastic_indexer_dispatcher_backoffs_total 1\n")
+	require.Contains(t, response, `elastic_indexer_bulk_request_duration_seconds_count{index="blocks"} 2`)
+	require.Contains(t, response, `elastic_indexer_bulk_request_size_bytes_sum{index="blocks"} 3072`)
+	require.Contains(t, response, `elastic_indexer_bulk_request_errors_total{index="blocks"} 1`)
+	require.Contains(t, response, `elastic_indexer_work_item_duration_seconds_sum{type="itemBlock"} 1`)
+	require.NotContains(t, response, `elastic_indexer_work_item_errors_total{`)
+	require.Contains(t, response, `elastic_indexer_last_indexed_nonce{shard="1"} 101`)
+	require.Contains(t, response, `elastic_indexer_last_indexed_nonce{shard="4294967295"} 7`)
+}
+
+func TestDisabledMetrics_ServeHTTP(t *testing.T) {
+	t.Parallel()
+
+	dm := NewDisabledMetrics()
+	require.False(t, dm.IsInterfaceNil())
+
+	dm.SetQueueDepth(1)
+	dm.SetBackOff(time.Second)
+	dm.ObserveBulkRequest("blocks", 1, time.Second, nil)
+	dm.ObserveWorkItem("itemBlock", time.Second, nil)
+	dm.SetLastIndexedNonce(0, 1)
+
+	recorder := httptest.NewRecorder()
+	dm.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusNoContent, recorder.Code)
+}
@@ -0,0 +1,301 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	logger "github.com/ME-MotherEarth/me-logger"
+)
+
+const (
+	// ContentType is the content type of the Prometheus text exposition format written by the registry
+	ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+	counterType   = "counter"
+	gaugeType     = "gauge"
+	histogramType = "histogram"
+
+	labelValuesSeparator = "\xff"
+)
+
+var log = logger.GetOrCreate("indexer/metrics")
+
+// Registry holds metric families and writes their current values in the Prometheus text exposition format
+type Registry struct {
+	mutFamilies sync.RWMutex
+	families    []*family
+	names       map[string]struct{}
+}
+
+type family struct {
+	mutSeries  sync.Mutex
+	name       string
+	help       string
+	metricType string
+	labelNames []string
+	buckets    []float64
+	series     map[string]*series
+}
+
+type series struct {
+	labelValues  []string
+	value        float64
+	bucketCounts []uint64
+	count        uint64
+}
+
+// Counter is a metric whose value only goes up
+type Counter struct {
+	family *family
+}
+
+// Gauge is a metric whose value can be set to any number
+type Gauge struct {
+	family *family
+}
+
+// Histogram is a metric that counts the observed values in configurable buckets
+type Histogram struct {
+	family *family
+}
+
+// NewRegistry will create an empty registry
+func NewRegistry() *Registry {
+	return &Registry{
+		families: make([]*family, 0),
+		names:    make(map[string]struct{}),
+	}
+}
+
+// NewCounter will register a counter with the provided name and label names
+func (r *Registry) NewCounter(name string, help string, labelNames ...string) (*Counter, error) {
+	f, err := r.register(name, help, counterType, nil, labelNames)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Counter{family: f}, nil
+}
+
+// NewGauge will register a gauge with the provided name and label names
+func (r *Registry) NewGauge(name string, help string, labelNames ...string) (*Gauge, error) {
+	f, err := r.register(name, help, gaugeType, nil, labelNames)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Gauge{family: f}, nil
+}
+
+// NewHistogram will register a histogram with the provided name, upper bounds of the buckets and label names
+func (r *Registry) NewHistogram(name string, help string, buckets []float64, labelNames ...string) (*Histogram, error) {
+	if len(buckets) == 0 {
+		return nil, fmt.Errorf("%w for metric %s", ErrNoBuckets, name)
+	}
+
+	sortedBuckets := make([]float64, len(buckets))
+	copy(sortedBuckets, buckets)
+	sort.Float64s(sortedBuckets)
+
+	f, err := r.register(name, help, histogramType, sortedBuckets, labelNames)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Histogram{family: f}, nil
+}
+
+func (r *Registry) register(name string, help string, metricType string, buckets []float64, labelNames []string) (*family, error) {
+	if name == "" {
+		return nil, ErrEmptyMetricName
+	}
+
+	r.mutFamilies.Lock()
+	defer r.mutFamilies.Unlock()
+
+	_, exists := r.names[name]
+	if exists {
+		return nil, fmt.Errorf("%w: %s", ErrMetricAlreadyRegistered, name)
+	}
+
+	f := &family{
+		name:       name,
+		help:       help,
+		metricType: metricType,
+		labelNames: labelNames,
+		buckets:    buckets,
+		series:     make(map[string]*series),
+	}
+	r.families = append(r.families, f)
+	r.names[name] = struct{}{}
+
+	return f, nil
+}
+
+// Add will increase the counter of the provided label values. Negative values are ignored
+func (c *Counter) Add(value float64, labelValues ...string) {
+	if value < 0 {
+		return
+	}
+
+	c.family.update(labelValues, func(s *series) {
+		s.value += value
+	})
+}
+
+// Inc will increase the counter of the provided label values by one
+func (c *Counter) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Set will set the gauge of the provided label values
+func (g *Gauge) Set(value float64, labelValues ...string) {
+	g.family.update(labelValues, func(s *series) {
+		s.value = value
+	})
+}
+
+// Observe will add the provided value in the histogram of the provided label values
+func (h *Histogram) Observe(value float64, labelValues ...string) {
+	buckets := h.family.buckets
+	h.family.update(labelValues, func(s *series) {
+		if s.bucketCounts == nil {
+			s.bucketCounts = make([]uint64, len(buckets))
+		}
+
+		for idx, upperBound := range buckets {
+			if value <= upperBound {
+				s.bucketCounts[idx]++
+			}
+		}
+		s.value += value
+		s.count++
+	})
+}
+
+func (f *family) update(labelValues []string, handler func(s *series)) {
+	if len(labelValues) != len(f.labelNames) {
+		log.Warn("metrics: wrong number of label values, the value will be ignored",
+			"metric", f.name, "expected", len(f.labelNames), "provided", len(labelValues))
+		return
+	}
+
+	key := strings.Join(labelValues, labelValuesSeparator)
+
+	f.mutSeries.Lock()
+	defer f.mutSeries.Unlock()
+
+	s, found := f.series[key]
+	if !found {
+		s = &series{
+			labelValues: append([]string(nil), labelValues...),
+		}
+		f.series[key] = s
+	}
+
+	handler(s)
+}
+
+// Write will write the values of all the registered metrics in the Prometheus text exposition format
+func (r *Registry) Write(writer io.Writer) error {
+	r.mutFamilies.RLock()
+	families := make([]*family, len(r.families))
+	copy(families, r.families)
+	r.mutFamilies.RUnlock()
+
+	bufferedWriter := bufio.NewWriter(writer)
+	for _, f := range families {
+		f.write(bufferedWriter)
+	}
+
+	return bufferedWriter.Flush()
+}
+
+// ServeHTTP will respond with the values of all the registered metrics, so the registry can be mounted as a
+// scrape endpoint
+func (r *Registry) ServeHTTP(writer http.ResponseWriter, _ *http.Request) {
+	writer.Header().Set("Content-Type", ContentType)
+	err := r.Write(writer)
+	if err != nil {
+		log.Debug("metrics: cannot write the metrics response", "error", err.Error())
+	}
+}
+
+func (f *family) write(writer *bufio.Writer) {
+	f.mutSeries.Lock()
+	defer f.mutSeries.Unlock()
+
+	_, _ = fmt.Fprintf(writer, "# HELP %s %s\n", f.name, escapeHelp(f.help))
+	_, _ = fmt.Fprintf(writer, "# TYPE %s %s\n", f.name, f.metricType)
+
+	keys := make([]string, 0, len(f.series))
+	for key := range f.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		s := f.series[key]
+		if f.metricType != histogramType {
+			_, _ = fmt.Fprintf(writer, "%s%s %s\n", f.name, formatLabels(f.labelNames, s.labelValues, "", 0), formatValue(s.value))
+			continue
+		}
+
+		for idx, upperBound := range f.buckets {
+			_, _ = fmt.Fprintf(writer, "%s_bucket%s %d\n",
+				f.name, formatLabels(f.labelNames, s.labelValues, "le", upperBound), s.bucketCounts[idx])
+		}
+		_, _ = fmt.Fprintf(writer, "%s_bucket%s %d\n",
+			f.name, formatLabels(f.labelNames, s.labelValues, "le", math.Inf(1)), s.count)
+		_, _ = fmt.Fprintf(writer, "%s_sum%s %s\n", f.name, formatLabels(f.labelNames, s.labelValues, "", 0), formatValue(s.value))
+		_, _ = fmt.Fprintf(writer, "%s_count%s %d\n", f.name, formatLabels(f.labelNames, s.labelValues, "", 0), s.count)
+	}
+}
+
+// formatLabels will return the labels of a series, followed by the bucket label if its name is not empty
+func formatLabels(labelNames []string, labelValues []string, bucketLabel string, upperBound float64) string {
+	pairs := make([]string, 0, len(labelNames)+1)
+	for idx, labelName := range labelNames {
+		pairs = append(pairs, fmt.Sprintf("%s=\"%s\"", labelName, escapeLabelValue(labelValues[idx])))
+	}
+	if bucketLabel != "" {
+		pairs = append(pairs, fmt.Sprintf("%s=\"%s\"", bucketLabel, formatValue(upperBound)))
+	}
+
+	if len(pairs) == 0 {
+		return ""
+	}
+
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func formatValue(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	default:
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	}
+}
+
+func escapeHelp(help string) string {
+	help = strings.ReplaceAll(help, "\\", "\\\\")
+	return strings.ReplaceAll(help, "\n", "\\n")
+}
+
+func escapeLabelValue(value string) string {
+	value = strings.ReplaceAll(value, "\\", "\\\\")
+	value = strings.ReplaceAll(value, "\"", "\\\"")
+	return strings.ReplaceAll(value, "\n", "\\n")
+}
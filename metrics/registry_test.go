@@ -0,0 +1,86 @@
+package metrics
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_RegisterErrors(t *testing.T) {
+	t.Parallel()
+
+	registry := NewRegistry()
+
+	counter, err := registry.NewCounter("", "help")
+	require.Nil(t, counter)
+	require.Equal(t, ErrEmptyMetricName, err)
+
+	_, err = registry.NewGauge("metric", "help")
+	require.Nil(t, err)
+	counter, err = registry.NewCounter("metric", "help")
+	require.Nil(t, counter)
+	require.True(t, errors.Is(err, ErrMetricAlreadyRegistered))
+
+	histogram, err := registry.NewHistogram("histogram", "help", nil)
+	require.Nil(t, histogram)
+	require.True(t, errors.Is(err, ErrNoBuckets))
+}
+
+func TestRegistry_Write(t *testing.T) {
+	t.Parallel()
+
+	registry := NewRegistry()
+	counter, _ := registry.NewCounter("requests_total", "Number of requests", "index")
+	gauge, _ := registry.NewGauge("queue_depth", "Queue depth")
+	histogram, _ := registry.NewHistogram("duration_seconds", "Duration", []float64{1, 0.1}, "type")
+
+	counter.Inc("blocks")
+	counter.Add(2, "blocks")
+	counter.Add(-1, "blocks")
+	counter.Inc(`a"b`)
+	counter.Inc()
+	gauge.Set(5)
+	histogram.Observe(0.05, "item")
+	histogram.Observe(0.5, "item")
+	histogram.Observe(2, "item")
+
+	buff := &bytes.Buffer{}
+	err := registry.Write(buff)
+	require.Nil(t, err)
+
+	expected := `# HELP requests_total Number of requests
+# TYPE requests_total counter
+requests_total{index="a\"b"} 1
+requests_total{index="blocks"} 3
+# HELP queue_depth Queue depth
+# TYPE queue_depth gauge
+queue_depth 5
+# HELP duration_seconds Duration
+# TYPE duration_seconds histogram
+duration_seconds_bucket{type="item",le="0.1"} 1
+duration_seconds_bucket{type="item",le="1"} 2
+duration_seconds_bucket{type="item",le="+Inf"} 3
+duration_seconds_sum{type="item"} 2.55
+duration_seconds_count{type="item"} 3
+`
+	require.Equal(t, expected, buff.String())
+}
+
+func TestRegistry_ServeHTTP(t *testing.T) {
+	t.Parallel()
+
+	registry := NewRegistry()
+	gauge, _ := registry.NewGauge("queue_depth", "Queue depth")
+	gauge.Set(3)
+
+	recorder := httptest.NewRecorder()
+	registry.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, ContentType, recorder.Header().Get("Content-Type"))
+	require.Contains(t, recorder.Body.String(), "queue_depth 3\n")
+}
@@ -0,0 +1,32 @@
+package mock
+
+import "github.com/ME-MotherEarth/me-elastic-indexer/data"
+
+// ABIDecoderStub -
+type ABIDecoderStub struct {
+	DecodeCallCalled  func(contract []byte, function string, dataField []byte) *data.DecodedData
+	DecodeEventCalled func(contract []byte, topics [][]byte, eventData []byte) *data.DecodedData
+}
+
+// DecodeCall -
+func (ads *ABIDecoderStub) DecodeCall(contract []byte, function string, dataField []byte) *data.DecodedData {
+	if ads.DecodeCallCalled != nil {
+		return ads.DecodeCallCalled(contract, function, dataField)
+	}
+
+	return nil
+}
+
+// DecodeEvent -
+func (ads *ABIDecoderStub) DecodeEvent(contract []byte, topics [][]byte, eventData []byte) *data.DecodedData {
+	if ads.DecodeEventCalled != nil {
+		return ads.DecodeEventCalled(contract, topics, eventData)
+	}
+
+	return nil
+}
+
+// IsInterfaceNil -
+func (ads *ABIDecoderStub) IsInterfaceNil() bool {
+	return ads == nil
+}
@@ -0,0 +1,31 @@
+package mock
+
+import (
+	coreData "github.com/ME-MotherEarth/me-core/data"
+	"github.com/ME-MotherEarth/me-core/data/indexer"
+)
+
+// AccountsCacheStub -
+type AccountsCacheStub struct {
+	UpdateAlteredAccountsCalled func(alteredAccounts map[string]*indexer.AlteredAccount)
+	UpdateAccountsCalled        func(accounts []coreData.UserAccountHandler)
+}
+
+// UpdateAlteredAccounts -
+func (acs *AccountsCacheStub) UpdateAlteredAccounts(alteredAccounts map[string]*indexer.AlteredAccount) {
+	if acs.UpdateAlteredAccountsCalled != nil {
+		acs.UpdateAlteredAccountsCalled(alteredAccounts)
+	}
+}
+
+// UpdateAccounts -
+func (acs *AccountsCacheStub) UpdateAccounts(accounts []coreData.UserAccountHandler) {
+	if acs.UpdateAccountsCalled != nil {
+		acs.UpdateAccountsCalled(accounts)
+	}
+}
+
+// IsInterfaceNil -
+func (acs *AccountsCacheStub) IsInterfaceNil() bool {
+	return acs == nil
+}
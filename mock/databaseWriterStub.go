@@ -8,16 +8,29 @@ import (
 
 // DatabaseWriterStub -
 type DatabaseWriterStub struct {
-	DoRequestCalled           func(req *esapi.IndexRequest) error
-	DoBulkRequestCalled       func(buff *bytes.Buffer, index string) error
-	DoQueryRemoveCalled       func(index string, body *bytes.Buffer) error
-	DoMultiGetCalled          func(ids []string, index string, withSource bool, response interface{}) error
-	CheckAndCreateIndexCalled func(index string) error
-	DoScrollRequestCalled     func(index string, body []byte, withSource bool, handlerFunc func(responseBytes []byte) error) error
+	DoRequestCalled                     func(req *esapi.IndexRequest) error
+	DoBulkRequestCalled                 func(buff *bytes.Buffer, index string) error
+	DoQueryRemoveCalled                 func(index string, body *bytes.Buffer) error
+	DoMultiGetCalled                    func(ids []string, index string, withSource bool, response interface{}) error
+	CheckAndCreateIndexCalled           func(index string) error
+	DoScrollRequestCalled               func(index string, body []byte, withSource bool, handlerFunc func(responseBytes []byte) error) error
+	DoCountRequestCalled                func(index string, body []byte) (uint64, error)
+	DoRefreshCalled                     func(index string) error
+	CheckAndCreateAliasCalled           func(alias string, index string) error
+	CheckAndCreateWriteAliasCalled      func(alias string, index string) error
+	CheckAndCreateLifecyclePolicyCalled func(policyName string, policy *bytes.Buffer) error
+	CheckAndCreateTemplateCalled        func(templateName string, template *bytes.Buffer) error
+	GetTemplateCalled                   func(templateName string) ([]byte, error)
+	PutTemplateCalled                   func(templateName string, template *bytes.Buffer) error
+	GetMappingsCalled                   func(index string) (map[string][]byte, error)
+	PutMappingCalled                    func(index string, mapping *bytes.Buffer) error
 }
 
 // DoCountRequest -
-func (dwm *DatabaseWriterStub) DoCountRequest(_ string, _ []byte) (uint64, error) {
+func (dwm *DatabaseWriterStub) DoCountRequest(index string, body []byte) (uint64, error) {
+	if dwm.DoCountRequestCalled != nil {
+		return dwm.DoCountRequestCalled(index, body)
+	}
 	return 0, nil
 }
 
@@ -72,12 +85,42 @@ func (dwm *DatabaseWriterStub) CheckAndCreateIndex(index string) error {
 }
 
 // CheckAndCreateAlias -
-func (dwm *DatabaseWriterStub) CheckAndCreateAlias(_ string, _ string) error {
+func (dwm *DatabaseWriterStub) CheckAndCreateAlias(alias string, index string) error {
+	if dwm.CheckAndCreateAliasCalled != nil {
+		return dwm.CheckAndCreateAliasCalled(alias, index)
+	}
+	return nil
+}
+
+// CheckAndCreateWriteAlias -
+func (dwm *DatabaseWriterStub) CheckAndCreateWriteAlias(alias string, index string) error {
+	if dwm.CheckAndCreateWriteAliasCalled != nil {
+		return dwm.CheckAndCreateWriteAliasCalled(alias, index)
+	}
 	return nil
 }
 
 // CheckAndCreateTemplate -
-func (dwm *DatabaseWriterStub) CheckAndCreateTemplate(_ string, _ *bytes.Buffer) error {
+func (dwm *DatabaseWriterStub) CheckAndCreateTemplate(templateName string, template *bytes.Buffer) error {
+	if dwm.CheckAndCreateTemplateCalled != nil {
+		return dwm.CheckAndCreateTemplateCalled(templateName, template)
+	}
+	return nil
+}
+
+// CheckAndCreateLifecyclePolicy -
+func (dwm *DatabaseWriterStub) CheckAndCreateLifecyclePolicy(policyName string, policy *bytes.Buffer) error {
+	if dwm.CheckAndCreateLifecyclePolicyCalled != nil {
+		return dwm.CheckAndCreateLifecyclePolicyCalled(policyName, policy)
+	}
+	return nil
+}
+
+// DoRefresh -
+func (dwm *DatabaseWriterStub) DoRefresh(index string) error {
+	if dwm.DoRefreshCalled != nil {
+		return dwm.DoRefreshCalled(index)
+	}
 	return nil
 }
 
@@ -86,6 +129,38 @@ func (dwm *DatabaseWriterStub) CheckAndCreatePolicy(_ string, _ *bytes.Buffer) e
 	return nil
 }
 
+// GetTemplate -
+func (dwm *DatabaseWriterStub) GetTemplate(templateName string) ([]byte, error) {
+	if dwm.GetTemplateCalled != nil {
+		return dwm.GetTemplateCalled(templateName)
+	}
+	return nil, nil
+}
+
+// PutTemplate -
+func (dwm *DatabaseWriterStub) PutTemplate(templateName string, template *bytes.Buffer) error {
+	if dwm.PutTemplateCalled != nil {
+		return dwm.PutTemplateCalled(templateName, template)
+	}
+	return nil
+}
+
+// GetMappings -
+func (dwm *DatabaseWriterStub) GetMappings(index string) (map[string][]byte, error) {
+	if dwm.GetMappingsCalled != nil {
+		return dwm.GetMappingsCalled(index)
+	}
+	return make(map[string][]byte), nil
+}
+
+// PutMapping -
+func (dwm *DatabaseWriterStub) PutMapping(index string, mapping *bytes.Buffer) error {
+	if dwm.PutMappingCalled != nil {
+		return dwm.PutMappingCalled(index, mapping)
+	}
+	return nil
+}
+
 // IsInterfaceNil returns true if there is no value under the interface
 func (dwm *DatabaseWriterStub) IsInterfaceNil() bool {
 	return dwm == nil
@@ -7,7 +7,7 @@ import (
 // DBAccountsHandlerStub -
 type DBAccountsHandlerStub struct {
 	PrepareAccountsHistoryCalled   func(timestamp uint64, accounts map[string]*data.AccountInfo) map[string]*data.AccountBalanceHistory
-	SerializeAccountsHistoryCalled func(accounts map[string]*data.AccountBalanceHistory, buffSlice *data.BufferSlice, index string) error
+	SerializeAccountsHistoryCalled func(accounts map[string]*data.AccountBalanceHistory, docs *data.DocumentsSlice, index string) error
 }
 
 // GetAccounts -
@@ -34,26 +34,30 @@ func (dba *DBAccountsHandlerStub) PrepareAccountsHistory(timestamp uint64, accou
 	return nil
 }
 
+// PutBalanceNumWithDecimalsInAccountsMECT -
+func (dba *DBAccountsHandlerStub) PutBalanceNumWithDecimalsInAccountsMECT(_ map[string]*data.AccountInfo, _ map[string]uint64) {
+}
+
 // SerializeAccountsHistory -
-func (dba *DBAccountsHandlerStub) SerializeAccountsHistory(accounts map[string]*data.AccountBalanceHistory, buffSlice *data.BufferSlice, index string) error {
+func (dba *DBAccountsHandlerStub) SerializeAccountsHistory(accounts map[string]*data.AccountBalanceHistory, docs *data.DocumentsSlice, index string) error {
 	if dba.SerializeAccountsHistoryCalled != nil {
-		return dba.SerializeAccountsHistoryCalled(accounts, buffSlice, index)
+		return dba.SerializeAccountsHistoryCalled(accounts, docs, index)
 	}
 	return nil
 }
 
 // SerializeAccounts -
-func (dba *DBAccountsHandlerStub) SerializeAccounts(_ map[string]*data.AccountInfo, _ *data.BufferSlice, _ string) error {
+func (dba *DBAccountsHandlerStub) SerializeAccounts(_ map[string]*data.AccountInfo, _ *data.DocumentsSlice, _ string) error {
 	return nil
 }
 
 // SerializeAccountsMECT -
-func (dba *DBAccountsHandlerStub) SerializeAccountsMECT(_ map[string]*data.AccountInfo, _ []*data.NFTDataUpdate, _ *data.BufferSlice, _ string) error {
+func (dba *DBAccountsHandlerStub) SerializeAccountsMECT(_ map[string]*data.AccountInfo, _ []*data.NFTDataUpdate, _ *data.DocumentsSlice, _ string) error {
 	return nil
 }
 
 // SerializeNFTCreateInfo -
-func (dba *DBAccountsHandlerStub) SerializeNFTCreateInfo(_ []*data.TokenInfo, _ *data.BufferSlice, _ string) error {
+func (dba *DBAccountsHandlerStub) SerializeNFTCreateInfo(_ []*data.TokenInfo, _ *data.DocumentsSlice, _ string) error {
 	return nil
 }
 
@@ -62,6 +66,6 @@ func (dba *DBAccountsHandlerStub) PutTokenMedataDataInTokens(_ []*data.TokenInfo
 }
 
 // SerializeTypeForProvidedIDs -
-func (dba *DBAccountsHandlerStub) SerializeTypeForProvidedIDs(_ []string, _ string, _ *data.BufferSlice, _ string) error {
+func (dba *DBAccountsHandlerStub) SerializeTypeForProvidedIDs(_ []string, _ string, _ *data.DocumentsSlice, _ string) error {
 	return nil
 }
@@ -10,11 +10,11 @@ import (
 // DBTransactionProcessorStub -
 type DBTransactionProcessorStub struct {
 	PrepareTransactionsForDatabaseCalled func(body *block.Body, header coreData.HeaderHandler, pool *indexer.Pool) *data.PreparedResults
-	SerializeReceiptsCalled              func(recs []*data.Receipt, buffSlice *data.BufferSlice, index string) error
-	SerializeScResultsCalled             func(scrs []*data.ScResult, buffSlice *data.BufferSlice, index string) error
+	SerializeReceiptsCalled              func(recs []*data.Receipt, docs *data.DocumentsSlice, index string) error
+	SerializeScResultsCalled             func(scrs []*data.ScResult, docs *data.DocumentsSlice, index string) error
 }
 
-func (tps *DBTransactionProcessorStub) SerializeTransactionWithRefund(_ map[string]*data.Transaction, _ map[string]*data.RefundData, _ *data.BufferSlice, _ string) error {
+func (tps *DBTransactionProcessorStub) SerializeTransactionWithRefund(_ map[string]*data.Transaction, _ map[string]*data.RefundData, _ *data.DocumentsSlice, _ string) error {
 	return nil
 }
 
@@ -33,34 +33,34 @@ func (tps *DBTransactionProcessorStub) GetHexEncodedHashesForRemove(_ coreData.H
 }
 
 // SerializeReceipts -
-func (tps *DBTransactionProcessorStub) SerializeReceipts(recs []*data.Receipt, buffSlice *data.BufferSlice, index string) error {
+func (tps *DBTransactionProcessorStub) SerializeReceipts(recs []*data.Receipt, docs *data.DocumentsSlice, index string) error {
 	if tps.SerializeReceiptsCalled != nil {
-		return tps.SerializeReceiptsCalled(recs, buffSlice, index)
+		return tps.SerializeReceiptsCalled(recs, docs, index)
 	}
 
 	return nil
 }
 
 // SerializeTransactions -
-func (tps *DBTransactionProcessorStub) SerializeTransactions(_ []*data.Transaction, _ map[string]string, _ uint32, _ *data.BufferSlice, _ string) error {
+func (tps *DBTransactionProcessorStub) SerializeTransactions(_ []*data.Transaction, _ map[string]string, _ uint32, _ *data.DocumentsSlice, _ string) error {
 	return nil
 }
 
 // SerializeScResults -
-func (tps *DBTransactionProcessorStub) SerializeScResults(scrs []*data.ScResult, buffSlice *data.BufferSlice, index string) error {
+func (tps *DBTransactionProcessorStub) SerializeScResults(scrs []*data.ScResult, docs *data.DocumentsSlice, index string) error {
 	if tps.SerializeScResultsCalled != nil {
-		return tps.SerializeScResultsCalled(scrs, buffSlice, index)
+		return tps.SerializeScResultsCalled(scrs, docs, index)
 	}
 
 	return nil
 }
 
 // SerializeDeploysData -
-func (tps *DBTransactionProcessorStub) SerializeDeploysData(_ []*data.ScDeployInfo, _ *data.BufferSlice, _ string) error {
+func (tps *DBTransactionProcessorStub) SerializeDeploysData(_ []*data.ScDeployInfo, _ *data.DocumentsSlice, _ string) error {
 	return nil
 }
 
 // SerializeTokens -
-func (tps *DBTransactionProcessorStub) SerializeTokens(_ []*data.TokenInfo, _ *data.BufferSlice, _ string) error {
+func (tps *DBTransactionProcessorStub) SerializeTokens(_ []*data.TokenInfo, _ *data.DocumentsSlice, _ string) error {
 	return nil
 }
@@ -0,0 +1,45 @@
+package mock
+
+import (
+	"github.com/ME-MotherEarth/me-elastic-indexer/data"
+	"github.com/ME-MotherEarth/me-elastic-indexer/payload"
+)
+
+// DeadLetterStoreStub -
+type DeadLetterStoreStub struct {
+	PutCalled                  func(itemType string, p *payload.Payload, reason error) error
+	PutRejectedDocumentsCalled func(itemType string, p *payload.Payload, documents []*data.RejectedDocument) error
+	CloseCalled                func() error
+}
+
+// Put -
+func (dlss *DeadLetterStoreStub) Put(itemType string, p *payload.Payload, reason error) error {
+	if dlss.PutCalled != nil {
+		return dlss.PutCalled(itemType, p, reason)
+	}
+
+	return nil
+}
+
+// PutRejectedDocuments -
+func (dlss *DeadLetterStoreStub) PutRejectedDocuments(itemType string, p *payload.Payload, documents []*data.RejectedDocument) error {
+	if dlss.PutRejectedDocumentsCalled != nil {
+		return dlss.PutRejectedDocumentsCalled(itemType, p, documents)
+	}
+
+	return nil
+}
+
+// Close -
+func (dlss *DeadLetterStoreStub) Close() error {
+	if dlss.CloseCalled != nil {
+		return dlss.CloseCalled()
+	}
+
+	return nil
+}
+
+// IsInterfaceNil -
+func (dlss *DeadLetterStoreStub) IsInterfaceNil() bool {
+	return dlss == nil
+}
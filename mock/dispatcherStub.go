@@ -6,7 +6,7 @@ import "github.com/ME-MotherEarth/me-elastic-indexer/workItems"
 type DispatcherMock struct {
 	StartIndexDataCalled func()
 	CloseCalled          func() error
-	AddCalled            func(item workItems.WorkItemHandler)
+	AddCalled            func(item workItems.WorkItemHandler) error
 }
 
 // StartIndexData -
@@ -25,10 +25,11 @@ func (dm *DispatcherMock) Close() error {
 }
 
 // Add -
-func (dm *DispatcherMock) Add(item workItems.WorkItemHandler) {
+func (dm *DispatcherMock) Add(item workItems.WorkItemHandler) error {
 	if dm.AddCalled != nil {
-		dm.AddCalled(item)
+		return dm.AddCalled(item)
 	}
+	return nil
 }
 
 // IsInterfaceNil returns true if there is no value under the interface
@@ -0,0 +1,54 @@
+package mock
+
+import (
+	"github.com/ME-MotherEarth/me-elastic-indexer/data"
+)
+
+// DocumentsSinkStub -
+type DocumentsSinkStub struct {
+	WriteDocumentsCalled     func(documents []*data.Document) error
+	GetDocumentsCalled       func(index string, ids []string) (map[string][]byte, error)
+	IterateMatchingIDsCalled func(index string, fields map[string]interface{}, handlerFunc func(ids []string) error) error
+	DeleteMatchingCalled     func(index string, fields map[string]interface{}) error
+}
+
+// WriteDocuments -
+func (dss *DocumentsSinkStub) WriteDocuments(documents []*data.Document) error {
+	if dss.WriteDocumentsCalled != nil {
+		return dss.WriteDocumentsCalled(documents)
+	}
+
+	return nil
+}
+
+// GetDocuments -
+func (dss *DocumentsSinkStub) GetDocuments(index string, ids []string) (map[string][]byte, error) {
+	if dss.GetDocumentsCalled != nil {
+		return dss.GetDocumentsCalled(index, ids)
+	}
+
+	return make(map[string][]byte), nil
+}
+
+// IterateMatchingIDs -
+func (dss *DocumentsSinkStub) IterateMatchingIDs(index string, fields map[string]interface{}, handlerFunc func(ids []string) error) error {
+	if dss.IterateMatchingIDsCalled != nil {
+		return dss.IterateMatchingIDsCalled(index, fields, handlerFunc)
+	}
+
+	return nil
+}
+
+// DeleteMatching -
+func (dss *DocumentsSinkStub) DeleteMatching(index string, fields map[string]interface{}) error {
+	if dss.DeleteMatchingCalled != nil {
+		return dss.DeleteMatchingCalled(index, fields)
+	}
+
+	return nil
+}
+
+// IsInterfaceNil -
+func (dss *DocumentsSinkStub) IsInterfaceNil() bool {
+	return dss == nil
+}
@@ -23,11 +23,17 @@ type ElasticProcessorStub struct {
 	RemoveTransactionsCalled         func(header coreData.HeaderHandler, body *block.Body) error
 	SaveMiniblocksCalled             func(header coreData.HeaderHandler, body *block.Body) error
 	SaveTransactionsCalled           func(body *block.Body, header coreData.HeaderHandler, pool *indexer.Pool) error
+	PrepareTransactionsCalled        func(body *block.Body, header coreData.HeaderHandler, pool *indexer.Pool) (*data.PreparedBlockTransactions, error)
+	SavePreparedTransactionsCalled   func(header coreData.HeaderHandler, pool *indexer.Pool, preparedTxs *data.PreparedBlockTransactions) error
 	SaveValidatorsRatingCalled       func(index string, validatorsRatingInfo []*data.ValidatorRatingInfo) error
 	SaveRoundsInfoCalled             func(infos []*data.RoundInfo) error
 	SaveShardValidatorsPubKeysCalled func(shardID, epoch uint32, shardValidatorsPubKeys [][]byte) error
 	SaveAccountsCalled               func(timestamp uint64, acc []*data.Account) error
 	RemoveAccountsMECTCalled         func(headerTimestamp uint64) error
+	FinalizeBlockCalled              func(headerHash []byte) error
+	RevertBlockChangesCalled         func(header coreData.HeaderHandler) error
+	SaveCheckpointCalled             func(headerHash []byte, header coreData.HeaderHandler) error
+	GetCheckpointsCalled             func(shardIDs []uint32) ([]*data.Checkpoint, error)
 }
 
 // RemoveAccountsMECT -
@@ -94,6 +100,22 @@ func (eim *ElasticProcessorStub) SaveTransactions(body *block.Body, header coreD
 	return nil
 }
 
+// PrepareTransactions -
+func (eim *ElasticProcessorStub) PrepareTransactions(body *block.Body, header coreData.HeaderHandler, pool *indexer.Pool) (*data.PreparedBlockTransactions, error) {
+	if eim.PrepareTransactionsCalled != nil {
+		return eim.PrepareTransactionsCalled(body, header, pool)
+	}
+	return &data.PreparedBlockTransactions{}, nil
+}
+
+// SavePreparedTransactions -
+func (eim *ElasticProcessorStub) SavePreparedTransactions(header coreData.HeaderHandler, pool *indexer.Pool, preparedTxs *data.PreparedBlockTransactions) error {
+	if eim.SavePreparedTransactionsCalled != nil {
+		return eim.SavePreparedTransactionsCalled(header, pool, preparedTxs)
+	}
+	return nil
+}
+
 // SaveValidatorsRating -
 func (eim *ElasticProcessorStub) SaveValidatorsRating(index string, validatorsRatingInfo []*data.ValidatorRatingInfo) error {
 	if eim.SaveValidatorsRatingCalled != nil {
@@ -127,6 +149,42 @@ func (eim *ElasticProcessorStub) SaveAccounts(timestamp uint64, acc []*data.Acco
 	return nil
 }
 
+// RevertBlockChanges -
+func (eim *ElasticProcessorStub) RevertBlockChanges(header coreData.HeaderHandler) error {
+	if eim.RevertBlockChangesCalled != nil {
+		return eim.RevertBlockChangesCalled(header)
+	}
+
+	return nil
+}
+
+// FinalizeBlock -
+func (eim *ElasticProcessorStub) FinalizeBlock(headerHash []byte) error {
+	if eim.FinalizeBlockCalled != nil {
+		return eim.FinalizeBlockCalled(headerHash)
+	}
+
+	return nil
+}
+
+// SaveCheckpoint -
+func (eim *ElasticProcessorStub) SaveCheckpoint(headerHash []byte, header coreData.HeaderHandler) error {
+	if eim.SaveCheckpointCalled != nil {
+		return eim.SaveCheckpointCalled(headerHash, header)
+	}
+
+	return nil
+}
+
+// GetCheckpoints -
+func (eim *ElasticProcessorStub) GetCheckpoints(shardIDs []uint32) ([]*data.Checkpoint, error) {
+	if eim.GetCheckpointsCalled != nil {
+		return eim.GetCheckpointsCalled(shardIDs)
+	}
+
+	return nil, nil
+}
+
 // IsInterfaceNil returns true if there is no value under the interface
 func (eim *ElasticProcessorStub) IsInterfaceNil() bool {
 	return eim == nil
@@ -0,0 +1,45 @@
+package mock
+
+import (
+	coreData "github.com/ME-MotherEarth/me-core/data"
+	"github.com/ME-MotherEarth/me-elastic-indexer/data"
+)
+
+// EventsPublisherStub -
+type EventsPublisherStub struct {
+	PublishBlockCalled        func(headerHash []byte, header coreData.HeaderHandler, block *data.Block) error
+	PublishTransactionsCalled func(headerHash []byte, header coreData.HeaderHandler, preparedTxs *data.PreparedBlockTransactions) error
+	PublishRevertCalled       func(headerHash []byte, header coreData.HeaderHandler) error
+}
+
+// PublishBlock -
+func (eps *EventsPublisherStub) PublishBlock(headerHash []byte, header coreData.HeaderHandler, block *data.Block) error {
+	if eps.PublishBlockCalled != nil {
+		return eps.PublishBlockCalled(headerHash, header, block)
+	}
+
+	return nil
+}
+
+// PublishTransactions -
+func (eps *EventsPublisherStub) PublishTransactions(headerHash []byte, header coreData.HeaderHandler, preparedTxs *data.PreparedBlockTransactions) error {
+	if eps.PublishTransactionsCalled != nil {
+		return eps.PublishTransactionsCalled(headerHash, header, preparedTxs)
+	}
+
+	return nil
+}
+
+// PublishRevert -
+func (eps *EventsPublisherStub) PublishRevert(headerHash []byte, header coreData.HeaderHandler) error {
+	if eps.PublishRevertCalled != nil {
+		return eps.PublishRevertCalled(headerHash, header)
+	}
+
+	return nil
+}
+
+// IsInterfaceNil -
+func (eps *EventsPublisherStub) IsInterfaceNil() bool {
+	return eps == nil
+}
@@ -0,0 +1,111 @@
+package mock
+
+import (
+	coreData "github.com/ME-MotherEarth/me-core/data"
+	"github.com/ME-MotherEarth/me-core/data/indexer"
+	"github.com/ME-MotherEarth/me-elastic-indexer/data"
+)
+
+// IndexerStub -
+type IndexerStub struct {
+	SaveBlockCalled             func(args *indexer.ArgsSaveBlockData) error
+	RevertIndexedBlockCalled    func(header coreData.HeaderHandler, body coreData.BodyHandler) error
+	SaveRoundsInfoCalled        func(roundsInfos []*indexer.RoundInfo) error
+	SaveValidatorsPubKeysCalled func(validatorsPubKeys map[uint32][][]byte, epoch uint32) error
+	SaveValidatorsRatingCalled  func(indexID string, infoRating []*indexer.ValidatorRatingInfo) error
+	SaveAccountsCalled          func(blockTimestamp uint64, acc []coreData.UserAccountHandler) error
+	FinalizedBlockCalled        func(headerHash []byte) error
+	GetCheckpointsCalled        func() ([]*data.Checkpoint, error)
+	CloseCalled                 func() error
+}
+
+// SaveBlock -
+func (is *IndexerStub) SaveBlock(args *indexer.ArgsSaveBlockData) error {
+	if is.SaveBlockCalled != nil {
+		return is.SaveBlockCalled(args)
+	}
+
+	return nil
+}
+
+// RevertIndexedBlock -
+func (is *IndexerStub) RevertIndexedBlock(header coreData.HeaderHandler, body coreData.BodyHandler) error {
+	if is.RevertIndexedBlockCalled != nil {
+		return is.RevertIndexedBlockCalled(header, body)
+	}
+
+	return nil
+}
+
+// SaveRoundsInfo -
+func (is *IndexerStub) SaveRoundsInfo(roundsInfos []*indexer.RoundInfo) error {
+	if is.SaveRoundsInfoCalled != nil {
+		return is.SaveRoundsInfoCalled(roundsInfos)
+	}
+
+	return nil
+}
+
+// SaveValidatorsPubKeys -
+func (is *IndexerStub) SaveValidatorsPubKeys(validatorsPubKeys map[uint32][][]byte, epoch uint32) error {
+	if is.SaveValidatorsPubKeysCalled != nil {
+		return is.SaveValidatorsPubKeysCalled(validatorsPubKeys, epoch)
+	}
+
+	return nil
+}
+
+// SaveValidatorsRating -
+func (is *IndexerStub) SaveValidatorsRating(indexID string, infoRating []*indexer.ValidatorRatingInfo) error {
+	if is.SaveValidatorsRatingCalled != nil {
+		return is.SaveValidatorsRatingCalled(indexID, infoRating)
+	}
+
+	return nil
+}
+
+// SaveAccounts -
+func (is *IndexerStub) SaveAccounts(blockTimestamp uint64, acc []coreData.UserAccountHandler) error {
+	if is.SaveAccountsCalled != nil {
+		return is.SaveAccountsCalled(blockTimestamp, acc)
+	}
+
+	return nil
+}
+
+// FinalizedBlock -
+func (is *IndexerStub) FinalizedBlock(headerHash []byte) error {
+	if is.FinalizedBlockCalled != nil {
+		return is.FinalizedBlockCalled(headerHash)
+	}
+
+	return nil
+}
+
+// GetCheckpoints -
+func (is *IndexerStub) GetCheckpoints() ([]*data.Checkpoint, error) {
+	if is.GetCheckpointsCalled != nil {
+		return is.GetCheckpointsCalled()
+	}
+
+	return nil, nil
+}
+
+// Close -
+func (is *IndexerStub) Close() error {
+	if is.CloseCalled != nil {
+		return is.CloseCalled()
+	}
+
+	return nil
+}
+
+// IsInterfaceNil -
+func (is *IndexerStub) IsInterfaceNil() bool {
+	return is == nil
+}
+
+// IsNilIndexer -
+func (is *IndexerStub) IsNilIndexer() bool {
+	return false
+}
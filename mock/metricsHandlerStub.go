@@ -0,0 +1,52 @@
+package mock
+
+import "time"
+
+// MetricsHandlerStub -
+type MetricsHandlerStub struct {
+	SetQueueDepthCalled       func(depth int)
+	SetBackOffCalled          func(backOff time.Duration)
+	ObserveBulkRequestCalled  func(index string, sizeInBytes int, duration time.Duration, err error)
+	ObserveWorkItemCalled     func(itemType string, duration time.Duration, err error)
+	SetLastIndexedNonceCalled func(shardID uint32, nonce uint64)
+}
+
+// SetQueueDepth -
+func (mhs *MetricsHandlerStub) SetQueueDepth(depth int) {
+	if mhs.SetQueueDepthCalled != nil {
+		mhs.SetQueueDepthCalled(depth)
+	}
+}
+
+// SetBackOff -
+func (mhs *MetricsHandlerStub) SetBackOff(backOff time.Duration) {
+	if mhs.SetBackOffCalled != nil {
+		mhs.SetBackOffCalled(backOff)
+	}
+}
+
+// ObserveBulkRequest -
+func (mhs *MetricsHandlerStub) ObserveBulkRequest(index string, sizeInBytes int, duration time.Duration, err error) {
+	if mhs.ObserveBulkRequestCalled != nil {
+		mhs.ObserveBulkRequestCalled(index, sizeInBytes, duration, err)
+	}
+}
+
+// ObserveWorkItem -
+func (mhs *MetricsHandlerStub) ObserveWorkItem(itemType string, duration time.Duration, err error) {
+	if mhs.ObserveWorkItemCalled != nil {
+		mhs.ObserveWorkItemCalled(itemType, duration, err)
+	}
+}
+
+// SetLastIndexedNonce -
+func (mhs *MetricsHandlerStub) SetLastIndexedNonce(shardID uint32, nonce uint64) {
+	if mhs.SetLastIndexedNonceCalled != nil {
+		mhs.SetLastIndexedNonceCalled(shardID, nonce)
+	}
+}
+
+// IsInterfaceNil -
+func (mhs *MetricsHandlerStub) IsInterfaceNil() bool {
+	return mhs == nil
+}
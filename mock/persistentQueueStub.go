@@ -0,0 +1,62 @@
+package mock
+
+import "github.com/ME-MotherEarth/me-elastic-indexer/queue"
+
+// PersistentQueueStub -
+type PersistentQueueStub struct {
+	AppendCalled     func(itemData []byte) (uint64, error)
+	AckCalled        func(id uint64) error
+	QuarantineCalled func(record *queue.Record) error
+	PendingCalled    func() []*queue.Record
+	CloseCalled      func() error
+}
+
+// Append -
+func (pqs *PersistentQueueStub) Append(itemData []byte) (uint64, error) {
+	if pqs.AppendCalled != nil {
+		return pqs.AppendCalled(itemData)
+	}
+
+	return 0, nil
+}
+
+// Ack -
+func (pqs *PersistentQueueStub) Ack(id uint64) error {
+	if pqs.AckCalled != nil {
+		return pqs.AckCalled(id)
+	}
+
+	return nil
+}
+
+// Quarantine -
+func (pqs *PersistentQueueStub) Quarantine(record *queue.Record) error {
+	if pqs.QuarantineCalled != nil {
+		return pqs.QuarantineCalled(record)
+	}
+
+	return nil
+}
+
+// Pending -
+func (pqs *PersistentQueueStub) Pending() []*queue.Record {
+	if pqs.PendingCalled != nil {
+		return pqs.PendingCalled()
+	}
+
+	return nil
+}
+
+// Close -
+func (pqs *PersistentQueueStub) Close() error {
+	if pqs.CloseCalled != nil {
+		return pqs.CloseCalled()
+	}
+
+	return nil
+}
+
+// IsInterfaceNil -
+func (pqs *PersistentQueueStub) IsInterfaceNil() bool {
+	return pqs == nil
+}
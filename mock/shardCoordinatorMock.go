@@ -7,12 +7,13 @@ import (
 // ShardCoordinatorMock -
 type ShardCoordinatorMock struct {
 	SelfID          uint32
+	NumOfShards     uint32
 	ComputeIdCalled func(address []byte) uint32
 }
 
 // NumberOfShards -
 func (scm *ShardCoordinatorMock) NumberOfShards() uint32 {
-	panic("implement me")
+	return scm.NumOfShards
 }
 
 // ComputeId -
@@ -3,6 +3,7 @@ package indexer
 import (
 	"github.com/ME-MotherEarth/me-core/data"
 	"github.com/ME-MotherEarth/me-core/data/indexer"
+	elasticData "github.com/ME-MotherEarth/me-elastic-indexer/data"
 )
 
 // NilIndexer will be used when an Indexer is required, but another one isn't necessary or available
@@ -54,6 +55,11 @@ func (ni *NilIndexer) FinalizedBlock(_ []byte) error {
 	return nil
 }
 
+// GetCheckpoints returns nil
+func (ni *NilIndexer) GetCheckpoints() ([]*elasticData.Checkpoint, error) {
+	return nil, nil
+}
+
 // IsInterfaceNil returns true if there is no value under the interface
 func (ni *NilIndexer) IsInterfaceNil() bool {
 	return ni == nil
@@ -0,0 +1,139 @@
+package outport
+
+import (
+	"container/list"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ME-MotherEarth/me-core/core"
+	"github.com/ME-MotherEarth/me-core/core/check"
+	coreData "github.com/ME-MotherEarth/me-core/data"
+	"github.com/ME-MotherEarth/me-core/data/indexer"
+	"github.com/ME-MotherEarth/me-elastic-indexer/payload"
+	vmcommon "github.com/ME-MotherEarth/me-vm-common"
+)
+
+// ArgsAccountsCache holds all dependencies required by the accounts cache in order to create new instances
+type ArgsAccountsCache struct {
+	AddressPubkeyConverter core.PubkeyConverter
+	MaxAccounts            int
+}
+
+type accountsCache struct {
+	addressPubkeyConverter core.PubkeyConverter
+	maxAccounts            int
+
+	mutAccounts sync.Mutex
+	accounts    map[string]*list.Element
+	updateOrder *list.List
+}
+
+// cachedAccount is an account snapshot that can be returned as an account handler
+type cachedAccount struct {
+	*payload.Account
+}
+
+// IncreaseNonce does nothing, as a snapshot is never changed
+func (ca *cachedAccount) IncreaseNonce(_ uint64) {
+}
+
+// NewAccountsCache will create an accounts adapter that serves the accounts received from the node. A standalone
+// indexer has no access to the state of the node, so it uses the latest snapshot received for every account. The data
+// tries are not available, so the MECT data of the accounts cannot be loaded from it. When MaxAccounts is reached,
+// the account updated least recently is evicted
+func NewAccountsCache(args ArgsAccountsCache) (*accountsCache, error) {
+	if check.IfNil(args.AddressPubkeyConverter) {
+		return nil, ErrNilPubkeyConverter
+	}
+	if args.MaxAccounts <= 0 {
+		return nil, fmt.Errorf("%w: %d", ErrInvalidMaxAccounts, args.MaxAccounts)
+	}
+
+	return &accountsCache{
+		addressPubkeyConverter: args.AddressPubkeyConverter,
+		maxAccounts:            args.MaxAccounts,
+		accounts:               make(map[string]*list.Element),
+		updateOrder:            list.New(),
+	}, nil
+}
+
+// UpdateAlteredAccounts will keep the accounts altered by a block
+func (ac *accountsCache) UpdateAlteredAccounts(alteredAccounts map[string]*indexer.AlteredAccount) {
+	ac.mutAccounts.Lock()
+	defer ac.mutAccounts.Unlock()
+
+	for _, altered := range alteredAccounts {
+		if altered == nil {
+			continue
+		}
+
+		address, err := ac.addressPubkeyConverter.Decode(altered.Address)
+		if err != nil {
+			log.Warn("accountsCache.UpdateAlteredAccounts cannot decode address", "address", altered.Address, "error", err)
+			continue
+		}
+
+		balance, ok := big.NewInt(0).SetString(altered.Balance, 10)
+		if !ok {
+			balance = big.NewInt(0)
+		}
+
+		ac.put(&cachedAccount{Account: &payload.Account{
+			Address: address,
+			Balance: balance,
+			Nonce:   altered.Nonce,
+		}})
+	}
+}
+
+// UpdateAccounts will keep the provided accounts
+func (ac *accountsCache) UpdateAccounts(accounts []coreData.UserAccountHandler) {
+	ac.mutAccounts.Lock()
+	defer ac.mutAccounts.Unlock()
+
+	for _, account := range accounts {
+		if check.IfNil(account) {
+			continue
+		}
+
+		ac.put(&cachedAccount{Account: payload.NewAccountSnapshot(account)})
+	}
+}
+
+func (ac *accountsCache) put(account *cachedAccount) {
+	key := string(account.Address)
+	element, found := ac.accounts[key]
+	if found {
+		element.Value = account
+		ac.updateOrder.MoveToBack(element)
+		return
+	}
+
+	ac.accounts[key] = ac.updateOrder.PushBack(account)
+	if ac.updateOrder.Len() <= ac.maxAccounts {
+		return
+	}
+
+	oldest := ac.updateOrder.Front()
+	ac.updateOrder.Remove(oldest)
+	delete(ac.accounts, string(oldest.Value.(*cachedAccount).Address))
+}
+
+// LoadAccount returns the latest snapshot of the account with the provided address
+func (ac *accountsCache) LoadAccount(address []byte) (vmcommon.AccountHandler, error) {
+	ac.mutAccounts.Lock()
+	defer ac.mutAccounts.Unlock()
+
+	element, found := ac.accounts[string(address)]
+	if !found {
+		return nil, fmt.Errorf("%w: %s", ErrAccountNotFound, ac.addressPubkeyConverter.Encode(address))
+	}
+
+	return element.Value.(*cachedAccount), nil
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (ac *accountsCache) IsInterfaceNil() bool {
+	return ac == nil
+}
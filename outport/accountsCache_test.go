@@ -0,0 +1,74 @@
+package outport
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	coreData "github.com/ME-MotherEarth/me-core/data"
+	"github.com/ME-MotherEarth/me-core/data/indexer"
+	"github.com/ME-MotherEarth/me-elastic-indexer/mock"
+	"github.com/ME-MotherEarth/me-elastic-indexer/payload"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewAccountsCache(t *testing.T) {
+	t.Parallel()
+
+	cache, err := NewAccountsCache(ArgsAccountsCache{MaxAccounts: 1})
+	require.Nil(t, cache)
+	require.Equal(t, ErrNilPubkeyConverter, err)
+
+	cache, err = NewAccountsCache(ArgsAccountsCache{AddressPubkeyConverter: mock.NewPubkeyConverterMock(32)})
+	require.Nil(t, cache)
+	require.True(t, errors.Is(err, ErrInvalidMaxAccounts))
+
+	cache, err = NewAccountsCache(ArgsAccountsCache{AddressPubkeyConverter: mock.NewPubkeyConverterMock(32), MaxAccounts: 1})
+	require.Nil(t, err)
+	require.False(t, cache.IsInterfaceNil())
+}
+
+func TestAccountsCache_LoadAccountShouldReturnLatestSnapshot(t *testing.T) {
+	t.Parallel()
+
+	cache, _ := NewAccountsCache(ArgsAccountsCache{AddressPubkeyConverter: mock.NewPubkeyConverterMock(32), MaxAccounts: 10})
+
+	_, err := cache.LoadAccount([]byte("a1"))
+	require.True(t, errors.Is(err, ErrAccountNotFound))
+
+	cache.UpdateAlteredAccounts(map[string]*indexer.AlteredAccount{
+		"6131": {Address: "6131", Balance: "100", Nonce: 2},
+		"bad":  {Address: "not hex", Balance: "1"},
+	})
+	account, err := cache.LoadAccount([]byte("a1"))
+	require.Nil(t, err)
+	require.Equal(t, uint64(2), account.GetNonce())
+	require.Equal(t, big.NewInt(100), account.(coreData.UserAccountHandler).GetBalance())
+
+	cache.UpdateAccounts([]coreData.UserAccountHandler{&payload.Account{Address: []byte("a1"), Balance: big.NewInt(5), Nonce: 3}})
+	account, _ = cache.LoadAccount([]byte("a1"))
+	require.Equal(t, uint64(3), account.GetNonce())
+	require.Equal(t, big.NewInt(5), account.(coreData.UserAccountHandler).GetBalance())
+}
+
+func TestAccountsCache_ShouldEvictLeastRecentlyUpdatedAccount(t *testing.T) {
+	t.Parallel()
+
+	cache, _ := NewAccountsCache(ArgsAccountsCache{AddressPubkeyConverter: mock.NewPubkeyConverterMock(32), MaxAccounts: 2})
+	cache.UpdateAccounts([]coreData.UserAccountHandler{
+		&payload.Account{Address: []byte("a1")},
+		&payload.Account{Address: []byte("a2")},
+	})
+	cache.UpdateAccounts([]coreData.UserAccountHandler{&payload.Account{Address: []byte("a1"), Nonce: 1}})
+	cache.UpdateAccounts([]coreData.UserAccountHandler{&payload.Account{Address: []byte("a3")}})
+
+	_, err := cache.LoadAccount([]byte("a2"))
+	require.True(t, errors.Is(err, ErrAccountNotFound))
+
+	account, err := cache.LoadAccount([]byte("a1"))
+	require.Nil(t, err)
+	require.Equal(t, uint64(1), account.GetNonce())
+
+	_, err = cache.LoadAccount([]byte("a3"))
+	require.Nil(t, err)
+}
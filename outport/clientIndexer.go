@@ -0,0 +1,240 @@
+package outport
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/ME-MotherEarth/me-core/core/check"
+	coreData "github.com/ME-MotherEarth/me-core/data"
+	"github.com/ME-MotherEarth/me-core/data/indexer"
+	"github.com/ME-MotherEarth/me-elastic-indexer/data"
+	"github.com/ME-MotherEarth/me-elastic-indexer/payload"
+)
+
+const (
+	maxSendAttempts    = 3
+	delayBetweenSends  = time.Second
+	defaultDialTimeout = 5 * time.Second
+)
+
+// ArgsClientIndexer holds all dependencies required by the client indexer in order to create new instances
+type ArgsClientIndexer struct {
+	Address         string
+	Codec           PayloadCodec
+	ResponseTimeout time.Duration
+}
+
+type clientIndexer struct {
+	address         string
+	codec           PayloadCodec
+	responseTimeout time.Duration
+	delayOnError    time.Duration
+
+	mutConnection sync.Mutex
+	conn          net.Conn
+	reader        *bufio.Reader
+	nextID        uint64
+	closed        bool
+}
+
+// NewClientIndexer will create an indexer that sends every call to a standalone indexer daemon and waits for its
+// acknowledgement. The node can use it as a drop-in replacement of the indexer created by the factory. The connection
+// is opened on the first call and is opened again whenever it breaks
+func NewClientIndexer(args ArgsClientIndexer) (*clientIndexer, error) {
+	if args.Address == "" {
+		return nil, ErrEmptyAddress
+	}
+	if check.IfNil(args.Codec) {
+		return nil, ErrNilPayloadCodec
+	}
+	if args.ResponseTimeout <= 0 {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidResponseTimeout, args.ResponseTimeout)
+	}
+
+	return &clientIndexer{
+		address:         args.Address,
+		codec:           args.Codec,
+		responseTimeout: args.ResponseTimeout,
+		delayOnError:    delayBetweenSends,
+	}, nil
+}
+
+// SaveBlock will send the block to the indexer daemon
+func (ci *clientIndexer) SaveBlock(args *indexer.ArgsSaveBlockData) error {
+	return ci.sendPayload(payload.NewSaveBlock(args))
+}
+
+// RevertIndexedBlock will send the reverted block to the indexer daemon
+func (ci *clientIndexer) RevertIndexedBlock(header coreData.HeaderHandler, body coreData.BodyHandler) error {
+	return ci.sendPayload(payload.NewRevertIndexedBlock(header, body))
+}
+
+// SaveRoundsInfo will send the rounds info to the indexer daemon
+func (ci *clientIndexer) SaveRoundsInfo(roundsInfos []*indexer.RoundInfo) error {
+	return ci.sendPayload(payload.NewSaveRoundsInfo(roundsInfos))
+}
+
+// SaveValidatorsPubKeys will send the validators public keys to the indexer daemon
+func (ci *clientIndexer) SaveValidatorsPubKeys(validatorsPubKeys map[uint32][][]byte, epoch uint32) error {
+	return ci.sendPayload(payload.NewSaveValidatorsPubKeys(validatorsPubKeys, epoch))
+}
+
+// SaveValidatorsRating will send the validators rating to the indexer daemon
+func (ci *clientIndexer) SaveValidatorsRating(indexID string, infoRating []*indexer.ValidatorRatingInfo) error {
+	return ci.sendPayload(payload.NewSaveValidatorsRating(indexID, infoRating))
+}
+
+// SaveAccounts will send the accounts to the indexer daemon
+func (ci *clientIndexer) SaveAccounts(blockTimestamp uint64, accounts []coreData.UserAccountHandler) error {
+	return ci.sendPayload(payload.NewSaveAccounts(blockTimestamp, accounts))
+}
+
+// FinalizedBlock will send the hash of the finalized block to the indexer daemon
+func (ci *clientIndexer) FinalizedBlock(headerHash []byte) error {
+	return ci.sendPayload(payload.NewFinalizedBlock(headerHash))
+}
+
+// GetCheckpoints returns the checkpoints of the indexer daemon
+func (ci *clientIndexer) GetCheckpoints() ([]*data.Checkpoint, error) {
+	response, err := ci.send(kindGetCheckpoints, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.Checkpoints, nil
+}
+
+func (ci *clientIndexer) sendPayload(p *payload.Payload) error {
+	body, err := ci.codec.Encode(p)
+	if err != nil {
+		return err
+	}
+
+	_, err = ci.send(kindPayload, body)
+	return err
+}
+
+// send will write the frame and will wait for its acknowledgement. If the connection breaks, the frame is sent again
+// on a new connection, so the daemon can receive it twice. An error returned by the daemon is not retried
+func (ci *clientIndexer) send(kind frameKind, body []byte) (*ack, error) {
+	ci.mutConnection.Lock()
+	defer ci.mutConnection.Unlock()
+
+	if ci.closed {
+		return nil, ErrClientClosed
+	}
+
+	ci.nextID++
+	f := &frame{
+		version: ProtocolVersion,
+		kind:    kind,
+		id:      ci.nextID,
+		body:    body,
+	}
+
+	var err error
+	for attempt := 1; attempt <= maxSendAttempts; attempt++ {
+		var response *ack
+		response, err = ci.exchange(f)
+		if err == nil {
+			if response.Error != "" {
+				return nil, fmt.Errorf("%w: %s", ErrRemoteIndexer, response.Error)
+			}
+
+			return response, nil
+		}
+
+		log.Warn("clientIndexer cannot send frame to the indexer daemon",
+			"address", ci.address, "id", f.id, "attempt", attempt, "error", err)
+		ci.closeConnection()
+		if attempt < maxSendAttempts {
+			time.Sleep(ci.delayOnError)
+		}
+	}
+
+	return nil, err
+}
+
+func (ci *clientIndexer) exchange(f *frame) (*ack, error) {
+	err := ci.openConnection()
+	if err != nil {
+		return nil, err
+	}
+
+	err = ci.conn.SetDeadline(time.Now().Add(ci.responseTimeout))
+	if err != nil {
+		return nil, err
+	}
+
+	err = writeFrame(ci.conn, f)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := readFrame(ci.reader)
+	if err != nil {
+		return nil, err
+	}
+	if response.kind != kindAck || response.id != f.id {
+		return nil, fmt.Errorf("%w: kind %d, id %d, expected id %d", ErrUnexpectedAck, response.kind, response.id, f.id)
+	}
+
+	result := &ack{}
+	err = json.Unmarshal(response.body, result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (ci *clientIndexer) openConnection() error {
+	if ci.conn != nil {
+		return nil
+	}
+
+	conn, err := net.DialTimeout("tcp", ci.address, defaultDialTimeout)
+	if err != nil {
+		return err
+	}
+
+	ci.conn = conn
+	ci.reader = bufio.NewReader(conn)
+
+	return nil
+}
+
+func (ci *clientIndexer) closeConnection() {
+	if ci.conn == nil {
+		return
+	}
+
+	_ = ci.conn.Close()
+	ci.conn = nil
+	ci.reader = nil
+}
+
+// Close will close the connection with the indexer daemon
+func (ci *clientIndexer) Close() error {
+	ci.mutConnection.Lock()
+	defer ci.mutConnection.Unlock()
+
+	ci.closed = true
+	ci.closeConnection()
+
+	return nil
+}
+
+// IsNilIndexer returns false
+func (ci *clientIndexer) IsNilIndexer() bool {
+	return false
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (ci *clientIndexer) IsInterfaceNil() bool {
+	return ci == nil
+}
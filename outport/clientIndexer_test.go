@@ -0,0 +1,256 @@
+package outport
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	coreData "github.com/ME-MotherEarth/me-core/data"
+	dataBlock "github.com/ME-MotherEarth/me-core/data/block"
+	"github.com/ME-MotherEarth/me-core/data/indexer"
+	"github.com/ME-MotherEarth/me-core/data/transaction"
+	"github.com/ME-MotherEarth/me-elastic-indexer/data"
+	"github.com/ME-MotherEarth/me-elastic-indexer/mock"
+	"github.com/ME-MotherEarth/me-elastic-indexer/payload"
+	"github.com/stretchr/testify/require"
+)
+
+func createCodec() PayloadCodec {
+	codec, _ := payload.NewCodec(&mock.MarshalizerMock{})
+	return codec
+}
+
+func startServer(t *testing.T, indexerStub *mock.IndexerStub, accountsCache AccountsCacheHandler) *server {
+	s, err := NewServer(ArgsServer{
+		Address:       "127.0.0.1:0",
+		Codec:         createCodec(),
+		Indexer:       indexerStub,
+		AccountsCache: accountsCache,
+	})
+	require.Nil(t, err)
+	require.Nil(t, s.Start())
+	t.Cleanup(func() {
+		_ = s.Close()
+	})
+
+	return s
+}
+
+func createClient(t *testing.T, address string) *clientIndexer {
+	ci, err := NewClientIndexer(ArgsClientIndexer{
+		Address:         address,
+		Codec:           createCodec(),
+		ResponseTimeout: time.Second,
+	})
+	require.Nil(t, err)
+	ci.delayOnError = time.Millisecond
+	t.Cleanup(func() {
+		_ = ci.Close()
+	})
+
+	return ci
+}
+
+func TestNewServer(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewServer(ArgsServer{Codec: createCodec(), Indexer: &mock.IndexerStub{}})
+	require.Equal(t, ErrEmptyAddress, err)
+
+	_, err = NewServer(ArgsServer{Address: "127.0.0.1:0", Indexer: &mock.IndexerStub{}})
+	require.Equal(t, ErrNilPayloadCodec, err)
+
+	_, err = NewServer(ArgsServer{Address: "127.0.0.1:0", Codec: createCodec()})
+	require.Equal(t, ErrNilIndexer, err)
+
+	s, err := NewServer(ArgsServer{Address: "127.0.0.1:0", Codec: createCodec(), Indexer: &mock.IndexerStub{}})
+	require.Nil(t, err)
+	require.False(t, s.IsInterfaceNil())
+	require.Equal(t, "", s.Addr())
+	require.Nil(t, s.Close())
+}
+
+func TestNewClientIndexer(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewClientIndexer(ArgsClientIndexer{Codec: createCodec(), ResponseTimeout: time.Second})
+	require.Equal(t, ErrEmptyAddress, err)
+
+	_, err = NewClientIndexer(ArgsClientIndexer{Address: "127.0.0.1:1", ResponseTimeout: time.Second})
+	require.Equal(t, ErrNilPayloadCodec, err)
+
+	_, err = NewClientIndexer(ArgsClientIndexer{Address: "127.0.0.1:1", Codec: createCodec()})
+	require.True(t, errors.Is(err, ErrInvalidResponseTimeout))
+
+	ci, err := NewClientIndexer(ArgsClientIndexer{Address: "127.0.0.1:1", Codec: createCodec(), ResponseTimeout: time.Second})
+	require.Nil(t, err)
+	require.False(t, ci.IsInterfaceNil())
+	require.False(t, ci.IsNilIndexer())
+}
+
+func TestClientIndexer_AllCallsShouldReachTheRemoteIndexer(t *testing.T) {
+	t.Parallel()
+
+	mutCalls := sync.Mutex{}
+	calls := make([]string, 0)
+	addCall := func(call string) {
+		mutCalls.Lock()
+		calls = append(calls, call)
+		mutCalls.Unlock()
+	}
+
+	header := &dataBlock.Header{Nonce: 10, ShardID: 1}
+	tx := &transaction.Transaction{Nonce: 3, Value: big.NewInt(5)}
+	checkpoints := []*data.Checkpoint{{ShardID: 1, Nonce: 10, Hash: "68"}}
+	accountsCache, _ := NewAccountsCache(ArgsAccountsCache{AddressPubkeyConverter: mock.NewPubkeyConverterMock(32), MaxAccounts: 10})
+	s := startServer(t, &mock.IndexerStub{
+		SaveBlockCalled: func(args *indexer.ArgsSaveBlockData) error {
+			require.Equal(t, []byte("h"), args.HeaderHash)
+			require.Equal(t, header, args.Header)
+			require.Equal(t, tx, args.TransactionsPool.Txs["tx"])
+			addCall("saveBlock")
+			return nil
+		},
+		RevertIndexedBlockCalled: func(h coreData.HeaderHandler, body coreData.BodyHandler) error {
+			require.Equal(t, header, h)
+			addCall("revert")
+			return nil
+		},
+		SaveRoundsInfoCalled: func(roundsInfos []*indexer.RoundInfo) error {
+			require.Equal(t, []*indexer.RoundInfo{{Index: 4, ShardId: 1, Timestamp: 6}}, roundsInfos)
+			addCall("rounds")
+			return nil
+		},
+		SaveValidatorsPubKeysCalled: func(validatorsPubKeys map[uint32][][]byte, epoch uint32) error {
+			require.Equal(t, map[uint32][][]byte{0: {[]byte("pk")}}, validatorsPubKeys)
+			require.Equal(t, uint32(2), epoch)
+			addCall("validators")
+			return nil
+		},
+		SaveValidatorsRatingCalled: func(indexID string, infoRating []*indexer.ValidatorRatingInfo) error {
+			require.Equal(t, "0_2", indexID)
+			require.Equal(t, []*indexer.ValidatorRatingInfo{{PublicKey: "pk", Rating: 50}}, infoRating)
+			addCall("rating")
+			return nil
+		},
+		SaveAccountsCalled: func(blockTimestamp uint64, acc []coreData.UserAccountHandler) error {
+			require.Equal(t, uint64(100), blockTimestamp)
+			require.Equal(t, []byte("a2"), acc[0].AddressBytes())
+			addCall("accounts")
+			return nil
+		},
+		FinalizedBlockCalled: func(headerHash []byte) error {
+			require.Equal(t, []byte("h"), headerHash)
+			addCall("finalized")
+			return nil
+		},
+		GetCheckpointsCalled: func() ([]*data.Checkpoint, error) {
+			return checkpoints, nil
+		},
+	}, accountsCache)
+	ci := createClient(t, s.Addr())
+
+	require.Nil(t, ci.SaveBlock(&indexer.ArgsSaveBlockData{
+		HeaderHash:       []byte("h"),
+		Header:           header,
+		Body:             &dataBlock.Body{},
+		TransactionsPool: &indexer.Pool{Txs: map[string]coreData.TransactionHandler{"tx": tx}},
+		AlteredAccounts:  map[string]*indexer.AlteredAccount{"6131": {Address: "6131", Balance: "7"}},
+	}))
+	require.Nil(t, ci.RevertIndexedBlock(header, &dataBlock.Body{}))
+	require.Nil(t, ci.SaveRoundsInfo([]*indexer.RoundInfo{{Index: 4, ShardId: 1, Timestamp: 6}}))
+	require.Nil(t, ci.SaveValidatorsPubKeys(map[uint32][][]byte{0: {[]byte("pk")}}, 2))
+	require.Nil(t, ci.SaveValidatorsRating("0_2", []*indexer.ValidatorRatingInfo{{PublicKey: "pk", Rating: 50}}))
+	require.Nil(t, ci.SaveAccounts(100, []coreData.UserAccountHandler{&payload.Account{Address: []byte("a2"), Balance: big.NewInt(1)}}))
+	require.Nil(t, ci.FinalizedBlock([]byte("h")))
+
+	receivedCheckpoints, err := ci.GetCheckpoints()
+	require.Nil(t, err)
+	require.Equal(t, checkpoints, receivedCheckpoints)
+
+	require.Equal(t, []string{"saveBlock", "revert", "rounds", "validators", "rating", "accounts", "finalized"}, calls)
+
+	_, err = accountsCache.LoadAccount([]byte("a1"))
+	require.Nil(t, err)
+	_, err = accountsCache.LoadAccount([]byte("a2"))
+	require.Nil(t, err)
+}
+
+func TestClientIndexer_RemoteErrorShouldBeReturned(t *testing.T) {
+	t.Parallel()
+
+	numCalls := 0
+	s := startServer(t, &mock.IndexerStub{
+		FinalizedBlockCalled: func(_ []byte) error {
+			numCalls++
+			return errors.New("local error")
+		},
+	}, nil)
+	ci := createClient(t, s.Addr())
+
+	err := ci.FinalizedBlock([]byte("h"))
+	require.True(t, errors.Is(err, ErrRemoteIndexer))
+	require.Contains(t, err.Error(), "local error")
+	require.Equal(t, 1, numCalls)
+}
+
+func TestClientIndexer_ShouldReconnectAfterTheConnectionBroke(t *testing.T) {
+	t.Parallel()
+
+	s := startServer(t, &mock.IndexerStub{}, nil)
+	ci := createClient(t, s.Addr())
+	require.Nil(t, ci.FinalizedBlock([]byte("h1")))
+
+	s.mutConnections.Lock()
+	for conn := range s.connections {
+		_ = conn.Close()
+	}
+	s.mutConnections.Unlock()
+
+	require.Nil(t, ci.FinalizedBlock([]byte("h2")))
+}
+
+func TestClientIndexer_UnreachableDaemonShouldErr(t *testing.T) {
+	t.Parallel()
+
+	listener, _ := net.Listen("tcp", "127.0.0.1:0")
+	address := listener.Addr().String()
+	_ = listener.Close()
+
+	ci := createClient(t, address)
+	require.NotNil(t, ci.FinalizedBlock([]byte("h")))
+
+	require.Nil(t, ci.Close())
+	require.Equal(t, ErrClientClosed, ci.FinalizedBlock([]byte("h")))
+}
+
+func TestServer_UnsupportedProtocolVersionShouldBeAnsweredAndClosed(t *testing.T) {
+	t.Parallel()
+
+	s := startServer(t, &mock.IndexerStub{}, nil)
+	conn, err := net.Dial("tcp", s.Addr())
+	require.Nil(t, err)
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	require.Nil(t, writeFrame(conn, &frame{version: ProtocolVersion + 1, kind: kindGetCheckpoints, id: 5}))
+
+	reader := bufio.NewReader(conn)
+	response, err := readFrame(reader)
+	require.Nil(t, err)
+	require.Equal(t, kindAck, response.kind)
+	require.Equal(t, uint64(5), response.id)
+
+	result := &ack{}
+	require.Nil(t, json.Unmarshal(response.body, result))
+	require.Contains(t, result.Error, ErrUnsupportedProtocolVersion.Error())
+
+	_, err = readFrame(reader)
+	require.NotNil(t, err)
+}
@@ -0,0 +1,42 @@
+package outport
+
+import "errors"
+
+// ErrEmptyAddress signals that an empty network address has been provided
+var ErrEmptyAddress = errors.New("empty address")
+
+// ErrNilPayloadCodec signals that a nil payload codec has been provided
+var ErrNilPayloadCodec = errors.New("nil payload codec")
+
+// ErrNilIndexer signals that a nil indexer has been provided
+var ErrNilIndexer = errors.New("nil indexer")
+
+// ErrNilPubkeyConverter signals that a nil public key converter has been provided
+var ErrNilPubkeyConverter = errors.New("nil public key converter")
+
+// ErrInvalidResponseTimeout signals that an invalid response timeout has been provided
+var ErrInvalidResponseTimeout = errors.New("invalid response timeout")
+
+// ErrUnsupportedProtocolVersion signals that a frame was written with a protocol version that is not supported
+var ErrUnsupportedProtocolVersion = errors.New("unsupported protocol version")
+
+// ErrUnknownFrameKind signals that a frame of an unknown kind was received
+var ErrUnknownFrameKind = errors.New("unknown frame kind")
+
+// ErrFrameTooLarge signals that a frame is larger than the maximum allowed size
+var ErrFrameTooLarge = errors.New("frame is too large")
+
+// ErrUnexpectedAck signals that the received acknowledgement does not match the sent message
+var ErrUnexpectedAck = errors.New("unexpected acknowledgement")
+
+// ErrRemoteIndexer signals that the remote indexer could not handle a message
+var ErrRemoteIndexer = errors.New("remote indexer error")
+
+// ErrClientClosed signals that an operation was attempted on a closed client
+var ErrClientClosed = errors.New("client is closed")
+
+// ErrAccountNotFound signals that no snapshot is known for the requested account
+var ErrAccountNotFound = errors.New("account not found")
+
+// ErrInvalidMaxAccounts signals that an invalid maximum number of cached accounts has been provided
+var ErrInvalidMaxAccounts = errors.New("invalid maximum number of cached accounts")
@@ -0,0 +1,21 @@
+package outport
+
+import (
+	coreData "github.com/ME-MotherEarth/me-core/data"
+	"github.com/ME-MotherEarth/me-core/data/indexer"
+	"github.com/ME-MotherEarth/me-elastic-indexer/payload"
+)
+
+// PayloadCodec defines what a component that converts payloads to and from bytes should be able to do
+type PayloadCodec interface {
+	Encode(p *payload.Payload) ([]byte, error)
+	Decode(buff []byte) (*payload.Payload, error)
+	IsInterfaceNil() bool
+}
+
+// AccountsCacheHandler defines what a component that keeps the accounts received from the node should be able to do
+type AccountsCacheHandler interface {
+	UpdateAlteredAccounts(alteredAccounts map[string]*indexer.AlteredAccount)
+	UpdateAccounts(accounts []coreData.UserAccountHandler)
+	IsInterfaceNil() bool
+}
@@ -0,0 +1,262 @@
+package outport
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+
+	"github.com/ME-MotherEarth/me-core/core/check"
+	elasticIndexer "github.com/ME-MotherEarth/me-elastic-indexer"
+	"github.com/ME-MotherEarth/me-elastic-indexer/payload"
+	logger "github.com/ME-MotherEarth/me-logger"
+)
+
+var log = logger.GetOrCreate("indexer/outport")
+
+// ArgsServer holds all dependencies required by the server in order to create new instances. The AccountsCache is
+// optional: if provided, it is updated with the accounts received from the node before they are indexed
+type ArgsServer struct {
+	Address       string
+	Codec         PayloadCodec
+	Indexer       elasticIndexer.Indexer
+	AccountsCache AccountsCacheHandler
+}
+
+type server struct {
+	address       string
+	codec         PayloadCodec
+	indexer       elasticIndexer.Indexer
+	accountsCache AccountsCacheHandler
+
+	mutHandle sync.Mutex
+
+	mutConnections sync.Mutex
+	listener       net.Listener
+	connections    map[net.Conn]struct{}
+	closed         bool
+	wg             sync.WaitGroup
+}
+
+// NewServer will create the component that receives the indexer calls of the node and hands them to the provided
+// indexer. The frames of all the connections are handled one at a time, in the order they are received
+func NewServer(args ArgsServer) (*server, error) {
+	if args.Address == "" {
+		return nil, ErrEmptyAddress
+	}
+	if check.IfNil(args.Codec) {
+		return nil, ErrNilPayloadCodec
+	}
+	if check.IfNil(args.Indexer) {
+		return nil, ErrNilIndexer
+	}
+
+	return &server{
+		address:       args.Address,
+		codec:         args.Codec,
+		indexer:       args.Indexer,
+		accountsCache: args.AccountsCache,
+		connections:   make(map[net.Conn]struct{}),
+	}, nil
+}
+
+// Start will listen on the configured address and will accept connections in background
+func (s *server) Start() error {
+	listener, err := net.Listen("tcp", s.address)
+	if err != nil {
+		return err
+	}
+
+	s.mutConnections.Lock()
+	s.listener = listener
+	s.mutConnections.Unlock()
+
+	log.Info("outport server is listening", "address", listener.Addr().String(), "protocol version", ProtocolVersion)
+
+	s.wg.Add(1)
+	go s.acceptConnections(listener)
+
+	return nil
+}
+
+// Addr returns the address the server listens on, or an empty string if it was not started
+func (s *server) Addr() string {
+	s.mutConnections.Lock()
+	defer s.mutConnections.Unlock()
+
+	if s.listener == nil {
+		return ""
+	}
+
+	return s.listener.Addr().String()
+}
+
+func (s *server) acceptConnections(listener net.Listener) {
+	defer s.wg.Done()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if !s.isClosed() {
+				log.Error("outport server cannot accept connection", "error", err)
+			}
+			return
+		}
+
+		if !s.trackConnection(conn) {
+			_ = conn.Close()
+			return
+		}
+
+		s.wg.Add(1)
+		go s.serveConnection(conn)
+	}
+}
+
+func (s *server) trackConnection(conn net.Conn) bool {
+	s.mutConnections.Lock()
+	defer s.mutConnections.Unlock()
+
+	if s.closed {
+		return false
+	}
+	s.connections[conn] = struct{}{}
+
+	return true
+}
+
+func (s *server) serveConnection(conn net.Conn) {
+	defer func() {
+		s.mutConnections.Lock()
+		delete(s.connections, conn)
+		s.mutConnections.Unlock()
+
+		_ = conn.Close()
+		s.wg.Done()
+	}()
+
+	log.Debug("outport server: node connected", "remote address", conn.RemoteAddr().String())
+
+	reader := bufio.NewReader(conn)
+	for {
+		f, err := readFrame(reader)
+		if err != nil {
+			if !errors.Is(err, io.EOF) && !s.isClosed() {
+				log.Warn("outport server cannot read frame, closing connection",
+					"remote address", conn.RemoteAddr().String(), "error", err)
+			}
+			return
+		}
+
+		response := s.handleFrame(f)
+		err = writeFrame(conn, response)
+		if err != nil {
+			log.Warn("outport server cannot write acknowledgement, closing connection",
+				"remote address", conn.RemoteAddr().String(), "error", err)
+			return
+		}
+
+		if f.version != ProtocolVersion {
+			return
+		}
+	}
+}
+
+func (s *server) handleFrame(f *frame) *frame {
+	response := &ack{}
+	err := s.processFrame(f, response)
+	if err != nil {
+		log.Warn("outport server cannot handle frame", "id", f.id, "kind", f.kind, "error", err)
+		response.Error = err.Error()
+	}
+
+	body, err := json.Marshal(response)
+	if err != nil {
+		body = []byte(fmt.Sprintf(`{"error":%q}`, err.Error()))
+	}
+
+	return &frame{
+		version: ProtocolVersion,
+		kind:    kindAck,
+		id:      f.id,
+		body:    body,
+	}
+}
+
+func (s *server) processFrame(f *frame, response *ack) error {
+	if f.version != ProtocolVersion {
+		return fmt.Errorf("%w: received %d, supported %d", ErrUnsupportedProtocolVersion, f.version, ProtocolVersion)
+	}
+
+	s.mutHandle.Lock()
+	defer s.mutHandle.Unlock()
+
+	switch f.kind {
+	case kindPayload:
+		p, err := s.codec.Decode(f.body)
+		if err != nil {
+			return err
+		}
+
+		return s.applyPayload(p)
+	case kindGetCheckpoints:
+		checkpoints, err := s.indexer.GetCheckpoints()
+		response.Checkpoints = checkpoints
+
+		return err
+	default:
+		return fmt.Errorf("%w: %d", ErrUnknownFrameKind, f.kind)
+	}
+}
+
+// applyPayload will keep the received accounts before making the call on the indexer
+func (s *server) applyPayload(p *payload.Payload) error {
+	if !check.IfNil(s.accountsCache) {
+		switch p.Type {
+		case payload.SaveBlock:
+			s.accountsCache.UpdateAlteredAccounts(p.ArgsSaveBlock.AlteredAccounts)
+		case payload.SaveAccounts:
+			s.accountsCache.UpdateAccounts(p.Accounts)
+		}
+	}
+
+	return payload.Apply(p, s.indexer)
+}
+
+func (s *server) isClosed() bool {
+	s.mutConnections.Lock()
+	defer s.mutConnections.Unlock()
+
+	return s.closed
+}
+
+// Close will stop accepting connections and will close the open ones. The indexer is not closed
+func (s *server) Close() error {
+	s.mutConnections.Lock()
+	if s.closed {
+		s.mutConnections.Unlock()
+		return nil
+	}
+	s.closed = true
+
+	var err error
+	if s.listener != nil {
+		err = s.listener.Close()
+	}
+	for conn := range s.connections {
+		_ = conn.Close()
+	}
+	s.mutConnections.Unlock()
+
+	s.wg.Wait()
+
+	return err
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (s *server) IsInterfaceNil() bool {
+	return s == nil
+}
@@ -0,0 +1,89 @@
+package outport
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/ME-MotherEarth/me-elastic-indexer/data"
+)
+
+// ProtocolVersion is the version of the wire format written in every frame. A peer drops the connection on frames
+// written with another version, so the node and the indexer daemon have to be upgraded together when it changes
+const ProtocolVersion uint8 = 1
+
+// frameKind identifies the content of a frame
+type frameKind uint8
+
+const (
+	// kindPayload is sent by the node, the body holds an encoded payload
+	kindPayload frameKind = iota + 1
+	// kindGetCheckpoints is sent by the node, the body is empty
+	kindGetCheckpoints
+	// kindAck is sent by the indexer for every received frame, the body holds a JSON encoded ack
+	kindAck
+)
+
+const (
+	// frameHeaderSize holds the version, the kind, the id and the length of the body
+	frameHeaderSize = 1 + 1 + 8 + 4
+	maxFrameSize    = 256 * 1024 * 1024
+)
+
+// frame is the unit sent over the wire. Every frame written by the node is answered by an ack frame with the same id
+type frame struct {
+	version uint8
+	kind    frameKind
+	id      uint64
+	body    []byte
+}
+
+// ack holds the outcome of handling a frame. An empty error means the frame was accepted
+type ack struct {
+	Error       string             `json:"error,omitempty"`
+	Checkpoints []*data.Checkpoint `json:"checkpoints,omitempty"`
+}
+
+func writeFrame(writer io.Writer, f *frame) error {
+	if len(f.body) > maxFrameSize {
+		return fmt.Errorf("%w: %d bytes", ErrFrameTooLarge, len(f.body))
+	}
+
+	buff := make([]byte, frameHeaderSize+len(f.body))
+	buff[0] = f.version
+	buff[1] = byte(f.kind)
+	binary.BigEndian.PutUint64(buff[2:10], f.id)
+	binary.BigEndian.PutUint32(buff[10:14], uint32(len(f.body)))
+	copy(buff[frameHeaderSize:], f.body)
+
+	_, err := writer.Write(buff)
+	return err
+}
+
+// readFrame will read the next frame. The version is returned as it was written, so the caller can answer with an
+// error before closing the connection
+func readFrame(reader io.Reader) (*frame, error) {
+	header := make([]byte, frameHeaderSize)
+	_, err := io.ReadFull(reader, header)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &frame{
+		version: header[0],
+		kind:    frameKind(header[1]),
+		id:      binary.BigEndian.Uint64(header[2:10]),
+	}
+	bodySize := binary.BigEndian.Uint32(header[10:14])
+	if bodySize > maxFrameSize {
+		return nil, fmt.Errorf("%w: %d bytes", ErrFrameTooLarge, bodySize)
+	}
+
+	f.body = make([]byte, bodySize)
+	_, err = io.ReadFull(reader, f.body)
+	if err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
@@ -0,0 +1,45 @@
+package outport
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteAndReadFrame(t *testing.T) {
+	t.Parallel()
+
+	buff := &bytes.Buffer{}
+	require.Nil(t, writeFrame(buff, &frame{version: ProtocolVersion, kind: kindPayload, id: 7, body: []byte("body")}))
+	require.Nil(t, writeFrame(buff, &frame{version: ProtocolVersion, kind: kindGetCheckpoints, id: 8}))
+
+	f, err := readFrame(buff)
+	require.Nil(t, err)
+	require.Equal(t, &frame{version: ProtocolVersion, kind: kindPayload, id: 7, body: []byte("body")}, f)
+
+	f, err = readFrame(buff)
+	require.Nil(t, err)
+	require.Equal(t, &frame{version: ProtocolVersion, kind: kindGetCheckpoints, id: 8, body: []byte{}}, f)
+
+	_, err = readFrame(buff)
+	require.Equal(t, io.EOF, err)
+}
+
+func TestReadFrame_TruncatedOrTooLargeShouldErr(t *testing.T) {
+	t.Parallel()
+
+	buff := &bytes.Buffer{}
+	_ = writeFrame(buff, &frame{version: ProtocolVersion, kind: kindPayload, id: 1, body: []byte("body")})
+	_, err := readFrame(bytes.NewReader(buff.Bytes()[:buff.Len()-1]))
+	require.Equal(t, io.ErrUnexpectedEOF, err)
+
+	header := make([]byte, frameHeaderSize)
+	header[0] = ProtocolVersion
+	binary.BigEndian.PutUint32(header[10:14], maxFrameSize+1)
+	_, err = readFrame(bytes.NewReader(header))
+	require.True(t, errors.Is(err, ErrFrameTooLarge))
+}
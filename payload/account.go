@@ -0,0 +1,52 @@
+package payload
+
+import (
+	"math/big"
+
+	coreData "github.com/ME-MotherEarth/me-core/data"
+)
+
+// Account is a serializable snapshot of a user account, holding only the fields used by the indexer
+type Account struct {
+	Address []byte   `json:"address"`
+	Balance *big.Int `json:"balance"`
+	Nonce   uint64   `json:"nonce"`
+}
+
+// NewAccountSnapshot will create a snapshot of the provided user account
+func NewAccountSnapshot(account coreData.UserAccountHandler) *Account {
+	return &Account{
+		Address: account.AddressBytes(),
+		Balance: account.GetBalance(),
+		Nonce:   account.GetNonce(),
+	}
+}
+
+// RetrieveValueFromDataTrieTracker returns an error because a snapshot does not hold the account data trie
+func (a *Account) RetrieveValueFromDataTrieTracker(_ []byte) ([]byte, error) {
+	return nil, ErrDataTrieNotAvailable
+}
+
+// GetBalance returns the balance of the account
+func (a *Account) GetBalance() *big.Int {
+	if a.Balance == nil {
+		return big.NewInt(0)
+	}
+
+	return a.Balance
+}
+
+// GetNonce returns the nonce of the account
+func (a *Account) GetNonce() uint64 {
+	return a.Nonce
+}
+
+// AddressBytes returns the address of the account
+func (a *Account) AddressBytes() []byte {
+	return a.Address
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (a *Account) IsInterfaceNil() bool {
+	return a == nil
+}
@@ -0,0 +1,130 @@
+package payload
+
+import (
+	"fmt"
+
+	coreData "github.com/ME-MotherEarth/me-core/data"
+	"github.com/ME-MotherEarth/me-core/data/indexer"
+	"github.com/ME-MotherEarth/me-elastic-indexer/data"
+)
+
+// NewSaveBlock will create the payload of a save block call
+func NewSaveBlock(args *indexer.ArgsSaveBlockData) *Payload {
+	return &Payload{
+		Type:          SaveBlock,
+		ArgsSaveBlock: args,
+	}
+}
+
+// NewRevertIndexedBlock will create the payload of a revert block call
+func NewRevertIndexedBlock(header coreData.HeaderHandler, body coreData.BodyHandler) *Payload {
+	return &Payload{
+		Type:   RevertIndexedBlock,
+		Header: header,
+		Body:   body,
+	}
+}
+
+// NewSaveRoundsInfo will create the payload of a save rounds info call
+func NewSaveRoundsInfo(roundsInfos []*indexer.RoundInfo) *Payload {
+	rounds := make([]*data.RoundInfo, 0, len(roundsInfos))
+	for _, info := range roundsInfos {
+		rounds = append(rounds, &data.RoundInfo{
+			Index:            info.Index,
+			SignersIndexes:   info.SignersIndexes,
+			BlockWasProposed: info.BlockWasProposed,
+			ShardId:          info.ShardId,
+			Epoch:            info.Epoch,
+			Timestamp:        info.Timestamp,
+		})
+	}
+
+	return &Payload{
+		Type:       SaveRoundsInfo,
+		RoundsInfo: rounds,
+	}
+}
+
+// NewSaveValidatorsPubKeys will create the payload of a save validators public keys call
+func NewSaveValidatorsPubKeys(validatorsPubKeys map[uint32][][]byte, epoch uint32) *Payload {
+	return &Payload{
+		Type:              SaveValidatorsPubKeys,
+		ValidatorsPubKeys: validatorsPubKeys,
+		Epoch:             epoch,
+	}
+}
+
+// NewSaveValidatorsRating will create the payload of a save validators rating call
+func NewSaveValidatorsRating(indexID string, infoRating []*indexer.ValidatorRatingInfo) *Payload {
+	ratingInfo := make([]*data.ValidatorRatingInfo, 0, len(infoRating))
+	for _, info := range infoRating {
+		ratingInfo = append(ratingInfo, &data.ValidatorRatingInfo{
+			PublicKey: info.PublicKey,
+			Rating:    info.Rating,
+		})
+	}
+
+	return &Payload{
+		Type:          SaveValidatorsRating,
+		RatingIndexID: indexID,
+		RatingInfo:    ratingInfo,
+	}
+}
+
+// NewSaveAccounts will create the payload of a save accounts call
+func NewSaveAccounts(blockTimestamp uint64, accounts []coreData.UserAccountHandler) *Payload {
+	return &Payload{
+		Type:      SaveAccounts,
+		Timestamp: blockTimestamp,
+		Accounts:  accounts,
+	}
+}
+
+// NewFinalizedBlock will create the payload of a finalized block call
+func NewFinalizedBlock(headerHash []byte) *Payload {
+	return &Payload{
+		Type:       FinalizedBlock,
+		HeaderHash: headerHash,
+	}
+}
+
+// Apply will make on the provided handler the call the payload was created for. The payloads created by the
+// dispatcher for the blocks saved after finalization are not indexer calls, so they cannot be applied
+func Apply(p *Payload, handler CallsHandler) error {
+	switch p.Type {
+	case SaveBlock:
+		return handler.SaveBlock(p.ArgsSaveBlock)
+	case RevertIndexedBlock:
+		return handler.RevertIndexedBlock(p.Header, p.Body)
+	case SaveRoundsInfo:
+		roundsInfo := make([]*indexer.RoundInfo, 0, len(p.RoundsInfo))
+		for _, info := range p.RoundsInfo {
+			roundsInfo = append(roundsInfo, &indexer.RoundInfo{
+				Index:            info.Index,
+				SignersIndexes:   info.SignersIndexes,
+				BlockWasProposed: info.BlockWasProposed,
+				ShardId:          info.ShardId,
+				Epoch:            info.Epoch,
+				Timestamp:        info.Timestamp,
+			})
+		}
+		return handler.SaveRoundsInfo(roundsInfo)
+	case SaveValidatorsRating:
+		ratingInfo := make([]*indexer.ValidatorRatingInfo, 0, len(p.RatingInfo))
+		for _, info := range p.RatingInfo {
+			ratingInfo = append(ratingInfo, &indexer.ValidatorRatingInfo{
+				PublicKey: info.PublicKey,
+				Rating:    info.Rating,
+			})
+		}
+		return handler.SaveValidatorsRating(p.RatingIndexID, ratingInfo)
+	case SaveValidatorsPubKeys:
+		return handler.SaveValidatorsPubKeys(p.ValidatorsPubKeys, p.Epoch)
+	case SaveAccounts:
+		return handler.SaveAccounts(p.Timestamp, p.Accounts)
+	case FinalizedBlock:
+		return handler.FinalizedBlock(p.HeaderHash)
+	default:
+		return fmt.Errorf("%w: %d", ErrNotAnIndexerCall, p.Type)
+	}
+}
@@ -0,0 +1,40 @@
+package payload_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ME-MotherEarth/me-core/data/indexer"
+	"github.com/ME-MotherEarth/me-elastic-indexer/mock"
+	"github.com/ME-MotherEarth/me-elastic-indexer/payload"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApply(t *testing.T) {
+	t.Parallel()
+
+	var receivedRounds []*indexer.RoundInfo
+	var receivedRating []*indexer.ValidatorRatingInfo
+	handler := &mock.IndexerStub{
+		SaveRoundsInfoCalled: func(roundsInfos []*indexer.RoundInfo) error {
+			receivedRounds = roundsInfos
+			return nil
+		},
+		SaveValidatorsRatingCalled: func(indexID string, infoRating []*indexer.ValidatorRatingInfo) error {
+			require.Equal(t, "0_1", indexID)
+			receivedRating = infoRating
+			return nil
+		},
+	}
+
+	rounds := []*indexer.RoundInfo{{Index: 1, SignersIndexes: []uint64{2}, BlockWasProposed: true, ShardId: 3, Epoch: 4}}
+	require.Nil(t, payload.Apply(payload.NewSaveRoundsInfo(rounds), handler))
+	require.Equal(t, rounds, receivedRounds)
+
+	rating := []*indexer.ValidatorRatingInfo{{PublicKey: "pk", Rating: 50}}
+	require.Nil(t, payload.Apply(payload.NewSaveValidatorsRating("0_1", rating), handler))
+	require.Equal(t, rating, receivedRating)
+
+	err := payload.Apply(&payload.Payload{Type: payload.SaveFinalizedBlock}, handler)
+	require.True(t, errors.Is(err, payload.ErrNotAnIndexerCall))
+}
@@ -0,0 +1,445 @@
+package payload
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/ME-MotherEarth/me-core/core/check"
+	coreData "github.com/ME-MotherEarth/me-core/data"
+	"github.com/ME-MotherEarth/me-core/data/block"
+	"github.com/ME-MotherEarth/me-core/data/indexer"
+	"github.com/ME-MotherEarth/me-core/data/receipt"
+	"github.com/ME-MotherEarth/me-core/data/rewardTx"
+	"github.com/ME-MotherEarth/me-core/data/smartContractResult"
+	"github.com/ME-MotherEarth/me-core/data/transaction"
+	"github.com/ME-MotherEarth/me-core/marshal"
+	"github.com/ME-MotherEarth/me-elastic-indexer/data"
+)
+
+const (
+	headerType      = "header"
+	headerV2Type    = "headerV2"
+	metaBlockType   = "metaBlock"
+	transactionType = "transaction"
+	scrType         = "scr"
+	rewardTxType    = "reward"
+	receiptType     = "receipt"
+)
+
+type typedObject struct {
+	Type string `json:"type"`
+	Data []byte `json:"data"`
+}
+
+type serializedLog struct {
+	TxHash string `json:"txHash"`
+	Log    []byte `json:"log"`
+}
+
+type serializedPool struct {
+	Txs      map[string]*typedObject `json:"txs,omitempty"`
+	Scrs     map[string]*typedObject `json:"scrs,omitempty"`
+	Rewards  map[string]*typedObject `json:"rewards,omitempty"`
+	Invalid  map[string]*typedObject `json:"invalid,omitempty"`
+	Receipts map[string]*typedObject `json:"receipts,omitempty"`
+	Logs     []*serializedLog        `json:"logs,omitempty"`
+}
+
+type serializedRating struct {
+	PublicKey string  `json:"publicKey"`
+	Rating    float32 `json:"rating"`
+}
+
+type serializedPayload struct {
+	Type                   Type                               `json:"type"`
+	HeaderHash             []byte                             `json:"headerHash,omitempty"`
+	Header                 *typedObject                       `json:"header,omitempty"`
+	Body                   []byte                             `json:"body,omitempty"`
+	SignersIndexes         []uint64                           `json:"signersIndexes,omitempty"`
+	NotarizedHeadersHashes []string                           `json:"notarizedHeadersHashes,omitempty"`
+	HeaderGasConsumption   *indexer.HeaderGasConsumption      `json:"headerGasConsumption,omitempty"`
+	Pool                   *serializedPool                    `json:"pool,omitempty"`
+	AlteredAccounts        map[string]*indexer.AlteredAccount `json:"alteredAccounts,omitempty"`
+	RoundsInfo             []*data.RoundInfo                  `json:"roundsInfo,omitempty"`
+	RatingIndexID          string                             `json:"ratingIndexID,omitempty"`
+	RatingInfo             []*serializedRating                `json:"ratingInfo,omitempty"`
+	Epoch                  uint32                             `json:"epoch,omitempty"`
+	ValidatorsPubKeys      map[uint32][][]byte                `json:"validatorsPubKeys,omitempty"`
+	Timestamp              uint64                             `json:"timestamp,omitempty"`
+	Accounts               []*Account                         `json:"accounts,omitempty"`
+}
+
+type codec struct {
+	marshalizer marshal.Marshalizer
+}
+
+// NewCodec will create a new instance of codec, capable of converting a Payload to bytes and back. The provided
+// marshalizer is used for the node's data structures (headers, bodies, transactions and logs)
+func NewCodec(marshalizer marshal.Marshalizer) (*codec, error) {
+	if check.IfNil(marshalizer) {
+		return nil, ErrNilMarshalizer
+	}
+
+	return &codec{
+		marshalizer: marshalizer,
+	}, nil
+}
+
+// Encode will convert the provided payload in a slice of bytes
+func (c *codec) Encode(p *Payload) ([]byte, error) {
+	if p == nil {
+		return nil, ErrNilPayload
+	}
+
+	sp := &serializedPayload{
+		Type:              p.Type,
+		RoundsInfo:        p.RoundsInfo,
+		RatingIndexID:     p.RatingIndexID,
+		Epoch:             p.Epoch,
+		ValidatorsPubKeys: p.ValidatorsPubKeys,
+		Timestamp:         p.Timestamp,
+	}
+
+	var err error
+	switch p.Type {
+	case SaveBlock, SaveFinalizedBlock:
+		err = c.encodeArgsSaveBlock(p.ArgsSaveBlock, sp)
+	case FinalizedBlock:
+		sp.HeaderHash = p.HeaderHash
+	case RevertIndexedBlock:
+		err = c.encodeHeaderAndBody(p.Header, p.Body, sp)
+	case SaveRoundsInfo, SaveValidatorsPubKeys:
+	case SaveValidatorsRating:
+		sp.RatingInfo = make([]*serializedRating, 0, len(p.RatingInfo))
+		for _, info := range p.RatingInfo {
+			sp.RatingInfo = append(sp.RatingInfo, &serializedRating{PublicKey: info.PublicKey, Rating: info.Rating})
+		}
+	case SaveAccounts:
+		sp.Accounts = make([]*Account, 0, len(p.Accounts))
+		for _, account := range p.Accounts {
+			sp.Accounts = append(sp.Accounts, NewAccountSnapshot(account))
+		}
+	default:
+		return nil, fmt.Errorf("%w: %d", ErrUnknownPayloadType, p.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(sp)
+}
+
+func (c *codec) encodeArgsSaveBlock(args *indexer.ArgsSaveBlockData, sp *serializedPayload) error {
+	if args == nil {
+		return ErrNilPayload
+	}
+
+	err := c.encodeHeaderAndBody(args.Header, args.Body, sp)
+	if err != nil {
+		return err
+	}
+
+	sp.HeaderHash = args.HeaderHash
+	sp.SignersIndexes = args.SignersIndexes
+	sp.NotarizedHeadersHashes = args.NotarizedHeadersHashes
+	sp.HeaderGasConsumption = &args.HeaderGasConsumption
+	sp.AlteredAccounts = args.AlteredAccounts
+
+	if args.TransactionsPool == nil {
+		return nil
+	}
+
+	sp.Pool, err = c.encodePool(args.TransactionsPool)
+	return err
+}
+
+func (c *codec) encodeHeaderAndBody(header coreData.HeaderHandler, body coreData.BodyHandler, sp *serializedPayload) error {
+	var err error
+	if !check.IfNil(header) {
+		sp.Header, err = c.encodeTypedObject(header)
+		if err != nil {
+			return err
+		}
+	}
+
+	if check.IfNil(body) {
+		return nil
+	}
+
+	sp.Body, err = c.marshalizer.Marshal(body)
+	return err
+}
+
+func (c *codec) encodePool(pool *indexer.Pool) (*serializedPool, error) {
+	var err error
+	sp := &serializedPool{}
+	if sp.Txs, err = c.encodeTxsMap(pool.Txs); err != nil {
+		return nil, err
+	}
+	if sp.Scrs, err = c.encodeTxsMap(pool.Scrs); err != nil {
+		return nil, err
+	}
+	if sp.Rewards, err = c.encodeTxsMap(pool.Rewards); err != nil {
+		return nil, err
+	}
+	if sp.Invalid, err = c.encodeTxsMap(pool.Invalid); err != nil {
+		return nil, err
+	}
+	if sp.Receipts, err = c.encodeTxsMap(pool.Receipts); err != nil {
+		return nil, err
+	}
+
+	sp.Logs = make([]*serializedLog, 0, len(pool.Logs))
+	for _, logData := range pool.Logs {
+		if logData == nil || check.IfNil(logData.LogHandler) {
+			continue
+		}
+
+		logBytes, errMarshal := c.marshalizer.Marshal(logData.LogHandler)
+		if errMarshal != nil {
+			return nil, errMarshal
+		}
+
+		sp.Logs = append(sp.Logs, &serializedLog{TxHash: logData.TxHash, Log: logBytes})
+	}
+
+	return sp, nil
+}
+
+func (c *codec) encodeTxsMap(txs map[string]coreData.TransactionHandler) (map[string]*typedObject, error) {
+	if len(txs) == 0 {
+		return nil, nil
+	}
+
+	encoded := make(map[string]*typedObject, len(txs))
+	for hash, tx := range txs {
+		obj, err := c.encodeTypedObject(tx)
+		if err != nil {
+			return nil, err
+		}
+
+		encoded[hash] = obj
+	}
+
+	return encoded, nil
+}
+
+func (c *codec) encodeTypedObject(obj interface{}) (*typedObject, error) {
+	var objType string
+	switch obj.(type) {
+	case *block.Header:
+		objType = headerType
+	case *block.HeaderV2:
+		objType = headerV2Type
+	case *block.MetaBlock:
+		objType = metaBlockType
+	case *transaction.Transaction:
+		objType = transactionType
+	case *smartContractResult.SmartContractResult:
+		objType = scrType
+	case *rewardTx.RewardTx:
+		objType = rewardTxType
+	case *receipt.Receipt:
+		objType = receiptType
+	default:
+		return nil, fmt.Errorf("%w: %T", ErrUnknownObjectType, obj)
+	}
+
+	objBytes, err := c.marshalizer.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	return &typedObject{Type: objType, Data: objBytes}, nil
+}
+
+// Decode will create a payload from the provided slice of bytes
+func (c *codec) Decode(buff []byte) (*Payload, error) {
+	sp := &serializedPayload{}
+	err := json.Unmarshal(buff, sp)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Payload{
+		Type:              sp.Type,
+		RoundsInfo:        sp.RoundsInfo,
+		RatingIndexID:     sp.RatingIndexID,
+		Epoch:             sp.Epoch,
+		ValidatorsPubKeys: sp.ValidatorsPubKeys,
+		Timestamp:         sp.Timestamp,
+	}
+
+	switch sp.Type {
+	case SaveBlock, SaveFinalizedBlock:
+		p.ArgsSaveBlock, err = c.decodeArgsSaveBlock(sp)
+	case FinalizedBlock:
+		p.HeaderHash = sp.HeaderHash
+	case RevertIndexedBlock:
+		p.Header, p.Body, err = c.decodeHeaderAndBody(sp)
+	case SaveRoundsInfo, SaveValidatorsPubKeys:
+	case SaveValidatorsRating:
+		p.RatingInfo = make([]*data.ValidatorRatingInfo, 0, len(sp.RatingInfo))
+		for _, info := range sp.RatingInfo {
+			p.RatingInfo = append(p.RatingInfo, &data.ValidatorRatingInfo{PublicKey: info.PublicKey, Rating: info.Rating})
+		}
+	case SaveAccounts:
+		p.Accounts = make([]coreData.UserAccountHandler, 0, len(sp.Accounts))
+		for _, account := range sp.Accounts {
+			p.Accounts = append(p.Accounts, account)
+		}
+	default:
+		return nil, fmt.Errorf("%w: %d", ErrUnknownPayloadType, sp.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+func (c *codec) decodeArgsSaveBlock(sp *serializedPayload) (*indexer.ArgsSaveBlockData, error) {
+	header, body, err := c.decodeHeaderAndBody(sp)
+	if err != nil {
+		return nil, err
+	}
+
+	args := &indexer.ArgsSaveBlockData{
+		HeaderHash:             sp.HeaderHash,
+		Body:                   body,
+		Header:                 header,
+		SignersIndexes:         sp.SignersIndexes,
+		NotarizedHeadersHashes: sp.NotarizedHeadersHashes,
+		AlteredAccounts:        sp.AlteredAccounts,
+	}
+	if sp.HeaderGasConsumption != nil {
+		args.HeaderGasConsumption = *sp.HeaderGasConsumption
+	}
+	if sp.Pool == nil {
+		return args, nil
+	}
+
+	args.TransactionsPool, err = c.decodePool(sp.Pool)
+	if err != nil {
+		return nil, err
+	}
+
+	return args, nil
+}
+
+func (c *codec) decodeHeaderAndBody(sp *serializedPayload) (coreData.HeaderHandler, coreData.BodyHandler, error) {
+	var header coreData.HeaderHandler
+	if sp.Header != nil {
+		obj, err := c.decodeTypedObject(sp.Header)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		var ok bool
+		header, ok = obj.(coreData.HeaderHandler)
+		if !ok {
+			return nil, nil, fmt.Errorf("%w: %s is not a header", ErrUnknownObjectType, sp.Header.Type)
+		}
+	}
+
+	if len(sp.Body) == 0 {
+		return header, nil, nil
+	}
+
+	body := &block.Body{}
+	err := c.marshalizer.Unmarshal(body, sp.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return header, body, nil
+}
+
+func (c *codec) decodePool(sp *serializedPool) (*indexer.Pool, error) {
+	var err error
+	pool := &indexer.Pool{}
+	if pool.Txs, err = c.decodeTxsMap(sp.Txs); err != nil {
+		return nil, err
+	}
+	if pool.Scrs, err = c.decodeTxsMap(sp.Scrs); err != nil {
+		return nil, err
+	}
+	if pool.Rewards, err = c.decodeTxsMap(sp.Rewards); err != nil {
+		return nil, err
+	}
+	if pool.Invalid, err = c.decodeTxsMap(sp.Invalid); err != nil {
+		return nil, err
+	}
+	if pool.Receipts, err = c.decodeTxsMap(sp.Receipts); err != nil {
+		return nil, err
+	}
+
+	pool.Logs = make([]*coreData.LogData, 0, len(sp.Logs))
+	for _, serializedLogData := range sp.Logs {
+		txLog := &transaction.Log{}
+		err = c.marshalizer.Unmarshal(txLog, serializedLogData.Log)
+		if err != nil {
+			return nil, err
+		}
+
+		pool.Logs = append(pool.Logs, &coreData.LogData{
+			LogHandler: txLog,
+			TxHash:     serializedLogData.TxHash,
+		})
+	}
+
+	return pool, nil
+}
+
+func (c *codec) decodeTxsMap(encoded map[string]*typedObject) (map[string]coreData.TransactionHandler, error) {
+	txs := make(map[string]coreData.TransactionHandler, len(encoded))
+	for hash, obj := range encoded {
+		decoded, err := c.decodeTypedObject(obj)
+		if err != nil {
+			return nil, err
+		}
+
+		tx, ok := decoded.(coreData.TransactionHandler)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s is not a transaction", ErrUnknownObjectType, obj.Type)
+		}
+
+		txs[hash] = tx
+	}
+
+	return txs, nil
+}
+
+func (c *codec) decodeTypedObject(obj *typedObject) (interface{}, error) {
+	var decoded interface{}
+	switch obj.Type {
+	case headerType:
+		decoded = &block.Header{}
+	case headerV2Type:
+		decoded = &block.HeaderV2{}
+	case metaBlockType:
+		decoded = &block.MetaBlock{}
+	case transactionType:
+		decoded = &transaction.Transaction{}
+	case scrType:
+		decoded = &smartContractResult.SmartContractResult{}
+	case rewardTxType:
+		decoded = &rewardTx.RewardTx{}
+	case receiptType:
+		decoded = &receipt.Receipt{}
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownObjectType, obj.Type)
+	}
+
+	err := c.marshalizer.Unmarshal(decoded, obj.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	return decoded, nil
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (c *codec) IsInterfaceNil() bool {
+	return c == nil
+}
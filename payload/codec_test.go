@@ -0,0 +1,174 @@
+package payload_test
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	coreData "github.com/ME-MotherEarth/me-core/data"
+	"github.com/ME-MotherEarth/me-core/data/block"
+	"github.com/ME-MotherEarth/me-core/data/indexer"
+	"github.com/ME-MotherEarth/me-core/data/receipt"
+	"github.com/ME-MotherEarth/me-core/data/rewardTx"
+	"github.com/ME-MotherEarth/me-core/data/smartContractResult"
+	"github.com/ME-MotherEarth/me-core/data/transaction"
+	"github.com/ME-MotherEarth/me-elastic-indexer/data"
+	"github.com/ME-MotherEarth/me-elastic-indexer/mock"
+	"github.com/ME-MotherEarth/me-elastic-indexer/payload"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCodec_NilMarshalizerShouldErr(t *testing.T) {
+	t.Parallel()
+
+	c, err := payload.NewCodec(nil)
+	require.Nil(t, c)
+	require.Equal(t, payload.ErrNilMarshalizer, err)
+}
+
+func TestCodec_EncodeDecodeSaveBlock(t *testing.T) {
+	t.Parallel()
+
+	c, _ := payload.NewCodec(&mock.MarshalizerMock{})
+	require.False(t, c.IsInterfaceNil())
+
+	p := &payload.Payload{
+		Type: payload.SaveBlock,
+		ArgsSaveBlock: &indexer.ArgsSaveBlockData{
+			HeaderHash: []byte("hash"),
+			Body: &block.Body{MiniBlocks: []*block.MiniBlock{{
+				TxHashes: [][]byte{[]byte("tx")},
+			}}},
+			Header:                 &block.Header{Nonce: 10, ShardID: 1, AccumulatedFees: big.NewInt(5), DeveloperFees: big.NewInt(1)},
+			SignersIndexes:         []uint64{1, 2},
+			NotarizedHeadersHashes: []string{"h1"},
+			HeaderGasConsumption:   indexer.HeaderGasConsumption{GasProvided: 100},
+			TransactionsPool: &indexer.Pool{
+				Txs:      map[string]coreData.TransactionHandler{"tx": &transaction.Transaction{Nonce: 1, Value: big.NewInt(10)}},
+				Scrs:     map[string]coreData.TransactionHandler{"scr": &smartContractResult.SmartContractResult{Nonce: 2, Value: big.NewInt(1)}},
+				Rewards:  map[string]coreData.TransactionHandler{"reward": &rewardTx.RewardTx{Round: 3, Value: big.NewInt(2)}},
+				Receipts: map[string]coreData.TransactionHandler{"receipt": &receipt.Receipt{TxHash: []byte("tx"), Value: big.NewInt(3)}},
+				Logs: []*coreData.LogData{{
+					TxHash: "tx",
+					LogHandler: &transaction.Log{
+						Address: []byte("addr"),
+						Events:  []*transaction.Event{{Identifier: []byte("id"), Topics: [][]byte{[]byte("t")}}},
+					},
+				}},
+			},
+		},
+	}
+
+	buff, err := c.Encode(p)
+	require.NoError(t, err)
+
+	decoded, err := c.Decode(buff)
+	require.NoError(t, err)
+	require.Equal(t, payload.SaveBlock, decoded.Type)
+
+	args := decoded.ArgsSaveBlock
+	require.Equal(t, p.ArgsSaveBlock.HeaderHash, args.HeaderHash)
+	require.Equal(t, p.ArgsSaveBlock.Header, args.Header)
+	require.Equal(t, p.ArgsSaveBlock.Body, args.Body)
+	require.Equal(t, p.ArgsSaveBlock.SignersIndexes, args.SignersIndexes)
+	require.Equal(t, p.ArgsSaveBlock.NotarizedHeadersHashes, args.NotarizedHeadersHashes)
+	require.Equal(t, p.ArgsSaveBlock.HeaderGasConsumption, args.HeaderGasConsumption)
+	require.Equal(t, p.ArgsSaveBlock.TransactionsPool.Txs, args.TransactionsPool.Txs)
+	require.Equal(t, p.ArgsSaveBlock.TransactionsPool.Scrs, args.TransactionsPool.Scrs)
+	require.Equal(t, p.ArgsSaveBlock.TransactionsPool.Rewards, args.TransactionsPool.Rewards)
+	require.Equal(t, p.ArgsSaveBlock.TransactionsPool.Receipts, args.TransactionsPool.Receipts)
+	require.Equal(t, p.ArgsSaveBlock.TransactionsPool.Logs, args.TransactionsPool.Logs)
+}
+
+func TestCodec_EncodeDecodeRevertBlock(t *testing.T) {
+	t.Parallel()
+
+	c, _ := payload.NewCodec(&mock.MarshalizerMock{})
+	p := &payload.Payload{
+		Type:   payload.RevertIndexedBlock,
+		Header: &block.MetaBlock{Nonce: 5, AccumulatedFees: big.NewInt(0), DeveloperFees: big.NewInt(0), AccumulatedFeesInEpoch: big.NewInt(0), DevFeesInEpoch: big.NewInt(0)},
+		Body:   &block.Body{},
+	}
+
+	buff, err := c.Encode(p)
+	require.NoError(t, err)
+
+	decoded, err := c.Decode(buff)
+	require.NoError(t, err)
+	require.Equal(t, p.Header, decoded.Header)
+	require.Equal(t, p.Body, decoded.Body)
+}
+
+func TestCodec_EncodeDecodeOtherPayloads(t *testing.T) {
+	t.Parallel()
+
+	c, _ := payload.NewCodec(&mock.MarshalizerMock{})
+	payloads := []*payload.Payload{
+		{Type: payload.SaveRoundsInfo, RoundsInfo: []*data.RoundInfo{{Index: 1, SignersIndexes: []uint64{1}, ShardId: 2, Epoch: 3}}},
+		{Type: payload.SaveValidatorsRating, RatingIndexID: "0_1", RatingInfo: []*data.ValidatorRatingInfo{{PublicKey: "pk", Rating: 50}}},
+		{Type: payload.SaveValidatorsPubKeys, Epoch: 1, ValidatorsPubKeys: map[uint32][][]byte{0: {[]byte("pk")}}},
+		{Type: payload.FinalizedBlock, HeaderHash: []byte("hash")},
+	}
+
+	for _, p := range payloads {
+		buff, err := c.Encode(p)
+		require.NoError(t, err)
+
+		decoded, err := c.Decode(buff)
+		require.NoError(t, err)
+		require.Equal(t, p, decoded)
+	}
+}
+
+func TestCodec_EncodeDecodeAccounts(t *testing.T) {
+	t.Parallel()
+
+	c, _ := payload.NewCodec(&mock.MarshalizerMock{})
+	p := &payload.Payload{
+		Type:      payload.SaveAccounts,
+		Timestamp: 100,
+		Accounts: []coreData.UserAccountHandler{&mock.UserAccountStub{
+			AddressBytesCalled: func() []byte {
+				return []byte("addr")
+			},
+			GetBalanceCalled: func() *big.Int {
+				return big.NewInt(1000)
+			},
+			GetNonceCalled: func() uint64 {
+				return 7
+			},
+		}},
+	}
+
+	buff, err := c.Encode(p)
+	require.NoError(t, err)
+
+	decoded, err := c.Decode(buff)
+	require.NoError(t, err)
+	require.Equal(t, uint64(100), decoded.Timestamp)
+	require.Len(t, decoded.Accounts, 1)
+	require.Equal(t, []byte("addr"), decoded.Accounts[0].AddressBytes())
+	require.Equal(t, big.NewInt(1000), decoded.Accounts[0].GetBalance())
+	require.Equal(t, uint64(7), decoded.Accounts[0].GetNonce())
+
+	_, err = decoded.Accounts[0].RetrieveValueFromDataTrieTracker([]byte("key"))
+	require.Equal(t, payload.ErrDataTrieNotAvailable, err)
+}
+
+func TestCodec_UnknownTypesShouldErr(t *testing.T) {
+	t.Parallel()
+
+	c, _ := payload.NewCodec(&mock.MarshalizerMock{})
+
+	_, err := c.Encode(nil)
+	require.Equal(t, payload.ErrNilPayload, err)
+
+	_, err = c.Encode(&payload.Payload{Type: 100})
+	require.True(t, errors.Is(err, payload.ErrUnknownPayloadType))
+
+	_, err = c.Encode(&payload.Payload{Type: payload.RevertIndexedBlock, Header: &block.Header{}, Body: &block.Body{}})
+	require.NoError(t, err)
+
+	_, err = c.Decode([]byte(`{"type":1,"header":{"type":"unknown","data":""}}`))
+	require.True(t, errors.Is(err, payload.ErrUnknownObjectType))
+}
@@ -0,0 +1,21 @@
+package payload
+
+import "errors"
+
+// ErrNilMarshalizer signals that a nil marshalizer has been provided
+var ErrNilMarshalizer = errors.New("nil marshalizer provided")
+
+// ErrNilPayload signals that a nil payload has been provided
+var ErrNilPayload = errors.New("nil payload")
+
+// ErrUnknownPayloadType signals that the type of the payload is not known
+var ErrUnknownPayloadType = errors.New("unknown payload type")
+
+// ErrUnknownObjectType signals that an object of an unknown type was provided for serialization
+var ErrUnknownObjectType = errors.New("unknown object type")
+
+// ErrDataTrieNotAvailable signals that the data trie of an account snapshot cannot be accessed
+var ErrDataTrieNotAvailable = errors.New("data trie is not available for an account snapshot")
+
+// ErrNotAnIndexerCall signals that the payload was not created for an indexer call
+var ErrNotAnIndexerCall = errors.New("payload is not an indexer call")
@@ -0,0 +1,17 @@
+package payload
+
+import (
+	coreData "github.com/ME-MotherEarth/me-core/data"
+	"github.com/ME-MotherEarth/me-core/data/indexer"
+)
+
+// CallsHandler defines the indexer calls a payload can be applied to
+type CallsHandler interface {
+	SaveBlock(args *indexer.ArgsSaveBlockData) error
+	RevertIndexedBlock(header coreData.HeaderHandler, body coreData.BodyHandler) error
+	SaveRoundsInfo(roundsInfos []*indexer.RoundInfo) error
+	SaveValidatorsPubKeys(validatorsPubKeys map[uint32][][]byte, epoch uint32) error
+	SaveValidatorsRating(indexID string, infoRating []*indexer.ValidatorRatingInfo) error
+	SaveAccounts(blockTimestamp uint64, acc []coreData.UserAccountHandler) error
+	FinalizedBlock(headerHash []byte) error
+}
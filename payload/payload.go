@@ -0,0 +1,45 @@
+package payload
+
+import (
+	coreData "github.com/ME-MotherEarth/me-core/data"
+	"github.com/ME-MotherEarth/me-core/data/indexer"
+	"github.com/ME-MotherEarth/me-elastic-indexer/data"
+)
+
+// Type identifies the indexer call whose arguments are held by a Payload
+type Type uint8
+
+const (
+	// SaveBlock identifies a payload created for a save block call
+	SaveBlock Type = iota + 1
+	// RevertIndexedBlock identifies a payload created for a revert block call
+	RevertIndexedBlock
+	// SaveRoundsInfo identifies a payload created for a save rounds info call
+	SaveRoundsInfo
+	// SaveValidatorsRating identifies a payload created for a save validators rating call
+	SaveValidatorsRating
+	// SaveValidatorsPubKeys identifies a payload created for a save validators public keys call
+	SaveValidatorsPubKeys
+	// SaveAccounts identifies a payload created for a save accounts call
+	SaveAccounts
+	// FinalizedBlock identifies a payload created for a finalized block call
+	FinalizedBlock
+	// SaveFinalizedBlock identifies a payload created for a block that is saved only after it was finalized
+	SaveFinalizedBlock
+)
+
+// Payload holds the arguments of one indexer call, so they can be persisted or transferred and replayed later
+type Payload struct {
+	Type              Type
+	HeaderHash        []byte
+	ArgsSaveBlock     *indexer.ArgsSaveBlockData
+	Header            coreData.HeaderHandler
+	Body              coreData.BodyHandler
+	RoundsInfo        []*data.RoundInfo
+	RatingIndexID     string
+	RatingInfo        []*data.ValidatorRatingInfo
+	Epoch             uint32
+	ValidatorsPubKeys map[uint32][][]byte
+	Timestamp         uint64
+	Accounts          []coreData.UserAccountHandler
+}
@@ -0,0 +1,214 @@
+package indexer
+
+import (
+	"encoding/hex"
+	"sync"
+
+	"github.com/ME-MotherEarth/me-core/core/check"
+	coreData "github.com/ME-MotherEarth/me-core/data"
+	"github.com/ME-MotherEarth/me-core/data/indexer"
+	"github.com/ME-MotherEarth/me-elastic-indexer/payload"
+	"github.com/ME-MotherEarth/me-elastic-indexer/queue"
+)
+
+type pendingBlock struct {
+	args *indexer.ArgsSaveBlockData
+	id   uint64
+}
+
+// pendingBlocks keeps the blocks that are not finalized yet. If a queue is provided, the blocks are also written in
+// it, so the ones that were not finalized before a restart are restored
+type pendingBlocks struct {
+	mutex  sync.Mutex
+	blocks []*pendingBlock
+	lastID uint64
+	queue  PersistentQueueHandler
+	codec  PayloadCodec
+}
+
+func newPendingBlocks(pendingQueue PersistentQueueHandler, codec PayloadCodec) (*pendingBlocks, error) {
+	pb := &pendingBlocks{
+		blocks: make([]*pendingBlock, 0),
+		queue:  pendingQueue,
+		codec:  codec,
+	}
+	if check.IfNil(pendingQueue) {
+		return pb, nil
+	}
+	if check.IfNil(codec) {
+		return nil, ErrNilPayloadCodec
+	}
+
+	pb.restore()
+
+	return pb, nil
+}
+
+func (pb *pendingBlocks) restore() {
+	records := pb.queue.Pending()
+	for _, record := range records {
+		p, err := pb.codec.Decode(record.Data)
+		if err == nil && (p.ArgsSaveBlock == nil || check.IfNil(p.ArgsSaveBlock.Header)) {
+			err = ErrInvalidPendingBlock
+		}
+		if err != nil {
+			log.Error("pendingBlocks: cannot restore block, it will be moved in quarantine",
+				"id", record.ID, "error", err.Error())
+			pb.quarantine(record)
+			continue
+		}
+
+		pb.blocks = append(pb.blocks, &pendingBlock{args: p.ArgsSaveBlock, id: record.ID})
+	}
+
+	if len(pb.blocks) > 0 {
+		log.Info("pendingBlocks: restored the blocks that were not finalized", "num blocks", len(pb.blocks))
+	}
+}
+
+// add will keep the provided block until it is finalized or reverted
+func (pb *pendingBlocks) add(args *indexer.ArgsSaveBlockData) (uint64, error) {
+	if check.IfNil(pb.queue) {
+		pb.mutex.Lock()
+		pb.lastID++
+		id := pb.lastID
+		pb.blocks = append(pb.blocks, &pendingBlock{args: args, id: id})
+		pb.mutex.Unlock()
+
+		return id, nil
+	}
+
+	itemData, err := pb.codec.Encode(&payload.Payload{
+		Type:          payload.SaveFinalizedBlock,
+		ArgsSaveBlock: args,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := pb.queue.Append(itemData)
+	if err != nil {
+		return 0, err
+	}
+
+	pb.mutex.Lock()
+	pb.blocks = append(pb.blocks, &pendingBlock{args: args, id: id})
+	pb.mutex.Unlock()
+
+	return id, nil
+}
+
+// remove will drop the blocks with the provided ids
+func (pb *pendingBlocks) remove(ids ...uint64) {
+	toRemove := make(map[uint64]struct{}, len(ids))
+	for _, id := range ids {
+		toRemove[id] = struct{}{}
+	}
+
+	pb.mutex.Lock()
+	remainingBlocks := make([]*pendingBlock, 0, len(pb.blocks))
+	for _, block := range pb.blocks {
+		_, shouldRemove := toRemove[block.id]
+		if !shouldRemove {
+			remainingBlocks = append(remainingBlocks, block)
+		}
+	}
+	pb.blocks = remainingBlocks
+	pb.mutex.Unlock()
+
+	pb.ack(ids)
+}
+
+// removeReverted will drop the block that matches the reverted header, together with the blocks built on top of it,
+// as they will never be finalized. The other blocks with the same nonce, such as the one that replaces it, are kept
+func (pb *pendingBlocks) removeReverted(isReverted func(header coreData.HeaderHandler) bool) {
+	pb.mutex.Lock()
+	revertedHashes := make(map[string]struct{})
+	ids := make([]uint64, 0)
+	remainingBlocks := make([]*pendingBlock, 0, len(pb.blocks))
+	// the blocks are kept in the order they were received, so a block is always checked after its parent
+	for _, block := range pb.blocks {
+		_, isParentReverted := revertedHashes[string(block.args.Header.GetPrevHash())]
+		if isParentReverted || isReverted(block.args.Header) {
+			revertedHashes[string(block.args.HeaderHash)] = struct{}{}
+			ids = append(ids, block.id)
+			continue
+		}
+
+		remainingBlocks = append(remainingBlocks, block)
+	}
+	pb.blocks = remainingBlocks
+	pb.mutex.Unlock()
+
+	pb.ack(ids)
+}
+
+// finalized returns the block with the provided hash and the blocks it is built on top of, following the previous
+// hash of each header, sorted by nonce. The other blocks with a lower or equal nonce belong to abandoned forks and are
+// returned apart, so they are dropped instead of saved. The blocks are kept until they are removed, so they are not
+// lost if they cannot be saved
+func (pb *pendingBlocks) finalized(headerHash []byte) ([]*pendingBlock, []*pendingBlock) {
+	pb.mutex.Lock()
+	defer pb.mutex.Unlock()
+
+	blocksByHash := make(map[string]*pendingBlock, len(pb.blocks))
+	for _, block := range pb.blocks {
+		blocksByHash[string(block.args.HeaderHash)] = block
+	}
+
+	finalizedBlock, found := blocksByHash[string(headerHash)]
+	if !found {
+		log.Debug("dataIndexer.FinalizedBlock: block is not kept, nothing to save", "hash", hex.EncodeToString(headerHash))
+		return nil, nil
+	}
+
+	chain := make([]*pendingBlock, 0)
+	onChain := make(map[uint64]struct{})
+	for block := finalizedBlock; block != nil; block = blocksByHash[string(block.args.Header.GetPrevHash())] {
+		if _, isVisited := onChain[block.id]; isVisited {
+			break
+		}
+		chain = append([]*pendingBlock{block}, chain...)
+		onChain[block.id] = struct{}{}
+	}
+
+	finalizedNonce := finalizedBlock.args.Header.GetNonce()
+	abandoned := make([]*pendingBlock, 0)
+	for _, block := range pb.blocks {
+		_, isOnChain := onChain[block.id]
+		if !isOnChain && block.args.Header.GetNonce() <= finalizedNonce {
+			abandoned = append(abandoned, block)
+		}
+	}
+
+	return chain, abandoned
+}
+
+func (pb *pendingBlocks) ack(ids []uint64) {
+	if check.IfNil(pb.queue) {
+		return
+	}
+
+	for _, id := range ids {
+		err := pb.queue.Ack(id)
+		if err != nil {
+			log.Warn("pendingBlocks: cannot acknowledge block, it will be restored on restart",
+				"id", id, "error", err.Error())
+		}
+	}
+}
+
+func (pb *pendingBlocks) quarantine(record *queue.Record) {
+	err := pb.queue.Quarantine(record)
+	if err != nil {
+		log.Warn("pendingBlocks: cannot move block in quarantine", "id", record.ID, "error", err.Error())
+	}
+}
+
+func (pb *pendingBlocks) close() error {
+	if check.IfNil(pb.queue) {
+		return nil
+	}
+
+	return pb.queue.Close()
+}
@@ -0,0 +1,242 @@
+package abi
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/ME-MotherEarth/me-core/core"
+	"github.com/ME-MotherEarth/me-core/core/check"
+	indexer "github.com/ME-MotherEarth/me-elastic-indexer"
+	"github.com/ME-MotherEarth/me-elastic-indexer/data"
+	logger "github.com/ME-MotherEarth/me-logger"
+)
+
+var log = logger.GetOrCreate("indexer/process/abi")
+
+const (
+	argumentsSeparator = "@"
+	upgradeFunction    = "upgradeContract"
+	// maxCachedContracts bounds the number of contracts whose ABI, found by the hash of their code, is kept in memory
+	maxCachedContracts = 10000
+)
+
+// codeHashHandler is implemented by the accounts of the node, which hold the hash of the code of the smart contracts
+type codeHashHandler interface {
+	GetCodeHash() []byte
+}
+
+// ArgsABIDecoder holds all dependencies required by the ABI decoder in order to create new instances. The accounts are
+// loaded only to find the hash of the code of the contracts that have no ABI bound to their address
+type ArgsABIDecoder struct {
+	Contracts       []ContractConfig
+	PubKeyConverter core.PubkeyConverter
+	AccountsDB      indexer.AccountsAdapter
+}
+
+type abiDecoder struct {
+	pubKeyConverter core.PubkeyConverter
+	accountsDB      indexer.AccountsAdapter
+	perAddress      map[string]*contractABI
+	perCodeHash     map[string]*contractABI
+
+	mutCache     sync.Mutex
+	cachedByCode map[string]*contractABI
+}
+
+// NewABIDecoder will create a decoder that loads the provided ABI files
+func NewABIDecoder(args ArgsABIDecoder) (*abiDecoder, error) {
+	if check.IfNil(args.PubKeyConverter) {
+		return nil, indexer.ErrNilPubkeyConverter
+	}
+	if check.IfNil(args.AccountsDB) {
+		return nil, indexer.ErrNilAccountsDB
+	}
+
+	ad := &abiDecoder{
+		pubKeyConverter: args.PubKeyConverter,
+		accountsDB:      args.AccountsDB,
+		perAddress:      make(map[string]*contractABI),
+		perCodeHash:     make(map[string]*contractABI),
+		cachedByCode:    make(map[string]*contractABI),
+	}
+	for _, contractConfig := range args.Contracts {
+		err := ad.addContract(contractConfig)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return ad, nil
+}
+
+func (ad *abiDecoder) addContract(contractConfig ContractConfig) error {
+	contract, err := loadContractABI(contractConfig.FilePath)
+	if err != nil {
+		return err
+	}
+
+	for _, address := range contractConfig.Addresses {
+		addressBytes, errDecode := ad.pubKeyConverter.Decode(address)
+		if errDecode != nil {
+			return fmt.Errorf("%w: file %s, address %s, error: %v", indexer.ErrInvalidABI, contractConfig.FilePath, address, errDecode)
+		}
+		ad.perAddress[string(addressBytes)] = contract
+	}
+	for _, codeHash := range contractConfig.CodeHashes {
+		codeHashBytes, errDecode := hex.DecodeString(codeHash)
+		if errDecode != nil {
+			return fmt.Errorf("%w: file %s, code hash %s, error: %v", indexer.ErrInvalidABI, contractConfig.FilePath, codeHash, errDecode)
+		}
+		ad.perCodeHash[string(codeHashBytes)] = contract
+	}
+
+	return nil
+}
+
+// DecodeCall will decode the arguments of the call of an endpoint of a smart contract. The arguments follow the
+// function in the data field, which can also start with a transfer of tokens
+func (ad *abiDecoder) DecodeCall(contract []byte, function string, dataField []byte) *data.DecodedData {
+	if function == "" || !core.IsSmartContractAddress(contract) {
+		return nil
+	}
+	if function == upgradeFunction {
+		// the upgraded contract can have another code, so its ABI is searched again on the next call
+		ad.removeCachedContract(contract)
+		return nil
+	}
+
+	contractDefinition := ad.getContractABI(contract)
+	if contractDefinition == nil {
+		return nil
+	}
+	endpoint, found := contractDefinition.endpoints[function]
+	if !found {
+		return nil
+	}
+
+	arguments, ok := extractArguments(function, dataField)
+	if !ok {
+		return nil
+	}
+
+	return &data.DecodedData{
+		ABI:  contractDefinition.name,
+		Name: endpoint.Name,
+		Args: decodeArguments(endpoint.Inputs, arguments, ad.pubKeyConverter),
+	}
+}
+
+// extractArguments will return the decoded arguments that follow the function in the data field
+func extractArguments(function string, dataField []byte) ([][]byte, bool) {
+	parts := strings.Split(string(dataField), argumentsSeparator)
+	functionIndex := 0
+	if parts[0] != function {
+		encodedFunction := hex.EncodeToString([]byte(function))
+		functionIndex = -1
+		for idx := 1; idx < len(parts); idx++ {
+			if parts[idx] == encodedFunction {
+				functionIndex = idx
+				break
+			}
+		}
+	}
+	if functionIndex < 0 {
+		return nil, false
+	}
+
+	arguments := make([][]byte, 0, len(parts)-functionIndex-1)
+	for _, part := range parts[functionIndex+1:] {
+		argument, err := hex.DecodeString(part)
+		if err != nil {
+			return nil, false
+		}
+		arguments = append(arguments, argument)
+	}
+
+	return arguments, true
+}
+
+// DecodeEvent will decode an event written by a smart contract. The first topic is the identifier of the event, the
+// next ones are the indexed fields, while the data holds the only field that is not indexed
+func (ad *abiDecoder) DecodeEvent(contract []byte, topics [][]byte, eventData []byte) *data.DecodedData {
+	if len(topics) == 0 || !core.IsSmartContractAddress(contract) {
+		return nil
+	}
+
+	contractDefinition := ad.getContractABI(contract)
+	if contractDefinition == nil {
+		return nil
+	}
+	event, found := contractDefinition.events[string(topics[0])]
+	if !found {
+		return nil
+	}
+
+	indexedInputs := make([]*ParameterDefinition, 0, len(event.Inputs))
+	dataInputs := make([]*ParameterDefinition, 0, 1)
+	for _, input := range event.Inputs {
+		if input.Indexed {
+			indexedInputs = append(indexedInputs, input)
+			continue
+		}
+		dataInputs = append(dataInputs, input)
+	}
+
+	args := decodeArguments(indexedInputs, topics[1:], ad.pubKeyConverter)
+	if len(dataInputs) == 1 && len(eventData) > 0 {
+		args = append(args, decodeArgument(dataInputs[0].Name, dataInputs[0].Type, eventData, ad.pubKeyConverter))
+	}
+
+	return &data.DecodedData{
+		ABI:  contractDefinition.name,
+		Name: event.Identifier,
+		Args: args,
+	}
+}
+
+func (ad *abiDecoder) getContractABI(contract []byte) *contractABI {
+	contractDefinition, found := ad.perAddress[string(contract)]
+	if found || len(ad.perCodeHash) == 0 {
+		return contractDefinition
+	}
+
+	ad.mutCache.Lock()
+	defer ad.mutCache.Unlock()
+
+	contractDefinition, found = ad.cachedByCode[string(contract)]
+	if found {
+		return contractDefinition
+	}
+
+	account, err := ad.accountsDB.LoadAccount(contract)
+	if err != nil {
+		log.Debug("abiDecoder.getContractABI: cannot load account", "address", ad.pubKeyConverter.Encode(contract), "error", err)
+		return nil
+	}
+	codeHashAccount, ok := account.(codeHashHandler)
+	if !ok {
+		return nil
+	}
+
+	if len(ad.cachedByCode) >= maxCachedContracts {
+		ad.cachedByCode = make(map[string]*contractABI)
+	}
+	// the contracts without an ABI are also kept, so their account is not loaded again
+	contractDefinition = ad.perCodeHash[string(codeHashAccount.GetCodeHash())]
+	ad.cachedByCode[string(contract)] = contractDefinition
+
+	return contractDefinition
+}
+
+func (ad *abiDecoder) removeCachedContract(contract []byte) {
+	ad.mutCache.Lock()
+	delete(ad.cachedByCode, string(contract))
+	ad.mutCache.Unlock()
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (ad *abiDecoder) IsInterfaceNil() bool {
+	return ad == nil
+}
@@ -0,0 +1,245 @@
+package abi
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"testing"
+
+	indexer "github.com/ME-MotherEarth/me-elastic-indexer"
+	"github.com/ME-MotherEarth/me-elastic-indexer/data"
+	"github.com/ME-MotherEarth/me-elastic-indexer/mock"
+	vmcommon "github.com/ME-MotherEarth/me-vm-common"
+	"github.com/stretchr/testify/require"
+)
+
+const adderABIFile = "testdata/adder.abi.json"
+
+var (
+	contractAddress = append(make([]byte, 10), bytes.Repeat([]byte{1}, 22)...)
+	otherContract   = append(make([]byte, 10), bytes.Repeat([]byte{2}, 22)...)
+	userAddress     = bytes.Repeat([]byte{3}, 32)
+)
+
+type accountWithCode struct {
+	*mock.UserAccountStub
+	codeHash []byte
+}
+
+func (awc *accountWithCode) GetCodeHash() []byte {
+	return awc.codeHash
+}
+
+func createMockArgs() ArgsABIDecoder {
+	return ArgsABIDecoder{
+		Contracts: []ContractConfig{
+			{
+				FilePath:  adderABIFile,
+				Addresses: []string{hex.EncodeToString(contractAddress)},
+			},
+		},
+		PubKeyConverter: mock.NewPubkeyConverterMock(32),
+		AccountsDB:      &mock.AccountsStub{},
+	}
+}
+
+func TestNewABIDecoder(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		args  func() ArgsABIDecoder
+		exErr error
+	}{
+		{
+			name: "NilPubKeyConverter",
+			args: func() ArgsABIDecoder {
+				args := createMockArgs()
+				args.PubKeyConverter = nil
+				return args
+			},
+			exErr: indexer.ErrNilPubkeyConverter,
+		},
+		{
+			name: "NilAccountsDB",
+			args: func() ArgsABIDecoder {
+				args := createMockArgs()
+				args.AccountsDB = nil
+				return args
+			},
+			exErr: indexer.ErrNilAccountsDB,
+		},
+		{
+			name: "InvalidAddress",
+			args: func() ArgsABIDecoder {
+				args := createMockArgs()
+				args.Contracts[0].Addresses = []string{"not hex"}
+				return args
+			},
+			exErr: indexer.ErrInvalidABI,
+		},
+		{
+			name: "InvalidCodeHash",
+			args: func() ArgsABIDecoder {
+				args := createMockArgs()
+				args.Contracts[0].CodeHashes = []string{"not hex"}
+				return args
+			},
+			exErr: indexer.ErrInvalidABI,
+		},
+		{
+			name: "ShouldWork",
+			args: func() ArgsABIDecoder {
+				return createMockArgs()
+			},
+			exErr: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewABIDecoder(tt.args())
+			require.True(t, errors.Is(err, tt.exErr))
+		})
+	}
+}
+
+func TestNewABIDecoder_MissingFileShouldErr(t *testing.T) {
+	t.Parallel()
+
+	args := createMockArgs()
+	args.Contracts[0].FilePath = "testdata/missing.abi.json"
+
+	decoder, err := NewABIDecoder(args)
+	require.Nil(t, decoder)
+	require.Error(t, err)
+}
+
+func TestAbiDecoder_DecodeCall(t *testing.T) {
+	t.Parallel()
+
+	decoder, _ := NewABIDecoder(createMockArgs())
+
+	decoded := decoder.DecodeCall(contractAddress, "add", []byte("add@0a"))
+	require.Equal(t, &data.DecodedData{
+		ABI:  "Adder",
+		Name: "add",
+		Args: []*data.DecodedArgument{
+			{Name: "value", Type: "BigUint", Value: "10"},
+		},
+	}, decoded)
+
+	// the call follows a transfer of tokens, so the function is hex encoded after the arguments of the transfer
+	dataField := []byte("MECTTransfer@" + hex.EncodeToString([]byte("TKN-abcd")) + "@01@" + hex.EncodeToString([]byte("add")) + "@ff")
+	decoded = decoder.DecodeCall(contractAddress, "add", dataField)
+	require.Equal(t, &data.DecodedData{
+		ABI:  "Adder",
+		Name: "add",
+		Args: []*data.DecodedArgument{
+			{Name: "value", Type: "BigUint", Value: "255"},
+		},
+	}, decoded)
+}
+
+func TestAbiDecoder_DecodeCallOptionalAndVariadicArguments(t *testing.T) {
+	t.Parallel()
+
+	decoder, _ := NewABIDecoder(createMockArgs())
+
+	dataField := []byte("deposit@" + hex.EncodeToString(userAddress) + "@ff")
+	decoded := decoder.DecodeCall(contractAddress, "deposit", dataField)
+	require.Equal(t, []*data.DecodedArgument{
+		{Name: "owner", Type: "Address", Value: hex.EncodeToString(userAddress)},
+		{Name: "delta", Type: "i64", Value: "-1"},
+	}, decoded.Args)
+
+	dataField = []byte("deposit@" + hex.EncodeToString(userAddress) + "@05@" + hex.EncodeToString([]byte("rent")))
+	decoded = decoder.DecodeCall(contractAddress, "deposit", dataField)
+	require.Equal(t, &data.DecodedArgument{Name: "note", Type: "utf-8 string", Value: "rent"}, decoded.Args[2])
+
+	dataField = []byte("addTokens@" + hex.EncodeToString([]byte("AAA-0102")) + "@" + hex.EncodeToString([]byte("BBB-0304")))
+	decoded = decoder.DecodeCall(contractAddress, "addTokens", dataField)
+	require.Equal(t, []*data.DecodedArgument{
+		{Name: "tokens", Type: "TokenIdentifier", Value: "AAA-0102"},
+		{Name: "tokens", Type: "TokenIdentifier", Value: "BBB-0304"},
+	}, decoded.Args)
+}
+
+func TestAbiDecoder_DecodeCallShouldNotDecode(t *testing.T) {
+	t.Parallel()
+
+	decoder, _ := NewABIDecoder(createMockArgs())
+
+	require.Nil(t, decoder.DecodeCall(contractAddress, "", []byte("add@0a")))
+	require.Nil(t, decoder.DecodeCall(userAddress, "add", []byte("add@0a")))
+	require.Nil(t, decoder.DecodeCall(otherContract, "add", []byte("add@0a")))
+	require.Nil(t, decoder.DecodeCall(contractAddress, "unknown", []byte("unknown@0a")))
+	require.Nil(t, decoder.DecodeCall(contractAddress, "add", []byte("add@not hex")))
+	require.Nil(t, decoder.DecodeCall(contractAddress, "add", []byte("MECTTransfer@01@02")))
+}
+
+func TestAbiDecoder_DecodeEvent(t *testing.T) {
+	t.Parallel()
+
+	decoder, _ := NewABIDecoder(createMockArgs())
+
+	topics := [][]byte{[]byte("added"), userAddress, {1}}
+	decoded := decoder.DecodeEvent(contractAddress, topics, big.NewInt(1000).Bytes())
+	require.Equal(t, &data.DecodedData{
+		ABI:  "Adder",
+		Name: "added",
+		Args: []*data.DecodedArgument{
+			{Name: "caller", Type: "Address", Value: hex.EncodeToString(userAddress)},
+			{Name: "enabled", Type: "bool", Value: "true"},
+			{Name: "sum", Type: "BigUint", Value: "1000"},
+		},
+	}, decoded)
+
+	require.Nil(t, decoder.DecodeEvent(contractAddress, nil, nil))
+	require.Nil(t, decoder.DecodeEvent(contractAddress, [][]byte{[]byte("unknown")}, nil))
+	require.Nil(t, decoder.DecodeEvent(userAddress, topics, nil))
+}
+
+func TestAbiDecoder_DecodeCallByCodeHash(t *testing.T) {
+	t.Parallel()
+
+	codeHash := []byte("code hash")
+	numLoads := 0
+	args := createMockArgs()
+	args.Contracts[0].Addresses = nil
+	args.Contracts[0].CodeHashes = []string{hex.EncodeToString(codeHash)}
+	args.AccountsDB = &mock.AccountsStub{
+		LoadAccountCalled: func(container []byte) (vmcommon.AccountHandler, error) {
+			numLoads++
+			if bytes.Equal(container, contractAddress) {
+				return &accountWithCode{UserAccountStub: &mock.UserAccountStub{}, codeHash: codeHash}, nil
+			}
+			return &accountWithCode{UserAccountStub: &mock.UserAccountStub{}, codeHash: []byte("other")}, nil
+		},
+	}
+	decoder, _ := NewABIDecoder(args)
+
+	require.NotNil(t, decoder.DecodeCall(contractAddress, "add", []byte("add@0a")))
+	require.NotNil(t, decoder.DecodeCall(contractAddress, "add", []byte("add@0b")))
+	require.Equal(t, 1, numLoads)
+
+	require.Nil(t, decoder.DecodeCall(otherContract, "add", []byte("add@0a")))
+	require.Nil(t, decoder.DecodeCall(otherContract, "add", []byte("add@0a")))
+	require.Equal(t, 2, numLoads)
+
+	// the account is loaded again after the contract is upgraded
+	require.Nil(t, decoder.DecodeCall(contractAddress, upgradeFunction, []byte("upgradeContract@00")))
+	require.NotNil(t, decoder.DecodeCall(contractAddress, "add", []byte("add@0a")))
+	require.Equal(t, 3, numLoads)
+}
+
+func TestAbiDecoder_IsInterfaceNil(t *testing.T) {
+	t.Parallel()
+
+	decoder, _ := NewABIDecoder(createMockArgs())
+	require.False(t, decoder.IsInterfaceNil())
+
+	decoder = nil
+	require.True(t, decoder.IsInterfaceNil())
+}
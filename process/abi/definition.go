@@ -0,0 +1,106 @@
+package abi
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	indexer "github.com/ME-MotherEarth/me-elastic-indexer"
+)
+
+// ContractConfig binds an ABI file to the smart contracts it describes. The contracts are selected by their address
+// or by the hex encoded hash of their code, so the ABI also applies to every contract deployed from the same code
+type ContractConfig struct {
+	FilePath   string
+	Addresses  []string
+	CodeHashes []string
+}
+
+// Definition holds the parts of a smart contract ABI file used to decode its calls and events
+type Definition struct {
+	Name      string                `json:"name"`
+	Endpoints []*EndpointDefinition `json:"endpoints"`
+	Events    []*EventDefinition    `json:"events"`
+}
+
+// EndpointDefinition holds the name and the arguments of an endpoint of a smart contract
+type EndpointDefinition struct {
+	Name   string                 `json:"name"`
+	Inputs []*ParameterDefinition `json:"inputs"`
+}
+
+// EventDefinition holds the identifier and the fields of an event written by a smart contract. The indexed fields are
+// written as topics, after the identifier, while the other field is written as the data of the event
+type EventDefinition struct {
+	Identifier string                 `json:"identifier"`
+	Inputs     []*ParameterDefinition `json:"inputs"`
+}
+
+// ParameterDefinition holds the name and the type of an argument of an endpoint or of a field of an event
+type ParameterDefinition struct {
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Indexed bool   `json:"indexed"`
+}
+
+// contractABI is the definition of a smart contract with its endpoints and events mapped by name
+type contractABI struct {
+	name      string
+	endpoints map[string]*EndpointDefinition
+	events    map[string]*EventDefinition
+}
+
+func loadContractABI(filePath string) (*contractABI, error) {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	definition := &Definition{}
+	err = json.Unmarshal(content, definition)
+	if err != nil {
+		return nil, fmt.Errorf("%w: file %s, error: %v", indexer.ErrInvalidABI, filePath, err)
+	}
+
+	return newContractABI(definition)
+}
+
+func newContractABI(definition *Definition) (*contractABI, error) {
+	contract := &contractABI{
+		name:      definition.Name,
+		endpoints: make(map[string]*EndpointDefinition, len(definition.Endpoints)),
+		events:    make(map[string]*EventDefinition, len(definition.Events)),
+	}
+	for _, endpoint := range definition.Endpoints {
+		if endpoint == nil || endpoint.Name == "" {
+			return nil, fmt.Errorf("%w: contract %s has an endpoint without name", indexer.ErrInvalidABI, definition.Name)
+		}
+		err := checkParameters(definition.Name, endpoint.Inputs)
+		if err != nil {
+			return nil, err
+		}
+		contract.endpoints[endpoint.Name] = endpoint
+	}
+	for _, event := range definition.Events {
+		if event == nil || event.Identifier == "" {
+			return nil, fmt.Errorf("%w: contract %s has an event without identifier", indexer.ErrInvalidABI, definition.Name)
+		}
+		err := checkParameters(definition.Name, event.Inputs)
+		if err != nil {
+			return nil, err
+		}
+		contract.events[event.Identifier] = event
+	}
+
+	return contract, nil
+}
+
+func checkParameters(contractName string, parameters []*ParameterDefinition) error {
+	for _, parameter := range parameters {
+		if parameter == nil || parameter.Type == "" {
+			return fmt.Errorf("%w: contract %s has a parameter without type", indexer.ErrInvalidABI, contractName)
+		}
+	}
+
+	return nil
+}
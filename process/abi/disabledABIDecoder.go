@@ -0,0 +1,25 @@
+package abi
+
+import "github.com/ME-MotherEarth/me-elastic-indexer/data"
+
+type disabledABIDecoder struct{}
+
+// NewDisabledABIDecoder will create an ABI decoder that does not decode anything
+func NewDisabledABIDecoder() *disabledABIDecoder {
+	return &disabledABIDecoder{}
+}
+
+// DecodeCall returns nil
+func (dad *disabledABIDecoder) DecodeCall(_ []byte, _ string, _ []byte) *data.DecodedData {
+	return nil
+}
+
+// DecodeEvent returns nil
+func (dad *disabledABIDecoder) DecodeEvent(_ []byte, _ [][]byte, _ []byte) *data.DecodedData {
+	return nil
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (dad *disabledABIDecoder) IsInterfaceNil() bool {
+	return dad == nil
+}
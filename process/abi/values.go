@@ -0,0 +1,108 @@
+package abi
+
+import (
+	"encoding/hex"
+	"math/big"
+	"strings"
+
+	"github.com/ME-MotherEarth/me-core/core"
+	"github.com/ME-MotherEarth/me-elastic-indexer/data"
+)
+
+const (
+	optionalTypePrefix = "optional<"
+	variadicTypePrefix = "variadic<"
+	genericTypeSuffix  = ">"
+	stringType         = "utf-8 string"
+	tokenTypeSuffix    = "TokenIdentifier"
+)
+
+var unsignedTypes = map[string]struct{}{
+	"BigUint": {}, "u8": {}, "u16": {}, "u32": {}, "u64": {}, "usize": {},
+}
+
+var signedTypes = map[string]struct{}{
+	"BigInt": {}, "i8": {}, "i16": {}, "i32": {}, "i64": {}, "isize": {},
+}
+
+// decodeArguments will decode the provided top encoded arguments with the types of the parameters. An optional
+// parameter is decoded only if its argument was provided, while a variadic one takes all the remaining arguments
+func decodeArguments(parameters []*ParameterDefinition, arguments [][]byte, pubKeyConverter core.PubkeyConverter) []*data.DecodedArgument {
+	decodedArgs := make([]*data.DecodedArgument, 0, len(arguments))
+	for _, parameter := range parameters {
+		if len(arguments) == 0 {
+			break
+		}
+
+		valueType, isVariadic := unwrapType(parameter.Type, variadicTypePrefix)
+		if !isVariadic {
+			valueType, _ = unwrapType(parameter.Type, optionalTypePrefix)
+			decodedArgs = append(decodedArgs, decodeArgument(parameter.Name, valueType, arguments[0], pubKeyConverter))
+			arguments = arguments[1:]
+			continue
+		}
+
+		for _, argument := range arguments {
+			decodedArgs = append(decodedArgs, decodeArgument(parameter.Name, valueType, argument, pubKeyConverter))
+		}
+		arguments = nil
+	}
+
+	return decodedArgs
+}
+
+func unwrapType(valueType string, prefix string) (string, bool) {
+	if !strings.HasPrefix(valueType, prefix) || !strings.HasSuffix(valueType, genericTypeSuffix) {
+		return valueType, false
+	}
+
+	return strings.TrimSuffix(strings.TrimPrefix(valueType, prefix), genericTypeSuffix), true
+}
+
+func decodeArgument(name string, valueType string, value []byte, pubKeyConverter core.PubkeyConverter) *data.DecodedArgument {
+	return &data.DecodedArgument{
+		Name:  name,
+		Type:  valueType,
+		Value: decodeTopEncodedValue(valueType, value, pubKeyConverter),
+	}
+}
+
+// decodeTopEncodedValue will return the value as a string that can be searched. The numbers are written in base 10,
+// the addresses are encoded with the address converter and the values of the other types are hex encoded
+func decodeTopEncodedValue(valueType string, value []byte, pubKeyConverter core.PubkeyConverter) string {
+	if _, isUnsigned := unsignedTypes[valueType]; isUnsigned {
+		return big.NewInt(0).SetBytes(value).String()
+	}
+	if _, isSigned := signedTypes[valueType]; isSigned {
+		return decodeSignedNumber(value).String()
+	}
+	if valueType == stringType || strings.HasSuffix(valueType, tokenTypeSuffix) {
+		return string(value)
+	}
+
+	switch valueType {
+	case "bool":
+		if len(value) == 1 && value[0] == 1 {
+			return "true"
+		}
+		return "false"
+	case "Address":
+		if len(value) == pubKeyConverter.Len() {
+			return pubKeyConverter.Encode(value)
+		}
+	}
+
+	return hex.EncodeToString(value)
+}
+
+// decodeSignedNumber will decode a number written in two's complement on the minimum number of bytes
+func decodeSignedNumber(value []byte) *big.Int {
+	number := big.NewInt(0).SetBytes(value)
+	isNegative := len(value) > 0 && value[0]&0x80 != 0
+	if !isNegative {
+		return number
+	}
+
+	modulus := big.NewInt(0).Lsh(big.NewInt(1), uint(len(value)*8))
+	return number.Sub(number, modulus)
+}
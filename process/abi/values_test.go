@@ -0,0 +1,58 @@
+package abi
+
+import (
+	"errors"
+	"testing"
+
+	indexer "github.com/ME-MotherEarth/me-elastic-indexer"
+	"github.com/ME-MotherEarth/me-elastic-indexer/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeTopEncodedValue(t *testing.T) {
+	t.Parallel()
+
+	pubKeyConverter := mock.NewPubkeyConverterMock(2)
+
+	require.Equal(t, "0", decodeTopEncodedValue("BigUint", nil, pubKeyConverter))
+	require.Equal(t, "65535", decodeTopEncodedValue("u64", []byte{0xff, 0xff}, pubKeyConverter))
+	require.Equal(t, "127", decodeTopEncodedValue("i8", []byte{0x7f}, pubKeyConverter))
+	require.Equal(t, "-128", decodeTopEncodedValue("i8", []byte{0x80}, pubKeyConverter))
+	require.Equal(t, "-256", decodeTopEncodedValue("BigInt", []byte{0xff, 0x00}, pubKeyConverter))
+	require.Equal(t, "0", decodeTopEncodedValue("BigInt", nil, pubKeyConverter))
+	require.Equal(t, "true", decodeTopEncodedValue("bool", []byte{1}, pubKeyConverter))
+	require.Equal(t, "false", decodeTopEncodedValue("bool", nil, pubKeyConverter))
+	require.Equal(t, "text", decodeTopEncodedValue("utf-8 string", []byte("text"), pubKeyConverter))
+	require.Equal(t, "TKN-abcd", decodeTopEncodedValue("MOAOrMECTTokenIdentifier", []byte("TKN-abcd"), pubKeyConverter))
+	require.Equal(t, "0102", decodeTopEncodedValue("Address", []byte{1, 2}, pubKeyConverter))
+	require.Equal(t, "010203", decodeTopEncodedValue("Address", []byte{1, 2, 3}, pubKeyConverter))
+	require.Equal(t, "0a0b", decodeTopEncodedValue("MyStruct", []byte{0x0a, 0x0b}, pubKeyConverter))
+}
+
+func TestDecodeArguments_MissingArguments(t *testing.T) {
+	t.Parallel()
+
+	parameters := []*ParameterDefinition{
+		{Name: "first", Type: "u32"},
+		{Name: "second", Type: "u32"},
+	}
+
+	args := decodeArguments(parameters, [][]byte{{5}}, mock.NewPubkeyConverterMock(32))
+	require.Len(t, args, 1)
+	require.Equal(t, "5", args[0].Value)
+}
+
+func TestNewContractABI_InvalidDefinitions(t *testing.T) {
+	t.Parallel()
+
+	_, err := newContractABI(&Definition{Name: "c", Endpoints: []*EndpointDefinition{{}}})
+	require.True(t, errors.Is(err, indexer.ErrInvalidABI))
+
+	_, err = newContractABI(&Definition{Name: "c", Events: []*EventDefinition{nil}})
+	require.True(t, errors.Is(err, indexer.ErrInvalidABI))
+
+	_, err = newContractABI(&Definition{Name: "c", Endpoints: []*EndpointDefinition{
+		{Name: "add", Inputs: []*ParameterDefinition{{Name: "value"}}},
+	}})
+	require.True(t, errors.Is(err, indexer.ErrInvalidABI))
+}
@@ -149,17 +149,20 @@ func (ap *accountsProcessor) PrepareRegularAccountsMap(timestamp uint64, account
 		address := ap.addressPubkeyConverter.Encode(userAccount.UserAccount.AddressBytes())
 		balance := userAccount.UserAccount.GetBalance()
 		balanceAsFloat := ap.balanceConverter.ComputeBalanceAsFloat(balance)
+		balanceSortable := converters.BigIntToSortableString(balance)
 		acc := &data.AccountInfo{
-			Address:                  address,
-			Nonce:                    userAccount.UserAccount.GetNonce(),
-			Balance:                  converters.BigIntToString(balance),
-			BalanceNum:               balanceAsFloat,
-			IsSender:                 userAccount.IsSender,
-			IsSmartContract:          core.IsSmartContractAddress(userAccount.UserAccount.AddressBytes()),
-			TotalBalanceWithStake:    converters.BigIntToString(balance),
-			TotalBalanceWithStakeNum: balanceAsFloat,
-			Timestamp:                time.Duration(timestamp),
-			ShardID:                  ap.shardID,
+			Address:                       address,
+			Nonce:                         userAccount.UserAccount.GetNonce(),
+			Balance:                       converters.BigIntToString(balance),
+			BalanceNum:                    balanceAsFloat,
+			BalanceSortable:               balanceSortable,
+			IsSender:                      userAccount.IsSender,
+			IsSmartContract:               core.IsSmartContractAddress(userAccount.UserAccount.AddressBytes()),
+			TotalBalanceWithStake:         converters.BigIntToString(balance),
+			TotalBalanceWithStakeNum:      balanceAsFloat,
+			TotalBalanceWithStakeSortable: balanceSortable,
+			Timestamp:                     time.Duration(timestamp),
+			ShardID:                       ap.shardID,
 		}
 
 		accountsMap[address] = acc
@@ -198,6 +201,7 @@ func (ap *accountsProcessor) PrepareAccountsMapMECT(
 			TokenNonce:      accountMECT.NFTNonce,
 			Balance:         balance.String(),
 			BalanceNum:      ap.balanceConverter.ComputeMECTBalanceAsFloat(balance),
+			BalanceSortable: converters.BigIntToSortableString(balance),
 			Properties:      properties,
 			IsSender:        accountMECT.IsSender,
 			IsSmartContract: core.IsSmartContractAddress(accountMECT.Account.AddressBytes()),
@@ -226,6 +230,28 @@ func (ap *accountsProcessor) PrepareAccountsMapMECT(
 	return accountsMECTMap, tokensData
 }
 
+// PutBalanceNumWithDecimalsInAccountsMECT will compute again the balance as float of the provided accounts MECT,
+// using the number of decimals of their tokens. The accounts of the tokens whose number of decimals is not provided
+// keep the balance computed with the denomination of the network
+func (ap *accountsProcessor) PutBalanceNumWithDecimalsInAccountsMECT(
+	accountsMECT map[string]*data.AccountInfo,
+	tokensDecimals map[string]uint64,
+) {
+	for _, accountMECT := range accountsMECT {
+		numDecimals, ok := tokensDecimals[accountMECT.TokenName]
+		if !ok {
+			continue
+		}
+
+		balance, ok := big.NewInt(0).SetString(accountMECT.Balance, 10)
+		if !ok {
+			continue
+		}
+
+		accountMECT.BalanceNum = ap.balanceConverter.ComputeMECTBalanceAsFloatWithDecimals(balance, numDecimals)
+	}
+}
+
 // PrepareAccountsHistory will prepare a map of accounts history balance from a map of accounts
 func (ap *accountsProcessor) PrepareAccountsHistory(
 	timestamp uint64,
@@ -236,6 +262,8 @@ func (ap *accountsProcessor) PrepareAccountsHistory(
 		acc := &data.AccountBalanceHistory{
 			Address:         userAccount.Address,
 			Balance:         userAccount.Balance,
+			BalanceNum:      userAccount.BalanceNum,
+			BalanceSortable: userAccount.BalanceSortable,
 			Timestamp:       time.Duration(timestamp),
 			Token:           userAccount.TokenName,
 			TokenNonce:      userAccount.TokenNonce,
@@ -333,14 +333,16 @@ func TestAccountsProcessor_PrepareAccountsMapMOA(t *testing.T) {
 	res := ap.PrepareRegularAccountsMap(123, []*data.Account{moaAccount})
 	require.Equal(t, map[string]*data.AccountInfo{
 		hex.EncodeToString([]byte(addr)): {
-			Address:                  hex.EncodeToString([]byte(addr)),
-			Nonce:                    1,
-			Balance:                  "1000",
-			BalanceNum:               balanceConverter.ComputeBalanceAsFloat(big.NewInt(1000)),
-			TotalBalanceWithStake:    "1000",
-			TotalBalanceWithStakeNum: balanceConverter.ComputeBalanceAsFloat(big.NewInt(1000)),
-			IsSmartContract:          true,
-			Timestamp:                time.Duration(123),
+			Address:                       hex.EncodeToString([]byte(addr)),
+			Nonce:                         1,
+			Balance:                       "1000",
+			BalanceNum:                    balanceConverter.ComputeBalanceAsFloat(big.NewInt(1000)),
+			BalanceSortable:               converters.BigIntToSortableString(big.NewInt(1000)),
+			TotalBalanceWithStake:         "1000",
+			TotalBalanceWithStakeNum:      balanceConverter.ComputeBalanceAsFloat(big.NewInt(1000)),
+			TotalBalanceWithStakeSortable: converters.BigIntToSortableString(big.NewInt(1000)),
+			IsSmartContract:               true,
+			Timestamp:                     time.Duration(123),
 		},
 	}, res)
 }
@@ -386,6 +388,7 @@ func TestAccountsProcessor_PrepareAccountsMapMECT(t *testing.T) {
 		Address:         hex.EncodeToString([]byte(addr)),
 		Balance:         "1000",
 		BalanceNum:      balanceConverter.ComputeBalanceAsFloat(big.NewInt(1000)),
+		BalanceSortable: converters.BigIntToSortableString(big.NewInt(1000)),
 		TokenName:       "token",
 		TokenIdentifier: "token-0f",
 		Properties:      hex.EncodeToString([]byte("ok")),
@@ -400,6 +403,7 @@ func TestAccountsProcessor_PrepareAccountsMapMECT(t *testing.T) {
 		Address:         hex.EncodeToString([]byte(addr)),
 		Balance:         "1000",
 		BalanceNum:      balanceConverter.ComputeBalanceAsFloat(big.NewInt(1000)),
+		BalanceSortable: converters.BigIntToSortableString(big.NewInt(1000)),
 		TokenName:       "token",
 		TokenIdentifier: "token-10",
 		Properties:      hex.EncodeToString([]byte("ok")),
@@ -416,11 +420,13 @@ func TestAccountsProcessor_PrepareAccountsHistory(t *testing.T) {
 
 	accounts := map[string]*data.AccountInfo{
 		"addr1": {
-			Address:    "addr1",
-			Balance:    "112",
-			TokenName:  "token-112",
-			TokenNonce: 10,
-			IsSender:   true,
+			Address:         "addr1",
+			Balance:         "112",
+			BalanceNum:      1.12,
+			BalanceSortable: converters.BigIntToSortableString(big.NewInt(112)),
+			TokenName:       "token-112",
+			TokenNonce:      10,
+			IsSender:        true,
 		},
 	}
 
@@ -429,16 +435,54 @@ func TestAccountsProcessor_PrepareAccountsHistory(t *testing.T) {
 	res := ap.PrepareAccountsHistory(100, accounts)
 	accountBalanceHistory := res["addr1-token-112-10"]
 	require.Equal(t, &data.AccountBalanceHistory{
-		Address:    "addr1",
-		Timestamp:  100,
-		Balance:    "112",
-		Token:      "token-112",
-		IsSender:   true,
-		TokenNonce: 10,
-		Identifier: "token-112-0a",
+		Address:         "addr1",
+		Timestamp:       100,
+		Balance:         "112",
+		BalanceNum:      1.12,
+		BalanceSortable: converters.BigIntToSortableString(big.NewInt(112)),
+		Token:           "token-112",
+		IsSender:        true,
+		TokenNonce:      10,
+		Identifier:      "token-112-0a",
 	}, accountBalanceHistory)
 }
 
+func TestAccountsProcessor_PutBalanceNumWithDecimalsInAccountsMECT(t *testing.T) {
+	t.Parallel()
+
+	accounts := map[string]*data.AccountInfo{
+		"addr1-TKN-abcd-0": {
+			Address:    "addr1",
+			Balance:    "1234567",
+			BalanceNum: 0.0001234567,
+			TokenName:  "TKN-abcd",
+		},
+		"addr1-NFT-abcd-1": {
+			Address:    "addr1",
+			Balance:    "2",
+			BalanceNum: 0.0000000002,
+			TokenName:  "NFT-abcd",
+			TokenNonce: 1,
+		},
+		"addr1-UNK-abcd-0": {
+			Address:    "addr1",
+			Balance:    "1000",
+			BalanceNum: 0.0000001,
+			TokenName:  "UNK-abcd",
+		},
+	}
+
+	ap, _ := NewAccountsProcessor(&mock.MarshalizerMock{}, mock.NewPubkeyConverterMock(32), &mock.AccountsStub{}, balanceConverter, 0)
+
+	ap.PutBalanceNumWithDecimalsInAccountsMECT(accounts, map[string]uint64{
+		"TKN-abcd": 6,
+		"NFT-abcd": 0,
+	})
+	require.Equal(t, 1.234567, accounts["addr1-TKN-abcd-0"].BalanceNum)
+	require.Equal(t, float64(2), accounts["addr1-NFT-abcd-1"].BalanceNum)
+	require.Equal(t, 0.0000001, accounts["addr1-UNK-abcd-0"].BalanceNum)
+}
+
 func TestAccountsProcessor_GetUserAccountErrors(t *testing.T) {
 	t.Parallel()
 
@@ -23,15 +23,14 @@ func TestSerializeNFTCreateInfo(t *testing.T) {
 		},
 	}
 
-	buffSlice := data.NewBufferSlice(data.DefaultMaxBulkSize)
-	err := (&accountsProcessor{}).SerializeNFTCreateInfo(nftsCreateInfo, buffSlice, "tokens")
+	docs := data.NewDocumentsSlice()
+	err := (&accountsProcessor{}).SerializeNFTCreateInfo(nftsCreateInfo, docs, "tokens")
 	require.NoError(t, err)
-	require.Equal(t, 1, len(buffSlice.Buffers()))
 
-	expectedRes := `{ "index" : { "_index":"tokens", "_id" : "my-token-001-0f" } }
-{"identifier":"my-token-001-0f","token":"my-token-0001","type":"NonFungibleMECT","data":{"creator":"010102","nonEmptyURIs":false,"whiteListedStorage":false}}
-`
-	require.Equal(t, expectedRes, buffSlice.Buffers()[0].String())
+	expectedDocs := []*data.Document{
+		{Index: "tokens", ID: "my-token-001-0f", Action: data.ActionIndex, Body: nftsCreateInfo[0]},
+	}
+	require.Equal(t, expectedDocs, docs.Documents())
 }
 
 func TestSerializeAccounts(t *testing.T) {
@@ -50,15 +49,14 @@ func TestSerializeAccounts(t *testing.T) {
 		},
 	}
 
-	buffSlice := data.NewBufferSlice(data.DefaultMaxBulkSize)
-	err := (&accountsProcessor{}).SerializeAccounts(accs, buffSlice, "accounts")
+	docs := data.NewDocumentsSlice()
+	err := (&accountsProcessor{}).SerializeAccounts(accs, docs, "accounts")
 	require.NoError(t, err)
-	require.Equal(t, 1, len(buffSlice.Buffers()))
 
-	expectedRes := `{ "update" : {"_index": "accounts", "_id" : "addr1" } }
-{"scripted_upsert": true, "script": {"source": "if ('create' == ctx.op) {ctx._source = params.account} else {if (ctx._source.containsKey('timestamp')) {if (ctx._source.timestamp <= params.account.timestamp) {ctx._source = params.account}} else {ctx._source = params.account}}","lang": "painless","params": { "account": {"address":"addr1","nonce":1,"balance":"50","balanceNum":0.1,"totalBalanceWithStake":"50","totalBalanceWithStakeNum":0.1,"shardID":0} }},"upsert": {}}
-`
-	require.Equal(t, expectedRes, buffSlice.Buffers()[0].String())
+	expectedDocs := []*data.Document{
+		{Index: "accounts", ID: "addr1", Action: data.ActionIndex, Body: accs["addr1"]},
+	}
+	require.Equal(t, expectedDocs, docs.Documents())
 }
 
 func TestSerializeAccountsMECTNonceZero(t *testing.T) {
@@ -77,15 +75,14 @@ func TestSerializeAccountsMECTNonceZero(t *testing.T) {
 		},
 	}
 
-	buffSlice := data.NewBufferSlice(data.DefaultMaxBulkSize)
-	err := (&accountsProcessor{}).SerializeAccountsMECT(accs, nil, buffSlice, "accountsmect")
+	docs := data.NewDocumentsSlice()
+	err := (&accountsProcessor{}).SerializeAccountsMECT(accs, nil, docs, "accountsmect")
 	require.NoError(t, err)
-	require.Equal(t, 1, len(buffSlice.Buffers()))
 
-	expectedRes := `{ "update" : {"_index": "accountsmect", "_id" : "addr1-token-abcd-00" } }
-{"scripted_upsert": true, "script": {"source": "if ('create' == ctx.op) {ctx._source = params.account} else {if (ctx._source.containsKey('timestamp')) {if (ctx._source.timestamp <= params.account.timestamp) {ctx._source = params.account}} else {ctx._source = params.account}}","lang": "painless","params": { "account": {"address":"addr1","nonce":1,"balance":"10000000000000","balanceNum":1,"token":"token-abcd","properties":"000","timestamp":123,"shardID":0} }},"upsert": {}}
-`
-	require.Equal(t, expectedRes, buffSlice.Buffers()[0].String())
+	expectedDocs := []*data.Document{
+		{Index: "accountsmect", ID: "addr1-token-abcd-00", Action: data.ActionIndex, Body: accs["addr1"], Timestamp: 123},
+	}
+	require.Equal(t, expectedDocs, docs.Documents())
 }
 
 func TestSerializeAccountsMECT(t *testing.T) {
@@ -103,15 +100,14 @@ func TestSerializeAccountsMECT(t *testing.T) {
 		},
 	}
 
-	buffSlice := data.NewBufferSlice(data.DefaultMaxBulkSize)
-	err := (&accountsProcessor{}).SerializeAccountsMECT(accs, nil, buffSlice, "accountsmect")
+	docs := data.NewDocumentsSlice()
+	err := (&accountsProcessor{}).SerializeAccountsMECT(accs, nil, docs, "accountsmect")
 	require.NoError(t, err)
-	require.Equal(t, 1, len(buffSlice.Buffers()))
 
-	expectedRes := `{ "update" : {"_index": "accountsmect", "_id" : "addr1-token-0001-05" } }
-{"scripted_upsert": true, "script": {"source": "if ('create' == ctx.op) {ctx._source = params.account} else {if (ctx._source.containsKey('timestamp')) {if (ctx._source.timestamp <= params.account.timestamp) {ctx._source = params.account}} else {ctx._source = params.account}}","lang": "painless","params": { "account": {"address":"addr1","nonce":1,"balance":"10000000000000","balanceNum":1,"token":"token-0001","tokenNonce":5,"properties":"000","shardID":0} }},"upsert": {}}
-`
-	require.Equal(t, expectedRes, buffSlice.Buffers()[0].String())
+	expectedDocs := []*data.Document{
+		{Index: "accountsmect", ID: "addr1-token-0001-05", Action: data.ActionIndex, Body: accs["addr1"]},
+	}
+	require.Equal(t, expectedDocs, docs.Documents())
 }
 
 func TestSerializeAccountsNFTWithMedaData(t *testing.T) {
@@ -143,15 +139,14 @@ func TestSerializeAccountsNFTWithMedaData(t *testing.T) {
 		},
 	}
 
-	buffSlice := data.NewBufferSlice(data.DefaultMaxBulkSize)
-	err := (&accountsProcessor{}).SerializeAccountsMECT(accs, nil, buffSlice, "accountsmect")
+	docs := data.NewDocumentsSlice()
+	err := (&accountsProcessor{}).SerializeAccountsMECT(accs, nil, docs, "accountsmect")
 	require.NoError(t, err)
-	require.Equal(t, 1, len(buffSlice.Buffers()))
 
-	expectedRes := `{ "update" : {"_index": "accountsmect", "_id" : "addr1-token-0001-16" } }
-{"scripted_upsert": true, "script": {"source": "if ('create' == ctx.op) {ctx._source = params.account} else {if (ctx._source.containsKey('timestamp')) {if (ctx._source.timestamp <= params.account.timestamp) {ctx._source = params.account}} else {ctx._source = params.account}}","lang": "painless","params": { "account": {"address":"addr1","nonce":1,"balance":"10000000000000","balanceNum":1,"token":"token-0001","identifier":"token-0001-5","tokenNonce":22,"properties":"000","data":{"name":"nft","creator":"010101","royalties":1,"hash":"aGFzaA==","uris":["dXJp"],"tags":["test","free","fun"],"attributes":"dGFnczp0ZXN0LGZyZWUsZnVuO2Rlc2NyaXB0aW9uOlRoaXMgaXMgYSB0ZXN0IGRlc2NyaXB0aW9uIGZvciBhbiBhd2Vzb21lIG5mdA==","metadata":"metadata-test","nonEmptyURIs":true,"whiteListedStorage":false},"shardID":0} }},"upsert": {}}
-`
-	require.Equal(t, expectedRes, buffSlice.Buffers()[0].String())
+	expectedDocs := []*data.Document{
+		{Index: "accountsmect", ID: "addr1-token-0001-16", Action: data.ActionIndex, Body: accs["addr1"]},
+	}
+	require.Equal(t, expectedDocs, docs.Documents())
 }
 
 func TestSerializeAccountsMECTDelete(t *testing.T) {
@@ -168,15 +163,14 @@ func TestSerializeAccountsMECTDelete(t *testing.T) {
 		},
 	}
 
-	buffSlice := data.NewBufferSlice(data.DefaultMaxBulkSize)
-	err := (&accountsProcessor{}).SerializeAccountsMECT(accs, nil, buffSlice, "accountsmect")
+	docs := data.NewDocumentsSlice()
+	err := (&accountsProcessor{}).SerializeAccountsMECT(accs, nil, docs, "accountsmect")
 	require.NoError(t, err)
-	require.Equal(t, 1, len(buffSlice.Buffers()))
 
-	expectedRes := `{ "update" : {"_index":"accountsmect", "_id" : "addr1-token-0001-00" } }
-{"scripted_upsert": true, "script": {"source": "if ('create' == ctx.op) {ctx.op = 'noop'} else {if (ctx._source.containsKey('timestamp')) {if (ctx._source.timestamp <= params.timestamp) {ctx.op = 'delete'}} else {ctx.op = 'delete'}}","lang": "painless","params": {"timestamp": 0}},"upsert": {}}
-`
-	require.Equal(t, expectedRes, buffSlice.Buffers()[0].String())
+	expectedDocs := []*data.Document{
+		{Index: "accountsmect", ID: "addr1-token-0001-00", Action: data.ActionDelete},
+	}
+	require.Equal(t, expectedDocs, docs.Documents())
 }
 
 func TestSerializeAccountsHistory(t *testing.T) {
@@ -193,13 +187,12 @@ func TestSerializeAccountsHistory(t *testing.T) {
 		},
 	}
 
-	buffSlice := data.NewBufferSlice(data.DefaultMaxBulkSize)
-	err := (&accountsProcessor{}).SerializeAccountsHistory(accsh, buffSlice, "accountshistory")
+	docs := data.NewDocumentsSlice()
+	err := (&accountsProcessor{}).SerializeAccountsHistory(accsh, docs, "accountshistory")
 	require.NoError(t, err)
-	require.Equal(t, 1, len(buffSlice.Buffers()))
 
-	expectedRes := `{ "index" : { "_index":"accountshistory", "_id" : "account1-token-0001-00-10" } }
-{"address":"account1","timestamp":10,"balance":"123","token":"token-0001","isSender":true,"isSmartContract":true,"shardID":0}
-`
-	require.Equal(t, expectedRes, buffSlice.Buffers()[0].String())
+	expectedDocs := []*data.Document{
+		{Index: "accountshistory", ID: "account1-token-0001-00-10", Action: data.ActionIndex, Body: accsh["account1"]},
+	}
+	require.Equal(t, expectedDocs, docs.Documents())
 }
@@ -1,6 +1,7 @@
 package block
 
 import (
+	"encoding/json"
 	"errors"
 	"math/big"
 	"testing"
@@ -27,12 +28,13 @@ func TestBlockProcessor_SerializeBlock(t *testing.T) {
 
 	bp, _ := NewBlockProcessor(&mock.HasherMock{}, &mock.MarshalizerMock{})
 
-	buffSlice := data.NewBufferSlice(data.DefaultMaxBulkSize)
-	err := bp.SerializeBlock(&data.Block{Nonce: 1}, buffSlice, "blocks")
+	docs := data.NewDocumentsSlice()
+	elasticBlock := &data.Block{Nonce: 1}
+	err := bp.SerializeBlock(elasticBlock, docs, "blocks")
 	require.Nil(t, err)
-	require.Equal(t, `{ "index" : { "_index":"blocks", "_id" : "" } }
-{"nonce":1,"round":0,"epoch":0,"miniBlocksHashes":null,"notarizedBlocksHashes":null,"proposer":0,"validators":null,"pubKeyBitmap":"","size":0,"sizeTxs":0,"timestamp":0,"stateRootHash":"","prevHash":"","shardId":0,"txCount":0,"notarizedTxsCount":0,"accumulatedFees":"","developerFees":"","epochStartBlock":false,"searchOrder":0,"gasProvided":0,"gasRefunded":0,"gasPenalized":0,"maxGasLimit":0}
-`, buffSlice.Buffers()[0].String())
+	require.Equal(t, []*data.Document{
+		{Index: "blocks", ID: "", Action: data.ActionIndex, Body: elasticBlock},
+	}, docs.Documents())
 }
 
 func TestBlockProcessor_SerializeEpochInfoDataErrors(t *testing.T) {
@@ -52,15 +54,20 @@ func TestBlockProcessor_SerializeEpochInfoData(t *testing.T) {
 
 	bp, _ := NewBlockProcessor(&mock.HasherMock{}, &mock.MarshalizerMock{})
 
-	buffSlice := data.NewBufferSlice(data.DefaultMaxBulkSize)
+	docs := data.NewDocumentsSlice()
 	err := bp.SerializeEpochInfoData(&dataBlock.MetaBlock{
 		AccumulatedFeesInEpoch: big.NewInt(1),
 		DevFeesInEpoch:         big.NewInt(2),
-	}, buffSlice, "epochinfo")
+	}, docs, "epochinfo")
 	require.Nil(t, err)
-	require.Equal(t, `{ "index" : { "_index":"epochinfo", "_id" : "0" } }
-{"accumulatedFees":"1","developerFees":"2"}
-`, buffSlice.Buffers()[0].String())
+	require.Equal(t, []*data.Document{
+		{
+			Index:  "epochinfo",
+			ID:     "0",
+			Action: data.ActionIndex,
+			Body:   &data.EpochInfo{AccumulatedFees: "1", DeveloperFees: "2"},
+		},
+	}, docs.Documents())
 }
 
 func TestBlockProcessor_SerializeBlockEpochStartMeta(t *testing.T) {
@@ -68,8 +75,8 @@ func TestBlockProcessor_SerializeBlockEpochStartMeta(t *testing.T) {
 
 	bp, _ := NewBlockProcessor(&mock.HasherMock{}, &mock.MarshalizerMock{})
 
-	buffSlice := data.NewBufferSlice(data.DefaultMaxBulkSize)
-	err := bp.SerializeBlock(&data.Block{
+	docs := data.NewDocumentsSlice()
+	elasticBlock := &data.Block{
 		Nonce:                 1,
 		Round:                 2,
 		Epoch:                 3,
@@ -100,9 +107,13 @@ func TestBlockProcessor_SerializeBlockEpochStartMeta(t *testing.T) {
 			PrevEpochStartRound:              222,
 			PrevEpochStartHash:               "7072657645706f6368",
 		},
-	}, buffSlice, "blocks")
+	}
+	err := bp.SerializeBlock(elasticBlock, docs, "blocks")
+	require.Nil(t, err)
+	require.Len(t, docs.Documents(), 1)
+	require.Equal(t, "11cb2a3a28522a11ae646a93aa4d50f87194cead7d6edeb333d502349407b61d", docs.Documents()[0].ID)
+
+	serializedBlock, err := json.Marshal(docs.Documents()[0].Body)
 	require.Nil(t, err)
-	require.Equal(t, `{ "index" : { "_index":"blocks", "_id" : "11cb2a3a28522a11ae646a93aa4d50f87194cead7d6edeb333d502349407b61d" } }
-{"nonce":1,"round":2,"epoch":3,"miniBlocksHashes":["mb1Hash","mbHash2"],"notarizedBlocksHashes":["notarized1"],"proposer":5,"validators":[0,1,2,3,4,5],"pubKeyBitmap":"00000110","size":345,"sizeTxs":0,"timestamp":123456,"stateRootHash":"stateHash","prevHash":"prevHash","shardId":4294967295,"txCount":100,"notarizedTxsCount":120,"accumulatedFees":"1000","developerFees":"50","epochStartBlock":true,"searchOrder":1010,"epochStartInfo":{"totalSupply":"100","totalToDistribute":"55","totalNewlyMinted":"20","rewardsPerBlock":"15","rewardsForProtocolSustainability":"2","nodePrice":"10","prevEpochStartRound":222,"prevEpochStartHash":"7072657645706f6368"},"gasProvided":0,"gasRefunded":0,"gasPenalized":0,"maxGasLimit":0}
-`, buffSlice.Buffers()[0].String())
+	require.Equal(t, `{"nonce":1,"round":2,"epoch":3,"miniBlocksHashes":["mb1Hash","mbHash2"],"notarizedBlocksHashes":["notarized1"],"proposer":5,"validators":[0,1,2,3,4,5],"pubKeyBitmap":"00000110","size":345,"sizeTxs":0,"timestamp":123456,"stateRootHash":"stateHash","prevHash":"prevHash","shardId":4294967295,"txCount":100,"notarizedTxsCount":120,"accumulatedFees":"1000","developerFees":"50","epochStartBlock":true,"searchOrder":1010,"epochStartInfo":{"totalSupply":"100","totalToDistribute":"55","totalNewlyMinted":"20","rewardsPerBlock":"15","rewardsForProtocolSustainability":"2","nodePrice":"10","prevEpochStartRound":222,"prevEpochStartHash":"7072657645706f6368"},"gasProvided":0,"gasRefunded":0,"gasPenalized":0,"maxGasLimit":0,"finalized":false}`, string(serializedBlock))
 }
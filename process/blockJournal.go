@@ -0,0 +1,228 @@
+package process
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"time"
+
+	coreData "github.com/ME-MotherEarth/me-core/data"
+	elasticIndexer "github.com/ME-MotherEarth/me-elastic-indexer"
+	"github.com/ME-MotherEarth/me-elastic-indexer/data"
+)
+
+// journaledIndexes holds the indices whose documents are recorded in the journal before a block changes them. The
+// blocks, miniblocks, transactions, smart contract results and operations are removed by hash when a block is reverted
+var journaledIndexes = map[string]struct{}{
+	elasticIndexer.AccountsIndex:            {},
+	elasticIndexer.AccountsHistoryIndex:     {},
+	elasticIndexer.AccountsMECTIndex:        {},
+	elasticIndexer.AccountsMECTHistoryIndex: {},
+	elasticIndexer.ReceiptsIndex:            {},
+	elasticIndexer.LogsIndex:                {},
+	elasticIndexer.EventsIndex:              {},
+	elasticIndexer.TransfersIndex:           {},
+	elasticIndexer.SCDeploysIndex:           {},
+	elasticIndexer.TokensIndex:              {},
+	elasticIndexer.TagsIndex:                {},
+	elasticIndexer.DelegatorsIndex:          {},
+	elasticIndexer.CollectionsIndex:         {},
+}
+
+// createJournaledIndexes returns the journaled indices, with the revertible indices declared by the event processors
+// added by integrators
+func createJournaledIndexes(revertibleIndexes []string) map[string]struct{} {
+	indexes := make(map[string]struct{}, len(journaledIndexes)+len(revertibleIndexes))
+	for index := range journaledIndexes {
+		indexes[index] = struct{}{}
+	}
+	for _, index := range revertibleIndexes {
+		indexes[index] = struct{}{}
+	}
+
+	return indexes
+}
+
+// the blocks that are received only after they were finalized are never reverted, so they are not journaled
+func (ei *elasticProcessor) isJournalEnabled() bool {
+	return ei.isIndexEnabled(elasticIndexer.JournalIndex) && !ei.finalizedDataOnly
+}
+
+// journalBlockChanges will save the current state of the documents that are going to be changed by the block. If the
+// block already has a journal, as it happens when a failed save is retried, only the documents missing from it are
+// added, so the recorded state is the one from before the first attempt
+func (ei *elasticProcessor) journalBlockChanges(headerHash []byte, header coreData.HeaderHandler, documents []*data.Document) error {
+	if !ei.isJournalEnabled() {
+		return nil
+	}
+
+	journalID := hex.EncodeToString(headerHash)
+	blockJournal, err := ei.getBlockJournal(journalID)
+	if err != nil {
+		return err
+	}
+	if blockJournal == nil {
+		blockJournal = &data.BlockJournal{
+			Nonce:     header.GetNonce(),
+			ShardID:   header.GetShardID(),
+			Timestamp: time.Duration(header.GetTimeStamp()),
+			Entries:   make([]*data.JournalEntry, 0),
+		}
+	}
+
+	idsPerIndex := getIDsToJournal(blockJournal, documents, ei.journaledIndexes)
+	if len(idsPerIndex) == 0 {
+		return nil
+	}
+
+	indices := make([]string, 0, len(idsPerIndex))
+	for index := range idsPerIndex {
+		indices = append(indices, index)
+	}
+	sort.Strings(indices)
+
+	for _, index := range indices {
+		ids := idsPerIndex[index]
+		existingDocuments, errGet := ei.sink.GetDocuments(index, ids)
+		if errGet != nil {
+			return errGet
+		}
+
+		for _, id := range ids {
+			blockJournal.Entries = append(blockJournal.Entries, &data.JournalEntry{
+				Index:    index,
+				ID:       id,
+				Previous: existingDocuments[id],
+			})
+		}
+	}
+
+	return ei.sink.WriteDocuments([]*data.Document{{
+		Index:  elasticIndexer.JournalIndex,
+		ID:     journalID,
+		Action: data.ActionIndex,
+		Body:   blockJournal,
+	}})
+}
+
+// getIDsToJournal returns, grouped by index and in the order they are first changed, the ids of the documents that
+// are not already in the journal
+func getIDsToJournal(
+	blockJournal *data.BlockJournal,
+	documents []*data.Document,
+	journaledIndexes map[string]struct{},
+) map[string][]string {
+	journaled := make(map[string]map[string]struct{})
+	markJournaled := func(index string, id string) bool {
+		ids, found := journaled[index]
+		if !found {
+			ids = make(map[string]struct{})
+			journaled[index] = ids
+		}
+
+		_, alreadyJournaled := ids[id]
+		ids[id] = struct{}{}
+
+		return alreadyJournaled
+	}
+
+	for _, entry := range blockJournal.Entries {
+		markJournaled(entry.Index, entry.ID)
+	}
+
+	idsPerIndex := make(map[string][]string)
+	for _, document := range documents {
+		_, shouldJournal := journaledIndexes[document.Index]
+		if !shouldJournal {
+			continue
+		}
+
+		if markJournaled(document.Index, document.ID) {
+			continue
+		}
+
+		idsPerIndex[document.Index] = append(idsPerIndex[document.Index], document.ID)
+	}
+
+	return idsPerIndex
+}
+
+func (ei *elasticProcessor) getBlockJournal(journalID string) (*data.BlockJournal, error) {
+	documents, err := ei.sink.GetDocuments(elasticIndexer.JournalIndex, []string{journalID})
+	if err != nil {
+		return nil, err
+	}
+
+	source, found := documents[journalID]
+	if !found {
+		return nil, nil
+	}
+
+	blockJournal := &data.BlockJournal{}
+	err = json.Unmarshal(source, blockJournal)
+	if err != nil {
+		return nil, err
+	}
+
+	return blockJournal, nil
+}
+
+// RevertBlockChanges will restore the documents changed by the block to the state recorded in its journal and will
+// remove the journal afterwards
+func (ei *elasticProcessor) RevertBlockChanges(header coreData.HeaderHandler) error {
+	if !ei.isJournalEnabled() {
+		return nil
+	}
+
+	headerHash, err := ei.blockProc.ComputeHeaderHash(header)
+	if err != nil {
+		return err
+	}
+
+	journalID := hex.EncodeToString(headerHash)
+	blockJournal, err := ei.getBlockJournal(journalID)
+	if err != nil {
+		return err
+	}
+	if blockJournal == nil {
+		log.Debug("elasticProcessor.RevertBlockChanges: no journal for block, only the block data will be removed",
+			"hash", journalID, "nonce", header.GetNonce())
+		return nil
+	}
+
+	rejected := &data.RejectedDocumentsError{}
+	err = rejected.Add(ei.sink.WriteDocuments(prepareRestoreDocuments(blockJournal)))
+	if err != nil {
+		return err
+	}
+
+	err = rejected.Add(ei.sink.WriteDocuments(prepareDeleteDocuments(elasticIndexer.JournalIndex, []string{journalID})))
+	if err != nil {
+		return err
+	}
+
+	return rejected.AsError()
+}
+
+func prepareRestoreDocuments(blockJournal *data.BlockJournal) []*data.Document {
+	documents := make([]*data.Document, 0, len(blockJournal.Entries))
+	for _, entry := range blockJournal.Entries {
+		if len(entry.Previous) == 0 {
+			documents = append(documents, &data.Document{
+				Index:  entry.Index,
+				ID:     entry.ID,
+				Action: data.ActionDelete,
+			})
+			continue
+		}
+
+		documents = append(documents, &data.Document{
+			Index:  entry.Index,
+			ID:     entry.ID,
+			Action: data.ActionIndex,
+			Body:   entry.Previous,
+		})
+	}
+
+	return documents
+}
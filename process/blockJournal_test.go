@@ -0,0 +1,223 @@
+package process
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/ME-MotherEarth/me-core/core"
+	dataBlock "github.com/ME-MotherEarth/me-core/data/block"
+	"github.com/ME-MotherEarth/me-core/data/indexer"
+	elasticIndexer "github.com/ME-MotherEarth/me-elastic-indexer"
+	"github.com/ME-MotherEarth/me-elastic-indexer/data"
+	"github.com/ME-MotherEarth/me-elastic-indexer/mock"
+	"github.com/ME-MotherEarth/me-elastic-indexer/process/sink/docstate"
+	"github.com/stretchr/testify/require"
+)
+
+func createStoreSinkStub(t *testing.T, store map[string]map[string][]byte) *mock.DocumentsSinkStub {
+	return &mock.DocumentsSinkStub{
+		WriteDocumentsCalled: func(documents []*data.Document) error {
+			for _, document := range documents {
+				if store[document.Index] == nil {
+					store[document.Index] = make(map[string][]byte)
+				}
+
+				if document.Action != data.ActionIndex && document.Action != data.ActionDelete {
+					require.Fail(t, "unexpected action")
+				}
+
+				state := docstate.NewState(store[document.Index][document.ID])
+				require.Nil(t, state.Apply(document))
+				if !state.Exists() {
+					delete(store[document.Index], document.ID)
+					continue
+				}
+
+				body, err := state.Serialize()
+				require.Nil(t, err)
+				store[document.Index][document.ID] = body
+			}
+
+			return nil
+		},
+		GetDocumentsCalled: func(index string, ids []string) (map[string][]byte, error) {
+			documents := make(map[string][]byte)
+			for _, id := range ids {
+				source, found := store[index][id]
+				if found {
+					documents[id] = source
+				}
+			}
+
+			return documents, nil
+		},
+	}
+}
+
+func TestElasticProcessor_RevertBlockChangesShouldRestoreJournaledDocuments(t *testing.T) {
+	t.Parallel()
+
+	store := map[string]map[string][]byte{
+		elasticIndexer.TokensIndex: {"TKN": []byte(`{"name":"old"}`)},
+	}
+	arguments := createMockElasticProcessorArgs()
+	arguments.EnabledIndexes[elasticIndexer.JournalIndex] = struct{}{}
+	elasticProc := newElasticsearchProcessor(createStoreSinkStub(t, store), arguments)
+
+	header := &dataBlock.Header{Nonce: 7, ShardID: 1, TimeStamp: 100}
+	headerHash, _ := core.CalculateHash(&mock.MarshalizerMock{}, &mock.HasherMock{}, header)
+
+	documents := []*data.Document{
+		{Index: elasticIndexer.TokensIndex, ID: "TKN", Action: data.ActionIndex, Body: &data.TokenInfo{Name: "new"}},
+		{Index: elasticIndexer.LogsIndex, ID: "log", Action: data.ActionIndex, Body: &data.Logs{Address: "addr"}},
+		{Index: elasticIndexer.TokensIndex, ID: "TKN", Action: data.ActionIndex, Body: &data.TokenInfo{Name: "newer"}},
+		{Index: elasticIndexer.TransactionsIndex, ID: "tx", Action: data.ActionIndex, Body: &data.Transaction{}},
+	}
+	err := elasticProc.journalBlockChanges(headerHash, header, documents)
+	require.Nil(t, err)
+	err = elasticProc.sink.WriteDocuments(documents)
+	require.Nil(t, err)
+
+	// a retried save should keep the state recorded before the first attempt
+	documents = append(documents, &data.Document{Index: elasticIndexer.TagsIndex, ID: "tag", Action: data.ActionIndex, Body: map[string]interface{}{"count": 1}})
+	err = elasticProc.journalBlockChanges(headerHash, header, documents)
+	require.Nil(t, err)
+	err = elasticProc.sink.WriteDocuments(documents)
+	require.Nil(t, err)
+
+	blockJournal, err := elasticProc.getBlockJournal(hex.EncodeToString(headerHash))
+	require.Nil(t, err)
+	require.Equal(t, &data.BlockJournal{
+		Nonce:     7,
+		ShardID:   1,
+		Timestamp: 100,
+		Entries: []*data.JournalEntry{
+			{Index: elasticIndexer.LogsIndex, ID: "log"},
+			{Index: elasticIndexer.TokensIndex, ID: "TKN", Previous: json.RawMessage(`{"name":"old"}`)},
+			{Index: elasticIndexer.TagsIndex, ID: "tag"},
+		},
+	}, blockJournal)
+
+	err = elasticProc.RevertBlockChanges(header)
+	require.Nil(t, err)
+	require.Equal(t, map[string]map[string][]byte{
+		elasticIndexer.TokensIndex:       {"TKN": []byte(`{"name":"old"}`)},
+		elasticIndexer.LogsIndex:         {},
+		elasticIndexer.TagsIndex:         {},
+		elasticIndexer.TransactionsIndex: {"tx": store[elasticIndexer.TransactionsIndex]["tx"]},
+		elasticIndexer.JournalIndex:      {},
+	}, store)
+}
+
+func TestElasticProcessor_JournalDisabledShouldNotAccessTheSink(t *testing.T) {
+	t.Parallel()
+
+	arguments := createMockElasticProcessorArgs()
+	documentsSink := &mock.DocumentsSinkStub{
+		WriteDocumentsCalled: func(documents []*data.Document) error {
+			require.Fail(t, "should have not been called")
+			return nil
+		},
+		GetDocumentsCalled: func(index string, ids []string) (map[string][]byte, error) {
+			require.Fail(t, "should have not been called")
+			return nil, nil
+		},
+	}
+	elasticProc := newElasticsearchProcessor(documentsSink, arguments)
+
+	header := &dataBlock.Header{Nonce: 7}
+	documents := []*data.Document{{Index: elasticIndexer.TokensIndex, ID: "TKN", Action: data.ActionIndex}}
+	err := elasticProc.journalBlockChanges([]byte("hash"), header, documents)
+	require.Nil(t, err)
+
+	err = elasticProc.RevertBlockChanges(header)
+	require.Nil(t, err)
+}
+
+func TestElasticProcessor_RevertBlockChangesShouldRestoreRevertibleCustomIndices(t *testing.T) {
+	t.Parallel()
+
+	store := map[string]map[string][]byte{
+		"myorders": {"order": []byte(`{"status":"open"}`)},
+	}
+	arguments := createMockElasticProcessorArgs()
+	arguments.EnabledIndexes[elasticIndexer.JournalIndex] = struct{}{}
+	arguments.RevertibleIndexes = []string{"myorders"}
+	elasticProc := newElasticsearchProcessor(createStoreSinkStub(t, store), arguments)
+
+	header := &dataBlock.Header{Nonce: 7, ShardID: 1, TimeStamp: 100}
+	documents := []*data.Document{
+		{Index: "myorders", ID: "order", Action: data.ActionIndex, Body: map[string]interface{}{"status": "filled"}},
+		{Index: "myevents", ID: "event", Action: data.ActionIndex, Body: map[string]interface{}{"kind": "fill"}},
+	}
+	headerHash, _ := core.CalculateHash(&mock.MarshalizerMock{}, &mock.HasherMock{}, header)
+	err := elasticProc.journalBlockChanges(headerHash, header, documents)
+	require.Nil(t, err)
+	err = elasticProc.sink.WriteDocuments(documents)
+	require.Nil(t, err)
+
+	err = elasticProc.RevertBlockChanges(header)
+	require.Nil(t, err)
+	require.Equal(t, []byte(`{"status":"open"}`), store["myorders"]["order"])
+	require.Equal(t, []byte(`{"kind":"fill"}`), store["myevents"]["event"])
+}
+
+func TestElasticProcessor_FinalizeBlockShouldRemoveTheJournalsOfTheShardUntilTheFinalizedNonce(t *testing.T) {
+	t.Parallel()
+
+	store := make(map[string]map[string][]byte)
+	deletedMatching := make([]map[string]interface{}, 0)
+	documentsSink := createStoreSinkStub(t, store)
+	writeInStore := documentsSink.WriteDocumentsCalled
+	documentsSink.WriteDocumentsCalled = func(documents []*data.Document) error {
+		for _, document := range documents {
+			if document.Index == elasticIndexer.JournalIndex {
+				require.Nil(t, writeInStore([]*data.Document{document}))
+			}
+		}
+
+		return nil
+	}
+	documentsSink.DeleteMatchingCalled = func(index string, fields map[string]interface{}) error {
+		require.Equal(t, elasticIndexer.JournalIndex, index)
+		deletedMatching = append(deletedMatching, fields)
+		return nil
+	}
+	arguments := createMockElasticProcessorArgs()
+	arguments.EnabledIndexes = map[string]struct{}{
+		elasticIndexer.BlockIndex:    {},
+		elasticIndexer.JournalIndex:  {},
+		elasticIndexer.AccountsIndex: {},
+	}
+	elasticProc := newElasticsearchProcessor(documentsSink, arguments)
+	elasticProc.selfShardID = 1
+
+	header := &dataBlock.Header{Nonce: 7, ShardID: 1}
+	headerHash, _ := core.CalculateHash(&mock.MarshalizerMock{}, &mock.HasherMock{}, header)
+	err := elasticProc.SaveHeader(headerHash, header, nil, &dataBlock.Body{}, nil, indexer.HeaderGasConsumption{}, 0)
+	require.Nil(t, err)
+
+	err = elasticProc.FinalizeBlock(headerHash)
+	require.Nil(t, err)
+	require.Equal(t, []map[string]interface{}{{"shardId": uint32(1), "nonce": data.AtMost{Value: 7}}}, deletedMatching)
+
+	// the block indexed before a restart is not tracked anymore, its nonce is read from its journal
+	untrackedHeader := &dataBlock.Header{Nonce: 9, ShardID: 1}
+	untrackedHash, _ := core.CalculateHash(&mock.MarshalizerMock{}, &mock.HasherMock{}, untrackedHeader)
+	err = elasticProc.journalBlockChanges(untrackedHash, untrackedHeader, []*data.Document{
+		{Index: elasticIndexer.AccountsIndex, ID: "addr", Action: data.ActionIndex, Body: map[string]interface{}{"balance": "1"}},
+	})
+	require.Nil(t, err)
+
+	deletedMatching = deletedMatching[:0]
+	err = elasticProc.FinalizeBlock(untrackedHash)
+	require.Nil(t, err)
+	require.Equal(t, []map[string]interface{}{{"shardId": uint32(1), "nonce": data.AtMost{Value: 9}}}, deletedMatching)
+
+	// without a journal or a tracked block, the finalized nonce is unknown
+	deletedMatching = deletedMatching[:0]
+	err = elasticProc.FinalizeBlock([]byte("unknown"))
+	require.Nil(t, err)
+	require.Empty(t, deletedMatching)
+}
@@ -3,6 +3,7 @@ package process
 import (
 	"github.com/ME-MotherEarth/me-core/core/check"
 	elasticIndexer "github.com/ME-MotherEarth/me-elastic-indexer"
+	"github.com/ME-MotherEarth/me-elastic-indexer/process/sink"
 )
 
 func checkArguments(arguments *ArgElasticProcessor) error {
@@ -12,8 +13,8 @@ func checkArguments(arguments *ArgElasticProcessor) error {
 	if arguments.EnabledIndexes == nil {
 		return elasticIndexer.ErrNilEnabledIndexesMap
 	}
-	if check.IfNilReflect(arguments.DBClient) {
-		return elasticIndexer.ErrNilDatabaseClient
+	if check.IfNil(arguments.Sink) {
+		return sink.ErrNilDocumentsSink
 	}
 	if check.IfNilReflect(arguments.StatisticsProc) {
 		return elasticIndexer.ErrNilStatisticHandler
@@ -39,6 +40,9 @@ func checkArguments(arguments *ArgElasticProcessor) error {
 	if check.IfNilReflect(arguments.OperationsProc) {
 		return elasticIndexer.ErrNilOperationsHandler
 	}
+	if check.IfNil(arguments.EventsPublisher) {
+		return elasticIndexer.ErrNilEventsPublisher
+	}
 
 	return nil
 }
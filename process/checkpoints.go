@@ -0,0 +1,108 @@
+package process
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	coreData "github.com/ME-MotherEarth/me-core/data"
+	elasticIndexer "github.com/ME-MotherEarth/me-elastic-indexer"
+	"github.com/ME-MotherEarth/me-elastic-indexer/data"
+)
+
+// SaveCheckpoint will record the provided block as the last one indexed for its shard. It is called after all the
+// data of the block was saved, so a block older than the checkpoint is either indexed or was never received. The
+// checkpoint is only moved forward, so an older block saved again does not lower it
+func (ei *elasticProcessor) SaveCheckpoint(headerHash []byte, header coreData.HeaderHandler) error {
+	if !ei.isIndexEnabled(elasticIndexer.CheckpointsIndex) {
+		return nil
+	}
+
+	document := checkpointDocument(&data.Checkpoint{
+		ShardID:   header.GetShardID(),
+		Nonce:     header.GetNonce(),
+		Hash:      hex.EncodeToString(headerHash),
+		Timestamp: time.Duration(header.GetTimeStamp()),
+	})
+	document.Nonce = header.GetNonce()
+
+	return ei.sink.WriteDocuments([]*data.Document{document})
+}
+
+// GetCheckpoints returns the checkpoints of the provided shards. The shards that have no indexed block are skipped
+func (ei *elasticProcessor) GetCheckpoints(shardIDs []uint32) ([]*data.Checkpoint, error) {
+	if !ei.isIndexEnabled(elasticIndexer.CheckpointsIndex) {
+		return nil, nil
+	}
+
+	ids := make([]string, 0, len(shardIDs))
+	for _, shardID := range shardIDs {
+		ids = append(ids, checkpointID(shardID))
+	}
+
+	documents, err := ei.sink.GetDocuments(elasticIndexer.CheckpointsIndex, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	checkpoints := make([]*data.Checkpoint, 0, len(documents))
+	for _, id := range ids {
+		source, found := documents[id]
+		if !found {
+			continue
+		}
+
+		checkpoint := &data.Checkpoint{}
+		err = json.Unmarshal(source, checkpoint)
+		if err != nil {
+			return nil, fmt.Errorf("%w while decoding the checkpoint %s", err, id)
+		}
+
+		checkpoints = append(checkpoints, checkpoint)
+	}
+
+	return checkpoints, nil
+}
+
+// revertCheckpoint will move the checkpoint of the shard before the reverted block, if the block was already
+// recorded by it
+func (ei *elasticProcessor) revertCheckpoint(header coreData.HeaderHandler) error {
+	if !ei.isIndexEnabled(elasticIndexer.CheckpointsIndex) {
+		return nil
+	}
+
+	checkpoints, err := ei.GetCheckpoints([]uint32{header.GetShardID()})
+	if err != nil {
+		return err
+	}
+	if len(checkpoints) == 0 || checkpoints[0].Nonce < header.GetNonce() {
+		return nil
+	}
+
+	nonce := header.GetNonce()
+	if nonce > 0 {
+		nonce--
+	}
+
+	return ei.sink.WriteDocuments([]*data.Document{checkpointDocument(&data.Checkpoint{
+		ShardID:   header.GetShardID(),
+		Nonce:     nonce,
+		Hash:      hex.EncodeToString(header.GetPrevHash()),
+		Timestamp: checkpoints[0].Timestamp,
+	})})
+}
+
+func checkpointDocument(checkpoint *data.Checkpoint) *data.Document {
+	return &data.Document{
+		Index:  elasticIndexer.CheckpointsIndex,
+		ID:     checkpointID(checkpoint.ShardID),
+		Action: data.ActionIndex,
+		Body:   checkpoint,
+	}
+}
+
+func checkpointID(shardID uint32) string {
+	return strconv.FormatUint(uint64(shardID), 10)
+}
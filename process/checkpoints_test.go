@@ -0,0 +1,79 @@
+package process
+
+import (
+	"encoding/hex"
+	"testing"
+
+	dataBlock "github.com/ME-MotherEarth/me-core/data/block"
+	elasticIndexer "github.com/ME-MotherEarth/me-elastic-indexer"
+	"github.com/ME-MotherEarth/me-elastic-indexer/data"
+	"github.com/ME-MotherEarth/me-elastic-indexer/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestElasticProcessor_SaveCheckpointIndexNotEnabledShouldDoNothing(t *testing.T) {
+	t.Parallel()
+
+	called := false
+	elasticProc := newElasticsearchProcessor(&mock.DocumentsSinkStub{
+		WriteDocumentsCalled: func(_ []*data.Document) error {
+			called = true
+			return nil
+		},
+	}, createMockElasticProcessorArgs())
+
+	err := elasticProc.SaveCheckpoint([]byte("hash"), &dataBlock.Header{Nonce: 1})
+	require.Nil(t, err)
+	require.False(t, called)
+
+	checkpoints, err := elasticProc.GetCheckpoints([]uint32{0})
+	require.Nil(t, err)
+	require.Nil(t, checkpoints)
+}
+
+func TestElasticProcessor_SaveAndGetCheckpoints(t *testing.T) {
+	t.Parallel()
+
+	store := make(map[string]map[string][]byte)
+	arguments := createMockElasticProcessorArgs()
+	arguments.EnabledIndexes[elasticIndexer.CheckpointsIndex] = struct{}{}
+	elasticProc := newElasticsearchProcessor(createStoreSinkStub(t, store), arguments)
+
+	require.Nil(t, elasticProc.SaveCheckpoint([]byte("h1"), &dataBlock.Header{Nonce: 1, ShardID: 1, TimeStamp: 10}))
+	require.Nil(t, elasticProc.SaveCheckpoint([]byte("h2"), &dataBlock.Header{Nonce: 2, ShardID: 1, TimeStamp: 16}))
+	require.Nil(t, elasticProc.SaveCheckpoint([]byte("m5"), &dataBlock.MetaBlock{Nonce: 5, TimeStamp: 12}))
+	require.Len(t, store[elasticIndexer.CheckpointsIndex], 2)
+
+	// a block saved again should not move the checkpoint back
+	require.Nil(t, elasticProc.SaveCheckpoint([]byte("h1"), &dataBlock.Header{Nonce: 1, ShardID: 1, TimeStamp: 10}))
+
+	checkpoints, err := elasticProc.GetCheckpoints([]uint32{0, 1, 0})
+	require.Nil(t, err)
+	require.Equal(t, []*data.Checkpoint{
+		{ShardID: 1, Nonce: 2, Hash: hex.EncodeToString([]byte("h2")), Timestamp: 16},
+	}, checkpoints)
+}
+
+func TestElasticProcessor_RemoveHeaderShouldMoveCheckpointBack(t *testing.T) {
+	t.Parallel()
+
+	store := make(map[string]map[string][]byte)
+	arguments := createMockElasticProcessorArgs()
+	arguments.EnabledIndexes[elasticIndexer.CheckpointsIndex] = struct{}{}
+	elasticProc := newElasticsearchProcessor(createStoreSinkStub(t, store), arguments)
+
+	prevHash := []byte("h6")
+	header := &dataBlock.Header{Nonce: 7, ShardID: 0, TimeStamp: 20, PrevHash: prevHash}
+	require.Nil(t, elasticProc.SaveCheckpoint([]byte("h7"), header))
+
+	notIndexedHeader := &dataBlock.Header{Nonce: 8, ShardID: 0, PrevHash: []byte("h7")}
+	require.Nil(t, elasticProc.RemoveHeader(notIndexedHeader))
+	checkpoints, _ := elasticProc.GetCheckpoints([]uint32{0})
+	require.Equal(t, uint64(7), checkpoints[0].Nonce)
+
+	require.Nil(t, elasticProc.RemoveHeader(header))
+	checkpoints, _ = elasticProc.GetCheckpoints([]uint32{0})
+	require.Equal(t, []*data.Checkpoint{
+		{ShardID: 0, Nonce: 6, Hash: hex.EncodeToString(prevHash), Timestamp: 20},
+	}, checkpoints)
+}
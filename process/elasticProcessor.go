@@ -1,9 +1,9 @@
 package process
 
 import (
-	"bytes"
 	"encoding/hex"
-	"fmt"
+	"encoding/json"
+	"sync"
 
 	"github.com/ME-MotherEarth/me-core/core"
 	"github.com/ME-MotherEarth/me-core/core/check"
@@ -11,239 +11,138 @@ import (
 	"github.com/ME-MotherEarth/me-core/data/block"
 	"github.com/ME-MotherEarth/me-core/data/indexer"
 	elasticIndexer "github.com/ME-MotherEarth/me-elastic-indexer"
-	"github.com/ME-MotherEarth/me-elastic-indexer/converters"
 	"github.com/ME-MotherEarth/me-elastic-indexer/data"
 	"github.com/ME-MotherEarth/me-elastic-indexer/process/collections"
+	"github.com/ME-MotherEarth/me-elastic-indexer/process/sink"
 	"github.com/ME-MotherEarth/me-elastic-indexer/process/tags"
 	"github.com/ME-MotherEarth/me-elastic-indexer/process/tokeninfo"
 	logger "github.com/ME-MotherEarth/me-logger"
-	"github.com/elastic/go-elasticsearch/v7/esapi"
 )
 
-const (
-	docsKey  = "docs"
-	errorKey = "error"
-	idKey    = "_id"
-	foundKey = "found"
-)
-
-var (
-	log = logger.GetOrCreate("indexer/process")
-
-	indexes = []string{
-		elasticIndexer.TransactionsIndex, elasticIndexer.BlockIndex, elasticIndexer.MiniblocksIndex, elasticIndexer.RatingIndex, elasticIndexer.RoundsIndex, elasticIndexer.ValidatorsIndex,
-		elasticIndexer.AccountsIndex, elasticIndexer.AccountsHistoryIndex, elasticIndexer.ReceiptsIndex, elasticIndexer.ScResultsIndex, elasticIndexer.AccountsMECTHistoryIndex, elasticIndexer.AccountsMECTIndex,
-		elasticIndexer.EpochInfoIndex, elasticIndexer.SCDeploysIndex, elasticIndexer.TokensIndex, elasticIndexer.TagsIndex, elasticIndexer.LogsIndex, elasticIndexer.DelegatorsIndex, elasticIndexer.OperationsIndex,
-		elasticIndexer.CollectionsIndex,
-	}
-)
-
-type objectsMap = map[string]interface{}
+var log = logger.GetOrCreate("indexer/process")
 
 // ArgElasticProcessor holds all dependencies required by the elasticProcessor in order to create
 // new instances
 type ArgElasticProcessor struct {
-	BulkRequestMaxSize int
-	UseKibana          bool
-	SelfShardID        uint32
-	IndexTemplates     map[string]*bytes.Buffer
-	IndexPolicies      map[string]*bytes.Buffer
-	EnabledIndexes     map[string]struct{}
-	TransactionsProc   DBTransactionsHandler
-	AccountsProc       DBAccountHandler
-	BlockProc          DBBlockHandler
-	MiniblocksProc     DBMiniblocksHandler
-	StatisticsProc     DBStatisticsHandler
-	ValidatorsProc     DBValidatorsHandler
-	DBClient           DatabaseClientHandler
-	LogsAndEventsProc  DBLogsAndEventsHandler
-	OperationsProc     OperationsHandler
+	SelfShardID       uint32
+	EnabledIndexes    map[string]struct{}
+	TransactionsProc  DBTransactionsHandler
+	AccountsProc      DBAccountHandler
+	BlockProc         DBBlockHandler
+	MiniblocksProc    DBMiniblocksHandler
+	StatisticsProc    DBStatisticsHandler
+	ValidatorsProc    DBValidatorsHandler
+	Sink              sink.DocumentsSink
+	LogsAndEventsProc DBLogsAndEventsHandler
+	OperationsProc    OperationsHandler
+	EventsPublisher   EventsPublisher
+	FinalizedDataOnly bool
+	RevertibleIndexes []string
 }
 
 type elasticProcessor struct {
-	bulkRequestMaxSize int
-	selfShardID        uint32
-	enabledIndexes     map[string]struct{}
-	elasticClient      DatabaseClientHandler
-	accountsProc       DBAccountHandler
-	blockProc          DBBlockHandler
-	transactionsProc   DBTransactionsHandler
-	miniblocksProc     DBMiniblocksHandler
-	statisticsProc     DBStatisticsHandler
-	validatorsProc     DBValidatorsHandler
-	logsAndEventsProc  DBLogsAndEventsHandler
-	operationsProc     OperationsHandler
-}
-
-// NewElasticProcessor handles Elasticsearch operations such as initialization, adding, modifying or removing data
+	mutPrepare        sync.Mutex
+	selfShardID       uint32
+	enabledIndexes    map[string]struct{}
+	sink              sink.DocumentsSink
+	accountsProc      DBAccountHandler
+	blockProc         DBBlockHandler
+	transactionsProc  DBTransactionsHandler
+	miniblocksProc    DBMiniblocksHandler
+	statisticsProc    DBStatisticsHandler
+	validatorsProc    DBValidatorsHandler
+	logsAndEventsProc DBLogsAndEventsHandler
+	operationsProc    OperationsHandler
+	eventsPublisher   EventsPublisher
+	finalizedDataOnly bool
+	finalityTracker   *finalityTracker
+	tokenDecimals     *tokenDecimalsCache
+	journaledIndexes  map[string]struct{}
+}
+
+// NewElasticProcessor handles the preparation of the indexed data and its saving in the provided documents sink
 func NewElasticProcessor(arguments *ArgElasticProcessor) (*elasticProcessor, error) {
 	err := checkArguments(arguments)
 	if err != nil {
 		return nil, err
 	}
 
-	ei := &elasticProcessor{
-		elasticClient:      arguments.DBClient,
-		enabledIndexes:     arguments.EnabledIndexes,
-		accountsProc:       arguments.AccountsProc,
-		blockProc:          arguments.BlockProc,
-		miniblocksProc:     arguments.MiniblocksProc,
-		transactionsProc:   arguments.TransactionsProc,
-		selfShardID:        arguments.SelfShardID,
-		statisticsProc:     arguments.StatisticsProc,
-		validatorsProc:     arguments.ValidatorsProc,
-		logsAndEventsProc:  arguments.LogsAndEventsProc,
-		operationsProc:     arguments.OperationsProc,
-		bulkRequestMaxSize: arguments.BulkRequestMaxSize,
-	}
-
-	err = ei.init(arguments.UseKibana, arguments.IndexTemplates, arguments.IndexPolicies)
-	if err != nil {
-		return nil, err
-	}
-
-	return ei, nil
+	return &elasticProcessor{
+		sink:              arguments.Sink,
+		enabledIndexes:    arguments.EnabledIndexes,
+		accountsProc:      arguments.AccountsProc,
+		blockProc:         arguments.BlockProc,
+		miniblocksProc:    arguments.MiniblocksProc,
+		transactionsProc:  arguments.TransactionsProc,
+		selfShardID:       arguments.SelfShardID,
+		statisticsProc:    arguments.StatisticsProc,
+		validatorsProc:    arguments.ValidatorsProc,
+		logsAndEventsProc: arguments.LogsAndEventsProc,
+		operationsProc:    arguments.OperationsProc,
+		eventsPublisher:   arguments.EventsPublisher,
+		finalizedDataOnly: arguments.FinalizedDataOnly,
+		finalityTracker:   newFinalityTracker(),
+		tokenDecimals:     newTokenDecimalsCache(),
+		journaledIndexes:  createJournaledIndexes(arguments.RevertibleIndexes),
+	}, nil
 }
 
-// TODO move all the index create part in a new component
-func (ei *elasticProcessor) init(useKibana bool, indexTemplates, _ map[string]*bytes.Buffer) error {
-	err := ei.createOpenDistroTemplates(indexTemplates)
-	if err != nil {
-		return err
-	}
-
-	if useKibana {
-		// TODO: Re-activate after we think of a solid way to handle forks+rotating indexes
-		// err = ei.createIndexPolicies(indexPolicies)
-		// if err != nil {
-		//	return err
-		// }
-	}
-
-	err = ei.createIndexTemplates(indexTemplates)
-	if err != nil {
-		return err
-	}
-
-	err = ei.createIndexes()
-	if err != nil {
-		return err
+func (ei *elasticProcessor) getExistingObjMap(hashes []string, index string) (map[string]bool, error) {
+	if len(hashes) == 0 {
+		return make(map[string]bool), nil
 	}
 
-	err = ei.createAliases()
+	documents, err := ei.sink.GetDocuments(index, hashes)
 	if err != nil {
-		return err
+		return make(map[string]bool), err
 	}
 
-	return nil
-}
-
-// nolint
-func (ei *elasticProcessor) createIndexPolicies(indexPolicies map[string]*bytes.Buffer) error {
-	indexesPolicies := []string{elasticIndexer.TransactionsPolicy, elasticIndexer.BlockPolicy, elasticIndexer.MiniblocksPolicy, elasticIndexer.RatingPolicy, elasticIndexer.RoundsPolicy, elasticIndexer.ValidatorsPolicy,
-		elasticIndexer.AccountsPolicy, elasticIndexer.AccountsMECTPolicy, elasticIndexer.AccountsHistoryPolicy, elasticIndexer.AccountsMECTHistoryPolicy, elasticIndexer.AccountsMECTIndex, elasticIndexer.ReceiptsPolicy, elasticIndexer.ScResultsPolicy}
-	for _, indexPolicyName := range indexesPolicies {
-		indexPolicy := getTemplateByName(indexPolicyName, indexPolicies)
-		if indexPolicy != nil {
-			err := ei.elasticClient.CheckAndCreatePolicy(indexPolicyName, indexPolicy)
-			if err != nil {
-				return err
-			}
-		}
+	founded := make(map[string]bool, len(documents))
+	for id := range documents {
+		founded[id] = true
 	}
 
-	return nil
+	return founded, nil
 }
 
-func (ei *elasticProcessor) createOpenDistroTemplates(indexTemplates map[string]*bytes.Buffer) error {
-	opendistroTemplate := getTemplateByName(elasticIndexer.OpenDistroIndex, indexTemplates)
-	if opendistroTemplate != nil {
-		err := ei.elasticClient.CheckAndCreateTemplate(elasticIndexer.OpenDistroIndex, opendistroTemplate)
-		if err != nil {
-			return err
-		}
+func (ei *elasticProcessor) getTokensFromDB(tokens []string) (*data.ResponseTokens, error) {
+	documents, err := ei.sink.GetDocuments(elasticIndexer.TokensIndex, tokens)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
-}
-
-func (ei *elasticProcessor) createIndexTemplates(indexTemplates map[string]*bytes.Buffer) error {
-	for _, index := range indexes {
-		indexTemplate := getTemplateByName(index, indexTemplates)
-		if indexTemplate != nil {
-			err := ei.elasticClient.CheckAndCreateTemplate(index, indexTemplate)
-			if err != nil {
-				return fmt.Errorf("index: %s, error: %w", index, err)
-			}
-		}
+	responseTokens := &data.ResponseTokens{
+		Docs: make([]data.ResponseTokenDB, 0, len(documents)),
 	}
-	return nil
-}
-
-func (ei *elasticProcessor) createIndexes() error {
-
-	for _, index := range indexes {
-		indexName := fmt.Sprintf("%s-%s", index, elasticIndexer.IndexSuffix)
-		err := ei.elasticClient.CheckAndCreateIndex(indexName)
-		if err != nil {
-			return fmt.Errorf("index: %s, error: %w", index, err)
+	for id, source := range documents {
+		tokenDB := data.ResponseTokenDB{
+			Found: true,
+			ID:    id,
 		}
-	}
-	return nil
-}
 
-func (ei *elasticProcessor) createAliases() error {
-	for _, index := range indexes {
-		indexName := fmt.Sprintf("%s-%s", index, elasticIndexer.IndexSuffix)
-		err := ei.elasticClient.CheckAndCreateAlias(index, indexName)
+		err = json.Unmarshal(source, &tokenDB.Source)
 		if err != nil {
-			return err
+			return nil, err
 		}
-	}
-
-	return nil
-}
-
-func (ei *elasticProcessor) getExistingObjMap(hashes []string, index string) (map[string]bool, error) {
-	if len(hashes) == 0 {
-		return make(map[string]bool), nil
-	}
-
-	response := make(objectsMap)
-	err := ei.elasticClient.DoMultiGet(hashes, index, false, &response)
-	if err != nil {
-		return make(map[string]bool), err
-	}
-
-	return getDecodedResponseMultiGet(response), nil
-}
-
-func getDecodedResponseMultiGet(response objectsMap) map[string]bool {
-	founded := make(map[string]bool)
-	interfaceSlice, ok := response[docsKey].([]interface{})
-	if !ok {
-		return founded
-	}
 
-	for _, element := range interfaceSlice {
-		obj := element.(objectsMap)
-		_, ok = obj[errorKey]
-		if ok {
-			continue
-		}
-		founded[obj[idKey].(string)] = obj[foundKey].(bool)
+		responseTokens.Docs = append(responseTokens.Docs, tokenDB)
 	}
+	ei.tokenDecimals.addFromResponse(responseTokens)
 
-	return founded
+	return responseTokens, nil
 }
 
-func getTemplateByName(templateName string, templateList map[string]*bytes.Buffer) *bytes.Buffer {
-	if template, ok := templateList[templateName]; ok {
-		return template
+func prepareDeleteDocuments(index string, ids []string) []*data.Document {
+	documents := make([]*data.Document, 0, len(ids))
+	for _, id := range ids {
+		documents = append(documents, &data.Document{
+			Index:  index,
+			ID:     id,
+			Action: data.ActionDelete,
+		})
 	}
 
-	log.Debug("elasticProcessor.getTemplateByName", "could not find template", templateName)
-	return nil
+	return documents
 }
 
 // SaveHeader will prepare and save information about a header in elasticsearch server
@@ -265,27 +164,42 @@ func (ei *elasticProcessor) SaveHeader(
 		return err
 	}
 
-	buffSlice := data.NewBufferSlice(ei.bulkRequestMaxSize)
-	err = ei.blockProc.SerializeBlock(elasticBlock, buffSlice, elasticIndexer.BlockIndex)
+	elasticBlock.Finalized = ei.finalizedDataOnly
+
+	docs := data.NewDocumentsSlice()
+	err = ei.blockProc.SerializeBlock(elasticBlock, docs, elasticIndexer.BlockIndex)
+	if err != nil {
+		return err
+	}
+
+	err = ei.indexEpochInfoData(header, docs)
 	if err != nil {
 		return err
 	}
 
-	err = ei.indexEpochInfoData(header, buffSlice)
+	rejected := &data.RejectedDocumentsError{}
+	err = rejected.Add(ei.sink.WriteDocuments(docs.Documents()))
 	if err != nil {
 		return err
 	}
 
-	return ei.doBulkRequests("", buffSlice.Buffers())
+	if !ei.finalizedDataOnly {
+		ei.finalityTracker.addBlock(headerHash, header.GetNonce())
+	}
+
+	err = ei.eventsPublisher.PublishBlock(headerHash, header, elasticBlock)
+	logPublishError("block", header, err)
+
+	return rejected.AsError()
 }
 
-func (ei *elasticProcessor) indexEpochInfoData(header coreData.HeaderHandler, buffSlice *data.BufferSlice) error {
+func (ei *elasticProcessor) indexEpochInfoData(header coreData.HeaderHandler, docs *data.DocumentsSlice) error {
 	if !ei.isIndexEnabled(elasticIndexer.EpochInfoIndex) ||
 		ei.selfShardID != core.MetachainShardId {
 		return nil
 	}
 
-	return ei.blockProc.SerializeEpochInfoData(header, buffSlice, elasticIndexer.EpochInfoIndex)
+	return ei.blockProc.SerializeEpochInfoData(header, docs, elasticIndexer.EpochInfoIndex)
 }
 
 // RemoveHeader will remove a block from elasticsearch server
@@ -295,10 +209,23 @@ func (ei *elasticProcessor) RemoveHeader(header coreData.HeaderHandler) error {
 		return err
 	}
 
-	return ei.elasticClient.DoQueryRemove(
-		elasticIndexer.BlockIndex,
-		converters.PrepareHashesForQueryRemove([]string{hex.EncodeToString(headerHash)}),
-	)
+	rejected := &data.RejectedDocumentsError{}
+	err = rejected.Add(ei.sink.WriteDocuments(prepareDeleteDocuments(elasticIndexer.BlockIndex, []string{hex.EncodeToString(headerHash)})))
+	if err != nil {
+		return err
+	}
+
+	ei.finalityTracker.removeBlock(headerHash)
+
+	err = rejected.Add(ei.revertCheckpoint(header))
+	if err != nil {
+		return err
+	}
+
+	err = ei.eventsPublisher.PublishRevert(headerHash, header)
+	logPublishError("revert", header, err)
+
+	return rejected.AsError()
 }
 
 // RemoveMiniblocks will remove all miniblocks that are in header from elasticsearch server
@@ -308,55 +235,35 @@ func (ei *elasticProcessor) RemoveMiniblocks(header coreData.HeaderHandler, body
 		return nil
 	}
 
-	return ei.elasticClient.DoQueryRemove(
-		elasticIndexer.MiniblocksIndex,
-		converters.PrepareHashesForQueryRemove(encodedMiniblocksHashes),
-	)
+	return ei.sink.WriteDocuments(prepareDeleteDocuments(elasticIndexer.MiniblocksIndex, encodedMiniblocksHashes))
 }
 
 // RemoveTransactions will remove transaction that are in miniblock from the elasticsearch server
 func (ei *elasticProcessor) RemoveTransactions(header coreData.HeaderHandler, body *block.Body) error {
 	encodedTxsHashes, encodedScrsHashes := ei.transactionsProc.GetHexEncodedHashesForRemove(header, body)
 
-	err := ei.removeIfHashesNotEmpty(elasticIndexer.TransactionsIndex, encodedTxsHashes)
-	if err != nil {
-		return err
-	}
-
-	err = ei.removeIfHashesNotEmpty(elasticIndexer.ScResultsIndex, encodedScrsHashes)
-	if err != nil {
-		return err
-	}
-
-	return ei.removeIfHashesNotEmpty(elasticIndexer.OperationsIndex, append(encodedTxsHashes, encodedScrsHashes...))
-}
-
-func (ei *elasticProcessor) removeIfHashesNotEmpty(index string, hashes []string) error {
-	if len(hashes) == 0 {
+	documents := prepareDeleteDocuments(elasticIndexer.TransactionsIndex, encodedTxsHashes)
+	documents = append(documents, prepareDeleteDocuments(elasticIndexer.ScResultsIndex, encodedScrsHashes)...)
+	documents = append(documents, prepareDeleteDocuments(elasticIndexer.OperationsIndex, append(encodedTxsHashes, encodedScrsHashes...))...)
+	if len(documents) == 0 {
 		return nil
 	}
 
-	return ei.elasticClient.DoQueryRemove(
-		index,
-		converters.PrepareHashesForQueryRemove(hashes),
-	)
+	return ei.sink.WriteDocuments(documents)
 }
 
 // RemoveAccountsMECT will remove data from accountsmect index and accountsmecthistory
 func (ei *elasticProcessor) RemoveAccountsMECT(headerTimestamp uint64) error {
-	query := fmt.Sprintf(`{"query": {"bool": {"must": [{"match": {"shardID": {"query": %d,"operator": "AND"}}},{"match": {"timestamp": {"query": "%d","operator": "AND"}}}]}}}`, ei.selfShardID, headerTimestamp)
-	err := ei.elasticClient.DoQueryRemove(
-		elasticIndexer.AccountsMECTIndex,
-		bytes.NewBuffer([]byte(query)),
-	)
+	fields := map[string]interface{}{
+		"shardID":   ei.selfShardID,
+		"timestamp": headerTimestamp,
+	}
+	err := ei.sink.DeleteMatching(elasticIndexer.AccountsMECTIndex, fields)
 	if err != nil {
 		return err
 	}
 
-	return ei.elasticClient.DoQueryRemove(
-		elasticIndexer.AccountsMECTHistoryIndex,
-		bytes.NewBuffer([]byte(query)),
-	)
+	return ei.sink.DeleteMatching(elasticIndexer.AccountsMECTHistoryIndex, fields)
 }
 
 // SaveMiniblocks will prepare and save information about miniblocks in elasticsearch server
@@ -375,10 +282,10 @@ func (ei *elasticProcessor) SaveMiniblocks(header coreData.HeaderHandler, body *
 		log.Warn("elasticProcessor.SaveMiniblocks cannot get indexed miniblocks", "error", err)
 	}
 
-	buffSlice := data.NewBufferSlice(ei.bulkRequestMaxSize)
-	ei.miniblocksProc.SerializeBulkMiniBlocks(mbs, miniblocksInDBMap, buffSlice, elasticIndexer.MiniblocksIndex)
+	docs := data.NewDocumentsSlice()
+	ei.miniblocksProc.SerializeBulkMiniBlocks(mbs, miniblocksInDBMap, docs, elasticIndexer.MiniblocksIndex)
 
-	return ei.doBulkRequests("", buffSlice.Buffers())
+	return ei.sink.WriteDocuments(docs.Documents())
 }
 
 func (ei *elasticProcessor) miniblocksInDBMap(mbs []*data.Miniblock) (map[string]bool, error) {
@@ -396,159 +303,421 @@ func (ei *elasticProcessor) SaveTransactions(
 	header coreData.HeaderHandler,
 	pool *indexer.Pool,
 ) error {
+	preparedTxs, err := ei.PrepareTransactions(body, header, pool)
+	if err != nil {
+		return err
+	}
+
+	return ei.SavePreparedTransactions(header, pool, preparedTxs)
+}
+
+// PrepareTransactions will process the transactions and the logs of a block and will serialize the documents that
+// do not depend on data already indexed. No request to the database is done, so it can run while a previous block
+// is being saved
+func (ei *elasticProcessor) PrepareTransactions(
+	body *block.Body,
+	header coreData.HeaderHandler,
+	pool *indexer.Pool,
+) (*data.PreparedBlockTransactions, error) {
+	ei.mutPrepare.Lock()
+	defer ei.mutPrepare.Unlock()
+
 	headerTimestamp := header.GetTimeStamp()
 
 	preparedResults := ei.transactionsProc.PrepareTransactionsForDatabase(body, header, pool)
+	ei.setFinalizedFlag(preparedResults)
 	logsData := ei.logsAndEventsProc.ExtractDataFromLogs(pool.Logs, preparedResults, headerTimestamp)
 
-	buffers := data.NewBufferSlice(ei.bulkRequestMaxSize)
-	err := ei.indexTransactions(preparedResults.Transactions, preparedResults.TxHashStatus, header, buffers)
+	docs := data.NewDocumentsSlice()
+	err := ei.indexTransactions(preparedResults.Transactions, preparedResults.TxHashStatus, header, docs)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ei.prepareAndIndexOperations(preparedResults.Transactions, preparedResults.TxHashStatus, header, preparedResults.ScResults, docs)
+	if err != nil {
+		return nil, err
+	}
+
+	logsDB := ei.logsAndEventsProc.PrepareLogsForDB(pool.Logs, headerTimestamp)
+	err = ei.indexLogs(logsDB, docs)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ei.indexEvents(pool.Logs, headerTimestamp, docs)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ei.indexScResults(preparedResults.ScResults, docs)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ei.indexReceipts(preparedResults.Receipts, docs)
+	if err != nil {
+		return nil, err
+	}
+
+	return &data.PreparedBlockTransactions{
+		Results:   preparedResults,
+		LogsData:  logsData,
+		Logs:      logsDB,
+		Documents: docs,
+	}, nil
+}
+
+// SavePreparedTransactions will fetch the data needed from the database, will serialize the rest of the documents of
+// the provided prepared transactions and will save all of them in elasticsearch server
+func (ei *elasticProcessor) SavePreparedTransactions(
+	header coreData.HeaderHandler,
+	pool *indexer.Pool,
+	preparedTxs *data.PreparedBlockTransactions,
+) error {
+	if preparedTxs == nil {
+		return elasticIndexer.ErrNilPreparedTransactions
+	}
+
+	headerTimestamp := header.GetTimeStamp()
+	preparedResults := preparedTxs.Results
+	logsData := preparedTxs.LogsData
+	docs := preparedTxs.Documents
+
+	err := ei.indexTransactionsAndOperationsWithRefund(preparedResults.TxHashRefund, docs)
 	if err != nil {
 		return err
 	}
 
-	err = ei.prepareAndIndexOperations(preparedResults.Transactions, preparedResults.TxHashStatus, header, preparedResults.ScResults, buffers)
+	err = ei.indexNFTCreateInfo(logsData.Tokens, docs)
 	if err != nil {
 		return err
 	}
 
-	err = ei.indexTransactionsAndOperationsWithRefund(preparedResults.TxHashRefund, buffers)
+	// the tokens issued in this block are not in the tokens index yet, but their balances are converted with their decimals
+	ei.tokenDecimals.addFromIssues(logsData.TokensInfo)
+
+	tagsCount := tags.NewTagsCount()
+	err = ei.indexAlteredAccounts(headerTimestamp, preparedResults.AlteredAccts, logsData.NFTsDataUpdates, docs, tagsCount)
 	if err != nil {
 		return err
 	}
 
-	err = ei.indexNFTCreateInfo(logsData.Tokens, buffers)
+	err = ei.prepareAndIndexTagsCount(tagsCount, docs)
 	if err != nil {
 		return err
 	}
 
-	err = ei.prepareAndIndexLogs(pool.Logs, headerTimestamp, buffers)
+	// the documents rejected while the token types are added, or while the journal is written, do not stop the block
+	rejected := &data.RejectedDocumentsError{}
+	err = rejected.Add(ei.indexTokens(logsData.TokensInfo, logsData.NFTsDataUpdates, docs))
 	if err != nil {
 		return err
 	}
 
-	err = ei.indexScResults(preparedResults.ScResults, buffers)
+	err = ei.prepareAndIndexDelegators(logsData.Delegators, docs)
 	if err != nil {
 		return err
 	}
 
-	err = ei.indexReceipts(preparedResults.Receipts, buffers)
+	err = ei.indexNFTBurnInfo(logsData.TokensSupply, docs)
 	if err != nil {
 		return err
 	}
 
-	tagsCount := tags.NewTagsCount()
-	err = ei.indexAlteredAccounts(headerTimestamp, preparedResults.AlteredAccts, logsData.NFTsDataUpdates, buffers, tagsCount)
+	err = ei.prepareAndIndexRolesData(logsData.TokenRolesAndProperties, docs)
 	if err != nil {
 		return err
 	}
 
-	err = ei.prepareAndIndexTagsCount(tagsCount, buffers)
+	err = ei.indexScDeploys(logsData.ScDeploys, docs)
 	if err != nil {
 		return err
 	}
 
-	err = ei.indexTokens(logsData.TokensInfo, logsData.NFTsDataUpdates, buffers)
+	err = ei.indexTransfers(logsData.Transfers, docs)
 	if err != nil {
 		return err
 	}
 
-	err = ei.prepareAndIndexDelegators(logsData.Delegators, buffers)
+	ei.indexCustomDocuments(logsData.CustomDocuments, docs)
+
+	headerHash, err := ei.blockProc.ComputeHeaderHash(header)
 	if err != nil {
 		return err
 	}
 
-	err = ei.indexNFTBurnInfo(logsData.TokensSupply, buffers)
+	err = rejected.Add(ei.journalBlockChanges(headerHash, header, docs.Documents()))
 	if err != nil {
 		return err
 	}
 
-	err = ei.prepareAndIndexRolesData(logsData.TokenRolesAndProperties, buffers)
+	err = rejected.Add(ei.sink.WriteDocuments(docs.Documents()))
 	if err != nil {
 		return err
 	}
 
-	err = ei.indexScDeploys(logsData.ScDeploys, buffers)
+	ei.trackResults(headerHash, header, preparedResults)
+
+	err = ei.eventsPublisher.PublishTransactions(headerHash, header, preparedTxs)
+	logPublishError("transactions", header, err)
+
+	return rejected.AsError()
+}
+
+// logPublishError will only log the error of a publish, as the messages are published after the documents were
+// written. Returning it would make the block to be saved again, so the documents that are not idempotent would be
+// applied twice and the messages already delivered would be published again
+func logPublishError(messages string, header coreData.HeaderHandler, err error) {
+	if err == nil {
+		return
+	}
+
+	log.Error("elasticProcessor: cannot publish messages, they will be missing from the stream",
+		"messages", messages,
+		"shard", header.GetShardID(),
+		"nonce", header.GetNonce(),
+		"error", err.Error())
+}
+
+func (ei *elasticProcessor) setFinalizedFlag(preparedResults *data.PreparedResults) {
+	for _, tx := range preparedResults.Transactions {
+		tx.Finalized = ei.finalizedDataOnly
+	}
+	for _, scr := range preparedResults.ScResults {
+		scr.Finalized = ei.finalizedDataOnly
+	}
+}
+
+func (ei *elasticProcessor) trackResults(headerHash []byte, header coreData.HeaderHandler, preparedResults *data.PreparedResults) {
+	if ei.finalizedDataOnly {
+		return
+	}
+
+	txHashes := make([]string, 0, len(preparedResults.Transactions))
+	for _, tx := range preparedResults.Transactions {
+		txHashes = append(txHashes, tx.Hash)
+	}
+	scrHashes := make([]string, 0, len(preparedResults.ScResults))
+	for _, scr := range preparedResults.ScResults {
+		scrHashes = append(scrHashes, scr.Hash)
+	}
+
+	ei.finalityTracker.addResults(headerHash, header.GetNonce(), txHashes, scrHashes)
+}
+
+// FinalizeBlock will mark as final the block with the provided hash, its transactions and smart contract results,
+// together with the ones of all the blocks with a lower nonce that were indexed before. The journals of the shard are
+// removed up to the nonce of the finalized block
+func (ei *elasticProcessor) FinalizeBlock(headerHash []byte) error {
+	if ei.finalizedDataOnly {
+		return nil
+	}
+
+	rejected := &data.RejectedDocumentsError{}
+	blocks := ei.finalityTracker.blocksUntil(headerHash)
+	if len(blocks) == 0 {
+		log.Debug("elasticProcessor.FinalizeBlock: block is not tracked, nothing to mark as final",
+			"hash", hex.EncodeToString(headerHash))
+	} else {
+		docs := data.NewDocumentsSlice()
+		for _, tb := range blocks {
+			ei.serializeFinalizedBlock(tb, docs)
+		}
+
+		err := rejected.Add(ei.sink.WriteDocuments(docs.Documents()))
+		if err != nil {
+			return err
+		}
+
+		ei.finalityTracker.remove(blocks...)
+	}
+
+	err := ei.removeFinalizedJournals(headerHash, blocks)
 	if err != nil {
 		return err
 	}
 
-	return ei.doBulkRequests("", buffers.Buffers())
+	return rejected.AsError()
 }
 
-func (ei *elasticProcessor) prepareAndIndexRolesData(tokenRolesAndProperties *tokeninfo.TokenRolesAndProperties, buffSlice *data.BufferSlice) error {
+// removeFinalizedJournals will remove the journals of the finalized block and of all the blocks of the shard with a
+// lower nonce, as a final block is never reverted. The journals are removed by nonce, so the ones of the blocks that
+// are not tracked anymore, such as the ones indexed before a restart, are removed as well
+func (ei *elasticProcessor) removeFinalizedJournals(headerHash []byte, finalizedBlocks []*trackedBlock) error {
+	if !ei.isJournalEnabled() {
+		return nil
+	}
+
+	var finalizedNonce uint64
+	if len(finalizedBlocks) > 0 {
+		finalizedNonce = finalizedBlocks[len(finalizedBlocks)-1].nonce
+	} else {
+		blockJournal, err := ei.getBlockJournal(hex.EncodeToString(headerHash))
+		if err != nil {
+			return err
+		}
+		if blockJournal == nil {
+			return nil
+		}
+		finalizedNonce = blockJournal.Nonce
+	}
+
+	return ei.sink.DeleteMatching(elasticIndexer.JournalIndex, map[string]interface{}{
+		"shardId": ei.selfShardID,
+		"nonce":   data.AtMost{Value: finalizedNonce},
+	})
+}
+
+func (ei *elasticProcessor) serializeFinalizedBlock(tb *trackedBlock, docs *data.DocumentsSlice) {
+	if tb.hasBlock && ei.isIndexEnabled(elasticIndexer.BlockIndex) {
+		docs.Add(prepareFinalizedDocuments(elasticIndexer.BlockIndex, []string{tb.hash})...)
+	}
+	if ei.isIndexEnabled(elasticIndexer.TransactionsIndex) {
+		docs.Add(prepareFinalizedDocuments(elasticIndexer.TransactionsIndex, tb.txHashes)...)
+	}
+	if ei.isIndexEnabled(elasticIndexer.ScResultsIndex) {
+		docs.Add(prepareFinalizedDocuments(elasticIndexer.ScResultsIndex, tb.scrHashes)...)
+	}
+	if ei.isIndexEnabled(elasticIndexer.OperationsIndex) {
+		docs.Add(prepareFinalizedDocuments(elasticIndexer.OperationsIndex, tb.txHashes)...)
+		docs.Add(prepareFinalizedDocuments(elasticIndexer.OperationsIndex, tb.scrHashes)...)
+	}
+}
+
+func prepareFinalizedDocuments(index string, ids []string) []*data.Document {
+	documents := make([]*data.Document, 0, len(ids))
+	for _, id := range ids {
+		documents = append(documents, &data.Document{
+			Index:  index,
+			ID:     id,
+			Action: data.ActionUpdate,
+			Fields: map[string]interface{}{
+				"finalized": true,
+			},
+		})
+	}
+
+	return documents
+}
+
+func (ei *elasticProcessor) prepareAndIndexRolesData(tokenRolesAndProperties *tokeninfo.TokenRolesAndProperties, docs *data.DocumentsSlice) error {
 	if !ei.isIndexEnabled(elasticIndexer.TokensIndex) {
 		return nil
 	}
 
-	return ei.logsAndEventsProc.SerializeRolesData(tokenRolesAndProperties, buffSlice, elasticIndexer.TokensIndex)
+	return ei.logsAndEventsProc.SerializeRolesData(tokenRolesAndProperties, docs, elasticIndexer.TokensIndex)
 }
 
-func (ei *elasticProcessor) prepareAndIndexDelegators(delegators map[string]*data.Delegator, buffSlice *data.BufferSlice) error {
+func (ei *elasticProcessor) prepareAndIndexDelegators(delegators map[string]*data.Delegator, docs *data.DocumentsSlice) error {
 	if !ei.isIndexEnabled(elasticIndexer.DelegatorsIndex) {
 		return nil
 	}
 
-	return ei.logsAndEventsProc.SerializeDelegators(delegators, buffSlice, elasticIndexer.DelegatorsIndex)
+	return ei.logsAndEventsProc.SerializeDelegators(delegators, docs, elasticIndexer.DelegatorsIndex)
 }
 
-func (ei *elasticProcessor) indexTransactionsAndOperationsWithRefund(txsHashRefund map[string]*data.RefundData, buffSlice *data.BufferSlice) error {
+func (ei *elasticProcessor) indexTransactionsAndOperationsWithRefund(txsHashRefund map[string]*data.RefundData, docs *data.DocumentsSlice) error {
 	if len(txsHashRefund) == 0 {
 		return nil
 	}
-	txsHashes := make([]string, len(txsHashRefund))
+	txsHashes := make([]string, 0, len(txsHashRefund))
 	for txHash := range txsHashRefund {
 		txsHashes = append(txsHashes, txHash)
 	}
 
-	responseTransactions := &data.ResponseTransactions{}
-	err := ei.elasticClient.DoMultiGet(txsHashes, elasticIndexer.TransactionsIndex, true, responseTransactions)
+	documents, err := ei.sink.GetDocuments(elasticIndexer.TransactionsIndex, txsHashes)
 	if err != nil {
 		return err
 	}
 
 	txsFromDB := make(map[string]*data.Transaction)
-	for idx := 0; idx < len(responseTransactions.Docs); idx++ {
-		txRes := responseTransactions.Docs[idx]
-		if !txRes.Found {
-			continue
+	for txHash, source := range documents {
+		tx := &data.Transaction{}
+		err = json.Unmarshal(source, tx)
+		if err != nil {
+			return err
 		}
 
-		txsFromDB[txRes.ID] = &txRes.Source
+		txsFromDB[txHash] = tx
 	}
 
-	err = ei.transactionsProc.SerializeTransactionWithRefund(txsFromDB, txsHashRefund, buffSlice, elasticIndexer.TransactionsIndex)
+	err = ei.transactionsProc.SerializeTransactionWithRefund(txsFromDB, txsHashRefund, docs, elasticIndexer.TransactionsIndex)
 	if err != nil {
 		return err
 	}
 
-	return ei.transactionsProc.SerializeTransactionWithRefund(txsFromDB, txsHashRefund, buffSlice, elasticIndexer.OperationsIndex)
+	return ei.transactionsProc.SerializeTransactionWithRefund(txsFromDB, txsHashRefund, docs, elasticIndexer.OperationsIndex)
 }
 
-func (ei *elasticProcessor) prepareAndIndexLogs(logsAndEvents []*coreData.LogData, timestamp uint64, buffSlice *data.BufferSlice) error {
+func (ei *elasticProcessor) indexLogs(logsDB []*data.Logs, docs *data.DocumentsSlice) error {
 	if !ei.isIndexEnabled(elasticIndexer.LogsIndex) {
 		return nil
 	}
 
-	logsDB := ei.logsAndEventsProc.PrepareLogsForDB(logsAndEvents, timestamp)
+	return ei.logsAndEventsProc.SerializeLogs(logsDB, docs, elasticIndexer.LogsIndex)
+}
+
+func (ei *elasticProcessor) indexEvents(logsAndEvents []*coreData.LogData, timestamp uint64, docs *data.DocumentsSlice) error {
+	if !ei.isIndexEnabled(elasticIndexer.EventsIndex) {
+		return nil
+	}
 
-	return ei.logsAndEventsProc.SerializeLogs(logsDB, buffSlice, elasticIndexer.LogsIndex)
+	eventsDB := ei.logsAndEventsProc.PrepareEventsForDB(logsAndEvents, timestamp)
+	return ei.logsAndEventsProc.SerializeEvents(eventsDB, docs, elasticIndexer.EventsIndex)
 }
 
-func (ei *elasticProcessor) indexScDeploys(deployData map[string]*data.ScDeployInfo, buffSlice *data.BufferSlice) error {
+// indexTransfers will serialize the provided transfers with their amount converted with the decimals of their token.
+// The decimals of the tokens that are not in the cache are taken from the tokens index
+func (ei *elasticProcessor) indexTransfers(transfers []*data.Transfer, docs *data.DocumentsSlice) error {
+	shouldSkipIndex := !ei.isIndexEnabled(elasticIndexer.TransfersIndex) || len(transfers) == 0
+	if shouldSkipIndex {
+		return nil
+	}
+
+	tokens := make([]string, 0, len(transfers))
+	for _, transfer := range transfers {
+		tokens = append(tokens, transfer.Token)
+	}
+
+	missingTokens := ei.tokenDecimals.getMissing(tokens)
+	if ei.isIndexEnabled(elasticIndexer.TokensIndex) && len(missingTokens) > 0 {
+		_, err := ei.getTokensFromDB(missingTokens)
+		if err != nil {
+			return err
+		}
+	}
+
+	ei.logsAndEventsProc.PutAmountNumInTransfers(transfers, ei.tokenDecimals.getForTokens(tokens))
+	return ei.logsAndEventsProc.SerializeTransfers(transfers, docs, elasticIndexer.TransfersIndex)
+}
+
+func (ei *elasticProcessor) indexScDeploys(deployData map[string]*data.ScDeployInfo, docs *data.DocumentsSlice) error {
 	if !ei.isIndexEnabled(elasticIndexer.SCDeploysIndex) {
 		return nil
 	}
 
-	return ei.logsAndEventsProc.SerializeSCDeploys(deployData, buffSlice, elasticIndexer.SCDeploysIndex)
+	return ei.logsAndEventsProc.SerializeSCDeploys(deployData, docs, elasticIndexer.SCDeploysIndex)
 }
 
-func (ei *elasticProcessor) indexTransactions(txs []*data.Transaction, txHashStatus map[string]string, header coreData.HeaderHandler, bytesBuff *data.BufferSlice) error {
+// indexCustomDocuments will add the documents emitted by the event processors added by integrators, for the enabled
+// indices
+func (ei *elasticProcessor) indexCustomDocuments(customDocuments []*data.Document, docs *data.DocumentsSlice) {
+	for _, document := range customDocuments {
+		if !ei.isIndexEnabled(document.Index) {
+			continue
+		}
+
+		docs.Add(document)
+	}
+}
+
+func (ei *elasticProcessor) indexTransactions(txs []*data.Transaction, txHashStatus map[string]string, header coreData.HeaderHandler, docs *data.DocumentsSlice) error {
 	if !ei.isIndexEnabled(elasticIndexer.TransactionsIndex) {
 		return nil
 	}
 
-	return ei.transactionsProc.SerializeTransactions(txs, txHashStatus, header.GetShardID(), bytesBuff, elasticIndexer.TransactionsIndex)
+	return ei.transactionsProc.SerializeTransactions(txs, txHashStatus, header.GetShardID(), docs, elasticIndexer.TransactionsIndex)
 }
 
 func (ei *elasticProcessor) prepareAndIndexOperations(
@@ -556,7 +725,7 @@ func (ei *elasticProcessor) prepareAndIndexOperations(
 	txHashStatus map[string]string,
 	header coreData.HeaderHandler,
 	scrs []*data.ScResult,
-	buffSlice *data.BufferSlice,
+	docs *data.DocumentsSlice,
 ) error {
 	if !ei.isIndexEnabled(elasticIndexer.OperationsIndex) {
 		return nil
@@ -564,12 +733,12 @@ func (ei *elasticProcessor) prepareAndIndexOperations(
 
 	processedTxs, processedSCRs := ei.operationsProc.ProcessTransactionsAndSCRs(txs, scrs)
 
-	err := ei.transactionsProc.SerializeTransactions(processedTxs, txHashStatus, header.GetShardID(), buffSlice, elasticIndexer.OperationsIndex)
+	err := ei.transactionsProc.SerializeTransactions(processedTxs, txHashStatus, header.GetShardID(), docs, elasticIndexer.OperationsIndex)
 	if err != nil {
 		return err
 	}
 
-	return ei.operationsProc.SerializeSCRs(processedSCRs, buffSlice, elasticIndexer.OperationsIndex)
+	return ei.operationsProc.SerializeSCRs(processedSCRs, docs, elasticIndexer.OperationsIndex)
 }
 
 // SaveValidatorsRating will save validators rating
@@ -578,12 +747,10 @@ func (ei *elasticProcessor) SaveValidatorsRating(index string, validatorsRatingI
 		return nil
 	}
 
-	buffSlice, err := ei.validatorsProc.SerializeValidatorsRating(index, validatorsRatingInfo)
-	if err != nil {
-		return err
-	}
+	docs := data.NewDocumentsSlice()
+	ei.validatorsProc.SerializeValidatorsRating(index, validatorsRatingInfo, docs, elasticIndexer.RatingIndex)
 
-	return ei.doBulkRequests(elasticIndexer.RatingIndex, buffSlice)
+	return ei.sink.WriteDocuments(docs.Documents())
 }
 
 // SaveShardValidatorsPubKeys will prepare and save information about a shard validators public keys in elasticsearch server
@@ -593,18 +760,10 @@ func (ei *elasticProcessor) SaveShardValidatorsPubKeys(shardID, epoch uint32, sh
 	}
 
 	validatorsPubKeys := ei.validatorsProc.PrepareValidatorsPublicKeys(shardValidatorsPubKeys)
-	buff, err := ei.validatorsProc.SerializeValidatorsPubKeys(validatorsPubKeys)
-	if err != nil {
-		return err
-	}
-
-	req := &esapi.IndexRequest{
-		Index:      elasticIndexer.ValidatorsIndex,
-		DocumentID: fmt.Sprintf("%d_%d", shardID, epoch),
-		Body:       bytes.NewReader(buff.Bytes()),
-	}
+	docs := data.NewDocumentsSlice()
+	ei.validatorsProc.SerializeValidatorsPubKeys(shardID, epoch, validatorsPubKeys, docs, elasticIndexer.ValidatorsIndex)
 
-	return ei.elasticClient.DoRequest(req)
+	return ei.sink.WriteDocuments(docs.Documents())
 }
 
 // SaveRoundsInfo will prepare and save information about a slice of rounds in elasticsearch server
@@ -613,33 +772,34 @@ func (ei *elasticProcessor) SaveRoundsInfo(info []*data.RoundInfo) error {
 		return nil
 	}
 
-	buff := ei.statisticsProc.SerializeRoundsInfo(info)
+	docs := data.NewDocumentsSlice()
+	ei.statisticsProc.SerializeRoundsInfo(info, docs, elasticIndexer.RoundsIndex)
 
-	return ei.elasticClient.DoBulkRequest(buff, elasticIndexer.RoundsIndex)
+	return ei.sink.WriteDocuments(docs.Documents())
 }
 
 func (ei *elasticProcessor) indexAlteredAccounts(
 	timestamp uint64,
 	alteredAccounts data.AlteredAccountsHandler,
 	updatesNFTsData []*data.NFTDataUpdate,
-	buffSlice *data.BufferSlice,
+	docs *data.DocumentsSlice,
 	tagsCount data.CountTags,
 ) error {
 	regularAccountsToIndex, accountsToIndexMECT := ei.accountsProc.GetAccounts(alteredAccounts)
 
-	err := ei.saveAccounts(timestamp, regularAccountsToIndex, buffSlice)
+	err := ei.saveAccounts(timestamp, regularAccountsToIndex, docs)
 	if err != nil {
 		return err
 	}
 
-	return ei.saveAccountsMECT(timestamp, accountsToIndexMECT, updatesNFTsData, buffSlice, tagsCount)
+	return ei.saveAccountsMECT(timestamp, accountsToIndexMECT, updatesNFTsData, docs, tagsCount)
 }
 
 func (ei *elasticProcessor) saveAccountsMECT(
 	timestamp uint64,
 	wrappedAccounts []*data.AccountMECT,
 	updatesNFTsData []*data.NFTDataUpdate,
-	buffSlice *data.BufferSlice,
+	docs *data.DocumentsSlice,
 	tagsCount data.CountTags,
 ) error {
 	accountsMECTMap, tokensData := ei.accountsProc.PrepareAccountsMapMECT(timestamp, wrappedAccounts, tagsCount)
@@ -647,18 +807,19 @@ func (ei *elasticProcessor) saveAccountsMECT(
 	if err != nil {
 		return err
 	}
+	ei.accountsProc.PutBalanceNumWithDecimalsInAccountsMECT(accountsMECTMap, ei.tokenDecimals.getForAccountsMECT(accountsMECTMap))
 
-	err = collections.ExtractAndSerializeCollectionsData(accountsMECTMap, buffSlice, elasticIndexer.CollectionsIndex)
+	err = collections.ExtractAndSerializeCollectionsData(accountsMECTMap, docs, elasticIndexer.CollectionsIndex)
 	if err != nil {
 		return err
 	}
 
-	err = ei.indexAccountsMECT(accountsMECTMap, updatesNFTsData, buffSlice)
+	err = ei.indexAccountsMECT(accountsMECTMap, updatesNFTsData, docs)
 	if err != nil {
 		return err
 	}
 
-	return ei.saveAccountsMECTHistory(timestamp, accountsMECTMap, buffSlice)
+	return ei.saveAccountsMECTHistory(timestamp, accountsMECTMap, docs)
 }
 
 func (ei *elasticProcessor) addTokenTypeAndCurrentOwnerInAccountsMECT(tokensData data.TokensHandler, accountsMECTMap map[string]*data.AccountInfo) error {
@@ -666,8 +827,7 @@ func (ei *elasticProcessor) addTokenTypeAndCurrentOwnerInAccountsMECT(tokensData
 		return nil
 	}
 
-	responseTokens := &data.ResponseTokens{}
-	err := ei.elasticClient.DoMultiGet(tokensData.GetAllTokens(), elasticIndexer.TokensIndex, true, responseTokens)
+	responseTokens, err := ei.getTokensFromDB(tokensData.GetAllTokens())
 	if err != nil {
 		return err
 	}
@@ -678,35 +838,34 @@ func (ei *elasticProcessor) addTokenTypeAndCurrentOwnerInAccountsMECT(tokensData
 	return nil
 }
 
-func (ei *elasticProcessor) prepareAndIndexTagsCount(tagsCount data.CountTags, buffSlice *data.BufferSlice) error {
+func (ei *elasticProcessor) prepareAndIndexTagsCount(tagsCount data.CountTags, docs *data.DocumentsSlice) error {
 	shouldSkipIndex := !ei.isIndexEnabled(elasticIndexer.TagsIndex) || tagsCount.Len() == 0
 	if shouldSkipIndex {
 		return nil
 	}
 
-	return tagsCount.Serialize(buffSlice, elasticIndexer.TagsIndex)
+	return tagsCount.Serialize(docs, elasticIndexer.TagsIndex)
 }
 
 func (ei *elasticProcessor) indexAccountsMECT(
 	accountsMECTMap map[string]*data.AccountInfo,
 	updatesNFTsData []*data.NFTDataUpdate,
-	buffSlice *data.BufferSlice,
+	docs *data.DocumentsSlice,
 ) error {
 	if !ei.isIndexEnabled(elasticIndexer.AccountsMECTIndex) {
 		return nil
 	}
 
-	return ei.accountsProc.SerializeAccountsMECT(accountsMECTMap, updatesNFTsData, buffSlice, elasticIndexer.AccountsMECTIndex)
+	return ei.accountsProc.SerializeAccountsMECT(accountsMECTMap, updatesNFTsData, docs, elasticIndexer.AccountsMECTIndex)
 }
 
-func (ei *elasticProcessor) indexNFTCreateInfo(tokensData data.TokensHandler, buffSlice *data.BufferSlice) error {
+func (ei *elasticProcessor) indexNFTCreateInfo(tokensData data.TokensHandler, docs *data.DocumentsSlice) error {
 	shouldSkipIndex := !ei.isIndexEnabled(elasticIndexer.TokensIndex) || tokensData.Len() == 0
 	if shouldSkipIndex {
 		return nil
 	}
 
-	responseTokens := &data.ResponseTokens{}
-	err := ei.elasticClient.DoMultiGet(tokensData.GetAllTokens(), elasticIndexer.TokensIndex, true, responseTokens)
+	responseTokens, err := ei.getTokensFromDB(tokensData.GetAllTokens())
 	if err != nil {
 		return err
 	}
@@ -716,92 +875,96 @@ func (ei *elasticProcessor) indexNFTCreateInfo(tokensData data.TokensHandler, bu
 	tokens := tokensData.GetAllWithoutMetaMECT()
 	ei.accountsProc.PutTokenMedataDataInTokens(tokens)
 
-	return ei.accountsProc.SerializeNFTCreateInfo(tokens, buffSlice, elasticIndexer.TokensIndex)
+	return ei.accountsProc.SerializeNFTCreateInfo(tokens, docs, elasticIndexer.TokensIndex)
 }
 
-func (ei *elasticProcessor) indexNFTBurnInfo(tokensData data.TokensHandler, buffSlice *data.BufferSlice) error {
+func (ei *elasticProcessor) indexNFTBurnInfo(tokensData data.TokensHandler, docs *data.DocumentsSlice) error {
 	shouldSkipIndex := !ei.isIndexEnabled(elasticIndexer.TokensIndex) || tokensData.Len() == 0
 	if shouldSkipIndex {
 		return nil
 	}
 
-	responseTokens := &data.ResponseTokens{}
-	err := ei.elasticClient.DoMultiGet(tokensData.GetAllTokens(), elasticIndexer.TokensIndex, true, responseTokens)
+	responseTokens, err := ei.getTokensFromDB(tokensData.GetAllTokens())
 	if err != nil {
 		return err
 	}
 
 	// TODO implement to keep in tokens also the supply
 	tokensData.AddTypeAndOwnerFromResponse(responseTokens)
-	return ei.logsAndEventsProc.SerializeSupplyData(tokensData, buffSlice, elasticIndexer.TokensIndex)
+	return ei.logsAndEventsProc.SerializeSupplyData(tokensData, docs, elasticIndexer.TokensIndex)
 }
 
 // SaveAccounts will prepare and save information about provided accounts in elasticsearch server
 func (ei *elasticProcessor) SaveAccounts(timestamp uint64, accts []*data.Account) error {
-	buffSlice := data.NewBufferSlice(ei.bulkRequestMaxSize)
-	return ei.saveAccounts(timestamp, accts, buffSlice)
+	docs := data.NewDocumentsSlice()
+	err := ei.saveAccounts(timestamp, accts, docs)
+	if err != nil {
+		return err
+	}
+
+	return ei.sink.WriteDocuments(docs.Documents())
 }
 
-func (ei *elasticProcessor) saveAccounts(timestamp uint64, accts []*data.Account, buffSlice *data.BufferSlice) error {
+func (ei *elasticProcessor) saveAccounts(timestamp uint64, accts []*data.Account, docs *data.DocumentsSlice) error {
 	accountsMap := ei.accountsProc.PrepareRegularAccountsMap(timestamp, accts)
-	err := ei.indexAccounts(accountsMap, elasticIndexer.AccountsIndex, buffSlice)
+	err := ei.indexAccounts(accountsMap, elasticIndexer.AccountsIndex, docs)
 	if err != nil {
 		return err
 	}
 
-	return ei.saveAccountsHistory(timestamp, accountsMap, buffSlice)
+	return ei.saveAccountsHistory(timestamp, accountsMap, docs)
 }
 
-func (ei *elasticProcessor) indexAccounts(accountsMap map[string]*data.AccountInfo, index string, buffSlice *data.BufferSlice) error {
+func (ei *elasticProcessor) indexAccounts(accountsMap map[string]*data.AccountInfo, index string, docs *data.DocumentsSlice) error {
 	if !ei.isIndexEnabled(index) {
 		return nil
 	}
 
-	return ei.serializeAndIndexAccounts(accountsMap, index, buffSlice)
+	return ei.serializeAndIndexAccounts(accountsMap, index, docs)
 }
 
-func (ei *elasticProcessor) serializeAndIndexAccounts(accountsMap map[string]*data.AccountInfo, index string, buffSlice *data.BufferSlice) error {
-	return ei.accountsProc.SerializeAccounts(accountsMap, buffSlice, index)
+func (ei *elasticProcessor) serializeAndIndexAccounts(accountsMap map[string]*data.AccountInfo, index string, docs *data.DocumentsSlice) error {
+	return ei.accountsProc.SerializeAccounts(accountsMap, docs, index)
 }
 
-func (ei *elasticProcessor) saveAccountsMECTHistory(timestamp uint64, accountsInfoMap map[string]*data.AccountInfo, buffSlice *data.BufferSlice) error {
+func (ei *elasticProcessor) saveAccountsMECTHistory(timestamp uint64, accountsInfoMap map[string]*data.AccountInfo, docs *data.DocumentsSlice) error {
 	if !ei.isIndexEnabled(elasticIndexer.AccountsMECTHistoryIndex) {
 		return nil
 	}
 
 	accountsMap := ei.accountsProc.PrepareAccountsHistory(timestamp, accountsInfoMap)
 
-	return ei.serializeAndIndexAccountsHistory(accountsMap, elasticIndexer.AccountsMECTHistoryIndex, buffSlice)
+	return ei.serializeAndIndexAccountsHistory(accountsMap, elasticIndexer.AccountsMECTHistoryIndex, docs)
 }
 
-func (ei *elasticProcessor) saveAccountsHistory(timestamp uint64, accountsInfoMap map[string]*data.AccountInfo, buffSlice *data.BufferSlice) error {
+func (ei *elasticProcessor) saveAccountsHistory(timestamp uint64, accountsInfoMap map[string]*data.AccountInfo, docs *data.DocumentsSlice) error {
 	if !ei.isIndexEnabled(elasticIndexer.AccountsHistoryIndex) {
 		return nil
 	}
 
 	accountsMap := ei.accountsProc.PrepareAccountsHistory(timestamp, accountsInfoMap)
 
-	return ei.serializeAndIndexAccountsHistory(accountsMap, elasticIndexer.AccountsHistoryIndex, buffSlice)
+	return ei.serializeAndIndexAccountsHistory(accountsMap, elasticIndexer.AccountsHistoryIndex, docs)
 }
 
-func (ei *elasticProcessor) serializeAndIndexAccountsHistory(accountsMap map[string]*data.AccountBalanceHistory, index string, buffSlice *data.BufferSlice) error {
-	return ei.accountsProc.SerializeAccountsHistory(accountsMap, buffSlice, index)
+func (ei *elasticProcessor) serializeAndIndexAccountsHistory(accountsMap map[string]*data.AccountBalanceHistory, index string, docs *data.DocumentsSlice) error {
+	return ei.accountsProc.SerializeAccountsHistory(accountsMap, docs, index)
 }
 
-func (ei *elasticProcessor) indexScResults(scrs []*data.ScResult, buffSlice *data.BufferSlice) error {
+func (ei *elasticProcessor) indexScResults(scrs []*data.ScResult, docs *data.DocumentsSlice) error {
 	if !ei.isIndexEnabled(elasticIndexer.ScResultsIndex) {
 		return nil
 	}
 
-	return ei.transactionsProc.SerializeScResults(scrs, buffSlice, elasticIndexer.ScResultsIndex)
+	return ei.transactionsProc.SerializeScResults(scrs, docs, elasticIndexer.ScResultsIndex)
 }
 
-func (ei *elasticProcessor) indexReceipts(receipts []*data.Receipt, buffSlice *data.BufferSlice) error {
+func (ei *elasticProcessor) indexReceipts(receipts []*data.Receipt, docs *data.DocumentsSlice) error {
 	if !ei.isIndexEnabled(elasticIndexer.ReceiptsIndex) {
 		return nil
 	}
 
-	return ei.transactionsProc.SerializeReceipts(receipts, buffSlice, elasticIndexer.ReceiptsIndex)
+	return ei.transactionsProc.SerializeReceipts(receipts, docs, elasticIndexer.ReceiptsIndex)
 }
 
 func (ei *elasticProcessor) isIndexEnabled(index string) bool {
@@ -809,18 +972,6 @@ func (ei *elasticProcessor) isIndexEnabled(index string) bool {
 	return isEnabled
 }
 
-func (ei *elasticProcessor) doBulkRequests(index string, buffSlice []*bytes.Buffer) error {
-	var err error
-	for idx := range buffSlice {
-		err = ei.elasticClient.DoBulkRequest(buffSlice[idx], index)
-		if err != nil {
-			return err
-		}
-	}
-
-	return nil
-}
-
 // IsInterfaceNil returns true if there is no value under the interface
 func (ei *elasticProcessor) IsInterfaceNil() bool {
 	return ei == nil
@@ -1,15 +1,10 @@
 package process
 
 import (
-	"bytes"
 	"encoding/hex"
-	"encoding/json"
 	"errors"
 	"fmt"
-	"io/ioutil"
 	"math/big"
-	"strconv"
-	"strings"
 	"testing"
 
 	"github.com/ME-MotherEarth/me-core/core"
@@ -26,17 +21,17 @@ import (
 	"github.com/ME-MotherEarth/me-elastic-indexer/process/logsevents"
 	"github.com/ME-MotherEarth/me-elastic-indexer/process/miniblocks"
 	"github.com/ME-MotherEarth/me-elastic-indexer/process/operations"
+	"github.com/ME-MotherEarth/me-elastic-indexer/process/sink"
 	"github.com/ME-MotherEarth/me-elastic-indexer/process/statistics"
 	"github.com/ME-MotherEarth/me-elastic-indexer/process/tags"
 	"github.com/ME-MotherEarth/me-elastic-indexer/process/transactions"
 	"github.com/ME-MotherEarth/me-elastic-indexer/process/validators"
-	"github.com/elastic/go-elasticsearch/v7/esapi"
 	"github.com/stretchr/testify/require"
 )
 
-func newElasticsearchProcessor(elasticsearchWriter DatabaseClientHandler, arguments *ArgElasticProcessor) *elasticProcessor {
+func newElasticsearchProcessor(documentsSink sink.DocumentsSink, arguments *ArgElasticProcessor) *elasticProcessor {
 	return &elasticProcessor{
-		elasticClient:     elasticsearchWriter,
+		sink:              documentsSink,
 		enabledIndexes:    arguments.EnabledIndexes,
 		blockProc:         arguments.BlockProc,
 		transactionsProc:  arguments.TransactionsProc,
@@ -45,6 +40,11 @@ func newElasticsearchProcessor(elasticsearchWriter DatabaseClientHandler, argume
 		validatorsProc:    arguments.ValidatorsProc,
 		statisticsProc:    arguments.StatisticsProc,
 		logsAndEventsProc: arguments.LogsAndEventsProc,
+		eventsPublisher:   arguments.EventsPublisher,
+		finalizedDataOnly: arguments.FinalizedDataOnly,
+		finalityTracker:   newFinalityTracker(),
+		tokenDecimals:     newTokenDecimalsCache(),
+		journaledIndexes:  createJournaledIndexes(arguments.RevertibleIndexes),
 	}
 }
 
@@ -54,7 +54,7 @@ func createMockElasticProcessorArgs() *ArgElasticProcessor {
 	acp, _ := accounts.NewAccountsProcessor(&mock.MarshalizerMock{}, &mock.PubkeyConverterMock{}, &mock.AccountsStub{}, balanceConverter, 0)
 	bp, _ := block.NewBlockProcessor(&mock.HasherMock{}, &mock.MarshalizerMock{})
 	mp, _ := miniblocks.NewMiniblocksProcessor(0, &mock.HasherMock{}, &mock.MarshalizerMock{}, false)
-	vp, _ := validators.NewValidatorsProcessor(mock.NewPubkeyConverterMock(32), 0)
+	vp, _ := validators.NewValidatorsProcessor(mock.NewPubkeyConverterMock(32))
 	args := &logsevents.ArgsLogsAndEventsProcessor{
 		ShardCoordinator: &mock.ShardCoordinatorMock{},
 		PubKeyConverter:  &mock.PubkeyConverterMock{},
@@ -62,12 +62,13 @@ func createMockElasticProcessorArgs() *ArgElasticProcessor {
 		BalanceConverter: balanceConverter,
 		Hasher:           &mock.HasherMock{},
 		TxFeeCalculator:  &mock.EconomicsHandlerStub{},
+		ABIDecoder:       &mock.ABIDecoderStub{},
 	}
 	lp, _ := logsevents.NewLogsAndEventsProcessor(args)
 	op, _ := operations.NewOperationsProcessor(false, &mock.ShardCoordinatorMock{})
 
 	return &ArgElasticProcessor{
-		DBClient: &mock.DatabaseWriterStub{},
+		Sink: &mock.DocumentsSinkStub{},
 		EnabledIndexes: map[string]struct{}{
 			elasticIndexer.BlockIndex: {}, elasticIndexer.TransactionsIndex: {}, elasticIndexer.MiniblocksIndex: {}, elasticIndexer.ValidatorsIndex: {}, elasticIndexer.RoundsIndex: {}, elasticIndexer.AccountsIndex: {}, elasticIndexer.RatingIndex: {}, elasticIndexer.AccountsHistoryIndex: {},
 		},
@@ -79,6 +80,7 @@ func createMockElasticProcessorArgs() *ArgElasticProcessor {
 		BlockProc:         bp,
 		LogsAndEventsProc: lp,
 		OperationsProc:    op,
+		EventsPublisher:   &mock.EventsPublisherStub{},
 	}
 }
 
@@ -144,7 +146,6 @@ func newTestBlockBody() *dataBlock.Body {
 func TestNewElasticProcessor(t *testing.T) {
 	t.Parallel()
 
-	expectedErr := errors.New("local error")
 	tests := []struct {
 		name  string
 		args  func() *ArgElasticProcessor
@@ -167,13 +168,13 @@ func TestNewElasticProcessor(t *testing.T) {
 			exErr: elasticIndexer.ErrNilEnabledIndexesMap,
 		},
 		{
-			name: "NilDatabaseClient",
+			name: "NilDocumentsSink",
 			args: func() *ArgElasticProcessor {
 				arguments := createMockElasticProcessorArgs()
-				arguments.DBClient = nil
+				arguments.Sink = nil
 				return arguments
 			},
-			exErr: elasticIndexer.ErrNilDatabaseClient,
+			exErr: sink.ErrNilDocumentsSink,
 		},
 		{
 			name: "NilStatisticProc",
@@ -230,17 +231,20 @@ func TestNewElasticProcessor(t *testing.T) {
 			exErr: elasticIndexer.ErrNilTransactionsHandler,
 		},
 		{
-			name: "InitError",
+			name: "NilEventsPublisher",
 			args: func() *ArgElasticProcessor {
 				arguments := createMockElasticProcessorArgs()
-				arguments.DBClient = &mock.DatabaseWriterStub{
-					CheckAndCreateIndexCalled: func(index string) error {
-						return expectedErr
-					},
-				}
+				arguments.EventsPublisher = nil
 				return arguments
 			},
-			exErr: expectedErr,
+			exErr: elasticIndexer.ErrNilEventsPublisher,
+		},
+		{
+			name: "ShouldWork",
+			args: func() *ArgElasticProcessor {
+				return createMockElasticProcessorArgs()
+			},
+			exErr: nil,
 		},
 	}
 
@@ -252,23 +256,13 @@ func TestNewElasticProcessor(t *testing.T) {
 	}
 }
 
-func TestNewElasticProcessorWithKibana(t *testing.T) {
-	args := createMockElasticProcessorArgs()
-	args.UseKibana = true
-	args.DBClient = &mock.DatabaseWriterStub{}
-
-	elasticProc, err := NewElasticProcessor(args)
-	require.NoError(t, err)
-	require.NotNil(t, elasticProc)
-}
-
 func TestElasticProcessor_RemoveHeader(t *testing.T) {
-	called := false
+	var writtenDocuments []*data.Document
 
 	args := createMockElasticProcessorArgs()
-	args.DBClient = &mock.DatabaseWriterStub{
-		DoQueryRemoveCalled: func(index string, body *bytes.Buffer) error {
-			called = true
+	args.Sink = &mock.DocumentsSinkStub{
+		WriteDocumentsCalled: func(documents []*data.Document) error {
+			writtenDocuments = documents
 			return nil
 		},
 	}
@@ -278,13 +272,19 @@ func TestElasticProcessor_RemoveHeader(t *testing.T) {
 	elasticProc, err := NewElasticProcessor(args)
 	require.NoError(t, err)
 
-	err = elasticProc.RemoveHeader(&dataBlock.Header{})
+	header := &dataBlock.Header{}
+	err = elasticProc.RemoveHeader(header)
 	require.Nil(t, err)
-	require.True(t, called)
+
+	headerHash, _ := core.CalculateHash(&mock.MarshalizerMock{}, &mock.HasherMock{}, header)
+	expectedDocuments := []*data.Document{
+		{Index: elasticIndexer.BlockIndex, ID: hex.EncodeToString(headerHash), Action: data.ActionDelete},
+	}
+	require.Equal(t, expectedDocuments, writtenDocuments)
 }
 
 func TestElasticProcessor_RemoveMiniblocks(t *testing.T) {
-	called := false
+	var writtenDocuments []*data.Document
 
 	mb1 := &dataBlock.MiniBlock{
 		Type: dataBlock.PeerBlock,
@@ -307,12 +307,9 @@ func TestElasticProcessor_RemoveMiniblocks(t *testing.T) {
 	mbHash2, _ := core.CalculateHash(&mock.MarshalizerMock{}, &mock.HasherMock{}, mb2)
 	mbHash3, _ := core.CalculateHash(&mock.MarshalizerMock{}, &mock.HasherMock{}, mb3)
 
-	args.DBClient = &mock.DatabaseWriterStub{
-		DoQueryRemoveCalled: func(index string, body *bytes.Buffer) error {
-			called = true
-			bodyStr := body.String()
-			require.True(t, strings.Contains(bodyStr, hex.EncodeToString(mbHash2)))
-			require.True(t, strings.Contains(bodyStr, hex.EncodeToString(mbHash3)))
+	args.Sink = &mock.DocumentsSinkStub{
+		WriteDocumentsCalled: func(documents []*data.Document) error {
+			writtenDocuments = documents
 			return nil
 		},
 	}
@@ -346,7 +343,12 @@ func TestElasticProcessor_RemoveMiniblocks(t *testing.T) {
 	}
 	err = elasticProc.RemoveMiniblocks(header, body)
 	require.Nil(t, err)
-	require.True(t, called)
+
+	expectedDocuments := []*data.Document{
+		{Index: elasticIndexer.MiniblocksIndex, ID: hex.EncodeToString(mbHash2), Action: data.ActionDelete},
+		{Index: elasticIndexer.MiniblocksIndex, ID: hex.EncodeToString(mbHash3), Action: data.ActionDelete},
+	}
+	require.Equal(t, expectedDocuments, writtenDocuments)
 }
 
 func TestElasticseachDatabaseSaveHeader_RequestError(t *testing.T) {
@@ -354,13 +356,13 @@ func TestElasticseachDatabaseSaveHeader_RequestError(t *testing.T) {
 	header := &dataBlock.Header{Nonce: 1}
 	signerIndexes := []uint64{0, 1}
 	arguments := createMockElasticProcessorArgs()
-	dbWriter := &mock.DatabaseWriterStub{
-		DoBulkRequestCalled: func(buff *bytes.Buffer, index string) error {
+	documentsSink := &mock.DocumentsSinkStub{
+		WriteDocumentsCalled: func(documents []*data.Document) error {
 			return localErr
 		},
 	}
 	arguments.BlockProc, _ = block.NewBlockProcessor(&mock.HasherMock{}, &mock.MarshalizerMock{})
-	elasticDatabase := newElasticsearchProcessor(dbWriter, arguments)
+	elasticDatabase := newElasticsearchProcessor(documentsSink, arguments)
 
 	err := elasticDatabase.SaveHeader([]byte("hh"), header, signerIndexes, &dataBlock.Body{}, nil, indexer.HeaderGasConsumption{}, 1)
 	require.Equal(t, localErr, err)
@@ -386,13 +388,16 @@ func TestElasticseachDatabaseSaveHeader_CheckRequestBody(t *testing.T) {
 	mbHash, _ := core.CalculateHash(&mock.MarshalizerMock{}, &mock.HasherMock{}, miniBlock)
 	hexEncodedHash := hex.EncodeToString(mbHash)
 
-	dbWriter := &mock.DatabaseWriterStub{
-		DoRequestCalled: func(req *esapi.IndexRequest) error {
-			require.Equal(t, elasticIndexer.BlockIndex, req.Index)
+	called := false
+	documentsSink := &mock.DocumentsSinkStub{
+		WriteDocumentsCalled: func(documents []*data.Document) error {
+			called = true
+			require.Len(t, documents, 1)
+			require.Equal(t, elasticIndexer.BlockIndex, documents[0].Index)
+			require.Equal(t, hex.EncodeToString([]byte("hh")), documents[0].ID)
+			require.Equal(t, data.ActionIndex, documents[0].Action)
 
-			var bl data.Block
-			blockBytes, _ := ioutil.ReadAll(req.Body)
-			_ = json.Unmarshal(blockBytes, &bl)
+			bl := documents[0].Body.(*data.Block)
 			require.Equal(t, header.Nonce, bl.Nonce)
 			require.Equal(t, hexEncodedHash, bl.MiniBlocksHashes[0])
 			require.Equal(t, signerIndexes, bl.Validators)
@@ -402,16 +407,17 @@ func TestElasticseachDatabaseSaveHeader_CheckRequestBody(t *testing.T) {
 	}
 
 	arguments.BlockProc, _ = block.NewBlockProcessor(&mock.HasherMock{}, &mock.MarshalizerMock{})
-	elasticDatabase := newElasticsearchProcessor(dbWriter, arguments)
+	elasticDatabase := newElasticsearchProcessor(documentsSink, arguments)
 	err := elasticDatabase.SaveHeader([]byte("hh"), header, signerIndexes, blockBody, nil, indexer.HeaderGasConsumption{}, 1)
 	require.Nil(t, err)
+	require.True(t, called)
 }
 
 func TestElasticseachSaveTransactions(t *testing.T) {
 	localErr := errors.New("localErr")
 	arguments := createMockElasticProcessorArgs()
-	dbWriter := &mock.DatabaseWriterStub{
-		DoBulkRequestCalled: func(buff *bytes.Buffer, index string) error {
+	documentsSink := &mock.DocumentsSinkStub{
+		WriteDocumentsCalled: func(documents []*data.Document) error {
 			return localErr
 		},
 	}
@@ -426,12 +432,13 @@ func TestElasticseachSaveTransactions(t *testing.T) {
 		ShardCoordinator:       &mock.ShardCoordinatorMock{},
 		Hasher:                 &mock.HasherMock{},
 		Marshalizer:            &mock.MarshalizerMock{},
+		ABIDecoder:             &mock.ABIDecoderStub{},
 		IsInImportMode:         false,
 	}
 	txDbProc, _ := transactions.NewTransactionsProcessor(args)
 	arguments.TransactionsProc = txDbProc
 
-	elasticDatabase := newElasticsearchProcessor(dbWriter, arguments)
+	elasticDatabase := newElasticsearchProcessor(documentsSink, arguments)
 	pool := &indexer.Pool{Txs: txPool}
 	err := elasticDatabase.SaveTransactions(body, header, pool)
 	require.Equal(t, localErr, err)
@@ -444,13 +451,16 @@ func TestElasticProcessor_SaveValidatorsRating(t *testing.T) {
 	blsKey := "bls"
 
 	arguments := createMockElasticProcessorArgs()
-	arguments.DBClient = &mock.DatabaseWriterStub{
-		DoBulkRequestCalled: func(buff *bytes.Buffer, index string) error {
+	arguments.Sink = &mock.DocumentsSinkStub{
+		WriteDocumentsCalled: func(documents []*data.Document) error {
+			require.Len(t, documents, 1)
+			require.Equal(t, elasticIndexer.RatingIndex, documents[0].Index)
+			require.Equal(t, "bls_1", documents[0].ID)
 			return localErr
 		},
 	}
 
-	arguments.ValidatorsProc, _ = validators.NewValidatorsProcessor(mock.NewPubkeyConverterMock(32), 0)
+	arguments.ValidatorsProc, _ = validators.NewValidatorsProcessor(mock.NewPubkeyConverterMock(32))
 	elasticProc, _ := NewElasticProcessor(arguments)
 
 	err := elasticProc.SaveValidatorsRating(
@@ -469,12 +479,12 @@ func TestElasticProcessor_SaveMiniblocks(t *testing.T) {
 	localErr := errors.New("localErr")
 
 	arguments := createMockElasticProcessorArgs()
-	arguments.DBClient = &mock.DatabaseWriterStub{
-		DoBulkRequestCalled: func(buff *bytes.Buffer, index string) error {
+	arguments.Sink = &mock.DocumentsSinkStub{
+		WriteDocumentsCalled: func(documents []*data.Document) error {
 			return localErr
 		},
-		DoMultiGetCalled: func(hashes []string, index string, withSource bool, response interface{}) error {
-			return nil
+		GetDocumentsCalled: func(index string, ids []string) (map[string][]byte, error) {
+			return nil, nil
 		},
 	}
 
@@ -495,13 +505,13 @@ func TestElasticsearch_saveShardValidatorsPubKeys_RequestError(t *testing.T) {
 	valPubKeys := [][]byte{[]byte("key1"), []byte("key2")}
 	localErr := errors.New("localErr")
 	arguments := createMockElasticProcessorArgs()
-	dbWriter := &mock.DatabaseWriterStub{
-		DoRequestCalled: func(req *esapi.IndexRequest) error {
+	documentsSink := &mock.DocumentsSinkStub{
+		WriteDocumentsCalled: func(documents []*data.Document) error {
 			return localErr
 		},
 	}
-	arguments.ValidatorsProc, _ = validators.NewValidatorsProcessor(mock.NewPubkeyConverterMock(32), 0)
-	elasticDatabase := newElasticsearchProcessor(dbWriter, arguments)
+	arguments.ValidatorsProc, _ = validators.NewValidatorsProcessor(mock.NewPubkeyConverterMock(32))
+	elasticDatabase := newElasticsearchProcessor(documentsSink, arguments)
 
 	err := elasticDatabase.SaveShardValidatorsPubKeys(shardID, epoch, valPubKeys)
 	require.Equal(t, localErr, err)
@@ -512,13 +522,15 @@ func TestElasticsearch_saveShardValidatorsPubKeys(t *testing.T) {
 	epoch := uint32(0)
 	valPubKeys := [][]byte{[]byte("key1"), []byte("key2")}
 	arguments := createMockElasticProcessorArgs()
-	dbWriter := &mock.DatabaseWriterStub{
-		DoRequestCalled: func(req *esapi.IndexRequest) error {
-			require.Equal(t, fmt.Sprintf("%d_%d", shardID, epoch), req.DocumentID)
+	documentsSink := &mock.DocumentsSinkStub{
+		WriteDocumentsCalled: func(documents []*data.Document) error {
+			require.Len(t, documents, 1)
+			require.Equal(t, elasticIndexer.ValidatorsIndex, documents[0].Index)
+			require.Equal(t, fmt.Sprintf("%d_%d", shardID, epoch), documents[0].ID)
 			return nil
 		},
 	}
-	elasticDatabase := newElasticsearchProcessor(dbWriter, arguments)
+	elasticDatabase := newElasticsearchProcessor(documentsSink, arguments)
 
 	err := elasticDatabase.SaveShardValidatorsPubKeys(shardID, epoch, valPubKeys)
 	require.Nil(t, err)
@@ -529,13 +541,15 @@ func TestElasticsearch_saveRoundInfo(t *testing.T) {
 		Index: 1, ShardId: 0, BlockWasProposed: true,
 	}
 	arguments := createMockElasticProcessorArgs()
-	dbWriter := &mock.DatabaseWriterStub{
-		DoRequestCalled: func(req *esapi.IndexRequest) error {
-			require.Equal(t, strconv.FormatUint(uint64(roundInfo.ShardId), 10)+"_"+strconv.FormatUint(roundInfo.Index, 10), req.DocumentID)
+	documentsSink := &mock.DocumentsSinkStub{
+		WriteDocumentsCalled: func(documents []*data.Document) error {
+			require.Len(t, documents, 1)
+			require.Equal(t, elasticIndexer.RoundsIndex, documents[0].Index)
+			require.Equal(t, "0_1", documents[0].ID)
 			return nil
 		},
 	}
-	elasticDatabase := newElasticsearchProcessor(dbWriter, arguments)
+	elasticDatabase := newElasticsearchProcessor(documentsSink, arguments)
 
 	err := elasticDatabase.SaveRoundsInfo([]*data.RoundInfo{roundInfo})
 	require.Nil(t, err)
@@ -545,12 +559,12 @@ func TestElasticsearch_saveRoundInfoRequestError(t *testing.T) {
 	roundInfo := &data.RoundInfo{}
 	localError := errors.New("local err")
 	arguments := createMockElasticProcessorArgs()
-	dbWriter := &mock.DatabaseWriterStub{
-		DoBulkRequestCalled: func(buff *bytes.Buffer, index string) error {
+	documentsSink := &mock.DocumentsSinkStub{
+		WriteDocumentsCalled: func(documents []*data.Document) error {
 			return localError
 		},
 	}
-	elasticDatabase := newElasticsearchProcessor(dbWriter, arguments)
+	elasticDatabase := newElasticsearchProcessor(documentsSink, arguments)
 
 	err := elasticDatabase.SaveRoundsInfo([]*data.RoundInfo{roundInfo})
 	require.Equal(t, localError, err)
@@ -560,16 +574,12 @@ func TestElasticsearch_saveRoundInfoRequestError(t *testing.T) {
 func TestElasticProcessor_RemoveTransactions(t *testing.T) {
 	arguments := createMockElasticProcessorArgs()
 
-	called := false
+	var writtenDocuments []*data.Document
 	txsHashes := [][]byte{[]byte("txHas1"), []byte("txHash2")}
 	expectedHashes := []string{hex.EncodeToString(txsHashes[0]), hex.EncodeToString(txsHashes[1])}
-	dbWriter := &mock.DatabaseWriterStub{
-		DoQueryRemoveCalled: func(index string, body *bytes.Buffer) error {
-			bodyStr := body.String()
-			require.Contains(t, []string{elasticIndexer.TransactionsIndex, elasticIndexer.OperationsIndex}, index)
-			require.True(t, strings.Contains(bodyStr, expectedHashes[0]))
-			require.True(t, strings.Contains(bodyStr, expectedHashes[1]))
-			called = true
+	documentsSink := &mock.DocumentsSinkStub{
+		WriteDocumentsCalled: func(documents []*data.Document) error {
+			writtenDocuments = documents
 			return nil
 		},
 	}
@@ -580,13 +590,14 @@ func TestElasticProcessor_RemoveTransactions(t *testing.T) {
 		ShardCoordinator:       &mock.ShardCoordinatorMock{},
 		Hasher:                 &mock.HasherMock{},
 		Marshalizer:            &mock.MarshalizerMock{},
+		ABIDecoder:             &mock.ABIDecoderStub{},
 		IsInImportMode:         false,
 	}
 	txDbProc, _ := transactions.NewTransactionsProcessor(args)
 
 	arguments.TransactionsProc = txDbProc
 
-	elasticSearchProc := newElasticsearchProcessor(dbWriter, arguments)
+	elasticSearchProc := newElasticsearchProcessor(documentsSink, arguments)
 
 	header := &dataBlock.Header{ShardID: core.MetachainShardId, MiniBlockHeaders: []dataBlock.MiniBlockHeader{{}}}
 	blk := &dataBlock.Body{
@@ -605,26 +616,52 @@ func TestElasticProcessor_RemoveTransactions(t *testing.T) {
 
 	err := elasticSearchProc.RemoveTransactions(header, blk)
 	require.Nil(t, err)
-	require.True(t, called)
+
+	expectedDocuments := []*data.Document{
+		{Index: elasticIndexer.TransactionsIndex, ID: expectedHashes[0], Action: data.ActionDelete},
+		{Index: elasticIndexer.TransactionsIndex, ID: expectedHashes[1], Action: data.ActionDelete},
+		{Index: elasticIndexer.OperationsIndex, ID: expectedHashes[0], Action: data.ActionDelete},
+		{Index: elasticIndexer.OperationsIndex, ID: expectedHashes[1], Action: data.ActionDelete},
+	}
+	require.Equal(t, expectedDocuments, writtenDocuments)
+}
+
+func TestElasticProcessor_RemoveAccountsMECT(t *testing.T) {
+	t.Parallel()
+
+	removedIndices := make([]string, 0)
+	documentsSink := &mock.DocumentsSinkStub{
+		DeleteMatchingCalled: func(index string, fields map[string]interface{}) error {
+			removedIndices = append(removedIndices, index)
+			require.Equal(t, map[string]interface{}{"shardID": uint32(1), "timestamp": uint64(1234)}, fields)
+			return nil
+		},
+	}
+	elasticSearchProc := newElasticsearchProcessor(documentsSink, createMockElasticProcessorArgs())
+	elasticSearchProc.selfShardID = 1
+
+	err := elasticSearchProc.RemoveAccountsMECT(1234)
+	require.Nil(t, err)
+	require.Equal(t, []string{elasticIndexer.AccountsMECTIndex, elasticIndexer.AccountsMECTHistoryIndex}, removedIndices)
 }
 
 func TestElasticProcessor_IndexEpochInfoData(t *testing.T) {
 	called := false
 	arguments := createMockElasticProcessorArgs()
-	dbWriter := &mock.DatabaseWriterStub{
-		DoBulkRequestCalled: func(buff *bytes.Buffer, index string) error {
+	documentsSink := &mock.DocumentsSinkStub{
+		WriteDocumentsCalled: func(documents []*data.Document) error {
 			called = true
 			return nil
 		},
 	}
 
-	elasticSearchProc := newElasticsearchProcessor(dbWriter, arguments)
+	elasticSearchProc := newElasticsearchProcessor(documentsSink, arguments)
 	elasticSearchProc.selfShardID = core.MetachainShardId
 	elasticSearchProc.enabledIndexes[elasticIndexer.EpochInfoIndex] = struct{}{}
 
-	buffSlice := data.NewBufferSlice(data.DefaultMaxBulkSize)
+	docs := data.NewDocumentsSlice()
 	shardHeader := &dataBlock.Header{}
-	err := elasticSearchProc.indexEpochInfoData(shardHeader, buffSlice)
+	err := elasticSearchProc.indexEpochInfoData(shardHeader, docs)
 	require.True(t, errors.Is(err, elasticIndexer.ErrHeaderTypeAssertion))
 
 	body := &dataBlock.Body{}
@@ -635,8 +672,7 @@ func TestElasticProcessor_IndexEpochInfoData(t *testing.T) {
 	require.True(t, called)
 }
 
-func TestElasticProcessor_SaveTransactionNoDataShouldNotDoRequest(t *testing.T) {
-	called := false
+func TestElasticProcessor_SaveTransactionNoDataShouldNotWriteDocuments(t *testing.T) {
 	arguments := createMockElasticProcessorArgs()
 	arguments.TransactionsProc = &mock.DBTransactionProcessorStub{
 		PrepareTransactionsForDatabaseCalled: func(body *dataBlock.Body, header coreData.HeaderHandler, pool *indexer.Pool) *data.PreparedResults {
@@ -647,50 +683,291 @@ func TestElasticProcessor_SaveTransactionNoDataShouldNotDoRequest(t *testing.T)
 				AlteredAccts: nil,
 			}
 		},
-		SerializeScResultsCalled: func(scrs []*data.ScResult, _ *data.BufferSlice, _ string) error {
+		SerializeScResultsCalled: func(scrs []*data.ScResult, _ *data.DocumentsSlice, _ string) error {
 			return nil
 		},
 	}
-	dbWriter := &mock.DatabaseWriterStub{
-		DoBulkRequestCalled: func(buff *bytes.Buffer, index string) error {
-			called = true
+	documentsSink := &mock.DocumentsSinkStub{
+		WriteDocumentsCalled: func(documents []*data.Document) error {
+			require.Empty(t, documents)
 			return nil
 		},
 	}
 
-	elasticSearchProc := newElasticsearchProcessor(dbWriter, arguments)
+	elasticSearchProc := newElasticsearchProcessor(documentsSink, arguments)
 	elasticSearchProc.enabledIndexes[elasticIndexer.ScResultsIndex] = struct{}{}
 
 	err := elasticSearchProc.SaveTransactions(&dataBlock.Body{}, &dataBlock.Header{}, &indexer.Pool{})
 	require.Nil(t, err)
-	require.False(t, called)
 }
 
 func TestElasticProcessor_IndexAlteredAccounts(t *testing.T) {
 	called := false
-	dbWriter := &mock.DatabaseWriterStub{
-		DoBulkRequestCalled: func(buff *bytes.Buffer, index string) error {
-			return nil
-		},
-		DoMultiGetCalled: func(ids []string, index string, withSource bool, response interface{}) error {
-			return nil
-		},
-	}
 	arguments := createMockElasticProcessorArgs()
 	arguments.AccountsProc = &mock.DBAccountsHandlerStub{
-		SerializeAccountsHistoryCalled: func(accounts map[string]*data.AccountBalanceHistory, _ *data.BufferSlice, _ string) error {
+		SerializeAccountsHistoryCalled: func(accounts map[string]*data.AccountBalanceHistory, _ *data.DocumentsSlice, _ string) error {
 			called = true
 			return nil
 		},
 	}
-	elasticSearchProc := newElasticsearchProcessor(dbWriter, arguments)
+	elasticSearchProc := newElasticsearchProcessor(&mock.DocumentsSinkStub{}, arguments)
 	elasticSearchProc.enabledIndexes[elasticIndexer.AccountsMECTIndex] = struct{}{}
 	elasticSearchProc.enabledIndexes[elasticIndexer.AccountsMECTHistoryIndex] = struct{}{}
 
-	buffSlice := data.NewBufferSlice(data.DefaultMaxBulkSize)
+	docs := data.NewDocumentsSlice()
 	alteredAccounts := data.NewAlteredAccounts()
 	tagsCount := tags.NewTagsCount()
-	err := elasticSearchProc.indexAlteredAccounts(100, alteredAccounts, nil, buffSlice, tagsCount)
+	err := elasticSearchProc.indexAlteredAccounts(100, alteredAccounts, nil, docs, tagsCount)
 	require.Nil(t, err)
 	require.True(t, called)
 }
+
+func TestElasticProcessor_SavePreparedTransactionsNilShouldErr(t *testing.T) {
+	t.Parallel()
+
+	elasticSearchProc := newElasticsearchProcessor(&mock.DocumentsSinkStub{}, createMockElasticProcessorArgs())
+
+	err := elasticSearchProc.SavePreparedTransactions(&dataBlock.Header{}, &indexer.Pool{}, nil)
+	require.Equal(t, elasticIndexer.ErrNilPreparedTransactions, err)
+}
+
+func TestElasticProcessor_AddTokenTypeShouldUpdateMatchingDocuments(t *testing.T) {
+	t.Parallel()
+
+	var writtenDocuments []*data.Document
+	documentsSink := &mock.DocumentsSinkStub{
+		IterateMatchingIDsCalled: func(index string, fields map[string]interface{}, handlerFunc func(ids []string) error) error {
+			require.Equal(t, elasticIndexer.AccountsMECTIndex, index)
+			require.Equal(t, map[string]interface{}{"token": "NFT-abcd", "type": nil}, fields)
+			return handlerFunc([]string{"addr1-NFT-abcd-01"})
+		},
+		WriteDocumentsCalled: func(documents []*data.Document) error {
+			writtenDocuments = documents
+			return nil
+		},
+	}
+	elasticSearchProc := newElasticsearchProcessor(documentsSink, createMockElasticProcessorArgs())
+
+	tokensData := []*data.TokenInfo{
+		{Token: "FNG-abcd", Type: core.FungibleMECT},
+		{Token: "NFT-abcd", Type: core.NonFungibleMECT},
+	}
+	err := elasticSearchProc.addTokenType(tokensData, elasticIndexer.AccountsMECTIndex)
+	require.Nil(t, err)
+
+	expectedDocuments := []*data.Document{
+		{
+			Index:  elasticIndexer.AccountsMECTIndex,
+			ID:     "addr1-NFT-abcd-01",
+			Action: data.ActionUpdate,
+			Fields: map[string]interface{}{"type": core.NonFungibleMECT},
+		},
+	}
+	require.Equal(t, expectedDocuments, writtenDocuments)
+}
+
+func TestElasticProcessor_SaveHeaderShouldPublishBlockOnlyAfterWrite(t *testing.T) {
+	t.Parallel()
+
+	localErr := errors.New("local error")
+	published := false
+	arguments := createMockElasticProcessorArgs()
+	arguments.EventsPublisher = &mock.EventsPublisherStub{
+		PublishBlockCalled: func(headerHash []byte, header coreData.HeaderHandler, block *data.Block) error {
+			published = true
+			require.Equal(t, []byte("hh"), headerHash)
+			require.Equal(t, uint64(1), block.Nonce)
+			return nil
+		},
+	}
+	writeErr := localErr
+	documentsSink := &mock.DocumentsSinkStub{
+		WriteDocumentsCalled: func(documents []*data.Document) error {
+			return writeErr
+		},
+	}
+	elasticProc := newElasticsearchProcessor(documentsSink, arguments)
+
+	header := &dataBlock.Header{Nonce: 1}
+	err := elasticProc.SaveHeader([]byte("hh"), header, nil, &dataBlock.Body{}, nil, indexer.HeaderGasConsumption{}, 0)
+	require.Equal(t, localErr, err)
+	require.False(t, published)
+
+	writeErr = nil
+	err = elasticProc.SaveHeader([]byte("hh"), header, nil, &dataBlock.Body{}, nil, indexer.HeaderGasConsumption{}, 0)
+	require.Nil(t, err)
+	require.True(t, published)
+}
+
+func TestElasticProcessor_RemoveHeaderShouldPublishRevert(t *testing.T) {
+	t.Parallel()
+
+	header := &dataBlock.Header{Nonce: 5}
+	expectedHash, _ := core.CalculateHash(&mock.MarshalizerMock{}, &mock.HasherMock{}, header)
+
+	published := false
+	arguments := createMockElasticProcessorArgs()
+	arguments.EventsPublisher = &mock.EventsPublisherStub{
+		PublishRevertCalled: func(headerHash []byte, h coreData.HeaderHandler) error {
+			published = true
+			require.Equal(t, expectedHash, headerHash)
+			require.Equal(t, header, h)
+			return nil
+		},
+	}
+	elasticProc, _ := NewElasticProcessor(arguments)
+
+	err := elasticProc.RemoveHeader(header)
+	require.Nil(t, err)
+	require.True(t, published)
+}
+
+func TestElasticProcessor_SavePreparedTransactionsShouldPublish(t *testing.T) {
+	t.Parallel()
+
+	preparedTxs := &data.PreparedBlockTransactions{
+		Results:   &data.PreparedResults{Transactions: []*data.Transaction{{Hash: "tx"}}},
+		LogsData:  &data.PreparedLogsResults{},
+		Documents: data.NewDocumentsSlice(),
+	}
+
+	published := false
+	arguments := createMockElasticProcessorArgs()
+	arguments.EventsPublisher = &mock.EventsPublisherStub{
+		PublishTransactionsCalled: func(headerHash []byte, header coreData.HeaderHandler, providedTxs *data.PreparedBlockTransactions) error {
+			published = true
+			require.True(t, preparedTxs == providedTxs)
+			return nil
+		},
+	}
+	elasticProc, _ := NewElasticProcessor(arguments)
+
+	err := elasticProc.SavePreparedTransactions(&dataBlock.Header{}, &indexer.Pool{}, preparedTxs)
+	require.Nil(t, err)
+	require.True(t, published)
+}
+
+func TestElasticProcessor_PublishErrorsShouldNotFailTheSave(t *testing.T) {
+	t.Parallel()
+
+	localErr := errors.New("local error")
+	numWrites := 0
+	arguments := createMockElasticProcessorArgs()
+	arguments.EventsPublisher = &mock.EventsPublisherStub{
+		PublishBlockCalled: func(headerHash []byte, header coreData.HeaderHandler, block *data.Block) error {
+			return localErr
+		},
+		PublishTransactionsCalled: func(headerHash []byte, header coreData.HeaderHandler, preparedTxs *data.PreparedBlockTransactions) error {
+			return localErr
+		},
+		PublishRevertCalled: func(headerHash []byte, header coreData.HeaderHandler) error {
+			return localErr
+		},
+	}
+	documentsSink := &mock.DocumentsSinkStub{
+		WriteDocumentsCalled: func(documents []*data.Document) error {
+			numWrites++
+			return nil
+		},
+	}
+	elasticProc := newElasticsearchProcessor(documentsSink, arguments)
+
+	header := &dataBlock.Header{Nonce: 1}
+	err := elasticProc.SaveHeader([]byte("hh"), header, nil, &dataBlock.Body{}, nil, indexer.HeaderGasConsumption{}, 0)
+	require.Nil(t, err)
+
+	err = elasticProc.SavePreparedTransactions(header, &indexer.Pool{}, &data.PreparedBlockTransactions{
+		Results:   &data.PreparedResults{},
+		LogsData:  &data.PreparedLogsResults{},
+		Documents: data.NewDocumentsSlice(),
+	})
+	require.Nil(t, err)
+
+	err = elasticProc.RemoveHeader(header)
+	require.Nil(t, err)
+	require.Equal(t, 3, numWrites)
+}
+
+func TestElasticProcessor_FinalizeBlockShouldMarkTrackedDocumentsAsFinal(t *testing.T) {
+	t.Parallel()
+
+	arguments := createMockElasticProcessorArgs()
+	arguments.EnabledIndexes[elasticIndexer.ScResultsIndex] = struct{}{}
+	writtenDocuments := make([]*data.Document, 0)
+	documentsSink := &mock.DocumentsSinkStub{
+		WriteDocumentsCalled: func(documents []*data.Document) error {
+			writtenDocuments = append(writtenDocuments, documents...)
+			return nil
+		},
+	}
+	elasticProc := newElasticsearchProcessor(documentsSink, arguments)
+
+	header1 := &dataBlock.Header{Nonce: 1}
+	header2 := &dataBlock.Header{Nonce: 2}
+	headerHash1, _ := core.CalculateHash(&mock.MarshalizerMock{}, &mock.HasherMock{}, header1)
+	headerHash2, _ := core.CalculateHash(&mock.MarshalizerMock{}, &mock.HasherMock{}, header2)
+
+	err := elasticProc.SaveHeader(headerHash1, header1, nil, &dataBlock.Body{}, nil, indexer.HeaderGasConsumption{}, 0)
+	require.Nil(t, err)
+	require.False(t, writtenDocuments[0].Body.(*data.Block).Finalized)
+
+	err = elasticProc.SavePreparedTransactions(header1, &indexer.Pool{}, &data.PreparedBlockTransactions{
+		Results: &data.PreparedResults{
+			Transactions: []*data.Transaction{{Hash: "tx"}},
+			ScResults:    []*data.ScResult{{Hash: "scr"}},
+		},
+		LogsData:  &data.PreparedLogsResults{},
+		Documents: data.NewDocumentsSlice(),
+	})
+	require.Nil(t, err)
+
+	err = elasticProc.SaveHeader(headerHash2, header2, nil, &dataBlock.Body{}, nil, indexer.HeaderGasConsumption{}, 0)
+	require.Nil(t, err)
+
+	writtenDocuments = writtenDocuments[:0]
+	err = elasticProc.FinalizeBlock(headerHash1)
+	require.Nil(t, err)
+	finalizedFields := map[string]interface{}{"finalized": true}
+	require.Equal(t, []*data.Document{
+		{Index: elasticIndexer.BlockIndex, ID: hex.EncodeToString(headerHash1), Action: data.ActionUpdate, Fields: finalizedFields},
+		{Index: elasticIndexer.TransactionsIndex, ID: "tx", Action: data.ActionUpdate, Fields: finalizedFields},
+		{Index: elasticIndexer.ScResultsIndex, ID: "scr", Action: data.ActionUpdate, Fields: finalizedFields},
+	}, writtenDocuments)
+
+	writtenDocuments = writtenDocuments[:0]
+	err = elasticProc.FinalizeBlock(headerHash1)
+	require.Nil(t, err)
+	require.Empty(t, writtenDocuments)
+
+	err = elasticProc.RemoveHeader(header2)
+	require.Nil(t, err)
+
+	writtenDocuments = writtenDocuments[:0]
+	err = elasticProc.FinalizeBlock(headerHash2)
+	require.Nil(t, err)
+	require.Empty(t, writtenDocuments)
+}
+
+func TestElasticProcessor_FinalizedDataOnlyShouldWriteFinalDocuments(t *testing.T) {
+	t.Parallel()
+
+	arguments := createMockElasticProcessorArgs()
+	arguments.FinalizedDataOnly = true
+	writtenDocuments := make([]*data.Document, 0)
+	documentsSink := &mock.DocumentsSinkStub{
+		WriteDocumentsCalled: func(documents []*data.Document) error {
+			writtenDocuments = append(writtenDocuments, documents...)
+			return nil
+		},
+	}
+	elasticProc := newElasticsearchProcessor(documentsSink, arguments)
+
+	err := elasticProc.SaveHeader([]byte("hh"), &dataBlock.Header{Nonce: 1}, nil, &dataBlock.Body{}, nil, indexer.HeaderGasConsumption{}, 0)
+	require.Nil(t, err)
+	require.True(t, writtenDocuments[0].Body.(*data.Block).Finalized)
+
+	writtenDocuments = writtenDocuments[:0]
+	err = elasticProc.FinalizeBlock([]byte("hh"))
+	require.Nil(t, err)
+	require.Empty(t, writtenDocuments)
+}
@@ -1,38 +1,62 @@
 package factory
 
 import (
+	"bytes"
+
 	"github.com/ME-MotherEarth/me-core/core"
+	"github.com/ME-MotherEarth/me-core/core/check"
 	"github.com/ME-MotherEarth/me-core/hashing"
 	"github.com/ME-MotherEarth/me-core/marshal"
 	indexer "github.com/ME-MotherEarth/me-elastic-indexer"
 	"github.com/ME-MotherEarth/me-elastic-indexer/converters"
+	"github.com/ME-MotherEarth/me-elastic-indexer/metrics"
 	processIndexer "github.com/ME-MotherEarth/me-elastic-indexer/process"
+	"github.com/ME-MotherEarth/me-elastic-indexer/process/abi"
 	"github.com/ME-MotherEarth/me-elastic-indexer/process/accounts"
 	blockProc "github.com/ME-MotherEarth/me-elastic-indexer/process/block"
 	"github.com/ME-MotherEarth/me-elastic-indexer/process/logsevents"
 	"github.com/ME-MotherEarth/me-elastic-indexer/process/miniblocks"
 	"github.com/ME-MotherEarth/me-elastic-indexer/process/operations"
+	"github.com/ME-MotherEarth/me-elastic-indexer/process/sink"
+	"github.com/ME-MotherEarth/me-elastic-indexer/process/sink/elastic"
+	"github.com/ME-MotherEarth/me-elastic-indexer/process/sink/postgres"
 	"github.com/ME-MotherEarth/me-elastic-indexer/process/statistics"
+	"github.com/ME-MotherEarth/me-elastic-indexer/process/stream"
 	"github.com/ME-MotherEarth/me-elastic-indexer/process/templatesAndPolicies"
 	"github.com/ME-MotherEarth/me-elastic-indexer/process/transactions"
 	"github.com/ME-MotherEarth/me-elastic-indexer/process/validators"
 )
 
-// ArgElasticProcessorFactory is struct that is used to store all components that are needed to create an elastic processor factory
+// ArgElasticProcessorFactory is struct that is used to store all components that are needed to create an elastic processor factory.
+// The EventProcessors are added by integrators to the built-in ones, and the indices they declare are created from
+// their templates, while their documents are written only if the indices are enabled. The calls and the events of the
+// ContractABIs are decoded with their ABI files
 type ArgElasticProcessorFactory struct {
-	Marshalizer              marshal.Marshalizer
-	Hasher                   hashing.Hasher
-	AddressPubkeyConverter   core.PubkeyConverter
-	ValidatorPubkeyConverter core.PubkeyConverter
-	DBClient                 processIndexer.DatabaseClientHandler
-	AccountsDB               indexer.AccountsAdapter
-	ShardCoordinator         indexer.ShardCoordinator
-	TransactionFeeCalculator indexer.FeesProcessorHandler
-	EnabledIndexes           []string
-	Denomination             int
-	BulkRequestMaxSize       int
-	IsInImportDBMode         bool
-	UseKibana                bool
+	Marshalizer               marshal.Marshalizer
+	Hasher                    hashing.Hasher
+	AddressPubkeyConverter    core.PubkeyConverter
+	ValidatorPubkeyConverter  core.PubkeyConverter
+	DBClient                  elastic.DatabaseClientHandler
+	SQLClient                 postgres.DatabaseClientHandler
+	MetricsHandler            elastic.BulkMetricsHandler
+	EventsBroker              stream.BrokerHandler
+	EventsTopicPrefix         string
+	AccountsDB                indexer.AccountsAdapter
+	ShardCoordinator          indexer.ShardCoordinator
+	TransactionFeeCalculator  indexer.FeesProcessorHandler
+	EnabledIndexes            []string
+	Denomination              int
+	BulkRequestMaxSize        int
+	NumConcurrentBulkRequests int
+	IsInImportDBMode          bool
+	UseKibana                 bool
+	FinalizedDataOnly         bool
+	Rollover                  elastic.RolloverConditions
+	IndexPrefix               string
+	AllowMappingsConflicts    bool
+	CheckMigrations           bool
+	EventProcessors           []logsevents.EventProcessor
+	ContractABIs              []abi.ContractConfig
 }
 
 // CreateElasticProcessor will create a new instance of ElasticProcessor
@@ -43,6 +67,21 @@ func CreateElasticProcessor(arguments ArgElasticProcessorFactory) (indexer.Elast
 		return nil, err
 	}
 
+	customIndices, err := logsevents.GetCustomIndices(arguments.EventProcessors)
+	if err != nil {
+		return nil, err
+	}
+	customIndicesNames := make([]string, 0, len(customIndices))
+	revertibleIndexes := make([]string, 0, len(customIndices))
+	for _, customIndex := range customIndices {
+		customIndicesNames = append(customIndicesNames, customIndex.Name)
+		// the template is copied, as the declared indices are created by the processor of the finalized data too
+		indexTemplates[customIndex.Name] = bytes.NewBuffer(customIndex.Template.Bytes())
+		if customIndex.Revertible {
+			revertibleIndexes = append(revertibleIndexes, customIndex.Name)
+		}
+	}
+
 	enabledIndexesMap := make(map[string]struct{})
 	for _, index := range arguments.EnabledIndexes {
 		enabledIndexesMap[index] = struct{}{}
@@ -76,19 +115,25 @@ func CreateElasticProcessor(arguments ArgElasticProcessorFactory) (indexer.Elast
 	if err != nil {
 		return nil, err
 	}
-	validatorsProc, err := validators.NewValidatorsProcessor(arguments.ValidatorPubkeyConverter, arguments.BulkRequestMaxSize)
+	validatorsProc, err := validators.NewValidatorsProcessor(arguments.ValidatorPubkeyConverter)
 	if err != nil {
 		return nil, err
 	}
 
 	generalInfoProc := statistics.NewStatisticsProcessor()
 
+	abiDecoder, err := createABIDecoder(arguments.ContractABIs, arguments.AddressPubkeyConverter, arguments.AccountsDB)
+	if err != nil {
+		return nil, err
+	}
+
 	argsTxsProc := &transactions.ArgsTransactionProcessor{
 		AddressPubkeyConverter: arguments.AddressPubkeyConverter,
 		TxFeeCalculator:        arguments.TransactionFeeCalculator,
 		ShardCoordinator:       arguments.ShardCoordinator,
 		Hasher:                 arguments.Hasher,
 		Marshalizer:            arguments.Marshalizer,
+		ABIDecoder:             abiDecoder,
 		IsInImportMode:         arguments.IsInImportDBMode,
 	}
 	txsProc, err := transactions.NewTransactionsProcessor(argsTxsProc)
@@ -103,6 +148,8 @@ func CreateElasticProcessor(arguments ArgElasticProcessorFactory) (indexer.Elast
 		BalanceConverter: balanceConverter,
 		Hasher:           arguments.Hasher,
 		TxFeeCalculator:  arguments.TransactionFeeCalculator,
+		ABIDecoder:       abiDecoder,
+		EventProcessors:  arguments.EventProcessors,
 	}
 	logsAndEventsProc, err := logsevents.NewLogsAndEventsProcessor(argsLogsAndEventsProc)
 	if err != nil {
@@ -114,23 +161,110 @@ func CreateElasticProcessor(arguments ArgElasticProcessorFactory) (indexer.Elast
 		return nil, err
 	}
 
+	argsElasticSink := elastic.ArgsElasticSink{
+		DBClient:                  arguments.DBClient,
+		MetricsHandler:            createBulkMetricsHandler(arguments.MetricsHandler),
+		BulkRequestMaxSize:        arguments.BulkRequestMaxSize,
+		NumConcurrentBulkRequests: arguments.NumConcurrentBulkRequests,
+		UseKibana:                 arguments.UseKibana,
+		IndexTemplates:            indexTemplates,
+		IndexPolicies:             indexPolicies,
+		Rollover:                  arguments.Rollover,
+		IndexPrefix:               arguments.IndexPrefix,
+		AllowMappingsConflicts:    arguments.AllowMappingsConflicts,
+		CheckMigrations:           arguments.CheckMigrations,
+		CustomIndices:             customIndicesNames,
+	}
+	elasticSink, err := elastic.NewElasticSink(argsElasticSink)
+	if err != nil {
+		return nil, err
+	}
+
+	documentsSink, err := createDocumentsSink(elasticSink, arguments.SQLClient, customIndicesNames)
+	if err != nil {
+		return nil, err
+	}
+
+	eventsPublisher, err := createEventsPublisher(arguments.EventsBroker, arguments.EventsTopicPrefix)
+	if err != nil {
+		return nil, err
+	}
+
 	args := &processIndexer.ArgElasticProcessor{
-		BulkRequestMaxSize: arguments.BulkRequestMaxSize,
-		TransactionsProc:   txsProc,
-		AccountsProc:       accountsProc,
-		BlockProc:          blockProcHandler,
-		MiniblocksProc:     miniblocksProc,
-		ValidatorsProc:     validatorsProc,
-		StatisticsProc:     generalInfoProc,
-		LogsAndEventsProc:  logsAndEventsProc,
-		DBClient:           arguments.DBClient,
-		EnabledIndexes:     enabledIndexesMap,
-		UseKibana:          arguments.UseKibana,
-		IndexTemplates:     indexTemplates,
-		IndexPolicies:      indexPolicies,
-		SelfShardID:        arguments.ShardCoordinator.SelfId(),
-		OperationsProc:     operationsProc,
+		TransactionsProc:  txsProc,
+		AccountsProc:      accountsProc,
+		BlockProc:         blockProcHandler,
+		MiniblocksProc:    miniblocksProc,
+		ValidatorsProc:    validatorsProc,
+		StatisticsProc:    generalInfoProc,
+		LogsAndEventsProc: logsAndEventsProc,
+		Sink:              documentsSink,
+		EnabledIndexes:    enabledIndexesMap,
+		SelfShardID:       arguments.ShardCoordinator.SelfId(),
+		OperationsProc:    operationsProc,
+		EventsPublisher:   eventsPublisher,
+		FinalizedDataOnly: arguments.FinalizedDataOnly,
+		RevertibleIndexes: revertibleIndexes,
 	}
 
 	return processIndexer.NewElasticProcessor(args)
 }
+
+// createDocumentsSink will also write the documents in a PostgreSQL database if a sql client is provided, while the
+// stored documents are still read from elasticsearch
+func createDocumentsSink(
+	elasticSink sink.DocumentsSink,
+	sqlClient postgres.DatabaseClientHandler,
+	customIndices []string,
+) (sink.DocumentsSink, error) {
+	if check.IfNil(sqlClient) {
+		return elasticSink, nil
+	}
+
+	postgresSink, err := postgres.NewPostgresSink(postgres.ArgsPostgresSink{
+		DBClient:     sqlClient,
+		CustomTables: customIndices,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return sink.NewMultiSink(elasticSink, postgresSink)
+}
+
+// createBulkMetricsHandler will return a handler that records nothing if no metrics handler is provided
+func createBulkMetricsHandler(metricsHandler elastic.BulkMetricsHandler) elastic.BulkMetricsHandler {
+	if check.IfNil(metricsHandler) {
+		return metrics.NewDisabledMetrics()
+	}
+
+	return metricsHandler
+}
+
+func createEventsPublisher(broker stream.BrokerHandler, topicPrefix string) (processIndexer.EventsPublisher, error) {
+	if check.IfNil(broker) {
+		return stream.NewDisabledEventsPublisher(), nil
+	}
+
+	return stream.NewEventsPublisher(stream.ArgsEventsPublisher{
+		Broker:      broker,
+		TopicPrefix: topicPrefix,
+	})
+}
+
+// createABIDecoder will return a decoder that decodes nothing if no smart contract ABI is provided
+func createABIDecoder(
+	contracts []abi.ContractConfig,
+	pubKeyConverter core.PubkeyConverter,
+	accountsDB indexer.AccountsAdapter,
+) (indexer.ABIDecoder, error) {
+	if len(contracts) == 0 {
+		return abi.NewDisabledABIDecoder(), nil
+	}
+
+	return abi.NewABIDecoder(abi.ArgsABIDecoder{
+		Contracts:       contracts,
+		PubKeyConverter: pubKeyConverter,
+		AccountsDB:      accountsDB,
+	})
+}
@@ -0,0 +1,128 @@
+package process
+
+import (
+	"encoding/hex"
+	"sort"
+	"sync"
+)
+
+// maxTrackedBlocks bounds the number of blocks that wait to be finalized, so the tracker does not grow indefinitely
+// if the node stops sending the finalized notifications
+const maxTrackedBlocks = 1000
+
+// trackedBlock holds the IDs of the documents of an indexed block that are not final yet
+type trackedBlock struct {
+	hash      string
+	nonce     uint64
+	hasBlock  bool
+	txHashes  []string
+	scrHashes []string
+}
+
+// finalityTracker keeps, for every indexed block that was not finalized yet, the IDs of the documents that have to
+// be marked as final when the block, or a block built on top of it, is finalized
+type finalityTracker struct {
+	mutBlocks sync.Mutex
+	blocks    map[string]*trackedBlock
+}
+
+func newFinalityTracker() *finalityTracker {
+	return &finalityTracker{
+		blocks: make(map[string]*trackedBlock),
+	}
+}
+
+func (ft *finalityTracker) addBlock(headerHash []byte, nonce uint64) {
+	ft.mutBlocks.Lock()
+	defer ft.mutBlocks.Unlock()
+
+	tb := ft.getOrCreate(headerHash, nonce)
+	tb.hasBlock = true
+}
+
+func (ft *finalityTracker) addResults(headerHash []byte, nonce uint64, txHashes []string, scrHashes []string) {
+	if len(txHashes) == 0 && len(scrHashes) == 0 {
+		return
+	}
+
+	ft.mutBlocks.Lock()
+	defer ft.mutBlocks.Unlock()
+
+	tb := ft.getOrCreate(headerHash, nonce)
+	tb.txHashes = append(tb.txHashes, txHashes...)
+	tb.scrHashes = append(tb.scrHashes, scrHashes...)
+}
+
+func (ft *finalityTracker) getOrCreate(headerHash []byte, nonce uint64) *trackedBlock {
+	hash := hex.EncodeToString(headerHash)
+	tb, found := ft.blocks[hash]
+	if found {
+		return tb
+	}
+
+	if len(ft.blocks) >= maxTrackedBlocks {
+		ft.removeOldest()
+	}
+
+	tb = &trackedBlock{
+		hash:  hash,
+		nonce: nonce,
+	}
+	ft.blocks[hash] = tb
+
+	return tb
+}
+
+func (ft *finalityTracker) removeOldest() {
+	var oldest *trackedBlock
+	for _, tb := range ft.blocks {
+		if oldest == nil || tb.nonce < oldest.nonce {
+			oldest = tb
+		}
+	}
+
+	log.Warn("finalityTracker: too many blocks wait to be finalized, the oldest one will not be marked as final",
+		"hash", oldest.hash, "nonce", oldest.nonce)
+	delete(ft.blocks, oldest.hash)
+}
+
+// blocksUntil returns the block with the provided hash together with all the tracked blocks that have a lower or
+// equal nonce, sorted by nonce. Nothing is returned if the block is not tracked
+func (ft *finalityTracker) blocksUntil(headerHash []byte) []*trackedBlock {
+	ft.mutBlocks.Lock()
+	defer ft.mutBlocks.Unlock()
+
+	finalBlock, found := ft.blocks[hex.EncodeToString(headerHash)]
+	if !found {
+		return nil
+	}
+
+	blocks := make([]*trackedBlock, 0)
+	for _, tb := range ft.blocks {
+		if tb.nonce <= finalBlock.nonce {
+			blocks = append(blocks, tb)
+		}
+	}
+
+	sort.Slice(blocks, func(i, j int) bool {
+		return blocks[i].nonce < blocks[j].nonce
+	})
+
+	return blocks
+}
+
+func (ft *finalityTracker) remove(blocks ...*trackedBlock) {
+	ft.mutBlocks.Lock()
+	defer ft.mutBlocks.Unlock()
+
+	for _, tb := range blocks {
+		delete(ft.blocks, tb.hash)
+	}
+}
+
+func (ft *finalityTracker) removeBlock(headerHash []byte) {
+	ft.mutBlocks.Lock()
+	defer ft.mutBlocks.Unlock()
+
+	delete(ft.blocks, hex.EncodeToString(headerHash))
+}
@@ -0,0 +1,48 @@
+package process
+
+import (
+	"encoding/hex"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFinalityTracker_BlocksUntilShouldReturnOlderBlocksSortedByNonce(t *testing.T) {
+	t.Parallel()
+
+	ft := newFinalityTracker()
+	ft.addBlock([]byte("h3"), 3)
+	ft.addBlock([]byte("h1"), 1)
+	ft.addResults([]byte("h1"), 1, []string{"tx1"}, nil)
+	ft.addResults([]byte("h2"), 2, nil, []string{"scr2"})
+	ft.addResults([]byte("h2"), 2, nil, nil)
+
+	require.Nil(t, ft.blocksUntil([]byte("unknown")))
+
+	blocks := ft.blocksUntil([]byte("h2"))
+	require.Equal(t, []*trackedBlock{
+		{hash: hex.EncodeToString([]byte("h1")), nonce: 1, hasBlock: true, txHashes: []string{"tx1"}},
+		{hash: hex.EncodeToString([]byte("h2")), nonce: 2, scrHashes: []string{"scr2"}},
+	}, blocks)
+
+	ft.remove(blocks...)
+	require.Nil(t, ft.blocksUntil([]byte("h2")))
+	require.Len(t, ft.blocksUntil([]byte("h3")), 1)
+
+	ft.removeBlock([]byte("h3"))
+	require.Empty(t, ft.blocks)
+}
+
+func TestFinalityTracker_ShouldDropOldestBlockWhenFull(t *testing.T) {
+	t.Parallel()
+
+	ft := newFinalityTracker()
+	for nonce := uint64(1); nonce <= maxTrackedBlocks+1; nonce++ {
+		ft.addBlock([]byte(fmt.Sprintf("h%d", nonce)), nonce)
+	}
+
+	require.Len(t, ft.blocks, maxTrackedBlocks)
+	require.Nil(t, ft.blocksUntil([]byte("h1")))
+	require.Len(t, ft.blocksUntil([]byte("h2")), 1)
+}
@@ -1,46 +1,27 @@
 package process
 
 import (
-	"bytes"
-
 	coreData "github.com/ME-MotherEarth/me-core/data"
 	"github.com/ME-MotherEarth/me-core/data/block"
 	"github.com/ME-MotherEarth/me-core/data/indexer"
 	"github.com/ME-MotherEarth/me-elastic-indexer/data"
 	"github.com/ME-MotherEarth/me-elastic-indexer/process/tokeninfo"
-	"github.com/elastic/go-elasticsearch/v7/esapi"
 )
 
-// DatabaseClientHandler defines the actions that a component that handles requests should do
-type DatabaseClientHandler interface {
-	DoRequest(req *esapi.IndexRequest) error
-	DoBulkRequest(buff *bytes.Buffer, index string) error
-	DoQueryRemove(index string, buff *bytes.Buffer) error
-	DoMultiGet(ids []string, index string, withSource bool, res interface{}) error
-	DoScrollRequest(index string, body []byte, withSource bool, handlerFunc func(responseBytes []byte) error) error
-	DoCountRequest(index string, body []byte) (uint64, error)
-
-	CheckAndCreateIndex(index string) error
-	CheckAndCreateAlias(alias string, index string) error
-	CheckAndCreateTemplate(templateName string, template *bytes.Buffer) error
-	CheckAndCreatePolicy(policyName string, policy *bytes.Buffer) error
-
-	IsInterfaceNil() bool
-}
-
 // DBAccountHandler defines the actions that an accounts' handler should do
 type DBAccountHandler interface {
 	GetAccounts(alteredAccounts data.AlteredAccountsHandler) ([]*data.Account, []*data.AccountMECT)
 	PrepareRegularAccountsMap(timestamp uint64, accounts []*data.Account) map[string]*data.AccountInfo
 	PrepareAccountsMapMECT(timestamp uint64, accounts []*data.AccountMECT, tagsCount data.CountTags) (map[string]*data.AccountInfo, data.TokensHandler)
 	PrepareAccountsHistory(timestamp uint64, accounts map[string]*data.AccountInfo) map[string]*data.AccountBalanceHistory
+	PutBalanceNumWithDecimalsInAccountsMECT(accountsMECT map[string]*data.AccountInfo, tokensDecimals map[string]uint64)
 	PutTokenMedataDataInTokens(tokensData []*data.TokenInfo)
 
-	SerializeAccountsHistory(accounts map[string]*data.AccountBalanceHistory, buffSlice *data.BufferSlice, index string) error
-	SerializeAccounts(accounts map[string]*data.AccountInfo, buffSlice *data.BufferSlice, index string) error
-	SerializeAccountsMECT(accounts map[string]*data.AccountInfo, updateNFTData []*data.NFTDataUpdate, buffSlice *data.BufferSlice, index string) error
-	SerializeNFTCreateInfo(tokensInfo []*data.TokenInfo, buffSlice *data.BufferSlice, index string) error
-	SerializeTypeForProvidedIDs(ids []string, tokenType string, buffSlice *data.BufferSlice, index string) error
+	SerializeAccountsHistory(accounts map[string]*data.AccountBalanceHistory, docs *data.DocumentsSlice, index string) error
+	SerializeAccounts(accounts map[string]*data.AccountInfo, docs *data.DocumentsSlice, index string) error
+	SerializeAccountsMECT(accounts map[string]*data.AccountInfo, updateNFTData []*data.NFTDataUpdate, docs *data.DocumentsSlice, index string) error
+	SerializeNFTCreateInfo(tokensInfo []*data.TokenInfo, docs *data.DocumentsSlice, index string) error
+	SerializeTypeForProvidedIDs(ids []string, tokenType string, docs *data.DocumentsSlice, index string) error
 }
 
 // DBBlockHandler defines the actions that a block handler should do
@@ -56,8 +37,8 @@ type DBBlockHandler interface {
 	) (*data.Block, error)
 	ComputeHeaderHash(header coreData.HeaderHandler) ([]byte, error)
 
-	SerializeEpochInfoData(header coreData.HeaderHandler, buffSlice *data.BufferSlice, index string) error
-	SerializeBlock(elasticBlock *data.Block, buffSlice *data.BufferSlice, index string) error
+	SerializeEpochInfoData(header coreData.HeaderHandler, docs *data.DocumentsSlice, index string) error
+	SerializeBlock(elasticBlock *data.Block, docs *data.DocumentsSlice, index string) error
 }
 
 // DBTransactionsHandler defines the actions that a transactions handler should do
@@ -69,10 +50,10 @@ type DBTransactionsHandler interface {
 	) *data.PreparedResults
 	GetHexEncodedHashesForRemove(header coreData.HeaderHandler, body *block.Body) ([]string, []string)
 
-	SerializeReceipts(receipts []*data.Receipt, buffSlice *data.BufferSlice, index string) error
-	SerializeTransactions(transactions []*data.Transaction, txHashStatus map[string]string, selfShardID uint32, buffSlice *data.BufferSlice, index string) error
-	SerializeTransactionWithRefund(txs map[string]*data.Transaction, txHashRefund map[string]*data.RefundData, buffSlice *data.BufferSlice, index string) error
-	SerializeScResults(scResults []*data.ScResult, buffSlice *data.BufferSlice, index string) error
+	SerializeReceipts(receipts []*data.Receipt, docs *data.DocumentsSlice, index string) error
+	SerializeTransactions(transactions []*data.Transaction, txHashStatus map[string]string, selfShardID uint32, docs *data.DocumentsSlice, index string) error
+	SerializeTransactionWithRefund(txs map[string]*data.Transaction, txHashRefund map[string]*data.RefundData, docs *data.DocumentsSlice, index string) error
+	SerializeScResults(scResults []*data.ScResult, docs *data.DocumentsSlice, index string) error
 }
 
 // DBMiniblocksHandler defines the actions that a miniblocks handler should do
@@ -80,38 +61,42 @@ type DBMiniblocksHandler interface {
 	PrepareDBMiniblocks(header coreData.HeaderHandler, body *block.Body) []*data.Miniblock
 	GetMiniblocksHashesHexEncoded(header coreData.HeaderHandler, body *block.Body) []string
 
-	SerializeBulkMiniBlocks(bulkMbs []*data.Miniblock, mbsInDB map[string]bool, buffSlice *data.BufferSlice, index string)
+	SerializeBulkMiniBlocks(bulkMbs []*data.Miniblock, mbsInDB map[string]bool, docs *data.DocumentsSlice, index string)
 }
 
 // DBStatisticsHandler defines the actions that a database statistics handler should do
 type DBStatisticsHandler interface {
-	SerializeRoundsInfo(roundsInfo []*data.RoundInfo) *bytes.Buffer
+	SerializeRoundsInfo(roundsInfo []*data.RoundInfo, docs *data.DocumentsSlice, index string)
 }
 
 // DBValidatorsHandler defines the actions that a validators handler should do
 type DBValidatorsHandler interface {
 	PrepareValidatorsPublicKeys(shardValidatorsPubKeys [][]byte) *data.ValidatorsPublicKeys
-	SerializeValidatorsPubKeys(validatorsPubKeys *data.ValidatorsPublicKeys) (*bytes.Buffer, error)
-	SerializeValidatorsRating(index string, validatorsRatingInfo []*data.ValidatorRatingInfo) ([]*bytes.Buffer, error)
+	SerializeValidatorsPubKeys(shardID uint32, epoch uint32, validatorsPubKeys *data.ValidatorsPublicKeys, docs *data.DocumentsSlice, index string)
+	SerializeValidatorsRating(ratingIndex string, validatorsRatingInfo []*data.ValidatorRatingInfo, docs *data.DocumentsSlice, index string)
 }
 
 // DBLogsAndEventsHandler defines the actions that a logs and events handler should do
 type DBLogsAndEventsHandler interface {
 	PrepareLogsForDB(logsAndEvents []*coreData.LogData, timestamp uint64) []*data.Logs
+	PrepareEventsForDB(logsAndEvents []*coreData.LogData, timestamp uint64) []*data.LogEvent
 	ExtractDataFromLogs(
 		logsAndEvents []*coreData.LogData,
 		preparedResults *data.PreparedResults,
 		timestamp uint64,
 	) *data.PreparedLogsResults
-
-	SerializeLogs(logs []*data.Logs, buffSlice *data.BufferSlice, index string) error
-	SerializeSCDeploys(deploysInfo map[string]*data.ScDeployInfo, buffSlice *data.BufferSlice, index string) error
-	SerializeTokens(tokens []*data.TokenInfo, updateNFTData []*data.NFTDataUpdate, buffSlice *data.BufferSlice, index string) error
-	SerializeDelegators(delegators map[string]*data.Delegator, buffSlice *data.BufferSlice, index string) error
-	SerializeSupplyData(tokensSupply data.TokensHandler, buffSlice *data.BufferSlice, index string) error
+	PutAmountNumInTransfers(transfers []*data.Transfer, tokensDecimals map[string]uint64)
+
+	SerializeLogs(logs []*data.Logs, docs *data.DocumentsSlice, index string) error
+	SerializeEvents(events []*data.LogEvent, docs *data.DocumentsSlice, index string) error
+	SerializeTransfers(transfers []*data.Transfer, docs *data.DocumentsSlice, index string) error
+	SerializeSCDeploys(deploysInfo map[string]*data.ScDeployInfo, docs *data.DocumentsSlice, index string) error
+	SerializeTokens(tokens []*data.TokenInfo, updateNFTData []*data.NFTDataUpdate, docs *data.DocumentsSlice, index string) error
+	SerializeDelegators(delegators map[string]*data.Delegator, docs *data.DocumentsSlice, index string) error
+	SerializeSupplyData(tokensSupply data.TokensHandler, docs *data.DocumentsSlice, index string) error
 	SerializeRolesData(
 		tokenRolesAndProperties *tokeninfo.TokenRolesAndProperties,
-		buffSlice *data.BufferSlice,
+		docs *data.DocumentsSlice,
 		index string,
 	) error
 }
@@ -119,5 +104,13 @@ type DBLogsAndEventsHandler interface {
 // OperationsHandler defines the actions that an operations' handler should do
 type OperationsHandler interface {
 	ProcessTransactionsAndSCRs(txs []*data.Transaction, scrs []*data.ScResult) ([]*data.Transaction, []*data.ScResult)
-	SerializeSCRs(scrs []*data.ScResult, buffSlice *data.BufferSlice, index string) error
+	SerializeSCRs(scrs []*data.ScResult, docs *data.DocumentsSlice, index string) error
+}
+
+// EventsPublisher defines the actions that a component that publishes the indexed data should do
+type EventsPublisher interface {
+	PublishBlock(headerHash []byte, header coreData.HeaderHandler, block *data.Block) error
+	PublishTransactions(headerHash []byte, header coreData.HeaderHandler, preparedTxs *data.PreparedBlockTransactions) error
+	PublishRevert(headerHash []byte, header coreData.HeaderHandler) error
+	IsInterfaceNil() bool
 }
@@ -6,6 +6,7 @@ import (
 
 	"github.com/ME-MotherEarth/me-core/core"
 	indexer "github.com/ME-MotherEarth/me-elastic-indexer"
+	"github.com/ME-MotherEarth/me-elastic-indexer/converters"
 	"github.com/ME-MotherEarth/me-elastic-indexer/data"
 )
 
@@ -77,10 +78,11 @@ func (dp *delegatorsProc) processEvent(args *argsProcessEvent) argOutputProcessE
 	}
 
 	delegator := &data.Delegator{
-		Address:        dp.pubkeyConverter.Encode(args.event.GetAddress()),
-		Contract:       contractAddr,
-		ActiveStake:    activeStake.String(),
-		ActiveStakeNum: dp.balanceConverter.ComputeBalanceAsFloat(activeStake),
+		Address:             dp.pubkeyConverter.Encode(args.event.GetAddress()),
+		Contract:            contractAddr,
+		ActiveStake:         activeStake.String(),
+		ActiveStakeNum:      dp.balanceConverter.ComputeBalanceAsFloat(activeStake),
+		ActiveStakeSortable: converters.BigIntToSortableString(activeStake),
 	}
 
 	if eventIdentifierStr == withdrawFunc && len(topics) >= minNumTopicsDelegators+1 {
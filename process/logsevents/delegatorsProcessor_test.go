@@ -32,10 +32,11 @@ func TestDelegatorsProcessor_ProcessEvent(t *testing.T) {
 	res := delegatorsProcessor.processEvent(args)
 	require.True(t, res.processed)
 	require.Equal(t, &data.Delegator{
-		Address:        "61646472",
-		Contract:       "636f6e7472616374",
-		ActiveStakeNum: 0.1,
-		ActiveStake:    "1000000000",
+		Address:             "61646472",
+		Contract:            "636f6e7472616374",
+		ActiveStakeNum:      0.1,
+		ActiveStake:         "1000000000",
+		ActiveStakeSortable: converters.BigIntToSortableString(big.NewInt(1000000000)),
 	}, res.delegator)
 }
 
@@ -59,11 +60,12 @@ func TestDelegatorProcessor_WithdrawWithDelete(t *testing.T) {
 	res := delegatorsProcessor.processEvent(args)
 	require.True(t, res.processed)
 	require.Equal(t, &data.Delegator{
-		Address:        "61646472",
-		Contract:       "636f6e7472616374",
-		ActiveStakeNum: 0,
-		ActiveStake:    "0",
-		ShouldDelete:   true,
+		Address:             "61646472",
+		Contract:            "636f6e7472616374",
+		ActiveStakeNum:      0,
+		ActiveStake:         "0",
+		ActiveStakeSortable: converters.BigIntToSortableString(big.NewInt(0)),
+		ShouldDelete:        true,
 	}, res.delegator)
 }
 
@@ -0,0 +1,124 @@
+package logsevents
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+
+	"github.com/ME-MotherEarth/me-core/core/check"
+	coreData "github.com/ME-MotherEarth/me-core/data"
+	elasticIndexer "github.com/ME-MotherEarth/me-elastic-indexer"
+	"github.com/ME-MotherEarth/me-elastic-indexer/data"
+)
+
+// customIndexNameRegex matches the names that are valid both as elasticsearch indices and as PostgreSQL tables
+var customIndexNameRegex = regexp.MustCompile("^[a-z][a-z0-9_]*$")
+
+// EventProcessor defines what an event processor added by an integrator to the built-in ones should do. Every log
+// event is passed to all the added event processors, which can emit documents in the indices they declare. The same
+// event processor is used by the processor of the finalized data too, so it has to be safe for concurrent use
+type EventProcessor interface {
+	ProcessEvent(args *ArgsProcessEvent) *OutputProcessEvent
+	Indices() []*CustomIndex
+	IsInterfaceNil() bool
+}
+
+// ArgsProcessEvent holds the log event passed to an event processor added by an integrator
+type ArgsProcessEvent struct {
+	Event            coreData.EventHandler
+	TxHashHexEncoded string
+	LogAddress       []byte
+	Timestamp        uint64
+	SelfShardID      uint32
+}
+
+// OutputProcessEvent holds the documents emitted by an event processor added by an integrator. The documents are
+// written only in the indices declared by the event processor, when they are enabled
+type OutputProcessEvent struct {
+	Documents []*data.Document
+}
+
+// CustomIndex is an index declared by an event processor added by an integrator. The template has to match the
+// indices named after it, e.g. "myevents-*", as the indexer creates the "myevents-000001" index behind the "myevents"
+// alias. The documents of a revertible index are recorded in the journal of the block, so they are restored when the
+// block is reverted, while the ones of the other indices are kept
+type CustomIndex struct {
+	Name       string
+	Template   *bytes.Buffer
+	Revertible bool
+}
+
+// GetCustomIndices returns the indices declared by the provided event processors, checking that every index has a
+// valid name and a template and that it is declared only once
+func GetCustomIndices(eventProcessors []EventProcessor) ([]*CustomIndex, error) {
+	customIndices := make([]*CustomIndex, 0)
+	declared := make(map[string]struct{})
+	for _, eventProcessor := range eventProcessors {
+		if check.IfNil(eventProcessor) {
+			return nil, elasticIndexer.ErrNilEventProcessor
+		}
+
+		for _, customIndex := range eventProcessor.Indices() {
+			err := checkCustomIndex(customIndex)
+			if err != nil {
+				return nil, err
+			}
+
+			_, alreadyDeclared := declared[customIndex.Name]
+			if alreadyDeclared {
+				return nil, fmt.Errorf("%w: %s", elasticIndexer.ErrCustomIndexAlreadyDeclared, customIndex.Name)
+			}
+			declared[customIndex.Name] = struct{}{}
+
+			customIndices = append(customIndices, customIndex)
+		}
+	}
+
+	return customIndices, nil
+}
+
+func checkCustomIndex(customIndex *CustomIndex) error {
+	if customIndex == nil {
+		return fmt.Errorf("%w: nil index", elasticIndexer.ErrInvalidCustomIndex)
+	}
+	if !customIndexNameRegex.MatchString(customIndex.Name) {
+		return fmt.Errorf("%w: invalid name %q", elasticIndexer.ErrInvalidCustomIndex, customIndex.Name)
+	}
+	if customIndex.Template == nil {
+		return fmt.Errorf("%w: index %s has no template", elasticIndexer.ErrInvalidCustomIndex, customIndex.Name)
+	}
+
+	return nil
+}
+
+// processCustomEvent will pass the event to all the event processors added by an integrator, keeping only the
+// documents of the indices they declared
+func (lep *logsAndEventsProcessor) processCustomEvent(logHashHexEncoded string, logAddress []byte, event coreData.EventHandler) {
+	for _, eventProcessor := range lep.customEventProcessors {
+		res := eventProcessor.ProcessEvent(&ArgsProcessEvent{
+			Event:            event,
+			TxHashHexEncoded: logHashHexEncoded,
+			LogAddress:       logAddress,
+			Timestamp:        lep.logsData.timestamp,
+			SelfShardID:      lep.selfShardID,
+		})
+		if res == nil {
+			continue
+		}
+
+		for _, document := range res.Documents {
+			if document == nil {
+				continue
+			}
+
+			_, isDeclared := lep.customIndices[document.Index]
+			if !isDeclared {
+				log.Warn("logsAndEventsProcessor.processCustomEvent: document of an index that was not declared",
+					"index", document.Index, "tx hash", logHashHexEncoded)
+				continue
+			}
+
+			lep.logsData.customDocuments = append(lep.logsData.customDocuments, document)
+		}
+	}
+}
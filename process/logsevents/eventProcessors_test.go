@@ -0,0 +1,146 @@
+package logsevents
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	coreData "github.com/ME-MotherEarth/me-core/data"
+	"github.com/ME-MotherEarth/me-core/data/transaction"
+	elasticIndexer "github.com/ME-MotherEarth/me-elastic-indexer"
+	"github.com/ME-MotherEarth/me-elastic-indexer/data"
+	"github.com/stretchr/testify/require"
+)
+
+type eventProcessorStub struct {
+	indices            []*CustomIndex
+	processEventCalled func(args *ArgsProcessEvent) *OutputProcessEvent
+}
+
+func (eps *eventProcessorStub) ProcessEvent(args *ArgsProcessEvent) *OutputProcessEvent {
+	if eps.processEventCalled != nil {
+		return eps.processEventCalled(args)
+	}
+
+	return nil
+}
+
+func (eps *eventProcessorStub) Indices() []*CustomIndex {
+	return eps.indices
+}
+
+func (eps *eventProcessorStub) IsInterfaceNil() bool {
+	return eps == nil
+}
+
+func createCustomIndex(name string) *CustomIndex {
+	return &CustomIndex{
+		Name:     name,
+		Template: bytes.NewBufferString(`{"index_patterns":["` + name + `-*"]}`),
+	}
+}
+
+func TestGetCustomIndices(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil event processor should error", func(t *testing.T) {
+		t.Parallel()
+
+		var nilProcessor *eventProcessorStub
+		_, err := GetCustomIndices([]EventProcessor{nilProcessor})
+		require.Equal(t, elasticIndexer.ErrNilEventProcessor, err)
+	})
+	t.Run("invalid indices should error", func(t *testing.T) {
+		t.Parallel()
+
+		invalidIndices := []*CustomIndex{
+			nil,
+			createCustomIndex(""),
+			createCustomIndex("My-Events"),
+			{Name: "myevents"},
+		}
+		for _, customIndex := range invalidIndices {
+			_, err := GetCustomIndices([]EventProcessor{&eventProcessorStub{indices: []*CustomIndex{customIndex}}})
+			require.True(t, errors.Is(err, elasticIndexer.ErrInvalidCustomIndex))
+		}
+	})
+	t.Run("index declared twice should error", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := GetCustomIndices([]EventProcessor{
+			&eventProcessorStub{indices: []*CustomIndex{createCustomIndex("myevents")}},
+			&eventProcessorStub{indices: []*CustomIndex{createCustomIndex("myevents")}},
+		})
+		require.True(t, errors.Is(err, elasticIndexer.ErrCustomIndexAlreadyDeclared))
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		myEvents := createCustomIndex("myevents")
+		myOrders := createCustomIndex("my_orders")
+		customIndices, err := GetCustomIndices([]EventProcessor{
+			&eventProcessorStub{indices: []*CustomIndex{myEvents}},
+			&eventProcessorStub{indices: []*CustomIndex{myOrders}},
+		})
+		require.Nil(t, err)
+		require.Equal(t, []*CustomIndex{myEvents, myOrders}, customIndices)
+	})
+}
+
+func TestNewLogsAndEventsProcessor_InvalidEventProcessorsShouldError(t *testing.T) {
+	t.Parallel()
+
+	args := createMockArgs()
+	args.EventProcessors = []EventProcessor{&eventProcessorStub{indices: []*CustomIndex{createCustomIndex("My-Events")}}}
+	proc, err := NewLogsAndEventsProcessor(args)
+	require.Nil(t, proc)
+	require.True(t, errors.Is(err, elasticIndexer.ErrInvalidCustomIndex))
+}
+
+func TestLogsAndEventsProcessor_ExtractDataFromLogsShouldKeepTheDocumentsOfTheDeclaredIndices(t *testing.T) {
+	t.Parallel()
+
+	receivedEvents := make([]*ArgsProcessEvent, 0)
+	args := createMockArgs()
+	args.EventProcessors = []EventProcessor{
+		&eventProcessorStub{
+			indices: []*CustomIndex{createCustomIndex("myevents")},
+			processEventCalled: func(args *ArgsProcessEvent) *OutputProcessEvent {
+				receivedEvents = append(receivedEvents, args)
+				if string(args.Event.GetIdentifier()) != "myEvent" {
+					return nil
+				}
+
+				return &OutputProcessEvent{
+					Documents: []*data.Document{
+						{Index: "myevents", ID: args.TxHashHexEncoded, Action: data.ActionIndex, Body: "event"},
+						{Index: "undeclared", ID: args.TxHashHexEncoded, Action: data.ActionIndex, Body: "event"},
+						nil,
+					},
+				}
+			},
+		},
+	}
+	proc, _ := NewLogsAndEventsProcessor(args)
+
+	logsAndEvents := []*coreData.LogData{
+		{
+			TxHash: "h1",
+			LogHandler: &transaction.Log{
+				Address: []byte("contract"),
+				Events: []*transaction.Event{
+					{Address: []byte("contract"), Identifier: []byte("myEvent")},
+					{Address: []byte("contract"), Identifier: []byte("otherEvent")},
+				},
+			},
+		},
+	}
+	res := proc.ExtractDataFromLogs(logsAndEvents, &data.PreparedResults{AlteredAccts: data.NewAlteredAccounts()}, 1000)
+
+	require.Len(t, receivedEvents, 2)
+	require.Equal(t, []byte("contract"), receivedEvents[0].LogAddress)
+	require.Equal(t, uint64(1000), receivedEvents[0].Timestamp)
+	require.Equal(t, []*data.Document{
+		{Index: "myevents", ID: "6831", Action: data.ActionIndex, Body: "event"},
+	}, res.CustomDocuments)
+}
@@ -0,0 +1,127 @@
+package logsevents
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ME-MotherEarth/me-core/core"
+	"github.com/ME-MotherEarth/me-core/core/check"
+	coreData "github.com/ME-MotherEarth/me-core/data"
+	"github.com/ME-MotherEarth/me-elastic-indexer/converters"
+	"github.com/ME-MotherEarth/me-elastic-indexer/data"
+)
+
+const numTopicsWithValue = 3
+
+// tokenEventsIdentifiers holds the identifiers of the events whose topics are the token, the nonce and the value. The
+// events set to true also have the address of the receiver, or of the wiped account, as the fourth topic
+var tokenEventsIdentifiers = map[string]bool{
+	core.BuiltInFunctionMECTTransfer:            true,
+	core.BuiltInFunctionMECTNFTTransfer:         true,
+	core.BuiltInFunctionMultiMECTNFTTransfer:    true,
+	core.BuiltInFunctionMECTWipe:                true,
+	core.BuiltInFunctionMECTBurn:                false,
+	core.BuiltInFunctionMECTLocalMint:           false,
+	core.BuiltInFunctionMECTLocalBurn:           false,
+	core.BuiltInFunctionMECTNFTCreate:           false,
+	core.BuiltInFunctionMECTNFTAddQuantity:      false,
+	core.BuiltInFunctionMECTNFTBurn:             false,
+	core.BuiltInFunctionMECTNFTAddURI:           false,
+	core.BuiltInFunctionMECTNFTUpdateAttributes: false,
+}
+
+// PrepareEventsForDB will prepare a document for every event of the provided logs. The ID of an event is made of the
+// hash of its log, the shard that indexed it and its order in the log, so it is the same when the block is indexed
+// again and the events of a reverted block can be deleted
+func (lep *logsAndEventsProcessor) PrepareEventsForDB(
+	logsAndEvents []*coreData.LogData,
+	timestamp uint64,
+) []*data.LogEvent {
+	events := make([]*data.LogEvent, 0, len(logsAndEvents))
+
+	for _, txLog := range logsAndEvents {
+		if txLog == nil || check.IfNil(txLog.LogHandler) {
+			continue
+		}
+
+		events = append(events, lep.prepareEventsForDB(txLog.TxHash, txLog.LogHandler, timestamp)...)
+	}
+
+	return events
+}
+
+func (lep *logsAndEventsProcessor) prepareEventsForDB(
+	id string,
+	logHandler coreData.LogHandler,
+	timestamp uint64,
+) []*data.LogEvent {
+	encodedID := hex.EncodeToString([]byte(id))
+	originalTxHash := ""
+	scr, ok := lep.logsData.scrsMap[encodedID]
+	if ok {
+		originalTxHash = scr.OriginalTxHash
+	}
+
+	logAddress := lep.pubKeyConverter.Encode(logHandler.GetAddress())
+	events := logHandler.GetLogEvents()
+	eventsDB := make([]*data.LogEvent, 0, len(events))
+	for idx, event := range events {
+		if check.IfNil(event) {
+			continue
+		}
+
+		eventDB := &data.LogEvent{
+			ID:             fmt.Sprintf("%s-%d-%d", encodedID, lep.selfShardID, idx),
+			TxHash:         encodedID,
+			OriginalTxHash: originalTxHash,
+			LogAddress:     logAddress,
+			Address:        lep.pubKeyConverter.Encode(event.GetAddress()),
+			Identifier:     string(event.GetIdentifier()),
+			Topics:         encodeTopics(event.GetTopics()),
+			Data:           hex.EncodeToString(event.GetData()),
+			Order:          idx,
+			ShardID:        lep.selfShardID,
+			Timestamp:      time.Duration(timestamp),
+			Decoded:        lep.abiDecoder.DecodeEvent(event.GetAddress(), event.GetTopics(), event.GetData()),
+		}
+		lep.decodeTopics(eventDB, event)
+
+		eventsDB = append(eventsDB, eventDB)
+	}
+
+	return eventsDB
+}
+
+func encodeTopics(topics [][]byte) []string {
+	encodedTopics := make([]string, 0, len(topics))
+	for _, topic := range topics {
+		encodedTopics = append(encodedTopics, hex.EncodeToString(topic))
+	}
+
+	return encodedTopics
+}
+
+// decodeTopics will set the token, nonce, value and receiver of the events that operate on tokens
+func (lep *logsAndEventsProcessor) decodeTopics(eventDB *data.LogEvent, event coreData.EventHandler) {
+	hasReceiver, isTokenEvent := tokenEventsIdentifiers[eventDB.Identifier]
+	topics := event.GetTopics()
+	if !isTokenEvent || len(topics) < numTopicsWithValue {
+		return
+	}
+
+	nonce := big.NewInt(0).SetBytes(topics[1]).Uint64()
+	eventDB.Token = string(topics[0])
+	eventDB.TokenIdentifier = converters.ComputeTokenIdentifier(eventDB.Token, nonce)
+	if nonce == 0 {
+		// the fungible tokens are identified by the token alone
+		eventDB.TokenIdentifier = eventDB.Token
+	}
+	eventDB.Nonce = nonce
+	eventDB.Value = big.NewInt(0).SetBytes(topics[2]).String()
+
+	if hasReceiver && len(topics) >= numTopicsWithReceiverAddress {
+		eventDB.Receiver = lep.pubKeyConverter.Encode(topics[3])
+	}
+}
@@ -0,0 +1,172 @@
+package logsevents
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ME-MotherEarth/me-core/core"
+	coreData "github.com/ME-MotherEarth/me-core/data"
+	"github.com/ME-MotherEarth/me-core/data/transaction"
+	"github.com/ME-MotherEarth/me-elastic-indexer/data"
+	"github.com/ME-MotherEarth/me-elastic-indexer/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogsAndEventsProcessor_PrepareEventsForDB(t *testing.T) {
+	t.Parallel()
+
+	logsAndEvents := []*coreData.LogData{
+		nil,
+		{TxHash: "wrong"},
+		{
+			TxHash: "txHash",
+			LogHandler: &transaction.Log{
+				Address: []byte("address"),
+				Events: []*transaction.Event{
+					{
+						Address:    []byte("addr"),
+						Identifier: []byte(core.BuiltInFunctionMECTNFTTransfer),
+						Topics:     [][]byte{[]byte("NFT-abcd"), big.NewInt(2).Bytes(), big.NewInt(1).Bytes(), []byte("receiver")},
+						Data:       []byte("data"),
+					},
+					nil,
+					{
+						Address:    []byte("addr"),
+						Identifier: []byte(core.BuiltInFunctionMECTLocalMint),
+						Topics:     [][]byte{[]byte("TKN-abcd"), big.NewInt(0).Bytes(), big.NewInt(1000).Bytes()},
+					},
+					{
+						Address:    []byte("contract"),
+						Identifier: []byte("myEvent"),
+						Topics:     [][]byte{[]byte("topic")},
+					},
+				},
+			},
+		},
+	}
+
+	args := createMockArgs()
+	args.ShardCoordinator = &mock.ShardCoordinatorMock{SelfID: 1}
+	proc, _ := NewLogsAndEventsProcessor(args)
+
+	_ = proc.ExtractDataFromLogs(nil, &data.PreparedResults{ScResults: []*data.ScResult{
+		{
+			Hash:           "747848617368",
+			OriginalTxHash: "originalHash",
+		},
+	}}, 1234)
+
+	events := proc.PrepareEventsForDB(logsAndEvents, 1234)
+	require.Equal(t, []*data.LogEvent{
+		{
+			ID:              "747848617368-1-0",
+			TxHash:          "747848617368",
+			OriginalTxHash:  "originalHash",
+			LogAddress:      "61646472657373",
+			Address:         "61646472",
+			Identifier:      core.BuiltInFunctionMECTNFTTransfer,
+			Topics:          []string{"4e46542d61626364", "02", "01", "7265636569766572"},
+			Data:            "64617461",
+			Order:           0,
+			ShardID:         1,
+			Timestamp:       time.Duration(1234),
+			Token:           "NFT-abcd",
+			TokenIdentifier: "NFT-abcd-02",
+			Nonce:           2,
+			Value:           "1",
+			Receiver:        "7265636569766572",
+		},
+		{
+			ID:              "747848617368-1-2",
+			TxHash:          "747848617368",
+			OriginalTxHash:  "originalHash",
+			LogAddress:      "61646472657373",
+			Address:         "61646472",
+			Identifier:      core.BuiltInFunctionMECTLocalMint,
+			Topics:          []string{"544b4e2d61626364", "", "03e8"},
+			Order:           2,
+			ShardID:         1,
+			Timestamp:       time.Duration(1234),
+			Token:           "TKN-abcd",
+			TokenIdentifier: "TKN-abcd",
+			Value:           "1000",
+		},
+		{
+			ID:             "747848617368-1-3",
+			TxHash:         "747848617368",
+			OriginalTxHash: "originalHash",
+			LogAddress:     "61646472657373",
+			Address:        "636f6e7472616374",
+			Identifier:     "myEvent",
+			Topics:         []string{"746f706963"},
+			Order:          3,
+			ShardID:        1,
+			Timestamp:      time.Duration(1234),
+		},
+	}, events)
+}
+
+func TestLogsAndEventsProcessor_PrepareEventsForDBShouldNotDecodeMissingTopics(t *testing.T) {
+	t.Parallel()
+
+	proc, _ := NewLogsAndEventsProcessor(createMockArgs())
+	_ = proc.ExtractDataFromLogs(nil, &data.PreparedResults{}, 1234)
+
+	events := proc.PrepareEventsForDB([]*coreData.LogData{
+		{
+			TxHash: "txHash",
+			LogHandler: &transaction.Log{
+				Address: []byte("address"),
+				Events: []*transaction.Event{
+					{
+						Address:    []byte("addr"),
+						Identifier: []byte(core.BuiltInFunctionMECTTransfer),
+						Topics:     [][]byte{[]byte("TKN-abcd"), big.NewInt(0).Bytes()},
+					},
+				},
+			},
+		},
+	}, 1234)
+	require.Len(t, events, 1)
+	require.Equal(t, core.BuiltInFunctionMECTTransfer, events[0].Identifier)
+	require.Empty(t, events[0].Token)
+	require.Empty(t, events[0].Value)
+	require.Empty(t, events[0].Receiver)
+}
+
+func TestLogsAndEventsProcessor_PrepareEventsForDBShouldSetDecodedEvent(t *testing.T) {
+	t.Parallel()
+
+	decoded := &data.DecodedData{ABI: "Adder", Name: "added"}
+	args := createMockArgs()
+	args.ABIDecoder = &mock.ABIDecoderStub{
+		DecodeEventCalled: func(contract []byte, topics [][]byte, eventData []byte) *data.DecodedData {
+			require.Equal(t, []byte("contract"), contract)
+			require.Equal(t, [][]byte{[]byte("added")}, topics)
+			require.Equal(t, []byte("data"), eventData)
+			return decoded
+		},
+	}
+	proc, _ := NewLogsAndEventsProcessor(args)
+	_ = proc.ExtractDataFromLogs(nil, &data.PreparedResults{}, 1234)
+
+	events := proc.PrepareEventsForDB([]*coreData.LogData{
+		{
+			TxHash: "txHash",
+			LogHandler: &transaction.Log{
+				Address: []byte("address"),
+				Events: []*transaction.Event{
+					{
+						Address:    []byte("contract"),
+						Identifier: []byte("added"),
+						Topics:     [][]byte{[]byte("added")},
+						Data:       []byte("data"),
+					},
+				},
+			},
+		},
+	}, 1234)
+	require.Len(t, events, 1)
+	require.Equal(t, decoded, events[0].Decoded)
+}
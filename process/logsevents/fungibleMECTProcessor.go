@@ -68,6 +68,7 @@ func (fep *fungibleMECTProcessor) processEvent(args *argsProcessEvent) argOutput
 		processed:       true,
 		receiver:        receiver,
 		receiverShardID: receiverShardID,
+		transfer:        prepareTransfer(args.event, fep.pubKeyConverter, fep.shardCoordinator),
 	}
 }
 
@@ -28,6 +28,7 @@ type argOutputProcessEvent struct {
 	delegator       *data.Delegator
 	processed       bool
 	updatePropNFT   *data.NFTDataUpdate
+	transfer        *data.Transfer
 }
 
 type eventsProcessor interface {
@@ -13,7 +13,9 @@ import (
 	"github.com/ME-MotherEarth/me-elastic-indexer/data"
 )
 
-// ArgsLogsAndEventsProcessor  holds all dependencies required to create new instances of logsAndEventsProcessor
+// ArgsLogsAndEventsProcessor  holds all dependencies required to create new instances of logsAndEventsProcessor. The
+// EventProcessors are added by integrators to the built-in ones, to index the events of their own smart contracts,
+// while the ABIDecoder decodes the events of the smart contracts that have an ABI
 type ArgsLogsAndEventsProcessor struct {
 	ShardCoordinator elasticIndexer.ShardCoordinator
 	PubKeyConverter  core.PubkeyConverter
@@ -21,12 +23,19 @@ type ArgsLogsAndEventsProcessor struct {
 	BalanceConverter elasticIndexer.BalanceConverter
 	Hasher           hashing.Hasher
 	TxFeeCalculator  elasticIndexer.FeesProcessorHandler
+	ABIDecoder       elasticIndexer.ABIDecoder
+	EventProcessors  []EventProcessor
 }
 
 type logsAndEventsProcessor struct {
-	hasher           hashing.Hasher
-	pubKeyConverter  core.PubkeyConverter
-	eventsProcessors []eventsProcessor
+	hasher                hashing.Hasher
+	pubKeyConverter       core.PubkeyConverter
+	abiDecoder            elasticIndexer.ABIDecoder
+	balanceConverter      elasticIndexer.BalanceConverter
+	eventsProcessors      []eventsProcessor
+	customEventProcessors []EventProcessor
+	customIndices         map[string]struct{}
+	selfShardID           uint32
 
 	logsData *logsData
 }
@@ -38,12 +47,26 @@ func NewLogsAndEventsProcessor(args *ArgsLogsAndEventsProcessor) (*logsAndEvents
 		return nil, err
 	}
 
+	customIndices, err := GetCustomIndices(args.EventProcessors)
+	if err != nil {
+		return nil, err
+	}
+	customIndicesMap := make(map[string]struct{}, len(customIndices))
+	for _, customIndex := range customIndices {
+		customIndicesMap[customIndex.Name] = struct{}{}
+	}
+
 	eventsProcessors := createEventsProcessors(args)
 
 	return &logsAndEventsProcessor{
-		pubKeyConverter:  args.PubKeyConverter,
-		eventsProcessors: eventsProcessors,
-		hasher:           args.Hasher,
+		pubKeyConverter:       args.PubKeyConverter,
+		abiDecoder:            args.ABIDecoder,
+		balanceConverter:      args.BalanceConverter,
+		eventsProcessors:      eventsProcessors,
+		customEventProcessors: args.EventProcessors,
+		customIndices:         customIndicesMap,
+		selfShardID:           args.ShardCoordinator.SelfId(),
+		hasher:                args.Hasher,
 	}, nil
 }
 
@@ -66,6 +89,9 @@ func checkArgsLogsAndEventsProcessor(args *ArgsLogsAndEventsProcessor) error {
 	if check.IfNil(args.TxFeeCalculator) {
 		return elasticIndexer.ErrNilTransactionFeeCalculator
 	}
+	if check.IfNil(args.ABIDecoder) {
+		return elasticIndexer.ErrNilABIDecoder
+	}
 
 	return nil
 }
@@ -114,6 +140,7 @@ func (lep *logsAndEventsProcessor) ExtractDataFromLogs(
 		events := txLog.LogHandler.GetLogEvents()
 		lep.processEvents(txLog.TxHash, txLog.LogHandler.GetAddress(), events)
 	}
+	lep.addTransfersFromDataField(preparedResults.Transactions, preparedResults.ScResults)
 
 	return &data.PreparedLogsResults{
 		Tokens:                  lep.logsData.tokens,
@@ -123,6 +150,8 @@ func (lep *logsAndEventsProcessor) ExtractDataFromLogs(
 		Delegators:              lep.logsData.delegators,
 		NFTsDataUpdates:         lep.logsData.nftsDataUpdates,
 		TokenRolesAndProperties: lep.logsData.tokenRolesAndProperties,
+		CustomDocuments:         lep.logsData.customDocuments,
+		Transfers:               lep.logsData.transfers,
 	}
 }
 
@@ -138,6 +167,8 @@ func (lep *logsAndEventsProcessor) processEvents(logHash string, logAddress []by
 
 func (lep *logsAndEventsProcessor) processEvent(logHash string, logAddress []byte, event coreData.EventHandler) {
 	logHashHexEncoded := hex.EncodeToString([]byte(logHash))
+	lep.processCustomEvent(logHashHexEncoded, logAddress, event)
+
 	for _, proc := range lep.eventsProcessors {
 		res := proc.processEvent(&argsProcessEvent{
 			event:                   event,
@@ -160,6 +191,9 @@ func (lep *logsAndEventsProcessor) processEvent(logHash string, logAddress []byt
 		if res.updatePropNFT != nil {
 			lep.logsData.nftsDataUpdates = append(lep.logsData.nftsDataUpdates, res.updatePropNFT)
 		}
+		if res.transfer != nil {
+			lep.logsData.addTransfer(logHashHexEncoded, res.transfer)
+		}
 
 		isEmptyIdentifier := res.identifier == ""
 		if isEmptyIdentifier && res.processed {
@@ -233,6 +267,7 @@ func (lep *logsAndEventsProcessor) prepareLogsForDB(
 			Topics:     event.GetTopics(),
 			Data:       event.GetData(),
 			Order:      idx,
+			Decoded:    lep.abiDecoder.DecodeEvent(event.GetAddress(), event.GetTopics(), event.GetData()),
 		})
 	}
 
@@ -24,6 +24,7 @@ func createMockArgs() *ArgsLogsAndEventsProcessor {
 		BalanceConverter: balanceConverter,
 		Hasher:           &mock.HasherMock{},
 		TxFeeCalculator:  &mock.EconomicsHandlerStub{},
+		ABIDecoder:       &mock.ABIDecoderStub{},
 	}
 }
 
@@ -55,6 +56,11 @@ func TestNewLogsAndEventsProcessor(t *testing.T) {
 	_, err = NewLogsAndEventsProcessor(args)
 	require.Equal(t, elasticIndexer.ErrNilHasher, err)
 
+	args = createMockArgs()
+	args.ABIDecoder = nil
+	_, err = NewLogsAndEventsProcessor(args)
+	require.Equal(t, elasticIndexer.ErrNilABIDecoder, err)
+
 	args = createMockArgs()
 	args.TxFeeCalculator = nil
 	_, err = NewLogsAndEventsProcessor(args)
@@ -194,16 +200,18 @@ func TestLogsAndEventsProcessor_ExtractDataFromLogsAndPutInAltered(t *testing.T)
 	}, resLogs.TokensInfo[0])
 
 	require.Equal(t, &data.Delegator{
-		Address:        "61646472",
-		Contract:       "636f6e7472616374",
-		ActiveStakeNum: 0.1,
-		ActiveStake:    "1000000000",
+		Address:             "61646472",
+		Contract:            "636f6e7472616374",
+		ActiveStakeNum:      0.1,
+		ActiveStake:         "1000000000",
+		ActiveStakeSortable: converters.BigIntToSortableString(big.NewInt(1000000000)),
 	}, resLogs.Delegators["61646472636f6e7472616374"])
 	require.Equal(t, &data.Delegator{
-		Address:        "61646472",
-		Contract:       "636f6e74726163742d7365636f6e64",
-		ActiveStakeNum: 0.1,
-		ActiveStake:    "1000000000",
+		Address:             "61646472",
+		Contract:            "636f6e74726163742d7365636f6e64",
+		ActiveStakeNum:      0.1,
+		ActiveStake:         "1000000000",
+		ActiveStakeSortable: converters.BigIntToSortableString(big.NewInt(1000000000)),
 	}, resLogs.Delegators["61646472636f6e74726163742d7365636f6e64"])
 }
 
@@ -1,6 +1,9 @@
 package logsevents
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/ME-MotherEarth/me-elastic-indexer/converters"
 	"github.com/ME-MotherEarth/me-elastic-indexer/data"
 	"github.com/ME-MotherEarth/me-elastic-indexer/process/tokeninfo"
@@ -18,6 +21,9 @@ type logsData struct {
 	tokensInfo              []*data.TokenInfo
 	nftsDataUpdates         []*data.NFTDataUpdate
 	tokenRolesAndProperties *tokeninfo.TokenRolesAndProperties
+	customDocuments         []*data.Document
+	transfers               []*data.Transfer
+	numTransfers            map[string]int
 }
 
 func newLogsData(
@@ -39,6 +45,30 @@ func newLogsData(
 	ld.delegators = make(map[string]*data.Delegator)
 	ld.nftsDataUpdates = make([]*data.NFTDataUpdate, 0)
 	ld.tokenRolesAndProperties = tokeninfo.NewTokenRolesAndProperties()
+	ld.customDocuments = make([]*data.Document, 0)
+	ld.transfers = make([]*data.Transfer, 0)
+	ld.numTransfers = make(map[string]int)
 
 	return ld
 }
+
+// addTransfer will add a transfer of the log, transaction or smart contract result with the provided hash. The ID of
+// the transfer is made of that hash and of its order among the transfers of the hash, so a block indexed again
+// overwrites its transfers
+func (ld *logsData) addTransfer(hash string, transfer *data.Transfer) {
+	transfer.ID = fmt.Sprintf("%s-%d", hash, ld.numTransfers[hash])
+	transfer.TxHash = hash
+	transfer.Timestamp = time.Duration(ld.timestamp)
+	scr, ok := ld.scrsMap[hash]
+	if ok {
+		transfer.TxHash = scr.OriginalTxHash
+		transfer.ScrHash = hash
+	}
+
+	ld.numTransfers[hash]++
+	ld.transfers = append(ld.transfers, transfer)
+}
+
+func (ld *logsData) hasTransfers(hash string) bool {
+	return ld.numTransfers[hash] > 0
+}
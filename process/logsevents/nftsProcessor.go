@@ -72,6 +72,7 @@ func (np *nftsProcessor) processEvent(args *argsProcessEvent) argOutputProcessEv
 	token := string(topics[0])
 	identifier := converters.ComputeTokenIdentifier(token, nonceBig.Uint64())
 	valueBig := big.NewInt(0).SetBytes(topics[2])
+	transfer := prepareTransfer(args.event, np.pubKeyConverter, np.shardCoordinator)
 
 	if !np.shouldAddReceiverData(args) {
 		return argOutputProcessEvent{
@@ -91,6 +92,7 @@ func (np *nftsProcessor) processEvent(args *argsProcessEvent) argOutputProcessEv
 			processed:       true,
 			receiver:        encodedReceiver,
 			receiverShardID: receiverShardID,
+			transfer:        transfer,
 		}
 	}
 
@@ -115,6 +117,7 @@ func (np *nftsProcessor) processEvent(args *argsProcessEvent) argOutputProcessEv
 		processed:       true,
 		receiver:        encodedReceiver,
 		receiverShardID: receiverShardID,
+		transfer:        transfer,
 	}
 }
 
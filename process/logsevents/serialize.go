@@ -2,7 +2,6 @@ package logsevents
 
 import (
 	"encoding/base64"
-	"encoding/json"
 	"fmt"
 
 	"github.com/ME-MotherEarth/me-core/core"
@@ -11,209 +10,151 @@ import (
 	"github.com/ME-MotherEarth/me-elastic-indexer/process/tokeninfo"
 )
 
-// SerializeLogs will serialize the provided logs in a way that Elastic Search expects a bulk request
-func (logsAndEventsProcessor) SerializeLogs(logs []*data.Logs, buffSlice *data.BufferSlice, index string) error {
+// SerializeLogs will serialize the provided logs in documents that can be written in the database
+func (logsAndEventsProcessor) SerializeLogs(logs []*data.Logs, docs *data.DocumentsSlice, index string) error {
 	for _, lg := range logs {
-		meta := []byte(fmt.Sprintf(`{ "update" : { "_index":"%s", "_id" : "%s" } }%s`, index, converters.JsonEscape(lg.ID), "\n"))
-		serializedData, errMarshal := json.Marshal(lg)
-		if errMarshal != nil {
-			return errMarshal
-		}
-
-		codeToExecute := `
-		if ('create' == ctx.op) {
-			ctx._source = params.log
-		} else {
-			if (ctx._source.containsKey('timestamp')) {
-				if (ctx._source.timestamp <= params.log.timestamp) {
-					ctx._source = params.log
-				}
-			} else {
-				ctx._source = params.log
-			}
-		}
-`
-		serializedDataStr := fmt.Sprintf(`{"scripted_upsert": true, "script": {`+
-			`"source": "%s",`+
-			`"lang": "painless",`+
-			`"params": { "log": %s }},`+
-			`"upsert": {}}`,
-			converters.FormatPainlessSource(codeToExecute), serializedData,
-		)
-
-		err := buffSlice.PutData(meta, []byte(serializedDataStr))
-		if err != nil {
-			return err
-		}
+		docs.Add(&data.Document{
+			Index:     index,
+			ID:        lg.ID,
+			Action:    data.ActionIndex,
+			Body:      lg,
+			Timestamp: uint64(lg.Timestamp),
+		})
 	}
 
 	return nil
 }
 
-// SerializeSCDeploys will serialize the provided smart contract deploys in a way that Elastic Search expects a bulk request
-func (logsAndEventsProcessor) SerializeSCDeploys(deploys map[string]*data.ScDeployInfo, buffSlice *data.BufferSlice, index string) error {
-	for scAddr, deployInfo := range deploys {
-		meta := []byte(fmt.Sprintf(`{ "update" : { "_index":"%s", "_id" : "%s" } }%s`, index, converters.JsonEscape(scAddr), "\n"))
+// SerializeEvents will serialize the provided events in documents that can be written in the database
+func (logsAndEventsProcessor) SerializeEvents(events []*data.LogEvent, docs *data.DocumentsSlice, index string) error {
+	for _, event := range events {
+		docs.Add(&data.Document{
+			Index:     index,
+			ID:        event.ID,
+			Action:    data.ActionIndex,
+			Body:      event,
+			Timestamp: uint64(event.Timestamp),
+		})
+	}
 
-		serializedData, err := serializeDeploy(deployInfo)
-		if err != nil {
-			return err
-		}
+	return nil
+}
 
-		err = buffSlice.PutData(meta, serializedData)
-		if err != nil {
-			return err
-		}
+// SerializeTransfers will serialize the provided transfers in documents that can be written in the database
+func (logsAndEventsProcessor) SerializeTransfers(transfers []*data.Transfer, docs *data.DocumentsSlice, index string) error {
+	for _, transfer := range transfers {
+		docs.Add(&data.Document{
+			Index:     index,
+			ID:        transfer.ID,
+			Action:    data.ActionIndex,
+			Body:      transfer,
+			Timestamp: uint64(transfer.Timestamp),
+		})
 	}
 
 	return nil
 }
 
-func serializeDeploy(deployInfo *data.ScDeployInfo) ([]byte, error) {
-	deployInfo.Upgrades = make([]*data.Upgrade, 0)
-	serializedData, errPrepareD := json.Marshal(deployInfo)
-	if errPrepareD != nil {
-		return nil, errPrepareD
+// SerializeSCDeploys will serialize the provided smart contract deploys in documents that can be written in the database
+func (logsAndEventsProcessor) SerializeSCDeploys(deploys map[string]*data.ScDeployInfo, docs *data.DocumentsSlice, index string) error {
+	for scAddr, deployInfo := range deploys {
+		docs.Add(prepareDeployDocument(scAddr, deployInfo, index))
 	}
 
+	return nil
+}
+
+func prepareDeployDocument(scAddr string, deployInfo *data.ScDeployInfo, index string) *data.Document {
+	deployInfo.Upgrades = make([]*data.Upgrade, 0)
 	upgradeData := &data.Upgrade{
 		TxHash:    deployInfo.TxHash,
 		Upgrader:  deployInfo.Creator,
 		Timestamp: deployInfo.Timestamp,
 	}
-	upgradeSerialized, errPrepareU := json.Marshal(upgradeData)
-	if errPrepareU != nil {
-		return nil, errPrepareU
-	}
 
-	codeToExecute := `
-		if (!ctx._source.containsKey('upgrades')) {
-			ctx._source.upgrades = [params.elem];
-		} else {
-			ctx._source.upgrades.add(params.elem);
-		}
-`
-	serializedDataStr := fmt.Sprintf(`{"script": {`+
-		`"source": "%s",`+
-		`"lang": "painless",`+
-		`"params": {"elem": %s}},`+
-		`"upsert": %s}`,
-		converters.FormatPainlessSource(codeToExecute), string(upgradeSerialized), string(serializedData))
-
-	return []byte(serializedDataStr), nil
+	// the first deploy creates the document, while the next ones are only recorded as upgrades
+	return &data.Document{
+		Index:  index,
+		ID:     scAddr,
+		Action: data.ActionUpsert,
+		Body:   deployInfo,
+		Append: map[string][]interface{}{
+			"upgrades": {upgradeData},
+		},
+	}
 }
 
-// SerializeTokens will serialize the provided tokens' data in a way that Elasticsearch expects a bulk request
-func (logsAndEventsProcessor) SerializeTokens(tokens []*data.TokenInfo, updateNFTData []*data.NFTDataUpdate, buffSlice *data.BufferSlice, index string) error {
+// SerializeTokens will serialize the provided tokens' data in documents that can be written in the database
+func (logsAndEventsProcessor) SerializeTokens(tokens []*data.TokenInfo, updateNFTData []*data.NFTDataUpdate, docs *data.DocumentsSlice, index string) error {
 	for _, tokenData := range tokens {
-		meta, serializedData, err := serializeToken(tokenData, index)
-		if err != nil {
-			return err
-		}
-
-		err = buffSlice.PutData(meta, serializedData)
-		if err != nil {
-			return err
-		}
+		docs.Add(prepareTokenDocument(tokenData, index))
 	}
 
-	return converters.PrepareNFTUpdateData(buffSlice, updateNFTData, false, index)
+	converters.PrepareNFTUpdateData(docs, updateNFTData, false, index)
+
+	return nil
 }
 
-func serializeToken(tokenData *data.TokenInfo, index string) ([]byte, []byte, error) {
+func prepareTokenDocument(tokenData *data.TokenInfo, index string) *data.Document {
 	if tokenData.TransferOwnership {
-		return serializeTokenTransferOwnership(tokenData, index)
+		return prepareTokenTransferOwnershipDocument(tokenData, index)
 	}
 
-	meta := []byte(fmt.Sprintf(`{ "update" : { "_index":"%s", "_id" : "%s" } }%s`, index, converters.JsonEscape(tokenData.Token), "\n"))
-	serializedTokenData, err := json.Marshal(tokenData)
-	if err != nil {
-		return nil, nil, err
+	// the roles are set by separate events, so they are kept when the token is overwritten
+	return &data.Document{
+		Index:      index,
+		ID:         tokenData.Token,
+		Action:     data.ActionIndex,
+		Body:       tokenData,
+		KeepFields: []string{"roles"},
 	}
-
-	codeToExecute := `
-		if (ctx._source.containsKey('roles')) {
-			HashMap roles = ctx._source.roles;
-			ctx._source = params.token;
-			ctx._source.roles = roles
-		}
-`
-	serializedDataStr := fmt.Sprintf(`{"script": {`+
-		`"source": "%s",`+
-		`"lang": "painless",`+
-		`"params": {"token": %s}},`+
-		`"upsert": %s}`,
-		converters.FormatPainlessSource(codeToExecute), string(serializedTokenData), string(serializedTokenData))
-
-	return meta, []byte(serializedDataStr), nil
 }
 
-func serializeTokenTransferOwnership(tokenData *data.TokenInfo, index string) ([]byte, []byte, error) {
-	meta := []byte(fmt.Sprintf(`{ "update" : { "_index":"%s", "_id" : "%s" } }%s`, index, converters.JsonEscape(tokenData.Token), "\n"))
-	tokenDataSerialized, err := json.Marshal(tokenData)
-	if err != nil {
-		return nil, nil, err
-	}
-
+func prepareTokenTransferOwnershipDocument(tokenData *data.TokenInfo, index string) *data.Document {
 	currentOwnerData := &data.OwnerData{}
 	if len(tokenData.OwnersHistory) > 0 {
 		currentOwnerData = tokenData.OwnersHistory[0]
 	}
 
-	ownerDataSerialized, err := json.Marshal(currentOwnerData)
-	if err != nil {
-		return nil, nil, err
+	return &data.Document{
+		Index:  index,
+		ID:     tokenData.Token,
+		Action: data.ActionUpsert,
+		Body:   tokenData,
+		Fields: map[string]interface{}{
+			"currentOwner": tokenData.CurrentOwner,
+		},
+		Append: map[string][]interface{}{
+			"ownersHistory": {currentOwnerData},
+		},
 	}
-
-	codeToExecute := `
-		if (!ctx._source.containsKey('ownersHistory')) {
-			ctx._source.ownersHistory = [params.elem]
-		} else {
-			ctx._source.ownersHistory.add(params.elem)
-		}
-		ctx._source.currentOwner = params.owner
-`
-	serializedDataStr := fmt.Sprintf(`{"script": {`+
-		`"source": "%s",`+
-		`"lang": "painless",`+
-		`"params": {"elem": %s, "owner": "%s"}},`+
-		`"upsert": %s}`,
-		converters.FormatPainlessSource(codeToExecute), string(ownerDataSerialized), converters.JsonEscape(tokenData.CurrentOwner), string(tokenDataSerialized))
-
-	return meta, []byte(serializedDataStr), nil
 }
 
-// SerializeDelegators will serialize the provided delegators in a way that Elasticsearch expects a bulk request
-func (lep *logsAndEventsProcessor) SerializeDelegators(delegators map[string]*data.Delegator, buffSlice *data.BufferSlice, index string) error {
+// SerializeDelegators will serialize the provided delegators in documents that can be written in the database
+func (lep *logsAndEventsProcessor) SerializeDelegators(delegators map[string]*data.Delegator, docs *data.DocumentsSlice, index string) error {
 	for _, delegator := range delegators {
-		meta, serializedData, err := lep.prepareSerializedDelegator(delegator, index)
-		if err != nil {
-			return err
-		}
-
-		err = buffSlice.PutData(meta, serializedData)
-		if err != nil {
-			return err
-		}
+		docs.Add(lep.prepareDelegatorDocument(delegator, index))
 	}
 
 	return nil
 }
 
-func (lep *logsAndEventsProcessor) prepareSerializedDelegator(delegator *data.Delegator, index string) ([]byte, []byte, error) {
+func (lep *logsAndEventsProcessor) prepareDelegatorDocument(delegator *data.Delegator, index string) *data.Document {
 	id := lep.computeDelegatorID(delegator)
 	if delegator.ShouldDelete {
-		meta := []byte(fmt.Sprintf(`{ "delete" : { "_index": "%s", "_id" : "%s" } }%s`, index, converters.JsonEscape(id), "\n"))
-		return meta, nil, nil
+		return &data.Document{
+			Index:  index,
+			ID:     id,
+			Action: data.ActionDelete,
+		}
 	}
 
-	meta := []byte(fmt.Sprintf(`{ "index" : { "_index": "%s", "_id" : "%s" } }%s`, index, converters.JsonEscape(id), "\n"))
-	serializedData, errMarshal := json.Marshal(delegator)
-	if errMarshal != nil {
-		return nil, nil, errMarshal
+	return &data.Document{
+		Index:  index,
+		ID:     id,
+		Action: data.ActionIndex,
+		Body:   delegator,
 	}
-
-	return meta, serializedData, nil
 }
 
 func (lep *logsAndEventsProcessor) computeDelegatorID(delegator *data.Delegator) string {
@@ -225,17 +166,17 @@ func (lep *logsAndEventsProcessor) computeDelegatorID(delegator *data.Delegator)
 }
 
 // SerializeSupplyData will serialize the provided supply data
-func (lep *logsAndEventsProcessor) SerializeSupplyData(tokensSupply data.TokensHandler, buffSlice *data.BufferSlice, index string) error {
+func (lep *logsAndEventsProcessor) SerializeSupplyData(tokensSupply data.TokensHandler, docs *data.DocumentsSlice, index string) error {
 	for _, supplyData := range tokensSupply.GetAll() {
 		if supplyData.Type != core.NonFungibleMECT {
 			continue
 		}
 
-		meta := []byte(fmt.Sprintf(`{ "delete" : { "_index": "%s", "_id" : "%s" } }%s`, index, converters.JsonEscape(supplyData.Identifier), "\n"))
-		err := buffSlice.PutData(meta, nil)
-		if err != nil {
-			return err
-		}
+		docs.Add(&data.Document{
+			Index:  index,
+			ID:     supplyData.Identifier,
+			Action: data.ActionDelete,
+		})
 	}
 
 	return nil
@@ -244,103 +185,60 @@ func (lep *logsAndEventsProcessor) SerializeSupplyData(tokensSupply data.TokensH
 // SerializeRolesData will serialize the provided roles data
 func (lep *logsAndEventsProcessor) SerializeRolesData(
 	tokenRolesAndProperties *tokeninfo.TokenRolesAndProperties,
-	buffSlice *data.BufferSlice,
+	docs *data.DocumentsSlice,
 	index string,
 ) error {
 	for role, roleData := range tokenRolesAndProperties.GetRoles() {
 		for _, rd := range roleData {
-			err := serializeRoleData(buffSlice, rd, role, index)
-			if err != nil {
-				return err
-			}
+			docs.Add(prepareRoleDocument(rd, role, index))
 		}
 	}
 
 	for _, tokenAndProp := range tokenRolesAndProperties.GetAllTokensWithProperties() {
-		err := serializePropertiesData(buffSlice, index, tokenAndProp)
-		if err != nil {
-			return err
-		}
+		docs.Add(preparePropertiesDocument(tokenAndProp, index))
 	}
 
 	return nil
 }
 
-func serializeRoleData(buffSlice *data.BufferSlice, rd *tokeninfo.RoleData, role string, index string) error {
-	meta := []byte(fmt.Sprintf(`{ "update" : {"_index": "%s", "_id" : "%s" } }%s`, index, converters.JsonEscape(rd.Token), "\n"))
-	var serializedDataStr string
-	if rd.Set {
-		codeToExecute := `	
-			if (!ctx._source.containsKey('roles')) {
-				ctx._source.roles = new HashMap();
-			}
-			if (!ctx._source.roles.containsKey(params.role)) {
-				ctx._source.roles.put(params.role, [params.address]);
-			} else {
-				int i;
-				for (i = 0; i < ctx._source.roles.get(params.role).length; i++) {
-					if (ctx._source.roles.get(params.role).get(i) == params.address) {
-						return;
-					}
-				}
-				ctx._source.roles.get(params.role).add(params.address);
-			}
-`
-		serializedDataStr = fmt.Sprintf(`{"script": {`+
-			`"source": "%s",`+
-			`"lang": "painless",`+
-			`"params": { "role": "%s", "address": "%s"}},`+
-			`"upsert": { "roles": {"%s": ["%s"]}}}`,
-			converters.FormatPainlessSource(codeToExecute),
-			converters.JsonEscape(role),
-			converters.JsonEscape(rd.Address),
-			converters.JsonEscape(role),
-			converters.JsonEscape(rd.Address),
-		)
-	} else {
-		codeToExecute := `
-	if (ctx._source.containsKey('roles')) {
-		if (ctx._source.roles.containsKey(params.role)) {
-			ctx._source.roles.get(params.role).removeIf(p -> p.equals(params.address))
-		}
-	}
-`
-		serializedDataStr = fmt.Sprintf(`{"script": {`+
-			`"source": "%s",`+
-			`"lang": "painless",`+
-			`"params": { "role": "%s", "address": "%s" }},`+
-			`"upsert": {} }`,
-			converters.FormatPainlessSource(codeToExecute),
-			converters.JsonEscape(role),
-			converters.JsonEscape(rd.Address),
-		)
+func prepareRoleDocument(rd *tokeninfo.RoleData, role string, index string) *data.Document {
+	rolePath := fmt.Sprintf("roles.%s", role)
+	if !rd.Set {
+		return &data.Document{
+			Index:  index,
+			ID:     rd.Token,
+			Action: data.ActionUpdate,
+			RemoveFromSet: map[string][]interface{}{
+				rolePath: {rd.Address},
+			},
+		}
+	}
+
+	return &data.Document{
+		Index:  index,
+		ID:     rd.Token,
+		Action: data.ActionUpsert,
+		Body: map[string]interface{}{
+			"roles": map[string][]string{
+				role: {rd.Address},
+			},
+		},
+		AddToSet: map[string][]interface{}{
+			rolePath: {rd.Address},
+		},
 	}
-
-	return buffSlice.PutData(meta, []byte(serializedDataStr))
 }
 
-func serializePropertiesData(buffSlice *data.BufferSlice, index string, tokenProp *tokeninfo.PropertiesData) error {
-	meta := []byte(fmt.Sprintf(`{ "update" : {"_index": "%s", "_id" : "%s" } }%s`, index, converters.JsonEscape(tokenProp.Token), "\n"))
-
-	propertiesBytes, err := json.Marshal(tokenProp.Properties)
-	if err != nil {
-		return err
+func preparePropertiesDocument(tokenProp *tokeninfo.PropertiesData, index string) *data.Document {
+	fields := make(map[string]interface{}, len(tokenProp.Properties))
+	for key, value := range tokenProp.Properties {
+		fields[fmt.Sprintf("properties.%s", key)] = value
 	}
 
-	codeToExecute := `	
-			if (!ctx._source.containsKey('properties')) {
-				ctx._source.properties = new HashMap();
-			}
-			params.properties.forEach(
-				(key, value) -> ctx._source.properties[key] = value
-			);
-`
-	serializedDataStr := fmt.Sprintf(`{"script": {`+
-		`"source": "%s",`+
-		`"lang": "painless",`+
-		`"params": { "properties": %s}},`+
-		`"upsert": {}}}`,
-		converters.FormatPainlessSource(codeToExecute), propertiesBytes)
-
-	return buffSlice.PutData(meta, []byte(serializedDataStr))
+	return &data.Document{
+		Index:  index,
+		ID:     tokenProp.Token,
+		Action: data.ActionUpdate,
+		Fields: fields,
+	}
 }
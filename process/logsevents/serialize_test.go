@@ -8,6 +8,7 @@ import (
 	"github.com/ME-MotherEarth/me-core/core"
 	"github.com/ME-MotherEarth/me-elastic-indexer/data"
 	"github.com/ME-MotherEarth/me-elastic-indexer/mock"
+	"github.com/ME-MotherEarth/me-elastic-indexer/process/tokeninfo"
 	"github.com/stretchr/testify/require"
 )
 
@@ -31,14 +32,58 @@ func TestLogsAndEventsProcessor_SerializeLogs(t *testing.T) {
 		},
 	}
 
-	buffSlice := data.NewBufferSlice(data.DefaultMaxBulkSize)
-	err := (&logsAndEventsProcessor{}).SerializeLogs(logs, buffSlice, "logs")
+	docs := data.NewDocumentsSlice()
+	err := (&logsAndEventsProcessor{}).SerializeLogs(logs, docs, "logs")
 	require.Nil(t, err)
 
-	expectedRes := `{ "update" : { "_index":"logs", "_id" : "747848617368" } }
-{"scripted_upsert": true, "script": {"source": "if ('create' == ctx.op) {ctx._source = params.log} else {if (ctx._source.containsKey('timestamp')) {if (ctx._source.timestamp <= params.log.timestamp) {ctx._source = params.log}} else {ctx._source = params.log}}","lang": "painless","params": { "log": {"address":"61646472657373","events":[{"address":"61646472","identifier":"MECTNFTTransfer","topics":["bXktdG9rZW4=","AQ==","cmVjZWl2ZXI="],"data":"ZGF0YQ==","order":0}],"timestamp":1234} }},"upsert": {}}
-`
-	require.Equal(t, expectedRes, buffSlice.Buffers()[0].String())
+	expectedDocs := []*data.Document{
+		{Index: "logs", ID: "747848617368", Action: data.ActionIndex, Body: logs[0], Timestamp: 1234},
+	}
+	require.Equal(t, expectedDocs, docs.Documents())
+}
+
+func TestLogsAndEventsProcessor_SerializeEvents(t *testing.T) {
+	t.Parallel()
+
+	events := []*data.LogEvent{
+		{
+			ID:         "747848617368-0-0",
+			TxHash:     "747848617368",
+			Identifier: core.BuiltInFunctionMECTTransfer,
+			Timestamp:  time.Duration(1234),
+		},
+	}
+
+	docs := data.NewDocumentsSlice()
+	err := (&logsAndEventsProcessor{}).SerializeEvents(events, docs, "events")
+	require.Nil(t, err)
+
+	expectedDocs := []*data.Document{
+		{Index: "events", ID: "747848617368-0-0", Action: data.ActionIndex, Body: events[0], Timestamp: 1234},
+	}
+	require.Equal(t, expectedDocs, docs.Documents())
+}
+
+func TestLogsAndEventsProcessor_SerializeTransfers(t *testing.T) {
+	t.Parallel()
+
+	transfers := []*data.Transfer{
+		{
+			ID:        "747848617368-0",
+			TxHash:    "747848617368",
+			Operation: core.BuiltInFunctionMECTTransfer,
+			Timestamp: time.Duration(1234),
+		},
+	}
+
+	docs := data.NewDocumentsSlice()
+	err := (&logsAndEventsProcessor{}).SerializeTransfers(transfers, docs, "transfers")
+	require.Nil(t, err)
+
+	expectedDocs := []*data.Document{
+		{Index: "transfers", ID: "747848617368-0", Action: data.ActionIndex, Body: transfers[0], Timestamp: 1234},
+	}
+	require.Equal(t, expectedDocs, docs.Documents())
 }
 
 func TestLogsAndEventsProcessor_SerializeSCDeploys(t *testing.T) {
@@ -52,14 +97,27 @@ func TestLogsAndEventsProcessor_SerializeSCDeploys(t *testing.T) {
 		},
 	}
 
-	buffSlice := data.NewBufferSlice(data.DefaultMaxBulkSize)
-	err := (&logsAndEventsProcessor{}).SerializeSCDeploys(scDeploys, buffSlice, "scdeploys")
+	docs := data.NewDocumentsSlice()
+	err := (&logsAndEventsProcessor{}).SerializeSCDeploys(scDeploys, docs, "scdeploys")
 	require.Nil(t, err)
 
-	expectedRes := `{ "update" : { "_index":"scdeploys", "_id" : "scAddr" } }
-{"script": {"source": "if (!ctx._source.containsKey('upgrades')) {ctx._source.upgrades = [params.elem];} else {ctx._source.upgrades.add(params.elem);}","lang": "painless","params": {"elem": {"upgradeTxHash":"hash","upgrader":"creator","timestamp":123}}},"upsert": {"deployTxHash":"hash","deployer":"creator","timestamp":123,"upgrades":[]}}
-`
-	require.Equal(t, expectedRes, buffSlice.Buffers()[0].String())
+	expectedDocs := []*data.Document{
+		{
+			Index:  "scdeploys",
+			ID:     "scAddr",
+			Action: data.ActionUpsert,
+			Body: &data.ScDeployInfo{
+				Creator:   "creator",
+				Timestamp: 123,
+				TxHash:    "hash",
+				Upgrades:  []*data.Upgrade{},
+			},
+			Append: map[string][]interface{}{
+				"upgrades": {&data.Upgrade{TxHash: "hash", Upgrader: "creator", Timestamp: 123}},
+			},
+		},
+	}
+	require.Equal(t, expectedDocs, docs.Documents())
 }
 
 func TestSerializeTokens(t *testing.T) {
@@ -98,17 +156,24 @@ func TestSerializeTokens(t *testing.T) {
 	}
 	tokens := []*data.TokenInfo{tok1, tok2}
 
-	buffSlice := data.NewBufferSlice(data.DefaultMaxBulkSize)
-	err := (&logsAndEventsProcessor{}).SerializeTokens(tokens, nil, buffSlice, "tokens")
+	docs := data.NewDocumentsSlice()
+	err := (&logsAndEventsProcessor{}).SerializeTokens(tokens, nil, docs, "tokens")
 	require.Nil(t, err)
-	require.Equal(t, 1, len(buffSlice.Buffers()))
-
-	expectedRes := `{ "update" : { "_index":"tokens", "_id" : "TKN-01234" } }
-{"script": {"source": "if (ctx._source.containsKey('roles')) {HashMap roles = ctx._source.roles;ctx._source = params.token;ctx._source.roles = roles}","lang": "painless","params": {"token": {"name":"TokenName","ticker":"TKN","token":"TKN-01234","issuer":"moa123","currentOwner":"moa123","type":"SemiFungibleMECT","timestamp":50000,"ownersHistory":[{"address":"moa123","timestamp":50000}]}}},"upsert": {"name":"TokenName","ticker":"TKN","token":"TKN-01234","issuer":"moa123","currentOwner":"moa123","type":"SemiFungibleMECT","timestamp":50000,"ownersHistory":[{"address":"moa123","timestamp":50000}]}}
-{ "update" : { "_index":"tokens", "_id" : "TKN2-51234" } }
-{"script": {"source": "if (!ctx._source.containsKey('ownersHistory')) {ctx._source.ownersHistory = [params.elem]} else {ctx._source.ownersHistory.add(params.elem)}ctx._source.currentOwner = params.owner","lang": "painless","params": {"elem": {"address":"abde123456","timestamp":60000}, "owner": "abde123456"}},"upsert": {"name":"Token2","ticker":"TKN2","token":"TKN2-51234","issuer":"moa1231213123","currentOwner":"abde123456","type":"NonFungibleMECT","timestamp":60000,"ownersHistory":[{"address":"abde123456","timestamp":60000}]}}
-`
-	require.Equal(t, expectedRes, buffSlice.Buffers()[0].String())
+
+	expectedDocs := []*data.Document{
+		{Index: "tokens", ID: "TKN-01234", Action: data.ActionIndex, Body: tok1, KeepFields: []string{"roles"}},
+		{
+			Index:  "tokens",
+			ID:     "TKN2-51234",
+			Action: data.ActionUpsert,
+			Body:   tok2,
+			Fields: map[string]interface{}{"currentOwner": "abde123456"},
+			Append: map[string][]interface{}{
+				"ownersHistory": {&data.OwnerData{Address: "abde123456", Timestamp: 60000}},
+			},
+		},
+	}
+	require.Equal(t, expectedDocs, docs.Documents())
 }
 
 func TestLogsAndEventsProcessor_SerializeDelegators(t *testing.T) {
@@ -129,14 +194,14 @@ func TestLogsAndEventsProcessor_SerializeDelegators(t *testing.T) {
 		hasher: &mock.HasherMock{},
 	}
 
-	buffSlice := data.NewBufferSlice(data.DefaultMaxBulkSize)
-	err := logsProc.SerializeDelegators(delegators, buffSlice, "delegators")
+	docs := data.NewDocumentsSlice()
+	err := logsProc.SerializeDelegators(delegators, docs, "delegators")
 	require.Nil(t, err)
 
-	expectedRes := `{ "index" : { "_index": "delegators", "_id" : "/GeogJjDjtpxnceK9t6+BVBYWuuJHbjmsWK0/1BlH9c=" } }
-{"address":"addr1","contract":"contract1","activeStake":"100000000000000","activeStakeNum":0.1}
-`
-	require.Equal(t, expectedRes, buffSlice.Buffers()[0].String())
+	expectedDocs := []*data.Document{
+		{Index: "delegators", ID: "/GeogJjDjtpxnceK9t6+BVBYWuuJHbjmsWK0/1BlH9c=", Action: data.ActionIndex, Body: delegator1},
+	}
+	require.Equal(t, expectedDocs, docs.Documents())
 }
 
 func TestLogsAndEventsProcessor_SerializeDelegatorsDelete(t *testing.T) {
@@ -156,11 +221,41 @@ func TestLogsAndEventsProcessor_SerializeDelegatorsDelete(t *testing.T) {
 		hasher: &mock.HasherMock{},
 	}
 
-	buffSlice := data.NewBufferSlice(data.DefaultMaxBulkSize)
-	err := logsProc.SerializeDelegators(delegators, buffSlice, "delegators")
+	docs := data.NewDocumentsSlice()
+	err := logsProc.SerializeDelegators(delegators, docs, "delegators")
 	require.Nil(t, err)
 
-	expectedRes := `{ "delete" : { "_index": "delegators", "_id" : "/GeogJjDjtpxnceK9t6+BVBYWuuJHbjmsWK0/1BlH9c=" } }
-`
-	require.Equal(t, expectedRes, buffSlice.Buffers()[0].String())
+	expectedDocs := []*data.Document{
+		{Index: "delegators", ID: "/GeogJjDjtpxnceK9t6+BVBYWuuJHbjmsWK0/1BlH9c=", Action: data.ActionDelete},
+	}
+	require.Equal(t, expectedDocs, docs.Documents())
+}
+
+func TestLogsAndEventsProcessor_SerializeRolesData(t *testing.T) {
+	t.Parallel()
+
+	tokenRolesAndProperties := tokeninfo.NewTokenRolesAndProperties()
+	tokenRolesAndProperties.AddRole("TKN-01234", "moa1", "MECTRoleNFTCreate", true)
+	tokenRolesAndProperties.AddRole("TKN-01234", "moa2", "MECTRoleNFTBurn", false)
+
+	docs := data.NewDocumentsSlice()
+	err := (&logsAndEventsProcessor{}).SerializeRolesData(tokenRolesAndProperties, docs, "tokens")
+	require.Nil(t, err)
+
+	expectedSetRole := &data.Document{
+		Index:  "tokens",
+		ID:     "TKN-01234",
+		Action: data.ActionUpsert,
+		Body: map[string]interface{}{
+			"roles": map[string][]string{"MECTRoleNFTCreate": {"moa1"}},
+		},
+		AddToSet: map[string][]interface{}{"roles.MECTRoleNFTCreate": {"moa1"}},
+	}
+	expectedUnsetRole := &data.Document{
+		Index:         "tokens",
+		ID:            "TKN-01234",
+		Action:        data.ActionUpdate,
+		RemoveFromSet: map[string][]interface{}{"roles.MECTRoleNFTBurn": {"moa2"}},
+	}
+	require.ElementsMatch(t, []*data.Document{expectedSetRole, expectedUnsetRole}, docs.Documents())
 }
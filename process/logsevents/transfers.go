@@ -0,0 +1,188 @@
+package logsevents
+
+import (
+	"math/big"
+
+	"github.com/ME-MotherEarth/me-core/core"
+	coreData "github.com/ME-MotherEarth/me-core/data"
+	"github.com/ME-MotherEarth/me-core/data/transaction"
+	elasticIndexer "github.com/ME-MotherEarth/me-elastic-indexer"
+	"github.com/ME-MotherEarth/me-elastic-indexer/converters"
+	"github.com/ME-MotherEarth/me-elastic-indexer/data"
+)
+
+// transferOperations holds the identifiers of the events, and the operations of the data fields, that move tokens
+// from an account to another one
+var transferOperations = map[string]struct{}{
+	core.BuiltInFunctionMECTTransfer:         {},
+	core.BuiltInFunctionMECTNFTTransfer:      {},
+	core.BuiltInFunctionMultiMECTNFTTransfer: {},
+}
+
+// dataFieldTransfers holds the fields of a transaction or of a smart contract result, parsed from its data field,
+// that describe the tokens it transfers
+type dataFieldTransfers struct {
+	hash              string
+	sender            string
+	receiver          string
+	receiverShard     uint32
+	operation         string
+	tokens            []string
+	values            []string
+	receivers         []string
+	receiversShardIDs []uint32
+}
+
+// prepareTransfer will return the transfer of the provided event. The event of a transfer between shards is written
+// in both shards, so the transfer is returned only by the shard of the sender, which is the address of the event
+func prepareTransfer(
+	event coreData.EventHandler,
+	pubKeyConverter core.PubkeyConverter,
+	shardCoordinator elasticIndexer.ShardCoordinator,
+) *data.Transfer {
+	identifier := string(event.GetIdentifier())
+	_, isTransfer := transferOperations[identifier]
+	topics := event.GetTopics()
+	if !isTransfer || len(topics) < numTopicsWithReceiverAddress {
+		return nil
+	}
+
+	selfShardID := shardCoordinator.SelfId()
+	if shardCoordinator.ComputeId(event.GetAddress()) != selfShardID {
+		return nil
+	}
+
+	token := string(topics[0])
+	nonce := big.NewInt(0).SetBytes(topics[1]).Uint64()
+	amount := big.NewInt(0).SetBytes(topics[2])
+
+	return &data.Transfer{
+		Operation:       identifier,
+		Sender:          pubKeyConverter.Encode(event.GetAddress()),
+		Receiver:        pubKeyConverter.Encode(topics[3]),
+		ReceiverShard:   shardCoordinator.ComputeId(topics[3]),
+		Token:           token,
+		TokenIdentifier: computeTransferTokenIdentifier(token, nonce),
+		Nonce:           nonce,
+		Amount:          amount.String(),
+		AmountSortable:  converters.BigIntToSortableString(amount),
+		ShardID:         selfShardID,
+	}
+}
+
+// computeTransferTokenIdentifier returns the identifier of the token with the provided nonce. The fungible tokens are
+// identified by the token alone
+func computeTransferTokenIdentifier(token string, nonce uint64) string {
+	if nonce == 0 {
+		return token
+	}
+
+	return converters.ComputeTokenIdentifier(token, nonce)
+}
+
+// addTransfersFromDataField will add the transfers of the transactions and of the smart contract results sent from
+// this shard whose transfers were not found in the events of the logs. A smart contract result is also skipped when
+// its parent has transfers, as it only carries to the shard of the receiver the tokens already transferred by its
+// parent. The relayed transactions are skipped too, their inner transaction being executed as a smart contract result
+func (lep *logsAndEventsProcessor) addTransfersFromDataField(txs []*data.Transaction, scrs []*data.ScResult) {
+	for _, tx := range txs {
+		shouldSkip := tx.SenderShard != lep.selfShardID || tx.IsRelayed || isFailedStatus(tx.Status) ||
+			lep.logsData.hasTransfers(tx.Hash)
+		if shouldSkip {
+			continue
+		}
+
+		lep.addDataFieldTransfers(&dataFieldTransfers{
+			hash:              tx.Hash,
+			sender:            tx.Sender,
+			receiver:          tx.Receiver,
+			receiverShard:     tx.ReceiverShard,
+			operation:         tx.Operation,
+			tokens:            tx.Tokens,
+			values:            tx.MECTValues,
+			receivers:         tx.Receivers,
+			receiversShardIDs: tx.ReceiversShardIDs,
+		})
+	}
+
+	for _, scr := range scrs {
+		shouldSkip := scr.SenderShard != lep.selfShardID || isFailedStatus(scr.Status) ||
+			lep.logsData.hasTransfers(scr.Hash) || lep.logsData.hasTransfers(scr.PrevTxHash)
+		if shouldSkip {
+			continue
+		}
+
+		lep.addDataFieldTransfers(&dataFieldTransfers{
+			hash:              scr.Hash,
+			sender:            scr.Sender,
+			receiver:          scr.Receiver,
+			receiverShard:     scr.ReceiverShard,
+			operation:         scr.Operation,
+			tokens:            scr.Tokens,
+			values:            scr.MECTValues,
+			receivers:         scr.Receivers,
+			receiversShardIDs: scr.ReceiversShardIDs,
+		})
+	}
+}
+
+func (lep *logsAndEventsProcessor) addDataFieldTransfers(dft *dataFieldTransfers) {
+	_, isTransfer := transferOperations[dft.operation]
+	if !isTransfer {
+		return
+	}
+
+	for idx, tokenIdentifier := range dft.tokens {
+		if idx >= len(dft.values) {
+			return
+		}
+		amount, ok := big.NewInt(0).SetString(dft.values[idx], 10)
+		if !ok {
+			continue
+		}
+
+		// the single MECT transfers have the receiver of the transaction, the other ones have it in the data field
+		receiver, receiverShard := dft.receiver, dft.receiverShard
+		if idx < len(dft.receivers) && idx < len(dft.receiversShardIDs) {
+			receiver, receiverShard = dft.receivers[idx], dft.receiversShardIDs[idx]
+		}
+
+		token, nonce := converters.ExtractTokenAndNonce(tokenIdentifier)
+		lep.logsData.addTransfer(dft.hash, &data.Transfer{
+			Operation:       dft.operation,
+			Sender:          dft.sender,
+			Receiver:        receiver,
+			ReceiverShard:   receiverShard,
+			Token:           token,
+			TokenIdentifier: tokenIdentifier,
+			Nonce:           nonce,
+			Amount:          amount.String(),
+			AmountSortable:  converters.BigIntToSortableString(amount),
+			ShardID:         lep.selfShardID,
+		})
+	}
+}
+
+func isFailedStatus(status string) bool {
+	return status == transaction.TxStatusFail.String() || status == transaction.TxStatusInvalid.String()
+}
+
+// PutAmountNumInTransfers will compute the amount as float of the provided transfers, using the number of decimals
+// of their tokens. The transfers of the tokens whose number of decimals is not provided have the amount computed with
+// the denomination of the network
+func (lep *logsAndEventsProcessor) PutAmountNumInTransfers(transfers []*data.Transfer, tokensDecimals map[string]uint64) {
+	for _, transfer := range transfers {
+		amount, ok := big.NewInt(0).SetString(transfer.Amount, 10)
+		if !ok {
+			continue
+		}
+
+		numDecimals, found := tokensDecimals[transfer.Token]
+		if !found {
+			transfer.AmountNum = lep.balanceConverter.ComputeMECTBalanceAsFloat(amount)
+			continue
+		}
+
+		transfer.AmountNum = lep.balanceConverter.ComputeMECTBalanceAsFloatWithDecimals(amount, numDecimals)
+	}
+}
@@ -0,0 +1,262 @@
+package logsevents
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ME-MotherEarth/me-core/core"
+	coreData "github.com/ME-MotherEarth/me-core/data"
+	"github.com/ME-MotherEarth/me-core/data/transaction"
+	"github.com/ME-MotherEarth/me-elastic-indexer/converters"
+	"github.com/ME-MotherEarth/me-elastic-indexer/data"
+	"github.com/ME-MotherEarth/me-elastic-indexer/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var otherShardPrefix = []byte("shard1")
+
+func createShardCoordinatorForTransfers() *mock.ShardCoordinatorMock {
+	return &mock.ShardCoordinatorMock{
+		ComputeIdCalled: func(address []byte) uint32 {
+			if bytes.HasPrefix(address, otherShardPrefix) {
+				return 1
+			}
+			return 0
+		},
+	}
+}
+
+func encodedHash(hash string) string {
+	return hex.EncodeToString([]byte(hash))
+}
+
+func TestLogsAndEventsProcessor_ExtractDataFromLogsShouldPrepareTransfersFromEvents(t *testing.T) {
+	t.Parallel()
+
+	logsAndEvents := []*coreData.LogData{
+		{
+			TxHash: "txHash",
+			LogHandler: &transaction.Log{
+				Address: []byte("sender"),
+				Events: []*transaction.Event{
+					{
+						Address:    []byte("sender"),
+						Identifier: []byte(core.BuiltInFunctionMultiMECTNFTTransfer),
+						Topics:     [][]byte{[]byte("TKN-abcd"), big.NewInt(0).Bytes(), big.NewInt(100).Bytes(), []byte("receiver")},
+					},
+					{
+						Address:    []byte("sender"),
+						Identifier: []byte(core.BuiltInFunctionMultiMECTNFTTransfer),
+						Topics:     [][]byte{[]byte("NFT-abcd"), big.NewInt(2).Bytes(), big.NewInt(1).Bytes(), []byte("shard1receiver")},
+					},
+				},
+			},
+		},
+		{
+			TxHash: "scrHash",
+			LogHandler: &transaction.Log{
+				Address: []byte("contract"),
+				Events: []*transaction.Event{
+					{
+						Address:    []byte("shard1sender"),
+						Identifier: []byte(core.BuiltInFunctionMECTTransfer),
+						Topics:     [][]byte{[]byte("TKN-abcd"), big.NewInt(0).Bytes(), big.NewInt(7).Bytes(), []byte("contract")},
+					},
+					{
+						Address:    []byte("contract"),
+						Identifier: []byte(core.BuiltInFunctionMECTTransfer),
+						Topics:     [][]byte{[]byte("TKN-abcd"), big.NewInt(0).Bytes(), big.NewInt(5).Bytes(), []byte("user")},
+					},
+				},
+			},
+		},
+	}
+
+	args := createMockArgs()
+	args.ShardCoordinator = createShardCoordinatorForTransfers()
+	proc, _ := NewLogsAndEventsProcessor(args)
+
+	res := proc.ExtractDataFromLogs(logsAndEvents, &data.PreparedResults{
+		AlteredAccts: data.NewAlteredAccounts(),
+		ScResults: []*data.ScResult{
+			{Hash: encodedHash("scrHash"), OriginalTxHash: "originalTxHash", SenderShard: 1},
+		},
+	}, 1234)
+
+	require.Equal(t, []*data.Transfer{
+		{
+			ID:              encodedHash("txHash") + "-0",
+			TxHash:          encodedHash("txHash"),
+			Operation:       core.BuiltInFunctionMultiMECTNFTTransfer,
+			Sender:          hex.EncodeToString([]byte("sender")),
+			Receiver:        hex.EncodeToString([]byte("receiver")),
+			Token:           "TKN-abcd",
+			TokenIdentifier: "TKN-abcd",
+			Amount:          "100",
+			AmountSortable:  converters.BigIntToSortableString(big.NewInt(100)),
+			Timestamp:       time.Duration(1234),
+		},
+		{
+			ID:              encodedHash("txHash") + "-1",
+			TxHash:          encodedHash("txHash"),
+			Operation:       core.BuiltInFunctionMultiMECTNFTTransfer,
+			Sender:          hex.EncodeToString([]byte("sender")),
+			Receiver:        hex.EncodeToString([]byte("shard1receiver")),
+			ReceiverShard:   1,
+			Token:           "NFT-abcd",
+			TokenIdentifier: "NFT-abcd-02",
+			Nonce:           2,
+			Amount:          "1",
+			AmountSortable:  converters.BigIntToSortableString(big.NewInt(1)),
+			Timestamp:       time.Duration(1234),
+		},
+		{
+			ID:              encodedHash("scrHash") + "-0",
+			TxHash:          "originalTxHash",
+			ScrHash:         encodedHash("scrHash"),
+			Operation:       core.BuiltInFunctionMECTTransfer,
+			Sender:          hex.EncodeToString([]byte("contract")),
+			Receiver:        hex.EncodeToString([]byte("user")),
+			Token:           "TKN-abcd",
+			TokenIdentifier: "TKN-abcd",
+			Amount:          "5",
+			AmountSortable:  converters.BigIntToSortableString(big.NewInt(5)),
+			Timestamp:       time.Duration(1234),
+		},
+	}, res.Transfers)
+}
+
+func TestLogsAndEventsProcessor_ExtractDataFromLogsShouldPrepareTransfersFromDataField(t *testing.T) {
+	t.Parallel()
+
+	logsAndEvents := []*coreData.LogData{
+		{
+			TxHash: "withEvents",
+			LogHandler: &transaction.Log{
+				Events: []*transaction.Event{
+					{
+						Address:    []byte("sender"),
+						Identifier: []byte(core.BuiltInFunctionMECTTransfer),
+						Topics:     [][]byte{[]byte("TKN-abcd"), big.NewInt(0).Bytes(), big.NewInt(3).Bytes(), []byte("receiver")},
+					},
+				},
+			},
+		},
+	}
+
+	multiTransfer := &data.Transaction{
+		Hash:              encodedHash("multi"),
+		Sender:            "sender",
+		Receiver:          "sender",
+		Status:            transaction.TxStatusSuccess.String(),
+		Operation:         core.BuiltInFunctionMultiMECTNFTTransfer,
+		Tokens:            []string{"TKN-abcd", "NFT-abcd-02"},
+		MECTValues:        []string{"100", "1"},
+		Receivers:         []string{"receiver", "receiver"},
+		ReceiversShardIDs: []uint32{1, 1},
+	}
+	txs := []*data.Transaction{
+		multiTransfer,
+		{
+			Hash:       encodedHash("failed"),
+			Status:     transaction.TxStatusFail.String(),
+			Operation:  core.BuiltInFunctionMECTTransfer,
+			Tokens:     []string{"TKN-abcd"},
+			MECTValues: []string{"1"},
+		},
+		{
+			Hash:        encodedHash("otherShard"),
+			SenderShard: 1,
+			Operation:   core.BuiltInFunctionMECTTransfer,
+			Tokens:      []string{"TKN-abcd"},
+			MECTValues:  []string{"1"},
+		},
+		{
+			Hash:       encodedHash("withEvents"),
+			Operation:  core.BuiltInFunctionMECTTransfer,
+			Tokens:     []string{"TKN-abcd"},
+			MECTValues: []string{"3"},
+		},
+	}
+	scrs := []*data.ScResult{
+		{
+			Hash:       encodedHash("carried"),
+			PrevTxHash: encodedHash("multi"),
+			Operation:  core.BuiltInFunctionMultiMECTNFTTransfer,
+			Tokens:     []string{"TKN-abcd"},
+			MECTValues: []string{"100"},
+		},
+		{
+			Hash:           encodedHash("scr"),
+			PrevTxHash:     "parent",
+			OriginalTxHash: "original",
+			Sender:         "contract",
+			Receiver:       "user",
+			Operation:      core.BuiltInFunctionMECTTransfer,
+			Tokens:         []string{"TKN-abcd"},
+			MECTValues:     []string{"5"},
+		},
+	}
+
+	args := createMockArgs()
+	args.ShardCoordinator = createShardCoordinatorForTransfers()
+	proc, _ := NewLogsAndEventsProcessor(args)
+
+	res := proc.ExtractDataFromLogs(logsAndEvents, &data.PreparedResults{
+		AlteredAccts: data.NewAlteredAccounts(),
+		Transactions: txs,
+		ScResults:    scrs,
+	}, 1234)
+
+	require.Len(t, res.Transfers, 4)
+	require.Equal(t, encodedHash("withEvents")+"-0", res.Transfers[0].ID)
+	require.Equal(t, &data.Transfer{
+		ID:              encodedHash("multi") + "-0",
+		TxHash:          encodedHash("multi"),
+		Operation:       core.BuiltInFunctionMultiMECTNFTTransfer,
+		Sender:          "sender",
+		Receiver:        "receiver",
+		ReceiverShard:   1,
+		Token:           "TKN-abcd",
+		TokenIdentifier: "TKN-abcd",
+		Amount:          "100",
+		AmountSortable:  converters.BigIntToSortableString(big.NewInt(100)),
+		Timestamp:       time.Duration(1234),
+	}, res.Transfers[1])
+	require.Equal(t, "NFT-abcd", res.Transfers[2].Token)
+	require.Equal(t, "NFT-abcd-02", res.Transfers[2].TokenIdentifier)
+	require.Equal(t, uint64(2), res.Transfers[2].Nonce)
+	require.Equal(t, &data.Transfer{
+		ID:              encodedHash("scr") + "-0",
+		TxHash:          "original",
+		ScrHash:         encodedHash("scr"),
+		Operation:       core.BuiltInFunctionMECTTransfer,
+		Sender:          "contract",
+		Receiver:        "user",
+		Token:           "TKN-abcd",
+		TokenIdentifier: "TKN-abcd",
+		Amount:          "5",
+		AmountSortable:  converters.BigIntToSortableString(big.NewInt(5)),
+		Timestamp:       time.Duration(1234),
+	}, res.Transfers[3])
+}
+
+func TestLogsAndEventsProcessor_PutAmountNumInTransfers(t *testing.T) {
+	t.Parallel()
+
+	proc, _ := NewLogsAndEventsProcessor(createMockArgs())
+
+	transfers := []*data.Transfer{
+		{Token: "TKN-abcd", Amount: "12345"},
+		{Token: "UNK-abcd", Amount: "12345"},
+		{Token: "TKN-abcd", Amount: "invalid"},
+	}
+	proc.PutAmountNumInTransfers(transfers, map[string]uint64{"TKN-abcd": 2})
+
+	require.Equal(t, 123.45, transfers[0].AmountNum)
+	require.Equal(t, 0.0000012345, transfers[1].AmountNum)
+	require.Equal(t, float64(0), transfers[2].AmountNum)
+}
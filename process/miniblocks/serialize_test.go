@@ -20,15 +20,14 @@ func TestMiniblocksProcessor_SerializeBulkMiniBlocks(t *testing.T) {
 		{Hash: "h2", SenderShardID: 0, ReceiverShardID: 2},
 	}
 
-	buffSlice := data.NewBufferSlice(data.DefaultMaxBulkSize)
-	mp.SerializeBulkMiniBlocks(miniblocks, nil, buffSlice, "miniblocks")
-
-	expectedBuff := `{ "index" : { "_index":"miniblocks", "_id" : "h1"} }
-{"senderShard":0,"receiverShard":1,"type":"","timestamp":0}
-{ "index" : { "_index":"miniblocks", "_id" : "h2"} }
-{"senderShard":0,"receiverShard":2,"type":"","timestamp":0}
-`
-	require.Equal(t, expectedBuff, buffSlice.Buffers()[0].String())
+	docs := data.NewDocumentsSlice()
+	mp.SerializeBulkMiniBlocks(miniblocks, nil, docs, "miniblocks")
+
+	expectedDocs := []*data.Document{
+		{Index: "miniblocks", ID: "h1", Action: data.ActionIndex, Body: &data.Miniblock{SenderShardID: 0, ReceiverShardID: 1}},
+		{Index: "miniblocks", ID: "h2", Action: data.ActionIndex, Body: &data.Miniblock{SenderShardID: 0, ReceiverShardID: 2}},
+	}
+	require.Equal(t, expectedDocs, docs.Documents())
 }
 
 func TestMiniblocksProcessor_SerializeBulkMiniBlocksInDB(t *testing.T) {
@@ -41,17 +40,21 @@ func TestMiniblocksProcessor_SerializeBulkMiniBlocksInDB(t *testing.T) {
 		{Hash: "h2", SenderShardID: 0, ReceiverShardID: 2},
 	}
 
-	buffSlice := data.NewBufferSlice(data.DefaultMaxBulkSize)
+	docs := data.NewDocumentsSlice()
 	mp.SerializeBulkMiniBlocks(miniblocks, map[string]bool{
 		"h1": true,
-	}, buffSlice, "miniblocks")
-
-	expectedBuff := `{ "update" : {"_index":"miniblocks", "_id" : "h1" } }
-{ "doc" : { "senderBlockHash" : "", "procTypeS": "" } }
-{ "index" : { "_index":"miniblocks", "_id" : "h2"} }
-{"senderShard":0,"receiverShard":2,"type":"","timestamp":0}
-`
-	require.Equal(t, expectedBuff, buffSlice.Buffers()[0].String())
+	}, docs, "miniblocks")
+
+	expectedDocs := []*data.Document{
+		{
+			Index:  "miniblocks",
+			ID:     "h1",
+			Action: data.ActionUpdate,
+			Fields: map[string]interface{}{"senderBlockHash": "", "procTypeS": ""},
+		},
+		{Index: "miniblocks", ID: "h2", Action: data.ActionIndex, Body: &data.Miniblock{SenderShardID: 0, ReceiverShardID: 2}},
+	}
+	require.Equal(t, expectedDocs, docs.Documents())
 }
 
 func TestSerializeMiniblock_CrossShardNormal(t *testing.T) {
@@ -61,15 +64,20 @@ func TestSerializeMiniblock_CrossShardNormal(t *testing.T) {
 		{Hash: "h1", SenderShardID: 0, ReceiverShardID: 1, ReceiverBlockHash: "receiverBlock"},
 	}
 
-	buffSlice := data.NewBufferSlice(data.DefaultMaxBulkSize)
+	docs := data.NewDocumentsSlice()
 	mp.SerializeBulkMiniBlocks(miniblocks, map[string]bool{
 		"h1": true,
-	}, buffSlice, "miniblocks")
-
-	expectedBuff := `{ "update" : {"_index":"miniblocks", "_id" : "h1" } }
-{ "doc" : { "receiverBlockHash" : "receiverBlock", "procTypeD": "" } }
-`
-	require.Equal(t, expectedBuff, buffSlice.Buffers()[0].String())
+	}, docs, "miniblocks")
+
+	expectedDocs := []*data.Document{
+		{
+			Index:  "miniblocks",
+			ID:     "h1",
+			Action: data.ActionUpdate,
+			Fields: map[string]interface{}{"receiverBlockHash": "receiverBlock", "procTypeD": ""},
+		},
+	}
+	require.Equal(t, expectedDocs, docs.Documents())
 }
 
 func TestSerializeMiniblock_IntraShardScheduled(t *testing.T) {
@@ -80,28 +88,39 @@ func TestSerializeMiniblock_IntraShardScheduled(t *testing.T) {
 			ProcessingTypeOnSource: block.Scheduled.String()},
 	}
 
-	buffSlice := data.NewBufferSlice(data.DefaultMaxBulkSize)
+	docs := data.NewDocumentsSlice()
 	mp.SerializeBulkMiniBlocks(miniblocks, map[string]bool{
 		"h1": false,
-	}, buffSlice, "miniblocks")
-
-	expectedBuff := `{ "index" : { "_index":"miniblocks", "_id" : "h1"} }
-{"senderShard":1,"receiverShard":1,"senderBlockHash":"senderBlock","type":"","procTypeS":"Scheduled","timestamp":0}
-`
-	require.Equal(t, expectedBuff, buffSlice.Buffers()[0].String())
+	}, docs, "miniblocks")
+
+	expectedDocs := []*data.Document{
+		{
+			Index:  "miniblocks",
+			ID:     "h1",
+			Action: data.ActionIndex,
+			Body: &data.Miniblock{SenderShardID: 1, ReceiverShardID: 1, SenderBlockHash: "senderBlock",
+				ProcessingTypeOnSource: block.Scheduled.String()},
+		},
+	}
+	require.Equal(t, expectedDocs, docs.Documents())
 
 	miniblocks = []*data.Miniblock{
 		{Hash: "h1", SenderShardID: 1, ReceiverShardID: 1, ReceiverBlockHash: "receiverBlock",
 			ProcessingTypeOnDestination: block.Processed.String()},
 	}
 
-	buffSlice = data.NewBufferSlice(data.DefaultMaxBulkSize)
+	docs = data.NewDocumentsSlice()
 	mp.SerializeBulkMiniBlocks(miniblocks, map[string]bool{
 		"h1": true,
-	}, buffSlice, "miniblocks")
-
-	expectedBuff = `{ "update" : {"_index":"miniblocks", "_id" : "h1" } }
-{ "doc" : { "receiverBlockHash" : "receiverBlock", "procTypeD": "Processed" } }
-`
-	require.Equal(t, expectedBuff, buffSlice.Buffers()[0].String())
+	}, docs, "miniblocks")
+
+	expectedDocs = []*data.Document{
+		{
+			Index:  "miniblocks",
+			ID:     "h1",
+			Action: data.ActionUpdate,
+			Fields: map[string]interface{}{"receiverBlockHash": "receiverBlock", "procTypeD": "Processed"},
+		},
+	}
+	require.Equal(t, expectedDocs, docs.Documents())
 }
@@ -1,51 +1,31 @@
 package operations
 
 import (
-	"encoding/json"
-	"fmt"
-
-	"github.com/ME-MotherEarth/me-elastic-indexer/converters"
 	"github.com/ME-MotherEarth/me-elastic-indexer/data"
 )
 
 // SerializeSCRs will serialize smart contract results
-func (op *operationsProcessor) SerializeSCRs(scrs []*data.ScResult, buffSlice *data.BufferSlice, index string) error {
+func (op *operationsProcessor) SerializeSCRs(scrs []*data.ScResult, docs *data.DocumentsSlice, index string) error {
 	for _, scr := range scrs {
-		meta, serializedData, err := op.prepareSerializedDataForAScResult(scr, index)
-		if err != nil {
-			return err
-		}
-
-		err = buffSlice.PutData(meta, serializedData)
-		if err != nil {
-			return err
-		}
+		docs.Add(op.prepareScResultDocument(scr, index))
 	}
 
 	return nil
 }
 
-func (op *operationsProcessor) prepareSerializedDataForAScResult(
-	scr *data.ScResult,
-	index string,
-) ([]byte, []byte, error) {
-	metaData := []byte(fmt.Sprintf(`{"update":{"_index":"%s","_id":"%s"}}%s`, index, converters.JsonEscape(scr.Hash), "\n"))
-	marshaledSCR, err := json.Marshal(scr)
-	if err != nil {
-		return nil, nil, err
+func (op *operationsProcessor) prepareScResultDocument(scr *data.ScResult, index string) *data.Document {
+	document := &data.Document{
+		Index:  index,
+		ID:     scr.Hash,
+		Action: data.ActionIndex,
+		Body:   scr,
 	}
 
 	selfShardID := op.shardCoordinator.SelfId()
 	isCrossShardOnSourceShard := scr.SenderShard != scr.ReceiverShard && scr.SenderShard == selfShardID
 	if isCrossShardOnSourceShard {
-		serializedData :=
-			[]byte(fmt.Sprintf(`{"script":{"source":"return"},"upsert":%s}`,
-				string(marshaledSCR)))
-
-		return metaData, serializedData, nil
+		document.Action = data.ActionCreate
 	}
 
-	meta := []byte(fmt.Sprintf(`{ "index" : { "_index":"%s","_id" : "%s" } }%s`, index, converters.JsonEscape(scr.Hash), "\n"))
-
-	return meta, marshaledSCR, nil
+	return document
 }
@@ -24,12 +24,13 @@ func TestOperationsProcessor_SerializeSCRS(t *testing.T) {
 		},
 	}
 
-	buffSlice := data.NewBufferSlice(data.DefaultMaxBulkSize)
-	err := op.SerializeSCRs(scrs, buffSlice, "operations")
+	docs := data.NewDocumentsSlice()
+	err := op.SerializeSCRs(scrs, docs, "operations")
 	require.Nil(t, err)
-	require.Equal(t, `{"update":{"_index":"operations","_id":""}}
-{"script":{"source":"return"},"upsert":{"nonce":0,"gasLimit":0,"gasPrice":0,"value":"","sender":"","receiver":"","senderShard":0,"receiverShard":1,"prevTxHash":"","originalTxHash":"","callType":"","timestamp":0}}
-{ "index" : { "_index":"operations","_id" : "" } }
-{"nonce":0,"gasLimit":0,"gasPrice":0,"value":"","sender":"","receiver":"","senderShard":2,"receiverShard":0,"prevTxHash":"","originalTxHash":"","callType":"","timestamp":0}
-`, buffSlice.Buffers()[0].String())
+
+	expectedDocs := []*data.Document{
+		{Index: "operations", Action: data.ActionCreate, Body: scrs[0]},
+		{Index: "operations", Action: data.ActionIndex, Body: scrs[1]},
+	}
+	require.Equal(t, expectedDocs, docs.Documents())
 }
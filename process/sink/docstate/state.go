@@ -0,0 +1,392 @@
+package docstate
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/ME-MotherEarth/me-elastic-indexer/data"
+)
+
+type objectsMap = map[string]interface{}
+
+// State holds the content of a stored document while the documents written for it are applied. It has the same
+// semantics as the painless script used by the elasticsearch sink, so the backends that cannot run the script apply
+// the documents with it
+type State struct {
+	raw     []byte
+	source  objectsMap
+	exists  bool
+	changed bool
+}
+
+// NewState will create the state of a document from its stored content. A nil content means the document does not exist
+func NewState(raw []byte) *State {
+	return &State{
+		raw:    raw,
+		exists: raw != nil,
+	}
+}
+
+// IsPlainIndex returns true if the document replaces the stored one without reading it
+func IsPlainIndex(document *data.Document) bool {
+	return document.Action == data.ActionIndex && !isConditional(document) && len(document.KeepFields) == 0
+}
+
+// IsPlainDelete returns true if the document removes the stored one without reading it
+func IsPlainDelete(document *data.Document) bool {
+	return document.Action == data.ActionDelete && !isConditional(document)
+}
+
+func isConditional(document *data.Document) bool {
+	return document.Timestamp != 0 || document.Nonce != 0
+}
+
+// Apply will change the state as the provided document requires
+func (ds *State) Apply(document *data.Document) error {
+	if IsPlainIndex(document) {
+		return ds.replaceWithBody(document.Body)
+	}
+	if IsPlainDelete(document) {
+		ds.remove()
+		return nil
+	}
+
+	if !ds.exists {
+		canCreate := document.Action == data.ActionIndex || document.Action == data.ActionCreate || document.Action == data.ActionUpsert
+		if !canCreate || document.Body == nil {
+			return nil
+		}
+
+		return ds.replaceWithBody(document.Body)
+	}
+
+	if document.Action == data.ActionCreate {
+		return nil
+	}
+
+	source, err := ds.getSource()
+	if err != nil {
+		return err
+	}
+
+	if document.Timestamp != 0 && getUint64Field(source, "timestamp") > document.Timestamp {
+		return nil
+	}
+	if document.Nonce != 0 && getUint64Field(source, "nonce") > document.Nonce {
+		return nil
+	}
+
+	switch document.Action {
+	case data.ActionDelete:
+		ds.remove()
+		return nil
+	case data.ActionIndex:
+		return ds.replaceKeepingFields(source, document)
+	default:
+		return ds.applyChanges(source, document)
+	}
+}
+
+func (ds *State) replaceWithBody(body interface{}) error {
+	raw, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	ds.raw = raw
+	ds.source = nil
+	ds.exists = true
+	ds.changed = true
+
+	return nil
+}
+
+func (ds *State) replaceKeepingFields(source objectsMap, document *data.Document) error {
+	newSource := make(objectsMap)
+	err := normalize(document.Body, &newSource)
+	if err != nil {
+		return err
+	}
+
+	for _, field := range document.KeepFields {
+		value, ok := source[field]
+		if ok {
+			newSource[field] = value
+		}
+	}
+
+	ds.setSource(newSource)
+
+	return nil
+}
+
+func (ds *State) applyChanges(source objectsMap, document *data.Document) error {
+	var fields objectsMap
+	var appendValues, addToSet, removeFromSet map[string][]interface{}
+	err := normalizeAll(
+		document.Fields, &fields,
+		document.Append, &appendValues,
+		document.AddToSet, &addToSet,
+		document.RemoveFromSet, &removeFromSet,
+	)
+	if err != nil {
+		return err
+	}
+
+	for path, value := range fields {
+		parent := ParentOf(source, path, value != nil)
+		if parent == nil {
+			continue
+		}
+
+		if value == nil {
+			delete(parent, LastKey(path))
+		} else {
+			parent[LastKey(path)] = value
+		}
+	}
+
+	for path, value := range document.Increments {
+		parent := ParentOf(source, path, true)
+		key := LastKey(path)
+		parent[key] = json.Number(strconv.FormatInt(toInt64(parent[key])+value, 10))
+	}
+
+	for path, values := range appendValues {
+		parent := ParentOf(source, path, true)
+		key := LastKey(path)
+		parent[key] = append(toList(parent[key]), values...)
+	}
+
+	for path, values := range addToSet {
+		parent := ParentOf(source, path, true)
+		key := LastKey(path)
+		list := toList(parent[key])
+		for _, value := range values {
+			if !contains(list, value) {
+				list = append(list, value)
+			}
+		}
+		parent[key] = list
+	}
+
+	for path, values := range removeFromSet {
+		parent := ParentOf(source, path, false)
+		if parent == nil {
+			continue
+		}
+
+		list, ok := parent[LastKey(path)].([]interface{})
+		if !ok {
+			continue
+		}
+
+		remaining := make([]interface{}, 0, len(list))
+		for _, value := range list {
+			if !contains(values, value) {
+				remaining = append(remaining, value)
+			}
+		}
+		parent[LastKey(path)] = remaining
+	}
+
+	if document.DeleteIfEmpty {
+		cleanup(source, document.ChangedPaths())
+		if len(source) == 0 {
+			ds.remove()
+			return nil
+		}
+	}
+
+	ds.setSource(source)
+
+	return nil
+}
+
+func (ds *State) getSource() (objectsMap, error) {
+	if ds.source != nil {
+		return ds.source, nil
+	}
+
+	source := make(objectsMap)
+	decoder := json.NewDecoder(bytes.NewReader(ds.raw))
+	decoder.UseNumber()
+	err := decoder.Decode(&source)
+	if err != nil {
+		return nil, err
+	}
+
+	ds.source = source
+
+	return source, nil
+}
+
+func (ds *State) setSource(source objectsMap) {
+	ds.source = source
+	ds.raw = nil
+	ds.exists = true
+	ds.changed = true
+}
+
+// Exists returns true if the document exists after the applied documents
+func (ds *State) Exists() bool {
+	return ds.exists
+}
+
+// Changed returns true if the applied documents changed the stored content
+func (ds *State) Changed() bool {
+	return ds.changed
+}
+
+func (ds *State) remove() {
+	ds.source = nil
+	ds.raw = nil
+	ds.exists = false
+	ds.changed = true
+}
+
+// Serialize returns the content of the document
+func (ds *State) Serialize() ([]byte, error) {
+	if ds.raw != nil {
+		return ds.raw, nil
+	}
+
+	return json.Marshal(ds.source)
+}
+
+// normalize will convert the provided value in the generic form of its json representation, so it can be compared with
+// the values of the stored documents
+func normalize(value interface{}, result interface{}) error {
+	serialized, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(serialized))
+	decoder.UseNumber()
+
+	return decoder.Decode(result)
+}
+
+func normalizeAll(valuesAndResults ...interface{}) error {
+	for idx := 0; idx < len(valuesAndResults); idx += 2 {
+		err := normalize(valuesAndResults[idx], valuesAndResults[idx+1])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ParentOf returns the object that holds the field addressed by the provided dotted path. The missing objects are
+// created only if create is true, otherwise nil is returned
+func ParentOf(source objectsMap, path string, create bool) objectsMap {
+	keys := strings.Split(path, ".")
+	obj := source
+	for _, key := range keys[:len(keys)-1] {
+		child, ok := obj[key].(objectsMap)
+		if !ok {
+			if !create {
+				return nil
+			}
+
+			child = make(objectsMap)
+			obj[key] = child
+		}
+		obj = child
+	}
+
+	return obj
+}
+
+// LastKey returns the name of the field addressed by the provided dotted path
+func LastKey(path string) string {
+	keys := strings.Split(path, ".")
+	return keys[len(keys)-1]
+}
+
+func cleanup(source objectsMap, paths []string) {
+	for _, path := range paths {
+		keys := strings.Split(path, ".")
+		for depth := len(keys); depth > 0; depth-- {
+			obj := source
+			for _, key := range keys[:depth-1] {
+				obj, _ = obj[key].(objectsMap)
+				if obj == nil {
+					break
+				}
+			}
+			if obj == nil {
+				continue
+			}
+
+			if isEmptyContainer(obj[keys[depth-1]]) {
+				delete(obj, keys[depth-1])
+			}
+		}
+	}
+}
+
+func isEmptyContainer(value interface{}) bool {
+	switch v := value.(type) {
+	case objectsMap:
+		return len(v) == 0
+	case []interface{}:
+		return len(v) == 0
+	default:
+		return false
+	}
+}
+
+func getUint64Field(source objectsMap, field string) uint64 {
+	number, ok := source[field].(json.Number)
+	if !ok {
+		return 0
+	}
+
+	value, err := strconv.ParseUint(number.String(), 10, 64)
+	if err != nil {
+		floatValue, _ := number.Float64()
+		return uint64(floatValue)
+	}
+
+	return value
+}
+
+func toInt64(value interface{}) int64 {
+	number, ok := value.(json.Number)
+	if !ok {
+		return 0
+	}
+
+	intValue, err := number.Int64()
+	if err != nil {
+		floatValue, _ := number.Float64()
+		return int64(floatValue)
+	}
+
+	return intValue
+}
+
+func toList(value interface{}) []interface{} {
+	list, ok := value.([]interface{})
+	if !ok {
+		return make([]interface{}, 0)
+	}
+
+	return list
+}
+
+func contains(list []interface{}, value interface{}) bool {
+	for _, element := range list {
+		if reflect.DeepEqual(element, value) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,152 @@
+package docstate
+
+import (
+	"testing"
+
+	"github.com/ME-MotherEarth/me-elastic-indexer/data"
+	"github.com/stretchr/testify/require"
+)
+
+func applyAndSerialize(t *testing.T, stored string, document *data.Document) (string, bool) {
+	var raw []byte
+	if stored != "" {
+		raw = []byte(stored)
+	}
+
+	state := NewState(raw)
+	err := state.Apply(document)
+	require.Nil(t, err)
+
+	if !state.Exists() {
+		return "", state.Changed()
+	}
+
+	serialized, err := state.Serialize()
+	require.Nil(t, err)
+
+	return string(serialized), state.Changed()
+}
+
+func TestState_ApplyOnMissingDocument(t *testing.T) {
+	t.Parallel()
+
+	result, changed := applyAndSerialize(t, "", &data.Document{
+		Action: data.ActionUpsert,
+		Body:   map[string]interface{}{"tag": "art", "count": 1},
+		Fields: map[string]interface{}{"tag": "art"},
+	})
+	require.True(t, changed)
+	require.Equal(t, `{"count":1,"tag":"art"}`, result)
+
+	_, changed = applyAndSerialize(t, "", &data.Document{
+		Action: data.ActionUpdate,
+		Fields: map[string]interface{}{"type": "NonFungibleMECT"},
+	})
+	require.False(t, changed)
+}
+
+func TestState_CreateShouldNotOverwrite(t *testing.T) {
+	t.Parallel()
+
+	_, changed := applyAndSerialize(t, `{"status":"success"}`, &data.Document{
+		Action: data.ActionCreate,
+		Body:   map[string]interface{}{"status": "pending"},
+	})
+	require.False(t, changed)
+}
+
+func TestState_OlderTimestampShouldBeIgnored(t *testing.T) {
+	t.Parallel()
+
+	_, changed := applyAndSerialize(t, `{"balance":"10","timestamp":200}`, &data.Document{
+		Action:    data.ActionIndex,
+		Body:      map[string]interface{}{"balance": "5", "timestamp": 100},
+		Timestamp: 100,
+	})
+	require.False(t, changed)
+
+	result, changed := applyAndSerialize(t, `{"balance":"10","timestamp":200}`, &data.Document{
+		Action:    data.ActionIndex,
+		Body:      map[string]interface{}{"balance": "15", "timestamp": 300},
+		Timestamp: 300,
+	})
+	require.True(t, changed)
+	require.Equal(t, `{"balance":"15","timestamp":300}`, result)
+}
+
+func TestState_LowerNonceShouldBeIgnored(t *testing.T) {
+	t.Parallel()
+
+	_, changed := applyAndSerialize(t, `{"hash":"h7","nonce":7}`, &data.Document{
+		Action: data.ActionIndex,
+		Body:   map[string]interface{}{"hash": "h5", "nonce": 5},
+		Nonce:  5,
+	})
+	require.False(t, changed)
+
+	result, changed := applyAndSerialize(t, `{"hash":"h7","nonce":7}`, &data.Document{
+		Action: data.ActionIndex,
+		Body:   map[string]interface{}{"hash": "h8", "nonce": 8},
+		Nonce:  8,
+	})
+	require.True(t, changed)
+	require.Equal(t, `{"hash":"h8","nonce":8}`, result)
+}
+
+func TestState_IndexShouldKeepFields(t *testing.T) {
+	t.Parallel()
+
+	result, _ := applyAndSerialize(t, `{"name":"old","roles":{"MECTRoleNFTCreate":["moa1"]}}`, &data.Document{
+		Action:     data.ActionIndex,
+		Body:       map[string]interface{}{"name": "new"},
+		KeepFields: []string{"roles"},
+	})
+	require.Equal(t, `{"name":"new","roles":{"MECTRoleNFTCreate":["moa1"]}}`, result)
+}
+
+func TestState_ApplyChanges(t *testing.T) {
+	t.Parallel()
+
+	result, _ := applyAndSerialize(t, `{"count":2,"roles":{"MECTRoleNFTBurn":["moa1","moa2"]},"history":[{"a":1}]}`, &data.Document{
+		Action:        data.ActionUpdate,
+		Fields:        map[string]interface{}{"properties.canMint": true, "currentOwner": "moa3"},
+		Increments:    map[string]int64{"count": 3},
+		Append:        map[string][]interface{}{"history": {map[string]interface{}{"a": 2}}},
+		AddToSet:      map[string][]interface{}{"roles.MECTRoleNFTCreate": {"moa1"}, "roles.MECTRoleNFTBurn": {"moa2"}},
+		RemoveFromSet: map[string][]interface{}{"roles.MECTRoleNFTBurn": {"moa1"}},
+	})
+
+	expected := `{"count":5,"currentOwner":"moa3","history":[{"a":1},{"a":2}],"properties":{"canMint":true},` +
+		`"roles":{"MECTRoleNFTBurn":["moa2"],"MECTRoleNFTCreate":["moa1"]}}`
+	require.Equal(t, expected, result)
+}
+
+func TestState_DeleteIfEmptyShouldRemoveDocument(t *testing.T) {
+	t.Parallel()
+
+	result, changed := applyAndSerialize(t, `{"token":{"01":"10","02":"5"}}`, &data.Document{
+		Action:        data.ActionUpdate,
+		Fields:        map[string]interface{}{"token.01": nil},
+		DeleteIfEmpty: true,
+	})
+	require.True(t, changed)
+	require.Equal(t, `{"token":{"02":"5"}}`, result)
+
+	result, changed = applyAndSerialize(t, `{"token":{"01":"10"}}`, &data.Document{
+		Action:        data.ActionUpdate,
+		Fields:        map[string]interface{}{"token.01": nil},
+		DeleteIfEmpty: true,
+	})
+	require.True(t, changed)
+	require.Equal(t, "", result)
+}
+
+func TestState_AddToSetShouldCompareSerializedValues(t *testing.T) {
+	t.Parallel()
+
+	result, _ := applyAndSerialize(t, `{"data":{"uris":["dXJp"]}}`, &data.Document{
+		Action:   data.ActionUpdate,
+		AddToSet: map[string][]interface{}{"data.uris": {[]byte("uri"), []byte("uri2")}},
+	})
+	require.Equal(t, `{"data":{"uris":["dXJp","dXJpMg=="]}}`, result)
+}
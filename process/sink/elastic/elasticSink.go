@@ -0,0 +1,263 @@
+package elastic
+
+import (
+	"bytes"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/ME-MotherEarth/me-core/core/check"
+	elasticIndexer "github.com/ME-MotherEarth/me-elastic-indexer"
+	"github.com/ME-MotherEarth/me-elastic-indexer/data"
+	logger "github.com/ME-MotherEarth/me-logger"
+)
+
+var log = logger.GetOrCreate("indexer/process/sink/elastic")
+
+// ArgsElasticSink holds all dependencies required by the elasticSink in order to create new instances
+type ArgsElasticSink struct {
+	DBClient                  DatabaseClientHandler
+	MetricsHandler            BulkMetricsHandler
+	BulkRequestMaxSize        int
+	NumConcurrentBulkRequests int
+	UseKibana                 bool
+	IndexTemplates            map[string]*bytes.Buffer
+	IndexPolicies             map[string]*bytes.Buffer
+	Rollover                  RolloverConditions
+	IndexPrefix               string
+	AllowMappingsConflicts    bool
+	CheckMigrations           bool
+	CustomIndices             []string
+}
+
+type elasticSink struct {
+	elasticClient             DatabaseClientHandler
+	metricsHandler            BulkMetricsHandler
+	bulkRequestMaxSize        int
+	numConcurrentBulkRequests int
+	rollingIndices            map[string]struct{}
+	indexes                   []string
+	indexPrefix               string
+	allowMappingsConflicts    bool
+}
+
+type responseDocuments struct {
+	Docs []responseDocument `json:"docs"`
+}
+
+type responseDocument struct {
+	ID     string          `json:"_id"`
+	Found  bool            `json:"found"`
+	Source json.RawMessage `json:"_source"`
+}
+
+// NewElasticSink will create the indices, templates and aliases that do not exist yet and will return a documents
+// sink that writes in elasticsearch server. If rollover conditions are provided, the rolling indices get a policy
+// that rolls them over and an alias that writes in the last backing index and reads from all of them. If an index
+// prefix is provided, it is added to the names of all the indices, aliases, templates and policies, so several
+// networks can be indexed in the same cluster. The templates that differ from the stored ones are replaced and the new
+// fields are added to the existing indices, while a field whose mapping changed stops the sink creation, unless the
+// mappings conflicts are allowed, as the index has to be reindexed. If the migrations are checked, a cluster with
+// pending schema migrations stops the sink creation too. The custom indices, declared by the event processors added
+// by integrators, are created along with the built-in ones, from the templates provided with them
+func NewElasticSink(args ArgsElasticSink) (*elasticSink, error) {
+	if check.IfNil(args.DBClient) {
+		return nil, elasticIndexer.ErrNilDatabaseClient
+	}
+	if check.IfNil(args.MetricsHandler) {
+		return nil, elasticIndexer.ErrNilMetricsHandler
+	}
+
+	allIndexes, err := createIndexesList(args.CustomIndices)
+	if err != nil {
+		return nil, err
+	}
+
+	es := &elasticSink{
+		elasticClient:             args.DBClient,
+		metricsHandler:            args.MetricsHandler,
+		bulkRequestMaxSize:        args.BulkRequestMaxSize,
+		numConcurrentBulkRequests: args.NumConcurrentBulkRequests,
+		rollingIndices:            make(map[string]struct{}),
+		indexes:                   allIndexes,
+		indexPrefix:               args.IndexPrefix,
+		allowMappingsConflicts:    args.AllowMappingsConflicts,
+	}
+	if args.Rollover.IsEnabled() {
+		for index := range rollingIndicesPolicies {
+			es.rollingIndices[index] = struct{}{}
+		}
+	}
+
+	err = es.init(args.UseKibana, args.IndexTemplates, args.Rollover)
+	if err != nil {
+		return nil, err
+	}
+
+	if args.CheckMigrations {
+		err = es.checkSchemaVersion()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return es, nil
+}
+
+// WriteDocuments will serialize the provided documents in bulk requests and will send them to elasticsearch server
+func (es *elasticSink) WriteDocuments(documents []*data.Document) error {
+	backingIndices, err := es.findBackingIndices(documents)
+	if err != nil {
+		return err
+	}
+
+	buffers := data.NewBufferSlicePerIndex(es.bulkRequestMaxSize)
+	for _, document := range documents {
+		for _, target := range es.withBackingIndices(document, backingIndices) {
+			meta, serializedData, errSerialize := serializeDocument(target)
+			if errSerialize != nil {
+				return errSerialize
+			}
+
+			err = buffers.Get(document.Index).PutData(meta, serializedData)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return es.doBulkRequestsPerIndex(buffers)
+}
+
+// GetDocuments will return the source of the documents with the provided ids that exist in the provided index
+func (es *elasticSink) GetDocuments(index string, ids []string) (map[string][]byte, error) {
+	documents := make(map[string][]byte)
+	if len(ids) == 0 {
+		return documents, nil
+	}
+	if es.isRolling(index) {
+		return es.getDocumentsFromAllIndices(index, ids)
+	}
+
+	response := &responseDocuments{}
+	err := es.elasticClient.DoMultiGet(ids, es.prefixed(index), true, response)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, doc := range response.Docs {
+		if !doc.Found {
+			continue
+		}
+
+		documents[doc.ID] = doc.Source
+	}
+
+	return documents, nil
+}
+
+// IterateMatchingIDs will call the provided handler with the ids of the documents that match all the provided fields
+func (es *elasticSink) IterateMatchingIDs(index string, fields map[string]interface{}, handlerFunc func(ids []string) error) error {
+	query, err := prepareMatchingQuery(fields)
+	if err != nil {
+		return err
+	}
+
+	resultsCount, err := es.elasticClient.DoCountRequest(es.prefixed(index), query)
+	if err != nil || resultsCount == 0 {
+		return err
+	}
+
+	scrollHandler := func(responseBytes []byte) error {
+		responseScroll := &data.ResponseScroll{}
+		errUnmarshal := json.Unmarshal(responseBytes, responseScroll)
+		if errUnmarshal != nil {
+			return errUnmarshal
+		}
+
+		ids := make([]string, 0, len(responseScroll.Hits.Hits))
+		for _, res := range responseScroll.Hits.Hits {
+			ids = append(ids, res.ID)
+		}
+
+		return handlerFunc(ids)
+	}
+
+	return es.elasticClient.DoScrollRequest(es.prefixed(index), query, false, scrollHandler)
+}
+
+// DeleteMatching will remove all the documents that match all the provided fields
+func (es *elasticSink) DeleteMatching(index string, fields map[string]interface{}) error {
+	query, err := prepareMatchingQuery(fields)
+	if err != nil {
+		return err
+	}
+
+	return es.elasticClient.DoQueryRemove(es.prefixed(index), bytes.NewBuffer(query))
+}
+
+// doBulkRequestsPerIndex will send the bulk requests of every index. The buffers of the same index are always sent
+// sequentially, while up to numConcurrentBulkRequests indices are handled at the same time. The documents rejected by
+// elasticsearch do not stop the other requests, they are all returned at the end
+func (es *elasticSink) doBulkRequestsPerIndex(buffers *data.BufferSlicePerIndex) error {
+	indices := buffers.Indices()
+	rejected := &data.RejectedDocumentsError{}
+	if es.numConcurrentBulkRequests <= 1 || len(indices) <= 1 {
+		for _, index := range indices {
+			err := rejected.Add(es.doBulkRequests(index, buffers.Get(index).Buffers()))
+			if err != nil {
+				return err
+			}
+		}
+
+		return rejected.AsError()
+	}
+
+	semaphore := make(chan struct{}, es.numConcurrentBulkRequests)
+	errs := make([]error, len(indices))
+	wg := sync.WaitGroup{}
+	wg.Add(len(indices))
+	for idx, index := range indices {
+		semaphore <- struct{}{}
+		go func(idx int, index string, buffSlice []*bytes.Buffer) {
+			defer func() {
+				<-semaphore
+				wg.Done()
+			}()
+
+			errs[idx] = es.doBulkRequests(index, buffSlice)
+		}(idx, index, buffers.Get(index).Buffers())
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		err = rejected.Add(err)
+		if err != nil {
+			return err
+		}
+	}
+
+	return rejected.AsError()
+}
+
+func (es *elasticSink) doBulkRequests(index string, buffSlice []*bytes.Buffer) error {
+	rejected := &data.RejectedDocumentsError{}
+	for idx := range buffSlice {
+		sizeInBytes := buffSlice[idx].Len()
+		startTime := time.Now()
+		err := es.elasticClient.DoBulkRequest(buffSlice[idx], "")
+		es.metricsHandler.ObserveBulkRequest(index, sizeInBytes, time.Since(startTime), err)
+
+		err = rejected.Add(err)
+		if err != nil {
+			return err
+		}
+	}
+
+	return rejected.AsError()
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (es *elasticSink) IsInterfaceNil() bool {
+	return es == nil
+}
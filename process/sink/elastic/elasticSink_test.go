@@ -0,0 +1,337 @@
+package elastic
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	elasticIndexer "github.com/ME-MotherEarth/me-elastic-indexer"
+	"github.com/ME-MotherEarth/me-elastic-indexer/data"
+	"github.com/ME-MotherEarth/me-elastic-indexer/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func createMockArgsElasticSink() ArgsElasticSink {
+	return ArgsElasticSink{
+		DBClient:                  &mock.DatabaseWriterStub{},
+		MetricsHandler:            &mock.MetricsHandlerStub{},
+		BulkRequestMaxSize:        1 << 20,
+		NumConcurrentBulkRequests: 1,
+	}
+}
+
+func TestNewElasticSink(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil database client should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsElasticSink()
+		args.DBClient = nil
+		es, err := NewElasticSink(args)
+		require.Nil(t, es)
+		require.Equal(t, elasticIndexer.ErrNilDatabaseClient, err)
+	})
+	t.Run("nil metrics handler should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsElasticSink()
+		args.MetricsHandler = nil
+		es, err := NewElasticSink(args)
+		require.Nil(t, es)
+		require.Equal(t, elasticIndexer.ErrNilMetricsHandler, err)
+	})
+	t.Run("init error should error", func(t *testing.T) {
+		t.Parallel()
+
+		localErr := errors.New("local error")
+		args := createMockArgsElasticSink()
+		args.DBClient = &mock.DatabaseWriterStub{
+			CheckAndCreateIndexCalled: func(index string) error {
+				return localErr
+			},
+		}
+		es, err := NewElasticSink(args)
+		require.Nil(t, es)
+		require.True(t, errors.Is(err, localErr))
+	})
+	t.Run("with kibana should work", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsElasticSink()
+		args.UseKibana = true
+		es, err := NewElasticSink(args)
+		require.Nil(t, err)
+		require.False(t, es.IsInterfaceNil())
+	})
+	t.Run("custom index replacing a built-in index should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMockArgsElasticSink()
+		args.CustomIndices = []string{"myevents", elasticIndexer.LogsIndex}
+		es, err := NewElasticSink(args)
+		require.Nil(t, es)
+		require.True(t, errors.Is(err, elasticIndexer.ErrCustomIndexAlreadyDeclared))
+	})
+	t.Run("custom indices should be created with their template", func(t *testing.T) {
+		t.Parallel()
+
+		createdIndices := make([]string, 0)
+		putTemplates := make([]string, 0)
+		args := createMockArgsElasticSink()
+		args.CustomIndices = []string{"myevents"}
+		args.IndexTemplates = map[string]*bytes.Buffer{
+			"myevents": bytes.NewBufferString(`{"index_patterns":["myevents-*"],"mappings":{"properties":{}}}`),
+		}
+		args.DBClient = &mock.DatabaseWriterStub{
+			CheckAndCreateIndexCalled: func(index string) error {
+				createdIndices = append(createdIndices, index)
+				return nil
+			},
+			PutTemplateCalled: func(templateName string, template *bytes.Buffer) error {
+				putTemplates = append(putTemplates, templateName)
+				return nil
+			},
+		}
+		_, err := NewElasticSink(args)
+		require.Nil(t, err)
+		require.Contains(t, createdIndices, "myevents-000001")
+		require.Equal(t, []string{"myevents"}, putTemplates)
+	})
+	t.Run("should work", func(t *testing.T) {
+		t.Parallel()
+
+		es, err := NewElasticSink(createMockArgsElasticSink())
+		require.Nil(t, err)
+		require.False(t, es.IsInterfaceNil())
+	})
+}
+
+func TestElasticSink_WriteDocumentsShouldKeepTheOrderPerIndex(t *testing.T) {
+	t.Parallel()
+
+	mutex := sync.Mutex{}
+	requestsPerIndex := make(map[string][]string)
+	args := createMockArgsElasticSink()
+	args.BulkRequestMaxSize = 1
+	args.NumConcurrentBulkRequests = 2
+	args.DBClient = &mock.DatabaseWriterStub{
+		DoBulkRequestCalled: func(buff *bytes.Buffer, index string) error {
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			request := buff.String()
+			indexName := strings.Split(strings.Split(request, `"_index":"`)[1], `"`)[0]
+			requestsPerIndex[indexName] = append(requestsPerIndex[indexName], request)
+			return nil
+		},
+	}
+	es, _ := NewElasticSink(args)
+
+	documents := []*data.Document{
+		{Index: "blocks", ID: "b1", Action: data.ActionIndex, Body: map[string]string{"nonce": "1"}},
+		{Index: "transactions", ID: "t1", Action: data.ActionIndex, Body: map[string]string{"status": "pending"}},
+		{Index: "blocks", ID: "b2", Action: data.ActionIndex, Body: map[string]string{"nonce": "2"}},
+		{Index: "transactions", ID: "t1", Action: data.ActionDelete},
+	}
+	err := es.WriteDocuments(documents)
+	require.Nil(t, err)
+
+	require.Len(t, requestsPerIndex["blocks"], 2)
+	require.Contains(t, requestsPerIndex["blocks"][0], `"_id" : "b1"`)
+	require.Contains(t, requestsPerIndex["blocks"][1], `"_id" : "b2"`)
+	require.Len(t, requestsPerIndex["transactions"], 2)
+	require.Contains(t, requestsPerIndex["transactions"][0], `{ "index" :`)
+	require.Contains(t, requestsPerIndex["transactions"][1], `{ "delete" :`)
+}
+
+func TestElasticSink_WriteDocumentsShouldObserveBulkRequestsPerIndex(t *testing.T) {
+	t.Parallel()
+
+	localErr := errors.New("local error")
+	mutex := sync.Mutex{}
+	sizePerIndex := make(map[string]int)
+	errorsPerIndex := make(map[string]error)
+	args := createMockArgsElasticSink()
+	args.NumConcurrentBulkRequests = 2
+	args.DBClient = &mock.DatabaseWriterStub{
+		DoBulkRequestCalled: func(buff *bytes.Buffer, index string) error {
+			if strings.Contains(buff.String(), "miniblocks") {
+				return localErr
+			}
+			return nil
+		},
+	}
+	args.MetricsHandler = &mock.MetricsHandlerStub{
+		ObserveBulkRequestCalled: func(index string, sizeInBytes int, duration time.Duration, err error) {
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			sizePerIndex[index] += sizeInBytes
+			errorsPerIndex[index] = err
+		},
+	}
+	es, _ := NewElasticSink(args)
+
+	err := es.WriteDocuments([]*data.Document{
+		{Index: "blocks", ID: "b1", Action: data.ActionDelete},
+		{Index: "miniblocks", ID: "m1", Action: data.ActionDelete},
+	})
+	require.Equal(t, localErr, err)
+
+	require.Len(t, sizePerIndex, 2)
+	require.True(t, sizePerIndex["blocks"] > 0)
+	require.True(t, sizePerIndex["miniblocks"] > 0)
+	require.Nil(t, errorsPerIndex["blocks"])
+	require.Equal(t, localErr, errorsPerIndex["miniblocks"])
+}
+
+func TestElasticSink_WriteDocumentsErrors(t *testing.T) {
+	t.Parallel()
+
+	t.Run("bulk request error should be returned", func(t *testing.T) {
+		t.Parallel()
+
+		localErr := errors.New("local error")
+		args := createMockArgsElasticSink()
+		args.NumConcurrentBulkRequests = 2
+		args.DBClient = &mock.DatabaseWriterStub{
+			DoBulkRequestCalled: func(buff *bytes.Buffer, index string) error {
+				return localErr
+			},
+		}
+		es, _ := NewElasticSink(args)
+
+		err := es.WriteDocuments([]*data.Document{
+			{Index: "blocks", ID: "b1", Action: data.ActionDelete},
+			{Index: "miniblocks", ID: "m1", Action: data.ActionDelete},
+		})
+		require.Equal(t, localErr, err)
+	})
+	t.Run("rejected documents should be returned after the other indices were written", func(t *testing.T) {
+		t.Parallel()
+
+		mutex := sync.Mutex{}
+		numBulkRequests := 0
+		args := createMockArgsElasticSink()
+		args.NumConcurrentBulkRequests = 2
+		args.DBClient = &mock.DatabaseWriterStub{
+			DoBulkRequestCalled: func(buff *bytes.Buffer, index string) error {
+				mutex.Lock()
+				numBulkRequests++
+				mutex.Unlock()
+
+				return &data.RejectedDocumentsError{Documents: []*data.RejectedDocument{{ID: buff.String()}}}
+			},
+		}
+		es, _ := NewElasticSink(args)
+
+		err := es.WriteDocuments([]*data.Document{
+			{Index: "blocks", ID: "b1", Action: data.ActionDelete},
+			{Index: "miniblocks", ID: "m1", Action: data.ActionDelete},
+		})
+		rejected := &data.RejectedDocumentsError{}
+		require.True(t, errors.As(err, &rejected))
+		require.Len(t, rejected.Documents, 2)
+		require.Equal(t, 2, numBulkRequests)
+	})
+	t.Run("invalid document should error", func(t *testing.T) {
+		t.Parallel()
+
+		es, _ := NewElasticSink(createMockArgsElasticSink())
+
+		err := es.WriteDocuments([]*data.Document{nil})
+		require.NotNil(t, err)
+	})
+}
+
+func TestElasticSink_GetDocuments(t *testing.T) {
+	t.Parallel()
+
+	args := createMockArgsElasticSink()
+	args.DBClient = &mock.DatabaseWriterStub{
+		DoMultiGetCalled: func(ids []string, index string, withSource bool, response interface{}) error {
+			require.Equal(t, []string{"h1", "h2"}, ids)
+			require.Equal(t, "tokens", index)
+			require.True(t, withSource)
+
+			resp := response.(*responseDocuments)
+			resp.Docs = []responseDocument{
+				{ID: "h1", Found: true, Source: []byte(`{"type":"NonFungibleMECT"}`)},
+				{ID: "h2", Found: false},
+			}
+			return nil
+		},
+	}
+	es, _ := NewElasticSink(args)
+
+	documents, err := es.GetDocuments("tokens", []string{"h1", "h2"})
+	require.Nil(t, err)
+	require.Equal(t, map[string][]byte{"h1": []byte(`{"type":"NonFungibleMECT"}`)}, documents)
+}
+
+func TestElasticSink_IterateMatchingIDs(t *testing.T) {
+	t.Parallel()
+
+	args := createMockArgsElasticSink()
+	args.DBClient = &mock.DatabaseWriterStub{
+		DoCountRequestCalled: func(index string, body []byte) (uint64, error) {
+			return 2, nil
+		},
+		DoScrollRequestCalled: func(index string, body []byte, withSource bool, handlerFunc func(responseBytes []byte) error) error {
+			require.Equal(t, "accountsmect", index)
+			require.False(t, withSource)
+			return handlerFunc([]byte(`{"hits":{"hits":[{"_id":"id1"},{"_id":"id2"}]}}`))
+		},
+	}
+	es, _ := NewElasticSink(args)
+
+	var receivedIDs []string
+	err := es.IterateMatchingIDs("accountsmect", map[string]interface{}{"token": "NFT-abcd"}, func(ids []string) error {
+		receivedIDs = append(receivedIDs, ids...)
+		return nil
+	})
+	require.Nil(t, err)
+	require.Equal(t, []string{"id1", "id2"}, receivedIDs)
+}
+
+func TestElasticSink_IterateMatchingIDsNoResultsShouldNotScroll(t *testing.T) {
+	t.Parallel()
+
+	args := createMockArgsElasticSink()
+	args.DBClient = &mock.DatabaseWriterStub{
+		DoScrollRequestCalled: func(index string, body []byte, withSource bool, handlerFunc func(responseBytes []byte) error) error {
+			require.Fail(t, "should have not been called")
+			return nil
+		},
+	}
+	es, _ := NewElasticSink(args)
+
+	err := es.IterateMatchingIDs("accountsmect", map[string]interface{}{"token": "NFT-abcd"}, func(ids []string) error {
+		return nil
+	})
+	require.Nil(t, err)
+}
+
+func TestElasticSink_DeleteMatching(t *testing.T) {
+	t.Parallel()
+
+	called := false
+	args := createMockArgsElasticSink()
+	args.DBClient = &mock.DatabaseWriterStub{
+		DoQueryRemoveCalled: func(index string, body *bytes.Buffer) error {
+			called = true
+			require.Equal(t, "accountsmect", index)
+			require.Equal(t, `{"query":{"bool":{"must":[{"match":{"shardID":{"operator":"AND","query":1}}}]}}}`, body.String())
+			return nil
+		},
+	}
+	es, _ := NewElasticSink(args)
+
+	err := es.DeleteMatching("accountsmect", map[string]interface{}{"shardID": 1})
+	require.Nil(t, err)
+	require.True(t, called)
+}
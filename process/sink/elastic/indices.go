@@ -0,0 +1,170 @@
+package elastic
+
+import (
+	"bytes"
+	"fmt"
+
+	elasticIndexer "github.com/ME-MotherEarth/me-elastic-indexer"
+)
+
+var indexes = []string{
+	elasticIndexer.TransactionsIndex, elasticIndexer.BlockIndex, elasticIndexer.MiniblocksIndex, elasticIndexer.RatingIndex, elasticIndexer.RoundsIndex, elasticIndexer.ValidatorsIndex,
+	elasticIndexer.AccountsIndex, elasticIndexer.AccountsHistoryIndex, elasticIndexer.ReceiptsIndex, elasticIndexer.ScResultsIndex, elasticIndexer.AccountsMECTHistoryIndex, elasticIndexer.AccountsMECTIndex,
+	elasticIndexer.EpochInfoIndex, elasticIndexer.SCDeploysIndex, elasticIndexer.TokensIndex, elasticIndexer.TagsIndex, elasticIndexer.LogsIndex, elasticIndexer.DelegatorsIndex, elasticIndexer.OperationsIndex,
+	elasticIndexer.CollectionsIndex, elasticIndexer.JournalIndex, elasticIndexer.CheckpointsIndex, elasticIndexer.MigrationsIndex, elasticIndexer.EventsIndex,
+	elasticIndexer.TransfersIndex,
+}
+
+// createIndexesList returns the built-in indices followed by the indices declared by the event processors added by
+// integrators, which can not replace a built-in index
+func createIndexesList(customIndices []string) ([]string, error) {
+	builtInIndexes := make(map[string]struct{}, len(indexes))
+	for _, index := range indexes {
+		builtInIndexes[index] = struct{}{}
+	}
+
+	allIndexes := make([]string, 0, len(indexes)+len(customIndices))
+	allIndexes = append(allIndexes, indexes...)
+	for _, index := range customIndices {
+		_, isBuiltIn := builtInIndexes[index]
+		if isBuiltIn || index == elasticIndexer.OpenDistroIndex {
+			return nil, fmt.Errorf("%w: %s", elasticIndexer.ErrCustomIndexAlreadyDeclared, index)
+		}
+
+		allIndexes = append(allIndexes, index)
+	}
+
+	return allIndexes, nil
+}
+
+func (es *elasticSink) init(useKibana bool, indexTemplates map[string]*bytes.Buffer, rollover RolloverConditions) error {
+	err := es.createOpenDistroTemplates(indexTemplates)
+	if err != nil {
+		return err
+	}
+
+	err = es.createRolloverPolicies(useKibana, rollover)
+	if err != nil {
+		return err
+	}
+
+	err = es.createIndexTemplates(useKibana, indexTemplates)
+	if err != nil {
+		return err
+	}
+
+	err = es.createIndexes()
+	if err != nil {
+		return err
+	}
+
+	err = es.createAliases()
+	if err != nil {
+		return err
+	}
+
+	return es.updateMappings(indexTemplates)
+}
+
+// createRolloverPolicies will create the policies of the rolling indices. Elasticsearch uses index lifecycle
+// management policies, while open distro uses index state management policies
+func (es *elasticSink) createRolloverPolicies(useKibana bool, rollover RolloverConditions) error {
+	for _, index := range es.indexes {
+		if !es.isRolling(index) {
+			continue
+		}
+
+		var err error
+		policyName := es.prefixed(rollingIndicesPolicies[index])
+		if useKibana {
+			err = es.elasticClient.CheckAndCreatePolicy(policyName, rollover.stateManagementPolicy(es.prefixed(index)))
+		} else {
+			err = es.elasticClient.CheckAndCreateLifecyclePolicy(policyName, rollover.lifecyclePolicy())
+		}
+		if err != nil {
+			return fmt.Errorf("policy: %s, error: %w", policyName, err)
+		}
+	}
+
+	return nil
+}
+
+func (es *elasticSink) createOpenDistroTemplates(indexTemplates map[string]*bytes.Buffer) error {
+	opendistroTemplate := getTemplateByName(elasticIndexer.OpenDistroIndex, indexTemplates)
+	if opendistroTemplate != nil {
+		err := es.elasticClient.CheckAndCreateTemplate(elasticIndexer.OpenDistroIndex, opendistroTemplate)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// createIndexTemplates will create or update the templates of the indices, matching only the prefixed indices. The
+// templates of the rolling indices also attach the new backing indices to their policy and alias
+func (es *elasticSink) createIndexTemplates(useKibana bool, indexTemplates map[string]*bytes.Buffer) error {
+	for _, index := range es.indexes {
+		indexTemplate := getTemplateByName(index, indexTemplates)
+		if indexTemplate == nil {
+			continue
+		}
+
+		indexTemplate, err := PrefixTemplate(indexTemplate, es.indexPrefix)
+		if err != nil {
+			return fmt.Errorf("index: %s, error: %w", index, err)
+		}
+
+		if es.isRolling(index) {
+			settings := rolloverSettings(es.prefixed(index), es.prefixed(rollingIndicesPolicies[index]), useKibana)
+			indexTemplate, err = withSettings(indexTemplate, settings)
+			if err != nil {
+				return fmt.Errorf("index: %s, error: %w", index, err)
+			}
+		}
+
+		err = es.updateTemplate(es.prefixed(index), indexTemplate)
+		if err != nil {
+			return fmt.Errorf("index: %s, error: %w", index, err)
+		}
+	}
+	return nil
+}
+
+func (es *elasticSink) createIndexes() error {
+	for _, index := range es.indexes {
+		indexName := fmt.Sprintf("%s-%s", es.prefixed(index), elasticIndexer.IndexSuffix)
+		err := es.elasticClient.CheckAndCreateIndex(indexName)
+		if err != nil {
+			return fmt.Errorf("index: %s, error: %w", index, err)
+		}
+	}
+	return nil
+}
+
+func (es *elasticSink) createAliases() error {
+	for _, index := range es.indexes {
+		var err error
+		alias := es.prefixed(index)
+		indexName := fmt.Sprintf("%s-%s", alias, elasticIndexer.IndexSuffix)
+		if es.isRolling(index) {
+			err = es.elasticClient.CheckAndCreateWriteAlias(alias, indexName)
+		} else {
+			err = es.elasticClient.CheckAndCreateAlias(alias, indexName)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func getTemplateByName(templateName string, templateList map[string]*bytes.Buffer) *bytes.Buffer {
+	if template, ok := templateList[templateName]; ok {
+		return template
+	}
+
+	log.Debug("elasticSink.getTemplateByName", "could not find template", templateName)
+	return nil
+}
@@ -0,0 +1,35 @@
+package elastic
+
+import (
+	"bytes"
+	"time"
+)
+
+// DatabaseClientHandler defines the actions that a component that handles requests should do
+type DatabaseClientHandler interface {
+	DoBulkRequest(buff *bytes.Buffer, index string) error
+	DoQueryRemove(index string, buff *bytes.Buffer) error
+	DoMultiGet(ids []string, index string, withSource bool, res interface{}) error
+	DoScrollRequest(index string, body []byte, withSource bool, handlerFunc func(responseBytes []byte) error) error
+	DoCountRequest(index string, body []byte) (uint64, error)
+	DoRefresh(index string) error
+
+	CheckAndCreateIndex(index string) error
+	CheckAndCreateAlias(alias string, index string) error
+	CheckAndCreateWriteAlias(alias string, index string) error
+	CheckAndCreateTemplate(templateName string, template *bytes.Buffer) error
+	CheckAndCreatePolicy(policyName string, policy *bytes.Buffer) error
+	CheckAndCreateLifecyclePolicy(policyName string, policy *bytes.Buffer) error
+	GetTemplate(templateName string) ([]byte, error)
+	PutTemplate(templateName string, template *bytes.Buffer) error
+	GetMappings(index string) (map[string][]byte, error)
+	PutMapping(index string, mapping *bytes.Buffer) error
+
+	IsInterfaceNil() bool
+}
+
+// BulkMetricsHandler defines what the component that records the metrics of the bulk requests should be able to do
+type BulkMetricsHandler interface {
+	ObserveBulkRequest(index string, sizeInBytes int, duration time.Duration, err error)
+	IsInterfaceNil() bool
+}
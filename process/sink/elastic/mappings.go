@@ -0,0 +1,351 @@
+package elastic
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	elasticIndexer "github.com/ME-MotherEarth/me-elastic-indexer"
+)
+
+const (
+	mappingsField       = "mappings"
+	propertiesField     = "properties"
+	multiFieldsField    = "fields"
+	indexSettingsPrefix = "index."
+	missingDefinition   = "none"
+)
+
+// indexDefinition holds the parts of a template, or of the mappings of an index, that are compared, flattened so
+// every setting and every field is found by its path
+type indexDefinition struct {
+	indexPatterns string
+	settings      map[string]string
+	parameters    map[string]string
+	fields        map[string]string
+}
+
+// newTemplateDefinition will flatten the provided template. The settings are normalized the same way elasticsearch
+// returns them, as strings prefixed with "index."
+func newTemplateDefinition(template []byte) (*indexDefinition, error) {
+	decoded := make(map[string]interface{})
+	err := json.Unmarshal(template, &decoded)
+	if err != nil {
+		return nil, err
+	}
+
+	mappings, _ := decoded[mappingsField].(map[string]interface{})
+	definition := newMappingsDefinition(mappings)
+	definition.indexPatterns = normalizeValue(decoded[indexPatternsField])
+
+	settings, _ := decoded[settingsField].(map[string]interface{})
+	flattenSettings(settings, "", definition.settings)
+
+	return definition, nil
+}
+
+// newMappingsDefinition will flatten the provided mappings of an index or of a template
+func newMappingsDefinition(mappings map[string]interface{}) *indexDefinition {
+	definition := &indexDefinition{
+		settings:   make(map[string]string),
+		parameters: make(map[string]string),
+		fields:     make(map[string]string),
+	}
+
+	for name, value := range mappings {
+		if name == propertiesField {
+			continue
+		}
+		definition.parameters[name] = normalizeValue(value)
+	}
+
+	properties, _ := mappings[propertiesField].(map[string]interface{})
+	flattenProperties(properties, "", definition.fields)
+
+	return definition
+}
+
+// templateDifferences returns the differences between the template stored in the cluster and the expected one
+func templateDifferences(liveTemplate []byte, expectedTemplate []byte) ([]string, error) {
+	live, err := newTemplateDefinition(liveTemplate)
+	if err != nil {
+		return nil, err
+	}
+	expected, err := newTemplateDefinition(expectedTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	differences := make([]string, 0)
+	if live.indexPatterns != expected.indexPatterns {
+		differences = append(differences, formatDifference(indexPatternsField, live.indexPatterns, expected.indexPatterns))
+	}
+	differences = append(differences, compareFlattened("setting ", live.settings, expected.settings, true)...)
+	differences = append(differences, compareFlattened("mapping parameter ", live.parameters, expected.parameters, true)...)
+	differences = append(differences, compareFlattened("field ", live.fields, expected.fields, true)...)
+
+	return differences, nil
+}
+
+// mappingsChanges returns the fields of the expected mappings that the index does not have yet, together with the
+// changed mapping parameters, which can be applied in place, and the fields whose mapping differs, which need a reindex
+func mappingsChanges(liveMappings []byte, expectedMappings []byte) ([]string, []string, error) {
+	decodedLive := make(map[string]interface{})
+	err := json.Unmarshal(liveMappings, &decodedLive)
+	if err != nil {
+		return nil, nil, err
+	}
+	decodedExpected := make(map[string]interface{})
+	err = json.Unmarshal(expectedMappings, &decodedExpected)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	live := newMappingsDefinition(decodedLive)
+	expected := newMappingsDefinition(decodedExpected)
+
+	compatible := compareFlattened("mapping parameter ", live.parameters, expected.parameters, false)
+	conflicts := make([]string, 0)
+	for _, field := range sortedKeys(expected.fields) {
+		liveDefinition, found := live.fields[field]
+		if !found {
+			compatible = append(compatible, "new field "+field)
+			continue
+		}
+		if liveDefinition != expected.fields[field] {
+			conflicts = append(conflicts, formatDifference("field "+field, liveDefinition, expected.fields[field]))
+		}
+	}
+
+	return compatible, conflicts, nil
+}
+
+// updateTemplate will create the template, or will replace it if it differs from the stored one. The indices that
+// already exist are not changed by a template. The provided buffer is not consumed, as the mappings of the template
+// are compared later with the ones of the indices
+func (es *elasticSink) updateTemplate(templateName string, template *bytes.Buffer) error {
+	liveTemplate, err := es.elasticClient.GetTemplate(templateName)
+	if err != nil {
+		return err
+	}
+	if liveTemplate == nil {
+		return es.elasticClient.PutTemplate(templateName, bytes.NewBuffer(template.Bytes()))
+	}
+
+	differences, err := templateDifferences(liveTemplate, template.Bytes())
+	if err != nil {
+		return err
+	}
+	if len(differences) == 0 {
+		return nil
+	}
+
+	log.Info("elasticSink.updateTemplate: the template differs from the stored one",
+		"template", templateName, "differences", strings.Join(differences, "; "))
+
+	return es.elasticClient.PutTemplate(templateName, bytes.NewBuffer(template.Bytes()))
+}
+
+// updateMappings will compare the mappings of the existing indices with the ones of their templates. The new fields
+// are added in place, while the fields whose mapping differs, which can be changed only by a reindex, are returned
+// in an error, or only logged if the mappings conflicts are allowed
+func (es *elasticSink) updateMappings(indexTemplates map[string]*bytes.Buffer) error {
+	conflicts := make([]string, 0)
+	for _, index := range es.indexes {
+		template, ok := indexTemplates[index]
+		if !ok {
+			continue
+		}
+
+		indexConflicts, err := es.updateIndexMappings(index, template.Bytes())
+		if err != nil {
+			return fmt.Errorf("index: %s, error: %w", index, err)
+		}
+
+		conflicts = append(conflicts, indexConflicts...)
+	}
+	if len(conflicts) == 0 {
+		return nil
+	}
+
+	if es.allowMappingsConflicts {
+		log.Warn("elasticSink.updateMappings: the indices have to be reindexed to get the mappings of their templates",
+			"conflicts", strings.Join(conflicts, "; "))
+		return nil
+	}
+
+	return fmt.Errorf("%w, the indices have to be reindexed:\n%s", elasticIndexer.ErrMappingsConflict, strings.Join(conflicts, "\n"))
+}
+
+func (es *elasticSink) updateIndexMappings(index string, template []byte) ([]string, error) {
+	expectedMappings, err := extractMappings(template)
+	if err != nil {
+		return nil, err
+	}
+
+	indicesMappings, err := es.elasticClient.GetMappings(es.prefixed(index))
+	if err != nil {
+		return nil, err
+	}
+
+	indicesNames := make([]string, 0, len(indicesMappings))
+	for indexName := range indicesMappings {
+		indicesNames = append(indicesNames, indexName)
+	}
+	sort.Strings(indicesNames)
+
+	conflicts := make([]string, 0)
+	for _, indexName := range indicesNames {
+		compatible, indexConflicts, errCompare := mappingsChanges(indicesMappings[indexName], expectedMappings)
+		if errCompare != nil {
+			return nil, errCompare
+		}
+		if len(indexConflicts) > 0 {
+			for _, conflict := range indexConflicts {
+				conflicts = append(conflicts, fmt.Sprintf("index %s: %s", indexName, conflict))
+			}
+			continue
+		}
+		if len(compatible) == 0 {
+			continue
+		}
+
+		log.Info("elasticSink.updateIndexMappings: updating the mappings of the index",
+			"index", indexName, "changes", strings.Join(compatible, ", "))
+		err = es.elasticClient.PutMapping(indexName, bytes.NewBuffer(expectedMappings))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return conflicts, nil
+}
+
+func extractMappings(template []byte) ([]byte, error) {
+	decoded := make(map[string]json.RawMessage)
+	err := json.Unmarshal(template, &decoded)
+	if err != nil {
+		return nil, err
+	}
+
+	mappings, ok := decoded[mappingsField]
+	if !ok {
+		return []byte("{}"), nil
+	}
+
+	return mappings, nil
+}
+
+// flattenProperties will add the mapping of every field, without the mappings of its sub-fields, which are added
+// separately under the path of the field
+func flattenProperties(properties map[string]interface{}, parentPath string, fields map[string]string) {
+	for name, value := range properties {
+		fieldPath := joinPath(parentPath, name)
+		field, ok := value.(map[string]interface{})
+		if !ok {
+			fields[fieldPath] = normalizeValue(value)
+			continue
+		}
+
+		attributes := make([]string, 0, len(field))
+		for attribute, attributeValue := range field {
+			if attribute == propertiesField || attribute == multiFieldsField {
+				continue
+			}
+			attributes = append(attributes, attribute+":"+normalizeValue(attributeValue))
+		}
+		sort.Strings(attributes)
+		fields[fieldPath] = "{" + strings.Join(attributes, ", ") + "}"
+
+		subProperties, _ := field[propertiesField].(map[string]interface{})
+		flattenProperties(subProperties, fieldPath, fields)
+		multiFields, _ := field[multiFieldsField].(map[string]interface{})
+		flattenProperties(multiFields, fieldPath, fields)
+	}
+}
+
+func flattenSettings(settings map[string]interface{}, parentPath string, flattened map[string]string) {
+	for name, value := range settings {
+		settingPath := joinPath(parentPath, name)
+		nested, ok := value.(map[string]interface{})
+		if ok {
+			flattenSettings(nested, settingPath, flattened)
+			continue
+		}
+
+		if !strings.HasPrefix(settingPath, indexSettingsPrefix) {
+			settingPath = indexSettingsPrefix + settingPath
+		}
+		flattened[settingPath] = normalizeValue(value)
+	}
+}
+
+// compareFlattened returns the differences between the live and the expected values. The values that exist only in
+// the live definition are reported only if requested
+func compareFlattened(kind string, live map[string]string, expected map[string]string, withRemoved bool) []string {
+	differences := make([]string, 0)
+	for _, name := range sortedKeys(expected) {
+		liveValue, found := live[name]
+		if !found {
+			liveValue = missingDefinition
+		}
+		if liveValue != expected[name] {
+			differences = append(differences, formatDifference(kind+name, liveValue, expected[name]))
+		}
+	}
+	if !withRemoved {
+		return differences
+	}
+
+	for _, name := range sortedKeys(live) {
+		if _, found := expected[name]; !found {
+			differences = append(differences, formatDifference(kind+name, live[name], missingDefinition))
+		}
+	}
+
+	return differences
+}
+
+// normalizeValue returns the same text for the values that elasticsearch considers equal, as the settings are
+// returned as strings
+func normalizeValue(value interface{}) string {
+	switch typedValue := value.(type) {
+	case nil:
+		return missingDefinition
+	case []interface{}:
+		values := make([]string, 0, len(typedValue))
+		for _, element := range typedValue {
+			values = append(values, normalizeValue(element))
+		}
+		return "[" + strings.Join(values, ",") + "]"
+	case map[string]interface{}:
+		encoded, _ := json.Marshal(typedValue)
+		return string(encoded)
+	default:
+		return fmt.Sprint(typedValue)
+	}
+}
+
+func formatDifference(name string, live string, expected string) string {
+	return fmt.Sprintf("%s: live %s, expected %s", name, live, expected)
+}
+
+func joinPath(parentPath string, name string) string {
+	if parentPath == "" {
+		return name
+	}
+
+	return parentPath + "." + name
+}
+
+func sortedKeys(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,203 @@
+package elastic
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	elasticIndexer "github.com/ME-MotherEarth/me-elastic-indexer"
+	"github.com/ME-MotherEarth/me-elastic-indexer/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const blocksTemplate = `{
+	"index_patterns": ["blocks-*"],
+	"settings": {"number_of_shards": 3, "index": {"sort.field": ["timestamp", "nonce"]}},
+	"mappings": {
+		"properties": {
+			"nonce": {"type": "double"},
+			"timestamp": {"type": "date", "format": "epoch_second"},
+			"proposer": {"type": "object", "properties": {"key": {"type": "keyword"}}}
+		}
+	}
+}`
+
+func createTemplatesArgs(templates string) ArgsElasticSink {
+	args := createMockArgsElasticSink()
+	args.IndexTemplates = map[string]*bytes.Buffer{
+		elasticIndexer.BlockIndex: bytes.NewBufferString(templates),
+	}
+
+	return args
+}
+
+func TestTemplateDifferences(t *testing.T) {
+	t.Parallel()
+
+	liveTemplate := `{
+		"order": 0,
+		"index_patterns": ["blocks-*"],
+		"settings": {"index": {"number_of_shards": "3", "sort": {"field": ["timestamp", "nonce"]}}},
+		"mappings": {
+			"properties": {
+				"nonce": {"type": "double"},
+				"timestamp": {"format": "epoch_second", "type": "date"},
+				"proposer": {"type": "object", "properties": {"key": {"type": "keyword"}}}
+			}
+		},
+		"aliases": {}
+	}`
+	differences, err := templateDifferences([]byte(liveTemplate), []byte(blocksTemplate))
+	require.Nil(t, err)
+	require.Empty(t, differences)
+
+	liveTemplate = `{
+		"index_patterns": ["blocks-*"],
+		"settings": {"index": {"number_of_shards": "5", "sort": {"field": ["timestamp", "nonce"]}}},
+		"mappings": {"properties": {"nonce": {"type": "long"}, "timestamp": {"format": "epoch_second", "type": "date"}}}
+	}`
+	differences, err = templateDifferences([]byte(liveTemplate), []byte(blocksTemplate))
+	require.Nil(t, err)
+	require.Equal(t, []string{
+		"setting index.number_of_shards: live 5, expected 3",
+		"field nonce: live {type:long}, expected {type:double}",
+		"field proposer: live none, expected {type:object}",
+		"field proposer.key: live none, expected {type:keyword}",
+	}, differences)
+
+	_, err = templateDifferences([]byte("not json"), []byte(blocksTemplate))
+	require.NotNil(t, err)
+}
+
+func TestMappingsChanges(t *testing.T) {
+	t.Parallel()
+
+	expected := `{"dynamic": false, "properties": {"fee": {"type": "keyword", "fields": {"num": {"type": "double"}}}, "nonce": {"type": "double"}}}`
+
+	compatible, conflicts, err := mappingsChanges([]byte(`{"dynamic": "false", "properties": {"nonce": {"type": "double"}}}`), []byte(expected))
+	require.Nil(t, err)
+	require.Empty(t, conflicts)
+	require.Equal(t, []string{"new field fee", "new field fee.num"}, compatible)
+
+	compatible, conflicts, err = mappingsChanges([]byte(`{"properties": {"fee": {"type": "text"}, "nonce": {"type": "double"}}}`), []byte(expected))
+	require.Nil(t, err)
+	require.Equal(t, []string{"mapping parameter dynamic: live none, expected false", "new field fee.num"}, compatible)
+	require.Equal(t, []string{"field fee: live {type:text}, expected {type:keyword}"}, conflicts)
+}
+
+func TestElasticSink_UpdateTemplates(t *testing.T) {
+	t.Parallel()
+
+	t.Run("same template should not be replaced", func(t *testing.T) {
+		t.Parallel()
+
+		args := createTemplatesArgs(blocksTemplate)
+		args.DBClient = &mock.DatabaseWriterStub{
+			GetTemplateCalled: func(templateName string) ([]byte, error) {
+				return []byte(blocksTemplate), nil
+			},
+			PutTemplateCalled: func(templateName string, template *bytes.Buffer) error {
+				require.Fail(t, "should have not been called")
+				return nil
+			},
+		}
+		_, err := NewElasticSink(args)
+		require.Nil(t, err)
+	})
+	t.Run("changed template should be replaced", func(t *testing.T) {
+		t.Parallel()
+
+		replaced := false
+		args := createTemplatesArgs(blocksTemplate)
+		args.DBClient = &mock.DatabaseWriterStub{
+			GetTemplateCalled: func(templateName string) ([]byte, error) {
+				return []byte(`{"index_patterns": ["blocks-*"], "mappings": {"properties": {"nonce": {"type": "double"}}}}`), nil
+			},
+			PutTemplateCalled: func(templateName string, template *bytes.Buffer) error {
+				replaced = true
+				require.Equal(t, elasticIndexer.BlockIndex, templateName)
+				require.Equal(t, blocksTemplate, template.String())
+				return nil
+			},
+		}
+		_, err := NewElasticSink(args)
+		require.Nil(t, err)
+		require.True(t, replaced)
+	})
+}
+
+func TestElasticSink_UpdateMappings(t *testing.T) {
+	t.Parallel()
+
+	t.Run("new fields should be added to every backing index", func(t *testing.T) {
+		t.Parallel()
+
+		updatedIndices := make([]string, 0)
+		args := createTemplatesArgs(blocksTemplate)
+		args.DBClient = &mock.DatabaseWriterStub{
+			GetMappingsCalled: func(index string) (map[string][]byte, error) {
+				require.Equal(t, elasticIndexer.BlockIndex, index)
+				return map[string][]byte{
+					"blocks-000002": []byte(`{"properties": {"nonce": {"type": "double"}}}`),
+					"blocks-000001": []byte(`{"properties": {"nonce": {"type": "double"}}}`),
+				}, nil
+			},
+			PutMappingCalled: func(index string, mapping *bytes.Buffer) error {
+				updatedIndices = append(updatedIndices, index)
+				require.Contains(t, mapping.String(), `"proposer"`)
+				return nil
+			},
+		}
+		_, err := NewElasticSink(args)
+		require.Nil(t, err)
+		require.Equal(t, []string{"blocks-000001", "blocks-000002"}, updatedIndices)
+	})
+	t.Run("changed fields should error", func(t *testing.T) {
+		t.Parallel()
+
+		args := createTemplatesArgs(blocksTemplate)
+		args.DBClient = &mock.DatabaseWriterStub{
+			GetMappingsCalled: func(index string) (map[string][]byte, error) {
+				return map[string][]byte{
+					"blocks-000001": []byte(`{"properties": {"nonce": {"type": "long"}}}`),
+				}, nil
+			},
+			PutMappingCalled: func(index string, mapping *bytes.Buffer) error {
+				require.Fail(t, "should have not been called")
+				return nil
+			},
+		}
+		es, err := NewElasticSink(args)
+		require.Nil(t, es)
+		require.True(t, errors.Is(err, elasticIndexer.ErrMappingsConflict))
+		require.Contains(t, err.Error(), "index blocks-000001: field nonce: live {type:long}, expected {type:double}")
+	})
+	t.Run("changed fields should only be logged if the conflicts are allowed", func(t *testing.T) {
+		t.Parallel()
+
+		args := createTemplatesArgs(blocksTemplate)
+		args.AllowMappingsConflicts = true
+		args.DBClient = &mock.DatabaseWriterStub{
+			GetMappingsCalled: func(index string) (map[string][]byte, error) {
+				return map[string][]byte{
+					"blocks-000001": []byte(`{"properties": {"nonce": {"type": "long"}}}`),
+				}, nil
+			},
+		}
+		_, err := NewElasticSink(args)
+		require.Nil(t, err)
+	})
+	t.Run("get mappings error should error", func(t *testing.T) {
+		t.Parallel()
+
+		localErr := errors.New("local error")
+		args := createTemplatesArgs(blocksTemplate)
+		args.DBClient = &mock.DatabaseWriterStub{
+			GetMappingsCalled: func(index string) (map[string][]byte, error) {
+				return nil, localErr
+			},
+		}
+		_, err := NewElasticSink(args)
+		require.True(t, errors.Is(err, localErr))
+	})
+}
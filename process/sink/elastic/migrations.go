@@ -0,0 +1,72 @@
+package elastic
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	elasticIndexer "github.com/ME-MotherEarth/me-elastic-indexer"
+	"github.com/ME-MotherEarth/me-elastic-indexer/data"
+)
+
+// schemaStateID is the ID of the document of the migrations index that holds the schema version of the cluster. The
+// other documents of the index hold the state of every migration and are written only by the index-modifier tool
+const schemaStateID = "schema"
+
+const matchAllQuery = `{"query":{"match_all":{}}}`
+
+// checkSchemaVersion will refuse a cluster whose schema is older than the one of the indexer, as its pending
+// migrations have to be applied first with the index-modifier tool. A cluster without indexed blocks is new, so it
+// gets the schema version of the indexer
+func (es *elasticSink) checkSchemaVersion() error {
+	documents, err := es.GetDocuments(elasticIndexer.MigrationsIndex, []string{schemaStateID})
+	if err != nil {
+		return err
+	}
+
+	source, found := documents[schemaStateID]
+	if !found {
+		return es.initSchemaVersion()
+	}
+
+	state := &data.SchemaState{}
+	err = json.Unmarshal(source, state)
+	if err != nil {
+		return fmt.Errorf("%w while decoding the schema version", err)
+	}
+
+	if state.Version < elasticIndexer.SchemaVersion {
+		return fmt.Errorf("%w: the cluster has the schema version %d, while the indexer needs the version %d",
+			elasticIndexer.ErrPendingMigrations, state.Version, elasticIndexer.SchemaVersion)
+	}
+	if state.Version > elasticIndexer.SchemaVersion {
+		log.Warn("elasticSink.checkSchemaVersion: the cluster was migrated by a newer indexer version",
+			"cluster version", state.Version, "indexer version", elasticIndexer.SchemaVersion)
+	}
+
+	return nil
+}
+
+func (es *elasticSink) initSchemaVersion() error {
+	numBlocks, err := es.elasticClient.DoCountRequest(es.prefixed(elasticIndexer.BlockIndex), []byte(matchAllQuery))
+	if err != nil {
+		return err
+	}
+	if numBlocks > 0 {
+		return fmt.Errorf("%w: the cluster has indexed blocks but no schema version, so it was indexed before the "+
+			"migrations existed. Set the version of its indices with the \"migrate baseline <version>\" command of "+
+			"the index-modifier tool, then apply the newer migrations with \"migrate up\"", elasticIndexer.ErrPendingMigrations)
+	}
+
+	log.Info("elasticSink.initSchemaVersion: setting the schema version of the new cluster", "version", elasticIndexer.SchemaVersion)
+
+	return es.WriteDocuments([]*data.Document{{
+		Index:  elasticIndexer.MigrationsIndex,
+		ID:     schemaStateID,
+		Action: data.ActionIndex,
+		Body: &data.SchemaState{
+			Version:   elasticIndexer.SchemaVersion,
+			Timestamp: time.Duration(time.Now().Unix()),
+		},
+	}})
+}
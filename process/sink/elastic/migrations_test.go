@@ -0,0 +1,90 @@
+package elastic
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+
+	elasticIndexer "github.com/ME-MotherEarth/me-elastic-indexer"
+	"github.com/ME-MotherEarth/me-elastic-indexer/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func createMigrationsArgs(t *testing.T, schemaState string, numBlocks uint64) ArgsElasticSink {
+	args := createMockArgsElasticSink()
+	args.CheckMigrations = true
+	args.DBClient = &mock.DatabaseWriterStub{
+		DoMultiGetCalled: func(ids []string, index string, withSource bool, response interface{}) error {
+			if index != elasticIndexer.MigrationsIndex || schemaState == "" {
+				return nil
+			}
+
+			resp := response.(*responseDocuments)
+			resp.Docs = []responseDocument{{ID: schemaStateID, Found: true, Source: []byte(schemaState)}}
+			return nil
+		},
+		DoCountRequestCalled: func(index string, body []byte) (uint64, error) {
+			require.Equal(t, elasticIndexer.BlockIndex, index)
+			return numBlocks, nil
+		},
+		DoBulkRequestCalled: func(buff *bytes.Buffer, index string) error {
+			require.Fail(t, "should have not been called")
+			return nil
+		},
+	}
+
+	return args
+}
+
+func TestElasticSink_CheckSchemaVersion(t *testing.T) {
+	t.Parallel()
+
+	t.Run("new cluster should get the schema version of the indexer", func(t *testing.T) {
+		t.Parallel()
+
+		written := ""
+		args := createMigrationsArgs(t, "", 0)
+		args.DBClient.(*mock.DatabaseWriterStub).DoBulkRequestCalled = func(buff *bytes.Buffer, index string) error {
+			written = buff.String()
+			return nil
+		}
+		_, err := NewElasticSink(args)
+		require.Nil(t, err)
+		require.Contains(t, written, `"_index":"migrations"`)
+		require.Contains(t, written, `"_id" : "schema"`)
+		require.Contains(t, written, fmt.Sprintf(`"version":%d`, elasticIndexer.SchemaVersion))
+	})
+	t.Run("cluster without schema version should error", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := NewElasticSink(createMigrationsArgs(t, "", 10))
+		require.True(t, errors.Is(err, elasticIndexer.ErrPendingMigrations))
+	})
+	t.Run("older schema version should error", func(t *testing.T) {
+		t.Parallel()
+
+		state := fmt.Sprintf(`{"version":%d}`, elasticIndexer.SchemaVersion-1)
+		_, err := NewElasticSink(createMigrationsArgs(t, state, 10))
+		require.True(t, errors.Is(err, elasticIndexer.ErrPendingMigrations))
+	})
+	t.Run("current or newer schema version should work", func(t *testing.T) {
+		t.Parallel()
+
+		state := fmt.Sprintf(`{"version":%d}`, elasticIndexer.SchemaVersion)
+		_, err := NewElasticSink(createMigrationsArgs(t, state, 10))
+		require.Nil(t, err)
+
+		state = fmt.Sprintf(`{"version":%d}`, elasticIndexer.SchemaVersion+1)
+		_, err = NewElasticSink(createMigrationsArgs(t, state, 10))
+		require.Nil(t, err)
+	})
+	t.Run("migrations not checked should not read the schema version", func(t *testing.T) {
+		t.Parallel()
+
+		args := createMigrationsArgs(t, "", 10)
+		args.CheckMigrations = false
+		_, err := NewElasticSink(args)
+		require.Nil(t, err)
+	})
+}
@@ -0,0 +1,67 @@
+package elastic
+
+import (
+	"bytes"
+	"encoding/json"
+
+	elasticIndexer "github.com/ME-MotherEarth/me-elastic-indexer"
+	"github.com/ME-MotherEarth/me-elastic-indexer/data"
+)
+
+const (
+	indexPatternsField = "index_patterns"
+	settingsField      = "settings"
+)
+
+// prefixed returns the name of the index, alias, template or policy in the cluster
+func (es *elasticSink) prefixed(name string) string {
+	return elasticIndexer.PrefixIndex(es.indexPrefix, name)
+}
+
+// withIndexPrefix returns the document pointed to the prefixed index. The document is copied, as it can be written
+// by other sinks too
+func (es *elasticSink) withIndexPrefix(document *data.Document) *data.Document {
+	if es.indexPrefix == "" {
+		return document
+	}
+
+	documentCopy := *document
+	documentCopy.Index = es.prefixed(document.Index)
+
+	return &documentCopy
+}
+
+// PrefixTemplate returns a copy of the index template that matches only the prefixed indices. The open distro rollover
+// alias, set in the templates used with kibana, is prefixed too
+func PrefixTemplate(template *bytes.Buffer, prefix string) (*bytes.Buffer, error) {
+	if prefix == "" {
+		return template, nil
+	}
+
+	decoded := make(map[string]interface{})
+	err := json.Unmarshal(template.Bytes(), &decoded)
+	if err != nil {
+		return nil, err
+	}
+
+	patterns, _ := decoded[indexPatternsField].([]interface{})
+	for idx, pattern := range patterns {
+		patternString, ok := pattern.(string)
+		if ok {
+			patterns[idx] = elasticIndexer.PrefixIndex(prefix, patternString)
+		}
+	}
+
+	settings, _ := decoded[settingsField].(map[string]interface{})
+	alias, ok := settings[ismRolloverAliasSetting].(string)
+	if ok {
+		settings[ismRolloverAliasSetting] = elasticIndexer.PrefixIndex(prefix, alias)
+	}
+
+	encoded, err := json.Marshal(decoded)
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes.NewBuffer(encoded), nil
+}
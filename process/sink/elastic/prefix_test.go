@@ -0,0 +1,144 @@
+package elastic
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	elasticIndexer "github.com/ME-MotherEarth/me-elastic-indexer"
+	"github.com/ME-MotherEarth/me-elastic-indexer/data"
+	"github.com/ME-MotherEarth/me-elastic-indexer/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+)
+
+func TestPrefixTemplate(t *testing.T) {
+	t.Parallel()
+
+	template := bytes.NewBufferString(`{"index_patterns":["blocks-*"],"settings":{"opendistro.index_state_management.rollover_alias":"blocks"}}`)
+
+	sameTemplate, err := PrefixTemplate(template, "")
+	require.Nil(t, err)
+	require.True(t, template == sameTemplate)
+
+	prefixedTemplate, err := PrefixTemplate(template, "testnet")
+	require.Nil(t, err)
+	require.Equal(t, `["testnet-blocks-*"]`, gjson.Get(prefixedTemplate.String(), "index_patterns").Raw)
+	require.Equal(t, "testnet-blocks", gjson.Get(prefixedTemplate.String(), `settings.opendistro\.index_state_management\.rollover_alias`).String())
+
+	_, err = PrefixTemplate(bytes.NewBufferString("not json"), "testnet")
+	require.NotNil(t, err)
+}
+
+func TestElasticSink_IndexPrefixInit(t *testing.T) {
+	t.Parallel()
+
+	mutex := sync.Mutex{}
+	names := make([]string, 0)
+	addName := func(name string) {
+		mutex.Lock()
+		names = append(names, name)
+		mutex.Unlock()
+	}
+
+	args := createMockArgsElasticSink()
+	args.IndexPrefix = "testnet"
+	args.Rollover = RolloverConditions{MaxDocs: 1000}
+	args.IndexTemplates = map[string]*bytes.Buffer{
+		elasticIndexer.OpenDistroIndex: bytes.NewBufferString(`{"index_patterns":[".opendistro-*"]}`),
+		elasticIndexer.LogsIndex:       bytes.NewBufferString(`{"index_patterns":["logs-*"]}`),
+	}
+	args.DBClient = &mock.DatabaseWriterStub{
+		CheckAndCreateTemplateCalled: func(templateName string, template *bytes.Buffer) error {
+			addName(templateName)
+			require.Equal(t, elasticIndexer.OpenDistroIndex, templateName)
+			require.Equal(t, `{"index_patterns":[".opendistro-*"]}`, template.String())
+			return nil
+		},
+		PutTemplateCalled: func(templateName string, template *bytes.Buffer) error {
+			addName(templateName)
+			require.Equal(t, `["testnet-logs-*"]`, gjson.Get(template.String(), "index_patterns").Raw)
+			require.Equal(t, "testnet-logs_policy", gjson.Get(template.String(), `settings.index\.lifecycle\.name`).String())
+			require.Equal(t, "testnet-logs", gjson.Get(template.String(), `settings.index\.lifecycle\.rollover_alias`).String())
+			return nil
+		},
+		CheckAndCreateLifecyclePolicyCalled: func(policyName string, policy *bytes.Buffer) error {
+			addName(policyName)
+			return nil
+		},
+		CheckAndCreateIndexCalled: func(index string) error {
+			addName(index)
+			return nil
+		},
+		CheckAndCreateWriteAliasCalled: func(alias string, index string) error {
+			addName(alias)
+			return nil
+		},
+		CheckAndCreateAliasCalled: func(alias string, index string) error {
+			addName(alias)
+			return nil
+		},
+	}
+	_, err := NewElasticSink(args)
+	require.Nil(t, err)
+
+	require.Contains(t, names, "testnet-logs")
+	require.Contains(t, names, "testnet-logs_policy")
+	require.Contains(t, names, "testnet-logs-000001")
+	require.Contains(t, names, "testnet-blocks")
+	for _, name := range names {
+		if name == elasticIndexer.OpenDistroIndex {
+			continue
+		}
+		require.True(t, strings.HasPrefix(name, "testnet-"), name)
+	}
+}
+
+func TestElasticSink_IndexPrefixDocuments(t *testing.T) {
+	t.Parallel()
+
+	mutex := sync.Mutex{}
+	requests := make([]string, 0)
+	observedIndices := make([]string, 0)
+	args := createMockArgsElasticSink()
+	args.IndexPrefix = "testnet"
+	args.DBClient = &mock.DatabaseWriterStub{
+		DoBulkRequestCalled: func(buff *bytes.Buffer, index string) error {
+			mutex.Lock()
+			requests = append(requests, buff.String())
+			mutex.Unlock()
+			return nil
+		},
+		DoMultiGetCalled: func(ids []string, index string, withSource bool, response interface{}) error {
+			require.Equal(t, "testnet-tokens", index)
+			return nil
+		},
+		DoQueryRemoveCalled: func(index string, body *bytes.Buffer) error {
+			require.Equal(t, "testnet-accountsmect", index)
+			return nil
+		},
+	}
+	args.MetricsHandler = &mock.MetricsHandlerStub{
+		ObserveBulkRequestCalled: func(index string, sizeInBytes int, duration time.Duration, err error) {
+			mutex.Lock()
+			observedIndices = append(observedIndices, index)
+			mutex.Unlock()
+		},
+	}
+	es, _ := NewElasticSink(args)
+
+	document := &data.Document{Index: elasticIndexer.BlockIndex, ID: "b1", Action: data.ActionIndex, Body: map[string]string{"nonce": "1"}}
+	err := es.WriteDocuments([]*data.Document{document})
+	require.Nil(t, err)
+	require.Equal(t, elasticIndexer.BlockIndex, document.Index)
+	require.Len(t, requests, 1)
+	require.Contains(t, requests[0], `"_index":"testnet-blocks"`)
+	require.Equal(t, []string{elasticIndexer.BlockIndex}, observedIndices)
+
+	_, err = es.GetDocuments(elasticIndexer.TokensIndex, []string{"t1"})
+	require.Nil(t, err)
+	err = es.DeleteMatching(elasticIndexer.AccountsMECTIndex, map[string]interface{}{"shardID": 1})
+	require.Nil(t, err)
+}
@@ -0,0 +1,273 @@
+package elastic
+
+import (
+	"bytes"
+	"encoding/json"
+
+	elasticIndexer "github.com/ME-MotherEarth/me-elastic-indexer"
+	"github.com/ME-MotherEarth/me-elastic-indexer/data"
+	"github.com/ME-MotherEarth/me-elastic-indexer/templates"
+)
+
+const (
+	lifecycleNameSetting          = "index.lifecycle.name"
+	lifecycleRolloverAliasSetting = "index.lifecycle.rollover_alias"
+	ismRolloverAliasSetting       = "opendistro.index_state_management.rollover_alias"
+	ismTemplatePriority           = 100
+)
+
+// rollingIndicesPolicies holds the indices that can be rolled over, with the name of their policy. The documents of
+// these indices are mostly appended, as the updates of a document written in an older backing index need a lookup
+var rollingIndicesPolicies = map[string]string{
+	elasticIndexer.TransactionsIndex:        elasticIndexer.TransactionsPolicy,
+	elasticIndexer.OperationsIndex:          elasticIndexer.OperationsPolicy,
+	elasticIndexer.LogsIndex:                elasticIndexer.LogsPolicy,
+	elasticIndexer.EventsIndex:              elasticIndexer.EventsPolicy,
+	elasticIndexer.TransfersIndex:           elasticIndexer.TransfersPolicy,
+	elasticIndexer.ScResultsIndex:           elasticIndexer.ScResultsPolicy,
+	elasticIndexer.AccountsHistoryIndex:     elasticIndexer.AccountsHistoryPolicy,
+	elasticIndexer.AccountsMECTHistoryIndex: elasticIndexer.AccountsMECTHistoryPolicy,
+}
+
+// RolloverConditions holds the conditions that start a new backing index for the rolling indices. A new index is
+// started as soon as any of the set conditions is met, e.g. MaxAge "30d" or MaxSize "50gb". If no condition is set,
+// every alias has a single index
+type RolloverConditions struct {
+	MaxAge  string
+	MaxSize string
+	MaxDocs uint64
+}
+
+type responseHits struct {
+	Hits struct {
+		Hits []struct {
+			Index  string          `json:"_index"`
+			ID     string          `json:"_id"`
+			Source json.RawMessage `json:"_source"`
+		} `json:"hits"`
+	} `json:"hits"`
+}
+
+// IsEnabled returns true if any rollover condition is set
+func (rc RolloverConditions) IsEnabled() bool {
+	return rc.MaxAge != "" || rc.MaxSize != "" || rc.MaxDocs != 0
+}
+
+// lifecyclePolicy returns the index lifecycle management policy, used by elasticsearch, that rolls over the index
+func (rc RolloverConditions) lifecyclePolicy() *bytes.Buffer {
+	rollover := templates.Object{}
+	if rc.MaxAge != "" {
+		rollover["max_age"] = rc.MaxAge
+	}
+	if rc.MaxSize != "" {
+		rollover["max_size"] = rc.MaxSize
+	}
+	if rc.MaxDocs != 0 {
+		rollover["max_docs"] = rc.MaxDocs
+	}
+
+	policy := templates.Object{
+		"policy": templates.Object{
+			"phases": templates.Object{
+				"hot": templates.Object{
+					"actions": templates.Object{
+						"rollover": rollover,
+					},
+				},
+			},
+		},
+	}
+
+	return policy.ToBuffer()
+}
+
+// stateManagementPolicy returns the index state management policy, used by open distro, that rolls over the index.
+// The policy is attached to the backing indices by its index pattern, so the provided index name has to hold the prefix
+func (rc RolloverConditions) stateManagementPolicy(index string) *bytes.Buffer {
+	rollover := templates.Object{}
+	if rc.MaxAge != "" {
+		rollover["min_index_age"] = rc.MaxAge
+	}
+	if rc.MaxSize != "" {
+		rollover["min_size"] = rc.MaxSize
+	}
+	if rc.MaxDocs != 0 {
+		rollover["min_doc_count"] = rc.MaxDocs
+	}
+
+	policy := templates.Object{
+		"policy": templates.Object{
+			"description":   "Open distro policy that rolls over the " + index + " elastic index.",
+			"default_state": "hot",
+			"states": templates.Array{
+				templates.Object{
+					"name": "hot",
+					"actions": templates.Array{
+						templates.Object{
+							"rollover": rollover,
+						},
+					},
+					"transitions": templates.Array{},
+				},
+			},
+			"ism_template": templates.Object{
+				"index_patterns": templates.Array{index + "-*"},
+				"priority":       ismTemplatePriority,
+			},
+		},
+	}
+
+	return policy.ToBuffer()
+}
+
+// rolloverSettings returns the settings that attach the backing indices of the alias to the policy
+func rolloverSettings(alias string, policyName string, useKibana bool) map[string]interface{} {
+	if useKibana {
+		return map[string]interface{}{
+			ismRolloverAliasSetting: alias,
+		}
+	}
+
+	return map[string]interface{}{
+		lifecycleNameSetting:          policyName,
+		lifecycleRolloverAliasSetting: alias,
+	}
+}
+
+// withSettings returns a copy of the index template that also holds the provided settings
+func withSettings(template *bytes.Buffer, settings map[string]interface{}) (*bytes.Buffer, error) {
+	decoded := make(map[string]interface{})
+	err := json.Unmarshal(template.Bytes(), &decoded)
+	if err != nil {
+		return nil, err
+	}
+
+	templateSettings, ok := decoded[settingsField].(map[string]interface{})
+	if !ok {
+		templateSettings = make(map[string]interface{})
+		decoded[settingsField] = templateSettings
+	}
+	for name, value := range settings {
+		templateSettings[name] = value
+	}
+
+	encoded, err := json.Marshal(decoded)
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes.NewBuffer(encoded), nil
+}
+
+// isRolling returns true if the index can have several backing indices
+func (es *elasticSink) isRolling(index string) bool {
+	_, isRolling := es.rollingIndices[index]
+	return isRolling
+}
+
+// searchByIDs will return the backing indices and the source of the documents with the provided ids, searched
+// through the alias, so in all the backing indices of the rolling index
+func (es *elasticSink) searchByIDs(index string, ids []string, withSource bool) (*responseHits, error) {
+	query, err := json.Marshal(objectsMap{
+		"query": objectsMap{
+			"ids": objectsMap{
+				"values": ids,
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := &responseHits{}
+	err = es.elasticClient.DoScrollRequest(index, query, withSource, func(responseBytes []byte) error {
+		page := &responseHits{}
+		errUnmarshal := json.Unmarshal(responseBytes, page)
+		if errUnmarshal != nil {
+			return errUnmarshal
+		}
+
+		response.Hits.Hits = append(response.Hits.Hits, page.Hits.Hits...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// getDocumentsFromAllIndices will return the documents found in any backing index of the rolling index. The index is
+// refreshed first, as the search requests see only the refreshed changes
+func (es *elasticSink) getDocumentsFromAllIndices(index string, ids []string) (map[string][]byte, error) {
+	err := es.elasticClient.DoRefresh(es.prefixed(index))
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := es.searchByIDs(es.prefixed(index), ids, true)
+	if err != nil {
+		return nil, err
+	}
+
+	documents := make(map[string][]byte, len(response.Hits.Hits))
+	for _, hit := range response.Hits.Hits {
+		documents[hit.ID] = hit.Source
+	}
+
+	return documents, nil
+}
+
+// findBackingIndices will search the backing indices that hold the documents written in the rolling indices, as an
+// alias sends all the changes to its write index, so a document indexed again after a rollover would be duplicated.
+// The index is not refreshed, as the documents that are not visible yet were just written, so they are in the write
+// index
+func (es *elasticSink) findBackingIndices(documents []*data.Document) (map[string]map[string][]string, error) {
+	idsPerIndex := make(map[string][]string)
+	for _, document := range documents {
+		if es.needsBackingIndex(document) {
+			idsPerIndex[document.Index] = append(idsPerIndex[document.Index], document.ID)
+		}
+	}
+
+	backingIndices := make(map[string]map[string][]string, len(idsPerIndex))
+	for index, ids := range idsPerIndex {
+		response, err := es.searchByIDs(es.prefixed(index), ids, false)
+		if err != nil {
+			return nil, err
+		}
+
+		backingIndices[index] = make(map[string][]string)
+		for _, hit := range response.Hits.Hits {
+			backingIndices[index][hit.ID] = append(backingIndices[index][hit.ID], hit.Index)
+		}
+	}
+
+	return backingIndices, nil
+}
+
+// withBackingIndices returns the document pointed to the backing indices that hold it. A document that is not found
+// stays on the alias, while a document found in several backing indices is changed in all of them
+func (es *elasticSink) withBackingIndices(document *data.Document, backingIndices map[string]map[string][]string) []*data.Document {
+	if document == nil {
+		return []*data.Document{document}
+	}
+
+	documentIndices := backingIndices[document.Index][document.ID]
+	if len(documentIndices) == 0 {
+		return []*data.Document{es.withIndexPrefix(document)}
+	}
+
+	documents := make([]*data.Document, 0, len(documentIndices))
+	for _, backingIndex := range documentIndices {
+		documentCopy := *document
+		documentCopy.Index = backingIndex
+		documents = append(documents, &documentCopy)
+	}
+
+	return documents
+}
+
+func (es *elasticSink) needsBackingIndex(document *data.Document) bool {
+	return document != nil && es.isRolling(document.Index)
+}
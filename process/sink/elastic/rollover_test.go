@@ -0,0 +1,157 @@
+package elastic
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+
+	elasticIndexer "github.com/ME-MotherEarth/me-elastic-indexer"
+	"github.com/ME-MotherEarth/me-elastic-indexer/data"
+	"github.com/ME-MotherEarth/me-elastic-indexer/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+)
+
+func TestRolloverConditions_IsEnabled(t *testing.T) {
+	t.Parallel()
+
+	require.False(t, RolloverConditions{}.IsEnabled())
+	require.True(t, RolloverConditions{MaxAge: "30d"}.IsEnabled())
+	require.True(t, RolloverConditions{MaxSize: "50gb"}.IsEnabled())
+	require.True(t, RolloverConditions{MaxDocs: 1000}.IsEnabled())
+}
+
+func TestRolloverConditions_Policies(t *testing.T) {
+	t.Parallel()
+
+	rc := RolloverConditions{MaxAge: "30d", MaxDocs: 1000}
+
+	policy := rc.lifecyclePolicy().String()
+	require.Equal(t, "30d", gjson.Get(policy, "policy.phases.hot.actions.rollover.max_age").String())
+	require.Equal(t, int64(1000), gjson.Get(policy, "policy.phases.hot.actions.rollover.max_docs").Int())
+	require.False(t, gjson.Get(policy, "policy.phases.hot.actions.rollover.max_size").Exists())
+
+	policy = rc.stateManagementPolicy(elasticIndexer.LogsIndex).String()
+	require.Equal(t, "30d", gjson.Get(policy, "policy.states.0.actions.0.rollover.min_index_age").String())
+	require.Equal(t, int64(1000), gjson.Get(policy, "policy.states.0.actions.0.rollover.min_doc_count").Int())
+	require.Equal(t, `["logs-*"]`, gjson.Get(policy, "policy.ism_template.index_patterns").Raw)
+}
+
+func TestWithSettings(t *testing.T) {
+	t.Parallel()
+
+	template := bytes.NewBufferString(`{"index_patterns":["logs-*"],"settings":{"number_of_shards":3}}`)
+	withRollover, err := withSettings(template, rolloverSettings(elasticIndexer.LogsIndex, elasticIndexer.LogsPolicy, false))
+	require.Nil(t, err)
+	require.Equal(t, int64(3), gjson.Get(withRollover.String(), "settings.number_of_shards").Int())
+	require.Equal(t, elasticIndexer.LogsPolicy, gjson.Get(withRollover.String(), `settings.index\.lifecycle\.name`).String())
+	require.Equal(t, elasticIndexer.LogsIndex, gjson.Get(withRollover.String(), `settings.index\.lifecycle\.rollover_alias`).String())
+
+	_, err = withSettings(bytes.NewBufferString("not json"), nil)
+	require.NotNil(t, err)
+}
+
+func TestElasticSink_RolloverInit(t *testing.T) {
+	t.Parallel()
+
+	policies := make(map[string]struct{})
+	writeAliases := make(map[string]string)
+	args := createMockArgsElasticSink()
+	args.Rollover = RolloverConditions{MaxSize: "50gb"}
+	args.DBClient = &mock.DatabaseWriterStub{
+		CheckAndCreateLifecyclePolicyCalled: func(policyName string, policy *bytes.Buffer) error {
+			policies[policyName] = struct{}{}
+			return nil
+		},
+		CheckAndCreateWriteAliasCalled: func(alias string, index string) error {
+			writeAliases[alias] = index
+			return nil
+		},
+		PutTemplateCalled: func(templateName string, template *bytes.Buffer) error {
+			_, isRolling := rollingIndicesPolicies[templateName]
+			require.Equal(t, isRolling, gjson.Get(template.String(), `settings.index\.lifecycle\.name`).Exists())
+			return nil
+		},
+	}
+	_, err := NewElasticSink(args)
+	require.Nil(t, err)
+
+	require.Len(t, policies, len(rollingIndicesPolicies))
+	require.Len(t, writeAliases, len(rollingIndicesPolicies))
+	require.Equal(t, elasticIndexer.TransactionsIndex+"-"+elasticIndexer.IndexSuffix, writeAliases[elasticIndexer.TransactionsIndex])
+}
+
+func TestElasticSink_GetDocumentsFromRollingIndex(t *testing.T) {
+	t.Parallel()
+
+	refreshed := false
+	args := createMockArgsElasticSink()
+	args.Rollover = RolloverConditions{MaxDocs: 1000}
+	args.DBClient = &mock.DatabaseWriterStub{
+		DoMultiGetCalled: func(ids []string, index string, withSource bool, response interface{}) error {
+			require.Fail(t, "should have not been called")
+			return nil
+		},
+		DoRefreshCalled: func(index string) error {
+			refreshed = true
+			return nil
+		},
+		DoScrollRequestCalled: func(index string, body []byte, withSource bool, handlerFunc func(responseBytes []byte) error) error {
+			require.True(t, refreshed)
+			require.Equal(t, elasticIndexer.TransactionsIndex, index)
+			require.Equal(t, `["t1","t2"]`, gjson.GetBytes(body, "query.ids.values").Raw)
+			require.True(t, withSource)
+			return handlerFunc([]byte(`{"hits":{"hits":[{"_index":"transactions-000002","_id":"t1","_source":{"status":"success"}}]}}`))
+		},
+	}
+	es, _ := NewElasticSink(args)
+
+	documents, err := es.GetDocuments(elasticIndexer.TransactionsIndex, []string{"t1", "t2"})
+	require.Nil(t, err)
+	require.Equal(t, map[string][]byte{"t1": []byte(`{"status":"success"}`)}, documents)
+}
+
+func TestElasticSink_WriteDocumentsShouldUpdateTheBackingIndices(t *testing.T) {
+	t.Parallel()
+
+	mutex := sync.Mutex{}
+	requests := make([]string, 0)
+	args := createMockArgsElasticSink()
+	args.Rollover = RolloverConditions{MaxAge: "7d"}
+	args.DBClient = &mock.DatabaseWriterStub{
+		DoScrollRequestCalled: func(index string, body []byte, withSource bool, handlerFunc func(responseBytes []byte) error) error {
+			require.Equal(t, elasticIndexer.TransactionsIndex, index)
+			require.Equal(t, `["t0","t1","t2","t3"]`, gjson.GetBytes(body, "query.ids.values").Raw)
+			require.False(t, withSource)
+			return handlerFunc([]byte(`{"hits":{"hits":[
+				{"_index":"transactions-000001","_id":"t0"},
+				{"_index":"transactions-000001","_id":"t1"},
+				{"_index":"transactions-000002","_id":"t1"}
+			]}}`))
+		},
+		DoBulkRequestCalled: func(buff *bytes.Buffer, index string) error {
+			mutex.Lock()
+			requests = append(requests, buff.String())
+			mutex.Unlock()
+			return nil
+		},
+	}
+	es, _ := NewElasticSink(args)
+
+	err := es.WriteDocuments([]*data.Document{
+		{Index: elasticIndexer.TransactionsIndex, ID: "t0", Action: data.ActionIndex, Body: map[string]string{"status": "pending"}},
+		{Index: elasticIndexer.TransactionsIndex, ID: "t1", Action: data.ActionDelete},
+		{Index: elasticIndexer.TransactionsIndex, ID: "t2", Action: data.ActionDelete},
+		{Index: elasticIndexer.TransactionsIndex, ID: "t3", Action: data.ActionIndex, Body: map[string]string{"status": "pending"}},
+	})
+	require.Nil(t, err)
+
+	request := strings.Join(requests, "")
+	require.Contains(t, request, `"_index":"transactions-000001", "_id" : "t0"`)
+	require.NotContains(t, request, `"_index":"transactions", "_id" : "t0"`)
+	require.Contains(t, request, `"_index":"transactions", "_id" : "t3"`)
+	require.Contains(t, request, `"_index":"transactions-000001", "_id" : "t1"`)
+	require.Contains(t, request, `"_index":"transactions-000002", "_id" : "t1"`)
+	require.Contains(t, request, `"_index":"transactions", "_id" : "t2"`)
+}
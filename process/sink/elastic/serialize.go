@@ -0,0 +1,277 @@
+package elastic
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/ME-MotherEarth/me-elastic-indexer/converters"
+	"github.com/ME-MotherEarth/me-elastic-indexer/data"
+	"github.com/ME-MotherEarth/me-elastic-indexer/process/sink"
+)
+
+type objectsMap = map[string]interface{}
+
+var actionNames = map[data.DocumentAction]string{
+	data.ActionIndex:  "index",
+	data.ActionCreate: "create",
+	data.ActionUpsert: "upsert",
+	data.ActionUpdate: "update",
+	data.ActionDelete: "delete",
+}
+
+// documentScript applies a backend-neutral document on the stored one. It is the same for all the documents, so
+// elasticsearch server compiles it only once, while the changes are provided as params
+const documentScript = `
+	Map parentOf(Map source, String path, boolean create) {
+		String[] keys = path.splitOnToken('.');
+		Map obj = source;
+		for (int i = 0; i < keys.length - 1; i++) {
+			if (!(obj.get(keys[i]) instanceof Map)) {
+				if (!create) {
+					return null;
+				}
+				obj.put(keys[i], new HashMap());
+			}
+			obj = obj.get(keys[i]);
+		}
+		return obj;
+	}
+	String lastKey(String path) {
+		String[] keys = path.splitOnToken('.');
+		return keys[keys.length - 1];
+	}
+	if ('create' == ctx.op) {
+		if (params.containsKey('body')) {
+			ctx._source = params.body;
+		} else {
+			ctx.op = 'noop';
+		}
+		return;
+	}
+	if ('create' == params.action) {
+		ctx.op = 'noop';
+		return;
+	}
+	if (params.containsKey('timestamp') && ctx._source.containsKey('timestamp') && ctx._source.timestamp > params.timestamp) {
+		ctx.op = 'noop';
+		return;
+	}
+	if (params.containsKey('nonce') && ctx._source.containsKey('nonce') && ctx._source.nonce > params.nonce) {
+		ctx.op = 'noop';
+		return;
+	}
+	if ('delete' == params.action) {
+		ctx.op = 'delete';
+		return;
+	}
+	if ('index' == params.action) {
+		Map source = new HashMap(params.body);
+		if (params.containsKey('keep')) {
+			for (def field : params.keep) {
+				if (ctx._source.containsKey(field)) {
+					source.put(field, ctx._source.get(field));
+				}
+			}
+		}
+		ctx._source = source;
+		return;
+	}
+	if (params.containsKey('fields')) {
+		for (def entry : params.fields.entrySet()) {
+			Map parent = parentOf(ctx._source, entry.getKey(), entry.getValue() != null);
+			if (parent == null) {
+				continue;
+			}
+			if (entry.getValue() == null) {
+				parent.remove(lastKey(entry.getKey()));
+			} else {
+				parent.put(lastKey(entry.getKey()), entry.getValue());
+			}
+		}
+	}
+	if (params.containsKey('increments')) {
+		for (def entry : params.increments.entrySet()) {
+			Map parent = parentOf(ctx._source, entry.getKey(), true);
+			String key = lastKey(entry.getKey());
+			if (parent.get(key) == null) {
+				parent.put(key, entry.getValue());
+			} else {
+				parent.put(key, parent.get(key) + entry.getValue());
+			}
+		}
+	}
+	if (params.containsKey('append')) {
+		for (def entry : params.append.entrySet()) {
+			Map parent = parentOf(ctx._source, entry.getKey(), true);
+			String key = lastKey(entry.getKey());
+			if (!(parent.get(key) instanceof List)) {
+				parent.put(key, new ArrayList());
+			}
+			parent.get(key).addAll(entry.getValue());
+		}
+	}
+	if (params.containsKey('addToSet')) {
+		for (def entry : params.addToSet.entrySet()) {
+			Map parent = parentOf(ctx._source, entry.getKey(), true);
+			String key = lastKey(entry.getKey());
+			if (!(parent.get(key) instanceof List)) {
+				parent.put(key, new ArrayList());
+			}
+			for (def value : entry.getValue()) {
+				if (!parent.get(key).contains(value)) {
+					parent.get(key).add(value);
+				}
+			}
+		}
+	}
+	if (params.containsKey('removeFromSet')) {
+		for (def entry : params.removeFromSet.entrySet()) {
+			Map parent = parentOf(ctx._source, entry.getKey(), false);
+			if (parent != null && parent.get(lastKey(entry.getKey())) instanceof List) {
+				parent.get(lastKey(entry.getKey())).removeAll(entry.getValue());
+			}
+		}
+	}
+	if (params.containsKey('cleanup')) {
+		for (def path : params.cleanup) {
+			String[] keys = path.splitOnToken('.');
+			for (int depth = keys.length; depth > 0; depth--) {
+				def obj = ctx._source;
+				for (int i = 0; i < depth - 1 && obj instanceof Map; i++) {
+					obj = obj.get(keys[i]);
+				}
+				if (!(obj instanceof Map)) {
+					continue;
+				}
+				def child = obj.get(keys[depth - 1]);
+				if ((child instanceof Map || child instanceof List) && child.isEmpty()) {
+					obj.remove(keys[depth - 1]);
+				}
+			}
+		}
+		if (ctx._source.isEmpty()) {
+			ctx.op = 'delete';
+		}
+	}
+`
+
+var formattedDocumentScript = converters.FormatPainlessSource(documentScript)
+
+func serializeDocument(document *data.Document) ([]byte, []byte, error) {
+	if document == nil {
+		return nil, nil, sink.ErrNilDocument
+	}
+
+	actionName, ok := actionNames[document.Action]
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: %d, index: %s, id: %s", sink.ErrUnknownDocumentAction, document.Action, document.Index, document.ID)
+	}
+
+	isConditional := document.Timestamp != 0 || document.Nonce != 0
+	isPlainIndex := document.Action == data.ActionIndex && !isConditional && len(document.KeepFields) == 0
+	if isPlainIndex {
+		serializedData, err := json.Marshal(document.Body)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return prepareMeta("index", document), serializedData, nil
+	}
+
+	isPlainDelete := document.Action == data.ActionDelete && !isConditional
+	if isPlainDelete {
+		return prepareMeta("delete", document), nil, nil
+	}
+
+	serializedData, err := json.Marshal(objectsMap{
+		"scripted_upsert": true,
+		"script": objectsMap{
+			"source": formattedDocumentScript,
+			"lang":   "painless",
+			"params": prepareScriptParams(actionName, document),
+		},
+		"upsert": objectsMap{},
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return prepareMeta("update", document), serializedData, nil
+}
+
+func prepareMeta(operation string, document *data.Document) []byte {
+	return []byte(fmt.Sprintf(`{ "%s" : { "_index":"%s", "_id" : "%s" } }%s`, operation, document.Index, converters.JsonEscape(document.ID), "\n"))
+}
+
+func prepareScriptParams(actionName string, document *data.Document) objectsMap {
+	params := objectsMap{
+		"action": actionName,
+	}
+
+	canCreate := document.Action == data.ActionIndex || document.Action == data.ActionCreate || document.Action == data.ActionUpsert
+	if canCreate && document.Body != nil {
+		params["body"] = document.Body
+	}
+	if document.Timestamp != 0 {
+		params["timestamp"] = document.Timestamp
+	}
+	if document.Nonce != 0 {
+		params["nonce"] = document.Nonce
+	}
+	if len(document.KeepFields) != 0 {
+		params["keep"] = document.KeepFields
+	}
+	if len(document.Fields) != 0 {
+		params["fields"] = document.Fields
+	}
+	if len(document.Increments) != 0 {
+		params["increments"] = document.Increments
+	}
+	if len(document.Append) != 0 {
+		params["append"] = document.Append
+	}
+	if len(document.AddToSet) != 0 {
+		params["addToSet"] = document.AddToSet
+	}
+	if len(document.RemoveFromSet) != 0 {
+		params["removeFromSet"] = document.RemoveFromSet
+	}
+	if document.DeleteIfEmpty {
+		params["cleanup"] = document.ChangedPaths()
+	}
+
+	return params
+}
+
+func prepareMatchingQuery(fields map[string]interface{}) ([]byte, error) {
+	sortedFields := make([]string, 0, len(fields))
+	for field := range fields {
+		sortedFields = append(sortedFields, field)
+	}
+	sort.Strings(sortedFields)
+
+	must := make([]interface{}, 0, len(fields))
+	mustNot := make([]interface{}, 0)
+	for _, field := range sortedFields {
+		value := fields[field]
+		if value == nil {
+			mustNot = append(mustNot, objectsMap{"exists": objectsMap{"field": field}})
+			continue
+		}
+		upperBound, isUpperBound := value.(data.AtMost)
+		if isUpperBound {
+			must = append(must, objectsMap{"range": objectsMap{field: objectsMap{"lte": upperBound.Value}}})
+			continue
+		}
+
+		must = append(must, objectsMap{"match": objectsMap{field: objectsMap{"query": value, "operator": "AND"}}})
+	}
+
+	boolQuery := objectsMap{"must": must}
+	if len(mustNot) != 0 {
+		boolQuery["must_not"] = mustNot
+	}
+
+	return json.Marshal(objectsMap{"query": objectsMap{"bool": boolQuery}})
+}
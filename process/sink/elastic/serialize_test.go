@@ -0,0 +1,116 @@
+package elastic
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/ME-MotherEarth/me-elastic-indexer/data"
+	"github.com/ME-MotherEarth/me-elastic-indexer/process/sink"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSerializeDocument_NilDocumentShouldErr(t *testing.T) {
+	t.Parallel()
+
+	_, _, err := serializeDocument(nil)
+	require.Equal(t, sink.ErrNilDocument, err)
+}
+
+func TestSerializeDocument_UnknownActionShouldErr(t *testing.T) {
+	t.Parallel()
+
+	_, _, err := serializeDocument(&data.Document{Index: "blocks", ID: "h1", Action: data.DocumentAction(100)})
+	require.True(t, errors.Is(err, sink.ErrUnknownDocumentAction))
+}
+
+func TestSerializeDocument_PlainIndex(t *testing.T) {
+	t.Parallel()
+
+	meta, serializedData, err := serializeDocument(&data.Document{
+		Index:  "blocks",
+		ID:     "h1",
+		Action: data.ActionIndex,
+		Body:   map[string]uint64{"nonce": 1},
+	})
+	require.Nil(t, err)
+	require.Equal(t, `{ "index" : { "_index":"blocks", "_id" : "h1" } }`+"\n", string(meta))
+	require.Equal(t, `{"nonce":1}`, string(serializedData))
+}
+
+func TestSerializeDocument_PlainDelete(t *testing.T) {
+	t.Parallel()
+
+	meta, serializedData, err := serializeDocument(&data.Document{Index: "blocks", ID: "h1", Action: data.ActionDelete})
+	require.Nil(t, err)
+	require.Equal(t, `{ "delete" : { "_index":"blocks", "_id" : "h1" } }`+"\n", string(meta))
+	require.Nil(t, serializedData)
+}
+
+func TestSerializeDocument_ScriptedUpdate(t *testing.T) {
+	t.Parallel()
+
+	meta, serializedData, err := serializeDocument(&data.Document{
+		Index:         "collections",
+		ID:            "addr",
+		Action:        data.ActionUpdate,
+		Fields:        map[string]interface{}{"token.nonceHex": nil},
+		Increments:    map[string]int64{"count": 1},
+		RemoveFromSet: map[string][]interface{}{"roles.MECTRoleNFTBurn": {"moa1"}},
+		Timestamp:     100,
+		Nonce:         7,
+		DeleteIfEmpty: true,
+	})
+	require.Nil(t, err)
+	require.Equal(t, `{ "update" : { "_index":"collections", "_id" : "addr" } }`+"\n", string(meta))
+
+	request := make(map[string]interface{})
+	err = json.Unmarshal(serializedData, &request)
+	require.Nil(t, err)
+	require.Equal(t, true, request["scripted_upsert"])
+
+	script := request["script"].(map[string]interface{})
+	require.Equal(t, formattedDocumentScript, script["source"])
+
+	expectedParams := map[string]interface{}{
+		"action":        "update",
+		"timestamp":     float64(100),
+		"nonce":         float64(7),
+		"fields":        map[string]interface{}{"token.nonceHex": nil},
+		"increments":    map[string]interface{}{"count": float64(1)},
+		"removeFromSet": map[string]interface{}{"roles.MECTRoleNFTBurn": []interface{}{"moa1"}},
+		"cleanup":       []interface{}{"roles.MECTRoleNFTBurn", "token.nonceHex"},
+	}
+	require.Equal(t, expectedParams, script["params"])
+}
+
+func TestSerializeDocument_UpdateShouldNotSendBody(t *testing.T) {
+	t.Parallel()
+
+	_, serializedData, err := serializeDocument(&data.Document{
+		Index:  "tokens",
+		ID:     "TKN-01234",
+		Action: data.ActionUpdate,
+		Body:   map[string]string{"name": "token"},
+		Fields: map[string]interface{}{"name": "token"},
+	})
+	require.Nil(t, err)
+
+	request := make(map[string]interface{})
+	_ = json.Unmarshal(serializedData, &request)
+	params := request["script"].(map[string]interface{})["params"].(map[string]interface{})
+	_, hasBody := params["body"]
+	require.False(t, hasBody)
+}
+
+func TestPrepareMatchingQuery(t *testing.T) {
+	t.Parallel()
+
+	query, err := prepareMatchingQuery(map[string]interface{}{"type": nil, "token": "NFT-abcd"})
+	require.Nil(t, err)
+	require.Equal(t, `{"query":{"bool":{"must":[{"match":{"token":{"operator":"AND","query":"NFT-abcd"}}}],"must_not":[{"exists":{"field":"type"}}]}}}`, string(query))
+
+	query, err = prepareMatchingQuery(map[string]interface{}{"shardId": uint32(1), "nonce": data.AtMost{Value: 7}})
+	require.Nil(t, err)
+	require.Equal(t, `{"query":{"bool":{"must":[{"range":{"nonce":{"lte":7}}},{"match":{"shardId":{"operator":"AND","query":1}}}]}}}`, string(query))
+}
@@ -0,0 +1,12 @@
+package sink
+
+import "errors"
+
+// ErrNilDocumentsSink signals that a nil documents sink has been provided
+var ErrNilDocumentsSink = errors.New("nil documents sink")
+
+// ErrNilDocument signals that a nil document has been provided
+var ErrNilDocument = errors.New("nil document")
+
+// ErrUnknownDocumentAction signals that a document with an unknown action has been provided
+var ErrUnknownDocumentAction = errors.New("unknown document action")
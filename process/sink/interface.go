@@ -0,0 +1,13 @@
+package sink
+
+import "github.com/ME-MotherEarth/me-elastic-indexer/data"
+
+// DocumentsSink defines a storage backend that receives the backend-neutral documents produced from the data
+// prepared by the process components
+type DocumentsSink interface {
+	WriteDocuments(documents []*data.Document) error
+	GetDocuments(index string, ids []string) (map[string][]byte, error)
+	IterateMatchingIDs(index string, fields map[string]interface{}, handlerFunc func(ids []string) error) error
+	DeleteMatching(index string, fields map[string]interface{}) error
+	IsInterfaceNil() bool
+}
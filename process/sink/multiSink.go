@@ -0,0 +1,110 @@
+package sink
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ME-MotherEarth/me-core/core/check"
+	"github.com/ME-MotherEarth/me-elastic-indexer/data"
+	logger "github.com/ME-MotherEarth/me-logger"
+)
+
+var log = logger.GetOrCreate("indexer/process/sink")
+
+const (
+	maxSecondaryAttempts            = 3
+	durationBetweenSecondaryRetries = time.Millisecond * 200
+)
+
+type multiSink struct {
+	primary     DocumentsSink
+	secondaries []DocumentsSink
+}
+
+// NewMultiSink will create a documents sink that writes the documents in all the provided sinks, in the provided
+// order. The stored documents are read only from the first sink. Only an error of the primary sink is returned: a
+// failed write is retried by the caller for all the sinks, so a secondary sink that fails is retried here on its own
+// and, if it keeps failing, its error is logged and the documents are missing from it
+func NewMultiSink(primary DocumentsSink, secondaries ...DocumentsSink) (*multiSink, error) {
+	if check.IfNil(primary) {
+		return nil, ErrNilDocumentsSink
+	}
+	for _, secondary := range secondaries {
+		if check.IfNil(secondary) {
+			return nil, ErrNilDocumentsSink
+		}
+	}
+
+	return &multiSink{
+		primary:     primary,
+		secondaries: secondaries,
+	}, nil
+}
+
+// WriteDocuments will write the provided documents in all the sinks
+func (ms *multiSink) WriteDocuments(documents []*data.Document) error {
+	return ms.forAll(func(documentsSink DocumentsSink) error {
+		return documentsSink.WriteDocuments(documents)
+	})
+}
+
+// GetDocuments will return the documents with the provided ids from the primary sink
+func (ms *multiSink) GetDocuments(index string, ids []string) (map[string][]byte, error) {
+	return ms.primary.GetDocuments(index, ids)
+}
+
+// IterateMatchingIDs will iterate over the ids of the matching documents from the primary sink
+func (ms *multiSink) IterateMatchingIDs(index string, fields map[string]interface{}, handlerFunc func(ids []string) error) error {
+	return ms.primary.IterateMatchingIDs(index, fields, handlerFunc)
+}
+
+// DeleteMatching will remove the matching documents from all the sinks
+func (ms *multiSink) DeleteMatching(index string, fields map[string]interface{}) error {
+	return ms.forAll(func(documentsSink DocumentsSink) error {
+		return documentsSink.DeleteMatching(index, fields)
+	})
+}
+
+// forAll will write in the secondary sinks only after the primary sink succeeded. The documents rejected by the
+// primary sink are returned after the secondary sinks were written, as all the other documents were saved
+func (ms *multiSink) forAll(handler func(documentsSink DocumentsSink) error) error {
+	rejected := &data.RejectedDocumentsError{}
+	err := rejected.Add(handler(ms.primary))
+	if err != nil {
+		return err
+	}
+
+	for idx, secondary := range ms.secondaries {
+		err = retrySecondary(secondary, handler)
+		if err != nil {
+			log.Error("multiSink: the secondary sink failed, the documents will be missing from it",
+				"sink", fmt.Sprintf("%d (%T)", idx, secondary),
+				"attempts", maxSecondaryAttempts,
+				"error", err.Error())
+		}
+	}
+
+	return rejected.AsError()
+}
+
+func retrySecondary(secondary DocumentsSink, handler func(documentsSink DocumentsSink) error) error {
+	var err error
+	for attempt := 1; attempt <= maxSecondaryAttempts; attempt++ {
+		err = handler(secondary)
+		if err == nil {
+			return nil
+		}
+
+		log.Warn("multiSink: the secondary sink failed", "attempt", attempt, "error", err.Error())
+		if attempt < maxSecondaryAttempts {
+			time.Sleep(durationBetweenSecondaryRetries)
+		}
+	}
+
+	return err
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (ms *multiSink) IsInterfaceNil() bool {
+	return ms == nil
+}
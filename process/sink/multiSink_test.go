@@ -0,0 +1,156 @@
+package sink_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ME-MotherEarth/me-elastic-indexer/data"
+	"github.com/ME-MotherEarth/me-elastic-indexer/mock"
+	"github.com/ME-MotherEarth/me-elastic-indexer/process/sink"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMultiSink(t *testing.T) {
+	t.Parallel()
+
+	ms, err := sink.NewMultiSink(nil)
+	require.Nil(t, ms)
+	require.Equal(t, sink.ErrNilDocumentsSink, err)
+
+	ms, err = sink.NewMultiSink(&mock.DocumentsSinkStub{}, nil)
+	require.Nil(t, ms)
+	require.Equal(t, sink.ErrNilDocumentsSink, err)
+
+	ms, err = sink.NewMultiSink(&mock.DocumentsSinkStub{}, &mock.DocumentsSinkStub{})
+	require.Nil(t, err)
+	require.False(t, ms.IsInterfaceNil())
+}
+
+func TestMultiSink_WriteDocumentsShouldWriteInAllSinks(t *testing.T) {
+	t.Parallel()
+
+	calls := make([]string, 0)
+	primary := &mock.DocumentsSinkStub{
+		WriteDocumentsCalled: func(documents []*data.Document) error {
+			calls = append(calls, "primary")
+			return nil
+		},
+	}
+	secondary := &mock.DocumentsSinkStub{
+		WriteDocumentsCalled: func(documents []*data.Document) error {
+			calls = append(calls, "secondary")
+			return nil
+		},
+		GetDocumentsCalled: func(index string, ids []string) (map[string][]byte, error) {
+			require.Fail(t, "should have not been called")
+			return nil, nil
+		},
+	}
+	ms, _ := sink.NewMultiSink(primary, secondary)
+
+	err := ms.WriteDocuments([]*data.Document{{Index: "blocks", ID: "b1", Action: data.ActionDelete}})
+	require.Nil(t, err)
+	require.Equal(t, []string{"primary", "secondary"}, calls)
+
+	_, err = ms.GetDocuments("blocks", []string{"b1"})
+	require.Nil(t, err)
+}
+
+func TestMultiSink_DeleteMatchingShouldStopOnError(t *testing.T) {
+	t.Parallel()
+
+	localErr := errors.New("local error")
+	primary := &mock.DocumentsSinkStub{
+		DeleteMatchingCalled: func(index string, fields map[string]interface{}) error {
+			return localErr
+		},
+	}
+	secondary := &mock.DocumentsSinkStub{
+		DeleteMatchingCalled: func(index string, fields map[string]interface{}) error {
+			require.Fail(t, "should have not been called")
+			return nil
+		},
+	}
+	ms, _ := sink.NewMultiSink(primary, secondary)
+
+	err := ms.DeleteMatching("accountsmect", map[string]interface{}{"shardID": 1})
+	require.Equal(t, localErr, err)
+}
+
+func TestMultiSink_SecondaryErrorShouldBeRetriedOnlyForTheSecondary(t *testing.T) {
+	t.Parallel()
+
+	numPrimaryWrites := 0
+	numSecondaryWrites := 0
+	primary := &mock.DocumentsSinkStub{
+		WriteDocumentsCalled: func(documents []*data.Document) error {
+			numPrimaryWrites++
+			return nil
+		},
+	}
+	secondary := &mock.DocumentsSinkStub{
+		WriteDocumentsCalled: func(documents []*data.Document) error {
+			numSecondaryWrites++
+			if numSecondaryWrites == 1 {
+				return errors.New("local error")
+			}
+			return nil
+		},
+	}
+	ms, _ := sink.NewMultiSink(primary, secondary)
+
+	err := ms.WriteDocuments([]*data.Document{{Index: "tags", ID: "t1", Action: data.ActionUpdate}})
+	require.Nil(t, err)
+	require.Equal(t, 1, numPrimaryWrites)
+	require.Equal(t, 2, numSecondaryWrites)
+}
+
+func TestMultiSink_SecondaryThatKeepsFailingShouldNotFailTheWrite(t *testing.T) {
+	t.Parallel()
+
+	numPrimaryDeletes := 0
+	numSecondaryDeletes := 0
+	primary := &mock.DocumentsSinkStub{
+		DeleteMatchingCalled: func(index string, fields map[string]interface{}) error {
+			numPrimaryDeletes++
+			return nil
+		},
+	}
+	secondary := &mock.DocumentsSinkStub{
+		DeleteMatchingCalled: func(index string, fields map[string]interface{}) error {
+			numSecondaryDeletes++
+			return errors.New("local error")
+		},
+	}
+	ms, _ := sink.NewMultiSink(primary, secondary)
+
+	err := ms.DeleteMatching("accountsmect", map[string]interface{}{"shardID": 1})
+	require.Nil(t, err)
+	require.Equal(t, 1, numPrimaryDeletes)
+	require.Equal(t, 3, numSecondaryDeletes)
+}
+
+func TestMultiSink_RejectedDocumentsOfThePrimaryShouldNotStopTheSecondaries(t *testing.T) {
+	t.Parallel()
+
+	numSecondaryWrites := 0
+	rejectedErr := &data.RejectedDocumentsError{Documents: []*data.RejectedDocument{{Index: "tags", ID: "t1"}}}
+	primary := &mock.DocumentsSinkStub{
+		WriteDocumentsCalled: func(documents []*data.Document) error {
+			return rejectedErr
+		},
+	}
+	secondary := &mock.DocumentsSinkStub{
+		WriteDocumentsCalled: func(documents []*data.Document) error {
+			numSecondaryWrites++
+			return nil
+		},
+	}
+	ms, _ := sink.NewMultiSink(primary, secondary)
+
+	err := ms.WriteDocuments([]*data.Document{{Index: "tags", ID: "t1", Action: data.ActionUpdate}})
+	rejected := &data.RejectedDocumentsError{}
+	require.True(t, errors.As(err, &rejected))
+	require.Equal(t, rejectedErr.Documents, rejected.Documents)
+	require.Equal(t, 1, numSecondaryWrites)
+}
@@ -0,0 +1,14 @@
+package postgres
+
+// SQLHandler defines the statements that can be executed on the database, inside or outside a transaction
+type SQLHandler interface {
+	Exec(query string, args ...interface{}) error
+	Query(query string, args []interface{}, rowHandler func(scan func(dest ...interface{}) error) error) error
+}
+
+// DatabaseClientHandler defines the actions that a component that handles sql requests should do
+type DatabaseClientHandler interface {
+	SQLHandler
+	DoInTransaction(handlerFunc func(tx SQLHandler) error) error
+	IsInterfaceNil() bool
+}
@@ -0,0 +1,282 @@
+package postgres
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/ME-MotherEarth/me-core/core/check"
+	elasticIndexer "github.com/ME-MotherEarth/me-elastic-indexer"
+	"github.com/ME-MotherEarth/me-elastic-indexer/data"
+	"github.com/ME-MotherEarth/me-elastic-indexer/process/sink"
+	"github.com/ME-MotherEarth/me-elastic-indexer/process/sink/docstate"
+	logger "github.com/ME-MotherEarth/me-logger"
+)
+
+var log = logger.GetOrCreate("indexer/process/sink/postgres")
+
+const maxRowsPerStatement = 500
+
+// ArgsPostgresSink holds all dependencies required by the postgresSink in order to create new instances
+type ArgsPostgresSink struct {
+	DBClient     DatabaseClientHandler
+	CustomTables []string
+}
+
+type postgresSink struct {
+	dbClient DatabaseClientHandler
+	tables   []string
+}
+
+// NewPostgresSink will create the tables that do not exist yet and will return a documents sink that writes in a
+// PostgreSQL database. Every index is stored in a table with the id and the json document, while the fields used for
+// joins are exposed as generated columns. The custom tables hold the documents of the indices declared by the event
+// processors added by integrators
+func NewPostgresSink(args ArgsPostgresSink) (*postgresSink, error) {
+	if check.IfNil(args.DBClient) {
+		return nil, elasticIndexer.ErrNilDatabaseClient
+	}
+
+	allTables := make([]string, 0, len(tables)+len(args.CustomTables))
+	allTables = append(allTables, tables...)
+	allTables = append(allTables, args.CustomTables...)
+
+	ps := &postgresSink{
+		dbClient: args.DBClient,
+		tables:   allTables,
+	}
+
+	err := ps.createTables()
+	if err != nil {
+		return nil, err
+	}
+
+	return ps, nil
+}
+
+// WriteDocuments will apply the provided documents in a single database transaction
+func (ps *postgresSink) WriteDocuments(documents []*data.Document) error {
+	if len(documents) == 0 {
+		return nil
+	}
+
+	documentsPerTable := make(map[string][]*data.Document)
+	for _, document := range documents {
+		err := checkDocument(document)
+		if err != nil {
+			return err
+		}
+
+		documentsPerTable[document.Index] = append(documentsPerTable[document.Index], document)
+	}
+
+	// the tables are always handled in the same order, so concurrent writers can not lock each other
+	tablesNames := make([]string, 0, len(documentsPerTable))
+	for table := range documentsPerTable {
+		tablesNames = append(tablesNames, table)
+	}
+	sort.Strings(tablesNames)
+
+	return ps.dbClient.DoInTransaction(func(tx SQLHandler) error {
+		for _, table := range tablesNames {
+			err := writeTableDocuments(tx, table, documentsPerTable[table])
+			if err != nil {
+				return fmt.Errorf("table: %s, error: %w", table, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+func checkDocument(document *data.Document) error {
+	if document == nil {
+		return sink.ErrNilDocument
+	}
+
+	isKnownAction := document.Action >= data.ActionIndex && document.Action <= data.ActionDelete
+	if !isKnownAction {
+		return fmt.Errorf("%w: %d, index: %s, id: %s", sink.ErrUnknownDocumentAction, document.Action, document.Index, document.ID)
+	}
+
+	return nil
+}
+
+func writeTableDocuments(tx SQLHandler, table string, documents []*data.Document) error {
+	idsToRead := make([]string, 0)
+	firstDocuments := make(map[string]struct{})
+	for _, document := range documents {
+		_, found := firstDocuments[document.ID]
+		if found {
+			continue
+		}
+
+		firstDocuments[document.ID] = struct{}{}
+		if !docstate.IsPlainIndex(document) && !docstate.IsPlainDelete(document) {
+			idsToRead = append(idsToRead, document.ID)
+		}
+	}
+
+	storedDocuments := make(map[string][]byte)
+	if len(idsToRead) > 0 {
+		// the documents that are changed based on their stored content are read and written while no other writer
+		// can change the table, the same way elasticsearch applies the update scripts
+		err := tx.Exec(fmt.Sprintf("LOCK TABLE %s IN SHARE ROW EXCLUSIVE MODE", quoteIdentifier(table)))
+		if err != nil {
+			return err
+		}
+
+		storedDocuments, err = getDocuments(tx, table, idsToRead)
+		if err != nil {
+			return err
+		}
+	}
+
+	ids := make([]string, 0, len(firstDocuments))
+	states := make(map[string]*docstate.State, len(firstDocuments))
+	for _, document := range documents {
+		state, found := states[document.ID]
+		if !found {
+			state = docstate.NewState(storedDocuments[document.ID])
+			states[document.ID] = state
+			ids = append(ids, document.ID)
+		}
+
+		err := state.Apply(document)
+		if err != nil {
+			return fmt.Errorf("id: %s, error: %w", document.ID, err)
+		}
+	}
+
+	return saveStates(tx, table, ids, states)
+}
+
+func saveStates(tx SQLHandler, table string, ids []string, states map[string]*docstate.State) error {
+	rowsToUpsert := make([]interface{}, 0)
+	idsToDelete := make([]interface{}, 0)
+	for _, id := range ids {
+		state := states[id]
+		if !state.Changed() {
+			continue
+		}
+		if !state.Exists() {
+			idsToDelete = append(idsToDelete, id)
+			continue
+		}
+
+		serializedDoc, err := state.Serialize()
+		if err != nil {
+			return err
+		}
+
+		rowsToUpsert = append(rowsToUpsert, id, string(serializedDoc))
+	}
+
+	for start := 0; start < len(idsToDelete); start += maxRowsPerStatement {
+		batch := idsToDelete[start:minInt(start+maxRowsPerStatement, len(idsToDelete))]
+		err := tx.Exec(fmt.Sprintf("DELETE FROM %s WHERE id IN (%s)", quoteIdentifier(table), preparePlaceholders(1, len(batch), 1)), batch...)
+		if err != nil {
+			return err
+		}
+	}
+
+	for start := 0; start < len(rowsToUpsert); start += 2 * maxRowsPerStatement {
+		batch := rowsToUpsert[start:minInt(start+2*maxRowsPerStatement, len(rowsToUpsert))]
+		query := fmt.Sprintf("INSERT INTO %s (id, doc) VALUES %s ON CONFLICT (id) DO UPDATE SET doc = EXCLUDED.doc",
+			quoteIdentifier(table), preparePlaceholders(1, len(batch)/2, 2))
+		err := tx.Exec(query, batch...)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetDocuments will return the content of the documents with the provided ids that exist in the provided table
+func (ps *postgresSink) GetDocuments(index string, ids []string) (map[string][]byte, error) {
+	return getDocuments(ps.dbClient, index, ids)
+}
+
+func getDocuments(handler SQLHandler, table string, ids []string) (map[string][]byte, error) {
+	documents := make(map[string][]byte)
+	for start := 0; start < len(ids); start += maxRowsPerStatement {
+		batch := ids[start:minInt(start+maxRowsPerStatement, len(ids))]
+		args := make([]interface{}, 0, len(batch))
+		for _, id := range batch {
+			args = append(args, id)
+		}
+
+		query := fmt.Sprintf("SELECT id, doc FROM %s WHERE id IN (%s)", quoteIdentifier(table), preparePlaceholders(1, len(batch), 1))
+		err := handler.Query(query, args, func(scan func(dest ...interface{}) error) error {
+			var id string
+			var doc []byte
+			errScan := scan(&id, &doc)
+			if errScan != nil {
+				return errScan
+			}
+
+			documents[id] = doc
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return documents, nil
+}
+
+// IterateMatchingIDs will call the provided handler with the ids of the documents that match all the provided fields,
+// in batches ordered by id
+func (ps *postgresSink) IterateMatchingIDs(index string, fields map[string]interface{}, handlerFunc func(ids []string) error) error {
+	condition, args, err := prepareMatchingCondition(fields)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf("SELECT id FROM %s WHERE %s AND id > $%d ORDER BY id LIMIT %d",
+		quoteIdentifier(index), condition, len(args)+1, maxRowsPerStatement)
+
+	lastID := ""
+	for {
+		ids := make([]string, 0, maxRowsPerStatement)
+		err = ps.dbClient.Query(query, append(args, lastID), func(scan func(dest ...interface{}) error) error {
+			var id string
+			errScan := scan(&id)
+			if errScan != nil {
+				return errScan
+			}
+
+			ids = append(ids, id)
+			return nil
+		})
+		if err != nil || len(ids) == 0 {
+			return err
+		}
+
+		err = handlerFunc(ids)
+		if err != nil {
+			return err
+		}
+
+		if len(ids) < maxRowsPerStatement {
+			return nil
+		}
+		lastID = ids[len(ids)-1]
+	}
+}
+
+// DeleteMatching will remove all the documents that match all the provided fields
+func (ps *postgresSink) DeleteMatching(index string, fields map[string]interface{}) error {
+	condition, args, err := prepareMatchingCondition(fields)
+	if err != nil {
+		return err
+	}
+
+	return ps.dbClient.Exec(fmt.Sprintf("DELETE FROM %s WHERE %s", quoteIdentifier(index), condition), args...)
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (ps *postgresSink) IsInterfaceNil() bool {
+	return ps == nil
+}
@@ -0,0 +1,186 @@
+//go:build integrationtests
+
+package postgres_test
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ME-MotherEarth/me-elastic-indexer/data"
+	"github.com/ME-MotherEarth/me-elastic-indexer/process/sink"
+	"github.com/ME-MotherEarth/me-elastic-indexer/process/sink/postgres"
+	_ "github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+)
+
+// postgresDSNEnvVariable holds the data source name of the PostgreSQL database used by the tests that run the
+// statements against a real engine, e.g. the one started by "make integration-tests-postgres"
+const postgresDSNEnvVariable = "POSTGRES_DSN"
+
+// openTestDatabase will open the database from the environment in a new schema, dropped at the end of the test. A
+// single connection is used, so all the statements see the search path of the schema
+func openTestDatabase(t *testing.T) *sql.DB {
+	dataSourceName := os.Getenv(postgresDSNEnvVariable)
+	if dataSourceName == "" {
+		t.Skipf("%s is not set", postgresDSNEnvVariable)
+	}
+
+	db, err := sql.Open("postgres", dataSourceName)
+	require.Nil(t, err)
+	db.SetMaxOpenConns(1)
+
+	schema := fmt.Sprintf("sink_test_%d", time.Now().UnixNano())
+	_, err = db.Exec(fmt.Sprintf("CREATE SCHEMA %s", schema))
+	require.Nil(t, err)
+	_, err = db.Exec(fmt.Sprintf("SET search_path TO %s", schema))
+	require.Nil(t, err)
+
+	t.Cleanup(func() {
+		_, _ = db.Exec(fmt.Sprintf("DROP SCHEMA %s CASCADE", schema))
+		_ = db.Close()
+	})
+
+	return db
+}
+
+func createEnginePostgresSink(t *testing.T, db *sql.DB) sink.DocumentsSink {
+	client, err := postgres.NewSQLClient(db)
+	require.Nil(t, err)
+
+	ps, err := postgres.NewPostgresSink(postgres.ArgsPostgresSink{DBClient: client, CustomTables: []string{"custom"}})
+	require.Nil(t, err)
+
+	return ps
+}
+
+func requireStoredDocument(t *testing.T, ps sink.DocumentsSink, index string, id string, expected map[string]interface{}) {
+	documents, err := ps.GetDocuments(index, []string{id})
+	require.Nil(t, err)
+
+	stored := make(map[string]interface{})
+	require.Nil(t, json.Unmarshal(documents[id], &stored))
+	require.Equal(t, expected, stored)
+}
+
+func TestPostgresSink_EngineShouldCreateTheTablesWithGeneratedColumns(t *testing.T) {
+	db := openTestDatabase(t)
+	ps := createEnginePostgresSink(t, db)
+
+	// the tables and the indices are created only if they do not exist
+	_ = createEnginePostgresSink(t, db)
+
+	err := ps.WriteDocuments([]*data.Document{
+		{
+			Index:  "blocks",
+			ID:     "h1",
+			Action: data.ActionIndex,
+			Body:   map[string]interface{}{"nonce": 5, "shardId": 1, "accumulatedFees": "1000000000000000000000"},
+		},
+		{
+			Index:  "scresults",
+			ID:     "s1",
+			Action: data.ActionIndex,
+			Body:   map[string]interface{}{"sender": "alice", "value": ""},
+		},
+	})
+	require.Nil(t, err)
+
+	var nonce, shardID int64
+	var accumulatedFees string
+	err = db.QueryRow(`SELECT nonce, shard_id, accumulated_fees FROM "blocks" WHERE id = $1`, "h1").Scan(&nonce, &shardID, &accumulatedFees)
+	require.Nil(t, err)
+	require.Equal(t, int64(5), nonce)
+	require.Equal(t, int64(1), shardID)
+	require.Equal(t, "1000000000000000000000", accumulatedFees)
+
+	// the empty numeric fields are stored as null
+	var value sql.NullString
+	err = db.QueryRow(`SELECT value FROM "scresults" WHERE sender = $1`, "alice").Scan(&value)
+	require.Nil(t, err)
+	require.False(t, value.Valid)
+}
+
+func TestPostgresSink_EngineShouldUpsertAndUpdateTheStoredDocuments(t *testing.T) {
+	db := openTestDatabase(t)
+	ps := createEnginePostgresSink(t, db)
+
+	tagDocument := func(tag string) *data.Document {
+		return &data.Document{
+			Index:      "tags",
+			ID:         tag,
+			Action:     data.ActionUpsert,
+			Body:       map[string]interface{}{"count": 1, "tag": tag},
+			Fields:     map[string]interface{}{"tag": tag},
+			Increments: map[string]int64{"count": 1},
+		}
+	}
+
+	err := ps.WriteDocuments([]*data.Document{tagDocument("art"), tagDocument("art"), tagDocument("music")})
+	require.Nil(t, err)
+	err = ps.WriteDocuments([]*data.Document{tagDocument("art")})
+	require.Nil(t, err)
+
+	requireStoredDocument(t, ps, "tags", "art", map[string]interface{}{"count": float64(3), "tag": "art"})
+	requireStoredDocument(t, ps, "tags", "music", map[string]interface{}{"count": float64(1), "tag": "music"})
+
+	err = ps.WriteDocuments([]*data.Document{
+		{Index: "custom", ID: "c1", Action: data.ActionIndex, Body: map[string]interface{}{"items": []string{"a"}}},
+		{Index: "custom", ID: "c1", Action: data.ActionUpdate, AddToSet: map[string][]interface{}{"items": {"a", "b"}}},
+		{Index: "custom", ID: "missing", Action: data.ActionUpdate, Fields: map[string]interface{}{"field": 1}},
+	})
+	require.Nil(t, err)
+
+	requireStoredDocument(t, ps, "custom", "c1", map[string]interface{}{"items": []interface{}{"a", "b"}})
+	documents, err := ps.GetDocuments("custom", []string{"missing"})
+	require.Nil(t, err)
+	require.Empty(t, documents)
+}
+
+func TestPostgresSink_EngineShouldIterateAndDeleteTheMatchingDocuments(t *testing.T) {
+	db := openTestDatabase(t)
+	ps := createEnginePostgresSink(t, db)
+
+	err := ps.WriteDocuments([]*data.Document{
+		{Index: "accountsmect", ID: "a1", Action: data.ActionIndex, Body: map[string]interface{}{"shardID": 1, "data": map[string]interface{}{"uris": []string{"u"}}}},
+		{Index: "accountsmect", ID: "a2", Action: data.ActionIndex, Body: map[string]interface{}{"shardID": 1}},
+		{Index: "accountsmect", ID: "a3", Action: data.ActionIndex, Body: map[string]interface{}{"shardID": 2}},
+	})
+	require.Nil(t, err)
+
+	matchingIDs := make([]string, 0)
+	err = ps.IterateMatchingIDs("accountsmect", map[string]interface{}{"shardID": 1, "data.uris": nil}, func(ids []string) error {
+		matchingIDs = append(matchingIDs, ids...)
+		return nil
+	})
+	require.Nil(t, err)
+	require.Equal(t, []string{"a2"}, matchingIDs)
+
+	err = ps.DeleteMatching("accountsmect", map[string]interface{}{"shardID": 1})
+	require.Nil(t, err)
+
+	documents, err := ps.GetDocuments("accountsmect", []string{"a1", "a2", "a3"})
+	require.Nil(t, err)
+	require.Len(t, documents, 1)
+	require.Contains(t, documents, "a3")
+
+	err = ps.WriteDocuments([]*data.Document{
+		{Index: "journal", ID: "j1", Action: data.ActionIndex, Body: map[string]interface{}{"shardId": 1, "nonce": 5}},
+		{Index: "journal", ID: "j2", Action: data.ActionIndex, Body: map[string]interface{}{"shardId": 1, "nonce": 7}},
+		{Index: "journal", ID: "j3", Action: data.ActionIndex, Body: map[string]interface{}{"shardId": 1, "nonce": 8}},
+		{Index: "journal", ID: "j4", Action: data.ActionIndex, Body: map[string]interface{}{"shardId": 2, "nonce": 5}},
+	})
+	require.Nil(t, err)
+
+	err = ps.DeleteMatching("journal", map[string]interface{}{"shardId": 1, "nonce": data.AtMost{Value: 7}})
+	require.Nil(t, err)
+
+	documents, err = ps.GetDocuments("journal", []string{"j1", "j2", "j3", "j4"})
+	require.Nil(t, err)
+	require.Len(t, documents, 2)
+	require.Contains(t, documents, "j3")
+	require.Contains(t, documents, "j4")
+}
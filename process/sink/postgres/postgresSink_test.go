@@ -0,0 +1,307 @@
+package postgres_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	elasticIndexer "github.com/ME-MotherEarth/me-elastic-indexer"
+	"github.com/ME-MotherEarth/me-elastic-indexer/data"
+	"github.com/ME-MotherEarth/me-elastic-indexer/process/sink"
+	"github.com/ME-MotherEarth/me-elastic-indexer/process/sink/postgres"
+	"github.com/stretchr/testify/require"
+)
+
+type statement struct {
+	query string
+	args  []interface{}
+}
+
+func returnRows(rows [][]interface{}, rowHandler func(scan func(dest ...interface{}) error) error) error {
+	for _, row := range rows {
+		currentRow := row
+		err := rowHandler(func(dest ...interface{}) error {
+			for idx, value := range currentRow {
+				switch d := dest[idx].(type) {
+				case *string:
+					*d = value.(string)
+				case *[]byte:
+					*d = []byte(value.(string))
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func createPostgresSink(t *testing.T, client *SQLClientStub) sink.DocumentsSink {
+	ps, err := postgres.NewPostgresSink(postgres.ArgsPostgresSink{DBClient: client})
+	require.Nil(t, err)
+
+	return ps
+}
+
+func TestNewPostgresSink(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil database client should error", func(t *testing.T) {
+		t.Parallel()
+
+		ps, err := postgres.NewPostgresSink(postgres.ArgsPostgresSink{})
+		require.Nil(t, ps)
+		require.Equal(t, elasticIndexer.ErrNilDatabaseClient, err)
+	})
+	t.Run("create table error should error", func(t *testing.T) {
+		t.Parallel()
+
+		localErr := errors.New("local error")
+		ps, err := postgres.NewPostgresSink(postgres.ArgsPostgresSink{DBClient: &SQLClientStub{
+			ExecCalled: func(query string, args ...interface{}) error {
+				return localErr
+			},
+		}})
+		require.Nil(t, ps)
+		require.True(t, errors.Is(err, localErr))
+	})
+	t.Run("should create tables with relational columns", func(t *testing.T) {
+		t.Parallel()
+
+		queries := make([]string, 0)
+		ps, err := postgres.NewPostgresSink(postgres.ArgsPostgresSink{DBClient: &SQLClientStub{
+			ExecCalled: func(query string, args ...interface{}) error {
+				queries = append(queries, query)
+				return nil
+			},
+		}})
+		require.Nil(t, err)
+		require.False(t, ps.IsInterfaceNil())
+
+		require.Contains(t, queries, `CREATE TABLE IF NOT EXISTS "tags" (id TEXT PRIMARY KEY, doc JSONB NOT NULL)`)
+		require.Contains(t, queries, `CREATE INDEX IF NOT EXISTS "transactions_sender_idx" ON "transactions" ("sender")`)
+
+		found := false
+		for _, query := range queries {
+			if strings.HasPrefix(query, `CREATE TABLE IF NOT EXISTS "transactions"`) {
+				found = true
+				require.Contains(t, query, `"sender" TEXT GENERATED ALWAYS AS (doc->>'sender') STORED`)
+				require.Contains(t, query, `"fee" NUMERIC GENERATED ALWAYS AS (NULLIF(doc->>'fee', '')::NUMERIC) STORED`)
+			}
+		}
+		require.True(t, found)
+	})
+}
+
+func TestPostgresSink_WriteDocumentsPlainShouldNotReadStoredDocuments(t *testing.T) {
+	t.Parallel()
+
+	client := &SQLClientStub{}
+	ps := createPostgresSink(t, client)
+
+	statements := make([]statement, 0)
+	client.ExecCalled = func(query string, args ...interface{}) error {
+		statements = append(statements, statement{query: query, args: args})
+		return nil
+	}
+	client.QueryCalled = func(query string, args []interface{}, rowHandler func(scan func(dest ...interface{}) error) error) error {
+		require.Fail(t, "should have not been called")
+		return nil
+	}
+
+	err := ps.WriteDocuments([]*data.Document{
+		{Index: "transactions", ID: "h1", Action: data.ActionIndex, Body: map[string]string{"status": "pending"}},
+		{Index: "blocks", ID: "b1", Action: data.ActionDelete},
+		{Index: "transactions", ID: "h2", Action: data.ActionIndex, Body: map[string]string{"status": "success"}},
+		{Index: "transactions", ID: "h1", Action: data.ActionIndex, Body: map[string]string{"status": "success"}},
+	})
+	require.Nil(t, err)
+
+	expectedStatements := []statement{
+		{query: `DELETE FROM "blocks" WHERE id IN ($1)`, args: []interface{}{"b1"}},
+		{
+			query: `INSERT INTO "transactions" (id, doc) VALUES ($1, $2), ($3, $4) ON CONFLICT (id) DO UPDATE SET doc = EXCLUDED.doc`,
+			args:  []interface{}{"h1", `{"status":"success"}`, "h2", `{"status":"success"}`},
+		},
+	}
+	require.Equal(t, expectedStatements, statements)
+}
+
+func TestPostgresSink_WriteDocumentsShouldApplyChangesOnStoredDocuments(t *testing.T) {
+	t.Parallel()
+
+	client := &SQLClientStub{}
+	ps := createPostgresSink(t, client)
+
+	statements := make([]statement, 0)
+	client.ExecCalled = func(query string, args ...interface{}) error {
+		statements = append(statements, statement{query: query, args: args})
+		return nil
+	}
+	client.QueryCalled = func(query string, args []interface{}, rowHandler func(scan func(dest ...interface{}) error) error) error {
+		require.Equal(t, `SELECT id, doc FROM "tags" WHERE id IN ($1, $2)`, query)
+		require.Equal(t, []interface{}{"YXJ0", "bXVzaWM="}, args)
+		return returnRows([][]interface{}{{"YXJ0", `{"count":2,"tag":"art"}`}}, rowHandler)
+	}
+
+	err := ps.WriteDocuments([]*data.Document{
+		{
+			Index:      "tags",
+			ID:         "YXJ0",
+			Action:     data.ActionUpsert,
+			Body:       map[string]interface{}{"count": 1, "tag": "art"},
+			Fields:     map[string]interface{}{"tag": "art"},
+			Increments: map[string]int64{"count": 1},
+		},
+		{
+			Index:      "tags",
+			ID:         "bXVzaWM=",
+			Action:     data.ActionUpsert,
+			Body:       map[string]interface{}{"count": 1, "tag": "music"},
+			Fields:     map[string]interface{}{"tag": "music"},
+			Increments: map[string]int64{"count": 1},
+		},
+	})
+	require.Nil(t, err)
+
+	expectedStatements := []statement{
+		{query: `LOCK TABLE "tags" IN SHARE ROW EXCLUSIVE MODE`},
+		{
+			query: `INSERT INTO "tags" (id, doc) VALUES ($1, $2), ($3, $4) ON CONFLICT (id) DO UPDATE SET doc = EXCLUDED.doc`,
+			args:  []interface{}{"YXJ0", `{"count":3,"tag":"art"}`, "bXVzaWM=", `{"count":1,"tag":"music"}`},
+		},
+	}
+	require.Equal(t, expectedStatements, statements)
+}
+
+func TestPostgresSink_WriteDocumentsShouldDeleteEmptyDocuments(t *testing.T) {
+	t.Parallel()
+
+	client := &SQLClientStub{}
+	ps := createPostgresSink(t, client)
+
+	statements := make([]statement, 0)
+	client.ExecCalled = func(query string, args ...interface{}) error {
+		statements = append(statements, statement{query: query, args: args})
+		return nil
+	}
+	client.QueryCalled = func(query string, args []interface{}, rowHandler func(scan func(dest ...interface{}) error) error) error {
+		return returnRows([][]interface{}{{"addr", `{"NFT-abcd":{"01":"1"}}`}}, rowHandler)
+	}
+
+	err := ps.WriteDocuments([]*data.Document{
+		{
+			Index:         "collections",
+			ID:            "addr",
+			Action:        data.ActionUpdate,
+			Fields:        map[string]interface{}{"NFT-abcd.01": nil},
+			DeleteIfEmpty: true,
+		},
+	})
+	require.Nil(t, err)
+
+	expectedStatements := []statement{
+		{query: `LOCK TABLE "collections" IN SHARE ROW EXCLUSIVE MODE`},
+		{query: `DELETE FROM "collections" WHERE id IN ($1)`, args: []interface{}{"addr"}},
+	}
+	require.Equal(t, expectedStatements, statements)
+}
+
+func TestPostgresSink_WriteDocumentsErrors(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil document should error", func(t *testing.T) {
+		t.Parallel()
+
+		ps := createPostgresSink(t, &SQLClientStub{})
+		err := ps.WriteDocuments([]*data.Document{nil})
+		require.Equal(t, sink.ErrNilDocument, err)
+	})
+	t.Run("unknown action should error", func(t *testing.T) {
+		t.Parallel()
+
+		ps := createPostgresSink(t, &SQLClientStub{})
+		err := ps.WriteDocuments([]*data.Document{{Index: "blocks", ID: "b1"}})
+		require.True(t, errors.Is(err, sink.ErrUnknownDocumentAction))
+	})
+	t.Run("transaction error should be returned", func(t *testing.T) {
+		t.Parallel()
+
+		localErr := errors.New("local error")
+		client := &SQLClientStub{}
+		ps := createPostgresSink(t, client)
+		client.DoInTransactionCalled = func(handlerFunc func(tx postgres.SQLHandler) error) error {
+			return localErr
+		}
+
+		err := ps.WriteDocuments([]*data.Document{{Index: "blocks", ID: "b1", Action: data.ActionDelete}})
+		require.Equal(t, localErr, err)
+	})
+}
+
+func TestPostgresSink_GetDocuments(t *testing.T) {
+	t.Parallel()
+
+	client := &SQLClientStub{}
+	ps := createPostgresSink(t, client)
+	client.QueryCalled = func(query string, args []interface{}, rowHandler func(scan func(dest ...interface{}) error) error) error {
+		require.Equal(t, `SELECT id, doc FROM "tokens" WHERE id IN ($1, $2)`, query)
+		return returnRows([][]interface{}{{"TKN-01", `{"type":"NonFungibleMECT"}`}}, rowHandler)
+	}
+
+	documents, err := ps.GetDocuments("tokens", []string{"TKN-01", "TKN-02"})
+	require.Nil(t, err)
+	require.Equal(t, map[string][]byte{"TKN-01": []byte(`{"type":"NonFungibleMECT"}`)}, documents)
+}
+
+func TestPostgresSink_IterateMatchingIDs(t *testing.T) {
+	t.Parallel()
+
+	client := &SQLClientStub{}
+	ps := createPostgresSink(t, client)
+	client.QueryCalled = func(query string, args []interface{}, rowHandler func(scan func(dest ...interface{}) error) error) error {
+		expectedQuery := `SELECT id FROM "accountsmect" WHERE COALESCE(jsonb_typeof(jsonb_extract_path(doc, $1)), 'null') = 'null' ` +
+			`AND doc @> $2::jsonb AND id > $3 ORDER BY id LIMIT 500`
+		require.Equal(t, expectedQuery, query)
+		require.Equal(t, []interface{}{"type", `{"token":"NFT-abcd"}`, ""}, args)
+		return returnRows([][]interface{}{{"id1"}, {"id2"}}, rowHandler)
+	}
+
+	receivedIDs := make([]string, 0)
+	err := ps.IterateMatchingIDs("accountsmect", map[string]interface{}{"token": "NFT-abcd", "type": nil}, func(ids []string) error {
+		receivedIDs = append(receivedIDs, ids...)
+		return nil
+	})
+	require.Nil(t, err)
+	require.Equal(t, []string{"id1", "id2"}, receivedIDs)
+}
+
+func TestPostgresSink_DeleteMatching(t *testing.T) {
+	t.Parallel()
+
+	client := &SQLClientStub{}
+	ps := createPostgresSink(t, client)
+
+	var executed statement
+	client.ExecCalled = func(query string, args ...interface{}) error {
+		executed = statement{query: query, args: args}
+		return nil
+	}
+
+	err := ps.DeleteMatching("accountsmect", map[string]interface{}{"shardID": uint32(1), "timestamp": uint64(1234)})
+	require.Nil(t, err)
+	require.Equal(t, statement{
+		query: `DELETE FROM "accountsmect" WHERE doc @> $1::jsonb`,
+		args:  []interface{}{`{"shardID":1,"timestamp":1234}`},
+	}, executed)
+
+	err = ps.DeleteMatching("journal", map[string]interface{}{"shardId": uint32(1), "nonce": data.AtMost{Value: 7}})
+	require.Nil(t, err)
+	require.Equal(t, statement{
+		query: `DELETE FROM "journal" WHERE (jsonb_extract_path_text(doc, $1))::numeric <= $2 AND doc @> $3::jsonb`,
+		args:  []interface{}{"nonce", uint64(7), `{"shardId":1}`},
+	}, executed)
+}
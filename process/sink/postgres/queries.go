@@ -0,0 +1,99 @@
+package postgres
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ME-MotherEarth/me-elastic-indexer/data"
+	"github.com/ME-MotherEarth/me-elastic-indexer/process/sink/docstate"
+)
+
+// prepareMatchingCondition will return a condition that matches the documents that contain all the provided fields
+// with the provided values. A nil value matches the documents that do not have the field, while a data.AtMost value
+// matches the documents whose numeric field is at most its value
+func prepareMatchingCondition(fields map[string]interface{}) (string, []interface{}, error) {
+	sortedFields := make([]string, 0, len(fields))
+	for field := range fields {
+		sortedFields = append(sortedFields, field)
+	}
+	sort.Strings(sortedFields)
+
+	contained := make(map[string]interface{})
+	conditions := make([]string, 0)
+	args := make([]interface{}, 0)
+	for _, field := range sortedFields {
+		value := fields[field]
+		upperBound, isUpperBound := value.(data.AtMost)
+		if isUpperBound {
+			var pathPlaceholders string
+			args, pathPlaceholders = appendPathArgs(args, field)
+			args = append(args, upperBound.Value)
+			conditions = append(conditions, fmt.Sprintf("(jsonb_extract_path_text(doc, %s))::numeric <= $%d", pathPlaceholders, len(args)))
+			continue
+		}
+		if value != nil {
+			docstate.ParentOf(contained, field, true)[docstate.LastKey(field)] = value
+			continue
+		}
+
+		var pathPlaceholders string
+		args, pathPlaceholders = appendPathArgs(args, field)
+		conditions = append(conditions, fmt.Sprintf("COALESCE(jsonb_typeof(jsonb_extract_path(doc, %s)), 'null') = 'null'", pathPlaceholders))
+	}
+
+	if len(contained) != 0 {
+		serializedContained, err := json.Marshal(contained)
+		if err != nil {
+			return "", nil, err
+		}
+
+		args = append(args, string(serializedContained))
+		conditions = append(conditions, fmt.Sprintf("doc @> $%d::jsonb", len(args)))
+	}
+
+	if len(conditions) == 0 {
+		return "TRUE", args, nil
+	}
+
+	return strings.Join(conditions, " AND "), args, nil
+}
+
+// appendPathArgs will append the keys of the dotted field as arguments and will return their placeholders
+func appendPathArgs(args []interface{}, field string) ([]interface{}, string) {
+	keys := strings.Split(field, ".")
+	for _, key := range keys {
+		args = append(args, key)
+	}
+
+	return args, preparePlaceholders(len(args)-len(keys)+1, len(keys), 1)
+}
+
+// preparePlaceholders will return numbered placeholders starting with the provided index, grouped in tuples if more
+// than one value is used per row
+func preparePlaceholders(start int, numRows int, valuesPerRow int) string {
+	rows := make([]string, 0, numRows)
+	for row := 0; row < numRows; row++ {
+		values := make([]string, 0, valuesPerRow)
+		for idx := 0; idx < valuesPerRow; idx++ {
+			values = append(values, fmt.Sprintf("$%d", start+row*valuesPerRow+idx))
+		}
+
+		if valuesPerRow == 1 {
+			rows = append(rows, values[0])
+			continue
+		}
+		rows = append(rows, fmt.Sprintf("(%s)", strings.Join(values, ", ")))
+	}
+
+	return strings.Join(rows, ", ")
+}
+
+func minInt(a int, b int) int {
+	if a < b {
+		return a
+	}
+
+	return b
+}
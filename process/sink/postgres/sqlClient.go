@@ -0,0 +1,102 @@
+package postgres
+
+import (
+	"database/sql"
+
+	elasticIndexer "github.com/ME-MotherEarth/me-elastic-indexer"
+)
+
+type sqlExecutor interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}
+
+type sqlClient struct {
+	db *sql.DB
+}
+
+// NewSQLClient will create a new instance of a database client that uses the provided database. The database has to
+// be opened with a driver that speaks the PostgreSQL dialect
+func NewSQLClient(db *sql.DB) (*sqlClient, error) {
+	if db == nil {
+		return nil, elasticIndexer.ErrNilSQLDatabase
+	}
+
+	return &sqlClient{
+		db: db,
+	}, nil
+}
+
+// Exec will execute the provided statement
+func (sc *sqlClient) Exec(query string, args ...interface{}) error {
+	return execStatement(sc.db, query, args)
+}
+
+// Query will execute the provided query and will call the row handler for every returned row
+func (sc *sqlClient) Query(query string, args []interface{}, rowHandler func(scan func(dest ...interface{}) error) error) error {
+	return queryRows(sc.db, query, args, rowHandler)
+}
+
+// DoInTransaction will call the provided handler with a transaction that is committed if the handler does not return
+// an error and is rolled back otherwise
+func (sc *sqlClient) DoInTransaction(handlerFunc func(tx SQLHandler) error) error {
+	tx, err := sc.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	err = handlerFunc(&sqlTransaction{tx: tx})
+	if err != nil {
+		errRollback := tx.Rollback()
+		if errRollback != nil {
+			log.Warn("sqlClient.DoInTransaction: cannot rollback transaction", "error", errRollback)
+		}
+
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (sc *sqlClient) IsInterfaceNil() bool {
+	return sc == nil
+}
+
+type sqlTransaction struct {
+	tx *sql.Tx
+}
+
+// Exec will execute the provided statement in the transaction
+func (st *sqlTransaction) Exec(query string, args ...interface{}) error {
+	return execStatement(st.tx, query, args)
+}
+
+// Query will execute the provided query in the transaction and will call the row handler for every returned row
+func (st *sqlTransaction) Query(query string, args []interface{}, rowHandler func(scan func(dest ...interface{}) error) error) error {
+	return queryRows(st.tx, query, args, rowHandler)
+}
+
+func execStatement(executor sqlExecutor, query string, args []interface{}) error {
+	_, err := executor.Exec(query, args...)
+	return err
+}
+
+func queryRows(executor sqlExecutor, query string, args []interface{}, rowHandler func(scan func(dest ...interface{}) error) error) error {
+	rows, err := executor.Query(query, args...)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	for rows.Next() {
+		err = rowHandler(rows.Scan)
+		if err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
@@ -0,0 +1,44 @@
+package postgres_test
+
+import (
+	"github.com/ME-MotherEarth/me-elastic-indexer/process/sink/postgres"
+)
+
+// SQLClientStub -
+type SQLClientStub struct {
+	ExecCalled            func(query string, args ...interface{}) error
+	QueryCalled           func(query string, args []interface{}, rowHandler func(scan func(dest ...interface{}) error) error) error
+	DoInTransactionCalled func(handlerFunc func(tx postgres.SQLHandler) error) error
+}
+
+// Exec -
+func (scs *SQLClientStub) Exec(query string, args ...interface{}) error {
+	if scs.ExecCalled != nil {
+		return scs.ExecCalled(query, args...)
+	}
+
+	return nil
+}
+
+// Query -
+func (scs *SQLClientStub) Query(query string, args []interface{}, rowHandler func(scan func(dest ...interface{}) error) error) error {
+	if scs.QueryCalled != nil {
+		return scs.QueryCalled(query, args, rowHandler)
+	}
+
+	return nil
+}
+
+// DoInTransaction -
+func (scs *SQLClientStub) DoInTransaction(handlerFunc func(tx postgres.SQLHandler) error) error {
+	if scs.DoInTransactionCalled != nil {
+		return scs.DoInTransactionCalled(handlerFunc)
+	}
+
+	return handlerFunc(scs)
+}
+
+// IsInterfaceNil -
+func (scs *SQLClientStub) IsInterfaceNil() bool {
+	return scs == nil
+}
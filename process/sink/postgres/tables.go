@@ -0,0 +1,178 @@
+package postgres
+
+import (
+	"fmt"
+	"strings"
+
+	elasticIndexer "github.com/ME-MotherEarth/me-elastic-indexer"
+)
+
+var tables = []string{
+	elasticIndexer.TransactionsIndex, elasticIndexer.BlockIndex, elasticIndexer.MiniblocksIndex, elasticIndexer.RatingIndex, elasticIndexer.RoundsIndex, elasticIndexer.ValidatorsIndex,
+	elasticIndexer.AccountsIndex, elasticIndexer.AccountsHistoryIndex, elasticIndexer.ReceiptsIndex, elasticIndexer.ScResultsIndex, elasticIndexer.AccountsMECTHistoryIndex, elasticIndexer.AccountsMECTIndex,
+	elasticIndexer.EpochInfoIndex, elasticIndexer.SCDeploysIndex, elasticIndexer.TokensIndex, elasticIndexer.TagsIndex, elasticIndexer.LogsIndex, elasticIndexer.DelegatorsIndex, elasticIndexer.OperationsIndex,
+	elasticIndexer.CollectionsIndex, elasticIndexer.JournalIndex, elasticIndexer.CheckpointsIndex, elasticIndexer.EventsIndex,
+	elasticIndexer.TransfersIndex,
+}
+
+const (
+	textColumn    = "TEXT"
+	bigintColumn  = "BIGINT"
+	numericColumn = "NUMERIC"
+)
+
+// column is a relational column generated from a field of the stored document, so the tables can be joined without
+// having to extract the fields from the json documents
+type column struct {
+	name    string
+	sqlType string
+	field   string
+	indexed bool
+}
+
+var relationalColumns = map[string][]column{
+	elasticIndexer.BlockIndex: {
+		{name: "nonce", sqlType: bigintColumn, field: "nonce", indexed: true},
+		{name: "round", sqlType: bigintColumn, field: "round"},
+		{name: "epoch", sqlType: bigintColumn, field: "epoch"},
+		{name: "shard_id", sqlType: bigintColumn, field: "shardId", indexed: true},
+		{name: "prev_hash", sqlType: textColumn, field: "prevHash"},
+		{name: "tx_count", sqlType: bigintColumn, field: "txCount"},
+		{name: "accumulated_fees", sqlType: numericColumn, field: "accumulatedFees"},
+		{name: "timestamp", sqlType: bigintColumn, field: "timestamp"},
+	},
+	elasticIndexer.TransactionsIndex: {
+		{name: "miniblock_hash", sqlType: textColumn, field: "miniBlockHash", indexed: true},
+		{name: "nonce", sqlType: bigintColumn, field: "nonce"},
+		{name: "round", sqlType: bigintColumn, field: "round"},
+		{name: "sender", sqlType: textColumn, field: "sender", indexed: true},
+		{name: "receiver", sqlType: textColumn, field: "receiver", indexed: true},
+		{name: "sender_shard", sqlType: bigintColumn, field: "senderShard"},
+		{name: "receiver_shard", sqlType: bigintColumn, field: "receiverShard"},
+		{name: "value", sqlType: numericColumn, field: "value"},
+		{name: "fee", sqlType: numericColumn, field: "fee"},
+		{name: "gas_used", sqlType: bigintColumn, field: "gasUsed"},
+		{name: "status", sqlType: textColumn, field: "status"},
+		{name: "function", sqlType: textColumn, field: "function"},
+		{name: "timestamp", sqlType: bigintColumn, field: "timestamp", indexed: true},
+	},
+	elasticIndexer.ScResultsIndex: {
+		{name: "miniblock_hash", sqlType: textColumn, field: "miniBlockHash", indexed: true},
+		{name: "nonce", sqlType: bigintColumn, field: "nonce"},
+		{name: "sender", sqlType: textColumn, field: "sender", indexed: true},
+		{name: "receiver", sqlType: textColumn, field: "receiver", indexed: true},
+		{name: "value", sqlType: numericColumn, field: "value"},
+		{name: "prev_tx_hash", sqlType: textColumn, field: "prevTxHash", indexed: true},
+		{name: "original_tx_hash", sqlType: textColumn, field: "originalTxHash", indexed: true},
+		{name: "status", sqlType: textColumn, field: "status"},
+		{name: "timestamp", sqlType: bigintColumn, field: "timestamp"},
+	},
+	elasticIndexer.AccountsIndex: {
+		{name: "address", sqlType: textColumn, field: "address", indexed: true},
+		{name: "nonce", sqlType: bigintColumn, field: "nonce"},
+		{name: "balance", sqlType: numericColumn, field: "balance"},
+		{name: "shard_id", sqlType: bigintColumn, field: "shardID"},
+		{name: "timestamp", sqlType: bigintColumn, field: "timestamp"},
+	},
+	elasticIndexer.AccountsMECTIndex: {
+		{name: "address", sqlType: textColumn, field: "address", indexed: true},
+		{name: "token", sqlType: textColumn, field: "token", indexed: true},
+		{name: "identifier", sqlType: textColumn, field: "identifier"},
+		{name: "token_nonce", sqlType: bigintColumn, field: "tokenNonce"},
+		{name: "balance", sqlType: numericColumn, field: "balance"},
+		{name: "type", sqlType: textColumn, field: "type"},
+		{name: "shard_id", sqlType: bigintColumn, field: "shardID"},
+		{name: "timestamp", sqlType: bigintColumn, field: "timestamp"},
+	},
+	elasticIndexer.TokensIndex: {
+		{name: "token", sqlType: textColumn, field: "token", indexed: true},
+		{name: "identifier", sqlType: textColumn, field: "identifier"},
+		{name: "name", sqlType: textColumn, field: "name"},
+		{name: "ticker", sqlType: textColumn, field: "ticker"},
+		{name: "type", sqlType: textColumn, field: "type"},
+		{name: "issuer", sqlType: textColumn, field: "issuer", indexed: true},
+		{name: "current_owner", sqlType: textColumn, field: "currentOwner", indexed: true},
+		{name: "num_decimals", sqlType: bigintColumn, field: "numDecimals"},
+		{name: "timestamp", sqlType: bigintColumn, field: "timestamp"},
+	},
+	elasticIndexer.EventsIndex: {
+		{name: "tx_hash", sqlType: textColumn, field: "txHash", indexed: true},
+		{name: "address", sqlType: textColumn, field: "address", indexed: true},
+		{name: "identifier", sqlType: textColumn, field: "identifier", indexed: true},
+		{name: "token_identifier", sqlType: textColumn, field: "tokenIdentifier", indexed: true},
+		{name: "nonce", sqlType: bigintColumn, field: "nonce"},
+		{name: "value", sqlType: numericColumn, field: "value"},
+		{name: "receiver", sqlType: textColumn, field: "receiver", indexed: true},
+		{name: "shard_id", sqlType: bigintColumn, field: "shardID"},
+		{name: "timestamp", sqlType: bigintColumn, field: "timestamp"},
+	},
+	elasticIndexer.TransfersIndex: {
+		{name: "tx_hash", sqlType: textColumn, field: "txHash", indexed: true},
+		{name: "scr_hash", sqlType: textColumn, field: "scrHash"},
+		{name: "sender", sqlType: textColumn, field: "sender", indexed: true},
+		{name: "receiver", sqlType: textColumn, field: "receiver", indexed: true},
+		{name: "token", sqlType: textColumn, field: "token", indexed: true},
+		{name: "token_identifier", sqlType: textColumn, field: "tokenIdentifier", indexed: true},
+		{name: "nonce", sqlType: bigintColumn, field: "nonce"},
+		{name: "amount", sqlType: numericColumn, field: "amount"},
+		{name: "shard_id", sqlType: bigintColumn, field: "shardID"},
+		{name: "timestamp", sqlType: bigintColumn, field: "timestamp"},
+	},
+}
+
+func (ps *postgresSink) createTables() error {
+	for _, table := range ps.tables {
+		err := ps.dbClient.Exec(prepareCreateTableStatement(table))
+		if err != nil {
+			return fmt.Errorf("table: %s, error: %w", table, err)
+		}
+
+		for _, col := range relationalColumns[table] {
+			if !col.indexed {
+				continue
+			}
+
+			err = ps.dbClient.Exec(prepareCreateIndexStatement(table, col.name))
+			if err != nil {
+				return fmt.Errorf("table: %s, column: %s, error: %w", table, col.name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func prepareCreateTableStatement(table string) string {
+	definitions := []string{
+		"id TEXT PRIMARY KEY",
+		"doc JSONB NOT NULL",
+	}
+	for _, col := range relationalColumns[table] {
+		definitions = append(definitions, fmt.Sprintf("%s %s GENERATED ALWAYS AS (%s) STORED", quoteIdentifier(col.name), col.sqlType, fieldExpression(col)))
+	}
+
+	return fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (%s)", quoteIdentifier(table), strings.Join(definitions, ", "))
+}
+
+func prepareCreateIndexStatement(table string, columnName string) string {
+	indexName := quoteIdentifier(fmt.Sprintf("%s_%s_idx", table, columnName))
+	return fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s (%s)", indexName, quoteIdentifier(table), quoteIdentifier(columnName))
+}
+
+func fieldExpression(col column) string {
+	textValue := fmt.Sprintf("doc->>%s", quoteLiteral(col.field))
+	if col.sqlType == textColumn {
+		return textValue
+	}
+
+	// the numeric fields can be empty strings in the stored documents (e.g. the value of some smart contract results)
+	return fmt.Sprintf("NULLIF(%s, '')::%s", textValue, col.sqlType)
+}
+
+func quoteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+func quoteLiteral(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
@@ -1,16 +1,11 @@
 package statistics
 
 import (
-	"bytes"
-	"encoding/json"
 	"fmt"
 
 	"github.com/ME-MotherEarth/me-elastic-indexer/data"
-	logger "github.com/ME-MotherEarth/me-logger"
 )
 
-var log = logger.GetOrCreate("indexer/process/statistics")
-
 type statisticsProcessor struct {
 }
 
@@ -20,36 +15,13 @@ func NewStatisticsProcessor() *statisticsProcessor {
 }
 
 // SerializeRoundsInfo will serialize information about rounds
-func (sp *statisticsProcessor) SerializeRoundsInfo(roundsInfo []*data.RoundInfo) *bytes.Buffer {
-	buff := &bytes.Buffer{}
+func (sp *statisticsProcessor) SerializeRoundsInfo(roundsInfo []*data.RoundInfo, docs *data.DocumentsSlice, index string) {
 	for _, info := range roundsInfo {
-		serializedRoundInfo, meta := serializeRoundInfo(info)
-
-		buff.Grow(len(meta) + len(serializedRoundInfo))
-		_, err := buff.Write(meta)
-		if err != nil {
-			log.Warn("generalInfoProcessor.SaveRoundsInfo cannot write meta", "error", err)
-		}
-
-		_, err = buff.Write(serializedRoundInfo)
-		if err != nil {
-			log.Warn("generalInfoProcessor.SaveRoundsInfo cannot write serialized round info", "error", err)
-		}
-	}
-
-	return buff
-}
-
-func serializeRoundInfo(info *data.RoundInfo) ([]byte, []byte) {
-	meta := []byte(fmt.Sprintf(`{ "index" : { "_id" : "%d_%d" } }%s`, info.ShardId, info.Index, "\n"))
-
-	serializedInfo, err := json.Marshal(info)
-	if err != nil {
-		log.Warn("serializeRoundInfo could not serialize round info, will skip indexing this round info", "error", err)
-		return nil, nil
+		docs.Add(&data.Document{
+			Index:  index,
+			ID:     fmt.Sprintf("%d_%d", info.ShardId, info.Index),
+			Action: data.ActionIndex,
+			Body:   info,
+		})
 	}
-
-	serializedInfo = append(serializedInfo, "\n"...)
-
-	return serializedInfo, meta
 }
@@ -12,11 +12,14 @@ func TestStatisticsProcessor_SerializeRoundsInfo(t *testing.T) {
 
 	sp := NewStatisticsProcessor()
 
-	buff := sp.SerializeRoundsInfo([]*data.RoundInfo{{
+	roundInfo := &data.RoundInfo{
 		Epoch: 1,
-	}})
-	expectedBuff := `{ "index" : { "_id" : "0_0" } }
-{"round":0,"signersIndexes":null,"blockWasProposed":false,"shardId":0,"epoch":1,"timestamp":0}
-`
-	require.Equal(t, expectedBuff, buff.String())
+	}
+	docs := data.NewDocumentsSlice()
+	sp.SerializeRoundsInfo([]*data.RoundInfo{roundInfo}, docs, "rounds")
+
+	expectedDocs := []*data.Document{
+		{Index: "rounds", ID: "0_0", Action: data.ActionIndex, Body: roundInfo},
+	}
+	require.Equal(t, expectedDocs, docs.Documents())
 }
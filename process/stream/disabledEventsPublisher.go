@@ -0,0 +1,33 @@
+package stream
+
+import (
+	coreData "github.com/ME-MotherEarth/me-core/data"
+	"github.com/ME-MotherEarth/me-elastic-indexer/data"
+)
+
+type disabledEventsPublisher struct{}
+
+// NewDisabledEventsPublisher will create an events publisher that does not publish anything
+func NewDisabledEventsPublisher() *disabledEventsPublisher {
+	return &disabledEventsPublisher{}
+}
+
+// PublishBlock does nothing
+func (dep *disabledEventsPublisher) PublishBlock(_ []byte, _ coreData.HeaderHandler, _ *data.Block) error {
+	return nil
+}
+
+// PublishTransactions does nothing
+func (dep *disabledEventsPublisher) PublishTransactions(_ []byte, _ coreData.HeaderHandler, _ *data.PreparedBlockTransactions) error {
+	return nil
+}
+
+// PublishRevert does nothing
+func (dep *disabledEventsPublisher) PublishRevert(_ []byte, _ coreData.HeaderHandler) error {
+	return nil
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (dep *disabledEventsPublisher) IsInterfaceNil() bool {
+	return dep == nil
+}
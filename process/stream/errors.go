@@ -0,0 +1,12 @@
+package stream
+
+import "errors"
+
+// ErrNilBroker signals that a nil broker has been provided
+var ErrNilBroker = errors.New("nil broker")
+
+// ErrNilHeader signals that a nil header has been provided
+var ErrNilHeader = errors.New("nil header")
+
+// ErrBrokerClosed signals that the broker has been closed
+var ErrBrokerClosed = errors.New("broker closed")
@@ -0,0 +1,203 @@
+package stream
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ME-MotherEarth/me-core/core/check"
+	coreData "github.com/ME-MotherEarth/me-core/data"
+	"github.com/ME-MotherEarth/me-elastic-indexer/data"
+	logger "github.com/ME-MotherEarth/me-logger"
+)
+
+var log = logger.GetOrCreate("indexer/process/stream")
+
+const (
+	maxPublishAttempts            = 3
+	durationBetweenPublishRetries = time.Millisecond * 500
+)
+
+// ArgsEventsPublisher holds all dependencies required by the eventsPublisher in order to create new instances
+type ArgsEventsPublisher struct {
+	Broker      BrokerHandler
+	TopicPrefix string
+}
+
+type eventsPublisher struct {
+	broker      BrokerHandler
+	topicPrefix string
+	retryDelay  time.Duration
+}
+
+// NewEventsPublisher will create a component that publishes the indexed data of every block as versioned messages.
+// Every message type is published on its own topic, keyed by the shard, so the messages of a shard keep their order.
+// A message that cannot be published is retried a few times, on its own, before the error is returned
+func NewEventsPublisher(args ArgsEventsPublisher) (*eventsPublisher, error) {
+	if check.IfNil(args.Broker) {
+		return nil, ErrNilBroker
+	}
+
+	return &eventsPublisher{
+		broker:      args.Broker,
+		topicPrefix: args.TopicPrefix,
+		retryDelay:  durationBetweenPublishRetries,
+	}, nil
+}
+
+// PublishBlock will publish the provided block
+func (ep *eventsPublisher) PublishBlock(headerHash []byte, header coreData.HeaderHandler, block *data.Block) error {
+	if block == nil {
+		return nil
+	}
+
+	return ep.publish(BlockMessage, headerHash, header, &BlockEvent{
+		Hash:  hex.EncodeToString(headerHash),
+		Block: block,
+	})
+}
+
+// PublishTransactions will publish the transactions, smart contract results, logs, tokens and delegators of the
+// provided prepared transactions. Empty collections are not published
+func (ep *eventsPublisher) PublishTransactions(headerHash []byte, header coreData.HeaderHandler, preparedTxs *data.PreparedBlockTransactions) error {
+	if preparedTxs == nil {
+		return nil
+	}
+
+	if preparedTxs.Results != nil {
+		err := ep.publishTransactions(headerHash, header, preparedTxs.Results)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(preparedTxs.Logs) > 0 {
+		logEvents := make([]*LogEvent, 0, len(preparedTxs.Logs))
+		for _, logs := range preparedTxs.Logs {
+			logEvents = append(logEvents, &LogEvent{ID: logs.ID, Logs: logs})
+		}
+
+		err := ep.publish(LogsMessage, headerHash, header, logEvents)
+		if err != nil {
+			return err
+		}
+	}
+
+	if preparedTxs.LogsData == nil {
+		return nil
+	}
+
+	return ep.publishLogsData(headerHash, header, preparedTxs.LogsData)
+}
+
+func (ep *eventsPublisher) publishTransactions(headerHash []byte, header coreData.HeaderHandler, results *data.PreparedResults) error {
+	if len(results.Transactions) > 0 {
+		txEvents := make([]*TransactionEvent, 0, len(results.Transactions))
+		for _, tx := range results.Transactions {
+			txEvents = append(txEvents, &TransactionEvent{Hash: tx.Hash, Transaction: tx})
+		}
+
+		err := ep.publish(TransactionsMessage, headerHash, header, txEvents)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(results.ScResults) == 0 {
+		return nil
+	}
+
+	scrEvents := make([]*ScResultEvent, 0, len(results.ScResults))
+	for _, scr := range results.ScResults {
+		scrEvents = append(scrEvents, &ScResultEvent{Hash: scr.Hash, ScResult: scr})
+	}
+
+	return ep.publish(ScResultsMessage, headerHash, header, scrEvents)
+}
+
+func (ep *eventsPublisher) publishLogsData(headerHash []byte, header coreData.HeaderHandler, logsData *data.PreparedLogsResults) error {
+	if len(logsData.TokensInfo) > 0 {
+		err := ep.publish(TokensMessage, headerHash, header, logsData.TokensInfo)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(logsData.Delegators) == 0 {
+		return nil
+	}
+
+	delegatorEvents := make([]*DelegatorEvent, 0, len(logsData.Delegators))
+	for _, delegator := range logsData.Delegators {
+		delegatorEvents = append(delegatorEvents, &DelegatorEvent{Removed: delegator.ShouldDelete, Delegator: delegator})
+	}
+
+	return ep.publish(DelegatorsMessage, headerHash, header, delegatorEvents)
+}
+
+// PublishRevert will publish a message that signals that the provided block was reverted
+func (ep *eventsPublisher) PublishRevert(headerHash []byte, header coreData.HeaderHandler) error {
+	return ep.publish(RevertMessage, headerHash, header, nil)
+}
+
+func (ep *eventsPublisher) publish(messageType MessageType, headerHash []byte, header coreData.HeaderHandler, payload interface{}) error {
+	if check.IfNil(header) {
+		return ErrNilHeader
+	}
+
+	message := &Message{
+		Version:    MessageVersion,
+		Type:       messageType,
+		ShardID:    header.GetShardID(),
+		Epoch:      header.GetEpoch(),
+		Nonce:      header.GetNonce(),
+		Round:      header.GetRound(),
+		HeaderHash: hex.EncodeToString(headerHash),
+		Timestamp:  header.GetTimeStamp(),
+	}
+
+	if payload != nil {
+		serializedPayload, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+
+		message.Payload = serializedPayload
+	}
+
+	serializedMessage, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	topic := ep.topic(messageType)
+	key := []byte(strconv.FormatUint(uint64(message.ShardID), 10))
+	for attempt := 1; ; attempt++ {
+		err = ep.broker.Publish(topic, key, serializedMessage)
+		if err == nil {
+			return nil
+		}
+		if attempt >= maxPublishAttempts {
+			return fmt.Errorf("%w while publishing %s message for block with nonce %d", err, messageType, message.Nonce)
+		}
+
+		log.Warn("eventsPublisher: cannot publish message, will retry",
+			"type", messageType, "nonce", message.Nonce, "attempt", attempt, "error", err.Error())
+		time.Sleep(ep.retryDelay)
+	}
+}
+
+func (ep *eventsPublisher) topic(messageType MessageType) string {
+	if ep.topicPrefix == "" {
+		return string(messageType)
+	}
+
+	return ep.topicPrefix + "." + string(messageType)
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (ep *eventsPublisher) IsInterfaceNil() bool {
+	return ep == nil
+}
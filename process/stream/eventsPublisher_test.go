@@ -0,0 +1,199 @@
+package stream
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	dataBlock "github.com/ME-MotherEarth/me-core/data/block"
+	"github.com/ME-MotherEarth/me-elastic-indexer/data"
+	"github.com/stretchr/testify/require"
+)
+
+type brokerStub struct {
+	PublishCalled func(topic string, key []byte, value []byte) error
+}
+
+func (bs *brokerStub) Publish(topic string, key []byte, value []byte) error {
+	return bs.PublishCalled(topic, key, value)
+}
+
+func (bs *brokerStub) IsInterfaceNil() bool {
+	return bs == nil
+}
+
+func decodeSingleMessage(t *testing.T, broker *inMemoryBroker, topic string) *Message {
+	messages := broker.Messages(topic)
+	require.Len(t, messages, 1)
+
+	message, err := DecodeMessage(messages[0].Value)
+	require.Nil(t, err)
+
+	return message
+}
+
+func TestNewEventsPublisher(t *testing.T) {
+	t.Parallel()
+
+	ep, err := NewEventsPublisher(ArgsEventsPublisher{})
+	require.Nil(t, ep)
+	require.Equal(t, ErrNilBroker, err)
+
+	ep, err = NewEventsPublisher(ArgsEventsPublisher{Broker: NewInMemoryBroker()})
+	require.Nil(t, err)
+	require.False(t, ep.IsInterfaceNil())
+}
+
+func TestEventsPublisher_PublishBlock(t *testing.T) {
+	t.Parallel()
+
+	broker := NewInMemoryBroker()
+	ep, _ := NewEventsPublisher(ArgsEventsPublisher{Broker: broker, TopicPrefix: "indexer"})
+
+	header := &dataBlock.Header{ShardID: 1, Nonce: 10, Round: 11, Epoch: 2, TimeStamp: 5000}
+	err := ep.PublishBlock([]byte("hash"), header, &data.Block{Nonce: 10, ShardID: 1})
+	require.Nil(t, err)
+
+	messages := broker.Messages("indexer.block")
+	require.Len(t, messages, 1)
+	require.Equal(t, []byte("1"), messages[0].Key)
+
+	message := decodeSingleMessage(t, broker, "indexer.block")
+	require.Equal(t, MessageVersion, message.Version)
+	require.Equal(t, BlockMessage, message.Type)
+	require.Equal(t, uint32(1), message.ShardID)
+	require.Equal(t, uint32(2), message.Epoch)
+	require.Equal(t, uint64(10), message.Nonce)
+	require.Equal(t, uint64(11), message.Round)
+	require.Equal(t, "68617368", message.HeaderHash)
+	require.Equal(t, uint64(5000), message.Timestamp)
+
+	blockEvent := &BlockEvent{}
+	err = json.Unmarshal(message.Payload, blockEvent)
+	require.Nil(t, err)
+	require.Equal(t, "68617368", blockEvent.Hash)
+	require.Equal(t, uint64(10), blockEvent.Nonce)
+}
+
+func TestEventsPublisher_PublishTransactions(t *testing.T) {
+	t.Parallel()
+
+	broker := NewInMemoryBroker()
+	ep, _ := NewEventsPublisher(ArgsEventsPublisher{Broker: broker})
+
+	preparedTxs := &data.PreparedBlockTransactions{
+		Results: &data.PreparedResults{
+			Transactions: []*data.Transaction{{Hash: "tx1", Sender: "moa1"}},
+			ScResults:    []*data.ScResult{{Hash: "scr1", OriginalTxHash: "tx1"}},
+		},
+		Logs: []*data.Logs{{ID: "tx1", Address: "moa1"}},
+		LogsData: &data.PreparedLogsResults{
+			TokensInfo: []*data.TokenInfo{{Token: "TKN-01234"}},
+			Delegators: map[string]*data.Delegator{"key": {Address: "moa1", Contract: "sc", ShouldDelete: true}},
+		},
+	}
+	err := ep.PublishTransactions([]byte("hash"), &dataBlock.Header{}, preparedTxs)
+	require.Nil(t, err)
+
+	txEvents := make([]*TransactionEvent, 0)
+	_ = json.Unmarshal(decodeSingleMessage(t, broker, "transactions").Payload, &txEvents)
+	require.Equal(t, []*TransactionEvent{{Hash: "tx1", Transaction: &data.Transaction{Sender: "moa1"}}}, txEvents)
+
+	scrEvents := make([]*ScResultEvent, 0)
+	_ = json.Unmarshal(decodeSingleMessage(t, broker, "scResults").Payload, &scrEvents)
+	require.Equal(t, []*ScResultEvent{{Hash: "scr1", ScResult: &data.ScResult{OriginalTxHash: "tx1"}}}, scrEvents)
+
+	logEvents := make([]*LogEvent, 0)
+	_ = json.Unmarshal(decodeSingleMessage(t, broker, "logs").Payload, &logEvents)
+	require.Equal(t, []*LogEvent{{ID: "tx1", Logs: &data.Logs{Address: "moa1"}}}, logEvents)
+
+	tokens := make([]*data.TokenInfo, 0)
+	_ = json.Unmarshal(decodeSingleMessage(t, broker, "tokens").Payload, &tokens)
+	require.Equal(t, []*data.TokenInfo{{Token: "TKN-01234"}}, tokens)
+
+	delegatorEvents := make([]*DelegatorEvent, 0)
+	_ = json.Unmarshal(decodeSingleMessage(t, broker, "delegators").Payload, &delegatorEvents)
+	require.Equal(t, []*DelegatorEvent{{Removed: true, Delegator: &data.Delegator{Address: "moa1", Contract: "sc"}}}, delegatorEvents)
+}
+
+func TestEventsPublisher_PublishTransactionsShouldSkipEmptyCollections(t *testing.T) {
+	t.Parallel()
+
+	ep, _ := NewEventsPublisher(ArgsEventsPublisher{Broker: &brokerStub{
+		PublishCalled: func(topic string, key []byte, value []byte) error {
+			require.Fail(t, "should have not been called")
+			return nil
+		},
+	}})
+
+	err := ep.PublishTransactions([]byte("hash"), &dataBlock.Header{}, &data.PreparedBlockTransactions{
+		Results:  &data.PreparedResults{},
+		LogsData: &data.PreparedLogsResults{},
+	})
+	require.Nil(t, err)
+}
+
+func TestEventsPublisher_PublishRevert(t *testing.T) {
+	t.Parallel()
+
+	broker := NewInMemoryBroker()
+	ep, _ := NewEventsPublisher(ArgsEventsPublisher{Broker: broker})
+
+	err := ep.PublishRevert([]byte("hash"), &dataBlock.Header{Nonce: 7})
+	require.Nil(t, err)
+
+	message := decodeSingleMessage(t, broker, "revert")
+	require.Equal(t, RevertMessage, message.Type)
+	require.Equal(t, uint64(7), message.Nonce)
+	require.Equal(t, "68617368", message.HeaderHash)
+	require.Nil(t, message.Payload)
+}
+
+func TestEventsPublisher_PublishErrors(t *testing.T) {
+	t.Parallel()
+
+	localErr := errors.New("local error")
+	numPublishes := 0
+	ep, _ := NewEventsPublisher(ArgsEventsPublisher{Broker: &brokerStub{
+		PublishCalled: func(topic string, key []byte, value []byte) error {
+			numPublishes++
+			return localErr
+		},
+	}})
+	ep.retryDelay = 0
+
+	err := ep.PublishRevert([]byte("hash"), nil)
+	require.Equal(t, ErrNilHeader, err)
+
+	err = ep.PublishRevert([]byte("hash"), &dataBlock.Header{})
+	require.True(t, errors.Is(err, localErr))
+	require.Equal(t, maxPublishAttempts, numPublishes)
+}
+
+func TestEventsPublisher_FailedMessageShouldBeRetriedOnItsOwn(t *testing.T) {
+	t.Parallel()
+
+	publishedTopics := make([]string, 0)
+	failedOnce := false
+	ep, _ := NewEventsPublisher(ArgsEventsPublisher{Broker: &brokerStub{
+		PublishCalled: func(topic string, key []byte, value []byte) error {
+			if topic == string(ScResultsMessage) && !failedOnce {
+				failedOnce = true
+				return errors.New("local error")
+			}
+
+			publishedTopics = append(publishedTopics, topic)
+			return nil
+		},
+	}})
+	ep.retryDelay = 0
+
+	err := ep.PublishTransactions([]byte("hash"), &dataBlock.Header{}, &data.PreparedBlockTransactions{
+		Results: &data.PreparedResults{
+			Transactions: []*data.Transaction{{Hash: "tx"}},
+			ScResults:    []*data.ScResult{{Hash: "scr"}},
+		},
+	})
+	require.Nil(t, err)
+	require.Equal(t, []string{string(TransactionsMessage), string(ScResultsMessage)}, publishedTopics)
+}
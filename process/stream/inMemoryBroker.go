@@ -0,0 +1,101 @@
+package stream
+
+import "sync"
+
+// BrokerMessage is a message received by the in memory broker
+type BrokerMessage struct {
+	Topic string
+	Key   []byte
+	Value []byte
+}
+
+type inMemoryBroker struct {
+	mut         sync.RWMutex
+	closed      bool
+	messages    map[string][]*BrokerMessage
+	subscribers map[string][]chan *BrokerMessage
+}
+
+// NewInMemoryBroker will create an in process broker that keeps all the published messages and delivers them to the
+// subscribers of their topic. It can be used instead of a real broker by tests and by the components that run in
+// the same process as the indexer
+func NewInMemoryBroker() *inMemoryBroker {
+	return &inMemoryBroker{
+		messages:    make(map[string][]*BrokerMessage),
+		subscribers: make(map[string][]chan *BrokerMessage),
+	}
+}
+
+// Publish will keep the message and will deliver it to the subscribers of the topic. It blocks while the channel of a
+// subscriber is full
+func (imb *inMemoryBroker) Publish(topic string, key []byte, value []byte) error {
+	imb.mut.Lock()
+	defer imb.mut.Unlock()
+
+	if imb.closed {
+		return ErrBrokerClosed
+	}
+
+	message := &BrokerMessage{
+		Topic: topic,
+		Key:   key,
+		Value: value,
+	}
+	imb.messages[topic] = append(imb.messages[topic], message)
+	for _, subscriber := range imb.subscribers[topic] {
+		subscriber <- message
+	}
+
+	return nil
+}
+
+// Subscribe will return a channel that receives the messages published on the provided topic from now on
+func (imb *inMemoryBroker) Subscribe(topic string, bufferSize int) <-chan *BrokerMessage {
+	imb.mut.Lock()
+	defer imb.mut.Unlock()
+
+	subscriber := make(chan *BrokerMessage, bufferSize)
+	if imb.closed {
+		close(subscriber)
+		return subscriber
+	}
+
+	imb.subscribers[topic] = append(imb.subscribers[topic], subscriber)
+
+	return subscriber
+}
+
+// Messages will return all the messages published on the provided topic
+func (imb *inMemoryBroker) Messages(topic string) []*BrokerMessage {
+	imb.mut.RLock()
+	defer imb.mut.RUnlock()
+
+	messages := make([]*BrokerMessage, len(imb.messages[topic]))
+	copy(messages, imb.messages[topic])
+
+	return messages
+}
+
+// Close will close the channels of all the subscribers
+func (imb *inMemoryBroker) Close() error {
+	imb.mut.Lock()
+	defer imb.mut.Unlock()
+
+	if imb.closed {
+		return nil
+	}
+
+	imb.closed = true
+	for _, subscribers := range imb.subscribers {
+		for _, subscriber := range subscribers {
+			close(subscriber)
+		}
+	}
+
+	return nil
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (imb *inMemoryBroker) IsInterfaceNil() bool {
+	return imb == nil
+}
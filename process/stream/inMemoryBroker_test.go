@@ -0,0 +1,39 @@
+package stream
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInMemoryBroker_SubscribeShouldReceiveMessagesInOrder(t *testing.T) {
+	t.Parallel()
+
+	broker := NewInMemoryBroker()
+	subscriber := broker.Subscribe("blocks", 10)
+
+	_ = broker.Publish("blocks", []byte("0"), []byte("first"))
+	_ = broker.Publish("transactions", []byte("0"), []byte("other topic"))
+	_ = broker.Publish("blocks", []byte("0"), []byte("second"))
+
+	require.Equal(t, []byte("first"), (<-subscriber).Value)
+	require.Equal(t, []byte("second"), (<-subscriber).Value)
+	require.Len(t, broker.Messages("blocks"), 2)
+	require.Len(t, broker.Messages("transactions"), 1)
+}
+
+func TestInMemoryBroker_Close(t *testing.T) {
+	t.Parallel()
+
+	broker := NewInMemoryBroker()
+	subscriber := broker.Subscribe("blocks", 1)
+
+	err := broker.Close()
+	require.Nil(t, err)
+
+	_, ok := <-subscriber
+	require.False(t, ok)
+
+	err = broker.Publish("blocks", nil, []byte("message"))
+	require.Equal(t, ErrBrokerClosed, err)
+}
@@ -0,0 +1,8 @@
+package stream
+
+// BrokerHandler defines what a message broker client (e.g. kafka producer, nats connection) has to do in order to
+// receive the indexed data. The messages with the same key have to be delivered in the order they were published
+type BrokerHandler interface {
+	Publish(topic string, key []byte, value []byte) error
+	IsInterfaceNil() bool
+}
@@ -0,0 +1,85 @@
+package stream
+
+import (
+	"encoding/json"
+
+	"github.com/ME-MotherEarth/me-elastic-indexer/data"
+)
+
+// MessageVersion is the version of the format of the published messages. It is increased on every change that is not
+// backwards compatible
+const MessageVersion uint32 = 1
+
+// MessageType defines the content of a published message
+type MessageType string
+
+const (
+	// BlockMessage holds a BlockEvent
+	BlockMessage MessageType = "block"
+	// TransactionsMessage holds a slice of TransactionEvent
+	TransactionsMessage MessageType = "transactions"
+	// ScResultsMessage holds a slice of ScResultEvent
+	ScResultsMessage MessageType = "scResults"
+	// LogsMessage holds a slice of LogEvent
+	LogsMessage MessageType = "logs"
+	// TokensMessage holds a slice of data.TokenInfo with the issued or changed tokens
+	TokensMessage MessageType = "tokens"
+	// DelegatorsMessage holds a slice of DelegatorEvent
+	DelegatorsMessage MessageType = "delegators"
+	// RevertMessage signals that all the data previously published for the block has to be discarded
+	RevertMessage MessageType = "revert"
+)
+
+// Message is the envelope of every published message. The header fields identify the block the payload belongs to
+type Message struct {
+	Version    uint32          `json:"version"`
+	Type       MessageType     `json:"type"`
+	ShardID    uint32          `json:"shardID"`
+	Epoch      uint32          `json:"epoch"`
+	Nonce      uint64          `json:"nonce"`
+	Round      uint64          `json:"round"`
+	HeaderHash string          `json:"headerHash"`
+	Timestamp  uint64          `json:"timestamp"`
+	Payload    json.RawMessage `json:"payload,omitempty"`
+}
+
+// BlockEvent is the payload of a BlockMessage
+type BlockEvent struct {
+	Hash string `json:"hash"`
+	*data.Block
+}
+
+// TransactionEvent is an element of the payload of a TransactionsMessage
+type TransactionEvent struct {
+	Hash string `json:"hash"`
+	*data.Transaction
+}
+
+// ScResultEvent is an element of the payload of a ScResultsMessage
+type ScResultEvent struct {
+	Hash string `json:"hash"`
+	*data.ScResult
+}
+
+// LogEvent is an element of the payload of a LogsMessage
+type LogEvent struct {
+	ID string `json:"id"`
+	*data.Logs
+}
+
+// DelegatorEvent is an element of the payload of a DelegatorsMessage
+type DelegatorEvent struct {
+	Removed bool `json:"removed"`
+	*data.Delegator
+}
+
+// DecodeMessage will unmarshal a published message. The payload can be unmarshalled afterwards, based on the message type
+func DecodeMessage(buff []byte) (*Message, error) {
+	message := &Message{}
+	err := json.Unmarshal(buff, message)
+	if err != nil {
+		return nil, err
+	}
+
+	return message, nil
+}
@@ -2,34 +2,32 @@ package tags
 
 import (
 	"encoding/base64"
-	"fmt"
 
-	"github.com/ME-MotherEarth/me-elastic-indexer/converters"
 	"github.com/ME-MotherEarth/me-elastic-indexer/data"
 )
 
-// Serialize will serialize tagsCount in a way that Elastic Search expects a bulk request
-func (tc *tagsCount) Serialize(buffSlice *data.BufferSlice, index string) error {
+// Serialize will serialize tagsCount in documents that can be written in the database
+func (tc *tagsCount) Serialize(docs *data.DocumentsSlice, index string) error {
 	for tag, count := range tc.tags {
 		if tag == "" {
 			continue
 		}
 
-		base64Tag := base64.StdEncoding.EncodeToString([]byte(tag))
-		meta := []byte(fmt.Sprintf(`{ "update" : {"_index":"%s", "_id" : "%s" } }%s`, index, converters.JsonEscape(base64Tag), "\n"))
-
-		codeToExecute := `
-			ctx._source.count += params.count; 
-			ctx._source.tag = params.tag
-`
-		serializedDataStr := fmt.Sprintf(`{"script": {"source": "%s","lang": "painless","params": {"count": %d, "tag": "%s"}},"upsert": {"count": %d, "tag":"%s"}}`,
-			converters.FormatPainlessSource(codeToExecute), count, converters.JsonEscape(tag), count, converters.JsonEscape(tag),
-		)
-
-		err := buffSlice.PutData(meta, []byte(serializedDataStr))
-		if err != nil {
-			return err
-		}
+		docs.Add(&data.Document{
+			Index:  index,
+			ID:     base64.StdEncoding.EncodeToString([]byte(tag)),
+			Action: data.ActionUpsert,
+			Body: map[string]interface{}{
+				"count": count,
+				"tag":   tag,
+			},
+			Fields: map[string]interface{}{
+				"tag": tag,
+			},
+			Increments: map[string]int64{
+				"count": int64(count),
+			},
+		})
 	}
 
 	return nil
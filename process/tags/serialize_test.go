@@ -16,12 +16,19 @@ func TestTagsCount_Serialize(t *testing.T) {
 	tagsC.ParseTags([]string{"Art"})
 	tagsC.ParseTags([]string{"Art"})
 
-	buffSlice := data.NewBufferSlice(data.DefaultMaxBulkSize)
-	err := tagsC.Serialize(buffSlice, "tags")
+	docs := data.NewDocumentsSlice()
+	err := tagsC.Serialize(docs, "tags")
 	require.Nil(t, err)
 
-	expected := `{ "update" : {"_index":"tags", "_id" : "QXJ0" } }
-{"script": {"source": "ctx._source.count += params.count; ctx._source.tag = params.tag","lang": "painless","params": {"count": 2, "tag": "Art"}},"upsert": {"count": 2, "tag":"Art"}}
-`
-	require.Equal(t, expected, buffSlice.Buffers()[0].String())
+	expectedDocs := []*data.Document{
+		{
+			Index:      "tags",
+			ID:         "QXJ0",
+			Action:     data.ActionUpsert,
+			Body:       map[string]interface{}{"count": 2, "tag": "Art"},
+			Fields:     map[string]interface{}{"tag": "Art"},
+			Increments: map[string]int64{"count": 2},
+		},
+	}
+	require.Equal(t, expectedDocs, docs.Documents())
 }
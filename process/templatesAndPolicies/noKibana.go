@@ -37,9 +37,14 @@ func (tr *templatesAndPolicyReaderNoKibana) GetElasticTemplatesAndPolicies() (ma
 	indexTemplates[indexer.TokensIndex] = noKibana.Tokens.ToBuffer()
 	indexTemplates[indexer.TagsIndex] = noKibana.Tags.ToBuffer()
 	indexTemplates[indexer.LogsIndex] = noKibana.Logs.ToBuffer()
+	indexTemplates[indexer.EventsIndex] = noKibana.Events.ToBuffer()
+	indexTemplates[indexer.TransfersIndex] = noKibana.Transfers.ToBuffer()
 	indexTemplates[indexer.DelegatorsIndex] = noKibana.Delegators.ToBuffer()
 	indexTemplates[indexer.OperationsIndex] = noKibana.Operations.ToBuffer()
 	indexTemplates[indexer.CollectionsIndex] = noKibana.Collections.ToBuffer()
+	indexTemplates[indexer.JournalIndex] = noKibana.Journal.ToBuffer()
+	indexTemplates[indexer.CheckpointsIndex] = noKibana.Checkpoints.ToBuffer()
+	indexTemplates[indexer.MigrationsIndex] = noKibana.Migrations.ToBuffer()
 
 	return indexTemplates, indexPolicies, nil
 }
@@ -43,9 +43,14 @@ func getTemplatesKibana() map[string]*bytes.Buffer {
 	indexTemplates[indexer.TokensIndex] = withKibana.Tokens.ToBuffer()
 	indexTemplates[indexer.TagsIndex] = withKibana.Tags.ToBuffer()
 	indexTemplates[indexer.LogsIndex] = withKibana.Logs.ToBuffer()
+	indexTemplates[indexer.EventsIndex] = withKibana.Events.ToBuffer()
+	indexTemplates[indexer.TransfersIndex] = withKibana.Transfers.ToBuffer()
 	indexTemplates[indexer.DelegatorsIndex] = withKibana.Delegators.ToBuffer()
 	indexTemplates[indexer.OperationsIndex] = withKibana.Operations.ToBuffer()
 	indexTemplates[indexer.CollectionsIndex] = withKibana.Collections.ToBuffer()
+	indexTemplates[indexer.JournalIndex] = withKibana.Journal.ToBuffer()
+	indexTemplates[indexer.CheckpointsIndex] = withKibana.Checkpoints.ToBuffer()
+	indexTemplates[indexer.MigrationsIndex] = withKibana.Migrations.ToBuffer()
 
 	return indexTemplates
 }
@@ -0,0 +1,121 @@
+package process
+
+import (
+	"sync"
+
+	"github.com/ME-MotherEarth/me-elastic-indexer/data"
+)
+
+// maxCachedTokensDecimals bounds the number of tokens whose number of decimals is kept in memory. The number of
+// decimals of a token does not change, so the cache is emptied when full and filled again from the tokens index
+const maxCachedTokensDecimals = 100000
+
+// tokenDecimalsCache keeps the number of decimals of the tokens, taken from the issue events and from the documents
+// of the tokens index, so the balances of the accounts MECT are converted with the decimals of their token
+type tokenDecimalsCache struct {
+	mutDecimals sync.RWMutex
+	decimals    map[string]uint64
+}
+
+func newTokenDecimalsCache() *tokenDecimalsCache {
+	return &tokenDecimalsCache{
+		decimals: make(map[string]uint64),
+	}
+}
+
+// addFromIssues will add the number of decimals of the issued tokens. The transfer of the ownership of a token is
+// processed as an issue event, but it does not carry the number of decimals
+func (tdc *tokenDecimalsCache) addFromIssues(tokensInfo []*data.TokenInfo) {
+	tdc.mutDecimals.Lock()
+	defer tdc.mutDecimals.Unlock()
+
+	for _, tokenInfo := range tokensInfo {
+		if tokenInfo.TransferOwnership {
+			continue
+		}
+
+		tdc.add(tokenInfo.Token, tokenInfo.NumDecimals)
+	}
+}
+
+// addFromResponse will add the number of decimals of the tokens found in the tokens index. A token document without
+// type was not written by an issue event, so its number of decimals is not known
+func (tdc *tokenDecimalsCache) addFromResponse(res *data.ResponseTokens) {
+	if res == nil {
+		return
+	}
+
+	tdc.mutDecimals.Lock()
+	defer tdc.mutDecimals.Unlock()
+
+	for _, tokenData := range res.Docs {
+		if !tokenData.Found || tokenData.Source.Type == "" {
+			continue
+		}
+
+		tdc.add(tokenData.ID, tokenData.Source.NumDecimals)
+	}
+}
+
+func (tdc *tokenDecimalsCache) add(token string, numDecimals uint64) {
+	_, found := tdc.decimals[token]
+	if !found && len(tdc.decimals) >= maxCachedTokensDecimals {
+		tdc.decimals = make(map[string]uint64)
+	}
+
+	tdc.decimals[token] = numDecimals
+}
+
+// getForAccountsMECT returns the number of decimals of the tokens of the provided accounts, for the tokens found in
+// the cache
+func (tdc *tokenDecimalsCache) getForAccountsMECT(accountsMECT map[string]*data.AccountInfo) map[string]uint64 {
+	tdc.mutDecimals.RLock()
+	defer tdc.mutDecimals.RUnlock()
+
+	tokensDecimals := make(map[string]uint64)
+	for _, accountMECT := range accountsMECT {
+		numDecimals, found := tdc.decimals[accountMECT.TokenName]
+		if found {
+			tokensDecimals[accountMECT.TokenName] = numDecimals
+		}
+	}
+
+	return tokensDecimals
+}
+
+// getForTokens returns the number of decimals of the provided tokens, for the tokens found in the cache
+func (tdc *tokenDecimalsCache) getForTokens(tokens []string) map[string]uint64 {
+	tdc.mutDecimals.RLock()
+	defer tdc.mutDecimals.RUnlock()
+
+	tokensDecimals := make(map[string]uint64)
+	for _, token := range tokens {
+		numDecimals, found := tdc.decimals[token]
+		if found {
+			tokensDecimals[token] = numDecimals
+		}
+	}
+
+	return tokensDecimals
+}
+
+// getMissing returns, without duplicates, the provided tokens whose number of decimals is not in the cache
+func (tdc *tokenDecimalsCache) getMissing(tokens []string) []string {
+	tdc.mutDecimals.RLock()
+	defer tdc.mutDecimals.RUnlock()
+
+	missing := make([]string, 0)
+	added := make(map[string]struct{})
+	for _, token := range tokens {
+		_, found := tdc.decimals[token]
+		_, isAdded := added[token]
+		if found || isAdded {
+			continue
+		}
+
+		added[token] = struct{}{}
+		missing = append(missing, token)
+	}
+
+	return missing
+}
@@ -0,0 +1,65 @@
+package process
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/ME-MotherEarth/me-elastic-indexer/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenDecimalsCache_ShouldKeepTheDecimalsOfIssuesAndFoundTokens(t *testing.T) {
+	t.Parallel()
+
+	tdc := newTokenDecimalsCache()
+	tdc.addFromIssues([]*data.TokenInfo{
+		{Token: "ISS-abcd", NumDecimals: 6},
+		{Token: "OWN-abcd", TransferOwnership: true},
+	})
+	tdc.addFromResponse(&data.ResponseTokens{
+		Docs: []data.ResponseTokenDB{
+			{Found: true, ID: "TKN-abcd", Source: data.SourceToken{Type: "FungibleMECT", NumDecimals: 2}},
+			{Found: true, ID: "NFT-abcd", Source: data.SourceToken{Type: "NonFungibleMECT"}},
+			{Found: true, ID: "ROL-abcd"},
+			{Found: false, ID: "MIS-abcd"},
+		},
+	})
+	tdc.addFromResponse(nil)
+
+	accountsMECT := map[string]*data.AccountInfo{}
+	for _, token := range []string{"ISS-abcd", "OWN-abcd", "TKN-abcd", "NFT-abcd", "ROL-abcd", "MIS-abcd"} {
+		accountsMECT["addr-"+token] = &data.AccountInfo{TokenName: token}
+	}
+
+	require.Equal(t, map[string]uint64{
+		"ISS-abcd": 6,
+		"TKN-abcd": 2,
+		"NFT-abcd": 0,
+	}, tdc.getForAccountsMECT(accountsMECT))
+}
+
+func TestTokenDecimalsCache_ShouldBeEmptiedWhenFull(t *testing.T) {
+	t.Parallel()
+
+	tdc := newTokenDecimalsCache()
+	for i := 0; i < maxCachedTokensDecimals; i++ {
+		tdc.add(strconv.Itoa(i), 1)
+	}
+	tdc.add("0", 2)
+	require.Len(t, tdc.decimals, maxCachedTokensDecimals)
+
+	tdc.add("NEW-abcd", 3)
+	require.Equal(t, map[string]uint64{"NEW-abcd": 3}, tdc.decimals)
+}
+
+func TestTokenDecimalsCache_GetForTokensAndMissing(t *testing.T) {
+	t.Parallel()
+
+	tdc := newTokenDecimalsCache()
+	tdc.add("TKN-abcd", 2)
+	tdc.add("NFT-abcd", 0)
+
+	tokens := []string{"TKN-abcd", "MIS-abcd", "NFT-abcd", "MIS-abcd", "OTH-abcd"}
+	require.Equal(t, map[string]uint64{"TKN-abcd": 2, "NFT-abcd": 0}, tdc.getForTokens(tokens))
+	require.Equal(t, []string{"MIS-abcd", "OTH-abcd"}, tdc.getMissing(tokens))
+}
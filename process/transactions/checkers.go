@@ -39,6 +39,9 @@ func checkTxsProcessorArg(args *ArgsTransactionProcessor) error {
 	if check.IfNil(args.TxFeeCalculator) {
 		return elasticIndexer.ErrNilTransactionFeeCalculator
 	}
+	if check.IfNil(args.ABIDecoder) {
+		return elasticIndexer.ErrNilABIDecoder
+	}
 
 	return nil
 }
@@ -23,6 +23,7 @@ func createMockArgs() *ArgsTransactionProcessor {
 		ShardCoordinator:       &mock.ShardCoordinatorMock{},
 		Hasher:                 &mock.HasherMock{},
 		Marshalizer:            &mock.MarshalizerMock{},
+		ABIDecoder:             &mock.ABIDecoderStub{},
 		IsInImportMode:         false,
 	}
 }
@@ -53,6 +54,15 @@ func TestNewTransactionsProcessor(t *testing.T) {
 			},
 			exErr: elasticIndexer.ErrNilTransactionFeeCalculator,
 		},
+		{
+			name: "NilABIDecoder",
+			args: func() *ArgsTransactionProcessor {
+				args := createMockArgs()
+				args.ABIDecoder = nil
+				return args
+			},
+			exErr: elasticIndexer.ErrNilABIDecoder,
+		},
 		{
 			name: "NilShardCoordinator",
 			args: func() *ArgsTransactionProcessor {
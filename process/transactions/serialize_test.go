@@ -29,17 +29,15 @@ func TestSerializeScResults(t *testing.T) {
 	}
 	scrs := []*data.ScResult{scResult1, scResult2}
 
-	buffSlice := data.NewBufferSlice(data.DefaultMaxBulkSize)
-	err := (&txsDatabaseProcessor{}).SerializeScResults(scrs, buffSlice, "transactions")
+	docs := data.NewDocumentsSlice()
+	err := (&txsDatabaseProcessor{}).SerializeScResults(scrs, docs, "transactions")
 	require.Nil(t, err)
-	require.Equal(t, 1, len(buffSlice.Buffers()))
-
-	expectedRes := `{ "index" : { "_index": "transactions", "_id" : "hash1" } }
-{"nonce":1,"gasLimit":50,"gasPrice":10,"value":"","sender":"","receiver":"","senderShard":0,"receiverShard":1,"prevTxHash":"","originalTxHash":"","callType":"","timestamp":0}
-{ "index" : { "_index": "transactions", "_id" : "hash2" } }
-{"nonce":2,"gasLimit":50,"gasPrice":10,"value":"","sender":"","receiver":"","senderShard":2,"receiverShard":3,"prevTxHash":"","originalTxHash":"","callType":"","timestamp":0}
-`
-	require.Equal(t, expectedRes, buffSlice.Buffers()[0].String())
+
+	expectedDocs := []*data.Document{
+		{Index: "transactions", ID: "hash1", Action: data.ActionIndex, Body: scResult1},
+		{Index: "transactions", ID: "hash2", Action: data.ActionIndex, Body: scResult2},
+	}
+	require.Equal(t, expectedDocs, docs.Documents())
 }
 
 func TestSerializeReceipts(t *testing.T) {
@@ -58,71 +56,96 @@ func TestSerializeReceipts(t *testing.T) {
 
 	recs := []*data.Receipt{rec1, rec2}
 
-	buffSlice := data.NewBufferSlice(data.DefaultMaxBulkSize)
-	err := (&txsDatabaseProcessor{}).SerializeReceipts(recs, buffSlice, "receipts")
+	docs := data.NewDocumentsSlice()
+	err := (&txsDatabaseProcessor{}).SerializeReceipts(recs, docs, "receipts")
 	require.Nil(t, err)
-	require.Equal(t, 1, len(buffSlice.Buffers()))
-
-	expectedRes := `{ "index" : { "_index": "receipts", "_id" : "recHash1" } }
-{"value":"","sender":"sender1","txHash":"txHash1","timestamp":0}
-{ "index" : { "_index": "receipts", "_id" : "recHash2" } }
-{"value":"","sender":"sender2","txHash":"txHash2","timestamp":0}
-`
-	require.Equal(t, expectedRes, buffSlice.Buffers()[0].String())
+
+	expectedDocs := []*data.Document{
+		{Index: "receipts", ID: "recHash1", Action: data.ActionIndex, Body: rec1},
+		{Index: "receipts", ID: "recHash2", Action: data.ActionIndex, Body: rec2},
+	}
+	require.Equal(t, expectedDocs, docs.Documents())
 }
 
 func TestSerializeTransactionsIntraShardTx(t *testing.T) {
 	t.Parallel()
 
-	buffSlice := data.NewBufferSlice(data.DefaultMaxBulkSize)
-	err := (&txsDatabaseProcessor{}).SerializeTransactions([]*data.Transaction{{
+	tx := &data.Transaction{
 		Hash:                 "txHash",
 		SmartContractResults: []*data.ScResult{{}},
-	}}, map[string]string{}, 0, buffSlice, "transactions")
+	}
+	docs := data.NewDocumentsSlice()
+	err := (&txsDatabaseProcessor{}).SerializeTransactions([]*data.Transaction{tx}, map[string]string{}, 0, docs, "transactions")
 	require.Nil(t, err)
 
-	expectedBuff := `{ "index" : { "_index":"transactions", "_id" : "txHash" } }
-{"miniBlockHash":"","nonce":0,"round":0,"value":"","receiver":"","sender":"","receiverShard":0,"senderShard":0,"gasPrice":0,"gasLimit":0,"gasUsed":0,"fee":"","data":null,"signature":"","timestamp":0,"status":"","searchOrder":0}
-`
-	require.Equal(t, expectedBuff, buffSlice.Buffers()[0].String())
+	expectedDocs := []*data.Document{
+		{Index: "transactions", ID: "txHash", Action: data.ActionIndex, Body: tx},
+	}
+	require.Equal(t, expectedDocs, docs.Documents())
 }
 
 func TestSerializeTransactionCrossShardTxSource(t *testing.T) {
 	t.Parallel()
 
-	buffSlice := data.NewBufferSlice(data.DefaultMaxBulkSize)
-	err := (&txsDatabaseProcessor{}).SerializeTransactions([]*data.Transaction{{
+	tx := &data.Transaction{
 		Hash:                 "txHash",
 		SenderShard:          0,
 		ReceiverShard:        1,
 		SmartContractResults: []*data.ScResult{{}},
 		Version:              1,
-	}}, map[string]string{}, 0, buffSlice, "transactions")
+	}
+	docs := data.NewDocumentsSlice()
+	err := (&txsDatabaseProcessor{}).SerializeTransactions([]*data.Transaction{tx}, map[string]string{}, 0, docs, "transactions")
 	require.Nil(t, err)
 
-	expectedBuff := `{"update":{ "_index":"transactions", "_id":"txHash"}}
-{"script":{"source":"return"},"upsert":{"miniBlockHash":"","nonce":0,"round":0,"value":"","receiver":"","sender":"","receiverShard":1,"senderShard":0,"gasPrice":0,"gasLimit":0,"gasUsed":0,"fee":"","data":null,"signature":"","timestamp":0,"status":"","searchOrder":0,"version":1}}
-`
-	require.Equal(t, expectedBuff, buffSlice.Buffers()[0].String())
+	expectedDocs := []*data.Document{
+		{Index: "transactions", ID: "txHash", Action: data.ActionCreate, Body: tx},
+	}
+	require.Equal(t, expectedDocs, docs.Documents())
 }
 
 func TestSerializeTransactionsCrossShardTxDestination(t *testing.T) {
 	t.Parallel()
 
-	buffSlice := data.NewBufferSlice(data.DefaultMaxBulkSize)
-	err := (&txsDatabaseProcessor{}).SerializeTransactions([]*data.Transaction{{
+	tx := &data.Transaction{
 		Hash:                 "txHash",
 		SenderShard:          1,
 		ReceiverShard:        0,
 		SmartContractResults: []*data.ScResult{{}},
 		Version:              1,
-	}}, map[string]string{}, 0, buffSlice, "transactions")
+	}
+	docs := data.NewDocumentsSlice()
+	err := (&txsDatabaseProcessor{}).SerializeTransactions([]*data.Transaction{tx}, map[string]string{}, 0, docs, "transactions")
 	require.Nil(t, err)
 
-	expectedBuff := `{ "index" : { "_index":"transactions", "_id" : "txHash" } }
-{"miniBlockHash":"","nonce":0,"round":0,"value":"","receiver":"","sender":"","receiverShard":0,"senderShard":1,"gasPrice":0,"gasLimit":0,"gasUsed":0,"fee":"","data":null,"signature":"","timestamp":0,"status":"","searchOrder":0,"version":1}
-`
-	require.Equal(t, expectedBuff, buffSlice.Buffers()[0].String())
+	expectedDocs := []*data.Document{
+		{Index: "transactions", ID: "txHash", Action: data.ActionIndex, Body: tx},
+	}
+	require.Equal(t, expectedDocs, docs.Documents())
+}
+
+func TestSerializeTransactionsIntraShardNFTTransferShouldKeepStatus(t *testing.T) {
+	t.Parallel()
+
+	tx := &data.Transaction{
+		Hash: "txHash",
+		Data: []byte("MECTNFTTransfer@4e46542d616263646566@01@01@72656365697665724164647265737321"),
+	}
+	docs := data.NewDocumentsSlice()
+	err := (&txsDatabaseProcessor{}).SerializeTransactions([]*data.Transaction{tx}, map[string]string{"txHash2": "fail"}, 0, docs, "transactions")
+	require.Nil(t, err)
+
+	expectedDocs := []*data.Document{
+		{Index: "transactions", ID: "txHash", Action: data.ActionIndex, Body: tx, KeepFields: []string{"status"}},
+		{
+			Index:  "transactions",
+			ID:     "txHash2",
+			Action: data.ActionUpsert,
+			Body:   &data.Transaction{Status: "fail"},
+			Fields: map[string]interface{}{"status": "fail"},
+		},
+	}
+	require.Equal(t, expectedDocs, docs.Documents())
 }
 
 func TestTxsDatabaseProcessor_SerializeTransactionWithRefund(t *testing.T) {
@@ -143,14 +166,29 @@ func TestTxsDatabaseProcessor_SerializeTransactionWithRefund(t *testing.T) {
 		},
 	}
 
-	buffSlice := data.NewBufferSlice(data.DefaultMaxBulkSize)
-	err := (&txsDatabaseProcessor{
+	txProc := &txsDatabaseProcessor{
 		txFeeCalculator: &mock.EconomicsHandlerMock{},
-	}).SerializeTransactionWithRefund(txs, txHashRefund, buffSlice, "transactions")
+	}
+	docs := data.NewDocumentsSlice()
+	err := txProc.SerializeTransactionWithRefund(txs, txHashRefund, docs, "transactions")
+	require.Nil(t, err)
+	err = txProc.SerializeTransactionWithRefund(txs, txHashRefund, docs, "operations")
 	require.Nil(t, err)
 
-	expectedBuff := `{ "index" : { "_index": "transactions", "_id" : "txHash" } }
-{"miniBlockHash":"","nonce":0,"round":0,"value":"","receiver":"receiver","sender":"sender","receiverShard":0,"senderShard":0,"gasPrice":1000000000,"gasLimit":150000000,"gasUsed":139832352,"fee":"1447823520000000","data":null,"signature":"","timestamp":0,"status":"","searchOrder":0}
-`
-	require.Equal(t, expectedBuff, buffSlice.Buffers()[0].String())
+	expectedTx := &data.Transaction{
+		Sender:   "sender",
+		Receiver: "receiver",
+		GasLimit: 150000000,
+		GasPrice: 1000000000,
+		GasUsed:  139832352,
+		Fee:      "1447823520000000",
+	}
+	expectedOperation := *expectedTx
+	expectedOperation.Type = "normal"
+	expectedDocs := []*data.Document{
+		{Index: "transactions", ID: "txHash", Action: data.ActionIndex, Body: expectedTx},
+		{Index: "operations", ID: "txHash", Action: data.ActionIndex, Body: &expectedOperation},
+	}
+	require.Equal(t, expectedDocs, docs.Documents())
+	require.Equal(t, "", txs["txHash"].Type)
 }
@@ -23,6 +23,7 @@ type smartContractResultsProcessor struct {
 	hasher           hashing.Hasher
 	marshalizer      marshal.Marshalizer
 	dataFieldParser  DataFieldParser
+	abiDecoder       indexer.ABIDecoder
 }
 
 func newSmartContractResultsProcessor(
@@ -31,6 +32,7 @@ func newSmartContractResultsProcessor(
 	marshalzier marshal.Marshalizer,
 	hasher hashing.Hasher,
 	dataFieldParser DataFieldParser,
+	abiDecoder indexer.ABIDecoder,
 ) *smartContractResultsProcessor {
 	return &smartContractResultsProcessor{
 		pubKeyConverter:  pubKeyConverter,
@@ -38,6 +40,7 @@ func newSmartContractResultsProcessor(
 		marshalizer:      marshalzier,
 		hasher:           hasher,
 		dataFieldParser:  dataFieldParser,
+		abiDecoder:       abiDecoder,
 	}
 }
 
@@ -168,6 +171,7 @@ func (proc *smartContractResultsProcessor) prepareSmartContractResult(
 		ReceiverShard:      receiverShard,
 		Operation:          res.Operation,
 		Function:           res.Function,
+		Decoded:            proc.abiDecoder.DecodeCall(calledContract(scr.RcvAddr, res), res.Function, scr.Data),
 		MECTValues:         res.MECTValues,
 		Tokens:             res.Tokens,
 		Receivers:          datafield.EncodeBytesSlice(proc.pubKeyConverter.Encode, res.Receivers),
@@ -29,7 +29,7 @@ func TestPrepareSmartContractResult(t *testing.T) {
 
 	parser := createDataFieldParserMock()
 	pubKeyConverter := &mock.PubkeyConverterMock{}
-	scrsProc := newSmartContractResultsProcessor(pubKeyConverter, &mock.ShardCoordinatorMock{}, &mock.MarshalizerMock{}, &mock.HasherMock{}, parser)
+	scrsProc := newSmartContractResultsProcessor(pubKeyConverter, &mock.ShardCoordinatorMock{}, &mock.MarshalizerMock{}, &mock.HasherMock{}, parser, &mock.ABIDecoderStub{})
 
 	nonce := uint64(10)
 	txHash := []byte("txHash")
@@ -75,7 +75,7 @@ func TestAddScrsReceiverToAlteredAccounts_ShouldWork(t *testing.T) {
 	t.Parallel()
 
 	parser := createDataFieldParserMock()
-	scrsProc := newSmartContractResultsProcessor(&mock.PubkeyConverterMock{}, &mock.ShardCoordinatorMock{}, &mock.MarshalizerMock{}, &mock.HasherMock{}, parser)
+	scrsProc := newSmartContractResultsProcessor(&mock.PubkeyConverterMock{}, &mock.ShardCoordinatorMock{}, &mock.MarshalizerMock{}, &mock.HasherMock{}, parser, &mock.ABIDecoderStub{})
 
 	alteredAddress := data.NewAlteredAccounts()
 	scrs := []*data.ScResult{
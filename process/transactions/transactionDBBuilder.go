@@ -23,6 +23,7 @@ type dbTransactionBuilder struct {
 	shardCoordinator       indexer.ShardCoordinator
 	txFeeCalculator        indexer.FeesProcessorHandler
 	dataFieldParser        DataFieldParser
+	abiDecoder             indexer.ABIDecoder
 }
 
 func newTransactionDBBuilder(
@@ -30,12 +31,14 @@ func newTransactionDBBuilder(
 	shardCoordinator indexer.ShardCoordinator,
 	txFeeCalculator indexer.FeesProcessorHandler,
 	dataFieldParser DataFieldParser,
+	abiDecoder indexer.ABIDecoder,
 ) *dbTransactionBuilder {
 	return &dbTransactionBuilder{
 		addressPubkeyConverter: addressPubkeyConverter,
 		shardCoordinator:       shardCoordinator,
 		txFeeCalculator:        txFeeCalculator,
 		dataFieldParser:        dataFieldParser,
+		abiDecoder:             abiDecoder,
 	}
 }
 
@@ -79,6 +82,7 @@ func (dtb *dbTransactionBuilder) prepareTransaction(
 		IsScCall:             isScCall,
 		Operation:            res.Operation,
 		Function:             res.Function,
+		Decoded:              dtb.abiDecoder.DecodeCall(calledContract(tx.RcvAddr, res), res.Function, tx.Data),
 		MECTValues:           res.MECTValues,
 		Tokens:               res.Tokens,
 		Receivers:            datafield.EncodeBytesSlice(dtb.addressPubkeyConverter.Encode, res.Receivers),
@@ -88,6 +92,16 @@ func (dtb *dbTransactionBuilder) prepareTransaction(
 	}
 }
 
+// calledContract returns the receiver of the call, which is written in the data field when the tokens are transferred
+// with a built-in function sent to the sender itself
+func calledContract(receiver []byte, res *datafield.ResponseParseData) []byte {
+	if len(res.Receivers) > 0 {
+		return res.Receivers[0]
+	}
+
+	return receiver
+}
+
 func (dtb *dbTransactionBuilder) prepareRewardTransaction(
 	rTx *rewardTx.RewardTx,
 	txHash []byte,
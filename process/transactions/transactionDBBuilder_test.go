@@ -14,6 +14,7 @@ import (
 	"github.com/ME-MotherEarth/me-core/data/transaction"
 	"github.com/ME-MotherEarth/me-elastic-indexer/data"
 	"github.com/ME-MotherEarth/me-elastic-indexer/mock"
+	datafield "github.com/ME-MotherEarth/me-vm-common/parsers/dataField"
 	"github.com/stretchr/testify/require"
 )
 
@@ -30,6 +31,7 @@ func createCommonProcessor() dbTransactionBuilder {
 		},
 		shardCoordinator: &mock.ShardCoordinatorMock{},
 		dataFieldParser:  createDataFieldParserMock(),
+		abiDecoder:       &mock.ABIDecoderStub{},
 	}
 }
 
@@ -120,3 +122,13 @@ func TestGetTransactionByType_RewardTx(t *testing.T) {
 
 	require.Equal(t, expectedTx, resultTx)
 }
+
+func TestCalledContract(t *testing.T) {
+	t.Parallel()
+
+	receiver := []byte("receiver")
+	require.Equal(t, receiver, calledContract(receiver, &datafield.ResponseParseData{}))
+
+	contract := []byte("contract")
+	require.Equal(t, contract, calledContract(receiver, &datafield.ResponseParseData{Receivers: [][]byte{contract}}))
+}
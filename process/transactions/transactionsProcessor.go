@@ -19,13 +19,14 @@ import (
 var log = logger.GetOrCreate("indexer/process/transactions")
 
 // ArgsTransactionProcessor holds all dependencies required by the txsDatabaseProcessor  in order to create
-// new instances
+// new instances. The ABIDecoder decodes the arguments of the calls of the smart contracts that have an ABI
 type ArgsTransactionProcessor struct {
 	AddressPubkeyConverter core.PubkeyConverter
 	TxFeeCalculator        indexer.FeesProcessorHandler
 	ShardCoordinator       indexer.ShardCoordinator
 	Hasher                 hashing.Hasher
 	Marshalizer            marshal.Marshalizer
+	ABIDecoder             indexer.ABIDecoder
 	IsInImportMode         bool
 }
 
@@ -55,9 +56,9 @@ func NewTransactionsProcessor(args *ArgsTransactionProcessor) (*txsDatabaseProce
 	}
 
 	selfShardID := args.ShardCoordinator.SelfId()
-	txBuilder := newTransactionDBBuilder(args.AddressPubkeyConverter, args.ShardCoordinator, args.TxFeeCalculator, operationsDataParser)
+	txBuilder := newTransactionDBBuilder(args.AddressPubkeyConverter, args.ShardCoordinator, args.TxFeeCalculator, operationsDataParser, args.ABIDecoder)
 	txsDBGrouper := newTxsGrouper(txBuilder, args.IsInImportMode, selfShardID, args.Hasher, args.Marshalizer)
-	scrProc := newSmartContractResultsProcessor(args.AddressPubkeyConverter, args.ShardCoordinator, args.Marshalizer, args.Hasher, operationsDataParser)
+	scrProc := newSmartContractResultsProcessor(args.AddressPubkeyConverter, args.ShardCoordinator, args.Marshalizer, args.Hasher, operationsDataParser, args.ABIDecoder)
 	scrsDataToTxs := newScrsDataToTransactions(args.TxFeeCalculator)
 
 	if args.IsInImportMode {
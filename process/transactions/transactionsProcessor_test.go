@@ -28,6 +28,7 @@ func createMockArgsTxsDBProc() *ArgsTransactionProcessor {
 		ShardCoordinator:       &mock.ShardCoordinatorMock{},
 		Hasher:                 &mock.HasherMock{},
 		Marshalizer:            &mock.MarshalizerMock{},
+		ABIDecoder:             &mock.ABIDecoderStub{},
 		IsInImportMode:         false,
 	}
 	return args
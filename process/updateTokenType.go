@@ -1,8 +1,6 @@
 package process
 
 import (
-	"encoding/json"
-	"fmt"
 	"time"
 
 	"github.com/ME-MotherEarth/me-core/core"
@@ -10,22 +8,28 @@ import (
 	"github.com/ME-MotherEarth/me-elastic-indexer/data"
 )
 
-func (ei *elasticProcessor) indexTokens(tokensData []*data.TokenInfo, updateNFTData []*data.NFTDataUpdate, buffSlice *data.BufferSlice) error {
+func (ei *elasticProcessor) indexTokens(tokensData []*data.TokenInfo, updateNFTData []*data.NFTDataUpdate, docs *data.DocumentsSlice) error {
 	if !ei.isIndexEnabled(elasticIndexer.TokensIndex) {
 		return nil
 	}
 
-	err := ei.logsAndEventsProc.SerializeTokens(tokensData, updateNFTData, buffSlice, elasticIndexer.TokensIndex)
+	err := ei.logsAndEventsProc.SerializeTokens(tokensData, updateNFTData, docs, elasticIndexer.TokensIndex)
 	if err != nil {
 		return err
 	}
 
-	err = ei.addTokenType(tokensData, elasticIndexer.AccountsMECTIndex)
+	rejected := &data.RejectedDocumentsError{}
+	err = rejected.Add(ei.addTokenType(tokensData, elasticIndexer.AccountsMECTIndex))
 	if err != nil {
 		return err
 	}
 
-	return ei.addTokenType(tokensData, elasticIndexer.TokensIndex)
+	err = rejected.Add(ei.addTokenType(tokensData, elasticIndexer.TokensIndex))
+	if err != nil {
+		return err
+	}
+
+	return rejected.AsError()
 }
 
 func (ei *elasticProcessor) addTokenType(tokensData []*data.TokenInfo, index string) error {
@@ -37,43 +41,32 @@ func (ei *elasticProcessor) addTokenType(tokensData []*data.TokenInfo, index str
 		log.Debug("elasticProcessor.addTokenType", "index", index, "duration", time.Since(startTime))
 	}(time.Now())
 
+	rejected := &data.RejectedDocumentsError{}
 	for _, td := range tokensData {
 		if td.Type == core.FungibleMECT {
 			continue
 		}
 
-		handlerFunc := func(responseBytes []byte) error {
-			responseScroll := &data.ResponseScroll{}
-			err := json.Unmarshal(responseBytes, responseScroll)
-			if err != nil {
-				return err
-			}
-
-			ids := make([]string, 0, len(responseScroll.Hits.Hits))
-			for _, res := range responseScroll.Hits.Hits {
-				ids = append(ids, res.ID)
-			}
-
-			buffSlice := data.NewBufferSlice(ei.bulkRequestMaxSize)
-			err = ei.accountsProc.SerializeTypeForProvidedIDs(ids, td.Type, buffSlice, index)
+		handlerFunc := func(ids []string) error {
+			docs := data.NewDocumentsSlice()
+			err := ei.accountsProc.SerializeTypeForProvidedIDs(ids, td.Type, docs, index)
 			if err != nil {
 				return err
 			}
 
-			return ei.doBulkRequests(index, buffSlice.Buffers())
+			return rejected.Add(ei.sink.WriteDocuments(docs.Documents()))
 		}
 
-		query := fmt.Sprintf(`{"query": {"bool": {"must": [{"match": {"token": {"query": "%s","operator": "AND"}}}],"must_not":[{"exists": {"field": "type"}}]}}}`, td.Token)
-		resultsCount, err := ei.elasticClient.DoCountRequest(index, []byte(query))
-		if err != nil || resultsCount == 0 {
-			return err
+		// a nil value matches the documents that do not have the type yet
+		fields := map[string]interface{}{
+			"token": td.Token,
+			"type":  nil,
 		}
-
-		err = ei.elasticClient.DoScrollRequest(index, []byte(query), false, handlerFunc)
+		err := ei.sink.IterateMatchingIDs(index, fields, handlerFunc)
 		if err != nil {
 			return err
 		}
 	}
 
-	return nil
+	return rejected.AsError()
 }
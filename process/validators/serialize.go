@@ -1,8 +1,6 @@
 package validators
 
 import (
-	"bytes"
-	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -10,48 +8,39 @@ import (
 )
 
 // SerializeValidatorsPubKeys will serialize validators public keys
-func (vp *validatorsProcessor) SerializeValidatorsPubKeys(validatorsPubKeys *data.ValidatorsPublicKeys) (*bytes.Buffer, error) {
-	marshalizedValidatorPubKeys, err := json.Marshal(validatorsPubKeys)
-	if err != nil {
-		return nil, err
-	}
-
-	buffer := &bytes.Buffer{}
-	buffer.Grow(len(marshalizedValidatorPubKeys))
-	_, err = buffer.Write(marshalizedValidatorPubKeys)
-	if err != nil {
-		return nil, err
-	}
-
-	return buffer, nil
+func (vp *validatorsProcessor) SerializeValidatorsPubKeys(
+	shardID uint32,
+	epoch uint32,
+	validatorsPubKeys *data.ValidatorsPublicKeys,
+	docs *data.DocumentsSlice,
+	index string,
+) {
+	docs.Add(&data.Document{
+		Index:  index,
+		ID:     fmt.Sprintf("%d_%d", shardID, epoch),
+		Action: data.ActionIndex,
+		Body:   validatorsPubKeys,
+	})
 }
 
 // SerializeValidatorsRating will serialize validators rating
 func (vp *validatorsProcessor) SerializeValidatorsRating(
-	index string,
+	ratingIndex string,
 	validatorsRatingInfo []*data.ValidatorRatingInfo,
-) ([]*bytes.Buffer, error) {
-	buffSlice := data.NewBufferSlice(vp.bulkSizeMaxSize)
-
+	docs *data.DocumentsSlice,
+	index string,
+) {
 	// inside me-motherearth, the index is "shardID_epoch" so in order to keep backwards compatibility some adjustments have to be made.
 	// shardID from index name has to be removed because it is sufficient to have document id = blsKey_epoch
-	indexWithoutShardID := removeShardIDFromIndex(index)
+	indexWithoutShardID := removeShardIDFromIndex(ratingIndex)
 	for _, valRatingInfo := range validatorsRatingInfo {
-		id := fmt.Sprintf("%s_%s", valRatingInfo.PublicKey, indexWithoutShardID)
-		meta := []byte(fmt.Sprintf(`{ "index" : { "_id" : "%s" } }%s`, id, "\n"))
-
-		serializedData, err := json.Marshal(valRatingInfo)
-		if err != nil {
-			continue
-		}
-
-		err = buffSlice.PutData(meta, serializedData)
-		if err != nil {
-			return nil, err
-		}
+		docs.Add(&data.Document{
+			Index:  index,
+			ID:     fmt.Sprintf("%s_%s", valRatingInfo.PublicKey, indexWithoutShardID),
+			Action: data.ActionIndex,
+			Body:   valRatingInfo,
+		})
 	}
-
-	return buffSlice.Buffers(), nil
 }
 
 func removeShardIDFromIndex(index string) string {
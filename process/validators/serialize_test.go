@@ -13,17 +13,19 @@ func TestValidatorsProcessor_SerializeValidatorsPubKeys(t *testing.T) {
 	validatorsPubKeys := &data.ValidatorsPublicKeys{
 		PublicKeys: []string{"bls1", "bls2"},
 	}
-	buff, err := (&validatorsProcessor{}).SerializeValidatorsPubKeys(validatorsPubKeys)
-	require.Nil(t, err)
+	docs := data.NewDocumentsSlice()
+	(&validatorsProcessor{}).SerializeValidatorsPubKeys(1, 10, validatorsPubKeys, docs, "validators")
 
-	expected := `{"publicKeys":["bls1","bls2"]}`
-	require.Equal(t, expected, buff.String())
+	expectedDocs := []*data.Document{
+		{Index: "validators", ID: "1_10", Action: data.ActionIndex, Body: validatorsPubKeys},
+	}
+	require.Equal(t, expectedDocs, docs.Documents())
 }
 
 func TestValidatorsProcessor_SerializeValidatorsRating(t *testing.T) {
 	t.Parallel()
 
-	buff, err := (&validatorsProcessor{}).SerializeValidatorsRating("0", []*data.ValidatorRatingInfo{
+	ratingInfo := []*data.ValidatorRatingInfo{
 		{
 			PublicKey: "bls1",
 			Rating:    50.1,
@@ -32,14 +34,15 @@ func TestValidatorsProcessor_SerializeValidatorsRating(t *testing.T) {
 			PublicKey: "bls3",
 			Rating:    50.2,
 		},
-	})
-	require.Nil(t, err)
-	expected := `{ "index" : { "_id" : "bls1_0" } }
-{"rating":50.1}
-{ "index" : { "_id" : "bls3_0" } }
-{"rating":50.2}
-`
-	require.Equal(t, expected, buff[0].String())
+	}
+	docs := data.NewDocumentsSlice()
+	(&validatorsProcessor{}).SerializeValidatorsRating("1_0", ratingInfo, docs, "rating")
+
+	expectedDocs := []*data.Document{
+		{Index: "rating", ID: "bls1_0", Action: data.ActionIndex, Body: ratingInfo[0]},
+		{Index: "rating", ID: "bls3_0", Action: data.ActionIndex, Body: ratingInfo[1]},
+	}
+	require.Equal(t, expectedDocs, docs.Documents())
 }
 
 func TestRemoveShardIDFromIndex(t *testing.T) {
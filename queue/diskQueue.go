@@ -0,0 +1,438 @@
+package queue
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	logger "github.com/ME-MotherEarth/me-logger"
+)
+
+const (
+	// LogFileName is the name of the append-only log file created in the queue directory
+	LogFileName = "queue.log"
+	// QuarantineFileName is the name of the file, placed in the queue directory, that keeps the items which cannot
+	// be replayed
+	QuarantineFileName = "quarantine.log"
+
+	// numAcksBetweenSyncs is the number of acknowledgements written in the log before it is synced on disk. An
+	// acknowledgement lost on a crash only makes its item to be replayed once more
+	numAcksBetweenSyncs = 100
+	// numAcksBetweenCompactions is the number of acknowledgements written in the log before it is rewritten to
+	// contain only the pending items
+	numAcksBetweenCompactions = 10000
+
+	recordItem     = byte(1)
+	recordAck      = byte(2)
+	headerSize     = 1 + 8
+	filePermission = 0644
+	dirPermission  = 0755
+)
+
+var log = logger.GetOrCreate("indexer/queue")
+
+// Record is an item stored in the queue together with its sequence number
+type Record struct {
+	ID   uint64
+	Data []byte
+}
+
+type diskQueue struct {
+	mutex               sync.Mutex
+	directory           string
+	file                *os.File
+	nextID              uint64
+	pendingIDs          map[uint64]struct{}
+	loaded              []*Record
+	closed              bool
+	numUnsyncedAcks     int
+	numAcksSinceCompact int
+	acksBetweenSyncs    int
+	acksBetweenCompacts int
+}
+
+// NewDiskQueue will create a new instance of diskQueue that persists items in an append-only log placed in the
+// provided directory. Items that were added and not acknowledged in a previous run can be fetched with Pending
+func NewDiskQueue(directory string) (*diskQueue, error) {
+	if directory == "" {
+		return nil, ErrEmptyDirectory
+	}
+
+	err := os.MkdirAll(directory, dirPermission)
+	if err != nil {
+		return nil, err
+	}
+
+	dq := &diskQueue{
+		directory:           directory,
+		nextID:              1,
+		pendingIDs:          make(map[uint64]struct{}),
+		acksBetweenSyncs:    numAcksBetweenSyncs,
+		acksBetweenCompacts: numAcksBetweenCompactions,
+	}
+
+	err = dq.load()
+	if err != nil {
+		return nil, err
+	}
+
+	err = dq.compact(dq.loaded)
+	if err != nil {
+		return nil, err
+	}
+
+	return dq, nil
+}
+
+func (dq *diskQueue) logFilePath() string {
+	return filepath.Join(dq.directory, LogFileName)
+}
+
+func (dq *diskQueue) load() error {
+	records, lastID, err := readPendingRecords(dq.logFilePath())
+	if err != nil {
+		return err
+	}
+
+	dq.nextID = lastID + 1
+	dq.loaded = records
+	for _, record := range records {
+		dq.pendingIDs[record.ID] = struct{}{}
+	}
+
+	log.Debug("diskQueue.load", "pending items", len(dq.loaded))
+
+	return nil
+}
+
+// readPendingRecords returns, sorted by their sequence number, the items of the provided log that were not
+// acknowledged, together with the greatest sequence number found in the log
+func readPendingRecords(logFilePath string) ([]*Record, uint64, error) {
+	file, err := os.Open(logFilePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return make([]*Record, 0), 0, nil
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	lastID := uint64(0)
+	items := make(map[uint64][]byte)
+	reader := bufio.NewReader(file)
+	for {
+		recordType, id, recordData, errRead := readRecord(reader)
+		if errRead == io.EOF {
+			break
+		}
+		if errRead != nil {
+			log.Warn("diskQueue: truncated or corrupted record, the rest of the log will be ignored",
+				"error", errRead.Error())
+			break
+		}
+
+		switch recordType {
+		case recordItem:
+			items[id] = recordData
+		case recordAck:
+			delete(items, id)
+		}
+
+		if id > lastID {
+			lastID = id
+		}
+	}
+
+	records := make([]*Record, 0, len(items))
+	for id, itemData := range items {
+		records = append(records, &Record{ID: id, Data: itemData})
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].ID < records[j].ID
+	})
+
+	return records, lastID, nil
+}
+
+// compact rewrites the log so that it only contains the provided items and opens it for appending
+func (dq *diskQueue) compact(records []*Record) error {
+	logFilePath := dq.logFilePath()
+	tmpFilePath := logFilePath + ".tmp"
+	tmpFile, err := os.OpenFile(tmpFilePath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, filePermission)
+	if err != nil {
+		return err
+	}
+
+	writer := bufio.NewWriter(tmpFile)
+	for _, record := range records {
+		_, err = writer.Write(encodeRecord(recordItem, record.ID, record.Data))
+		if err != nil {
+			_ = tmpFile.Close()
+			return err
+		}
+	}
+
+	err = writer.Flush()
+	if err != nil {
+		_ = tmpFile.Close()
+		return err
+	}
+
+	err = tmpFile.Sync()
+	if err != nil {
+		_ = tmpFile.Close()
+		return err
+	}
+
+	err = tmpFile.Close()
+	if err != nil {
+		return err
+	}
+
+	err = os.Rename(tmpFilePath, logFilePath)
+	if err != nil {
+		return err
+	}
+
+	dq.file, err = os.OpenFile(logFilePath, os.O_APPEND|os.O_WRONLY, filePermission)
+	if err != nil {
+		return err
+	}
+
+	dq.numUnsyncedAcks = 0
+	dq.numAcksSinceCompact = 0
+
+	return nil
+}
+
+// compactWhileRunning rewrites the log, while the queue is in use, so it does not grow with the acknowledged items.
+// The pending items are read back from the log, as only the ones loaded at start are kept in memory
+func (dq *diskQueue) compactWhileRunning() error {
+	err := dq.file.Sync()
+	if err != nil {
+		return err
+	}
+
+	records, _, err := readPendingRecords(dq.logFilePath())
+	if err != nil {
+		return err
+	}
+
+	err = dq.file.Close()
+	if err != nil {
+		return err
+	}
+
+	err = dq.compact(records)
+	if err != nil {
+		return err
+	}
+
+	dq.loaded = dq.pendingLoadedRecords()
+	log.Debug("diskQueue.compactWhileRunning", "pending items", len(records))
+
+	return nil
+}
+
+// Append will persist the provided data and will return the sequence number assigned to it
+func (dq *diskQueue) Append(itemData []byte) (uint64, error) {
+	if len(itemData) > MaxFrameDataSize {
+		return 0, fmt.Errorf("%w: %d bytes", ErrRecordTooLarge, len(itemData))
+	}
+
+	dq.mutex.Lock()
+	defer dq.mutex.Unlock()
+
+	if dq.closed {
+		return 0, ErrQueueClosed
+	}
+
+	id := dq.nextID
+	_, err := dq.file.Write(encodeRecord(recordItem, id, itemData))
+	if err != nil {
+		return 0, err
+	}
+
+	err = dq.file.Sync()
+	if err != nil {
+		return 0, err
+	}
+
+	dq.nextID++
+	dq.pendingIDs[id] = struct{}{}
+
+	return id, nil
+}
+
+// Ack will mark the item with the provided sequence number as processed, so it will not be replayed
+func (dq *diskQueue) Ack(id uint64) error {
+	dq.mutex.Lock()
+	defer dq.mutex.Unlock()
+
+	if dq.closed {
+		return ErrQueueClosed
+	}
+
+	_, isPending := dq.pendingIDs[id]
+	if !isPending {
+		return nil
+	}
+
+	delete(dq.pendingIDs, id)
+	if len(dq.pendingIDs) == 0 {
+		// nothing left to replay, the log can be discarded
+		err := dq.file.Truncate(0)
+		if err != nil {
+			return err
+		}
+
+		dq.loaded = nil
+		dq.numUnsyncedAcks = 0
+		dq.numAcksSinceCompact = 0
+
+		return dq.file.Sync()
+	}
+
+	_, err := dq.file.Write(encodeRecord(recordAck, id, nil))
+	if err != nil {
+		return err
+	}
+
+	dq.numAcksSinceCompact++
+	if dq.numAcksSinceCompact >= dq.acksBetweenCompacts {
+		return dq.compactWhileRunning()
+	}
+
+	dq.numUnsyncedAcks++
+	if dq.numUnsyncedAcks < dq.acksBetweenSyncs {
+		return nil
+	}
+
+	dq.numUnsyncedAcks = 0
+	return dq.file.Sync()
+}
+
+// Quarantine will move the provided item, which cannot be replayed, in the quarantine file of the queue directory
+// and will acknowledge it. The item is kept in the log if it cannot be written in the quarantine file
+func (dq *diskQueue) Quarantine(record *Record) error {
+	if record == nil {
+		return nil
+	}
+
+	dq.mutex.Lock()
+	closed := dq.closed
+	dq.mutex.Unlock()
+	if closed {
+		return ErrQueueClosed
+	}
+
+	err := appendToFile(filepath.Join(dq.directory, QuarantineFileName), encodeRecord(recordItem, record.ID, record.Data))
+	if err != nil {
+		return err
+	}
+
+	return dq.Ack(record.ID)
+}
+
+func appendToFile(filePath string, buff []byte) error {
+	file, err := os.OpenFile(filePath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, filePermission)
+	if err != nil {
+		return err
+	}
+
+	_, err = file.Write(buff)
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	err = file.Sync()
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	return file.Close()
+}
+
+// Pending returns, in the order they were added, the items left unacknowledged by a previous run
+func (dq *diskQueue) Pending() []*Record {
+	dq.mutex.Lock()
+	defer dq.mutex.Unlock()
+
+	return dq.pendingLoadedRecords()
+}
+
+func (dq *diskQueue) pendingLoadedRecords() []*Record {
+	records := make([]*Record, 0, len(dq.loaded))
+	for _, record := range dq.loaded {
+		_, isPending := dq.pendingIDs[record.ID]
+		if isPending {
+			records = append(records, record)
+		}
+	}
+
+	return records
+}
+
+// Len returns the number of items that were not acknowledged yet
+func (dq *diskQueue) Len() int {
+	dq.mutex.Lock()
+	defer dq.mutex.Unlock()
+
+	return len(dq.pendingIDs)
+}
+
+// Close will close the underlying log file
+func (dq *diskQueue) Close() error {
+	dq.mutex.Lock()
+	defer dq.mutex.Unlock()
+
+	if dq.closed {
+		return nil
+	}
+
+	dq.closed = true
+	errSync := dq.file.Sync()
+	errClose := dq.file.Close()
+	if errSync != nil {
+		return errSync
+	}
+
+	return errClose
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (dq *diskQueue) IsInterfaceNil() bool {
+	return dq == nil
+}
+
+func encodeRecord(recordType byte, id uint64, recordData []byte) []byte {
+	header := make([]byte, headerSize)
+	header[0] = recordType
+	binary.BigEndian.PutUint64(header[1:9], id)
+
+	return EncodeFrame(header, recordData)
+}
+
+func readRecord(reader io.Reader) (byte, uint64, []byte, error) {
+	header, recordData, err := ReadFrame(reader, headerSize)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+
+	recordType := header[0]
+	if recordType != recordItem && recordType != recordAck {
+		return 0, 0, nil, fmt.Errorf("%w: %d", ErrInvalidRecordType, recordType)
+	}
+
+	return recordType, binary.BigEndian.Uint64(header[1:9]), recordData, nil
+}
@@ -0,0 +1,175 @@
+package queue
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewDiskQueue_EmptyDirectoryShouldErr(t *testing.T) {
+	t.Parallel()
+
+	dq, err := NewDiskQueue("")
+	require.Nil(t, dq)
+	require.Equal(t, ErrEmptyDirectory, err)
+}
+
+func TestDiskQueue_AppendAckAndReload(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	dq, err := NewDiskQueue(dir)
+	require.NoError(t, err)
+	require.False(t, dq.IsInterfaceNil())
+	require.Empty(t, dq.Pending())
+
+	id1, err := dq.Append([]byte("item1"))
+	require.NoError(t, err)
+	id2, err := dq.Append([]byte("item2"))
+	require.NoError(t, err)
+	id3, err := dq.Append([]byte("item3"))
+	require.NoError(t, err)
+	require.Equal(t, 3, dq.Len())
+
+	require.NoError(t, dq.Ack(id2))
+	require.NoError(t, dq.Close())
+
+	dq, err = NewDiskQueue(dir)
+	require.NoError(t, err)
+
+	pending := dq.Pending()
+	require.Equal(t, []*Record{
+		{ID: id1, Data: []byte("item1")},
+		{ID: id3, Data: []byte("item3")},
+	}, pending)
+
+	id4, err := dq.Append([]byte("item4"))
+	require.NoError(t, err)
+	require.Greater(t, id4, id3)
+
+	require.NoError(t, dq.Ack(id1))
+	require.Equal(t, 2, dq.Len())
+	require.Equal(t, []*Record{{ID: id3, Data: []byte("item3")}}, dq.Pending())
+	require.NoError(t, dq.Close())
+}
+
+func TestDiskQueue_AllItemsAcknowledgedShouldTruncateLog(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	dq, _ := NewDiskQueue(dir)
+
+	id, _ := dq.Append([]byte("item"))
+	require.NoError(t, dq.Ack(id))
+	require.NoError(t, dq.Close())
+
+	info, err := os.Stat(filepath.Join(dir, LogFileName))
+	require.NoError(t, err)
+	require.Equal(t, int64(0), info.Size())
+
+	dq, _ = NewDiskQueue(dir)
+	require.Empty(t, dq.Pending())
+	require.NoError(t, dq.Close())
+}
+
+func TestDiskQueue_CorruptedTailShouldBeIgnored(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	dq, _ := NewDiskQueue(dir)
+	id, _ := dq.Append([]byte("item"))
+	_, _ = dq.Append([]byte("partially written"))
+	require.NoError(t, dq.Close())
+
+	logFilePath := filepath.Join(dir, LogFileName)
+	info, _ := os.Stat(logFilePath)
+	require.NoError(t, os.Truncate(logFilePath, info.Size()-3))
+
+	dq, err := NewDiskQueue(dir)
+	require.NoError(t, err)
+	require.Equal(t, []*Record{{ID: id, Data: []byte("item")}}, dq.Pending())
+
+	// the log was rewritten, so new records are appended after the last valid one
+	_, _ = dq.Append([]byte("new item"))
+	require.NoError(t, dq.Close())
+
+	dq, _ = NewDiskQueue(dir)
+	require.Len(t, dq.Pending(), 2)
+	require.NoError(t, dq.Close())
+}
+
+func TestDiskQueue_LogShouldBeCompactedWhileRunning(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	dq, _ := NewDiskQueue(dir)
+	dq.acksBetweenCompacts = 3
+
+	ids := make([]uint64, 0)
+	for _, item := range []string{"item1", "item2", "item3", "item4", "item5"} {
+		id, err := dq.Append([]byte(item))
+		require.NoError(t, err)
+		ids = append(ids, id)
+	}
+
+	logFilePath := filepath.Join(dir, LogFileName)
+	sizeBeforeAcks, _ := os.Stat(logFilePath)
+	for _, id := range ids[:3] {
+		require.NoError(t, dq.Ack(id))
+	}
+
+	// only the two pending items are left in the log
+	sizeAfterCompaction, _ := os.Stat(logFilePath)
+	require.Less(t, sizeAfterCompaction.Size(), sizeBeforeAcks.Size())
+	require.Equal(t, int64(2*(headerSize+frameFieldsSize)+len("item4")+len("item5")), sizeAfterCompaction.Size())
+
+	id6, err := dq.Append([]byte("item6"))
+	require.NoError(t, err)
+	require.NoError(t, dq.Ack(ids[3]))
+	require.NoError(t, dq.Close())
+
+	dq, _ = NewDiskQueue(dir)
+	require.Equal(t, []*Record{
+		{ID: ids[4], Data: []byte("item5")},
+		{ID: id6, Data: []byte("item6")},
+	}, dq.Pending())
+	require.NoError(t, dq.Close())
+}
+
+func TestDiskQueue_QuarantineShouldMoveTheItemOutOfTheLog(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	dq, _ := NewDiskQueue(dir)
+	id1, _ := dq.Append([]byte("invalid item"))
+	id2, _ := dq.Append([]byte("item"))
+	require.NoError(t, dq.Close())
+
+	dq, _ = NewDiskQueue(dir)
+	require.NoError(t, dq.Quarantine(dq.Pending()[0]))
+	require.Equal(t, []*Record{{ID: id2, Data: []byte("item")}}, dq.Pending())
+	require.NoError(t, dq.Close())
+
+	dq, _ = NewDiskQueue(dir)
+	require.Equal(t, []*Record{{ID: id2, Data: []byte("item")}}, dq.Pending())
+	require.NoError(t, dq.Close())
+
+	quarantined, _, err := readPendingRecords(filepath.Join(dir, QuarantineFileName))
+	require.NoError(t, err)
+	require.Equal(t, []*Record{{ID: id1, Data: []byte("invalid item")}}, quarantined)
+}
+
+func TestDiskQueue_OperationsAfterCloseShouldErr(t *testing.T) {
+	t.Parallel()
+
+	dq, _ := NewDiskQueue(t.TempDir())
+	require.NoError(t, dq.Close())
+	require.NoError(t, dq.Close())
+
+	_, err := dq.Append([]byte("item"))
+	require.Equal(t, ErrQueueClosed, err)
+	require.Equal(t, ErrQueueClosed, dq.Ack(1))
+	require.Equal(t, ErrQueueClosed, dq.Quarantine(&Record{ID: 1}))
+}
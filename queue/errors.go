@@ -0,0 +1,18 @@
+package queue
+
+import "errors"
+
+// ErrEmptyDirectory signals that an empty directory path has been provided
+var ErrEmptyDirectory = errors.New("empty queue directory")
+
+// ErrQueueClosed signals that an operation was attempted on a closed queue
+var ErrQueueClosed = errors.New("queue is closed")
+
+// ErrRecordTooLarge signals that a record exceeds the maximum accepted size
+var ErrRecordTooLarge = errors.New("record too large")
+
+// ErrInvalidRecordType signals that a record of an unknown type was read from the log
+var ErrInvalidRecordType = errors.New("invalid record type")
+
+// ErrChecksumMismatch signals that the checksum of a record read from the log does not match its content
+var ErrChecksumMismatch = errors.New("record checksum mismatch")
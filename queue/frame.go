@@ -0,0 +1,58 @@
+package queue
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+)
+
+// MaxFrameDataSize is the maximum size of the data held by a frame
+const MaxFrameDataSize = 1 << 30
+
+// frameFieldsSize is the size of the fields written after the header of a frame: the size and the checksum of its data
+const frameFieldsSize = 4 + 4
+
+// EncodeFrame returns a frame holding the given header, followed by the size and the checksum of the data, and by the
+// data itself. The header keeps the fields of a record that are not part of its data
+func EncodeFrame(header []byte, frameData []byte) []byte {
+	buff := make([]byte, len(header)+frameFieldsSize+len(frameData))
+	copy(buff, header)
+	fields := buff[len(header) : len(header)+frameFieldsSize]
+	binary.BigEndian.PutUint32(fields[0:4], uint32(len(frameData)))
+	binary.BigEndian.PutUint32(fields[4:8], crc32.ChecksumIEEE(frameData))
+	copy(buff[len(header)+frameFieldsSize:], frameData)
+
+	return buff
+}
+
+// ReadFrame reads a frame written by EncodeFrame with a header of the given size and returns its header and its data.
+// It returns io.EOF only when the reader ends before the frame, a frame cut short returns io.ErrUnexpectedEOF
+func ReadFrame(reader io.Reader, headerSize int) ([]byte, []byte, error) {
+	header := make([]byte, headerSize+frameFieldsSize)
+	_, err := io.ReadFull(reader, header)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	fields := header[headerSize:]
+	size := binary.BigEndian.Uint32(fields[0:4])
+	if size > MaxFrameDataSize {
+		return nil, nil, fmt.Errorf("%w: %d bytes", ErrRecordTooLarge, size)
+	}
+
+	frameData := make([]byte, size)
+	_, err = io.ReadFull(reader, frameData)
+	if err == io.EOF {
+		return nil, nil, io.ErrUnexpectedEOF
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if crc32.ChecksumIEEE(frameData) != binary.BigEndian.Uint32(fields[4:8]) {
+		return nil, nil, ErrChecksumMismatch
+	}
+
+	return header[:headerSize], frameData, nil
+}
@@ -0,0 +1,46 @@
+package queue
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadFrame_ShouldReturnTheEncodedHeaderAndData(t *testing.T) {
+	t.Parallel()
+
+	buff := bytes.NewBuffer(EncodeFrame([]byte{1, 2, 3}, []byte("data")))
+	buff.Write(EncodeFrame([]byte{4, 5, 6}, nil))
+
+	header, frameData, err := ReadFrame(buff, 3)
+	require.NoError(t, err)
+	require.Equal(t, []byte{1, 2, 3}, header)
+	require.Equal(t, []byte("data"), frameData)
+
+	header, frameData, err = ReadFrame(buff, 3)
+	require.NoError(t, err)
+	require.Equal(t, []byte{4, 5, 6}, header)
+	require.Empty(t, frameData)
+
+	_, _, err = ReadFrame(buff, 3)
+	require.Equal(t, io.EOF, err)
+}
+
+func TestReadFrame_CorruptedOrTruncatedFrameShouldErr(t *testing.T) {
+	t.Parallel()
+
+	frame := EncodeFrame([]byte{1}, []byte("data"))
+
+	corrupted := append([]byte{}, frame...)
+	corrupted[len(corrupted)-1] ^= 0xff
+	_, _, err := ReadFrame(bytes.NewReader(corrupted), 1)
+	require.Equal(t, ErrChecksumMismatch, err)
+
+	_, _, err = ReadFrame(bytes.NewReader(frame[:len(frame)-4]), 1)
+	require.Equal(t, io.ErrUnexpectedEOF, err)
+
+	_, _, err = ReadFrame(bytes.NewReader(frame[:3]), 1)
+	require.Equal(t, io.ErrUnexpectedEOF, err)
+}
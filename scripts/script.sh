@@ -52,4 +52,31 @@ stop_open_search() {
   docker stop "${IMAGE_OPEN_SEARCH}"
 }
 
+IMAGE_POSTGRES=postgres-container
+DEFAULT_POSTGRES_VERSION=15
+
+start_postgres() {
+  POSTGRES_VERSION=$1
+  if [ -z "${POSTGRES_VERSION}" ]; then
+    POSTGRES_VERSION=${DEFAULT_POSTGRES_VERSION}
+  fi
+
+  docker pull postgres:${POSTGRES_VERSION}
+
+  docker rm ${IMAGE_POSTGRES} 2> /dev/null
+  docker run -d --name "${IMAGE_POSTGRES}" -p 5432:5432 \
+   -e "POSTGRES_USER=indexer" -e "POSTGRES_PASSWORD=indexer" -e "POSTGRES_DB=indexer" \
+   postgres:${POSTGRES_VERSION}
+
+  # Wait postgres to accept connections
+  echo "Waiting PostgreSQL to start..."
+  until docker exec "${IMAGE_POSTGRES}" pg_isready -U indexer > /dev/null 2>&1; do
+    sleep 1s
+  done
+}
+
+stop_postgres() {
+  docker stop "${IMAGE_POSTGRES}"
+}
+
 "$@"
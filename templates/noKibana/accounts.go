@@ -25,6 +25,12 @@ var Accounts = Object{
 			"totalBalanceWithStakeNum": Object{
 				"type": "double",
 			},
+			"balanceSortable": Object{
+				"type": "keyword",
+			},
+			"totalBalanceWithStakeSortable": Object{
+				"type": "keyword",
+			},
 			"nonce": Object{
 				"type": "double",
 			},
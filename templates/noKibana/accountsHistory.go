@@ -11,6 +11,12 @@ var AccountsHistory = Object{
 	},
 	"mappings": Object{
 		"properties": Object{
+			"balanceNum": Object{
+				"type": "double",
+			},
+			"balanceSortable": Object{
+				"type": "keyword",
+			},
 			"timestamp": Object{
 				"type":   "date",
 				"format": "epoch_second",
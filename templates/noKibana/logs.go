@@ -26,6 +26,31 @@ var Logs = Object{
 					"data": Object{
 						"type": "text",
 					},
+					"decoded": Object{
+						"properties": Object{
+							"abi": Object{
+								"type": "keyword",
+							},
+							"name": Object{
+								"type": "keyword",
+							},
+							"args": Object{
+								"type": "nested",
+								"properties": Object{
+									"name": Object{
+										"type": "keyword",
+									},
+									"type": Object{
+										"type": "keyword",
+									},
+									"value": Object{
+										"type":         "keyword",
+										"ignore_above": 256,
+									},
+								},
+							},
+						},
+					},
 				},
 			},
 			"timestamp": Object{
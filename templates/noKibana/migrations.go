@@ -0,0 +1,36 @@
+package noKibana
+
+// Migrations will hold the configuration for the migrations index
+var Migrations = Object{
+	"index_patterns": Array{
+		"migrations-*",
+	},
+	"settings": Object{
+		"number_of_shards":   1,
+		"number_of_replicas": 0,
+	},
+
+	"mappings": Object{
+		"properties": Object{
+			"version": Object{
+				"type": "long",
+			},
+			"name": Object{
+				"type": "keyword",
+			},
+			"index": Object{
+				"type": "keyword",
+			},
+			"destination": Object{
+				"type": "keyword",
+			},
+			"status": Object{
+				"type": "keyword",
+			},
+			"timestamp": Object{
+				"type":   "date",
+				"format": "epoch_second",
+			},
+		},
+	},
+}
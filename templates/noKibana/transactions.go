@@ -33,6 +33,31 @@ var Transactions = Object{
 			"gasPrice": Object{
 				"type": "double",
 			},
+			"decoded": Object{
+				"properties": Object{
+					"abi": Object{
+						"type": "keyword",
+					},
+					"name": Object{
+						"type": "keyword",
+					},
+					"args": Object{
+						"type": "nested",
+						"properties": Object{
+							"name": Object{
+								"type": "keyword",
+							},
+							"type": Object{
+								"type": "keyword",
+							},
+							"value": Object{
+								"type":         "keyword",
+								"ignore_above": 256,
+							},
+						},
+					},
+				},
+			},
 		},
 	},
 }
@@ -14,6 +14,9 @@ var AccountsMECT = Object{
 			"balanceNum": Object{
 				"type": "double",
 			},
+			"balanceSortable": Object{
+				"type": "keyword",
+			},
 			"data": Object{
 				"type": "nested",
 				"properties": Object{
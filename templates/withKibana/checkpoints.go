@@ -0,0 +1,30 @@
+package withKibana
+
+// Checkpoints will hold the configuration for the checkpoints index
+var Checkpoints = Object{
+	"index_patterns": Array{
+		"checkpoints-*",
+	},
+	"settings": Object{
+		"number_of_shards":   1,
+		"number_of_replicas": 0,
+	},
+
+	"mappings": Object{
+		"properties": Object{
+			"shardId": Object{
+				"type": "long",
+			},
+			"nonce": Object{
+				"type": "double",
+			},
+			"hash": Object{
+				"type": "keyword",
+			},
+			"timestamp": Object{
+				"type":   "date",
+				"format": "epoch_second",
+			},
+		},
+	},
+}
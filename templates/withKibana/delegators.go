@@ -15,6 +15,9 @@ var Delegators = Object{
 			"activeStakeNum": Object{
 				"type": "double",
 			},
+			"activeStakeSortable": Object{
+				"type": "keyword",
+			},
 		},
 	},
 }
@@ -0,0 +1,88 @@
+package withKibana
+
+// Events will hold the configuration for the events index
+var Events = Object{
+	"index_patterns": Array{
+		"events-*",
+	},
+	"settings": Object{
+		"number_of_shards":   3,
+		"number_of_replicas": 0,
+	},
+	"mappings": Object{
+		"properties": Object{
+			"txHash": Object{
+				"type": "keyword",
+			},
+			"originalTxHash": Object{
+				"type": "keyword",
+			},
+			"logAddress": Object{
+				"type": "keyword",
+			},
+			"address": Object{
+				"type": "keyword",
+			},
+			"identifier": Object{
+				"type": "keyword",
+			},
+			"topics": Object{
+				"type":         "keyword",
+				"ignore_above": 256,
+			},
+			"data": Object{
+				"type": "text",
+			},
+			"order": Object{
+				"type": "long",
+			},
+			"shardID": Object{
+				"type": "long",
+			},
+			"token": Object{
+				"type": "keyword",
+			},
+			"tokenIdentifier": Object{
+				"type": "keyword",
+			},
+			"nonce": Object{
+				"type": "long",
+			},
+			"value": Object{
+				"type": "keyword",
+			},
+			"receiver": Object{
+				"type": "keyword",
+			},
+			"timestamp": Object{
+				"type":   "date",
+				"format": "epoch_second",
+			},
+			"decoded": Object{
+				"properties": Object{
+					"abi": Object{
+						"type": "keyword",
+					},
+					"name": Object{
+						"type": "keyword",
+					},
+					"args": Object{
+						"type": "nested",
+						"properties": Object{
+							"name": Object{
+								"type": "keyword",
+							},
+							"type": Object{
+								"type": "keyword",
+							},
+							"value": Object{
+								"type":         "keyword",
+								"ignore_above": 256,
+							},
+						},
+					},
+				},
+			},
+		},
+	},
+}
@@ -0,0 +1,28 @@
+package withKibana
+
+// Journal will hold the configuration for the journal index
+var Journal = Object{
+	"index_patterns": Array{
+		"journal-*",
+	},
+	"settings": Object{
+		"number_of_shards":   1,
+		"number_of_replicas": 0,
+	},
+
+	"mappings": Object{
+		"dynamic": false,
+		"properties": Object{
+			"nonce": Object{
+				"type": "double",
+			},
+			"shardId": Object{
+				"type": "long",
+			},
+			"timestamp": Object{
+				"type":   "date",
+				"format": "epoch_second",
+			},
+		},
+	},
+}
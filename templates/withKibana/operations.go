@@ -27,6 +27,31 @@ var Operations = Object{
 				"type":   "date",
 				"format": "epoch_second",
 			},
+			"decoded": Object{
+				"properties": Object{
+					"abi": Object{
+						"type": "keyword",
+					},
+					"name": Object{
+						"type": "keyword",
+					},
+					"args": Object{
+						"type": "nested",
+						"properties": Object{
+							"name": Object{
+								"type": "keyword",
+							},
+							"type": Object{
+								"type": "keyword",
+							},
+							"value": Object{
+								"type":         "keyword",
+								"ignore_above": 256,
+							},
+						},
+					},
+				},
+			},
 		},
 	},
 }
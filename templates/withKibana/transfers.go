@@ -0,0 +1,59 @@
+package withKibana
+
+// Transfers will hold the configuration for the transfers index
+var Transfers = Object{
+	"index_patterns": Array{
+		"transfers-*",
+	},
+	"settings": Object{
+		"number_of_shards":   3,
+		"number_of_replicas": 0,
+	},
+	"mappings": Object{
+		"properties": Object{
+			"txHash": Object{
+				"type": "keyword",
+			},
+			"scrHash": Object{
+				"type": "keyword",
+			},
+			"operation": Object{
+				"type": "keyword",
+			},
+			"sender": Object{
+				"type": "keyword",
+			},
+			"receiver": Object{
+				"type": "keyword",
+			},
+			"receiverShard": Object{
+				"type": "long",
+			},
+			"token": Object{
+				"type": "keyword",
+			},
+			"tokenIdentifier": Object{
+				"type": "keyword",
+			},
+			"nonce": Object{
+				"type": "long",
+			},
+			"amount": Object{
+				"type": "keyword",
+			},
+			"amountNum": Object{
+				"type": "double",
+			},
+			"amountSortable": Object{
+				"type": "keyword",
+			},
+			"shardID": Object{
+				"type": "long",
+			},
+			"timestamp": Object{
+				"type":   "date",
+				"format": "epoch_second",
+			},
+		},
+	},
+}
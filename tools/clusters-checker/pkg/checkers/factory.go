@@ -16,7 +16,7 @@ func CreateClusterChecker(cfg *config.Config, timestampIndex int, logPrefix stri
 		Addresses: []string{cfg.SourceCluster.URL},
 		Username:  cfg.SourceCluster.User,
 		Password:  cfg.SourceCluster.Password,
-	})
+	}, cfg.SourceCluster.IndexPrefix)
 	if err != nil {
 		return nil, fmt.Errorf("cannot create source client %s", err.Error())
 	}
@@ -25,7 +25,7 @@ func CreateClusterChecker(cfg *config.Config, timestampIndex int, logPrefix stri
 		Addresses: []string{cfg.DestinationCluster.URL},
 		Username:  cfg.DestinationCluster.User,
 		Password:  cfg.DestinationCluster.Password,
-	})
+	}, cfg.DestinationCluster.IndexPrefix)
 	if err != nil {
 		return nil, fmt.Errorf("cannot create destination client %s", err.Error())
 	}
@@ -24,6 +24,8 @@ var (
 	httpStatusesForRetry = []int{429, 502, 503, 504}
 )
 
+const indexPrefixSeparator = "-"
+
 type esClient struct {
 	client *elasticsearch.Client
 	// countScroll is used to be incremented after each scroll so the scroll duration is different each time,
@@ -31,10 +33,12 @@ type esClient struct {
 	countScroll int
 	countSearch int
 	mutex       sync.Mutex
+	indexPrefix string
 }
 
-// NewElasticClient will create a new instance of an esClient
-func NewElasticClient(cfg elasticsearch.Config) (*esClient, error) {
+// NewElasticClient will create a new instance of an esClient. If an index prefix is provided, the requests are sent
+// to the indices of that network, e.g. "testnet-transactions" instead of "transactions"
+func NewElasticClient(cfg elasticsearch.Config, indexPrefix string) (*esClient, error) {
 	if len(cfg.RetryOnStatus) == 0 {
 		cfg.RetryOnStatus = httpStatusesForRetry
 		cfg.RetryBackoff = func(i int) time.Duration {
@@ -55,14 +59,23 @@ func NewElasticClient(cfg elasticsearch.Config) (*esClient, error) {
 		client:      elasticClient,
 		countScroll: 0,
 		mutex:       sync.Mutex{},
+		indexPrefix: indexPrefix,
 	}, nil
 }
 
+func (esc *esClient) prefixed(index string) string {
+	if esc.indexPrefix == "" {
+		return index
+	}
+
+	return esc.indexPrefix + indexPrefixSeparator + index
+}
+
 func (esc *esClient) InitializeScroll(index string, body []byte, response interface{}) (string, bool, error) {
 	res, err := esc.client.Search(
 		esc.client.Search.WithSize(9000),
 		esc.client.Search.WithScroll(10*time.Minute+time.Duration(esc.updateAndGetCountScroll())*time.Millisecond),
-		esc.client.Search.WithIndex(index),
+		esc.client.Search.WithIndex(esc.prefixed(index)),
 		esc.client.Search.WithBody(bytes.NewBuffer(body)),
 	)
 	if err != nil {
@@ -149,7 +162,7 @@ func (esc *esClient) DoScrollRequestAllDocuments(
 		esc.client.Search.WithSize(size),
 		esc.client.Search.WithScroll(10*time.Minute+time.Duration(esc.updateAndGetCountScroll())*time.Millisecond),
 		esc.client.Search.WithContext(context.Background()),
-		esc.client.Search.WithIndex(index),
+		esc.client.Search.WithIndex(esc.prefixed(index)),
 		esc.client.Search.WithBody(bytes.NewBuffer(body)),
 	)
 	if err != nil {
@@ -9,7 +9,7 @@ import (
 // DoCountRequest will get the number of elements that correspond with the provided query
 func (esc *esClient) DoCountRequest(index string, body []byte) (uint64, error) {
 	res, err := esc.client.Count(
-		esc.client.Count.WithIndex(index),
+		esc.client.Count.WithIndex(esc.prefixed(index)),
 		esc.client.Count.WithBody(bytes.NewBuffer(body)),
 	)
 	if err != nil {
@@ -10,7 +10,7 @@ import (
 
 func (esc *esClient) DoGetRequest(index string, body []byte, response interface{}, size int) error {
 	res, err := esc.client.Search(
-		esc.client.Search.WithIndex(index),
+		esc.client.Search.WithIndex(esc.prefixed(index)),
 		esc.client.Search.WithBody(bytes.NewBuffer(body)),
 		esc.client.Search.WithRequestCache(false),
 		esc.client.Search.WithSize(size),
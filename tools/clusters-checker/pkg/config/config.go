@@ -2,14 +2,16 @@ package config
 
 type Config struct {
 	SourceCluster struct {
-		URL      string `toml:"url"`
-		User     string `toml:"user"`
-		Password string `toml:"password"`
+		URL         string `toml:"url"`
+		User        string `toml:"user"`
+		Password    string `toml:"password"`
+		IndexPrefix string `toml:"index-prefix"`
 	} `toml:"source-cluster"`
 	DestinationCluster struct {
-		URL      string `toml:"url"`
-		User     string `toml:"user"`
-		Password string `toml:"password"`
+		URL         string `toml:"url"`
+		User        string `toml:"user"`
+		Password    string `toml:"password"`
+		IndexPrefix string `toml:"index-prefix"`
 	} `toml:"destination-cluster"`
 	Compare struct {
 		IntervalSettings []struct {
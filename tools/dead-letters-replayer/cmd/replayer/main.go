@@ -0,0 +1,65 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/ME-MotherEarth/me-elastic-indexer/deadletter"
+	"github.com/ME-MotherEarth/me-elastic-indexer/queue"
+)
+
+// replayedSuffix is appended to the dead letters file after its letters were replayed, so they are not replayed twice
+const replayedSuffix = ".replayed"
+
+var (
+	deadLettersFile = flag.String("dead-letters", "", "The path to the dead letters file written by the indexer")
+	queuePath       = flag.String("queue", "", "The path to the persistent queue directory of the indexer")
+)
+
+// The replayer appends the dead letters in the persistent queue of the indexer, so they are saved again when the
+// indexer starts. The indexer must be stopped while the replayer runs
+func main() {
+	flag.Parse()
+
+	err := replay(*deadLettersFile, *queuePath)
+	if err != nil {
+		fmt.Println("cannot replay the dead letters:", err.Error())
+		os.Exit(1)
+	}
+}
+
+func replay(deadLettersFile string, queuePath string) error {
+	if deadLettersFile == "" || queuePath == "" {
+		flag.Usage()
+		return fmt.Errorf("both the dead letters file and the queue path have to be provided")
+	}
+
+	letters, err := deadletter.ReadLetters(deadLettersFile)
+	if err != nil {
+		return err
+	}
+
+	diskQueue, err := queue.NewDiskQueue(queuePath)
+	if err != nil {
+		return err
+	}
+
+	numReplayed, err := deadletter.Replay(letters, diskQueue)
+	errClose := diskQueue.Close()
+	if err != nil {
+		return err
+	}
+	if errClose != nil {
+		return errClose
+	}
+
+	err = os.Rename(deadLettersFile, deadLettersFile+replayedSuffix)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("replayed %d of %d dead letters\n", numReplayed, len(letters))
+
+	return nil
+}
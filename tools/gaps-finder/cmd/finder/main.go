@@ -0,0 +1,96 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+
+	indexer "github.com/ME-MotherEarth/me-elastic-indexer"
+	"github.com/ME-MotherEarth/me-elastic-indexer/client"
+	"github.com/ME-MotherEarth/me-elastic-indexer/data"
+	"github.com/ME-MotherEarth/me-elastic-indexer/gaps"
+	"github.com/elastic/go-elasticsearch/v7"
+)
+
+var (
+	clusterAddress = flag.String("url", "http://localhost:9200", "The address of the elasticsearch cluster")
+	indexPrefix    = flag.String("prefix", "", "The prefix of the indices of the network, if any")
+	shardID        = flag.Uint("shard", 0, "The shard of the scanned blocks")
+	fromNonce      = flag.Uint64("from", 0, "The first scanned nonce")
+	toNonce        = flag.Uint64("to", 0, "The last scanned nonce. If not provided, the checkpoint of the shard is used")
+)
+
+type multiGetClient interface {
+	DoMultiGet(ids []string, index string, withSource bool, resBody interface{}) error
+}
+
+type checkpointsResponse struct {
+	Docs []struct {
+		Found  bool            `json:"found"`
+		Source data.Checkpoint `json:"_source"`
+	} `json:"docs"`
+}
+
+// The finder prints the ranges of nonces that have no block in the blocks index, so they can be fed again. By default,
+// the blocks are scanned up to the checkpoint of the shard, as the newer blocks can still be on their way
+func main() {
+	flag.Parse()
+
+	err := findGaps()
+	if err != nil {
+		fmt.Println("cannot find the gaps:", err.Error())
+		os.Exit(1)
+	}
+}
+
+func findGaps() error {
+	clusterClient, err := client.NewElasticClient(elasticsearch.Config{
+		Addresses: []string{*clusterAddress},
+	})
+	if err != nil {
+		return err
+	}
+
+	lastNonce := *toNonce
+	if lastNonce == 0 {
+		lastNonce, err = getCheckpointNonce(clusterClient, uint32(*shardID))
+		if err != nil {
+			return err
+		}
+	}
+
+	scanner, err := gaps.NewScanner(gaps.ArgsScanner{
+		ScrollClient: clusterClient,
+		IndexPrefix:  *indexPrefix,
+	})
+	if err != nil {
+		return err
+	}
+
+	missingRanges, err := scanner.FindGaps(uint32(*shardID), *fromNonce, lastNonce)
+	if err != nil {
+		return err
+	}
+
+	for _, missingRange := range missingRanges {
+		fmt.Printf("shard %d: missing nonces %d - %d\n", *shardID, missingRange.From, missingRange.To)
+	}
+	fmt.Printf("shard %d: found %d gaps between nonces %d and %d\n", *shardID, len(missingRanges), *fromNonce, lastNonce)
+
+	return nil
+}
+
+func getCheckpointNonce(clusterClient multiGetClient, shard uint32) (uint64, error) {
+	response := &checkpointsResponse{}
+	checkpointsIndex := indexer.PrefixIndex(*indexPrefix, indexer.CheckpointsIndex)
+	err := clusterClient.DoMultiGet([]string{strconv.FormatUint(uint64(shard), 10)}, checkpointsIndex, true, response)
+	if err != nil {
+		return 0, err
+	}
+	if len(response.Docs) == 0 || !response.Docs[0].Found {
+		return 0, fmt.Errorf("shard %d has no checkpoint, the last nonce has to be provided", shard)
+	}
+
+	return response.Docs[0].Source.Nonce, nil
+}
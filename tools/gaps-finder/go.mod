@@ -0,0 +1,28 @@
+module github.com/ME-MotherEarth/me-elastic-indexer/tools/gaps-finder
+
+go 1.19
+
+require (
+	github.com/ME-MotherEarth/me-elastic-indexer v0.0.0
+	github.com/elastic/go-elasticsearch/v7 v7.12.0
+)
+
+require (
+	github.com/ME-MotherEarth/me-core v0.0.1 // indirect
+	github.com/ME-MotherEarth/me-logger v0.0.1 // indirect
+	github.com/ME-MotherEarth/me-vm-common v0.0.1 // indirect
+	github.com/denisbrodbeck/machineid v1.0.1 // indirect
+	github.com/gogo/protobuf v0.0.0-00010101000000-000000000000 // indirect
+	github.com/golang/protobuf v1.5.2 // indirect
+	github.com/mr-tron/base58 v1.2.0 // indirect
+	github.com/pelletier/go-toml v1.9.5 // indirect
+	github.com/tidwall/gjson v1.14.3 // indirect
+	github.com/tidwall/match v1.1.1 // indirect
+	github.com/tidwall/pretty v1.2.0 // indirect
+	google.golang.org/protobuf v1.26.0 // indirect
+)
+
+replace (
+	github.com/ME-MotherEarth/me-elastic-indexer => ../..
+	github.com/gogo/protobuf => github.com/ME-MotherEarth/protobuf v1.3.2
+)
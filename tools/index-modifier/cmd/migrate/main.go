@@ -0,0 +1,135 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/ME-MotherEarth/me-elastic-indexer/tools/index-modifier/pkg/alterindex"
+	"github.com/ME-MotherEarth/me-elastic-indexer/tools/index-modifier/pkg/client"
+	"github.com/ME-MotherEarth/me-elastic-indexer/tools/index-modifier/pkg/migration"
+	"github.com/elastic/go-elasticsearch/v7"
+)
+
+const usage = `usage: migrate [flags] up | status | baseline <version>
+  up                  applies the pending migrations, resuming an interrupted one
+  status              lists the pending migrations
+  baseline <version>  sets the schema version of a cluster already indexed with that schema
+`
+
+const tokensIndex = "tokens"
+
+var (
+	clusterAddress = flag.String("url", "http://localhost:9200", "The address of the elasticsearch cluster")
+	indexPrefix    = flag.String("index-prefix", "", "The index prefix of the network, set for a cluster shared by several networks, e.g. \"testnet\"")
+)
+
+// The migrations change the schema of the indices of a cluster, one numbered migration after another. The indexer has
+// to be stopped while they are applied, as it does not start while the cluster has pending migrations
+func main() {
+	flag.Usage = func() {
+		fmt.Print(usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	err := run(flag.Args())
+	if err != nil {
+		fmt.Println("migrate:", err.Error())
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	if len(args) == 0 {
+		flag.Usage()
+		return fmt.Errorf("no command provided")
+	}
+
+	migrator, err := createMigrator()
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "up":
+		err = migrator.Up()
+		if err != nil {
+			return err
+		}
+		fmt.Println("done")
+		return nil
+	case "status":
+		return printPending(migrator)
+	case "baseline":
+		if len(args) != 2 {
+			flag.Usage()
+			return fmt.Errorf("the baseline command needs the schema version")
+		}
+		version, errParse := strconv.ParseUint(args[1], 10, 32)
+		if errParse != nil {
+			return errParse
+		}
+		return migrator.Baseline(uint32(version))
+	default:
+		flag.Usage()
+		return fmt.Errorf("unknown command %s", args[0])
+	}
+}
+
+func createMigrator() (migration.Migrator, error) {
+	clusterClient, err := client.NewElasticClient(elasticsearch.Config{
+		Addresses: []string{*clusterAddress},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	migrations, err := registerMigrations(clusterClient)
+	if err != nil {
+		return nil, fmt.Errorf("%w while creating the migrations", err)
+	}
+
+	indexModifier, err := alterindex.CreateIndexModifier(*clusterAddress, *clusterAddress, *indexPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	migrator, err := migration.NewMigrator(migration.ArgsMigrator{
+		Client:        clusterClient,
+		IndexModifier: indexModifier,
+		Migrations:    migrations,
+		IndexPrefix:   *indexPrefix,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return migrator, nil
+}
+
+func prefixed(index string) string {
+	if *indexPrefix == "" {
+		return index
+	}
+
+	return *indexPrefix + "-" + index
+}
+
+func printPending(migrator migration.Migrator) error {
+	pending, err := migrator.Pending()
+	if err != nil {
+		return err
+	}
+	if len(pending) == 0 {
+		fmt.Println("no pending migrations")
+		return nil
+	}
+
+	for _, pendingMigration := range pending {
+		fmt.Printf("%d\t%s\t%s\n", pendingMigration.Version, pendingMigration.Index, pendingMigration.Name)
+	}
+
+	return nil
+}
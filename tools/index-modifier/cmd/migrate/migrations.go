@@ -0,0 +1,92 @@
+package main
+
+import (
+	"bytes"
+
+	"github.com/ME-MotherEarth/me-elastic-indexer/tools/index-modifier/pkg/migration"
+	"github.com/ME-MotherEarth/me-elastic-indexer/tools/index-modifier/pkg/modifiers"
+)
+
+// accountsMECTHistoryMappings are the mappings of the accountsmecthistory index with the balance as float
+const accountsMECTHistoryMappings = `{
+	"properties": {
+		"balanceNum": {
+			"type": "double"
+		},
+		"balanceSortable": {
+			"type": "keyword"
+		},
+		"timestamp": {
+			"format": "epoch_second",
+			"type": "date"
+		},
+		"tokenNonce": {
+			"type": "double"
+		}
+	}
+}`
+
+// registerMigrations returns the migrations of the indices schema, in the order they are applied. A new migration is
+// registered with the next version, which also becomes the SchemaVersion of the indexer writing the new schema
+func registerMigrations(documentsGetter modifiers.DocumentsGetter) ([]*migration.Migration, error) {
+	txsModifier, err := modifiers.NewTxsModifier()
+	if err != nil {
+		return nil, err
+	}
+	scrsModifier, err := modifiers.NewSCRsModifier()
+	if err != nil {
+		return nil, err
+	}
+
+	registry := migration.NewRegistry()
+	err = registry.Register(&migration.Migration{
+		Version:        1,
+		Name:           "parse the data field of the transactions",
+		Index:          "transactions",
+		Modifier:       txsModifier.Modify,
+		DropsDocuments: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = registry.Register(&migration.Migration{
+		Version:        2,
+		Name:           "parse the data field of the smart contract results",
+		Index:          "scresults",
+		Modifier:       scrsModifier.Modify,
+		DropsDocuments: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// the number of decimals of the tokens is read once for both indices of the accounts MECT
+	mectBalancesModifier, err := modifiers.NewMECTBalancesModifier(documentsGetter, prefixed(tokensIndex))
+	if err != nil {
+		return nil, err
+	}
+
+	err = registry.Register(&migration.Migration{
+		Version:  3,
+		Name:     "convert the balances of the accounts MECT with the decimals of their token",
+		Index:    "accountsmect",
+		Modifier: mectBalancesModifier.Modify,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = registry.Register(&migration.Migration{
+		Version:  4,
+		Name:     "add the balance as float, converted with the decimals of the token, to the accounts MECT history",
+		Index:    "accountsmecthistory",
+		Modifier: mectBalancesModifier.Modify,
+		Mappings: bytes.NewBufferString(accountsMECTHistoryMappings),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return registry.Migrations(), nil
+}
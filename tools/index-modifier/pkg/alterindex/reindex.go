@@ -13,7 +13,8 @@ import (
 )
 
 const (
-	queryMatchAll = `{"query":{"match_all": {}}}`
+	queryMatchAll        = `{"query":{"match_all": {}}}`
+	indexPrefixSeparator = "-"
 )
 
 var log = logger.GetOrCreate("index-modifier/pkg/alterindex")
@@ -21,6 +22,7 @@ var log = logger.GetOrCreate("index-modifier/pkg/alterindex")
 type indexModifier struct {
 	scrollClient ScrollClient
 	bulkClient   BulkClient
+	indexPrefix  string
 }
 
 func backOff(i int) time.Duration {
@@ -30,8 +32,9 @@ func backOff(i int) time.Duration {
 	return d
 }
 
-// CreateIndexModifier will create a new instance of indexModifier
-func CreateIndexModifier(scrollClientAddress, bulkClientAddress string) (*indexModifier, error) {
+// CreateIndexModifier will create a new instance of indexModifier. If an index prefix is provided, the indices of
+// that network are altered, e.g. "testnet-transactions" instead of "transactions"
+func CreateIndexModifier(scrollClientAddress, bulkClientAddress, indexPrefix string) (*indexModifier, error) {
 	cfg := elasticsearch.Config{
 		Addresses:     []string{scrollClientAddress},
 		MaxRetries:    0,
@@ -52,6 +55,7 @@ func CreateIndexModifier(scrollClientAddress, bulkClientAddress string) (*indexM
 	return &indexModifier{
 		scrollClient: scrollClient,
 		bulkClient:   bulkClient,
+		indexPrefix:  indexPrefix,
 	}, nil
 }
 
@@ -66,7 +70,7 @@ func (im *indexModifier) AlterIndex(indexRead, indexWrite string, modifier func(
 		}
 
 		for i := 0; i < len(dataBuffers); i++ {
-			err = im.bulkClient.DoBulkRequest(dataBuffers[i], indexWrite)
+			err = im.bulkClient.DoBulkRequest(dataBuffers[i], im.prefixed(indexWrite))
 			if err != nil {
 				return fmt.Errorf("%w while r.destinationElastic.DoBulkRequest", err)
 			}
@@ -77,10 +81,18 @@ func (im *indexModifier) AlterIndex(indexRead, indexWrite string, modifier func(
 		return nil
 	}
 
-	err := im.scrollClient.DoScrollRequestAllDocuments(indexRead, []byte(queryMatchAll), handlerFunc)
+	err := im.scrollClient.DoScrollRequestAllDocuments(im.prefixed(indexRead), []byte(queryMatchAll), handlerFunc)
 	if err != nil {
 		return fmt.Errorf("%w while r.sourceElastic.DoScrollRequestAllDocuments", err)
 	}
 
 	return nil
 }
+
+func (im *indexModifier) prefixed(index string) string {
+	if im.indexPrefix == "" {
+		return index
+	}
+
+	return im.indexPrefix + indexPrefixSeparator + index
+}
@@ -3,6 +3,7 @@ package client
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -71,6 +72,200 @@ func (esc *esClient) DoScrollRequestAllDocuments(
 	return esc.iterateScroll(scrollID.String(), handlerFunc)
 }
 
+// CreateIndex will create the provided index, if it does not exist. The index gets the settings and mappings of the
+// templates that match its name
+func (esc *esClient) CreateIndex(index string) error {
+	res, err := esc.client.Indices.Exists([]string{index})
+	if err != nil {
+		return err
+	}
+	closeBody(res)
+	if res.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	res, err = esc.client.Indices.Create(index)
+	if err != nil {
+		return err
+	}
+
+	_, err = getBytesFromResponse(res)
+	return err
+}
+
+// GetTemplate returns the index template with the provided name, as it is stored in the cluster
+func (esc *esClient) GetTemplate(templateName string) ([]byte, error) {
+	res, err := esc.client.Indices.GetTemplate(esc.client.Indices.GetTemplate.WithName(templateName))
+	if err != nil {
+		return nil, err
+	}
+
+	bodyBytes, err := getBytesFromResponse(res)
+	if err != nil {
+		return nil, err
+	}
+
+	response := make(map[string]json.RawMessage)
+	err = json.Unmarshal(bodyBytes, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	template, found := response[templateName]
+	if !found {
+		return nil, fmt.Errorf("template %s not found", templateName)
+	}
+
+	return template, nil
+}
+
+// PutTemplate will create the index template or will replace the existing one
+func (esc *esClient) PutTemplate(templateName string, template *bytes.Buffer) error {
+	res, err := esc.client.Indices.PutTemplate(templateName, template)
+	if err != nil {
+		return err
+	}
+
+	_, err = getBytesFromResponse(res)
+	return err
+}
+
+// CountDocuments returns the number of documents of the provided index or alias
+func (esc *esClient) CountDocuments(index string) (uint64, error) {
+	res, err := esc.client.Count(esc.client.Count.WithIndex(index))
+	if err != nil {
+		return 0, err
+	}
+
+	bodyBytes, err := getBytesFromResponse(res)
+	if err != nil {
+		return 0, err
+	}
+
+	return gjson.GetBytes(bodyBytes, "count").Uint(), nil
+}
+
+// Refresh will make the last changes of the index visible to the search and count requests
+func (esc *esClient) Refresh(index string) error {
+	res, err := esc.client.Indices.Refresh(esc.client.Indices.Refresh.WithIndex(index))
+	if err != nil {
+		return err
+	}
+
+	_, err = getBytesFromResponse(res)
+	return err
+}
+
+// GetAliasIndices returns the indices the alias points to, each with true if it is the write index of the alias
+func (esc *esClient) GetAliasIndices(alias string) (map[string]bool, error) {
+	res, err := esc.client.Indices.GetAlias(esc.client.Indices.GetAlias.WithName(alias))
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode == http.StatusNotFound {
+		closeBody(res)
+		return make(map[string]bool), nil
+	}
+
+	bodyBytes, err := getBytesFromResponse(res)
+	if err != nil {
+		return nil, err
+	}
+
+	response := make(map[string]struct {
+		Aliases map[string]struct {
+			IsWriteIndex bool `json:"is_write_index"`
+		} `json:"aliases"`
+	})
+	err = json.Unmarshal(bodyBytes, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	aliasIndices := make(map[string]bool, len(response))
+	for index, indexAliases := range response {
+		aliasIndices[index] = indexAliases.Aliases[alias].IsWriteIndex
+	}
+
+	return aliasIndices, nil
+}
+
+// SwapAlias will move, in a single atomic request, the alias from the previous indices to the new one
+func (esc *esClient) SwapAlias(alias string, previousIndices []string, newIndex string, isWriteIndex bool) error {
+	actions := make([]interface{}, 0, len(previousIndices)+1)
+	for _, index := range previousIndices {
+		actions = append(actions, map[string]interface{}{
+			"remove": map[string]interface{}{
+				"index": index,
+				"alias": alias,
+			},
+		})
+	}
+
+	addAction := map[string]interface{}{
+		"index": newIndex,
+		"alias": alias,
+	}
+	if isWriteIndex {
+		addAction["is_write_index"] = true
+	}
+	actions = append(actions, map[string]interface{}{
+		"add": addAction,
+	})
+
+	body, err := json.Marshal(map[string]interface{}{
+		"actions": actions,
+	})
+	if err != nil {
+		return err
+	}
+
+	res, err := esc.client.Indices.UpdateAliases(bytes.NewBuffer(body))
+	if err != nil {
+		return err
+	}
+
+	_, err = getBytesFromResponse(res)
+	return err
+}
+
+// GetDocument returns the source of the document with the provided id, or nil if the document or the index does
+// not exist
+func (esc *esClient) GetDocument(index string, id string) ([]byte, error) {
+	res, err := esc.client.Get(index, id)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode == http.StatusNotFound {
+		closeBody(res)
+		return nil, nil
+	}
+
+	bodyBytes, err := getBytesFromResponse(res)
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(gjson.GetBytes(bodyBytes, "_source").Raw), nil
+}
+
+// PutDocument will index the provided document, replacing the one with the same id. The document is visible to the
+// next requests
+func (esc *esClient) PutDocument(index string, id string, document []byte) error {
+	res, err := esc.client.Index(
+		index,
+		bytes.NewBuffer(document),
+		esc.client.Index.WithDocumentID(id),
+		esc.client.Index.WithRefresh("true"),
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = getBytesFromResponse(res)
+	return err
+}
+
 func (esc *esClient) iterateScroll(
 	scrollID string,
 	handlerFunc func(responseBytes []byte) error,
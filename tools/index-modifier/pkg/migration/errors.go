@@ -0,0 +1,21 @@
+package migration
+
+import "errors"
+
+// ErrNilClusterClient signals that a nil cluster client has been provided
+var ErrNilClusterClient = errors.New("nil cluster client")
+
+// ErrNilIndexModifier signals that a nil index modifier has been provided
+var ErrNilIndexModifier = errors.New("nil index modifier")
+
+// ErrInvalidMigration signals that a migration without an index or a modifier has been provided
+var ErrInvalidMigration = errors.New("invalid migration")
+
+// ErrMigrationVersion signals that the migrations are not numbered in increasing order
+var ErrMigrationVersion = errors.New("the migration version has to be greater than the previous one")
+
+// ErrDocumentsCount signals that the new index does not hold the documents of the migrated one
+var ErrDocumentsCount = errors.New("the number of documents of the new index differs")
+
+// ErrMissingStateIndex signals that the index holding the state of the migrations does not exist
+var ErrMissingStateIndex = errors.New("the migrations index does not exist, it is created by the indexer when it starts")
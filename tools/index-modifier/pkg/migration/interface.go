@@ -0,0 +1,28 @@
+package migration
+
+import "bytes"
+
+// ClusterClient defines what the client used to apply the migrations should do
+type ClusterClient interface {
+	CreateIndex(index string) error
+	GetTemplate(templateName string) ([]byte, error)
+	PutTemplate(templateName string, template *bytes.Buffer) error
+	CountDocuments(index string) (uint64, error)
+	Refresh(index string) error
+	GetAliasIndices(alias string) (map[string]bool, error)
+	SwapAlias(alias string, previousIndices []string, newIndex string, isWriteIndex bool) error
+	GetDocument(index string, id string) ([]byte, error)
+	PutDocument(index string, id string, document []byte) error
+}
+
+// IndexModifier defines what the component that copies the documents of an index in another one should do
+type IndexModifier interface {
+	AlterIndex(indexRead, indexWrite string, modifier func(responseBytes []byte) ([]*bytes.Buffer, error)) error
+}
+
+// Migrator defines what the component that applies the migrations of the indices schema should do
+type Migrator interface {
+	Up() error
+	Pending() ([]*Migration, error)
+	Baseline(version uint32) error
+}
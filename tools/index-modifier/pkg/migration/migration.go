@@ -0,0 +1,60 @@
+package migration
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// Migration is a numbered change of the schema of an index. The documents of the index are copied, transformed by the
+// modifier, in a new index, which replaces the previous ones behind the alias
+type Migration struct {
+	Version  uint32
+	Name     string
+	Index    string
+	Modifier func(responseBytes []byte) ([]*bytes.Buffer, error)
+	// Mappings, if set, replace the mappings of the index template before the new index is created. The settings of
+	// the template, as the rollover ones added by the indexer, are kept
+	Mappings *bytes.Buffer
+	// DropsDocuments is set if the modifier skips some documents, so the new index can hold fewer documents
+	DropsDocuments bool
+}
+
+type registry struct {
+	migrations []*Migration
+}
+
+// NewRegistry will create a new instance of an empty migrations registry
+func NewRegistry() *registry {
+	return &registry{
+		migrations: make([]*Migration, 0),
+	}
+}
+
+// Register will add the provided migration, which has to be numbered after the already registered ones
+func (r *registry) Register(migration *Migration) error {
+	if migration == nil || migration.Index == "" || migration.Modifier == nil {
+		return ErrInvalidMigration
+	}
+	if migration.Version <= r.LatestVersion() {
+		return fmt.Errorf("%w, migration: %s, version: %d, previous version: %d",
+			ErrMigrationVersion, migration.Name, migration.Version, r.LatestVersion())
+	}
+
+	r.migrations = append(r.migrations, migration)
+
+	return nil
+}
+
+// Migrations returns the registered migrations, in the order they have to be applied
+func (r *registry) Migrations() []*Migration {
+	return r.migrations
+}
+
+// LatestVersion returns the schema version of a cluster with all the registered migrations applied
+func (r *registry) LatestVersion() uint32 {
+	if len(r.migrations) == 0 {
+		return 0
+	}
+
+	return r.migrations[len(r.migrations)-1].Version
+}
@@ -0,0 +1,271 @@
+package migration
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	logger "github.com/ME-MotherEarth/me-logger"
+)
+
+const (
+	// indexSuffix is the suffix of the new index, so the rolling indices keep being rolled over after the migration
+	indexSuffix          = "000001"
+	indexPrefixSeparator = "-"
+	mappingsField        = "mappings"
+)
+
+var log = logger.GetOrCreate("index-modifier/pkg/migration")
+
+// ArgsMigrator holds all dependencies required by the migrator in order to create new instances
+type ArgsMigrator struct {
+	Client        ClusterClient
+	IndexModifier IndexModifier
+	Migrations    []*Migration
+	IndexPrefix   string
+}
+
+type migrator struct {
+	client        ClusterClient
+	indexModifier IndexModifier
+	migrations    []*Migration
+	indexPrefix   string
+}
+
+// NewMigrator will create a new instance of a migrator. If an index prefix is provided, the indices of that network
+// are migrated, e.g. "testnet-transactions" instead of "transactions"
+func NewMigrator(args ArgsMigrator) (*migrator, error) {
+	if args.Client == nil {
+		return nil, ErrNilClusterClient
+	}
+	if args.IndexModifier == nil {
+		return nil, ErrNilIndexModifier
+	}
+
+	return &migrator{
+		client:        args.Client,
+		indexModifier: args.IndexModifier,
+		migrations:    args.Migrations,
+		indexPrefix:   args.IndexPrefix,
+	}, nil
+}
+
+// Up will apply, in order, the migrations newer than the schema version of the cluster. Every step of a migration is
+// recorded in the cluster, so an interrupted migration is resumed and the applied ones are skipped. The indexer has to
+// be stopped while the migrations are applied
+func (m *migrator) Up() error {
+	pending, err := m.Pending()
+	if err != nil {
+		return err
+	}
+
+	for _, migration := range pending {
+		log.Info("applying migration", "version", migration.Version, "name", migration.Name, "index", migration.Index)
+
+		err = m.apply(migration)
+		if err != nil {
+			return fmt.Errorf("%w while applying the migration %d (%s)", err, migration.Version, migration.Name)
+		}
+
+		err = m.setSchemaVersion(migration.Version)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Pending returns the migrations newer than the schema version of the cluster
+func (m *migrator) Pending() ([]*Migration, error) {
+	err := m.checkStateIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	version, err := m.getSchemaVersion()
+	if err != nil {
+		return nil, err
+	}
+
+	pending := make([]*Migration, 0)
+	for _, migration := range m.migrations {
+		if migration.Version > version {
+			pending = append(pending, migration)
+		}
+	}
+
+	return pending, nil
+}
+
+// Baseline will set the schema version of the cluster without applying the migrations up to it. It is used for a
+// cluster indexed by an indexer that already writes the schema of that version
+func (m *migrator) Baseline(version uint32) error {
+	err := m.checkStateIndex()
+	if err != nil {
+		return err
+	}
+
+	return m.setSchemaVersion(version)
+}
+
+// apply will copy the documents of the index in a new versioned index, will check that all of them were copied and
+// will move the alias to the new index. The previous indices are kept, without the alias, so they can be checked
+// before being deleted
+func (m *migrator) apply(migration *Migration) error {
+	state, err := m.getMigrationState(migration.Version)
+	if err != nil {
+		return err
+	}
+	if state == nil {
+		state = &migrationState{
+			Version:     migration.Version,
+			Name:        migration.Name,
+			Index:       migration.Index,
+			Destination: fmt.Sprintf("%s-v%d-%s", migration.Index, migration.Version, indexSuffix),
+		}
+		err = m.setMigrationStatus(state, statusCopying)
+		if err != nil {
+			return err
+		}
+	}
+
+	if state.Status == statusCopying {
+		err = m.copyDocuments(migration, state.Destination)
+		if err != nil {
+			return err
+		}
+
+		err = m.setMigrationStatus(state, statusCopied)
+		if err != nil {
+			return err
+		}
+	}
+
+	if state.Status == statusCopied {
+		err = m.verifyCounts(migration, state.Destination)
+		if err != nil {
+			return err
+		}
+
+		err = m.swapAlias(migration.Index, state.Destination)
+		if err != nil {
+			return err
+		}
+
+		return m.setMigrationStatus(state, statusDone)
+	}
+
+	return nil
+}
+
+// copyDocuments will create the new index and will copy in it all the documents of the alias. The documents keep
+// their ids, so the copy of an interrupted migration overwrites the already copied documents
+func (m *migrator) copyDocuments(migration *Migration, destination string) error {
+	if migration.Mappings != nil {
+		err := m.replaceTemplateMappings(migration.Index, migration.Mappings)
+		if err != nil {
+			return err
+		}
+	}
+
+	err := m.client.CreateIndex(m.prefixed(destination))
+	if err != nil {
+		return err
+	}
+
+	return m.indexModifier.AlterIndex(migration.Index, destination, migration.Modifier)
+}
+
+func (m *migrator) replaceTemplateMappings(index string, mappings *bytes.Buffer) error {
+	liveTemplate, err := m.client.GetTemplate(m.prefixed(index))
+	if err != nil {
+		return err
+	}
+
+	template := make(map[string]interface{})
+	err = json.Unmarshal(liveTemplate, &template)
+	if err != nil {
+		return err
+	}
+
+	newMappings := make(map[string]interface{})
+	err = json.Unmarshal(mappings.Bytes(), &newMappings)
+	if err != nil {
+		return err
+	}
+	template[mappingsField] = newMappings
+
+	encoded, err := json.Marshal(template)
+	if err != nil {
+		return err
+	}
+
+	return m.client.PutTemplate(m.prefixed(index), bytes.NewBuffer(encoded))
+}
+
+func (m *migrator) verifyCounts(migration *Migration, destination string) error {
+	alias := m.prefixed(migration.Index)
+	newIndex := m.prefixed(destination)
+
+	for _, index := range []string{alias, newIndex} {
+		err := m.client.Refresh(index)
+		if err != nil {
+			return err
+		}
+	}
+
+	aliasCount, err := m.client.CountDocuments(alias)
+	if err != nil {
+		return err
+	}
+	newIndexCount, err := m.client.CountDocuments(newIndex)
+	if err != nil {
+		return err
+	}
+
+	log.Info("verifying the documents count", "alias", alias, "count", aliasCount, "new index", newIndex, "new count", newIndexCount)
+	if newIndexCount == aliasCount || (migration.DropsDocuments && newIndexCount < aliasCount) {
+		return nil
+	}
+
+	return fmt.Errorf("%w, %s has %d documents, while %s has %d", ErrDocumentsCount, newIndex, newIndexCount, alias, aliasCount)
+}
+
+// swapAlias will move the alias from all its indices to the new one. The new index becomes the write index of an
+// alias of a rolling index. An alias that already points only to the new index is not changed
+func (m *migrator) swapAlias(index string, destination string) error {
+	alias := m.prefixed(index)
+	newIndex := m.prefixed(destination)
+
+	aliasIndices, err := m.client.GetAliasIndices(alias)
+	if err != nil {
+		return err
+	}
+
+	isWriteAlias := false
+	previousIndices := make([]string, 0, len(aliasIndices))
+	for aliasIndex, isWriteIndex := range aliasIndices {
+		isWriteAlias = isWriteAlias || isWriteIndex
+		if aliasIndex != newIndex {
+			previousIndices = append(previousIndices, aliasIndex)
+		}
+	}
+	if len(previousIndices) == 0 {
+		return nil
+	}
+	sort.Strings(previousIndices)
+
+	log.Info("moving the alias to the new index", "alias", alias, "new index", newIndex, "previous indices", previousIndices)
+
+	return m.client.SwapAlias(alias, previousIndices, newIndex, isWriteAlias)
+}
+
+func (m *migrator) prefixed(index string) string {
+	if m.indexPrefix == "" {
+		return index
+	}
+
+	return m.indexPrefix + indexPrefixSeparator + index
+}
@@ -0,0 +1,105 @@
+package migration
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
+const (
+	// migrationsIndex is the index of the cluster that holds the schema version and the state of every migration. It
+	// is created by the indexer, which reads the schema version when it starts
+	migrationsIndex = "migrations"
+	// schemaStateID is the ID of the document that holds the schema version of the cluster
+	schemaStateID = "schema"
+
+	statusCopying = "copying"
+	statusCopied  = "copied"
+	statusDone    = "done"
+)
+
+type schemaState struct {
+	Version   uint32 `json:"version"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+type migrationState struct {
+	Version     uint32 `json:"version"`
+	Name        string `json:"name"`
+	Index       string `json:"index"`
+	Destination string `json:"destination"`
+	Status      string `json:"status"`
+	Timestamp   int64  `json:"timestamp"`
+}
+
+func (m *migrator) checkStateIndex() error {
+	aliasIndices, err := m.client.GetAliasIndices(m.prefixed(migrationsIndex))
+	if err != nil {
+		return err
+	}
+	if len(aliasIndices) == 0 {
+		return ErrMissingStateIndex
+	}
+
+	return nil
+}
+
+// getSchemaVersion returns the schema version of the cluster. A cluster without a schema version was indexed before
+// the first migration
+func (m *migrator) getSchemaVersion() (uint32, error) {
+	source, err := m.client.GetDocument(m.prefixed(migrationsIndex), schemaStateID)
+	if err != nil || source == nil {
+		return 0, err
+	}
+
+	state := &schemaState{}
+	err = json.Unmarshal(source, state)
+	if err != nil {
+		return 0, err
+	}
+
+	return state.Version, nil
+}
+
+func (m *migrator) setSchemaVersion(version uint32) error {
+	return m.putState(schemaStateID, &schemaState{
+		Version:   version,
+		Timestamp: time.Now().Unix(),
+	})
+}
+
+// getMigrationState returns the state of the migration, or nil if the migration was not started
+func (m *migrator) getMigrationState(version uint32) (*migrationState, error) {
+	source, err := m.client.GetDocument(m.prefixed(migrationsIndex), migrationStateID(version))
+	if err != nil || source == nil {
+		return nil, err
+	}
+
+	state := &migrationState{}
+	err = json.Unmarshal(source, state)
+	if err != nil {
+		return nil, err
+	}
+
+	return state, nil
+}
+
+func (m *migrator) setMigrationStatus(state *migrationState, status string) error {
+	state.Status = status
+	state.Timestamp = time.Now().Unix()
+
+	return m.putState(migrationStateID(state.Version), state)
+}
+
+func (m *migrator) putState(id string, state interface{}) error {
+	document, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	return m.client.PutDocument(m.prefixed(migrationsIndex), id, document)
+}
+
+func migrationStateID(version uint32) string {
+	return strconv.FormatUint(uint64(version), 10)
+}
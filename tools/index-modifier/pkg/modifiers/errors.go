@@ -0,0 +1,6 @@
+package modifiers
+
+import "errors"
+
+// ErrNilDocumentsGetter signals that a nil documents getter has been provided
+var ErrNilDocumentsGetter = errors.New("nil documents getter")
@@ -0,0 +1,6 @@
+package modifiers
+
+// DocumentsGetter defines what the client used by the modifiers to read single documents should do
+type DocumentsGetter interface {
+	GetDocument(index string, id string) ([]byte, error)
+}
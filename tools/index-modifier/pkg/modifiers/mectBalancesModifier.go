@@ -0,0 +1,180 @@
+package modifiers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/ME-MotherEarth/me-elastic-indexer/data"
+)
+
+const (
+	tokenField      = "token"
+	balanceField    = "balance"
+	balanceNumField = "balanceNum"
+
+	// maxNumDecimalsInFloatBalance is the precision of the MECT balances as float, the same as the indexer's one
+	maxNumDecimalsInFloatBalance = 18
+)
+
+type responseDocumentsBulk struct {
+	Hits struct {
+		Hits []struct {
+			ID     string                     `json:"_id"`
+			Source map[string]json.RawMessage `json:"_source"`
+		} `json:"hits"`
+	} `json:"hits"`
+}
+
+type tokenSource struct {
+	Type        string `json:"type"`
+	NumDecimals uint64 `json:"numDecimals"`
+}
+
+type tokenDecimals struct {
+	numDecimals uint64
+	found       bool
+}
+
+type mectBalancesModifier struct {
+	documentsGetter DocumentsGetter
+	tokensIndex     string
+	decimals        map[string]tokenDecimals
+}
+
+// NewMECTBalancesModifier will create a new instance of mectBalancesModifier. The number of decimals of the tokens is
+// read from the provided tokens index, with the index prefix of the network included
+func NewMECTBalancesModifier(documentsGetter DocumentsGetter, tokensIndex string) (*mectBalancesModifier, error) {
+	if documentsGetter == nil {
+		return nil, ErrNilDocumentsGetter
+	}
+
+	return &mectBalancesModifier{
+		documentsGetter: documentsGetter,
+		tokensIndex:     tokensIndex,
+		decimals:        make(map[string]tokenDecimals),
+	}, nil
+}
+
+// Modify will compute again the balance as float of the accounts MECT documents from the provided responseBody, using
+// the number of decimals of their token. The documents of the tokens not found in the tokens index are kept unchanged
+func (mbm *mectBalancesModifier) Modify(responseBody []byte) ([]*bytes.Buffer, error) {
+	responseDocuments := &responseDocumentsBulk{}
+	err := json.Unmarshal(responseBody, responseDocuments)
+	if err != nil {
+		return nil, err
+	}
+
+	buffSlice := data.NewBufferSlice(data.DefaultMaxBulkSize)
+	for _, hit := range responseDocuments.Hits.Hits {
+		errPrep := mbm.putBalanceNum(hit.Source)
+		if errPrep != nil {
+			return nil, fmt.Errorf("%w while preparing the document %s", errPrep, hit.ID)
+		}
+
+		meta := []byte(fmt.Sprintf(`{ "index" : { "_id" : "%s" } }%s`, hit.ID, "\n"))
+		serializedData, errSerialize := json.Marshal(hit.Source)
+		if errSerialize != nil {
+			return nil, errSerialize
+		}
+
+		errPut := buffSlice.PutData(meta, serializedData)
+		if errPut != nil {
+			return nil, errPut
+		}
+	}
+
+	return buffSlice.Buffers(), nil
+}
+
+func (mbm *mectBalancesModifier) putBalanceNum(source map[string]json.RawMessage) error {
+	var token, balance string
+	err := unmarshalStringField(source, tokenField, &token)
+	if err != nil {
+		return err
+	}
+	err = unmarshalStringField(source, balanceField, &balance)
+	if err != nil {
+		return err
+	}
+
+	balanceBig, ok := big.NewInt(0).SetString(balance, 10)
+	if token == "" || !ok {
+		return nil
+	}
+
+	decimals, err := mbm.getDecimals(token)
+	if err != nil || !decimals.found {
+		return err
+	}
+
+	balanceNum, err := json.Marshal(computeBalanceAsFloat(balanceBig, decimals.numDecimals))
+	if err != nil {
+		return err
+	}
+	source[balanceNumField] = balanceNum
+
+	return nil
+}
+
+// getDecimals returns the number of decimals of the token. A token document without type was not written by an issue
+// event, so its number of decimals is not known
+func (mbm *mectBalancesModifier) getDecimals(token string) (tokenDecimals, error) {
+	decimals, cached := mbm.decimals[token]
+	if cached {
+		return decimals, nil
+	}
+
+	document, err := mbm.documentsGetter.GetDocument(mbm.tokensIndex, token)
+	if err != nil {
+		return tokenDecimals{}, err
+	}
+	if document != nil {
+		source := &tokenSource{}
+		err = json.Unmarshal(document, source)
+		if err != nil {
+			return tokenDecimals{}, err
+		}
+
+		decimals = tokenDecimals{
+			numDecimals: source.NumDecimals,
+			found:       source.Type != "",
+		}
+	}
+	if !decimals.found {
+		log.Warn("the number of decimals of the token is not known, the balances are not changed", "token", token)
+	}
+
+	mbm.decimals[token] = decimals
+
+	return decimals, nil
+}
+
+func unmarshalStringField(source map[string]json.RawMessage, field string, value *string) error {
+	raw, ok := source[field]
+	if !ok {
+		return nil
+	}
+
+	return json.Unmarshal(raw, value)
+}
+
+// computeBalanceAsFloat converts the balance the same way the indexer does for a token with a known number of decimals
+func computeBalanceAsFloat(balance *big.Int, numDecimals uint64) float64 {
+	if balance.Sign() <= 0 {
+		return 0
+	}
+
+	precisionDecimals := numDecimals
+	if precisionDecimals > maxNumDecimalsInFloatBalance {
+		precisionDecimals = maxNumDecimalsInFloatBalance
+	}
+	balancePrecision := math.Pow(10, float64(precisionDecimals))
+
+	balanceFloat64, _ := big.NewFloat(0).SetInt(balance).Float64()
+	bal := balanceFloat64 / math.Pow(10, float64(numDecimals))
+
+	return math.Round(bal*balancePrecision) / balancePrecision
+}
@@ -0,0 +1,153 @@
+package modifiers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testTokensIndex = "testnet-tokens"
+
+type documentsGetterStub struct {
+	GetDocumentCalled func(index string, id string) ([]byte, error)
+}
+
+func (dgs *documentsGetterStub) GetDocument(index string, id string) ([]byte, error) {
+	if dgs.GetDocumentCalled != nil {
+		return dgs.GetDocumentCalled(index, id)
+	}
+
+	return nil, nil
+}
+
+func createTokensGetter(tokens map[string]string, numCalls map[string]int) *documentsGetterStub {
+	return &documentsGetterStub{
+		GetDocumentCalled: func(index string, id string) ([]byte, error) {
+			numCalls[id]++
+			if index != testTokensIndex {
+				return nil, errors.New("unexpected index " + index)
+			}
+
+			document, found := tokens[id]
+			if !found {
+				return nil, nil
+			}
+
+			return []byte(document), nil
+		},
+	}
+}
+
+func createResponseBody(sources ...string) []byte {
+	hits := make([]string, 0, len(sources))
+	for idx, source := range sources {
+		hits = append(hits, fmt.Sprintf(`{"_id":"doc%d","_source":%s}`, idx, source))
+	}
+
+	return []byte(fmt.Sprintf(`{"hits":{"hits":[%s]}}`, strings.Join(hits, ",")))
+}
+
+// readModifiedSources returns the sources of the documents from the bulk requests, by their id
+func readModifiedSources(t *testing.T, buffers []*bytes.Buffer) map[string]map[string]interface{} {
+	sources := make(map[string]map[string]interface{})
+	for _, buffer := range buffers {
+		lines := bytes.Split(bytes.TrimSpace(buffer.Bytes()), []byte("\n"))
+		require.Equal(t, 0, len(lines)%2)
+
+		for idx := 0; idx < len(lines); idx += 2 {
+			meta := make(map[string]map[string]string)
+			require.Nil(t, json.Unmarshal(lines[idx], &meta))
+
+			source := make(map[string]interface{})
+			require.Nil(t, json.Unmarshal(lines[idx+1], &source))
+			sources[meta["index"]["_id"]] = source
+		}
+	}
+
+	return sources
+}
+
+func TestNewMECTBalancesModifier(t *testing.T) {
+	t.Parallel()
+
+	mbm, err := NewMECTBalancesModifier(nil, testTokensIndex)
+	require.Nil(t, mbm)
+	require.Equal(t, ErrNilDocumentsGetter, err)
+
+	mbm, err = NewMECTBalancesModifier(&documentsGetterStub{}, testTokensIndex)
+	require.Nil(t, err)
+	require.NotNil(t, mbm)
+}
+
+func TestMECTBalancesModifier_Modify(t *testing.T) {
+	t.Parallel()
+
+	tokens := map[string]string{
+		"TKN-abcdef": `{"type":"FungibleMECT","numDecimals":6}`,
+		"NFT-abcdef": `{"type":"NonFungibleMECT","numDecimals":0}`,
+		"OLD-abcdef": `{"name":"old"}`,
+	}
+	numCalls := make(map[string]int)
+	mbm, _ := NewMECTBalancesModifier(createTokensGetter(tokens, numCalls), testTokensIndex)
+
+	buffers, err := mbm.Modify(createResponseBody(
+		`{"address":"addr1","token":"TKN-abcdef","balance":"1500000","balanceNum":1500000}`,
+		`{"address":"addr2","token":"TKN-abcdef","balance":"250","balanceNum":250}`,
+		`{"address":"addr3","token":"NFT-abcdef","balance":"1","balanceNum":1}`,
+		`{"address":"addr4","token":"OLD-abcdef","balance":"10","balanceNum":10}`,
+		`{"address":"addr5","token":"MISS-abcdef","balance":"10","balanceNum":10}`,
+		`{"address":"addr6","token":"TKN-abcdef","balance":"0"}`,
+	))
+	require.Nil(t, err)
+
+	sources := readModifiedSources(t, buffers)
+	require.Len(t, sources, 6)
+	require.Equal(t, 1.5, sources["doc0"][balanceNumField])
+	require.Equal(t, 0.00025, sources["doc1"][balanceNumField])
+	require.Equal(t, float64(1), sources["doc2"][balanceNumField])
+	require.Equal(t, "addr2", sources["doc1"]["address"])
+
+	// the tokens without a known number of decimals keep their balance as float
+	require.Equal(t, float64(10), sources["doc3"][balanceNumField])
+	require.Equal(t, float64(10), sources["doc4"][balanceNumField])
+	require.Equal(t, float64(0), sources["doc5"][balanceNumField])
+
+	// the number of decimals of a token is read only once
+	require.Equal(t, map[string]int{"TKN-abcdef": 1, "NFT-abcdef": 1, "OLD-abcdef": 1, "MISS-abcdef": 1}, numCalls)
+}
+
+func TestMECTBalancesModifier_ModifyShouldErrWhenTheTokenCannotBeRead(t *testing.T) {
+	t.Parallel()
+
+	expectedErr := errors.New("expected error")
+	mbm, _ := NewMECTBalancesModifier(&documentsGetterStub{
+		GetDocumentCalled: func(_ string, _ string) ([]byte, error) {
+			return nil, expectedErr
+		},
+	}, testTokensIndex)
+
+	buffers, err := mbm.Modify(createResponseBody(`{"token":"TKN-abcdef","balance":"1"}`))
+	require.Nil(t, buffers)
+	require.True(t, errors.Is(err, expectedErr))
+
+	_, err = mbm.Modify([]byte("not json"))
+	require.NotNil(t, err)
+}
+
+func TestComputeBalanceAsFloat(t *testing.T) {
+	t.Parallel()
+
+	balance, _ := big.NewInt(0).SetString("123456789000000000000", 10)
+	require.Equal(t, 123.456789, computeBalanceAsFloat(balance, 18))
+	require.Equal(t, 1234567.89, computeBalanceAsFloat(big.NewInt(123456789), 2))
+	require.Equal(t, float64(0), computeBalanceAsFloat(big.NewInt(-5), 2))
+
+	// more decimals than the float precision are rounded
+	require.Equal(t, float64(0), computeBalanceAsFloat(big.NewInt(1), 20))
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/ME-MotherEarth/me-core/core"
 	"github.com/ME-MotherEarth/me-elastic-indexer/data"
 	"github.com/ME-MotherEarth/me-elastic-indexer/process/transactions"
+	datafield "github.com/ME-MotherEarth/me-vm-common/parsers/dataField"
 )
 
 type responseSCRsBulk struct {
@@ -45,7 +46,7 @@ func (sm *scrsModifier) Modify(responseBody []byte) ([]*bytes.Buffer, error) {
 		return nil, err
 	}
 
-	buffSlice := data.NewBufferSlice()
+	buffSlice := data.NewBufferSlice(data.DefaultMaxBulkSize)
 	for _, hit := range responseSCRs.Hits.Hits {
 		if shouldIgnoreSCR(hit.Source) {
 			continue
@@ -120,7 +121,7 @@ func (sm *scrsModifier) prepareSCRForIndexing(scr *data.ScResult) error {
 	scr.Function = res.Function
 	scr.MECTValues = res.MECTValues
 	scr.Tokens = res.Tokens
-	scr.Receivers = res.Receivers
+	scr.Receivers = datafield.EncodeBytesSlice(sm.pubKeyConverter.Encode, res.Receivers)
 	scr.ReceiversShardIDs = res.ReceiversShardID
 
 	return nil
@@ -10,9 +10,9 @@ import (
 	factoryMarshalizer "github.com/ME-MotherEarth/me-core/marshal/factory"
 	"github.com/ME-MotherEarth/me-elastic-indexer/data"
 	"github.com/ME-MotherEarth/me-elastic-indexer/process/transactions"
-	"github.com/ME-MotherEarth/me-elastic-indexer/process/transactions/datafield"
 	"github.com/ME-MotherEarth/me-elastic-indexer/tools/index-modifier/pkg/modifiers/utils"
 	logger "github.com/ME-MotherEarth/me-logger"
+	datafield "github.com/ME-MotherEarth/me-vm-common/parsers/dataField"
 )
 
 var log = logger.GetOrCreate("index-modifier/pkg/alterindex")
@@ -50,9 +50,12 @@ func createOperationParser(pubkeyConverter core.PubkeyConverter) (transactions.D
 		return nil, err
 	}
 	marshalizer, err := factoryMarshalizer.NewMarshalizer(factoryMarshalizer.GogoProtobuf)
+	if err != nil {
+		return nil, err
+	}
 
 	arguments := &datafield.ArgsOperationDataFieldParser{
-		PubKeyConverter:  pubkeyConverter,
+		AddressLength:    pubkeyConverter.Len(),
 		Marshalizer:      marshalizer,
 		ShardCoordinator: shardCoordinator,
 	}
@@ -82,7 +85,7 @@ func (tm *txsModifier) Modify(responseBody []byte) ([]*bytes.Buffer, error) {
 		return nil, err
 	}
 
-	buffSlice := data.NewBufferSlice()
+	buffSlice := data.NewBufferSlice(data.DefaultMaxBulkSize)
 	for _, hit := range responseTxs.Hits.Hits {
 		if shouldIgnoreTx(hit.Source) {
 			continue
@@ -162,7 +165,7 @@ func (tm *txsModifier) prepareTxForIndexing(tx *data.Transaction) error {
 	tx.Function = res.Function
 	tx.MECTValues = res.MECTValues
 	tx.Tokens = res.Tokens
-	tx.Receivers = res.Receivers
+	tx.Receivers = datafield.EncodeBytesSlice(tm.pubKeyConverter.Encode, res.Receivers)
 	tx.ReceiversShardIDs = res.ReceiversShardID
 
 	return nil
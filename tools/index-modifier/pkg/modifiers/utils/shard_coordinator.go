@@ -70,6 +70,25 @@ func (msc *multiShardCoordinator) ComputeIdFromBytes(address []byte) uint32 {
 	return shard
 }
 
+// NumberOfShards returns the number of shards
+func (msc *multiShardCoordinator) NumberOfShards() uint32 {
+	return msc.numberOfShards
+}
+
+// SameShard returns true if the addresses are in the same shard
+func (msc *multiShardCoordinator) SameShard(firstAddress, secondAddress []byte) bool {
+	if len(firstAddress) == 0 || len(secondAddress) == 0 {
+		return true
+	}
+
+	return msc.ComputeId(firstAddress) == msc.ComputeId(secondAddress)
+}
+
+// CommunicationIdentifier returns the identifier between the current shard and the destination shard
+func (msc *multiShardCoordinator) CommunicationIdentifier(destShardID uint32) string {
+	return core.CommunicationIdentifierBetweenShards(msc.selfId, destShardID)
+}
+
 // SelfId gets the shard id of the current node
 func (msc *multiShardCoordinator) SelfId() uint32 {
 	return msc.selfId
@@ -7,8 +7,10 @@ import (
 	"os"
 	"path"
 
+	indexer "github.com/ME-MotherEarth/me-elastic-indexer"
 	"github.com/ME-MotherEarth/me-elastic-indexer/client"
 	"github.com/ME-MotherEarth/me-elastic-indexer/client/logging"
+	"github.com/ME-MotherEarth/me-elastic-indexer/process/sink/elastic"
 	"github.com/ME-MotherEarth/me-elastic-indexer/tools/indexes-creator/reader"
 	logger "github.com/ME-MotherEarth/me-logger"
 	"github.com/elastic/go-elasticsearch/v7"
@@ -24,6 +26,7 @@ type config struct {
 		Username       string   `toml:"username"`
 		Password       string   `toml:"password"`
 		UseKibana      bool     `toml:"use-kibana"`
+		IndexPrefix    string   `toml:"index-prefix"`
 		EnabledIndices []string `toml:"enabled-indices"`
 	} `toml:"config"`
 }
@@ -122,19 +125,25 @@ func createTemplates(cfg *config, indexesMappings map[string]*bytes.Buffer) erro
 	}
 
 	for index, indexData := range indexesMappings {
-		errCheck := databaseClient.CheckAndCreateTemplate(index, indexData)
+		alias := indexer.PrefixIndex(cfg.ClusterConfig.IndexPrefix, index)
+		template, errPrefix := elastic.PrefixTemplate(indexData, cfg.ClusterConfig.IndexPrefix)
+		if errPrefix != nil {
+			return fmt.Errorf("index: %s, error: %w", index, errPrefix)
+		}
+
+		errCheck := databaseClient.CheckAndCreateTemplate(alias, template)
 		if errCheck != nil {
 			return fmt.Errorf("index: %s, error: %w", index, errCheck)
 		}
 
-		indexName := fmt.Sprintf("%s-%s", index, "000001")
+		indexName := fmt.Sprintf("%s-%s", alias, "000001")
 		errCreate := databaseClient.CheckAndCreateIndex(indexName)
 		if errCreate != nil {
 			return fmt.Errorf("index: %s, error: %w", index, errCreate)
 		}
 
-		errAlias := databaseClient.CheckAndCreateAlias(index, indexName)
-		if err != nil {
+		errAlias := databaseClient.CheckAndCreateAlias(alias, indexName)
+		if errAlias != nil {
 			return errAlias
 		}
 	}
@@ -3,23 +3,31 @@ module github.com/ME-MotherEarth/me-elastic-indexer/tools/indexes-creator
 go 1.17
 
 require (
-	github.com/ME-MotherEarth/me-elastic-indexer v1.2.2
-	github.com/ME-MotherEarth/me-logger v1.0.6
+	github.com/ME-MotherEarth/me-elastic-indexer v0.0.0
+	github.com/ME-MotherEarth/me-logger v0.0.1
 	github.com/elastic/go-elasticsearch/v7 v7.12.0
-	github.com/pelletier/go-toml v1.9.3
+	github.com/pelletier/go-toml v1.9.5
 	github.com/urfave/cli v1.22.5
 )
 
 require (
-	github.com/ME-MotherEarth/me-core v1.1.7 // indirect
-	github.com/ME-MotherEarth/me-vm-common v1.2.9 // indirect
+	github.com/ME-MotherEarth/me-core v0.0.1 // indirect
+	github.com/ME-MotherEarth/me-vm-common v0.0.1 // indirect
 	github.com/cpuguy83/go-md2man/v2 v2.0.0-20190314233015-f79a8a8ca69d // indirect
 	github.com/denisbrodbeck/machineid v1.0.1 // indirect
-	github.com/gogo/protobuf v1.3.2 // indirect
+	github.com/gogo/protobuf v0.0.0-00010101000000-000000000000 // indirect
 	github.com/golang/protobuf v1.5.2 // indirect
 	github.com/mr-tron/base58 v1.2.0 // indirect
 	github.com/russross/blackfriday/v2 v2.0.1 // indirect
 	github.com/shurcooL/sanitized_anchor_name v1.0.0 // indirect
+	github.com/tidwall/gjson v1.14.3 // indirect
+	github.com/tidwall/match v1.1.1 // indirect
+	github.com/tidwall/pretty v1.2.0 // indirect
 	golang.org/x/sys v0.0.0-20210615035016-665e8c7367d1 // indirect
 	google.golang.org/protobuf v1.26.0 // indirect
 )
+
+replace (
+	github.com/ME-MotherEarth/me-elastic-indexer => ../..
+	github.com/gogo/protobuf => github.com/ME-MotherEarth/protobuf v1.3.2
+)
@@ -5,6 +5,7 @@ import (
 	"github.com/ME-MotherEarth/me-core/data/block"
 	"github.com/ME-MotherEarth/me-core/data/indexer"
 	"github.com/ME-MotherEarth/me-elastic-indexer/data"
+	"github.com/ME-MotherEarth/me-elastic-indexer/payload"
 )
 
 // WorkItemHandler defines the interface for item that needs to be saved in elasticsearch database
@@ -13,6 +14,25 @@ type WorkItemHandler interface {
 	IsInterfaceNil() bool
 }
 
+// PersistableWorkItemHandler defines a work item whose arguments can be extracted in order to be persisted
+type PersistableWorkItemHandler interface {
+	WorkItemHandler
+	Payload() *payload.Payload
+}
+
+// PreparableWorkItemHandler defines a work item that can process its data ahead of time, while a previous item
+// is being saved
+type PreparableWorkItemHandler interface {
+	WorkItemHandler
+	Prepare()
+}
+
+// BlockWorkItemHandler defines a work item that saves a block
+type BlockWorkItemHandler interface {
+	WorkItemHandler
+	GetHeader() coreData.HeaderHandler
+}
+
 type saveBlockIndexer interface {
 	SaveHeader(
 		headerHash []byte,
@@ -25,6 +45,9 @@ type saveBlockIndexer interface {
 	) error
 	SaveMiniblocks(header coreData.HeaderHandler, body *block.Body) error
 	SaveTransactions(body *block.Body, header coreData.HeaderHandler, pool *indexer.Pool) error
+	PrepareTransactions(body *block.Body, header coreData.HeaderHandler, pool *indexer.Pool) (*data.PreparedBlockTransactions, error)
+	SavePreparedTransactions(header coreData.HeaderHandler, pool *indexer.Pool, preparedTxs *data.PreparedBlockTransactions) error
+	SaveCheckpoint(headerHash []byte, header coreData.HeaderHandler) error
 }
 
 type saveRatingIndexer interface {
@@ -36,6 +59,7 @@ type removeIndexer interface {
 	RemoveMiniblocks(header coreData.HeaderHandler, body *block.Body) error
 	RemoveTransactions(header coreData.HeaderHandler, body *block.Body) error
 	RemoveAccountsMECT(headerTimestamp uint64) error
+	RevertBlockChanges(header coreData.HeaderHandler) error
 }
 
 type saveRounds interface {
@@ -49,3 +73,17 @@ type saveValidatorsIndexer interface {
 type saveAccountsIndexer interface {
 	SaveAccounts(blockTimestamp uint64, accounts []*data.Account) error
 }
+
+type finalizedBlockIndexer interface {
+	FinalizeBlock(headerHash []byte) error
+}
+
+type payloadIndexer interface {
+	saveBlockIndexer
+	saveRatingIndexer
+	removeIndexer
+	saveRounds
+	saveValidatorsIndexer
+	saveAccountsIndexer
+	finalizedBlockIndexer
+}
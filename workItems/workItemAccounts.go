@@ -3,6 +3,7 @@ package workItems
 import (
 	coreData "github.com/ME-MotherEarth/me-core/data"
 	"github.com/ME-MotherEarth/me-elastic-indexer/data"
+	"github.com/ME-MotherEarth/me-elastic-indexer/payload"
 )
 
 type itemAccounts struct {
@@ -45,6 +46,15 @@ func (wiv *itemAccounts) Save() error {
 	return nil
 }
 
+// Payload returns the arguments of the work item, so it can be persisted and rebuilt later
+func (wiv *itemAccounts) Payload() *payload.Payload {
+	return &payload.Payload{
+		Type:      payload.SaveAccounts,
+		Timestamp: wiv.blockTimestamp,
+		Accounts:  wiv.accounts,
+	}
+}
+
 // IsInterfaceNil returns true if there is no value under the interface
 func (wiv *itemAccounts) IsInterfaceNil() bool {
 	return wiv == nil
@@ -4,6 +4,7 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/ME-MotherEarth/me-core/core/check"
@@ -11,6 +12,8 @@ import (
 	"github.com/ME-MotherEarth/me-core/data/block"
 	"github.com/ME-MotherEarth/me-core/data/indexer"
 	"github.com/ME-MotherEarth/me-core/marshal"
+	elasticData "github.com/ME-MotherEarth/me-elastic-indexer/data"
+	"github.com/ME-MotherEarth/me-elastic-indexer/payload"
 	logger "github.com/ME-MotherEarth/me-logger"
 )
 
@@ -23,6 +26,10 @@ type itemBlock struct {
 	indexer       saveBlockIndexer
 	marshalizer   marshal.Marshalizer
 	argsSaveBlock *indexer.ArgsSaveBlockData
+	payloadType   payload.Type
+
+	mutPrepared sync.Mutex
+	preparedTxs *elasticData.PreparedBlockTransactions
 }
 
 // NewItemBlock will create a new instance of ItemBlock
@@ -35,10 +42,55 @@ func NewItemBlock(
 		indexer:       indexer,
 		marshalizer:   marshalizer,
 		argsSaveBlock: args,
+		payloadType:   payload.SaveBlock,
+	}
+}
+
+// NewItemFinalizedBlockData will create a new instance of ItemBlock for a block that is saved only after it was
+// finalized. The provided indexer should write only the indices that hold final data
+func NewItemFinalizedBlockData(
+	indexer saveBlockIndexer,
+	marshalizer marshal.Marshalizer,
+	args *indexer.ArgsSaveBlockData,
+) WorkItemHandler {
+	return &itemBlock{
+		indexer:       indexer,
+		marshalizer:   marshalizer,
+		argsSaveBlock: args,
+		payloadType:   payload.SaveFinalizedBlock,
+	}
+}
+
+// Prepare will process the transactions of the block ahead of time, without saving them. The result is used by
+// the next call of Save. If the preparation fails, Save will process the transactions again
+func (wib *itemBlock) Prepare() {
+	if check.IfNil(wib.argsSaveBlock.Header) {
+		return
+	}
+
+	body, ok := wib.argsSaveBlock.Body.(*block.Body)
+	if !ok || len(body.MiniBlocks) == 0 {
+		return
+	}
+
+	if wib.argsSaveBlock.TransactionsPool == nil {
+		wib.argsSaveBlock.TransactionsPool = &indexer.Pool{}
 	}
+
+	preparedTxs, err := wib.indexer.PrepareTransactions(body, wib.argsSaveBlock.Header, wib.argsSaveBlock.TransactionsPool)
+	if err != nil {
+		log.Debug("itemBlock.Prepare cannot prepare transactions, they will be prepared on save",
+			"block hash", hex.EncodeToString(wib.argsSaveBlock.HeaderHash), "error", err)
+		return
+	}
+
+	wib.mutPrepared.Lock()
+	wib.preparedTxs = preparedTxs
+	wib.mutPrepared.Unlock()
 }
 
-// Save will prepare and save a block item in elasticsearch database
+// Save will prepare and save a block item in elasticsearch database. The documents rejected by the database do not stop
+// the block, they are returned after all the other data was saved
 func (wib *itemBlock) Save() error {
 	if check.IfNil(wib.argsSaveBlock.Header) {
 		log.Warn("nil header provided when trying to index block, will skip")
@@ -63,31 +115,45 @@ func (wib *itemBlock) Save() error {
 		wib.argsSaveBlock.TransactionsPool = &indexer.Pool{}
 	}
 
+	rejected := &elasticData.RejectedDocumentsError{}
 	txsSizeInBytes := ComputeSizeOfTxs(wib.marshalizer, wib.argsSaveBlock.TransactionsPool)
-	err := wib.indexer.SaveHeader(
+	err := rejected.Add(wib.indexer.SaveHeader(
 		wib.argsSaveBlock.HeaderHash,
 		wib.argsSaveBlock.Header,
 		wib.argsSaveBlock.SignersIndexes,
 		body,
 		wib.argsSaveBlock.NotarizedHeadersHashes,
 		wib.argsSaveBlock.HeaderGasConsumption,
-		txsSizeInBytes)
+		txsSizeInBytes))
 	if err != nil {
 		return fmt.Errorf("%w when saving header block, hash %s, nonce %d",
 			err, hex.EncodeToString(wib.argsSaveBlock.HeaderHash), wib.argsSaveBlock.Header.GetNonce())
 	}
 
-	if len(body.MiniBlocks) == 0 {
-		return nil
+	if len(body.MiniBlocks) > 0 {
+		err = wib.saveMiniblocksAndTransactions(body, rejected)
+		if err != nil {
+			return err
+		}
 	}
 
-	err = wib.indexer.SaveMiniblocks(wib.argsSaveBlock.Header, body)
+	err = rejected.Add(wib.indexer.SaveCheckpoint(wib.argsSaveBlock.HeaderHash, wib.argsSaveBlock.Header))
+	if err != nil {
+		return fmt.Errorf("%w when saving checkpoint, block hash %s, nonce %d",
+			err, hex.EncodeToString(wib.argsSaveBlock.HeaderHash), wib.argsSaveBlock.Header.GetNonce())
+	}
+
+	return rejected.AsError()
+}
+
+func (wib *itemBlock) saveMiniblocksAndTransactions(body *block.Body, rejected *elasticData.RejectedDocumentsError) error {
+	err := rejected.Add(wib.indexer.SaveMiniblocks(wib.argsSaveBlock.Header, body))
 	if err != nil {
 		return fmt.Errorf("%w when saving miniblocks, block hash %s, nonce %d",
 			err, hex.EncodeToString(wib.argsSaveBlock.HeaderHash), wib.argsSaveBlock.Header.GetNonce())
 	}
 
-	err = wib.indexer.SaveTransactions(body, wib.argsSaveBlock.Header, wib.argsSaveBlock.TransactionsPool)
+	err = rejected.Add(wib.saveTransactions(body))
 	if err != nil {
 		return fmt.Errorf("%w when saving transactions, block hash %s, nonce %d",
 			err, hex.EncodeToString(wib.argsSaveBlock.HeaderHash), wib.argsSaveBlock.Header.GetNonce())
@@ -96,6 +162,33 @@ func (wib *itemBlock) Save() error {
 	return nil
 }
 
+// saveTransactions will use the prepared transactions only once, as their buffers are consumed when saved
+func (wib *itemBlock) saveTransactions(body *block.Body) error {
+	wib.mutPrepared.Lock()
+	preparedTxs := wib.preparedTxs
+	wib.preparedTxs = nil
+	wib.mutPrepared.Unlock()
+
+	if preparedTxs == nil {
+		return wib.indexer.SaveTransactions(body, wib.argsSaveBlock.Header, wib.argsSaveBlock.TransactionsPool)
+	}
+
+	return wib.indexer.SavePreparedTransactions(wib.argsSaveBlock.Header, wib.argsSaveBlock.TransactionsPool, preparedTxs)
+}
+
+// GetHeader returns the header of the block saved by the work item
+func (wib *itemBlock) GetHeader() data.HeaderHandler {
+	return wib.argsSaveBlock.Header
+}
+
+// Payload returns the arguments of the work item, so it can be persisted and rebuilt later
+func (wib *itemBlock) Payload() *payload.Payload {
+	return &payload.Payload{
+		Type:          wib.payloadType,
+		ArgsSaveBlock: wib.argsSaveBlock,
+	}
+}
+
 // IsInterfaceNil returns true if there is no value under the interface
 func (wib *itemBlock) IsInterfaceNil() bool {
 	return wib == nil
@@ -13,6 +13,7 @@ import (
 	"github.com/ME-MotherEarth/me-core/data/indexer"
 	"github.com/ME-MotherEarth/me-core/data/transaction"
 	"github.com/ME-MotherEarth/me-core/marshal"
+	elasticData "github.com/ME-MotherEarth/me-elastic-indexer/data"
 	"github.com/ME-MotherEarth/me-elastic-indexer/mock"
 	"github.com/ME-MotherEarth/me-elastic-indexer/workItems"
 	"github.com/stretchr/testify/assert"
@@ -175,6 +176,159 @@ func TestItemBlock_SaveShouldWork(t *testing.T) {
 	require.Equal(t, 3, countCalled)
 }
 
+func TestItemBlock_SaveShouldSaveCheckpointAfterTheBlockData(t *testing.T) {
+	calls := make([]string, 0)
+	headerHash := []byte("hash")
+	itemBlock := workItems.NewItemBlock(
+		&mock.ElasticProcessorStub{
+			SaveHeaderCalled: func(_ []byte, _ data.HeaderHandler, _ []uint64, _ *dataBlock.Body, _ []string, _ indexer.HeaderGasConsumption, _ int) error {
+				calls = append(calls, "header")
+				return nil
+			},
+			SaveTransactionsCalled: func(_ *dataBlock.Body, _ data.HeaderHandler, _ *indexer.Pool) error {
+				calls = append(calls, "transactions")
+				return nil
+			},
+			SaveCheckpointCalled: func(hash []byte, _ data.HeaderHandler) error {
+				require.Equal(t, headerHash, hash)
+				calls = append(calls, "checkpoint")
+				return nil
+			},
+		},
+		&mock.MarshalizerMock{},
+		&indexer.ArgsSaveBlockData{
+			HeaderHash:       headerHash,
+			Header:           &dataBlock.Header{},
+			Body:             &dataBlock.Body{MiniBlocks: []*dataBlock.MiniBlock{{}}},
+			TransactionsPool: &indexer.Pool{},
+		},
+	)
+
+	err := itemBlock.Save()
+	require.NoError(t, err)
+	require.Equal(t, []string{"header", "transactions", "checkpoint"}, calls)
+}
+
+func TestItemBlock_SaveRejectedDocumentsShouldNotStopTheBlock(t *testing.T) {
+	calls := make([]string, 0)
+	itemBlock := workItems.NewItemBlock(
+		&mock.ElasticProcessorStub{
+			SaveHeaderCalled: func(_ []byte, _ data.HeaderHandler, _ []uint64, _ *dataBlock.Body, _ []string, _ indexer.HeaderGasConsumption, _ int) error {
+				calls = append(calls, "header")
+				return &elasticData.RejectedDocumentsError{Documents: []*elasticData.RejectedDocument{{Index: "blocks", ID: "b1"}}}
+			},
+			SaveTransactionsCalled: func(_ *dataBlock.Body, _ data.HeaderHandler, _ *indexer.Pool) error {
+				calls = append(calls, "transactions")
+				return &elasticData.RejectedDocumentsError{Documents: []*elasticData.RejectedDocument{{Index: "transactions", ID: "t1"}}}
+			},
+			SaveCheckpointCalled: func(_ []byte, _ data.HeaderHandler) error {
+				calls = append(calls, "checkpoint")
+				return nil
+			},
+		},
+		&mock.MarshalizerMock{},
+		&indexer.ArgsSaveBlockData{
+			Header:           &dataBlock.Header{},
+			Body:             &dataBlock.Body{MiniBlocks: []*dataBlock.MiniBlock{{}}},
+			TransactionsPool: &indexer.Pool{},
+		},
+	)
+
+	err := itemBlock.Save()
+	rejected := &elasticData.RejectedDocumentsError{}
+	require.True(t, errors.As(err, &rejected))
+	require.Equal(t, []*elasticData.RejectedDocument{{Index: "blocks", ID: "b1"}, {Index: "transactions", ID: "t1"}}, rejected.Documents)
+	require.Equal(t, []string{"header", "transactions", "checkpoint"}, calls)
+}
+
+func TestItemBlock_SaveCheckpointShouldErr(t *testing.T) {
+	localErr := errors.New("local err")
+	itemBlock := workItems.NewItemBlock(
+		&mock.ElasticProcessorStub{
+			SaveCheckpointCalled: func(_ []byte, _ data.HeaderHandler) error {
+				return localErr
+			},
+		},
+		&mock.MarshalizerMock{},
+		&indexer.ArgsSaveBlockData{
+			Header:           &dataBlock.Header{},
+			Body:             &dataBlock.Body{},
+			TransactionsPool: &indexer.Pool{},
+		},
+	)
+
+	err := itemBlock.Save()
+	require.True(t, errors.Is(err, localErr))
+}
+
+func TestItemBlock_PrepareShouldBeUsedBySave(t *testing.T) {
+	preparedTxs := &elasticData.PreparedBlockTransactions{}
+	savedPrepared := 0
+	itemBlock := workItems.NewItemBlock(
+		&mock.ElasticProcessorStub{
+			PrepareTransactionsCalled: func(body *dataBlock.Body, header data.HeaderHandler, pool *indexer.Pool) (*elasticData.PreparedBlockTransactions, error) {
+				return preparedTxs, nil
+			},
+			SavePreparedTransactionsCalled: func(header data.HeaderHandler, pool *indexer.Pool, prepared *elasticData.PreparedBlockTransactions) error {
+				require.True(t, prepared == preparedTxs)
+				savedPrepared++
+				return errors.New("local err")
+			},
+			SaveTransactionsCalled: func(body *dataBlock.Body, header data.HeaderHandler, pool *indexer.Pool) error {
+				return nil
+			},
+		},
+		&mock.MarshalizerMock{},
+		&indexer.ArgsSaveBlockData{
+			Header: &dataBlock.Header{},
+			Body:   &dataBlock.Body{MiniBlocks: []*dataBlock.MiniBlock{{}}},
+		},
+	)
+
+	preparableItem, ok := itemBlock.(workItems.PreparableWorkItemHandler)
+	require.True(t, ok)
+	preparableItem.Prepare()
+
+	err := itemBlock.Save()
+	require.Error(t, err)
+	require.Equal(t, 1, savedPrepared)
+
+	// prepared data is used only once, so a retry will process the transactions again
+	err = itemBlock.Save()
+	require.NoError(t, err)
+	require.Equal(t, 1, savedPrepared)
+}
+
+func TestItemBlock_PrepareErrorShouldFallbackToSaveTransactions(t *testing.T) {
+	saveTransactionsCalled := false
+	itemBlock := workItems.NewItemBlock(
+		&mock.ElasticProcessorStub{
+			PrepareTransactionsCalled: func(body *dataBlock.Body, header data.HeaderHandler, pool *indexer.Pool) (*elasticData.PreparedBlockTransactions, error) {
+				return nil, errors.New("local err")
+			},
+			SavePreparedTransactionsCalled: func(header data.HeaderHandler, pool *indexer.Pool, prepared *elasticData.PreparedBlockTransactions) error {
+				require.Fail(t, "should have not been called")
+				return nil
+			},
+			SaveTransactionsCalled: func(body *dataBlock.Body, header data.HeaderHandler, pool *indexer.Pool) error {
+				saveTransactionsCalled = true
+				return nil
+			},
+		},
+		&mock.MarshalizerMock{},
+		&indexer.ArgsSaveBlockData{
+			Header: &dataBlock.Header{},
+			Body:   &dataBlock.Body{MiniBlocks: []*dataBlock.MiniBlock{{}}},
+		},
+	)
+
+	itemBlock.(workItems.PreparableWorkItemHandler).Prepare()
+
+	err := itemBlock.Save()
+	require.NoError(t, err)
+	require.True(t, saveTransactionsCalled)
+}
+
 func TestComputeSizeOfTxsDuration(t *testing.T) {
 	res := testing.Benchmark(benchmarkComputeSizeOfTxsDuration)
 
@@ -0,0 +1,45 @@
+package workItems
+
+import (
+	"encoding/hex"
+
+	"github.com/ME-MotherEarth/me-elastic-indexer/payload"
+)
+
+type itemFinalizedBlock struct {
+	indexer    finalizedBlockIndexer
+	headerHash []byte
+}
+
+// NewItemFinalizedBlock will create a new instance of itemFinalizedBlock
+func NewItemFinalizedBlock(indexer finalizedBlockIndexer, headerHash []byte) WorkItemHandler {
+	return &itemFinalizedBlock{
+		indexer:    indexer,
+		headerHash: headerHash,
+	}
+}
+
+// Save will mark as final in elasticsearch database the data of the block with the provided hash
+func (wifb *itemFinalizedBlock) Save() error {
+	err := wifb.indexer.FinalizeBlock(wifb.headerHash)
+	if err != nil {
+		log.Warn("itemFinalizedBlock.Save", "could not mark block as final", err.Error(),
+			"hash", hex.EncodeToString(wifb.headerHash))
+		return err
+	}
+
+	return nil
+}
+
+// Payload returns the arguments of the work item, so it can be persisted and rebuilt later
+func (wifb *itemFinalizedBlock) Payload() *payload.Payload {
+	return &payload.Payload{
+		Type:       payload.FinalizedBlock,
+		HeaderHash: wifb.headerHash,
+	}
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (wifb *itemFinalizedBlock) IsInterfaceNil() bool {
+	return wifb == nil
+}
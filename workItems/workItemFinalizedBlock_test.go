@@ -0,0 +1,51 @@
+package workItems_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ME-MotherEarth/me-elastic-indexer/mock"
+	"github.com/ME-MotherEarth/me-elastic-indexer/payload"
+	"github.com/ME-MotherEarth/me-elastic-indexer/workItems"
+	"github.com/stretchr/testify/require"
+)
+
+func TestItemFinalizedBlock_Save(t *testing.T) {
+	var finalizedHash []byte
+	itemFinalized := workItems.NewItemFinalizedBlock(
+		&mock.ElasticProcessorStub{
+			FinalizeBlockCalled: func(headerHash []byte) error {
+				finalizedHash = headerHash
+				return nil
+			},
+		},
+		[]byte("hash"),
+	)
+	require.False(t, itemFinalized.IsInterfaceNil())
+
+	err := itemFinalized.Save()
+	require.NoError(t, err)
+	require.Equal(t, []byte("hash"), finalizedHash)
+}
+
+func TestItemFinalizedBlock_SaveShouldErr(t *testing.T) {
+	localErr := errors.New("local err")
+	itemFinalized := workItems.NewItemFinalizedBlock(
+		&mock.ElasticProcessorStub{
+			FinalizeBlockCalled: func(headerHash []byte) error {
+				return localErr
+			},
+		},
+		[]byte("hash"),
+	)
+
+	err := itemFinalized.Save()
+	require.Equal(t, localErr, err)
+}
+
+func TestItemFinalizedBlock_Payload(t *testing.T) {
+	itemFinalized := workItems.NewItemFinalizedBlock(&mock.ElasticProcessorStub{}, []byte("hash"))
+
+	p := itemFinalized.(workItems.PersistableWorkItemHandler).Payload()
+	require.Equal(t, &payload.Payload{Type: payload.FinalizedBlock, HeaderHash: []byte("hash")}, p)
+}
@@ -0,0 +1,44 @@
+package workItems
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ME-MotherEarth/me-core/marshal"
+	"github.com/ME-MotherEarth/me-elastic-indexer/payload"
+)
+
+// ErrNilPayload signals that a nil payload has been provided
+var ErrNilPayload = errors.New("nil payload")
+
+// NewItemFromPayload will rebuild the work item whose arguments are held by the provided payload
+func NewItemFromPayload(
+	indexer payloadIndexer,
+	marshalizer marshal.Marshalizer,
+	p *payload.Payload,
+) (WorkItemHandler, error) {
+	if p == nil {
+		return nil, ErrNilPayload
+	}
+
+	switch p.Type {
+	case payload.SaveBlock:
+		return NewItemBlock(indexer, marshalizer, p.ArgsSaveBlock), nil
+	case payload.RevertIndexedBlock:
+		return NewItemRemoveBlock(indexer, p.Body, p.Header), nil
+	case payload.SaveRoundsInfo:
+		return NewItemRounds(indexer, p.RoundsInfo), nil
+	case payload.SaveValidatorsRating:
+		return NewItemRating(indexer, p.RatingIndexID, p.RatingInfo), nil
+	case payload.SaveValidatorsPubKeys:
+		return NewItemValidators(indexer, p.Epoch, p.ValidatorsPubKeys), nil
+	case payload.SaveAccounts:
+		return NewItemAccounts(indexer, p.Timestamp, p.Accounts), nil
+	case payload.FinalizedBlock:
+		return NewItemFinalizedBlock(indexer, p.HeaderHash), nil
+	case payload.SaveFinalizedBlock:
+		return NewItemFinalizedBlockData(indexer, marshalizer, p.ArgsSaveBlock), nil
+	default:
+		return nil, fmt.Errorf("%w: %d", payload.ErrUnknownPayloadType, p.Type)
+	}
+}
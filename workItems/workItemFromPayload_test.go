@@ -0,0 +1,55 @@
+package workItems_test
+
+import (
+	"errors"
+	"testing"
+
+	dataBlock "github.com/ME-MotherEarth/me-core/data/block"
+	"github.com/ME-MotherEarth/me-core/data/indexer"
+	"github.com/ME-MotherEarth/me-elastic-indexer/data"
+	"github.com/ME-MotherEarth/me-elastic-indexer/mock"
+	"github.com/ME-MotherEarth/me-elastic-indexer/payload"
+	"github.com/ME-MotherEarth/me-elastic-indexer/workItems"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewItemFromPayload_NilOrUnknownPayloadShouldErr(t *testing.T) {
+	wi, err := workItems.NewItemFromPayload(&mock.ElasticProcessorStub{}, &mock.MarshalizerMock{}, nil)
+	require.Nil(t, wi)
+	require.Equal(t, workItems.ErrNilPayload, err)
+
+	wi, err = workItems.NewItemFromPayload(&mock.ElasticProcessorStub{}, &mock.MarshalizerMock{}, &payload.Payload{Type: 100})
+	require.Nil(t, wi)
+	require.True(t, errors.Is(err, payload.ErrUnknownPayloadType))
+}
+
+func TestNewItemFromPayload_ShouldRebuildItems(t *testing.T) {
+	roundsInfo := []*data.RoundInfo{{Index: 1}}
+	elasticProc := &mock.ElasticProcessorStub{
+		SaveRoundsInfoCalled: func(infos []*data.RoundInfo) error {
+			require.Equal(t, roundsInfo, infos)
+			return nil
+		},
+	}
+
+	items := []workItems.WorkItemHandler{
+		workItems.NewItemBlock(elasticProc, &mock.MarshalizerMock{}, &indexer.ArgsSaveBlockData{Header: &dataBlock.Header{}, Body: &dataBlock.Body{}}),
+		workItems.NewItemRemoveBlock(elasticProc, &dataBlock.Body{}, &dataBlock.Header{}),
+		workItems.NewItemRounds(elasticProc, roundsInfo),
+		workItems.NewItemRating(elasticProc, "0_1", []*data.ValidatorRatingInfo{{PublicKey: "pk"}}),
+		workItems.NewItemValidators(elasticProc, 1, map[uint32][][]byte{0: {[]byte("pk")}}),
+		workItems.NewItemAccounts(elasticProc, 10, nil),
+		workItems.NewItemFinalizedBlock(elasticProc, []byte("hash")),
+		workItems.NewItemFinalizedBlockData(elasticProc, &mock.MarshalizerMock{}, &indexer.ArgsSaveBlockData{Header: &dataBlock.Header{}, Body: &dataBlock.Body{}}),
+	}
+
+	for _, item := range items {
+		persistableItem, ok := item.(workItems.PersistableWorkItemHandler)
+		require.True(t, ok)
+
+		rebuiltItem, err := workItems.NewItemFromPayload(elasticProc, &mock.MarshalizerMock{}, persistableItem.Payload())
+		require.NoError(t, err)
+		require.Equal(t, item, rebuiltItem)
+		require.NoError(t, rebuiltItem.Save())
+	}
+}
@@ -1,6 +1,9 @@
 package workItems
 
-import "github.com/ME-MotherEarth/me-elastic-indexer/data"
+import (
+	"github.com/ME-MotherEarth/me-elastic-indexer/data"
+	"github.com/ME-MotherEarth/me-elastic-indexer/payload"
+)
 
 type itemRating struct {
 	indexer    saveRatingIndexer
@@ -17,6 +20,15 @@ func NewItemRating(indexer saveRatingIndexer, indexID string, infoRating []*data
 	}
 }
 
+// Payload returns the arguments of the work item, so it can be persisted and rebuilt later
+func (wir *itemRating) Payload() *payload.Payload {
+	return &payload.Payload{
+		Type:          payload.SaveValidatorsRating,
+		RatingIndexID: wir.indexID,
+		RatingInfo:    wir.infoRating,
+	}
+}
+
 // IsInterfaceNil returns true if there is no value under the interface
 func (wir *itemRating) IsInterfaceNil() bool {
 	return wir == nil
@@ -3,6 +3,8 @@ package workItems
 import (
 	"github.com/ME-MotherEarth/me-core/data"
 	"github.com/ME-MotherEarth/me-core/data/block"
+	elasticData "github.com/ME-MotherEarth/me-elastic-indexer/data"
+	"github.com/ME-MotherEarth/me-elastic-indexer/payload"
 )
 
 type itemRemoveBlock struct {
@@ -24,14 +26,30 @@ func NewItemRemoveBlock(
 	}
 }
 
+// Payload returns the arguments of the work item, so it can be persisted and rebuilt later
+func (wirb *itemRemoveBlock) Payload() *payload.Payload {
+	return &payload.Payload{
+		Type:   payload.RevertIndexedBlock,
+		Header: wirb.headerHandler,
+		Body:   wirb.bodyHandler,
+	}
+}
+
 // IsInterfaceNil returns true if there is no value under the interface
 func (wirb *itemRemoveBlock) IsInterfaceNil() bool {
 	return wirb == nil
 }
 
-// Save will remove a block and miniblocks from elasticsearch database
+// Save will restore the documents changed by a block and will remove the block, its miniblocks and transactions from
+// elasticsearch database. The documents rejected by the database do not stop the removal, they are returned at the end
 func (wirb *itemRemoveBlock) Save() error {
-	err := wirb.indexer.RemoveHeader(wirb.headerHandler)
+	rejected := &elasticData.RejectedDocumentsError{}
+	err := rejected.Add(wirb.indexer.RevertBlockChanges(wirb.headerHandler))
+	if err != nil {
+		return err
+	}
+
+	err = rejected.Add(wirb.indexer.RemoveHeader(wirb.headerHandler))
 	if err != nil {
 		return err
 	}
@@ -41,15 +59,20 @@ func (wirb *itemRemoveBlock) Save() error {
 		return ErrBodyTypeAssertion
 	}
 
-	err = wirb.indexer.RemoveMiniblocks(wirb.headerHandler, body)
+	err = rejected.Add(wirb.indexer.RemoveMiniblocks(wirb.headerHandler, body))
+	if err != nil {
+		return err
+	}
+
+	err = rejected.Add(wirb.indexer.RemoveTransactions(wirb.headerHandler, body))
 	if err != nil {
 		return err
 	}
 
-	err = wirb.indexer.RemoveTransactions(wirb.headerHandler, body)
+	err = rejected.Add(wirb.indexer.RemoveAccountsMECT(wirb.headerHandler.GetTimeStamp()))
 	if err != nil {
 		return err
 	}
 
-	return wirb.indexer.RemoveAccountsMECT(wirb.headerHandler.GetTimeStamp())
+	return rejected.AsError()
 }
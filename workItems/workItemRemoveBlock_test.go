@@ -15,6 +15,10 @@ func TestItemRemoveBlock_Save(t *testing.T) {
 	countCalled := 0
 	itemRemove := workItems.NewItemRemoveBlock(
 		&mock.ElasticProcessorStub{
+			RevertBlockChangesCalled: func(header data.HeaderHandler) error {
+				countCalled++
+				return nil
+			},
 			RemoveHeaderCalled: func(header data.HeaderHandler) error {
 				countCalled++
 				return nil
@@ -35,7 +39,27 @@ func TestItemRemoveBlock_Save(t *testing.T) {
 
 	err := itemRemove.Save()
 	require.NoError(t, err)
-	require.Equal(t, 3, countCalled)
+	require.Equal(t, 4, countCalled)
+}
+
+func TestItemRemoveBlock_SaveRevertBlockChangesShouldErr(t *testing.T) {
+	localErr := errors.New("local err")
+	itemRemove := workItems.NewItemRemoveBlock(
+		&mock.ElasticProcessorStub{
+			RevertBlockChangesCalled: func(header data.HeaderHandler) error {
+				return localErr
+			},
+			RemoveHeaderCalled: func(header data.HeaderHandler) error {
+				require.Fail(t, "should have not been called")
+				return nil
+			},
+		},
+		&dataBlock.Body{},
+		&dataBlock.Header{},
+	)
+
+	err := itemRemove.Save()
+	require.Equal(t, localErr, err)
 }
 
 func TestItemRemoveBlock_SaveRemoveHeaderShouldErr(t *testing.T) {
@@ -2,6 +2,7 @@ package workItems
 
 import (
 	"github.com/ME-MotherEarth/me-elastic-indexer/data"
+	"github.com/ME-MotherEarth/me-elastic-indexer/payload"
 )
 
 type itemRounds struct {
@@ -28,6 +29,14 @@ func (wir *itemRounds) Save() error {
 	return nil
 }
 
+// Payload returns the arguments of the work item, so it can be persisted and rebuilt later
+func (wir *itemRounds) Payload() *payload.Payload {
+	return &payload.Payload{
+		Type:       payload.SaveRoundsInfo,
+		RoundsInfo: wir.roundsInfo,
+	}
+}
+
 // IsInterfaceNil returns true if there is no value under the interface
 func (wir *itemRounds) IsInterfaceNil() bool {
 	return wir == nil
@@ -1,5 +1,10 @@
 package workItems
 
+import (
+	elasticData "github.com/ME-MotherEarth/me-elastic-indexer/data"
+	"github.com/ME-MotherEarth/me-elastic-indexer/payload"
+)
+
 type itemValidators struct {
 	indexer           saveValidatorsIndexer
 	epoch             uint32
@@ -19,10 +24,12 @@ func NewItemValidators(
 	}
 }
 
-// Save will save information about validators
+// Save will save information about validators. The documents rejected by the database for a shard do not stop the
+// other shards, they are returned at the end
 func (wiv *itemValidators) Save() error {
+	rejected := &elasticData.RejectedDocumentsError{}
 	for shardID, shardPubKeys := range wiv.validatorsPubKeys {
-		err := wiv.indexer.SaveShardValidatorsPubKeys(shardID, wiv.epoch, shardPubKeys)
+		err := rejected.Add(wiv.indexer.SaveShardValidatorsPubKeys(shardID, wiv.epoch, shardPubKeys))
 		if err != nil {
 			log.Warn("itemValidators.Save could not index validators public keys",
 				"for shard", shardID,
@@ -31,7 +38,16 @@ func (wiv *itemValidators) Save() error {
 		}
 	}
 
-	return nil
+	return rejected.AsError()
+}
+
+// Payload returns the arguments of the work item, so it can be persisted and rebuilt later
+func (wiv *itemValidators) Payload() *payload.Payload {
+	return &payload.Payload{
+		Type:              payload.SaveValidatorsPubKeys,
+		Epoch:             wiv.epoch,
+		ValidatorsPubKeys: wiv.validatorsPubKeys,
+	}
 }
 
 // IsInterfaceNil returns true if there is no value under the interface